@@ -1,6 +1,8 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -16,35 +18,210 @@ var (
 )
 
 func main() {
-	if err := run(); err != nil {
+	// Run by git or ssh to prompt for credentials in the tig running them
+	if ui.AskPassHelper() {
+		os.Exit(ui.RunAskPass(os.Args[1:], os.Stdout))
+	}
+
+	err := run(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func run() error {
+func run(args []string) error {
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	session, err := applyFlags(cfg, args)
+	if err != nil {
+		return err
+	}
+
 	// Get current working directory
 	repoPath, err := filepath.Abs(".")
 	if err != nil {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	// Initialize git client
+	// Initialize git client, recording the changes made through it
 	client := git.NewClient()
+	if dir, err := config.AuditLogDir(); err == nil {
+		client = git.NewAuditClient(client, git.NewAuditLog(dir))
+	}
 	if err := client.Open(repoPath); err != nil {
 		// Continue without git repository - we'll show appropriate messages
 	}
 
+	// Editors and scripts drive the tig already running here
+	if session.send != "" {
+		socket, err := ui.ControlSocket(cfg, client, repoPath)
+		if err != nil {
+			return err
+		}
+		if socket == "" {
+			return fmt.Errorf("--send needs the control-socket option set")
+		}
+		return ui.SendControl(socket, session.send)
+	}
+
+	// With single-instance the tig running for the repository takes over
+	if session.replay == "" {
+		handed, err := ui.HandOver(cfg, client, repoPath)
+		if err != nil {
+			return err
+		}
+		if handed {
+			fmt.Fprintf(os.Stderr, "tig is already running for this repository, switched it to the %s view\n", cfg.General.StartupView)
+			return nil
+		}
+	}
+
 	terminal, err := ui.NewTerminal()
 	if err != nil {
 		return fmt.Errorf("failed to initialize terminal: %w", err)
 	}
 	defer terminal.Close()
 
+	if recent := loadRecentRepos(); recent != nil {
+		if client.IsRepository() {
+			_ = recent.Add(client.GetRootPath())
+		}
+		terminal.SetRecentRepos(recent)
+	}
+
+	if session.replay != "" {
+		events, err := readSession(session.replay)
+		if err != nil {
+			return err
+		}
+		terminal.SetReplay(events)
+	}
+
+	if session.record != "" {
+		// Recordings hold what was typed, which is nobody else's business
+		file, err := os.OpenFile(session.record, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to create recording: %w", err)
+		}
+		defer file.Close()
+		if err := file.Chmod(0600); err != nil {
+			return fmt.Errorf("failed to create recording: %w", err)
+		}
+
+		recorder := ui.NewSessionRecorder(file)
+		terminal.SetRecorder(recorder)
+		if err := terminal.Run(cfg, client, repoPath); err != nil {
+			return err
+		}
+		if err := recorder.Err(); err != nil {
+			return fmt.Errorf("failed to write recording: %w", err)
+		}
+		return file.Close()
+	}
+
 	return terminal.Run(cfg, client, repoPath)
-}
\ No newline at end of file
+}
+
+// sessionFlags holds the files given to record or replay a session, or the
+// command sent to a running tig instead of starting one
+type sessionFlags struct {
+	record string
+	replay string
+	send   string
+}
+
+// loadRecentRepos loads the recently opened repositories, nil when they
+// can't be kept track of
+func loadRecentRepos() *config.RecentRepos {
+	path, err := config.RecentReposPath()
+	if err != nil {
+		return nil
+	}
+	recent, _ := config.LoadRecentRepos(path)
+	return recent
+}
+
+// readSession reads a recording made with --record
+func readSession(path string) ([]ui.SessionEvent, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer file.Close()
+
+	events, err := ui.ReadSession(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return events, nil
+}
+
+// applyFlags parses the command line and applies the options given there on
+// top of the tigrc settings. Only flags actually given override anything.
+func applyFlags(cfg *config.Config, args []string) (sessionFlags, error) {
+	var session sessionFlags
+	fs := flag.NewFlagSet("tig", flag.ContinueOnError)
+	fs.StringVar(&session.record, "record", "", "record key events and screen frames to `file`")
+	fs.StringVar(&session.replay, "replay", "", "replay the key events recorded in `file`")
+	fs.StringVar(&session.send, "send", "", "send `command`, such as \"show commit <rev>\", to the tig listening on the control socket")
+	fs.String("control-socket", "", "unix socket to take commands on, auto for one per repository")
+	fs.Bool("single-instance", false, "switch the tig already running for the repository to the view instead of starting another")
+	fs.Bool("show-id", false, "show commit IDs in the main view")
+	fs.Bool("date-order", false, "order commits by date instead of topology")
+	fs.Bool("word-diff", false, "show word diffs in the pager")
+	fs.String("theme", "", "color theme to use")
+	fs.Bool("read-only", false, "refuse actions which change the repository")
+	fs.String("view", "", "view to start in: main, status, refs, stash or review")
+
+	if err := fs.Parse(args); err != nil {
+		return session, err
+	}
+	if fs.NArg() > 0 {
+		return session, fmt.Errorf("unexpected argument: %s", fs.Arg(0))
+	}
+
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		value := f.Value.String()
+		switch f.Name {
+		case "date-order":
+			if value == "true" {
+				err = cfg.Set("commit-order", "date")
+			} else {
+				err = cfg.Set("commit-order", "topo")
+			}
+		case "record", "replay", "send":
+		case "view":
+			err = cfg.Set("startup-view", value)
+		case "theme":
+			if !validTheme(value) {
+				err = fmt.Errorf("unknown theme: %s", value)
+				return
+			}
+			err = cfg.Set("theme", value)
+		default:
+			err = cfg.Set(f.Name, value)
+		}
+	})
+	return session, err
+}
+
+// validTheme checks that a theme is one of the predefined color schemes
+func validTheme(name string) bool {
+	for _, scheme := range ui.AvailableSchemes() {
+		if scheme == name {
+			return true
+		}
+	}
+	return false
+}
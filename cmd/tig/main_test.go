@@ -3,7 +3,9 @@ package main
 import (
 	"testing"
 
+	"github.com/azhao1981/tig/internal/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestVersion(t *testing.T) {
@@ -16,6 +18,46 @@ func TestRun(t *testing.T) {
 	// In a real scenario, we would use a mock terminal
 	// For now, we'll just test that the function exists and returns an error
 	// when there's no terminal
-	err := run()
+	err := run(nil)
+	assert.Error(t, err)
+}
+
+func TestApplyFlags(t *testing.T) {
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	require.NoError(t, cfg.Set("word-diff", "yes"))
+
+	session, err := applyFlags(cfg, []string{"--show-id", "--date-order", "--theme=dark", "--read-only", "--record", "session.log"})
+	assert.NoError(t, err)
+	assert.True(t, cfg.Views.Main.ShowID)
+	assert.True(t, cfg.General.ReadOnly)
+	assert.Equal(t, "date", cfg.General.CommitOrder)
+	assert.Equal(t, "dark", cfg.Colors.Scheme)
+	assert.Equal(t, sessionFlags{record: "session.log"}, session)
+
+	// Flags which are not given keep the tigrc value
+	assert.True(t, cfg.Views.Diff.WordDiff)
+
+	_, err = applyFlags(cfg, []string{"--word-diff=false"})
+	assert.NoError(t, err)
+	assert.False(t, cfg.Views.Diff.WordDiff)
+
+	// The view given on the command line wins over startup-view
+	require.NoError(t, cfg.Set("startup-view", "status"))
+	_, err = applyFlags(cfg, []string{"--view=refs"})
+	assert.NoError(t, err)
+	assert.Equal(t, "refs", cfg.General.StartupView)
+	_, err = applyFlags(cfg, []string{"--view=blame"})
+	assert.Error(t, err)
+
+	session, err = applyFlags(cfg, []string{"--control-socket=auto", "--single-instance", "--send", "show commit HEAD"})
+	assert.NoError(t, err)
+	assert.Equal(t, "auto", cfg.General.ControlSocket)
+	assert.True(t, cfg.General.SingleInstance)
+	assert.Equal(t, sessionFlags{send: "show commit HEAD"}, session)
+
+	_, err = applyFlags(cfg, []string{"--theme=neon"})
+	assert.Error(t, err)
+	_, err = applyFlags(cfg, []string{"--no-such-flag"})
 	assert.Error(t, err)
 }
\ No newline at end of file
@@ -1,8 +1,14 @@
 package config
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 )
 
 // Config represents the main configuration structure
@@ -13,6 +19,11 @@ type Config struct {
 	Colors    ColorConfig     `mapstructure:"colors"`
 	Views     ViewsConfig     `mapstructure:"views"`
 	General   GeneralConfig   `mapstructure:"general"`
+
+	// Path is the tigrc file the configuration was loaded from, if any
+	Path string `mapstructure:"-"`
+	// Warnings lists the tigrc lines which could not be applied
+	Warnings []string `mapstructure:"-"`
 }
 
 // UIConfig holds UI-related configuration
@@ -24,17 +35,25 @@ type UIConfig struct {
 	ShowDate      bool   `mapstructure:"show_date"`
 	ShowAuthor    bool   `mapstructure:"show_author"`
 	ShowLineNumbers bool `mapstructure:"show_line_numbers"`
+	ShowTrailingSpace bool `mapstructure:"show_trailing_space"`
+	ShowTabs        bool `mapstructure:"show_tabs"`
+	ShowCR          bool `mapstructure:"show_cr"`
+	Borderless      bool `mapstructure:"borderless"`
 }
 
 // GitConfig holds Git-related configuration
 type GitConfig struct {
 	AuthorWidth   int    `mapstructure:"author_width"`
+	AuthorDisplay string `mapstructure:"author_display"`
+	AuthorColors  bool   `mapstructure:"author_colors"`
 	DateFormat    string `mapstructure:"date_format"`
+	DateDisplay   string `mapstructure:"date_display"`
 	ShowNotes     bool   `mapstructure:"show_notes"`
 	ShowDiffStat  bool   `mapstructure:"show_diff_stat"`
 	ShowBranches  bool   `mapstructure:"show_branches"`
 	ShowRemotes   bool   `mapstructure:"show_remotes"`
 	ShowTags      bool   `mapstructure:"show_tags"`
+	BlameIgnoreRevs string `mapstructure:"blame_ignore_revs"`
 }
 
 // KeymapConfig holds key binding configuration
@@ -50,9 +69,10 @@ type ColorConfig struct {
 
 // ViewsConfig holds view-specific configuration
 type ViewsConfig struct {
-	Main   MainViewConfig   `mapstructure:"main"`
-	Diff   DiffViewConfig   `mapstructure:"diff"`
-	Status StatusViewConfig `mapstructure:"status"`
+	Main   MainViewConfig     `mapstructure:"main"`
+	Diff   DiffViewConfig     `mapstructure:"diff"`
+	Status StatusViewConfig   `mapstructure:"status"`
+	Log    map[string]LogView `mapstructure:"log"` // Filters of the main view saved by name
 }
 
 // MainViewConfig holds main view configuration
@@ -63,6 +83,10 @@ type MainViewConfig struct {
 	ShowDate     bool `mapstructure:"show_date"`
 	ShowAuthor   bool `mapstructure:"show_author"`
 	ShowCommitTitle bool `mapstructure:"show_commit_title"`
+	DateSeparators  bool `mapstructure:"date_separators"`
+	ShowAvatar      bool `mapstructure:"show_avatar"`
+	SubjectTooltip  bool `mapstructure:"subject_tooltip"` // Show the whole subject of the selected commit on the status line when cut
+	Filter          string `mapstructure:"filter"` // Filters or saved view the main view starts with
 }
 
 // DiffViewConfig holds diff view configuration
@@ -70,21 +94,43 @@ type DiffViewConfig struct {
 	ContextLines int  `mapstructure:"context_lines"`
 	ShowStat     bool `mapstructure:"show_stat"`
 	IgnoreSpace  bool `mapstructure:"ignore_space"`
+	WordDiff     bool `mapstructure:"word_diff"`
+	RenameThreshold int  `mapstructure:"rename_threshold"`
+	FindCopies      bool `mapstructure:"find_copies"`
+	HideEOLChanges  bool `mapstructure:"hide_eol_changes"`
+	StructuredDiffs string `mapstructure:"structured_diffs"` // pattern:mode pairs
 }
 
 // StatusViewConfig holds status view configuration
 type StatusViewConfig struct {
-	ShowUntracked bool `mapstructure:"show_untracked"`
-	ShowIgnored   bool `mapstructure:"show_ignored"`
+	ShowUntracked  bool   `mapstructure:"show_untracked"`
+	ShowIgnored    bool   `mapstructure:"show_ignored"`
+	Sort           string `mapstructure:"sort"`
+	GroupByDir     bool   `mapstructure:"group_by_dir"`
+	UntrackedLimit int    `mapstructure:"untracked_limit"`
+	Folded         string `mapstructure:"folded"` // Sections folded on startup
 }
 
 // GeneralConfig holds general configuration
 type GeneralConfig struct {
-	Editor          string `mapstructure:"editor"`
-	Pager           string `mapstructure:"pager"`
-	Terminal        string `mapstructure:"terminal"`
-	CommitOrder     string `mapstructure:"commit_order"`
-	VerticalSplit   bool   `mapstructure:"vertical_split"`
+	Editor           string `mapstructure:"editor"`
+	Pager            string `mapstructure:"pager"`
+	Terminal         string `mapstructure:"terminal"`
+	CommitOrder      string `mapstructure:"commit_order"`
+	VerticalSplit    bool   `mapstructure:"vertical_split"`
+	RefreshInterval  int    `mapstructure:"refresh_interval"`
+	Notify           string `mapstructure:"notify"`            // How to notify: bell, osc9
+	NotifyCommand    string `mapstructure:"notify_command"`    // Run with the notification text
+	NotifyOn         string `mapstructure:"notify_on"`         // Kinds of jobs to notify of
+	NotifyAfter      int    `mapstructure:"notify_after"`      // Seconds a job must take
+	AutoFetch        int    `mapstructure:"auto_fetch"`        // Minutes between fetches, 0 to disable
+	ReadOnly         bool   `mapstructure:"read_only"`         // Refuse actions changing the repository
+	ClipboardCommand string `mapstructure:"clipboard_command"` // Prints the clipboard, found when empty
+	CopyCommand      string `mapstructure:"copy_command"`      // Copies its input to the clipboard, found when empty
+	StartupView      string `mapstructure:"startup_view"`      // View shown on startup
+	ControlSocket    string `mapstructure:"control_socket"`    // Socket other programs send commands to, auto for one per repository
+	SingleInstance   bool   `mapstructure:"single_instance"`   // Hand over to the tig running for the repository
+	Confirm          map[string]string `mapstructure:"confirm"` // Confirmation level of each destructive action
 }
 
 // Load loads configuration from tigrc files and environment variables
@@ -94,11 +140,137 @@ func Load() (*Config, error) {
 	// Set default configuration
 	setDefaults(config)
 
-	// For now, just return the default configuration
-	// TODO: Implement configuration file parsing
+	// The first tigrc found wins, as with git's own search order
+	for _, path := range GetConfigPaths() {
+		path = os.ExpandEnv(path)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if err := config.LoadFile(path); err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	return config, nil
+}
+
+// LoadPath loads the default configuration overlaid with the given tigrc,
+// as used to reload the configuration after the file changed
+func LoadPath(path string) (*Config, error) {
+	config := &Config{}
+	setDefaults(config)
+	if err := config.LoadFile(path); err != nil {
+		return nil, err
+	}
 	return config, nil
 }
 
+// LoadFile applies the set, bind, color, view and confirm commands of a tigrc file on top
+// of the current configuration. Lines which cannot be applied are skipped
+// and recorded in Warnings, so one bad setting does not prevent startup.
+func (c *Config) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineno := 0
+	for scanner.Scan() {
+		lineno++
+		line := scanner.Text()
+		start := lineno
+
+		// A trailing backslash continues the line
+		for strings.HasSuffix(line, "\\") && scanner.Scan() {
+			lineno++
+			line = strings.TrimSuffix(line, "\\") + " " + scanner.Text()
+		}
+
+		if err := c.parseLine(line); err != nil {
+			c.Warnings = append(c.Warnings, fmt.Sprintf("%s:%d: %v", path, start, err))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	c.Path = path
+	return nil
+}
+
+// parseLine applies a single tigrc line
+func (c *Config) parseLine(line string) error {
+	line = stripComment(line)
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	switch fields[0] {
+	case "set":
+		// set name = value, where the value may contain spaces
+		name, value, ok := strings.Cut(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "set")), "=")
+		if !ok {
+			return fmt.Errorf("expected set <option> = <value>")
+		}
+		return c.Set(strings.TrimSpace(name), unquote(strings.TrimSpace(value)))
+	case "bind":
+		// bind keymap key action; only the generic keymap exists
+		if len(fields) < 4 {
+			return fmt.Errorf("expected bind <keymap> <key> <action>")
+		}
+		if fields[1] != "generic" {
+			return fmt.Errorf("unsupported keymap: %s", fields[1])
+		}
+		if c.Keymaps.Bindings == nil {
+			c.Keymaps.Bindings = make(map[string]string)
+		}
+		c.Keymaps.Bindings[strings.Join(fields[3:], " ")] = fields[2]
+		return nil
+	case "color":
+		if len(fields) < 3 {
+			return fmt.Errorf("expected color <area> <color>")
+		}
+		if c.Colors.Colors == nil {
+			c.Colors.Colors = make(map[string]string)
+		}
+		c.Colors.Colors[fields[1]] = fields[2]
+		return nil
+	case "view":
+		return c.parseViewLine(strings.TrimPrefix(strings.TrimSpace(line), "view"))
+	case "confirm":
+		return c.parseConfirmLine(fields[1:])
+	}
+	return fmt.Errorf("unknown command: %s", fields[0])
+}
+
+// stripComment removes a # comment, leaving # inside quoted values alone
+func stripComment(line string) string {
+	quoted := false
+	for i, ch := range line {
+		switch ch {
+		case '"':
+			quoted = !quoted
+		case '#':
+			if !quoted {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// unquote strips the double quotes around a tigrc value
+func unquote(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
 // setDefaults sets default configuration values
 func setDefaults(config *Config) {
 	// UI defaults
@@ -109,10 +281,16 @@ func setDefaults(config *Config) {
 	config.UI.ShowDate = true
 	config.UI.ShowAuthor = true
 	config.UI.ShowLineNumbers = true
+	config.UI.ShowTrailingSpace = true
+	config.UI.ShowTabs = false
+	config.UI.ShowCR = true
 
 	// Git defaults
 	config.Git.AuthorWidth = 20
+	config.Git.AuthorDisplay = "name"
+	config.Git.AuthorColors = false
 	config.Git.DateFormat = "%Y-%m-%d"
+	config.Git.DateDisplay = "absolute"
 	config.Git.ShowNotes = true
 	config.Git.ShowDiffStat = true
 	config.Git.ShowBranches = true
@@ -130,15 +308,25 @@ func setDefaults(config *Config) {
 	config.Views.Diff.ContextLines = 3
 	config.Views.Diff.ShowStat = true
 	config.Views.Diff.IgnoreSpace = false
+	config.Views.Diff.StructuredDiffs = "*.ipynb:notebook"
 
 	config.Views.Status.ShowUntracked = true
 	config.Views.Status.ShowIgnored = false
+	config.Views.Status.Sort = "path"
 
 	// General defaults
-	config.General.Editor = getDefaultEditor()
+	config.General.Editor = DefaultEditor()
 	config.General.Pager = "less"
 	config.General.CommitOrder = "topo"
 	config.General.VerticalSplit = false
+	config.General.RefreshInterval = 5
+	config.General.Notify = ""
+	config.General.NotifyCommand = ""
+	config.General.NotifyOn = "fetch push"
+	config.General.NotifyAfter = 5
+	config.General.AutoFetch = 0
+	config.General.ReadOnly = false
+	config.General.StartupView = "main"
 
 	// Keymaps defaults
 	config.Keymaps.Bindings = map[string]string{
@@ -173,12 +361,13 @@ func setDefaults(config *Config) {
 	}
 }
 
-// getDefaultEditor returns the default editor from environment or system
-func getDefaultEditor() string {
-	if editor := os.Getenv("EDITOR"); editor != "" {
+// DefaultEditor returns the editor used when the editor option is not set,
+// from the environment or the system
+func DefaultEditor() string {
+	if editor := os.Getenv("VISUAL"); editor != "" {
 		return editor
 	}
-	if editor := os.Getenv("VISUAL"); editor != "" {
+	if editor := os.Getenv("EDITOR"); editor != "" {
 		return editor
 	}
 	return "vi"
@@ -200,8 +389,137 @@ func (c *Config) Validate() error {
 	return nil
 }
 
-// Save saves the configuration to the default location
+// Save saves the configuration to the user's tigrc
 func (c *Config) Save() error {
-	// Add save logic here
+	path, err := UserConfigPath()
+	if err != nil {
+		return err
+	}
+	return c.SaveTo(path)
+}
+
+// SaveTo writes the configuration as a tigrc file. The file is replaced
+// atomically so a running tig watching it never reads a partial file.
+func (c *Config) SaveTo(path string) error {
+	var buf bytes.Buffer
+	c.Format(&buf)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tigrc-*")
+	if err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
 	return nil
+}
+
+// Format writes the configuration in tigrc syntax, with every option
+// described and all entries sorted so saved files diff cleanly
+func (c *Config) Format(w io.Writer) {
+	fmt.Fprintln(w, "# tig configuration, written by :save-config")
+	fmt.Fprintln(w, "#")
+	fmt.Fprintln(w, "# A running tig reloads this file when it changes.")
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Options")
+	for _, name := range OptionNames() {
+		value, _ := c.Get(name)
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "# %s\n", options[name].description)
+		fmt.Fprintf(w, "set %s = %s\n", name, quote(value))
+	}
+
+	if len(c.Keymaps.Bindings) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "## Key bindings")
+		fmt.Fprintln(w)
+		for _, action := range sortedKeys(c.Keymaps.Bindings) {
+			if key := c.Keymaps.Bindings[action]; key != "" {
+				fmt.Fprintf(w, "bind generic %s %s\n", key, action)
+			}
+		}
+	}
+
+	if len(c.Colors.Colors) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "## Colors")
+		fmt.Fprintln(w)
+		for _, area := range sortedKeys(c.Colors.Colors) {
+			fmt.Fprintf(w, "color %s %s\n", area, c.Colors.Colors[area])
+		}
+	}
+
+	if len(c.Views.Log) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "## Views")
+		fmt.Fprintln(w)
+		for _, name := range c.LogViewNames() {
+			fmt.Fprintf(w, "view %s %s\n", name, c.Views.Log[name])
+		}
+	}
+
+	if len(c.General.Confirm) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "## Confirmations")
+		fmt.Fprintln(w)
+		for _, action := range sortedKeys(c.General.Confirm) {
+			fmt.Fprintf(w, "confirm %s %s\n", action, c.General.Confirm[action])
+		}
+	}
+}
+
+// UserConfigPath returns the tigrc of the current user, preferring an
+// existing file under the XDG config directory over ~/.tigrc
+func UserConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(home, ".config")
+	}
+	if path := filepath.Join(configHome, "tig", "tigrc"); fileExists(path) {
+		return path, nil
+	}
+	return filepath.Join(home, ".tigrc"), nil
+}
+
+// fileExists reports whether a regular file exists at path
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// quote double quotes a tigrc value which would otherwise not survive
+// being read back
+func quote(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t#") {
+		return `"` + value + `"`
+	}
+	return value
 }
\ No newline at end of file
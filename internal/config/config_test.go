@@ -1,6 +1,9 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -66,10 +69,15 @@ func TestConfigValidation(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func TestGetDefaultEditor(t *testing.T) {
+func TestDefaultEditor(t *testing.T) {
 	// Test that we get a default editor
-	editor := getDefaultEditor()
+	editor := DefaultEditor()
 	assert.NotEmpty(t, editor)
+
+	// VISUAL comes before EDITOR, as with git
+	t.Setenv("VISUAL", "code --wait")
+	t.Setenv("EDITOR", "nano")
+	assert.Equal(t, "code --wait", DefaultEditor())
 }
 
 func TestGetConfigPaths(t *testing.T) {
@@ -262,4 +270,357 @@ func TestColorSchemes(t *testing.T) {
 	assert.Contains(t, cfg.Colors.Colors, "author")
 	assert.Contains(t, cfg.Colors.Colors, "date")
 	assert.Contains(t, cfg.Colors.Colors, "id")
-}
\ No newline at end of file
+}
+func TestSetOption(t *testing.T) {
+	cfg := &Config{}
+	setDefaults(cfg)
+
+	assert.NoError(t, cfg.Set("tab-size", "4"))
+	assert.Equal(t, 4, cfg.UI.TabSize)
+	assert.NoError(t, cfg.Set("show-id", "yes"))
+	assert.True(t, cfg.Views.Main.ShowID)
+	assert.False(t, cfg.Views.Main.DateSeparators)
+	assert.NoError(t, cfg.Set("date-separators", "yes"))
+	assert.True(t, cfg.Views.Main.DateSeparators)
+	assert.NoError(t, cfg.Set("commit-order", "date"))
+	assert.Equal(t, "date", cfg.General.CommitOrder)
+	assert.Equal(t, "absolute", cfg.Git.DateDisplay)
+	assert.NoError(t, cfg.Set("date-display", "relative"))
+	assert.Equal(t, "relative", cfg.Git.DateDisplay)
+	assert.Error(t, cfg.Set("date-display", "fuzzy"))
+	assert.NoError(t, cfg.Set("borderless", "yes"))
+	assert.NoError(t, cfg.Set("rename-threshold", "80"))
+	assert.NoError(t, cfg.Set("blame-ignore-revs", "abc123 v1.0"))
+	assert.Equal(t, "abc123 v1.0", cfg.Git.BlameIgnoreRevs)
+	assert.Equal(t, 80, cfg.Views.Diff.RenameThreshold)
+	assert.Error(t, cfg.Set("rename-threshold", "101"))
+	assert.NoError(t, cfg.Set("clipboard-command", "xsel -ob"))
+	assert.Equal(t, "xsel -ob", cfg.General.ClipboardCommand)
+	assert.NoError(t, cfg.Set("copy-command", "xclip -in"))
+	assert.Equal(t, "xclip -in", cfg.General.CopyCommand)
+	assert.Equal(t, "path", cfg.Views.Status.Sort)
+	assert.NoError(t, cfg.Set("status-sort", "size"))
+	assert.Equal(t, "size", cfg.Views.Status.Sort)
+	assert.Error(t, cfg.Set("status-sort", "name"))
+	assert.NoError(t, cfg.Set("status-group-by-dir", "yes"))
+	assert.True(t, cfg.Views.Status.GroupByDir)
+	assert.NoError(t, cfg.Set("status-untracked-limit", "50"))
+	assert.Equal(t, 50, cfg.Views.Status.UntrackedLimit)
+	assert.Error(t, cfg.Set("status-untracked-limit", "-1"))
+	assert.True(t, cfg.UI.Borderless)
+
+	value, err := cfg.Get("show-id")
+	assert.NoError(t, err)
+	assert.Equal(t, "yes", value)
+
+	assert.Error(t, cfg.Set("tab-size", "0"))
+	assert.Error(t, cfg.Set("show-id", "maybe"))
+	assert.Error(t, cfg.Set("commit-order", "random"))
+	assert.Error(t, cfg.Set("no-such-option", "1"))
+	assert.Contains(t, OptionNames(), "word-diff")
+}
+
+func TestSetListOption(t *testing.T) {
+	cfg := &Config{}
+	setDefaults(cfg)
+	assert.Equal(t, []string{"fetch", "push"}, ListValues(cfg.General.NotifyOn))
+
+	assert.NoError(t, cfg.Set("notify", "bell, osc9"))
+	assert.Equal(t, "bell osc9", cfg.General.Notify)
+	assert.NoError(t, cfg.Set("notify", ""))
+	assert.Empty(t, ListValues(cfg.General.Notify))
+
+	assert.EqualError(t, cfg.Set("notify-on", "push merge"), "notify-on: merge is not one of fetch, pull, push, maintenance")
+	assert.Equal(t, "fetch push", cfg.General.NotifyOn)
+}
+
+func TestStructuredDiffs(t *testing.T) {
+	cfg := &Config{}
+	setDefaults(cfg)
+	assert.Equal(t, "notebook", cfg.StructuredDiffMode("analysis/model.ipynb"))
+	assert.Empty(t, cfg.StructuredDiffMode("package.json"))
+
+	assert.NoError(t, cfg.Set("structured-diffs", "*.json:json, data/*.ipynb:notebook"))
+	assert.Equal(t, "*.json:json data/*.ipynb:notebook", cfg.Views.Diff.StructuredDiffs)
+	assert.Equal(t, "json", cfg.StructuredDiffMode("web/package.json"))
+	assert.Equal(t, "notebook", cfg.StructuredDiffMode("data/model.ipynb"))
+	assert.Empty(t, cfg.StructuredDiffMode("analysis/model.ipynb"))
+
+	assert.EqualError(t, cfg.Set("structured-diffs", "*.yaml:yaml"), "structured-diffs: yaml is not one of json, notebook")
+	assert.EqualError(t, cfg.Set("structured-diffs", "*.json"), "structured-diffs: expected pattern:mode, got *.json")
+	assert.Equal(t, "*.json:json data/*.ipynb:notebook", cfg.Views.Diff.StructuredDiffs)
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tigrc")
+	content := `# Example tigrc
+set tab-size = 4
+set date-format = "%Y-%m-%d %H:%M"
+set word-diff = yes  # trailing comment
+bind generic x !git show %(commit)
+color diff-add cyan
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	cfg := &Config{}
+	setDefaults(cfg)
+	require.NoError(t, cfg.LoadFile(path))
+
+	assert.Equal(t, path, cfg.Path)
+	assert.Equal(t, 4, cfg.UI.TabSize)
+	assert.Equal(t, "%Y-%m-%d %H:%M", cfg.Git.DateFormat)
+	assert.True(t, cfg.Views.Diff.WordDiff)
+	assert.Equal(t, "x", cfg.Keymaps.Bindings["!git show %(commit)"])
+	assert.Equal(t, "cyan", cfg.Colors.Colors["diff-add"])
+
+	// Bad lines are skipped with a warning, continuation lines are joined
+	content = "set tab-size\nset no-such-option = 1\nset tab-size = \\\n\t2\nbind main x quit\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	cfg.Warnings = nil
+	assert.NoError(t, cfg.LoadFile(path))
+	assert.Equal(t, 2, cfg.UI.TabSize)
+	require.Len(t, cfg.Warnings, 3)
+	assert.Contains(t, cfg.Warnings[0], "tigrc:1:")
+	assert.Contains(t, cfg.Warnings[1], "tigrc:2:")
+	assert.Contains(t, cfg.Warnings[2], "tigrc:5:")
+
+	assert.Error(t, cfg.LoadFile(filepath.Join(t.TempDir(), "missing")))
+}
+
+func TestSaveTo(t *testing.T) {
+	cfg := &Config{}
+	setDefaults(cfg)
+	require.NoError(t, cfg.Set("tab-size", "4"))
+	require.NoError(t, cfg.Set("date-format", "%Y-%m-%d %H:%M # local"))
+	cfg.Keymaps.Bindings["!git show %(commit)"] = "x"
+
+	path := filepath.Join(t.TempDir(), "tig", "tigrc")
+	require.NoError(t, cfg.SaveTo(path))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "# Number of spaces a tab expands to\nset tab-size = 4\n")
+	assert.Contains(t, string(content), "bind generic x !git show %(commit)\n")
+
+	// The saved file reads back to the same configuration
+	loaded, err := LoadPath(path)
+	require.NoError(t, err)
+	assert.Empty(t, loaded.Warnings)
+	assert.Equal(t, 4, loaded.UI.TabSize)
+	assert.Equal(t, "%Y-%m-%d %H:%M # local", loaded.Git.DateFormat)
+	assert.Equal(t, cfg.Keymaps.Bindings, loaded.Keymaps.Bindings)
+	assert.Equal(t, cfg.Colors.Colors, loaded.Colors.Colors)
+
+	// Saving is stable
+	require.NoError(t, loaded.SaveTo(path))
+	again, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(content), string(again))
+}
+
+func TestUserConfigPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	path, err := UserConfigPath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".tigrc"), path)
+
+	xdg := filepath.Join(home, ".config", "tig", "tigrc")
+	require.NoError(t, os.MkdirAll(filepath.Dir(xdg), 0755))
+	require.NoError(t, os.WriteFile(xdg, nil, 0644))
+	path, err = UserConfigPath()
+	require.NoError(t, err)
+	assert.Equal(t, xdg, path)
+}
+
+func TestRecentRepos(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_STATE_HOME", "")
+
+	path, err := RecentReposPath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".local", "state", "tig", "recent"), path)
+
+	dir, err := AuditLogDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".local", "state", "tig", "audit"), dir)
+
+	recent, err := LoadRecentRepos(path)
+	require.NoError(t, err)
+	assert.Empty(t, recent.Repos)
+
+	require.NoError(t, recent.Add("/src/a"))
+	require.NoError(t, recent.Add("/src/b"))
+	require.NoError(t, recent.Add("/src/a"))
+	assert.Equal(t, []string{"/src/a", "/src/b"}, recent.Repos)
+
+	for i := 0; i < MaxRecentRepos; i++ {
+		require.NoError(t, recent.Add(filepath.Join("/src", "more", string(rune('a'+i)))))
+	}
+	assert.Len(t, recent.Repos, MaxRecentRepos)
+
+	loaded, err := LoadRecentRepos(path)
+	require.NoError(t, err)
+	assert.Equal(t, recent.Repos, loaded.Repos)
+}
+
+func TestLogViews(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tigrc")
+	content := `set tab-size = 4
+view mine author=jane since="2 weeks ago" # my work
+view docs path=docs/ branch=main
+view bad author
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	cfg, err := LoadPath(path)
+	require.NoError(t, err)
+	require.Len(t, cfg.Warnings, 1)
+	assert.Contains(t, cfg.Warnings[0], "tigrc:4:")
+	assert.Equal(t, []string{"docs", "mine"}, cfg.LogViewNames())
+	assert.Equal(t, LogView{Author: "jane", Since: "2 weeks ago"}, cfg.Views.Log["mine"])
+	assert.Equal(t, `author=jane since="2 weeks ago"`, cfg.Views.Log["mine"].String())
+
+	// Saving appends to the file, keeping the rest as written
+	view, err := ParseLogView(`author="Jane Doe" until=2024-01-01`)
+	require.NoError(t, err)
+	require.NoError(t, cfg.SaveLogView("mine", view))
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(saved), content))
+	assert.True(t, strings.HasSuffix(string(saved), "\nview mine author=\"Jane Doe\" until=2024-01-01\n"))
+
+	loaded, err := LoadPath(path)
+	require.NoError(t, err)
+	assert.Equal(t, cfg.Views.Log, loaded.Views.Log)
+
+	// The views survive :save-config
+	require.NoError(t, loaded.SaveTo(path))
+	again, err := LoadPath(path)
+	require.NoError(t, err)
+	assert.Empty(t, again.Warnings)
+	assert.Equal(t, cfg.Views.Log, again.Views.Log)
+
+	assert.Error(t, cfg.SaveLogView("my view", view))
+	assert.Error(t, cfg.SaveLogView("empty", LogView{}))
+	_, err = ParseLogView("date=today")
+	assert.Error(t, err)
+	_, err = ParseLogView(`author="unterminated`)
+	assert.Error(t, err)
+	_, err = ParseLogView("branch=--all")
+	assert.Error(t, err)
+}
+
+func TestMainFilter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tigrc")
+	content := `set main-filter = mine
+set status-folded = untracked, staged
+set startup-view = status
+view mine author=jane since="2 weeks ago"
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	cfg, err := LoadPath(path)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Warnings)
+	assert.Equal(t, "untracked staged", cfg.Views.Status.Folded)
+	assert.Equal(t, "status", cfg.General.StartupView)
+
+	// A saved view may be defined after the option naming it
+	name, view, err := cfg.MainFilter()
+	require.NoError(t, err)
+	assert.Equal(t, "mine", name)
+	assert.Equal(t, LogView{Author: "jane", Since: "2 weeks ago"}, view)
+
+	require.NoError(t, cfg.Set("main-filter", `branch=HEAD author="Jane Doe"`))
+	assert.Equal(t, `author="Jane Doe" branch=HEAD`, cfg.Views.Main.Filter)
+	name, view, err = cfg.MainFilter()
+	require.NoError(t, err)
+	assert.Empty(t, name)
+	assert.Equal(t, LogView{Author: "Jane Doe", Branch: "HEAD"}, view)
+
+	require.NoError(t, cfg.Set("main-filter", "theirs"))
+	_, _, err = cfg.MainFilter()
+	assert.Error(t, err)
+	assert.Error(t, cfg.Set("main-filter", "branch=--all"))
+	assert.Error(t, cfg.Set("startup-view", "blame"))
+	assert.Error(t, cfg.Set("status-folded", "ignored"))
+}
+
+func TestConfirmLevels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tigrc")
+	content := `confirm discard simple
+confirm force-push none
+confirm rebase name
+confirm branch-delete maybe
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	cfg, err := LoadPath(path)
+	require.NoError(t, err)
+	require.Len(t, cfg.Warnings, 2)
+	assert.Contains(t, cfg.Warnings[0], "unknown action to confirm: rebase")
+	assert.Contains(t, cfg.Warnings[1], "unknown confirmation level: maybe")
+
+	assert.Equal(t, ConfirmSimple, cfg.ConfirmLevel("discard"))
+	assert.Equal(t, ConfirmNone, cfg.ConfirmLevel("force-push"))
+	assert.Equal(t, ConfirmSimple, cfg.ConfirmLevel("branch-delete"))
+	assert.Equal(t, ConfirmName, (&Config{}).ConfirmLevel("force-push"))
+	assert.Equal(t, []string{"branch-delete", "discard", "force-push", "reset", "reset-hard"}, ConfirmActions())
+
+	// The levels survive :save-config
+	require.NoError(t, cfg.SaveTo(path))
+	loaded, err := LoadPath(path)
+	require.NoError(t, err)
+	assert.Empty(t, loaded.Warnings)
+	assert.Equal(t, cfg.General.Confirm, loaded.General.Confirm)
+}
+
+func TestControlSocketPath(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	t.Setenv("HOME", "/home/jane")
+
+	path, err := ControlSocketPath("", "/src/tig")
+	require.NoError(t, err)
+	assert.Empty(t, path)
+
+	path, err = ControlSocketPath("~/tig.sock", "/src/tig")
+	require.NoError(t, err)
+	assert.Equal(t, "/home/jane/tig.sock", path)
+
+	// Each repository has a socket of its own with auto
+	path, err = ControlSocketPath("auto", "/src/tig")
+	require.NoError(t, err)
+	assert.Equal(t, "/run/user/1000/tig", filepath.Dir(path))
+	assert.True(t, strings.HasSuffix(path, ".sock"))
+	same, err := ControlSocketPath("auto", "/src/tig/")
+	require.NoError(t, err)
+	assert.Equal(t, path, same)
+	other, err := ControlSocketPath("auto", "/src/git")
+	require.NoError(t, err)
+	assert.NotEqual(t, path, other)
+}
+
+func TestControlSocketPathTempDir(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	t.Setenv("TMPDIR", tmp)
+
+	// The directory of the user is made for nobody else to get in
+	path, err := ControlSocketPath("auto", "/src/tig")
+	require.NoError(t, err)
+	dir := filepath.Dir(path)
+	assert.Equal(t, tmp, filepath.Dir(dir))
+	info, err := os.Stat(dir)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	// One others can get in, or a link to elsewhere, is refused
+	require.NoError(t, os.Chmod(dir, 0755))
+	_, err = ControlSocketPath("auto", "/src/tig")
+	assert.ErrorContains(t, err, "mode 0755")
+	require.NoError(t, os.Remove(dir))
+	require.NoError(t, os.Symlink(t.TempDir(), dir))
+	_, err = ControlSocketPath("auto", "/src/tig")
+	assert.ErrorContains(t, err, "not a directory of yours")
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Confirmation levels of destructive actions, set in tigrc as
+//
+//	confirm force-push name
+const (
+	ConfirmNone   = "none"   // Run the action straight away
+	ConfirmSimple = "simple" // Ask y/n
+	ConfirmName   = "name"   // Ask to type the name of what the action destroys
+)
+
+// confirmDefaults are the confirmation levels of the destructive actions
+// when tigrc leaves them alone
+var confirmDefaults = map[string]string{
+	"discard":       ConfirmNone,   // :undo-discard restores the file
+	"reset":         ConfirmSimple, // Soft and mixed resets, which move the branch
+	"reset-hard":    ConfirmSimple,
+	"branch-delete": ConfirmSimple,
+	"force-push":    ConfirmName,
+}
+
+// ConfirmActions returns the actions whose confirmation can be set, sorted
+func ConfirmActions() []string {
+	return sortedKeys(confirmDefaults)
+}
+
+// ConfirmLevel returns how running a destructive action is confirmed
+func (c *Config) ConfirmLevel(action string) string {
+	if level, ok := c.General.Confirm[action]; ok {
+		return level
+	}
+	return confirmDefaults[action]
+}
+
+// parseConfirmLine applies a confirm line, given what follows "confirm"
+func (c *Config) parseConfirmLine(fields []string) error {
+	if len(fields) != 2 {
+		return fmt.Errorf("expected confirm <action> <none|simple|name>")
+	}
+	action, level := fields[0], fields[1]
+	if _, ok := confirmDefaults[action]; !ok {
+		return fmt.Errorf("unknown action to confirm: %s (one of %s)", action, strings.Join(ConfirmActions(), ", "))
+	}
+	switch level {
+	case ConfirmNone, ConfirmSimple, ConfirmName:
+	default:
+		return fmt.Errorf("unknown confirmation level: %s (none, simple or name)", level)
+	}
+
+	if c.General.Confirm == nil {
+		c.General.Confirm = make(map[string]string)
+	}
+	c.General.Confirm[action] = level
+	return nil
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ControlSocketPath returns where the control socket set by the
+// control-socket option is for the repository at root, empty when there is
+// none. Each repository has its own socket in the runtime directory with
+// auto, so that another tig, or an editor, finds it from the repository
+// alone.
+func ControlSocketPath(value, root string) (string, error) {
+	switch value {
+	case "":
+		return "", nil
+	case "auto":
+		dir, err := runtimeDir()
+		if err != nil {
+			return "", err
+		}
+		sum := sha256.Sum256([]byte(filepath.Clean(root)))
+		return filepath.Join(dir, hex.EncodeToString(sum[:8])+".sock"), nil
+	}
+
+	path := os.ExpandEnv(value)
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find home directory: %w", err)
+		}
+		path = filepath.Join(home, rest)
+	}
+	return path, nil
+}
+
+// runtimeDir returns the directory tig keeps its sockets in, under the XDG
+// runtime directory or, without one, a directory of the user in the
+// temporary directory. Anyone may create the latter first, so it is only
+// used when it is a directory of the user that nobody else can get in.
+func runtimeDir() (string, error) {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "tig"), nil
+	}
+
+	dir := filepath.Join(os.TempDir(), fmt.Sprintf("tig-%d", os.Getuid()))
+	if err := os.Mkdir(dir, 0700); err != nil && !os.IsExist(err) {
+		return "", err
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() || !ownedByUser(info) {
+		return "", fmt.Errorf("refusing to use %s: not a directory of yours", dir)
+	}
+	if info.Mode().Perm() != 0700 {
+		return "", fmt.Errorf("refusing to use %s: mode %04o rather than 0700", dir, info.Mode().Perm())
+	}
+	return dir, nil
+}
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// LogView is a named combination of filters of the main view, written in
+// tigrc as
+//
+//	view my-recent-work author=jane since="2 weeks ago" path=src/
+type LogView struct {
+	Author string // Pattern matched against author names and emails
+	Path   string // File or directory the commits touch
+	Since  string // Dates in any format git log understands
+	Until  string
+	Branch string // Revision the log starts from, HEAD when empty
+}
+
+// logViewFields are the filters of a view in the order they are written
+var logViewFields = []string{"author", "path", "since", "until", "branch"}
+
+// field returns the filter of a view with the given name
+func (v *LogView) field(name string) *string {
+	switch name {
+	case "author":
+		return &v.Author
+	case "path":
+		return &v.Path
+	case "since":
+		return &v.Since
+	case "until":
+		return &v.Until
+	case "branch":
+		return &v.Branch
+	}
+	return nil
+}
+
+// IsEmpty returns whether the view filters nothing
+func (v LogView) IsEmpty() bool {
+	return v == LogView{}
+}
+
+// String writes the filters of the view as name=value pairs, quoting the
+// values as tigrc does
+func (v LogView) String() string {
+	var pairs []string
+	for _, name := range logViewFields {
+		if value := *v.field(name); value != "" {
+			pairs = append(pairs, name+"="+quote(value))
+		}
+	}
+	return strings.Join(pairs, " ")
+}
+
+// ParseLogView parses filters written as name=value pairs, values with
+// spaces being double quoted
+func ParseLogView(text string) (LogView, error) {
+	words, err := splitQuoted(text)
+	if err != nil {
+		return LogView{}, err
+	}
+	return parseLogViewFields(words)
+}
+
+// parseLogViewFields parses the name=value pairs of a view
+func parseLogViewFields(words []string) (LogView, error) {
+	var view LogView
+	for _, word := range words {
+		name, value, ok := strings.Cut(word, "=")
+		field := view.field(name)
+		if !ok || field == nil {
+			return LogView{}, fmt.Errorf("expected <filter>=<value> with filter one of %s, got %q", strings.Join(logViewFields, ", "), word)
+		}
+		*field = value
+	}
+	if strings.HasPrefix(view.Branch, "-") {
+		return LogView{}, fmt.Errorf("invalid branch: %s", view.Branch)
+	}
+	return view, nil
+}
+
+// splitQuoted splits text at spaces outside of double quotes, removing the
+// quotes
+func splitQuoted(text string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	quoted, inWord := false, false
+	for _, ch := range text {
+		switch {
+		case ch == '"':
+			quoted, inWord = !quoted, true
+		case !quoted && (ch == ' ' || ch == '\t'):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(ch)
+			inWord = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// parseViewLine applies a view line of tigrc, the text following "view"
+func (c *Config) parseViewLine(text string) error {
+	words, err := splitQuoted(text)
+	if err != nil {
+		return err
+	}
+	if len(words) < 2 {
+		return fmt.Errorf("expected view <name> <filter>=<value>...")
+	}
+	view, err := parseLogViewFields(words[1:])
+	if err != nil {
+		return err
+	}
+	if c.Views.Log == nil {
+		c.Views.Log = make(map[string]LogView)
+	}
+	c.Views.Log[words[0]] = view
+	return nil
+}
+
+// LogViewNames returns the names of the saved views, sorted
+func (c *Config) LogViewNames() []string {
+	names := make([]string, 0, len(c.Views.Log))
+	for name := range c.Views.Log {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SaveLogView saves a view under a name, appending it to the tigrc the
+// configuration was loaded from, or to the user's, so the rest of the file
+// is kept as written. A view saved again under the same name replaces the
+// earlier one when the file is read back.
+func (c *Config) SaveLogView(name string, view LogView) error {
+	if name == "" || strings.ContainsAny(name, " \t\"=#") {
+		return fmt.Errorf("invalid view name: %q", name)
+	}
+	if view.IsEmpty() {
+		return fmt.Errorf("the view filters nothing")
+	}
+
+	path := c.Path
+	if path == "" {
+		var err error
+		if path, err = UserConfigPath(); err != nil {
+			return err
+		}
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to save view: %w", err)
+	}
+	if _, err := fmt.Fprintf(file, "view %s %s\n", name, view); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to save view: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to save view: %w", err)
+	}
+
+	if c.Views.Log == nil {
+		c.Views.Log = make(map[string]LogView)
+	}
+	c.Views.Log[name] = view
+	return nil
+}
+
+// mainFilterOption creates an option for a field holding either filters
+// written as in view lines or the name of a saved view, which may only be
+// defined further down the tigrc
+func mainFilterOption(description string, field func(c *Config) *string) option {
+	return option{
+		description: description,
+		get: func(c *Config) string {
+			return *field(c)
+		},
+		set: func(c *Config, value string) error {
+			value = strings.TrimSpace(value)
+			if strings.Contains(value, "=") {
+				view, err := ParseLogView(value)
+				if err != nil {
+					return err
+				}
+				value = view.String()
+			} else if strings.ContainsAny(value, " \t\"#") {
+				return fmt.Errorf("invalid view name: %q", value)
+			}
+			*field(c) = value
+			return nil
+		},
+	}
+}
+
+// MainFilter returns the filters the main view starts with, along with the
+// name of the saved view they come from, if any
+func (c *Config) MainFilter() (string, LogView, error) {
+	value := c.Views.Main.Filter
+	if value == "" || strings.Contains(value, "=") {
+		view, err := ParseLogView(value)
+		return "", view, err
+	}
+	view, ok := c.Views.Log[value]
+	if !ok {
+		return "", LogView{}, fmt.Errorf("main-filter: no view named %s", value)
+	}
+	return value, view, nil
+}
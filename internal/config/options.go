@@ -0,0 +1,233 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// option describes a setting which can be changed by name, as done by the
+// set command of tigrc files and the command line flags
+type option struct {
+	description string
+	get         func(c *Config) string
+	set         func(c *Config, value string) error
+}
+
+// options maps the tigrc names of the settings to the fields they change
+var options = map[string]option{
+	"tab-size":               intOption("Number of spaces a tab expands to", func(c *Config) *int { return &c.UI.TabSize }, 1),
+	"commit-order":           choiceOption("Order of commits in the main view: auto, default, topo, date or reverse", func(c *Config) *string { return &c.General.CommitOrder }, "auto", "default", "topo", "date", "reverse"),
+	"ignore-case":            boolOption("Ignore case when searching", func(c *Config) *bool { return &c.UI.IgnoreCase }),
+	"show-trailing-space":    boolOption("Highlight trailing whitespace of added lines", func(c *Config) *bool { return &c.UI.ShowTrailingSpace }),
+	"show-tabs":              boolOption("Mark tab characters", func(c *Config) *bool { return &c.UI.ShowTabs }),
+	"show-cr":                boolOption("Show carriage returns of CRLF line endings as ^M", func(c *Config) *bool { return &c.UI.ShowCR }),
+	"show-line-numbers":      boolOption("Show line numbers", func(c *Config) *bool { return &c.UI.ShowLineNumbers }),
+	"author-width":           intOption("Width of the author column", func(c *Config) *int { return &c.Git.AuthorWidth }, 0),
+	"author-display":         choiceOption("How authors are shown: name, email or initials", func(c *Config) *string { return &c.Git.AuthorDisplay }, "name", "email", "initials"),
+	"author-colors":          boolOption("Color each author differently, by their email", func(c *Config) *bool { return &c.Git.AuthorColors }),
+	"date-format":            stringOption("strftime format of commit dates", func(c *Config) *string { return &c.Git.DateFormat }),
+	"blame-ignore-revs":      stringOption("Revisions, separated by spaces, whose changes blame skips in addition to those of .git-blame-ignore-revs", func(c *Config) *string { return &c.Git.BlameIgnoreRevs }),
+	"date-display":           choiceOption("How commit dates are shown: absolute, local to the time zone of tig, or relative such as 5 minutes ago", func(c *Config) *string { return &c.Git.DateDisplay }, "absolute", "local", "relative"),
+	"show-notes":             boolOption("Show git notes in the diff view", func(c *Config) *bool { return &c.Git.ShowNotes }),
+	"show-id":                boolOption("Show commit IDs in the main view", func(c *Config) *bool { return &c.Views.Main.ShowID }),
+	"show-date":              boolOption("Show commit dates in the main view", func(c *Config) *bool { return &c.Views.Main.ShowDate }),
+	"show-author":            boolOption("Show commit authors in the main view", func(c *Config) *bool { return &c.Views.Main.ShowAuthor }),
+	"show-avatar":            boolOption("Show the initials of commit authors on a color of their own in the main view", func(c *Config) *bool { return &c.Views.Main.ShowAvatar }),
+	"subject-tooltip":        boolOption("Show the whole subject of the selected commit on the status line of the main view when it is cut", func(c *Config) *bool { return &c.Views.Main.SubjectTooltip }),
+	"show-refs":              boolOption("Show branches and tags in the main view", func(c *Config) *bool { return &c.Views.Main.ShowRefs }),
+	"show-graph":             boolOption("Show the revision graph in the main view", func(c *Config) *bool { return &c.Views.Main.ShowGraph }),
+	"main-filter":            mainFilterOption("Filters the main view starts with, written as in view lines such as branch=HEAD author=jane, or the name of a saved view; empty for the commits of all branches", func(c *Config) *string { return &c.Views.Main.Filter }),
+	"date-separators":        boolOption("Separate the commits of different days in the main view", func(c *Config) *bool { return &c.Views.Main.DateSeparators }),
+	"diff-context":           intOption("Number of context lines around changes", func(c *Config) *int { return &c.Views.Diff.ContextLines }, 0),
+	"diff-stat":              boolOption("Show a diffstat above diffs", func(c *Config) *bool { return &c.Views.Diff.ShowStat }),
+	"ignore-space":           boolOption("Ignore whitespace changes in diffs", func(c *Config) *bool { return &c.Views.Diff.IgnoreSpace }),
+	"rename-threshold":       percentOption("Similarity percentage from which files are shown as renamed or copied in commit diffs, 0 for git's default", func(c *Config) *int { return &c.Views.Diff.RenameThreshold }),
+	"find-copies":            boolOption("Show files copied from other files changed by a commit in its diff", func(c *Config) *bool { return &c.Views.Diff.FindCopies }),
+	"hide-eol-changes":       boolOption("Hide the lines of diffs whose line ending alone changed", func(c *Config) *bool { return &c.Views.Diff.HideEOLChanges }),
+	"structured-diffs":       structuredDiffOption("Diffs of files matching patterns shown structured, as pattern:mode pairs where mode is json, sorting keys and normalizing indentation, or notebook, which also strips cell outputs", func(c *Config) *string { return &c.Views.Diff.StructuredDiffs }),
+	"word-diff":              boolOption("Show word diffs in the pager", func(c *Config) *bool { return &c.Views.Diff.WordDiff }),
+	"show-untracked":         boolOption("Show untracked files in the status view", func(c *Config) *bool { return &c.Views.Status.ShowUntracked }),
+	"status-sort":            choiceOption("Order of files in the status view: path, extension, mtime for the most recently modified first, or size for the most changed lines first", func(c *Config) *string { return &c.Views.Status.Sort }, "path", "extension", "mtime", "size"),
+	"status-group-by-dir":    boolOption("Group files of the status view by top-level directory", func(c *Config) *bool { return &c.Views.Status.GroupByDir }),
+	"status-folded":          listOption("Sections of the status view folded on startup: staged, modified, untracked and/or conflict", func(c *Config) *string { return &c.Views.Status.Folded }, "staged", "modified", "untracked", "conflict"),
+	"status-untracked-limit": intOption("Untracked files listed by the status view before the others are left out, 0 for no limit", func(c *Config) *int { return &c.Views.Status.UntrackedLimit }, 0),
+	"editor":                 stringOption("Command used to edit files", func(c *Config) *string { return &c.General.Editor }),
+	"pager":                  stringOption("Command used to page raw output", func(c *Config) *string { return &c.General.Pager }),
+	"vertical-split":         boolOption("Split views vertically", func(c *Config) *bool { return &c.General.VerticalSplit }),
+	"borderless":             boolOption("Draw views without borders, keeping their title and status lines", func(c *Config) *bool { return &c.UI.Borderless }),
+	"refresh-interval":       intOption("Seconds between refreshes of the current view, 0 to disable", func(c *Config) *int { return &c.General.RefreshInterval }, 0),
+	"auto-fetch":             intOption("Minutes between fetches in the background, 0 to disable; tig.autoFetch in a repository's git config overrides it", func(c *Config) *int { return &c.General.AutoFetch }, 0),
+	"notify":                 listOption("How to notify of finished background jobs: bell and/or osc9", func(c *Config) *string { return &c.General.Notify }, "bell", "osc9"),
+	"notify-command":         stringOption("Command run with the text of notifications, such as notify-send", func(c *Config) *string { return &c.General.NotifyCommand }),
+	"notify-on":              listOption("Kinds of background jobs to notify of: fetch, pull, push and/or maintenance", func(c *Config) *string { return &c.General.NotifyOn }, "fetch", "pull", "push", "maintenance"),
+	"notify-after":           intOption("Seconds a background job must take to be notified of", func(c *Config) *int { return &c.General.NotifyAfter }, 0),
+	"read-only":              boolOption("Refuse actions which change the repository, such as staging, committing or pushing", func(c *Config) *bool { return &c.General.ReadOnly }),
+	"clipboard-command":      stringOption("Command printing the system clipboard pasted with Ctrl+V, such as xclip -o; found among the usual ones when empty", func(c *Config) *string { return &c.General.ClipboardCommand }),
+	"copy-command":           stringOption("Command the text copied with y is piped to, such as xclip -in; found among the usual ones when empty", func(c *Config) *string { return &c.General.CopyCommand }),
+	"startup-view":           choiceOption("View shown on startup: main, status, refs, stash or review", func(c *Config) *string { return &c.General.StartupView }, "main", "status", "refs", "stash", "review"),
+	"control-socket":         stringOption("Unix socket editors and other programs send commands to, such as show commit <rev> or blame <file>:<line>; auto for one per repository in the runtime directory, empty for none", func(c *Config) *string { return &c.General.ControlSocket }),
+	"single-instance":        boolOption("Show the view asked for in the tig already running for the repository and exit instead of starting another, found through its control socket, auto when control-socket is empty", func(c *Config) *bool { return &c.General.SingleInstance }),
+	"theme":                  stringOption("Color scheme: default, dark, light, monochrome, solarized-dark or solarized-light", func(c *Config) *string { return &c.Colors.Scheme }),
+}
+
+// Set changes the named option, parsing the value as tigrc does
+func (c *Config) Set(name, value string) error {
+	opt, ok := options[name]
+	if !ok {
+		return fmt.Errorf("unknown option: %s", name)
+	}
+	if err := opt.set(c, value); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// Get returns the value of the named option formatted as in tigrc
+func (c *Config) Get(name string) (string, error) {
+	opt, ok := options[name]
+	if !ok {
+		return "", fmt.Errorf("unknown option: %s", name)
+	}
+	return opt.get(c), nil
+}
+
+// OptionNames returns the names of all options in sorted order
+func OptionNames() []string {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseBool parses the boolean spellings accepted by tig
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "yes", "true", "on", "1":
+		return true, nil
+	case "no", "false", "off", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean: %s", value)
+}
+
+// boolOption creates an option for a boolean field
+func boolOption(description string, field func(c *Config) *bool) option {
+	return option{
+		description: description,
+		get: func(c *Config) string {
+			if *field(c) {
+				return "yes"
+			}
+			return "no"
+		},
+		set: func(c *Config, value string) error {
+			b, err := parseBool(value)
+			if err != nil {
+				return err
+			}
+			*field(c) = b
+			return nil
+		},
+	}
+}
+
+// intOption creates an option for an integer field with a lower bound
+func intOption(description string, field func(c *Config) *int, min int) option {
+	return option{
+		description: description,
+		get: func(c *Config) string {
+			return strconv.Itoa(*field(c))
+		},
+		set: func(c *Config, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid number: %s", value)
+			}
+			if n < min {
+				return fmt.Errorf("must be at least %d", min)
+			}
+			*field(c) = n
+			return nil
+		},
+	}
+}
+
+// percentOption creates an option for a percentage field
+func percentOption(description string, field func(c *Config) *int) option {
+	opt := intOption(description, field, 0)
+	set := opt.set
+	opt.set = func(c *Config, value string) error {
+		if n, err := strconv.Atoi(value); err == nil && n > 100 {
+			return fmt.Errorf("must be at most 100")
+		}
+		return set(c, value)
+	}
+	return opt
+}
+
+// stringOption creates an option for a free-form string field
+func stringOption(description string, field func(c *Config) *string) option {
+	return option{
+		description: description,
+		get: func(c *Config) string {
+			return *field(c)
+		},
+		set: func(c *Config, value string) error {
+			*field(c) = value
+			return nil
+		},
+	}
+}
+
+// listOption creates an option for a string field holding any number of
+// the given values, separated by spaces or commas
+func listOption(description string, field func(c *Config) *string, choices ...string) option {
+	return option{
+		description: description,
+		get: func(c *Config) string {
+			return *field(c)
+		},
+		set: func(c *Config, value string) error {
+			values := ListValues(value)
+			for _, v := range values {
+				if !slices.Contains(choices, v) {
+					return fmt.Errorf("%s is not one of %s", v, strings.Join(choices, ", "))
+				}
+			}
+			*field(c) = strings.Join(values, " ")
+			return nil
+		},
+	}
+}
+
+// ListValues splits the value of a list option
+func ListValues(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
+
+// choiceOption creates an option for a string field restricted to the
+// given values
+func choiceOption(description string, field func(c *Config) *string, choices ...string) option {
+	return option{
+		description: description,
+		get: func(c *Config) string {
+			return *field(c)
+		},
+		set: func(c *Config, value string) error {
+			for _, choice := range choices {
+				if value == choice {
+					*field(c) = value
+					return nil
+				}
+			}
+			return fmt.Errorf("must be one of %s", strings.Join(choices, ", "))
+		},
+	}
+}
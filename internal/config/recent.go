@@ -0,0 +1,100 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxRecentRepos is how many repositories are remembered as recently opened
+const MaxRecentRepos = 10
+
+// RecentRepos is the list of repositories opened last, most recent first,
+// stored one path per line
+type RecentRepos struct {
+	path  string
+	Repos []string
+}
+
+// RecentReposPath returns where the recently opened repositories are
+// remembered, under the XDG state directory
+func RecentReposPath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "recent"), nil
+}
+
+// AuditLogDir returns the directory the audit logs of repositories are
+// kept in, under the XDG state directory
+func AuditLogDir() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "audit"), nil
+}
+
+// stateDir returns the directory tig keeps its state in
+func stateDir() (string, error) {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find home directory: %w", err)
+		}
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateHome, "tig"), nil
+}
+
+// LoadRecentRepos reads the recently opened repositories from path, a
+// missing file meaning none were opened yet
+func LoadRecentRepos(path string) (*RecentRepos, error) {
+	recent := &RecentRepos{path: path}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return recent, nil
+	}
+	if err != nil {
+		return recent, fmt.Errorf("failed to read recent repositories: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if repo := strings.TrimSpace(scanner.Text()); repo != "" && len(recent.Repos) < MaxRecentRepos {
+			recent.Repos = append(recent.Repos, repo)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return recent, fmt.Errorf("failed to read recent repositories: %w", err)
+	}
+	return recent, nil
+}
+
+// Add moves a repository to the top of the list and saves it
+func (r *RecentRepos) Add(repo string) error {
+	repos := []string{repo}
+	for _, existing := range r.Repos {
+		if existing != repo && len(repos) < MaxRecentRepos {
+			repos = append(repos, existing)
+		}
+	}
+	r.Repos = repos
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to save recent repositories: %w", err)
+	}
+	data := strings.Join(r.Repos, "\n") + "\n"
+	if err := os.WriteFile(r.path, []byte(data), 0644); err != nil {
+		return fmt.Errorf("failed to save recent repositories: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// StructuredDiffModes are the ways of rendering diffs of structured files:
+// json sorts keys and normalizes indentation, notebook also strips the
+// outputs of Jupyter notebook cells
+var StructuredDiffModes = []string{"json", "notebook"}
+
+// StructuredDiff renders the diffs of the paths matching a pattern with a
+// structured diff mode
+type StructuredDiff struct {
+	Pattern string
+	Mode    string
+}
+
+// Matches returns whether a path matches the pattern, as a whole or by its
+// base name
+func (s StructuredDiff) Matches(file string) bool {
+	whole, _ := path.Match(s.Pattern, file)
+	base, _ := path.Match(s.Pattern, path.Base(file))
+	return whole || base
+}
+
+// ParseStructuredDiffs parses the pattern:mode pairs of the
+// structured-diffs option, separated by spaces or commas
+func ParseStructuredDiffs(value string) ([]StructuredDiff, error) {
+	var diffs []StructuredDiff
+	for _, pair := range ListValues(value) {
+		pattern, mode, ok := strings.Cut(pair, ":")
+		if !ok || pattern == "" {
+			return nil, fmt.Errorf("expected pattern:mode, got %s", pair)
+		}
+		if !slices.Contains(StructuredDiffModes, mode) {
+			return nil, fmt.Errorf("%s is not one of %s", mode, strings.Join(StructuredDiffModes, ", "))
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern: %s", pattern)
+		}
+		diffs = append(diffs, StructuredDiff{Pattern: pattern, Mode: mode})
+	}
+	return diffs, nil
+}
+
+// StructuredDiffMode returns the structured diff mode of the first pattern
+// a path matches, empty when it is diffed as it is
+func (c *Config) StructuredDiffMode(file string) string {
+	diffs, _ := ParseStructuredDiffs(c.Views.Diff.StructuredDiffs)
+	for _, diff := range diffs {
+		if diff.Matches(file) {
+			return diff.Mode
+		}
+	}
+	return ""
+}
+
+// structuredDiffOption creates an option for a field holding pattern:mode
+// pairs
+func structuredDiffOption(description string, field func(c *Config) *string) option {
+	return option{
+		description: description,
+		get: func(c *Config) string {
+			return *field(c)
+		},
+		set: func(c *Config, value string) error {
+			diffs, err := ParseStructuredDiffs(value)
+			if err != nil {
+				return err
+			}
+			var pairs []string
+			for _, diff := range diffs {
+				pairs = append(pairs, diff.Pattern+":"+diff.Mode)
+			}
+			*field(c) = strings.Join(pairs, " ")
+			return nil
+		},
+	}
+}
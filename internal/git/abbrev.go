@@ -0,0 +1,95 @@
+package git
+
+import (
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultAbbrev is the length hashes are abbreviated to when git can't be
+// asked, as outside of repositories, and the least core.abbrev=auto gives
+const DefaultAbbrev = 7
+
+// abbrevLength remembers the length hashes are abbreviated to, which only
+// changes with core.abbrev or as objects are added
+type abbrevLength struct {
+	mutex  sync.Mutex
+	length int // Zero until computed
+}
+
+// reset forgets the length, as done when another repository is opened
+func (a *abbrevLength) reset() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.length = 0
+}
+
+// ResetAbbrev forgets the length hashes are abbreviated to, for it to be
+// computed again by the next AbbrevHash, as done on each refresh
+func (c *GoGitClient) ResetAbbrev() {
+	c.abbrevs.reset()
+}
+
+// AbbrevHash abbreviates a full object hash to the length of core.abbrev,
+// or with core.abbrev=auto to a length growing with the number of objects
+// as git does. Anything but a full hash is returned as is.
+func (c *GoGitClient) AbbrevHash(hash string) string {
+	if !isFullHash(hash) {
+		return hash
+	}
+	return hash[:min(c.abbrevLength(), len(hash))]
+}
+
+// abbrevLength returns the length hashes are abbreviated to, computing it
+// once until reset
+func (c *GoGitClient) abbrevLength() int {
+	c.abbrevs.mutex.Lock()
+	defer c.abbrevs.mutex.Unlock()
+	if c.abbrevs.length == 0 {
+		c.abbrevs.length = DefaultAbbrev
+		if c.repo != nil {
+			c.abbrevs.length = c.configAbbrev()
+		}
+	}
+	return c.abbrevs.length
+}
+
+// configAbbrev returns the abbreviation length of core.abbrev, "no" giving
+// full hashes, estimating the number of objects when it is auto or unset
+func (c *GoGitClient) configAbbrev() int {
+	value := ""
+	if output, err := c.runGit(c.path, "config", "--get", "core.abbrev"); err == nil {
+		value = strings.ToLower(strings.TrimSpace(string(output)))
+	}
+
+	switch value {
+	case "", "auto":
+	case "no", "false", "off":
+		return 64
+	default:
+		if n, err := strconv.Atoi(value); err == nil {
+			return max(n, 4)
+		}
+	}
+
+	count := 0
+	if output, err := c.runGit(c.path, "count-objects", "-v"); err == nil {
+		if stats, err := parseCountObjects(string(output)); err == nil {
+			count = stats.LooseObjects + stats.PackedObjects
+		}
+	}
+	return autoAbbrev(count)
+}
+
+// autoAbbrev returns the length git abbreviates hashes to with
+// core.abbrev=auto for a repository of count objects: half the number of
+// bits of the count, rounded up, and never less than the default
+func autoAbbrev(count int) int {
+	return max((bits.Len(uint(count))+1)/2, DefaultAbbrev)
+}
+
+// isFullHash returns whether s is a complete SHA-1 or SHA-256 object hash
+func isFullHash(s string) bool {
+	return (len(s) == 40 || len(s) == 64) && strings.Trim(s, "0123456789abcdef") == ""
+}
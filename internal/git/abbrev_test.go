@@ -0,0 +1,53 @@
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbbrevHash(t *testing.T) {
+	dir := newTestRepo(t)
+	head := gitIn(t, dir, "rev-parse", "HEAD")
+
+	// Outside of a repository the default length is used
+	client := NewClient()
+	assert.Equal(t, head[:DefaultAbbrev], client.AbbrevHash(head))
+	assert.Equal(t, "main", client.AbbrevHash("main"))
+	assert.Equal(t, "abc123", client.AbbrevHash("abc123"))
+
+	require.NoError(t, client.Open(dir))
+	assert.Equal(t, gitIn(t, dir, "rev-parse", "--short", "HEAD"), client.AbbrevHash(head))
+
+	// core.abbrev is respected, and reopening forgets what was cached
+	gitIn(t, dir, "config", "core.abbrev", "12")
+	require.NoError(t, client.Open(dir))
+	short := client.AbbrevHash(head)
+	assert.Len(t, short, 12)
+	assert.True(t, strings.HasPrefix(head, short))
+}
+
+func TestAbbrevLength(t *testing.T) {
+	dir := newTestRepo(t)
+	head := gitIn(t, dir, "rev-parse", "HEAD")
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	// The length is kept until reset, as done on each refresh
+	assert.Len(t, client.AbbrevHash(head), DefaultAbbrev)
+	gitIn(t, dir, "config", "core.abbrev", "4")
+	assert.Len(t, client.AbbrevHash(head), DefaultAbbrev)
+	client.ResetAbbrev()
+	assert.Equal(t, head[:4], client.AbbrevHash(head))
+
+	gitIn(t, dir, "config", "core.abbrev", "no")
+	client.ResetAbbrev()
+	assert.Equal(t, head, client.AbbrevHash(head))
+
+	// The length grows with the repository as with git
+	assert.Equal(t, DefaultAbbrev, autoAbbrev(0))
+	assert.Equal(t, DefaultAbbrev, autoAbbrev(5000))
+	assert.Equal(t, 12, autoAbbrev(10_000_000))
+}
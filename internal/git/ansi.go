@@ -0,0 +1,27 @@
+package git
+
+import "strings"
+
+// StripANSI removes the escape sequences, such as the colors of git
+// commands run with --color=always, from a line of output
+func StripANSI(text string) string {
+	if !strings.Contains(text, "\x1b") {
+		return text
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\x1b' {
+			b.WriteByte(text[i])
+			continue
+		}
+		if i+1 < len(text) && text[i+1] == '[' {
+			// A control sequence ends with its final byte
+			i += 2
+			for i < len(text) && (text[i] < 0x40 || text[i] > 0x7e) {
+				i++
+			}
+		}
+	}
+	return b.String()
+}
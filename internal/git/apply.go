@@ -0,0 +1,39 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// StagePatch applies a patch to the index only, as git add -p does with
+// the hunks it stages. The patch is checked first, so a patch which does
+// not apply leaves the index alone and the error explains why. Hunk line
+// counts are recounted, which lets hand edited hunks keep a stale header.
+func (c *GoGitClient) StagePatch(patch string) error {
+	if c.repo == nil {
+		return fmt.Errorf("repository not opened")
+	}
+
+	apply := func(args ...string) error {
+		cmd := exec.Command("git", append([]string{"apply", "--cached", "--recount", "--whitespace=nowarn"}, args...)...)
+		cmd.Dir = c.path
+		cmd.Stdin = strings.NewReader(patch)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			// The first line of git's explanation is the one that matters
+			if msg, _, _ := strings.Cut(strings.TrimSpace(stderr.String()), "\n"); msg != "" {
+				return fmt.Errorf("patch does not apply: %s", strings.TrimPrefix(msg, "error: "))
+			}
+			return fmt.Errorf("patch does not apply: %w", err)
+		}
+		return nil
+	}
+
+	if err := apply("--check"); err != nil {
+		return err
+	}
+	return apply()
+}
@@ -0,0 +1,25 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStagePatch(t *testing.T) {
+	dir := newTestRepo(t)
+	writeTestFile(t, dir, "file.txt", "changed\n")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	// The header counts are off, as they often are after editing
+	patch := "diff --git a/file.txt b/file.txt\n--- a/file.txt\n+++ b/file.txt\n@@ -1 +1,3 @@\n-base\n+staged\n"
+	require.NoError(t, client.StagePatch(patch))
+	assert.Equal(t, "staged", gitIn(t, dir, "show", ":file.txt"))
+
+	err := client.StagePatch(patch)
+	assert.ErrorContains(t, err, "patch does not apply")
+	assert.Equal(t, "staged", gitIn(t, dir, "show", ":file.txt"))
+}
@@ -0,0 +1,404 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditLog records the operations changing a repository, one file per
+// repository, so what was done during a session can be looked up later.
+// Each line holds the time, the operation with its arguments and its
+// result, separated by tabs.
+type AuditLog struct {
+	dir   string
+	mutex sync.Mutex
+	now   func() time.Time
+}
+
+// NewAuditLog creates an audit log keeping its files in dir
+func NewAuditLog(dir string) *AuditLog {
+	return &AuditLog{dir: dir, now: time.Now}
+}
+
+// Path returns the file the operations on the repository at root are
+// recorded in
+func (l *AuditLog) Path(root string) string {
+	return filepath.Join(l.dir, url.PathEscape(root)+".log")
+}
+
+// Record appends an operation on the repository at root with its result,
+// which is detail on success and the error otherwise
+func (l *AuditLog) Record(root, operation string, args []string, detail string, opErr error) error {
+	if root == "" {
+		return nil
+	}
+
+	result := "ok"
+	if opErr != nil {
+		result = "failed: " + opErr.Error()
+	} else if detail != "" {
+		result = "ok: " + detail
+	}
+	fields := append([]string{operation}, args...)
+	for i, field := range fields {
+		fields[i] = quoteAuditField(field)
+	}
+	line := fmt.Sprintf("%s\t%s\t%s\n", l.now().Format(time.RFC3339), strings.Join(fields, " "), strings.ReplaceAll(result, "\n", " "))
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	file, err := os.OpenFile(l.Path(root), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(line); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// quoteAuditField quotes arguments which would not read back as one word
+func quoteAuditField(field string) string {
+	if field == "" || strings.ContainsAny(field, " \t\n\"'\\") {
+		return strconv.Quote(field)
+	}
+	return field
+}
+
+// auditClient records the operations of a client changing the repository
+type auditClient struct {
+	Client
+	log *AuditLog
+}
+
+// NewAuditClient wraps a client so the operations it makes changing the
+// repository are recorded in log. Fetches are left out, as they only
+// update remote-tracking branches and run unattended.
+func NewAuditClient(client Client, log *AuditLog) Client {
+	return &auditClient{Client: client, log: log}
+}
+
+// AuditLog returns the log the operations are recorded in
+func (c *auditClient) AuditLog() *AuditLog {
+	return c.log
+}
+
+// record records an operation on the open repository. Failing to record
+// it doesn't fail the operation, which already happened.
+func (c *auditClient) record(operation string, args []string, detail string, err error) {
+	_ = c.log.Record(c.GetRootPath(), operation, args, detail, err)
+}
+
+func (c *auditClient) Init(path, branch string) error {
+	err := c.Client.Init(path, branch)
+	args := []string{path}
+	if branch != "" {
+		args = append(args, branch)
+	}
+	// A failed init leaves the previous repository open
+	root := c.GetRootPath()
+	if err != nil {
+		root, _ = filepath.Abs(path)
+	}
+	_ = c.log.Record(root, "init", args, "", err)
+	return err
+}
+
+func (c *auditClient) DeleteRefs(plan []*RefDeletion) error {
+	args := make([]string, 0, len(plan))
+	for _, ref := range plan {
+		args = append(args, ref.Name)
+	}
+	err := c.Client.DeleteRefs(plan)
+	c.record("delete-refs", args, "", err)
+	return err
+}
+
+func (c *auditClient) CreateBranch(name, start string) error {
+	err := c.Client.CreateBranch(name, start)
+	c.record("branch", []string{name, start}, "", err)
+	return err
+}
+
+func (c *auditClient) RenameBranch(name, newName string) error {
+	err := c.Client.RenameBranch(name, newName)
+	c.record("branch", []string{"--move", name, newName}, "", err)
+	return err
+}
+
+func (c *auditClient) DeleteBranch(name string, force bool) error {
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	err := c.Client.DeleteBranch(name, force)
+	c.record("branch", []string{flag, name}, "", err)
+	return err
+}
+
+func (c *auditClient) Push(ctx context.Context, remote string, refspecs []string, progress func(line string)) error {
+	err := c.Client.Push(ctx, remote, refspecs, progress)
+	c.record("push", append([]string{remote}, refspecs...), "", err)
+	return err
+}
+
+func (c *auditClient) Pull(ctx context.Context, progress func(line string)) error {
+	err := c.Client.Pull(ctx, progress)
+	c.record("pull", []string{"--ff-only"}, "", err)
+	return err
+}
+
+func (c *auditClient) StageFile(path string) error {
+	err := c.Client.StageFile(path)
+	c.record("stage", []string{path}, "", err)
+	return err
+}
+
+func (c *auditClient) ApplyHunkToIndex(path string, hunk *DiffHunk) error {
+	err := c.Client.ApplyHunkToIndex(path, hunk)
+	c.record("apply-hunk", []string{"--cached", path, hunkRange(hunk)}, "", err)
+	return err
+}
+
+func (c *auditClient) StagePatch(patch string) error {
+	err := c.Client.StagePatch(patch)
+	c.record("stage-patch", patchHunks(patch), "", err)
+	return err
+}
+
+// patchHunks names the hunks of a patch by file, each file followed by the
+// ranges of its hunks
+func patchHunks(patch string) []string {
+	diff, err := ParseDiff(patch)
+	if err != nil {
+		return nil
+	}
+	var args []string
+	for _, file := range diff.Files {
+		args = append(args, file.Path())
+		for _, hunk := range file.Hunks {
+			args = append(args, hunkRange(hunk))
+		}
+	}
+	return args
+}
+
+func (c *auditClient) StageHunk(path string, hunk *DiffHunk) error {
+	err := c.Client.StageHunk(path, hunk)
+	c.record("stage-hunk", []string{path, hunkRange(hunk)}, "", err)
+	return err
+}
+
+func (c *auditClient) UnstageHunk(path string, hunk *DiffHunk) error {
+	err := c.Client.UnstageHunk(path, hunk)
+	c.record("unstage-hunk", []string{path, hunkRange(hunk)}, "", err)
+	return err
+}
+
+func (c *auditClient) StageHunkLines(path string, hunk *DiffHunk, first, last int) error {
+	err := c.Client.StageHunkLines(path, hunk, first, last)
+	c.record("stage-lines", []string{path, hunkRange(hunk), fmt.Sprintf("%d-%d", first, last)}, "", err)
+	return err
+}
+
+func (c *auditClient) UnstageHunkLines(path string, hunk *DiffHunk, first, last int) error {
+	err := c.Client.UnstageHunkLines(path, hunk, first, last)
+	c.record("unstage-lines", []string{path, hunkRange(hunk), fmt.Sprintf("%d-%d", first, last)}, "", err)
+	return err
+}
+
+func (c *auditClient) ApplyHunkToWorktree(path string, hunk *DiffHunk) error {
+	err := c.Client.ApplyHunkToWorktree(path, hunk)
+	c.record("apply-hunk", []string{path, hunkRange(hunk)}, "", err)
+	return err
+}
+
+func (c *auditClient) DiscardHunk(path string, hunk *DiffHunk) error {
+	err := c.Client.DiscardHunk(path, hunk)
+	c.record("discard-hunk", []string{path, hunkRange(hunk)}, "", err)
+	return err
+}
+
+// hunkRange names a hunk by the lines it changes, such as "-3,2 +3,4"
+func hunkRange(hunk *DiffHunk) string {
+	return fmt.Sprintf("-%d,%d +%d,%d", hunk.OldStart, hunk.OldLines, hunk.NewStart, hunk.NewLines)
+}
+
+func (c *auditClient) UnstageFile(path string) error {
+	err := c.Client.UnstageFile(path)
+	c.record("unstage", []string{path}, "", err)
+	return err
+}
+
+func (c *auditClient) StageAll() error {
+	err := c.Client.StageAll()
+	c.record("stage-all", nil, "", err)
+	return err
+}
+
+func (c *auditClient) UnstageAll() error {
+	err := c.Client.UnstageAll()
+	c.record("unstage-all", nil, "", err)
+	return err
+}
+
+func (c *auditClient) DiscardChanges(path string) error {
+	err := c.Client.DiscardChanges(path)
+	c.record("discard", []string{path}, "", err)
+	return err
+}
+
+func (c *auditClient) UndoDiscard() (string, error) {
+	path, err := c.Client.UndoDiscard()
+	c.record("undo-discard", nil, path, err)
+	return path, err
+}
+
+func (c *auditClient) Commit(message string, opts *CommitOptions) error {
+	err := c.Client.Commit(message, opts)
+	summary, _, _ := strings.Cut(message, "\n")
+	args := []string{summary}
+	if opts != nil && opts.Amend {
+		args = append([]string{"--amend"}, args...)
+	}
+	c.record("commit", args, "", err)
+	return err
+}
+
+func (c *auditClient) Backport(rev, branch string) (*BackportResult, error) {
+	result, err := c.Client.Backport(rev, branch)
+	detail := ""
+	if result != nil && len(result.Conflicts) > 0 {
+		detail = "conflicts in " + strings.Join(result.Conflicts, ", ")
+	} else if result != nil {
+		detail = result.Commit
+	}
+	c.record("backport", []string{rev, branch}, detail, err)
+	return result, err
+}
+
+func (c *auditClient) CherryPick(hash string) (*PickResult, error) {
+	result, err := c.Client.CherryPick(hash)
+	c.record("cherry-pick", []string{hash}, pickDetail(result), err)
+	return result, err
+}
+
+func (c *auditClient) Revert(hash string) (*PickResult, error) {
+	result, err := c.Client.Revert(hash)
+	c.record("revert", []string{hash}, pickDetail(result), err)
+	return result, err
+}
+
+// pickDetail describes the outcome of a cherry-pick or revert
+func pickDetail(result *PickResult) string {
+	if result != nil && len(result.Conflicts) > 0 {
+		return "conflicts in " + strings.Join(result.Conflicts, ", ")
+	} else if result != nil {
+		return result.Commit
+	}
+	return ""
+}
+
+func (c *auditClient) CommitFixup(hash string) (string, error) {
+	commit, err := c.Client.CommitFixup(hash)
+	c.record("fixup", []string{hash}, commit, err)
+	return commit, err
+}
+
+func (c *auditClient) Autosquash() (string, error) {
+	base, err := c.Client.Autosquash()
+	detail := ""
+	if err == nil {
+		detail = "from " + base
+	}
+	c.record("autosquash", nil, detail, err)
+	return base, err
+}
+
+func (c *auditClient) Checkout(rev string) error {
+	err := c.Client.Checkout(rev)
+	c.record("checkout", []string{rev}, "", err)
+	return err
+}
+
+func (c *auditClient) CheckoutOrphan(name string) error {
+	err := c.Client.CheckoutOrphan(name)
+	c.record("checkout", []string{"--orphan", name}, "", err)
+	return err
+}
+
+func (c *auditClient) CheckoutOverwriting(rev string, paths []string) error {
+	err := c.Client.CheckoutOverwriting(rev, paths)
+	c.record("checkout", append([]string{rev, "--overwriting"}, paths...), "", err)
+	return err
+}
+
+func (c *auditClient) Rebase(upstream string) error {
+	err := c.Client.Rebase(upstream)
+	c.record("rebase", []string{upstream}, "", err)
+	return err
+}
+
+func (c *auditClient) Reset(rev, mode string) error {
+	err := c.Client.Reset(rev, mode)
+	c.record("reset", []string{"--" + mode, rev}, "", err)
+	return err
+}
+
+func (c *auditClient) Stash(message string) (string, error) {
+	hash, err := c.Client.Stash(message)
+	c.record("stash", []string{message}, hash, err)
+	return hash, err
+}
+
+func (c *auditClient) PushStash(message string, untracked bool) (string, error) {
+	hash, err := c.Client.PushStash(message, untracked)
+	args := []string{message}
+	if untracked {
+		args = append([]string{"--include-untracked"}, args...)
+	}
+	c.record("stash", args, hash, err)
+	return hash, err
+}
+
+func (c *auditClient) StashUntracked(message string, paths []string) (string, error) {
+	hash, err := c.Client.StashUntracked(message, paths)
+	c.record("stash", append([]string{"--include-untracked", message, "--"}, paths...), hash, err)
+	return hash, err
+}
+
+func (c *auditClient) ApplyStash(hash string) error {
+	err := c.Client.ApplyStash(hash)
+	c.record("stash-apply", []string{hash}, "", err)
+	return err
+}
+
+func (c *auditClient) PopStash(hash string) error {
+	err := c.Client.PopStash(hash)
+	c.record("stash-pop", []string{hash}, "", err)
+	return err
+}
+
+func (c *auditClient) DropStash(hash string) error {
+	err := c.Client.DropStash(hash)
+	c.record("stash-drop", []string{hash}, "", err)
+	return err
+}
+
+func (c *auditClient) Maintain(ctx context.Context, task string, progress func(line string)) error {
+	err := c.Client.Maintain(ctx, task, progress)
+	c.record("maintain", []string{task}, "", err)
+	return err
+}
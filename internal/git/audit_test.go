@@ -0,0 +1,52 @@
+package git
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditClient(t *testing.T) {
+	dir := newTestRepo(t)
+	log := NewAuditLog(t.TempDir())
+	log.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	client := NewAuditClient(NewClient(), log)
+	require.NoError(t, client.Open(dir))
+	root := client.GetRootPath()
+
+	writeTestFile(t, dir, "new file.txt", "new\n")
+	require.NoError(t, client.StageFile("new file.txt"))
+	require.NoError(t, client.Commit("add a file\n\nwith a body", &CommitOptions{}))
+	_, err := client.CommitFixup("HEAD")
+	assert.Error(t, err)
+	writeTestFile(t, dir, "file.txt", "base\nmore\n")
+	require.NoError(t, client.StagePatch(gitIn(t, dir, "diff")+"\n"))
+
+	// Reading the repository isn't recorded
+	_, err = client.GetStatus()
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(log.Path(root))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"2024-05-01T12:00:00Z\tstage \"new file.txt\"\tok",
+		"2024-05-01T12:00:00Z\tcommit \"add a file\"\tok",
+		"2024-05-01T12:00:00Z\tfixup HEAD\tfailed: no staged changes to commit as a fixup",
+		"2024-05-01T12:00:00Z\tstage-patch file.txt \"-1,1 +1,2\"\tok",
+	}, strings.Split(strings.TrimSpace(string(data)), "\n"))
+
+	// Each repository has its own log
+	other := t.TempDir()
+	assert.NoError(t, client.Init(other, "trunk"))
+	assert.NotEqual(t, log.Path(root), log.Path(client.GetRootPath()))
+	data, err = os.ReadFile(log.Path(client.GetRootPath()))
+	require.NoError(t, err)
+	assert.Equal(t, "2024-05-01T12:00:00Z\tinit "+other+" trunk\tok\n", string(data))
+
+	assert.Same(t, log, client.(*auditClient).AuditLog())
+}
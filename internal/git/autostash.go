@@ -0,0 +1,55 @@
+package git
+
+import (
+	"strings"
+)
+
+// LocalChanges lists the tracked files with changes, staged or not, which
+// a checkout, rebase or hard reset could refuse to run over or lose
+func (c *GoGitClient) LocalChanges() ([]string, error) {
+	output, err := c.runGit(c.path, "status", "--porcelain", "-z", "--untracked-files=no")
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	entries := strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		paths = append(paths, entry[3:])
+		// Renames and copies are followed by the path they came from
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++
+		}
+	}
+	return paths, nil
+}
+
+// Stash stashes the local changes to tracked files with the given message
+// and returns the hash of the stash
+func (c *GoGitClient) Stash(message string) (string, error) {
+	return c.PushStash(message, false)
+}
+
+// PopStash applies the stash with the given hash to the worktree and drops
+// it. A stash which doesn't apply cleanly is kept, conflicts being left in
+// the worktree.
+func (c *GoGitClient) PopStash(hash string) error {
+	if err := validateRevRange(hash); err != nil {
+		return err
+	}
+	if _, err := c.runGit(c.path, "stash", "apply", "--quiet", hash); err != nil {
+		return err
+	}
+
+	// Other stashes may have been pushed on top of it meanwhile
+	ref, found, err := c.stashRef(hash)
+	if err != nil || !found {
+		return err
+	}
+	_, err = c.runGit(c.path, "stash", "drop", "--quiet", ref)
+	return err
+}
@@ -0,0 +1,150 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalChanges(t *testing.T) {
+	dir := newTestRepo(t)
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	changes, err := client.LocalChanges()
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// Untracked files are left out, renames are listed by their new path
+	writeTestFile(t, dir, "untracked.txt", "new\n")
+	gitIn(t, dir, "mv", "file.txt", "renamed.txt")
+	writeTestFile(t, dir, "renamed.txt", "changed\n")
+	changes, err = client.LocalChanges()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"renamed.txt"}, changes)
+}
+
+func TestStashAndPop(t *testing.T) {
+	dir := newTestRepo(t)
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	_, err := client.Stash("nothing")
+	assert.ErrorContains(t, err, "no local changes")
+
+	writeTestFile(t, dir, "file.txt", "mine\n")
+	hash, err := client.Stash("autostash")
+	require.NoError(t, err)
+	assert.Equal(t, "base", gitIn(t, dir, "show", "HEAD:file.txt"))
+	data, err := os.ReadFile(filepath.Join(dir, "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "base\n", string(data))
+
+	// The stash is found under another one pushed meanwhile
+	writeTestFile(t, dir, "file.txt", "other\n")
+	gitIn(t, dir, "stash", "push", "--quiet", "--message", "other")
+	require.NoError(t, client.PopStash(hash))
+	data, err = os.ReadFile(filepath.Join(dir, "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "mine\n", string(data))
+	assert.Contains(t, gitIn(t, dir, "stash", "list"), "other")
+	assert.NotContains(t, gitIn(t, dir, "stash", "list"), "autostash")
+
+	// A stash which doesn't apply is kept
+	hash, err = client.Stash("conflicting")
+	require.NoError(t, err)
+	writeTestFile(t, dir, "file.txt", "theirs\n")
+	gitIn(t, dir, "commit", "--quiet", "-am", "theirs")
+	assert.Error(t, client.PopStash(hash))
+	assert.Contains(t, gitIn(t, dir, "stash", "list"), "conflicting")
+}
+
+func TestCheckoutRebaseReset(t *testing.T) {
+	dir := newTestRepo(t)
+	base := gitIn(t, dir, "rev-parse", "HEAD")
+	gitIn(t, dir, "checkout", "--quiet", "-b", "topic")
+	writeTestFile(t, dir, "topic.txt", "topic\n")
+	gitIn(t, dir, "add", "topic.txt")
+	gitIn(t, dir, "commit", "--quiet", "-m", "topic")
+	gitIn(t, dir, "checkout", "--quiet", "main")
+	writeTestFile(t, dir, "file.txt", "main\n")
+	gitIn(t, dir, "commit", "--quiet", "-am", "main")
+	main := gitIn(t, dir, "rev-parse", "HEAD")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	require.NoError(t, client.Checkout("topic"))
+	assert.Equal(t, "topic", gitIn(t, dir, "branch", "--show-current"))
+	assert.Error(t, client.Checkout("--orphan"))
+
+	// An orphan branch starts with an empty index and the files untracked
+	require.NoError(t, client.CheckoutOrphan("pages"))
+	assert.Equal(t, "pages", client.UnbornBranch())
+	assert.Empty(t, gitIn(t, dir, "ls-files"))
+	_, err := os.Stat(filepath.Join(dir, "topic.txt"))
+	require.NoError(t, err)
+	assert.Error(t, client.CheckoutOrphan("topic"))
+	assert.Error(t, client.CheckoutOrphan("-f"))
+	gitIn(t, dir, "checkout", "--quiet", "--force", "topic")
+
+	require.NoError(t, client.Rebase("main"))
+	assert.Equal(t, main, gitIn(t, dir, "rev-parse", "HEAD^"))
+
+	// A rebase refused over local changes leaves nothing to clean up
+	writeTestFile(t, dir, "file.txt", "dirty\n")
+	assert.Error(t, client.Rebase(base))
+	assert.Equal(t, main, gitIn(t, dir, "rev-parse", "HEAD^"))
+
+	require.NoError(t, client.Reset(main, "mixed"))
+	assert.Equal(t, main, gitIn(t, dir, "rev-parse", "HEAD"))
+	assert.Equal(t, "M file.txt", gitIn(t, dir, "status", "--porcelain", "--untracked-files=no"))
+	require.NoError(t, client.Reset("HEAD", "hard"))
+	assert.Empty(t, gitIn(t, dir, "status", "--porcelain", "--untracked-files=no"))
+	assert.ErrorContains(t, client.Reset("HEAD", "keep"), "invalid reset mode")
+}
+
+func TestUntrackedConflicts(t *testing.T) {
+	dir := newTestRepo(t)
+	gitIn(t, dir, "checkout", "--quiet", "-b", "topic")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "docs"), 0755))
+	writeTestFile(t, dir, "docs/guide.md", "guide\n")
+	writeTestFile(t, dir, "notes.txt", "notes\n")
+	writeTestFile(t, dir, "build", "file\n")
+	gitIn(t, dir, "add", ".")
+	gitIn(t, dir, "commit", "--quiet", "-m", "topic")
+	gitIn(t, dir, "checkout", "--quiet", "main")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+	conflicts, err := client.UntrackedConflicts("topic")
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+
+	// Files of the branch, a file in the way of its directory and a
+	// directory in the way of its file conflict, other and ignored files
+	// don't
+	writeTestFile(t, dir, "notes.txt", "mine\n")
+	writeTestFile(t, dir, "docs", "mine\n")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "build"), 0755))
+	writeTestFile(t, dir, "build/out.o", "mine\n")
+	writeTestFile(t, dir, "other.txt", "mine\n")
+	writeTestFile(t, dir, ".git/info/exclude", "ignored.txt\n")
+	writeTestFile(t, dir, "ignored.txt", "mine\n")
+	conflicts, err = client.UntrackedConflicts("topic")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"notes.txt", "docs", "build/out.o"}, conflicts)
+	assert.Error(t, client.Checkout("topic"))
+
+	// They are stashed alone, or overwritten once kept in the trash
+	hash, err := client.StashUntracked("in the way", []string{"notes.txt"})
+	require.NoError(t, err)
+	assert.Contains(t, gitIn(t, dir, "show", "--format=", "--name-only", hash+"^3"), "notes.txt")
+	assert.FileExists(t, filepath.Join(dir, "other.txt"))
+	require.NoError(t, client.CheckoutOverwriting("topic", []string{"docs", "build/out.o"}))
+	assert.Equal(t, "topic", gitIn(t, dir, "branch", "--show-current"))
+	assert.Contains(t, gitIn(t, dir, "log", "--format=%s", "refs/tig/trash"), "discard build/out.o")
+}
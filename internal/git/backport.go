@@ -0,0 +1,123 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// BackportResult describes the outcome of applying a commit or stash to
+// another branch
+type BackportResult struct {
+	Branch    string
+	Commit    string   // Abbreviated hash of the new commit on success
+	Conflicts []string // Conflicting paths if the change did not apply
+}
+
+// Backport applies a commit, or a stash given as stash@{n}, on top of the
+// given branch. The change is made in a temporary worktree so the current
+// checkout is left untouched. Conflicts abort the operation and are
+// reported in the result rather than as an error.
+func (c *GoGitClient) Backport(rev, branch string) (*BackportResult, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+	if err := validateRevRange(rev); err != nil {
+		return nil, err
+	}
+	if err := validateRevRange(branch); err != nil {
+		return nil, err
+	}
+	if _, err := c.runGit(c.path, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err != nil {
+		return nil, fmt.Errorf("no such branch: %s", branch)
+	}
+
+	// Resolve the revision here, as names like HEAD mean something else
+	// inside the temporary worktree
+	stash := strings.HasPrefix(rev, "stash@{")
+	if !stash {
+		hash, err := c.runGit(c.path, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+		if err != nil {
+			return nil, fmt.Errorf("no such commit: %s", rev)
+		}
+		rev = strings.TrimSpace(string(hash))
+	}
+
+	dir, err := os.MkdirTemp("", "tig-backport-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := c.runGit(c.path, "worktree", "add", "--quiet", dir, branch); err != nil {
+		return nil, err
+	}
+	defer c.runGit(c.path, "worktree", "remove", "--force", dir)
+
+	result := &BackportResult{Branch: branch}
+	if stash {
+		err = c.applyStash(dir, rev)
+	} else {
+		_, err = c.runGit(dir, "cherry-pick", "-x", rev)
+	}
+	if err != nil {
+		conflicts, _ := c.runGit(dir, "diff", "--name-only", "--diff-filter=U")
+		result.Conflicts = strings.Fields(string(conflicts))
+		if len(result.Conflicts) == 0 {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	head, err := c.runGit(dir, "rev-parse", "--short", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	result.Commit = strings.TrimSpace(string(head))
+	return result, nil
+}
+
+// applyStash applies a stash in a worktree and commits the result
+func (c *GoGitClient) applyStash(dir, stash string) error {
+	if _, err := c.runGit(dir, "stash", "apply", stash); err != nil {
+		return err
+	}
+	if _, err := c.runGit(dir, "add", "--all"); err != nil {
+		return err
+	}
+	subject, err := c.runGit(dir, "log", "-1", "--format=%s", stash)
+	if err != nil {
+		return err
+	}
+	_, err = c.runGit(dir, "commit", "--quiet", "-m", "Apply "+stash+": "+strings.TrimSpace(string(subject)))
+	return err
+}
+
+// runGit runs a git command in the given directory, turning failures into
+// errors carrying git's own explanation
+func (c *GoGitClient) runGit(dir string, args ...string) ([]byte, error) {
+	return c.runGitEnv(dir, nil, args...)
+}
+
+// runGitEnv is runGit with variables added to the environment of the
+// command
+func (c *GoGitClient) runGitEnv(dir string, env []string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return output, fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return output, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return output, nil
+}
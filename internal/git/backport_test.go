@@ -0,0 +1,107 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRepo creates a repository with a single commit on main
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	gitIn(t, dir, "init", "--quiet", "--initial-branch=main")
+	gitIn(t, dir, "config", "user.name", "Test User")
+	gitIn(t, dir, "config", "user.email", "test@example.com")
+	writeTestFile(t, dir, "file.txt", "base\n")
+	gitIn(t, dir, "add", "file.txt")
+	gitIn(t, dir, "commit", "--quiet", "-m", "base")
+	return dir
+}
+
+// gitIn runs a git command in a test repository and returns its output
+func gitIn(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %v: %s", args, output)
+	return strings.TrimSpace(string(output))
+}
+
+// writeTestFile writes a file in a test repository
+func writeTestFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
+
+func TestBackport(t *testing.T) {
+	dir := newTestRepo(t)
+	gitIn(t, dir, "branch", "release")
+
+	writeTestFile(t, dir, "fix.txt", "fix\n")
+	gitIn(t, dir, "add", "fix.txt")
+	gitIn(t, dir, "commit", "--quiet", "-m", "fix")
+	fix := gitIn(t, dir, "rev-parse", "HEAD")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	result, err := client.Backport(fix, "release")
+	assert.NoError(t, err)
+	assert.Empty(t, result.Conflicts)
+	assert.NotEmpty(t, result.Commit)
+	assert.Equal(t, "fix", gitIn(t, dir, "log", "-1", "--format=%s", "release"))
+
+	// The checkout is left alone and the temporary worktree is gone
+	assert.Equal(t, "main", gitIn(t, dir, "branch", "--show-current"))
+	assert.Len(t, strings.Split(gitIn(t, dir, "worktree", "list"), "\n"), 1)
+
+	_, err = client.Backport(fix, "no-such-branch")
+	assert.Error(t, err)
+}
+
+func TestBackportConflict(t *testing.T) {
+	dir := newTestRepo(t)
+	gitIn(t, dir, "checkout", "--quiet", "-b", "release")
+	writeTestFile(t, dir, "file.txt", "release\n")
+	gitIn(t, dir, "commit", "--quiet", "-am", "release change")
+	gitIn(t, dir, "checkout", "--quiet", "main")
+
+	writeTestFile(t, dir, "file.txt", "main\n")
+	gitIn(t, dir, "commit", "--quiet", "-am", "main change")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	result, err := client.Backport("HEAD", "release")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"file.txt"}, result.Conflicts)
+	assert.Equal(t, "release change", gitIn(t, dir, "log", "-1", "--format=%s", "release"))
+}
+
+func TestBackportStash(t *testing.T) {
+	dir := newTestRepo(t)
+	gitIn(t, dir, "branch", "release")
+
+	writeTestFile(t, dir, "file.txt", "stashed\n")
+	gitIn(t, dir, "stash", "push", "--quiet", "-m", "wip")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	result, err := client.Backport("stash@{0}", "release")
+	assert.NoError(t, err)
+	assert.Empty(t, result.Conflicts)
+	assert.Equal(t, "stashed", gitIn(t, dir, "show", "release:file.txt"))
+	assert.Equal(t, "base", gitIn(t, dir, "show", "main:file.txt"))
+}
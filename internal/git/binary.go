@@ -0,0 +1,165 @@
+package git
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"sync"
+)
+
+// BinaryDescriber summarises one version of a binary file, such as the
+// dimensions of an image. It reports false for content it does not know.
+type BinaryDescriber func(data []byte) (string, bool)
+
+// binaryDescriber is a registered describer
+type binaryDescriber struct {
+	name     string
+	describe BinaryDescriber
+}
+
+var (
+	binaryDescribersMu sync.RWMutex
+	binaryDescribers   []binaryDescriber
+)
+
+func init() {
+	RegisterBinaryDescriber("Image", describeImage)
+	RegisterBinaryDescriber("Font", describeFont)
+}
+
+// RegisterBinaryDescriber adds a describer to the registry. Describers are
+// tried in registration order and each one recognising the old or new
+// content contributes a line to the description of a binary change.
+func RegisterBinaryDescriber(name string, describe BinaryDescriber) {
+	binaryDescribersMu.Lock()
+	defer binaryDescribersMu.Unlock()
+
+	binaryDescribers = append(binaryDescribers, binaryDescriber{name: name, describe: describe})
+}
+
+// BinaryDescribeLimit is how much of the start of a binary file the
+// describers are given, enough for the headers of images and fonts
+const BinaryDescribeLimit = 64 * 1024
+
+// DescribeBinary describes how a binary file changed. A nil side means the
+// file did not exist in that version.
+func DescribeBinary(oldData, newData []byte) []string {
+	return DescribeBinaryHeads(oldData, int64(len(oldData)), newData, int64(len(newData)))
+}
+
+// DescribeBinaryHeads describes how a binary file changed from the start
+// of each version, such as read by BlobHead, and their full sizes. A nil
+// start means the file did not exist in that version.
+func DescribeBinaryHeads(oldHead []byte, oldSize int64, newHead []byte, newSize int64) []string {
+	binaryDescribersMu.RLock()
+	defer binaryDescribersMu.RUnlock()
+
+	lines := []string{fmt.Sprintf("Size: %s -> %s", formatBlobSize(oldHead, oldSize), formatBlobSize(newHead, newSize))}
+	for _, d := range binaryDescribers {
+		oldDesc, oldOK := describeBlob(d.describe, oldHead)
+		newDesc, newOK := describeBlob(d.describe, newHead)
+		if oldOK || newOK {
+			lines = append(lines, fmt.Sprintf("%s: %s -> %s", d.name, oldDesc, newDesc))
+		}
+	}
+	return lines
+}
+
+// describeBlob runs a describer, using "-" for missing or unknown content
+func describeBlob(describe BinaryDescriber, data []byte) (string, bool) {
+	if data == nil {
+		return "-", false
+	}
+	if desc, ok := describe(data); ok {
+		return desc, true
+	}
+	return "-", false
+}
+
+// formatBlobSize formats the size of a blob in human readable units, "-"
+// when there is no blob
+func formatBlobSize(data []byte, size int64) string {
+	if data == nil {
+		return "-"
+	}
+
+	return FormatSize(size)
+}
+
+// FormatSize formats a size in bytes in human readable units
+func FormatSize(size int64) string {
+	switch {
+	case size < 1024:
+		return fmt.Sprintf("%d B", size)
+	case size < 1024*1024:
+		return fmt.Sprintf("%.1f KiB", float64(size)/1024)
+	case size < 1024*1024*1024:
+		return fmt.Sprintf("%.1f MiB", float64(size)/(1024*1024))
+	default:
+		return fmt.Sprintf("%.1f GiB", float64(size)/(1024*1024*1024))
+	}
+}
+
+// describeImage reports the format and dimensions of an image
+func describeImage(data []byte) (string, bool) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", false
+	}
+	return fmt.Sprintf("%s %dx%d", format, config.Width, config.Height), true
+}
+
+// describeFont reports the format of a font and, for TrueType and OpenType
+// fonts, the number of glyphs
+func describeFont(data []byte) (string, bool) {
+	if len(data) < 4 {
+		return "", false
+	}
+
+	var format string
+	switch string(data[:4]) {
+	case "\x00\x01\x00\x00", "true":
+		format = "TrueType"
+	case "OTTO":
+		format = "OpenType"
+	case "wOFF":
+		return "WOFF", true
+	case "wOF2":
+		return "WOFF2", true
+	default:
+		return "", false
+	}
+
+	if glyphs, ok := sfntGlyphCount(data); ok {
+		return fmt.Sprintf("%s, %d glyphs", format, glyphs), true
+	}
+	return format, true
+}
+
+// sfntGlyphCount reads the glyph count from the maxp table of a font
+func sfntGlyphCount(data []byte) (int, bool) {
+	if len(data) < 12 {
+		return 0, false
+	}
+
+	numTables := int(binary.BigEndian.Uint16(data[4:6]))
+	for i := 0; i < numTables; i++ {
+		record := 12 + i*16
+		if record+16 > len(data) {
+			return 0, false
+		}
+		if string(data[record:record+4]) != "maxp" {
+			continue
+		}
+		offset := int(binary.BigEndian.Uint32(data[record+8 : record+12]))
+		if offset+6 > len(data) {
+			return 0, false
+		}
+		return int(binary.BigEndian.Uint16(data[offset+4 : offset+6])), true
+	}
+	return 0, false
+}
@@ -0,0 +1,83 @@
+package git
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeBinaryImage(t *testing.T) {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 16, 8)))
+	assert.NoError(t, err)
+
+	lines := DescribeBinary(nil, buf.Bytes())
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "Size: - -> "+formatBlobSize(buf.Bytes(), int64(buf.Len())), lines[0])
+	assert.Equal(t, "Image: - -> png 16x8", lines[1])
+}
+
+func TestDescribeBinaryFont(t *testing.T) {
+	// Minimal sfnt with a single maxp table declaring 42 glyphs
+	font := []byte{
+		0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x10, 0x00, 0x00, 0x00, 0x00,
+		'm', 'a', 'x', 'p', 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x1c, 0x00, 0x00, 0x00, 0x06,
+		0x00, 0x00, 0x50, 0x00, 0x00, 0x2a,
+	}
+
+	lines := DescribeBinary(font, []byte("wOF2rest"))
+	assert.Equal(t, []string{
+		"Size: 34 B -> 8 B",
+		"Font: TrueType, 42 glyphs -> WOFF2",
+	}, lines)
+}
+
+func TestDescribeBinaryUnknown(t *testing.T) {
+	lines := DescribeBinary(make([]byte, 2048), nil)
+	assert.Equal(t, []string{"Size: 2.0 KiB -> -"}, lines)
+}
+
+func TestRegisterBinaryDescriber(t *testing.T) {
+	saved := binaryDescribers
+	defer func() { binaryDescribers = saved }()
+
+	RegisterBinaryDescriber("Magic", func(data []byte) (string, bool) {
+		if bytes.HasPrefix(data, []byte("MAGIC")) {
+			return "magic", true
+		}
+		return "", false
+	})
+
+	lines := DescribeBinary([]byte("other"), []byte("MAGIC!"))
+	assert.Equal(t, "Magic: - -> magic", lines[len(lines)-1])
+}
+
+func TestBlobHead(t *testing.T) {
+	dir := newTestRepo(t)
+	writeTestFile(t, dir, "big.bin", "\x00"+strings.Repeat("x", 99999))
+	gitIn(t, dir, "add", ".")
+	gitIn(t, dir, "commit", "--quiet", "-m", "big")
+	hash := gitIn(t, dir, "rev-parse", "HEAD:big.bin")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+	head, size, err := client.BlobHead(hash, 16)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100000), size)
+	assert.Equal(t, "\x00"+strings.Repeat("x", 15), string(head))
+
+	// Blobs shorter than the limit are read whole
+	head, size, err = client.BlobHead(gitIn(t, dir, "rev-parse", "HEAD:file.txt"), 16)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(head)), size)
+
+	_, _, err = client.BlobHead("HEAD", 16)
+	assert.Error(t, err)
+	_, _, err = client.BlobHead(strings.Repeat("0", 40), 16)
+	assert.Error(t, err)
+}
@@ -0,0 +1,117 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// IgnoreRevsFile is the file listing the commits blame skips by
+// convention, such as those reformatting the code
+const IgnoreRevsFile = ".git-blame-ignore-revs"
+
+// BlameOptions represents options for blame operations
+type BlameOptions struct {
+	IgnoreRevs []string // Revisions whose changes are skipped
+}
+
+// NotCommitted is the commit blame gives the lines of the work tree which
+// are not committed yet
+const NotCommitted = "0000000000000000000000000000000000000000"
+
+// BlameLine is the commit which last changed a line of a file
+type BlameLine struct {
+	Commit  string
+	Ignored bool // Attributed past the changes of an ignored revision
+}
+
+// Blame returns the commit which last changed each line of a file as of
+// the given revision, or of the work tree when empty, indexed by line
+// number minus one. Lines not committed yet are blamed on NotCommitted.
+// The changes of the revisions the options, the .git-blame-ignore-revs
+// file of the repository and the blame.ignoreRevsFile setting name are
+// skipped, and the lines which would have been blamed on them are marked.
+func (c *GoGitClient) Blame(rev, path string, opts *BlameOptions) ([]BlameLine, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+	var revArgs []string
+	if rev != "" {
+		if err := validateRevRange(rev); err != nil {
+			return nil, err
+		}
+		revArgs = []string{rev}
+	}
+
+	var ignoreArgs []string
+	if _, err := os.Stat(filepath.Join(c.path, IgnoreRevsFile)); err == nil {
+		ignoreArgs = append(ignoreArgs, "--ignore-revs-file", IgnoreRevsFile)
+	}
+	if opts != nil {
+		for _, ignored := range opts.IgnoreRevs {
+			if err := validateRevRange(ignored); err != nil {
+				return nil, err
+			}
+			ignoreArgs = append(ignoreArgs, "--ignore-rev", ignored)
+		}
+	}
+
+	output, err := c.runGit(c.path, append(append(append([]string{"blame", "--porcelain"}, ignoreArgs...), revArgs...), "--", path)...)
+	if err != nil {
+		return nil, err
+	}
+	commits := parseBlame(output)
+
+	lines := make([]BlameLine, len(commits))
+	for i, commit := range commits {
+		lines[i].Commit = commit
+	}
+	if len(ignoreArgs) == 0 && c.ConfigValue("blame.ignoreRevsFile") == "" {
+		return lines, nil
+	}
+
+	// Lines blamed on other commits than without ignoring any went past
+	// an ignored one. An empty file name clears the configured list.
+	output, err = c.runGit(c.path, append(append([]string{"blame", "--porcelain", "--ignore-revs-file="}, revArgs...), "--", path)...)
+	if err != nil {
+		return nil, err
+	}
+	for i, commit := range parseBlame(output) {
+		if i < len(lines) && lines[i].Commit != commit {
+			lines[i].Ignored = true
+		}
+	}
+	return lines, nil
+}
+
+// parseBlame reads the commits of lines from git blame --porcelain. Every
+// line of the file is announced by a header holding its commit, its line
+// number in that commit and its line number in the file.
+func parseBlame(output []byte) []string {
+	var commits []string
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "\t") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 || !isFullHash(fields[0]) {
+			continue
+		}
+		lineno, err := strconv.Atoi(fields[2])
+		if err != nil || lineno < 1 {
+			continue
+		}
+		for len(commits) < lineno {
+			commits = append(commits, "")
+		}
+		commits[lineno-1] = fields[0]
+	}
+	return commits
+}
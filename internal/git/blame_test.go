@@ -0,0 +1,73 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlame(t *testing.T) {
+	dir := newTestRepo(t)
+	base := gitIn(t, dir, "rev-parse", "HEAD")
+	writeTestFile(t, dir, "file.txt", "base\nmore\n")
+	gitIn(t, dir, "commit", "--quiet", "-am", "more")
+	more := gitIn(t, dir, "rev-parse", "HEAD")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	commits, err := client.Blame("HEAD", "file.txt", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []BlameLine{{Commit: base}, {Commit: more}}, commits)
+
+	commits, err = client.Blame("HEAD^", "file.txt", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []BlameLine{{Commit: base}}, commits)
+
+	// Without a revision the work tree is blamed
+	writeTestFile(t, dir, "file.txt", "base\nchanged\n")
+	commits, err = client.Blame("", "file.txt", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []BlameLine{{Commit: base}, {Commit: NotCommitted}}, commits)
+
+	_, err = client.Blame("HEAD", "missing.txt", nil)
+	assert.Error(t, err)
+	_, err = client.Blame("--reverse", "file.txt", nil)
+	assert.Error(t, err)
+}
+
+func TestBlameIgnoreRevs(t *testing.T) {
+	dir := newTestRepo(t)
+	writeTestFile(t, dir, "file.txt", "one\ntwo\n")
+	gitIn(t, dir, "commit", "--quiet", "-am", "two lines")
+	first := gitIn(t, dir, "rev-parse", "HEAD")
+	writeTestFile(t, dir, "file.txt", "one \ntwo\n")
+	gitIn(t, dir, "commit", "--quiet", "-am", "reformat")
+	reformat := gitIn(t, dir, "rev-parse", "HEAD")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	commits, err := client.Blame("HEAD", "file.txt", &BlameOptions{IgnoreRevs: []string{"HEAD"}})
+	require.NoError(t, err)
+	assert.Equal(t, []BlameLine{{Commit: first, Ignored: true}, {Commit: first}}, commits)
+
+	// The conventional file of the repository is honored too
+	writeTestFile(t, dir, IgnoreRevsFile, "# Reformatting\n"+reformat+"\n")
+	commits, err = client.Blame("HEAD", "file.txt", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []BlameLine{{Commit: first, Ignored: true}, {Commit: first}}, commits)
+
+	// So is the one git is configured with, which git reads itself
+	require.NoError(t, os.Rename(filepath.Join(dir, IgnoreRevsFile), filepath.Join(dir, "ignored")))
+	gitIn(t, dir, "config", "blame.ignoreRevsFile", "ignored")
+	commits, err = client.Blame("HEAD", "file.txt", nil)
+	require.NoError(t, err)
+	assert.True(t, commits[0].Ignored)
+
+	_, err = client.Blame("HEAD", "file.txt", &BlameOptions{IgnoreRevs: []string{"--reverse"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,70 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// validateBranchName rejects names git would not take as a branch, or
+// would parse as an option
+func (c *GoGitClient) validateBranchName(name string) error {
+	if name == "" || strings.HasPrefix(name, "-") {
+		return fmt.Errorf("invalid branch name: %q", name)
+	}
+	if _, err := c.runGit(c.path, "check-ref-format", "--branch", name); err != nil {
+		return fmt.Errorf("invalid branch name: %q", name)
+	}
+	return nil
+}
+
+// CreateBranch creates a branch at start, HEAD when empty, without
+// checking it out. An existing branch is left alone.
+func (c *GoGitClient) CreateBranch(name, start string) error {
+	if c.repo == nil {
+		return fmt.Errorf("repository not opened")
+	}
+	if err := c.validateBranchName(name); err != nil {
+		return err
+	}
+	if start == "" {
+		start = "HEAD"
+	}
+	if err := validateRevRange(start); err != nil {
+		return err
+	}
+	_, err := c.runGit(c.path, "branch", "--no-track", name, start)
+	return err
+}
+
+// RenameBranch renames a branch along with its reflog and configuration,
+// refusing to overwrite another branch
+func (c *GoGitClient) RenameBranch(name, newName string) error {
+	if c.repo == nil {
+		return fmt.Errorf("repository not opened")
+	}
+	if err := c.validateBranchName(name); err != nil {
+		return err
+	}
+	if err := c.validateBranchName(newName); err != nil {
+		return err
+	}
+	_, err := c.runGit(c.path, "branch", "--move", name, newName)
+	return err
+}
+
+// DeleteBranch deletes a branch. Unless forced, git refuses to delete one
+// whose commits aren't merged into its upstream, or HEAD without one.
+func (c *GoGitClient) DeleteBranch(name string, force bool) error {
+	if c.repo == nil {
+		return fmt.Errorf("repository not opened")
+	}
+	if err := c.validateBranchName(name); err != nil {
+		return err
+	}
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	_, err := c.runGit(c.path, "branch", flag, name)
+	return err
+}
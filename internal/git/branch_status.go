@@ -0,0 +1,85 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BranchStatus summarizes the state of the current branch
+type BranchStatus struct {
+	Branch   string // Empty when HEAD is detached
+	Upstream string
+	Ahead    int
+	Behind   int
+	Dirty    bool // Tracked files have staged or unstaged changes
+}
+
+// GetBranchStatus returns the current branch, how far it is ahead and
+// behind its upstream, and whether the worktree has changes
+func (c *GoGitClient) GetBranchStatus() (*BranchStatus, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+
+	output, err := c.runGit(c.path, "status", "--porcelain=v2", "--branch", "--untracked-files=no")
+	if err != nil {
+		return nil, err
+	}
+	return parseBranchStatus(string(output))
+}
+
+// BranchStatus summarizes the state of the branch of a full status, for
+// callers having one at hand
+func (s *Status) BranchStatus() *BranchStatus {
+	status := &BranchStatus{
+		Branch:   s.Branch,
+		Upstream: s.Upstream,
+		Ahead:    s.Ahead,
+		Behind:   s.Behind,
+		Dirty:    len(s.Staged) > 0 || len(s.Modified) > 0 || len(s.Conflict) > 0,
+	}
+	if status.Branch == "HEAD" {
+		status.Branch = ""
+	}
+	return status
+}
+
+// parseBranchStatus parses the output of git status --porcelain=v2 --branch
+func parseBranchStatus(output string) (*BranchStatus, error) {
+	status := &BranchStatus{}
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "# ") {
+			status.Dirty = true
+			continue
+		}
+
+		key, value, _ := strings.Cut(strings.TrimPrefix(line, "# "), " ")
+		switch key {
+		case "branch.head":
+			if value != "(detached)" {
+				status.Branch = value
+			}
+		case "branch.upstream":
+			status.Upstream = value
+		case "branch.ab":
+			var ahead, behind string
+			if _, err := fmt.Sscan(value, &ahead, &behind); err != nil {
+				return nil, fmt.Errorf("invalid branch.ab line: %q", line)
+			}
+			a, err := strconv.Atoi(strings.TrimPrefix(ahead, "+"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid branch.ab line: %q", line)
+			}
+			b, err := strconv.Atoi(strings.TrimPrefix(behind, "-"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid branch.ab line: %q", line)
+			}
+			status.Ahead, status.Behind = a, b
+		}
+	}
+	return status, nil
+}
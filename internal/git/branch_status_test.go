@@ -0,0 +1,103 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBranchStatus(t *testing.T) {
+	status, err := parseBranchStatus(`# branch.oid 1234567890abcdef
+# branch.head main
+# branch.upstream origin/main
+# branch.ab +2 -3
+1 .M N... 100644 100644 100644 abc def file.txt
+`)
+	require.NoError(t, err)
+	assert.Equal(t, &BranchStatus{Branch: "main", Upstream: "origin/main", Ahead: 2, Behind: 3, Dirty: true}, status)
+
+	status, err = parseBranchStatus("# branch.oid 1234567890abcdef\n# branch.head (detached)\n")
+	require.NoError(t, err)
+	assert.Equal(t, &BranchStatus{}, status)
+
+	_, err = parseBranchStatus("# branch.ab +x -1\n")
+	assert.Error(t, err)
+}
+
+func TestGetBranchStatus(t *testing.T) {
+	dir := newTestRepo(t)
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	status, err := client.GetBranchStatus()
+	require.NoError(t, err)
+	assert.Equal(t, &BranchStatus{Branch: "main"}, status)
+
+	// Untracked files don't make the worktree dirty, modified ones do
+	writeTestFile(t, dir, "untracked.txt", "new\n")
+	status, err = client.GetBranchStatus()
+	require.NoError(t, err)
+	assert.False(t, status.Dirty)
+
+	writeTestFile(t, dir, "file.txt", "changed\n")
+	status, err = client.GetBranchStatus()
+	require.NoError(t, err)
+	assert.True(t, status.Dirty)
+}
+
+func TestStatusUpstream(t *testing.T) {
+	dir := newTestRepo(t)
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	status, err := client.GetStatus()
+	require.NoError(t, err)
+	assert.Empty(t, status.Upstream)
+
+	// The branch and its upstream each get a commit of their own
+	gitIn(t, dir, "remote", "add", "origin", dir)
+	gitIn(t, dir, "update-ref", "refs/remotes/origin/main", "HEAD")
+	gitIn(t, dir, "branch", "--quiet", "--set-upstream-to=origin/main")
+	gitIn(t, dir, "commit", "--quiet", "--allow-empty", "-m", "local")
+	gitIn(t, dir, "checkout", "--quiet", "--detach", "origin/main")
+	gitIn(t, dir, "commit", "--quiet", "--allow-empty", "-m", "remote one")
+	gitIn(t, dir, "commit", "--quiet", "--allow-empty", "-m", "remote two")
+	gitIn(t, dir, "update-ref", "refs/remotes/origin/main", "HEAD")
+	gitIn(t, dir, "checkout", "--quiet", "main")
+
+	status, err = client.GetStatus()
+	require.NoError(t, err)
+	assert.Equal(t, "origin/main", status.Upstream)
+	assert.Equal(t, 1, status.Ahead)
+	assert.Equal(t, 2, status.Behind)
+
+	index, err := client.RefIndex()
+	require.NoError(t, err)
+	ahead, behind := index.Tracking("refs/heads/main")
+	assert.Equal(t, 1, ahead)
+	assert.Equal(t, 2, behind)
+
+	// A branch whose upstream is gone has none
+	gitIn(t, dir, "update-ref", "-d", "refs/remotes/origin/main")
+	status, err = client.GetStatus()
+	require.NoError(t, err)
+	assert.Empty(t, status.Upstream)
+	assert.Zero(t, status.Ahead)
+	index, err = client.RefIndex()
+	require.NoError(t, err)
+	ahead, behind = index.Tracking("refs/heads/main")
+	assert.Zero(t, ahead+behind)
+}
+
+func TestStatusBranchStatus(t *testing.T) {
+	status := &Status{Branch: "main", Upstream: "origin/main", Ahead: 1, Behind: 2, Untracked: []FileStatus{{Path: "new.txt"}}}
+	assert.Equal(t, &BranchStatus{Branch: "main", Upstream: "origin/main", Ahead: 1, Behind: 2}, status.BranchStatus())
+
+	// Untracked files leave the branch clean, other changes don't
+	status.Modified = []FileStatus{{Path: "file.txt", Y: "M"}}
+	assert.True(t, status.BranchStatus().Dirty)
+
+	// A detached HEAD is on no branch
+	assert.Empty(t, (&Status{Branch: "HEAD"}).BranchStatus().Branch)
+}
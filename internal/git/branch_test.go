@@ -0,0 +1,44 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBranchLifecycle(t *testing.T) {
+	dir := newTestRepo(t)
+	base := gitIn(t, dir, "rev-parse", "HEAD")
+	writeTestFile(t, dir, "file.txt", "changed\n")
+	gitIn(t, dir, "commit", "--quiet", "-am", "second")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	// Branches start at HEAD or the given revision, without a checkout
+	require.NoError(t, client.CreateBranch("topic", ""))
+	require.NoError(t, client.CreateBranch("old", base))
+	assert.Equal(t, gitIn(t, dir, "rev-parse", "HEAD"), gitIn(t, dir, "rev-parse", "topic"))
+	assert.Equal(t, base, gitIn(t, dir, "rev-parse", "old"))
+	assert.Equal(t, "main", gitIn(t, dir, "branch", "--show-current"))
+	assert.Error(t, client.CreateBranch("topic", ""))
+	assert.Error(t, client.CreateBranch("bad..name", ""))
+	assert.Error(t, client.CreateBranch("-d", ""))
+	assert.Error(t, client.CreateBranch("new", "--all"))
+
+	// Renaming keeps the configuration and refuses to overwrite
+	gitIn(t, dir, "config", "branch.topic.remote", "origin")
+	require.NoError(t, client.RenameBranch("topic", "feature"))
+	assert.Equal(t, "origin", gitIn(t, dir, "config", "branch.feature.remote"))
+	assert.Error(t, client.RenameBranch("feature", "old"))
+
+	// Merged branches are deleted, others only when forced
+	require.NoError(t, client.DeleteBranch("old", false))
+	gitIn(t, dir, "checkout", "--quiet", "feature")
+	gitIn(t, dir, "commit", "--quiet", "--allow-empty", "-m", "feature")
+	gitIn(t, dir, "checkout", "--quiet", "main")
+	assert.Error(t, client.DeleteBranch("feature", false))
+	require.NoError(t, client.DeleteBranch("feature", true))
+	assert.Equal(t, "main", gitIn(t, dir, "branch", "--format=%(refname:short)"))
+}
@@ -0,0 +1,131 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ResetModes are the modes Reset accepts, from keeping the most to the
+// least of the current state
+var ResetModes = []string{"soft", "mixed", "hard"}
+
+// Checkout checks out the given branch or revision
+func (c *GoGitClient) Checkout(rev string) error {
+	if err := validateRevRange(rev); err != nil {
+		return err
+	}
+	_, err := c.runGit(c.path, "checkout", "--quiet", rev, "--")
+	return err
+}
+
+// UntrackedConflicts returns the untracked files which checking out rev
+// would overwrite, which git refuses to do. Files in the way of a
+// directory of rev, or in a directory which is a file there, are listed
+// too. Ignored files are left out, git overwriting them.
+func (c *GoGitClient) UntrackedConflicts(rev string) ([]string, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+	if err := validateRevRange(rev); err != nil {
+		return nil, err
+	}
+
+	output, err := c.runGit(c.path, "ls-files", "-z", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	if len(output) == 0 {
+		return nil, nil
+	}
+	untracked := strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00")
+
+	output, err = c.runGit(c.path, "ls-tree", "-r", "-z", "--name-only", "--full-tree", rev)
+	if err != nil {
+		return nil, err
+	}
+	// The files of rev, and their directories
+	files, dirs := make(map[string]bool), make(map[string]bool)
+	for _, file := range strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00") {
+		files[file] = true
+		for dir := path.Dir(file); dir != "." && !dirs[dir]; dir = path.Dir(dir) {
+			dirs[dir] = true
+		}
+	}
+
+	var conflicts []string
+	for _, file := range untracked {
+		conflict := files[file] || dirs[file]
+		for dir := path.Dir(file); dir != "." && !conflict; dir = path.Dir(dir) {
+			conflict = files[dir]
+		}
+		if conflict {
+			conflicts = append(conflicts, file)
+		}
+	}
+	return conflicts, nil
+}
+
+// CheckoutOverwriting checks out rev over the given untracked files, which
+// are kept in the trash before being removed
+func (c *GoGitClient) CheckoutOverwriting(rev string, paths []string) error {
+	if err := validateRevRange(rev); err != nil {
+		return err
+	}
+	for _, file := range paths {
+		if err := c.trashFile(file); err != nil {
+			return fmt.Errorf("failed to keep %s: %w", file, err)
+		}
+		if err := os.Remove(filepath.Join(c.path, file)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return c.Checkout(rev)
+}
+
+// CheckoutOrphan switches to a new branch with no history, whose first
+// commit starts empty: the index is cleared, the files of the worktree
+// being kept as untracked
+func (c *GoGitClient) CheckoutOrphan(name string) error {
+	if err := validateRevRange(name); err != nil {
+		return err
+	}
+	if _, err := c.runGit(c.path, "checkout", "--quiet", "--orphan", name); err != nil {
+		return err
+	}
+	_, err := c.runGit(c.path, "rm", "-r", "--cached", "--quiet", "--ignore-unmatch", "--", ".")
+	return err
+}
+
+// Rebase rebases the current branch onto upstream. A rebase which stops on
+// conflicts is aborted, leaving the branch as it was.
+func (c *GoGitClient) Rebase(upstream string) error {
+	if err := validateRevRange(upstream); err != nil {
+		return err
+	}
+	if _, err := c.runGit(c.path, "rebase", "--quiet", upstream); err != nil {
+		// Nothing is left to abort when the rebase refused to start
+		c.runGit(c.path, "rebase", "--abort")
+		return err
+	}
+	return nil
+}
+
+// Reset moves the current branch to the given revision, keeping the index
+// and worktree as the soft, mixed or hard mode says
+func (c *GoGitClient) Reset(rev, mode string) error {
+	if err := validateRevRange(rev); err != nil {
+		return err
+	}
+	valid := false
+	for _, m := range ResetModes {
+		valid = valid || m == mode
+	}
+	if !valid {
+		return fmt.Errorf("invalid reset mode: %s", mode)
+	}
+	_, err := c.runGit(c.path, "reset", "--quiet", "--"+mode, rev, "--")
+	return err
+}
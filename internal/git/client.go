@@ -1,15 +1,20 @@
 package git
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
@@ -20,38 +25,118 @@ type Client interface {
 	GetRepository() (*Repository, error)
 	GetWorktree() (*Worktree, error)
 	IsRepository() bool
+	Init(path, branch string) error
+	Clone(url, dir string) (string, error)
+	SetAskPass(program string, env []string)
 	
 	// Reference operations
 	GetHead() (*Ref, error)
+	UnbornBranch() string
 	GetBranches() ([]*Ref, error)
 	GetTags() ([]*Ref, error)
 	GetRemotes() ([]*Remote, error)
+	RefIndex() (*RefIndex, error)
+	RefsStamp() (uint64, error)
+	AheadBehind(branch, upstream string) (int, int, error)
+	PlanRefDeletion(refs []string) ([]*RefDeletion, error)
+	DeleteRefs(plan []*RefDeletion) error
+	CreateBranch(name, start string) error
+	RenameBranch(name, newName string) error
+	DeleteBranch(name string, force bool) error
+	PushRemote(branch string) (string, error)
+	Push(ctx context.Context, remote string, refspecs []string, progress func(line string)) error
+	Fetch(ctx context.Context, progress func(line string)) error
+	Pull(ctx context.Context, progress func(line string)) error
+	ConfigValue(key string) string
 	
 	// Commit operations
 	GetCommit(hash string) (*Commit, error)
 	GetCommits(opts *LogOptions) ([]*Commit, error)
+	StreamCommits(ctx context.Context, opts *LogOptions, out chan<- *Commit) error
 	GetLogCount() (int, error)
+	GetCommitDiff(hash string, opts *DiffOptions) (*Diff, error)
+	GetRangeDiff(revRange string, paths ...string) (*Diff, error)
+	GetDiffStat(revRange string) ([]*FileStat, error)
+	FormatPatches(base, tip, dir string) ([]string, error)
+	CompareSeries(ranges ...string) ([]*RangeDiffEntry, error)
+	MergeBase(a, b string) (string, error)
+	Topology(branch, base string) (*BranchTopology, error)
+	DefaultBranch() string
+	ResolveRevision(rev string) (string, error)
+	CompareBranches(ours, theirs string) (*BranchComparison, error)
+	Blame(rev, path string, opts *BlameOptions) ([]BlameLine, error)
+	Grep(pattern string, opts *GrepOptions) ([]*GrepLine, error)
+	FileLog(path string, maxCount int) ([]*Commit, error)
+	FilteredLog(filter LogFilter, maxCount int) ([]*Commit, error)
 	
 	// Status and file operations
 	GetStatus() (*Status, error)
+	GetBranchStatus() (*BranchStatus, error)
 	GetDiff(path string) (*Diff, error)
+	GetStagedDiff(path string) (*Diff, error)
+	GetUntrackedDiff(path string) (*Diff, error)
+	GetChangeStat(staged bool) ([]*FileStat, error)
+	EOLOnlyChanges(staged bool) ([]string, error)
 	GetFiles(path string) ([]*File, error)
+	GetTree(rev, path string) ([]*File, error)
+	GetBlob(hash string) ([]byte, error)
+	BlobHead(hash string, limit int) ([]byte, int64, error)
 	
 	// Staging operations
 	StageFile(path string) error
 	UnstageFile(path string) error
+	StagePatch(patch string) error
+	ApplyHunkToIndex(path string, hunk *DiffHunk) error
+	StageHunk(path string, hunk *DiffHunk) error
+	UnstageHunk(path string, hunk *DiffHunk) error
+	StageHunkLines(path string, hunk *DiffHunk, first, last int) error
+	UnstageHunkLines(path string, hunk *DiffHunk, first, last int) error
+	ApplyHunkToWorktree(path string, hunk *DiffHunk) error
+	DiscardHunk(path string, hunk *DiffHunk) error
 	StageAll() error
 	UnstageAll() error
 	DiscardChanges(path string) error
+	UndoDiscard() (string, error)
 	
 	// Commit operations
 	Commit(message string, opts *CommitOptions) error
+	Backport(rev, branch string) (*BackportResult, error)
+	CherryPick(hash string) (*PickResult, error)
+	Revert(hash string) (*PickResult, error)
+	CommitFixup(hash string) (string, error)
+	Autosquash() (string, error)
+	Checkout(rev string) error
+	CheckoutOrphan(name string) error
+	UntrackedConflicts(rev string) ([]string, error)
+	CheckoutOverwriting(rev string, paths []string) error
+	Rebase(upstream string) error
+	Reset(rev, mode string) error
 	
 	// Stash operations
 	GetStashes() ([]*Stash, error)
+	LocalChanges() ([]string, error)
+	Stash(message string) (string, error)
+	PushStash(message string, untracked bool) (string, error)
+	StashUntracked(message string, paths []string) (string, error)
+	ApplyStash(hash string) error
+	PopStash(hash string) error
+	DropStash(hash string) error
 	
+	// Review notes
+	ReviewNotes() ([]*ReviewNote, error)
+	SaveReviewNotes(notes []*ReviewNote) error
+	
+	// Maintenance operations
+	RepoStats(largest int) (*RepoStats, error)
+	Maintain(ctx context.Context, task string, progress func(line string)) error
+	LargeObjects(ctx context.Context, n int, progress func(line string)) ([]LargeObject, error)
+	Fsck(ctx context.Context, report func(line string)) (*FsckResult, error)
+
 	// Utility operations
 	GetRootPath() string
+	ObjectFormat() string
+	AbbrevHash(hash string) string
+	ResetAbbrev()
 	GetRelativePath(path string) string
 	ExecuteCommand(args ...string) ([]byte, error)
 }
@@ -97,6 +182,7 @@ type Commit struct {
 	Parents   []string
 	Tree      string
 	Stats     *DiffStats
+	Trailers  []Trailer
 }
 
 // Signature represents author/committer information
@@ -116,8 +202,10 @@ type DiffStats struct {
 // Status represents the working directory status
 type Status struct {
 	Branch    string
-	Ahead     int
-	Behind    int
+	NoCommits bool   // The branch has no commit yet
+	Upstream  string // Short name of the upstream of the branch, empty when it has none
+	Ahead     int    // Commits of the branch its upstream doesn't have
+	Behind    int    // Commits of the upstream the branch doesn't have
 	Staged    []FileStatus
 	Modified  []FileStatus
 	Untracked []FileStatus
@@ -138,6 +226,7 @@ type FileStatus struct {
 	IsConflict  bool
 	IsUntracked bool
 	IsAdded     bool
+	EOLOnly     bool // Only line endings changed
 }
 
 // File represents a file in the repository
@@ -165,6 +254,9 @@ type LogOptions struct {
 	Path     string
 	All      bool
 	Reverse  bool
+	Order    string    // Order of the commits streamed: topo, date or reverse, git's own otherwise
+	Follow   bool      // Follow Path through renames
+	Filter   LogFilter // Limits the commits streamed, in place of Branch and Path
 }
 
 // DiffOptions represents options for diff operations
@@ -173,6 +265,22 @@ type DiffOptions struct {
 	IgnoreSpace  bool
 	IgnoreCase   bool
 	Paths        []string
+	RenameThreshold int  // Similarity percentage of renames and copies, git's default if 0
+	FindCopies      bool // Look for copies of the files the commit changes
+}
+
+// renameArgs returns the git arguments detecting renames, and copies if
+// asked for
+func (o *DiffOptions) renameArgs() []string {
+	threshold := ""
+	if o != nil && o.RenameThreshold > 0 {
+		threshold = fmt.Sprintf("%d%%", min(o.RenameThreshold, 100))
+	}
+	args := []string{"-M" + threshold}
+	if o != nil && o.FindCopies {
+		args = append(args, "-C"+threshold)
+	}
+	return args
 }
 
 // CommitOptions represents options for commit operations
@@ -186,8 +294,14 @@ type CommitOptions struct {
 
 // GoGitClient implements the Client interface using go-git
 type GoGitClient struct {
-	path string
-	repo *git.Repository
+	path         string
+	repo         *git.Repository
+	objectFormat string
+	abbrevs      abbrevLength
+	binaries     binaryCache
+	refDirs      []string // Git directory and common directory, found on first use
+	askPass      string   // Program prompting for credentials, see SetAskPass
+	askPassEnv   []string
 }
 
 // NewClient creates a new Git client
@@ -202,13 +316,23 @@ func (c *GoGitClient) Open(path string) error {
 		return fmt.Errorf("failed to get absolute path: %w", err)
 	}
 
-	repo, err := git.PlainOpen(absPath)
+	repo, err := git.PlainOpenWithOptions(absPath, &git.PlainOpenOptions{DetectDotGit: true})
 	if err != nil {
 		return fmt.Errorf("failed to open repository: %w", err)
 	}
 
+	// Commands run from the top of the worktree, not the subdirectory
+	// tig was started in
+	if wt, err := repo.Worktree(); err == nil {
+		absPath = wt.Filesystem.Root()
+	}
+
 	c.path = absPath
 	c.repo = repo
+	c.objectFormat = detectObjectFormat(repo)
+	c.abbrevs.reset()
+	c.binaries.reset()
+	c.refDirs = nil
 	return nil
 }
 
@@ -232,10 +356,9 @@ func (c *GoGitClient) GetWorktree() (*Worktree, error) {
 	return &Worktree{wt: wt}, nil
 }
 
-// IsRepository checks if the current directory is a Git repository
+// IsRepository checks if a Git repository has been opened
 func (c *GoGitClient) IsRepository() bool {
-	_, err := git.PlainOpen("")
-	return err == nil
+	return c.repo != nil
 }
 
 // GetHead returns the HEAD reference
@@ -244,6 +367,10 @@ func (c *GoGitClient) GetHead() (*Ref, error) {
 		return nil, fmt.Errorf("repository not opened")
 	}
 
+	if c.cliBackend() {
+		return c.cliHead()
+	}
+
 	head, err := c.repo.Head()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get HEAD: %w", err)
@@ -262,6 +389,10 @@ func (c *GoGitClient) GetBranches() ([]*Ref, error) {
 		return nil, fmt.Errorf("repository not opened")
 	}
 
+	if c.cliBackend() {
+		return c.cliRefs("refs/heads/", RefTypeBranch)
+	}
+
 	branches, err := c.repo.Branches()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get branches: %w", err)
@@ -286,6 +417,10 @@ func (c *GoGitClient) GetTags() ([]*Ref, error) {
 		return nil, fmt.Errorf("repository not opened")
 	}
 
+	if c.cliBackend() {
+		return c.cliRefs("refs/tags/", RefTypeTag)
+	}
+
 	tags, err := c.repo.Tags()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tags: %w", err)
@@ -338,6 +473,14 @@ func (c *GoGitClient) GetCommit(hash string) (*Commit, error) {
 		return nil, fmt.Errorf("repository not opened")
 	}
 
+	if c.cliBackend() {
+		commits, err := c.cliCommits("--no-walk", "--max-count=1", hash, "--")
+		if err != nil || len(commits) == 0 {
+			return nil, fmt.Errorf("failed to get commit: %w", err)
+		}
+		return commits[0], nil
+	}
+
 	commitHash := plumbing.NewHash(hash)
 	commit, err := c.repo.CommitObject(commitHash)
 	if err != nil {
@@ -347,12 +490,17 @@ func (c *GoGitClient) GetCommit(hash string) (*Commit, error) {
 	return c.commitToModel(commit)
 }
 
-// GetCommits returns commits based on the given options
+// GetCommits returns commits based on the given options. HEAD has none
+// before the first commit.
 func (c *GoGitClient) GetCommits(opts *LogOptions) ([]*Commit, error) {
 	if c.repo == nil {
 		return nil, fmt.Errorf("repository not opened")
 	}
 
+	if c.cliBackend() {
+		return c.cliLog(opts)
+	}
+
 	var head plumbing.Hash
 	if opts.Branch != "" {
 		ref, err := c.repo.Reference(plumbing.ReferenceName(opts.Branch), true)
@@ -363,6 +511,9 @@ func (c *GoGitClient) GetCommits(opts *LogOptions) ([]*Commit, error) {
 	} else {
 		ref, err := c.repo.Head()
 		if err != nil {
+			if c.UnbornBranch() != "" {
+				return nil, nil
+			}
 			return nil, fmt.Errorf("failed to get HEAD: %w", err)
 		}
 		head = ref.Hash()
@@ -426,8 +577,22 @@ func (c *GoGitClient) GetLogCount() (int, error) {
 		return 0, fmt.Errorf("repository not opened")
 	}
 
+	if c.cliBackend() {
+		output, err := c.runGit(c.path, "rev-list", "--count", "HEAD")
+		if err != nil {
+			if c.UnbornBranch() != "" {
+				return 0, nil
+			}
+			return 0, fmt.Errorf("failed to get commits: %w", err)
+		}
+		return strconv.Atoi(strings.TrimSpace(string(output)))
+	}
+
 	ref, err := c.repo.Head()
 	if err != nil {
+		if c.UnbornBranch() != "" {
+			return 0, nil
+		}
 		return 0, fmt.Errorf("failed to get HEAD: %w", err)
 	}
 
@@ -454,6 +619,10 @@ func (c *GoGitClient) GetStatus() (*Status, error) {
 		return nil, fmt.Errorf("repository not opened")
 	}
 
+	if c.cliBackend() {
+		return c.cliStatus()
+	}
+
 	worktree, err := c.repo.Worktree()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get worktree: %w", err)
@@ -469,112 +638,303 @@ func (c *GoGitClient) GetStatus() (*Status, error) {
 	head, err := c.repo.Head()
 	if err == nil {
 		branch = head.Name().Short()
+	} else {
+		branch = c.UnbornBranch()
 	}
-
-	// Calculate ahead/behind
-	ahead, behind := 0, 0
-	if branch != "" && branch != "HEAD" {
-		remoteRef, err := c.repo.Reference(plumbing.ReferenceName(fmt.Sprintf("refs/remotes/origin/%s", branch)), true)
-		if err == nil {
-			// This is a simplified calculation - in a real implementation,
-			// you'd want to use more sophisticated logic
-			_ = remoteRef
-		}
-	}
+	noCommits := err != nil && branch != ""
 
 	result := &Status{
-		Branch: branch,
-		Ahead:  ahead,
-		Behind: behind,
+		Branch:    branch,
+		NoCommits: noCommits,
 	}
+	c.setUpstream(result)
 
 	for path, fileStatus := range status {
-		file := FileStatus{
-			Path: path,
-			X:    string(fileStatus.Staging),
-			Y:    string(fileStatus.Worktree),
-		}
-
-		// Set flags based on status
-		switch {
-		case fileStatus.Staging == 'A':
-			file.IsNew = true
-			result.Staged = append(result.Staged, file)
-		case fileStatus.Staging == 'D':
-			file.IsDeleted = true
-			result.Staged = append(result.Staged, file)
-		case fileStatus.Staging == 'M':
-			file.IsModified = true
-			result.Staged = append(result.Staged, file)
-		case fileStatus.Staging == 'R':
-			file.IsRenamed = true
-			result.Staged = append(result.Staged, file)
-		case fileStatus.Staging == 'C':
-			file.IsCopied = true
-			result.Staged = append(result.Staged, file)
-		}
+		result.add(path, byte(fileStatus.Staging), byte(fileStatus.Worktree))
+	}
 
-		switch {
-		case fileStatus.Worktree == '?':
-			result.Untracked = append(result.Untracked, file)
-		case fileStatus.Worktree == 'M':
-			file.IsModified = true
-			result.Modified = append(result.Modified, file)
-		case fileStatus.Worktree == 'D':
-			file.IsDeleted = true
-			result.Modified = append(result.Modified, file)
-		case fileStatus.Worktree == 'A':
-			file.IsNew = true
-			result.Modified = append(result.Modified, file)
-		}
+	return result, nil
+}
 
-		// Handle conflicts
-		if fileStatus.Staging == 'U' || fileStatus.Worktree == 'U' {
-			file.IsConflict = true
-			result.Conflict = append(result.Conflict, file)
-		}
+// setUpstream sets the upstream of the current branch and how far ahead
+// and behind it the branch is, leaving them unset when the branch has no
+// upstream, or none left
+func (c *GoGitClient) setUpstream(status *Status) {
+	if status.Branch == "" || status.Branch == "HEAD" || status.NoCommits {
+		return
+	}
+	output, err := c.runGit(c.path, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	if err != nil {
+		return
 	}
+	ahead, behind, err := c.AheadBehind("HEAD", "@{upstream}")
+	if err != nil {
+		return
+	}
+	status.Upstream = strings.TrimSpace(string(output))
+	status.Ahead, status.Behind = ahead, behind
+}
 
-	return result, nil
+// add files a path into the sections of the status its staged and
+// unstaged states put it in, given as the letters of git status --short
+func (s *Status) add(path string, x, y byte) {
+	file := FileStatus{
+		Path: path,
+		X:    string(x),
+		Y:    string(y),
+	}
+
+	// Set flags based on status
+	switch {
+	case x == 'A':
+		file.IsNew = true
+		s.Staged = append(s.Staged, file)
+	case x == 'D':
+		file.IsDeleted = true
+		s.Staged = append(s.Staged, file)
+	case x == 'M':
+		file.IsModified = true
+		s.Staged = append(s.Staged, file)
+	case x == 'R':
+		file.IsRenamed = true
+		s.Staged = append(s.Staged, file)
+	case x == 'C':
+		file.IsCopied = true
+		s.Staged = append(s.Staged, file)
+	}
+
+	switch {
+	case y == '?':
+		file.IsUntracked = true
+		s.Untracked = append(s.Untracked, file)
+	case y == 'M':
+		file.IsModified = true
+		s.Modified = append(s.Modified, file)
+	case y == 'D':
+		file.IsDeleted = true
+		s.Modified = append(s.Modified, file)
+	case y == 'A':
+		file.IsNew = true
+		s.Modified = append(s.Modified, file)
+	}
+
+	// Handle conflicts
+	if x == 'U' || y == 'U' {
+		file.IsConflict = true
+		s.Conflict = append(s.Conflict, file)
+	}
 }
 
-// GetDiff returns the diff for the given path
+// GetDiff returns the changes of the worktree not staged yet for the given
+// path
 func (c *GoGitClient) GetDiff(path string) (*Diff, error) {
 	if c.repo == nil {
 		return nil, fmt.Errorf("repository not opened")
 	}
 
-	worktree, err := c.repo.Worktree()
+	output, err := c.runGit(c.path, "diff", "--patch", "--textconv", "--color=always", "--", path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get worktree: %w", err)
+		return nil, fmt.Errorf("failed to get diff of %s: %w", path, err)
 	}
 
-	// This is a simplified implementation
-	// In a real implementation, you'd use git.Diff
-	_ = worktree
-	_ = path
+	return c.parseDiff(output)
+}
 
-	return &Diff{}, nil
+// GetCommitDiff returns the patch a commit introduces, with renames and
+// copies detected as the options say. Merge commits are diffed against
+// their first parent.
+func (c *GoGitClient) GetCommitDiff(hash string, opts *DiffOptions) (*Diff, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+
+	args := append([]string{"show", "--format=", "--patch", "--textconv", "--color=always", "--diff-merges=first-parent"}, opts.renameArgs()...)
+	output, err := c.ExecuteCommand(append(args, hash)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff of %s: %w", hash, err)
+	}
+
+	return c.parseDiff(output)
 }
 
-// GetFiles returns files in the given path
-func (c *GoGitClient) GetFiles(path string) ([]*File, error) {
+// GetRangeDiff returns the patch between the two ends of a revision range
+// such as "v1.0..v1.1", optionally limited to the given paths
+func (c *GoGitClient) GetRangeDiff(revRange string, paths ...string) (*Diff, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+	if err := validateRevRange(revRange); err != nil {
+		return nil, err
+	}
+
+	args := append([]string{"diff", "--patch", "--textconv", "--color=always", "-M", revRange, "--"}, paths...)
+	output, err := c.ExecuteCommand(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff of %s: %w", revRange, err)
+	}
+
+	return c.parseDiff(output)
+}
+
+// GetDiffStat returns the per-file line counts between the two ends of a
+// revision range
+func (c *GoGitClient) GetDiffStat(revRange string) ([]*FileStat, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+	if err := validateRevRange(revRange); err != nil {
+		return nil, err
+	}
+
+	output, err := c.ExecuteCommand("diff", "--numstat", "-z", "-M", revRange, "--")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diffstat of %s: %w", revRange, err)
+	}
+
+	return ParseNumstat(output)
+}
+
+// CompareSeries compares two versions of a patch series with git
+// range-diff. The ranges are given the way range-diff accepts them: as
+// "old-range new-range", "base old-tip new-tip" or "old-tip...new-tip".
+func (c *GoGitClient) CompareSeries(ranges ...string) ([]*RangeDiffEntry, error) {
 	if c.repo == nil {
 		return nil, fmt.Errorf("repository not opened")
 	}
+	if len(ranges) < 1 || len(ranges) > 3 {
+		return nil, fmt.Errorf("expected one to three revision ranges, got %d", len(ranges))
+	}
+	for _, revRange := range ranges {
+		if err := validateRevRange(revRange); err != nil {
+			return nil, err
+		}
+	}
+
+	args := append([]string{"range-diff", "--no-color"}, ranges...)
+	output, err := c.ExecuteCommand(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s: %w", strings.Join(ranges, " "), err)
+	}
+
+	return ParseRangeDiff(string(output))
+}
+
+// GetBlob returns the content of a blob, which may be given abbreviated
+func (c *GoGitClient) GetBlob(hash string) ([]byte, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+
+	output, err := c.ExecuteCommand("cat-file", "blob", hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob %s: %w", hash, err)
+	}
+	return output, nil
+}
+
+// BlobHead returns the start of a blob, up to limit bytes, and its full
+// size, without reading the rest of it
+func (c *GoGitClient) BlobHead(hash string, limit int) ([]byte, int64, error) {
+	if c.repo == nil {
+		return nil, 0, fmt.Errorf("repository not opened")
+	}
+	if strings.ContainsAny(hash, " \n") {
+		return nil, 0, fmt.Errorf("invalid object name: %s", hash)
+	}
+
+	cmd := exec.Command("git", "cat-file", "--batch")
+	cmd.Dir = c.path
+	cmd.Stdin = strings.NewReader(hash + "\n")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, 0, fmt.Errorf("git cat-file: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, 0, fmt.Errorf("git cat-file: %w", err)
+	}
+	// The rest of the blob is left unread
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	// <hash> <type> <size>, or <name> missing
+	reader := bufio.NewReader(stdout)
+	header, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, 0, fmt.Errorf("git cat-file: %w", err)
+	}
+	fields := strings.Fields(header)
+	if len(fields) != 3 || fields[1] != "blob" {
+		return nil, 0, fmt.Errorf("failed to read blob %s", hash)
+	}
+	size, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("git cat-file: bad header %q", header)
+	}
 
-	// This is a simplified implementation
-	return []*File{}, nil
+	head := make([]byte, min(size, int64(limit)))
+	if _, err := io.ReadFull(reader, head); err != nil {
+		return nil, 0, fmt.Errorf("git cat-file: %w", err)
+	}
+	return head, size, nil
 }
 
-// GetStashes returns all stashes
-func (c *GoGitClient) GetStashes() ([]*Stash, error) {
+// GetFiles returns the files and directories of the index directly in the
+// given path, relative to the repository root. Their paths are the names
+// within the path.
+func (c *GoGitClient) GetFiles(path string) ([]*File, error) {
 	if c.repo == nil {
 		return nil, fmt.Errorf("repository not opened")
 	}
 
-	// This is a simplified implementation
-	return []*Stash{}, nil
+	if c.cliBackend() {
+		return c.cliFiles(path)
+	}
+
+	index, err := c.repo.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	prefix := strings.Trim(path, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	seen := make(map[string]bool)
+	files := []*File{}
+	blobs := make(map[string][]*File)
+	for _, entry := range index.Entries {
+		if !strings.HasPrefix(entry.Name, prefix) {
+			continue
+		}
+		name := strings.TrimPrefix(entry.Name, prefix)
+		isDir := false
+		if i := strings.Index(name, "/"); i >= 0 {
+			name, isDir = name[:i], true
+		}
+		// Conflicted files have an entry for each stage
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		file := &File{Path: name, IsDir: isDir}
+		if isDir {
+			file.Mode = os.ModeDir | 0755
+		} else {
+			file.Size = int64(entry.Size)
+			if mode, err := entry.Mode.ToOSFileMode(); err == nil {
+				file.Mode = mode
+			}
+			if entry.Mode == filemode.Regular || entry.Mode == filemode.Executable {
+				blobs[entry.Hash.String()] = append(blobs[entry.Hash.String()], file)
+			}
+		}
+		files = append(files, file)
+	}
+	return files, c.markBinary(blobs)
 }
 
 // StageFile stages a single file
@@ -583,6 +943,13 @@ func (c *GoGitClient) StageFile(path string) error {
 		return fmt.Errorf("repository not opened")
 	}
 
+	if c.cliBackend() {
+		if _, err := c.runGit(c.path, "add", "--", path); err != nil {
+			return fmt.Errorf("failed to stage file %s: %w", path, err)
+		}
+		return nil
+	}
+
 	worktree, err := c.repo.Worktree()
 	if err != nil {
 		return fmt.Errorf("failed to get worktree: %w", err)
@@ -602,14 +969,8 @@ func (c *GoGitClient) UnstageFile(path string) error {
 		return fmt.Errorf("repository not opened")
 	}
 
-	worktree, err := c.repo.Worktree()
-	if err != nil {
-		return fmt.Errorf("failed to get worktree: %w", err)
-	}
-
 	// Use reset to unstage files
-	_, err = worktree.Add(path)
-	if err != nil {
+	if _, err := c.runGit(c.path, "reset", "--quiet", "--", path); err != nil {
 		return fmt.Errorf("failed to unstage file %s: %w", path, err)
 	}
 
@@ -622,6 +983,13 @@ func (c *GoGitClient) StageAll() error {
 		return fmt.Errorf("repository not opened")
 	}
 
+	if c.cliBackend() {
+		if _, err := c.runGit(c.path, "add", "--all"); err != nil {
+			return fmt.Errorf("failed to stage all files: %w", err)
+		}
+		return nil
+	}
+
 	worktree, err := c.repo.Worktree()
 	if err != nil {
 		return fmt.Errorf("failed to get worktree: %w", err)
@@ -641,16 +1009,21 @@ func (c *GoGitClient) UnstageAll() error {
 		return fmt.Errorf("repository not opened")
 	}
 
-	// Use git reset to unstage all files
-	_, err := c.ExecuteCommand("reset", "HEAD", ".")
+	// Use git reset to unstage all files, which leaves out HEAD for the
+	// index to be emptied before the first commit
+	_, err := c.ExecuteCommand("reset", "--quiet", "--", ".")
 	return err
 }
 
-// DiscardChanges discards changes to a file
+// DiscardChanges discards changes to a file, keeping its content in the
+// trash first for UndoDiscard to restore it
 func (c *GoGitClient) DiscardChanges(path string) error {
 	if c.repo == nil {
 		return fmt.Errorf("repository not opened")
 	}
+	if err := c.trashFile(path); err != nil {
+		return fmt.Errorf("failed to keep the discarded changes: %w", err)
+	}
 
 	// Use git checkout to discard changes
 	_, err := c.ExecuteCommand("checkout", "--", path)
@@ -663,6 +1036,10 @@ func (c *GoGitClient) Commit(message string, opts *CommitOptions) error {
 		return fmt.Errorf("repository not opened")
 	}
 
+	if c.cliBackend() {
+		return c.cliCommit(message, opts)
+	}
+
 	worktree, err := c.repo.Worktree()
 	if err != nil {
 		return fmt.Errorf("failed to get worktree: %w", err)
@@ -707,26 +1084,21 @@ func (c *GoGitClient) ExecuteCommand(args ...string) ([]byte, error) {
 
 // commitToModel converts a go-git commit to our Commit model
 func (c *GoGitClient) commitToModel(commit *object.Commit) (*Commit, error) {
-	// Split message into summary and body
 	message := commit.Message
-	summary := message
-	body := ""
-	
-	if idx := strings.Index(message, "\n"); idx >= 0 {
-		summary = message[:idx]
-		body = strings.TrimSpace(message[idx+1:])
-	}
+	summary, body := splitMessage(message)
+	_, trailers := SplitTrailers(message)
 
 	// Calculate diff stats (simplified)
 	stats := &DiffStats{}
 
 	commitModel := &Commit{
-		Hash:    commit.Hash.String(),
-		Message: message,
-		Summary: summary,
-		Body:    body,
-		Tree:    commit.TreeHash.String(),
-		Stats:   stats,
+		Hash:     commit.Hash.String(),
+		Message:  message,
+		Summary:  summary,
+		Body:     body,
+		Tree:     commit.TreeHash.String(),
+		Stats:    stats,
+		Trailers: trailers,
 		Author: Signature{
 			Name:  commit.Author.Name,
 			Email: commit.Author.Email,
@@ -747,6 +1119,19 @@ func (c *GoGitClient) commitToModel(commit *object.Commit) (*Commit, error) {
 	return commitModel, nil
 }
 
+// splitMessage splits a commit message into its summary and body. Like
+// git, the summary is the whole first paragraph, its lines joined by
+// spaces.
+func splitMessage(message string) (string, string) {
+	message = strings.TrimLeft(message, "\n")
+	paragraph, body, _ := strings.Cut(message, "\n\n")
+	lines := strings.Split(strings.TrimSpace(paragraph), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.Join(lines, " "), strings.TrimSpace(body)
+}
+
 // Diff represents a diff
 
 type Diff struct {
@@ -764,7 +1149,14 @@ type DiffFile struct {
 	IsRenamed bool
 	IsCopied  bool
 	IsBinary  bool
+	Similarity int // Similarity percentage of renames and copies
+	OldHash   string
+	NewHash   string
+	Headers   []string
 	Hunks     []*DiffHunk
+	Textconv  string // Command of the diff driver the contents were converted to text with
+	Structured string // Structured diff mode the hunks were made with, such as json
+	HiddenEOLChanges int // Lines whose line ending alone changed, hidden from the hunks
 }
 
 // DiffHunk represents a diff hunk
@@ -773,6 +1165,7 @@ type DiffHunk struct {
 	OldLines int
 	NewStart int
 	NewLines int
+	Header   string
 	Lines    []*DiffLine
 }
 
@@ -782,6 +1175,7 @@ type DiffLine struct {
 	Content string
 	OldLine int
 	NewLine int
+	Colored string // Line as colored by git, prefix included, empty if plain
 }
 
 // DiffLineType represents the type of diff line
@@ -791,10 +1185,14 @@ const (
 	DiffLineContext DiffLineType = iota
 	DiffLineAddition
 	DiffLineDeletion
+	DiffLineNoNewline
 )
 
 // Helper functions
 func convertCommit(commit *object.Commit) (*Commit, error) {
+	summary, body := splitMessage(commit.Message)
+	_, trailers := SplitTrailers(commit.Message)
+
 	stats := &DiffStats{
 		FilesChanged: 0, // Placeholder
 		Insertions:   0, // Placeholder
@@ -802,13 +1200,14 @@ func convertCommit(commit *object.Commit) (*Commit, error) {
 	}
 
 	commitModel := &Commit{
-		Hash:    commit.Hash.String(),
-		Message: commit.Message,
-		Summary: strings.Split(commit.Message, "\n")[0],
-		Body:    strings.Join(strings.Split(commit.Message, "\n")[1:], "\n"),
-		Parents: []string{},
-		Tree:    commit.TreeHash.String(),
-		Stats:   stats,
+		Hash:     commit.Hash.String(),
+		Message:  commit.Message,
+		Summary:  summary,
+		Body:     body,
+		Parents:  []string{},
+		Tree:     commit.TreeHash.String(),
+		Stats:    stats,
+		Trailers: trailers,
 		Author: Signature{
 			Name:  commit.Author.Name,
 			Email: commit.Author.Email,
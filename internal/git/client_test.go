@@ -80,6 +80,21 @@ func TestCommit(t *testing.T) {
 	assert.Equal(t, 5, commit.Stats.Deletions)
 }
 
+func TestSplitMessage(t *testing.T) {
+	summary, body := splitMessage("Subject\n\nBody\n")
+	assert.Equal(t, "Subject", summary)
+	assert.Equal(t, "Body", body)
+
+	// Like git, the subject is the whole first paragraph
+	summary, body = splitMessage("\nA subject wrapped \n  on two lines\n\nBody\n\nMore\n")
+	assert.Equal(t, "A subject wrapped on two lines", summary)
+	assert.Equal(t, "Body\n\nMore", body)
+
+	summary, body = splitMessage("Only a subject")
+	assert.Equal(t, "Only a subject", summary)
+	assert.Empty(t, body)
+}
+
 func TestStatus(t *testing.T) {
 	status := &Status{
 		Branch: "main",
@@ -0,0 +1,92 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BranchComparison holds the commits only found on either side of two
+// branches and the merge base they diverged from
+type BranchComparison struct {
+	Ours       string
+	Theirs     string
+	Base       *Commit
+	OnlyOurs   []*Commit
+	OnlyTheirs []*Commit
+}
+
+// compareLogFormat separates the fields of a commit by NUL characters, and
+// its trailers by SOH characters
+const compareLogFormat = "--format=%H%x00%P%x00%an%x00%ae%x00%at%x00%s%x00%(trailers:unfold,only,separator=%x01)"
+
+// CompareBranches lists the commits of ours and theirs which are not in the
+// other, along with their merge base
+func (c *GoGitClient) CompareBranches(ours, theirs string) (*BranchComparison, error) {
+	base, err := c.MergeBase(ours, theirs)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &BranchComparison{Ours: ours, Theirs: theirs}
+	if comparison.OnlyOurs, err = c.logCommits(base + ".." + ours); err != nil {
+		return nil, err
+	}
+	if comparison.OnlyTheirs, err = c.logCommits(base + ".." + theirs); err != nil {
+		return nil, err
+	}
+
+	commits, err := c.logCommits("--max-count=1", base)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("merge base %s not found", base)
+	}
+	comparison.Base = commits[0]
+	return comparison, nil
+}
+
+// logCommits runs git log with the given arguments and parses the commits
+func (c *GoGitClient) logCommits(args ...string) ([]*Commit, error) {
+	output, err := c.runGit(c.path, append([]string{"log", compareLogFormat}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	return parseLogCommits(string(output))
+}
+
+// parseLogCommits parses the lines written by git log using
+// compareLogFormat
+func parseLogCommits(output string) ([]*Commit, error) {
+	var commits []*Commit
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, "\x00", 7)
+		if len(fields) < 6 {
+			return nil, fmt.Errorf("invalid log line: %q", line)
+		}
+
+		seconds, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid commit time: %q", fields[4])
+		}
+		author := Signature{Name: fields[2], Email: fields[3], Time: time.Unix(seconds, 0)}
+		commit := &Commit{
+			Hash:      fields[0],
+			Parents:   strings.Fields(fields[1]),
+			Author:    author,
+			Committer: author,
+			Message:   fields[5],
+			Summary:   fields[5],
+		}
+		if len(fields) == 7 {
+			commit.Trailers = parseLogTrailers(fields[6])
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
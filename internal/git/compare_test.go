@@ -0,0 +1,57 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareBranches(t *testing.T) {
+	dir := newTestRepo(t)
+	base := gitIn(t, dir, "rev-parse", "HEAD")
+
+	gitIn(t, dir, "checkout", "--quiet", "-b", "topic")
+	for _, name := range []string{"one", "two"} {
+		writeTestFile(t, dir, name+".txt", name+"\n")
+		gitIn(t, dir, "add", name+".txt")
+		gitIn(t, dir, "commit", "--quiet", "-m", "Add "+name)
+	}
+
+	gitIn(t, dir, "checkout", "--quiet", "main")
+	writeTestFile(t, dir, "main.txt", "main\n")
+	gitIn(t, dir, "add", "main.txt")
+	gitIn(t, dir, "commit", "--quiet", "-m", "Add main")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	comparison, err := client.CompareBranches("HEAD", "topic")
+	require.NoError(t, err)
+	assert.Equal(t, base, comparison.Base.Hash)
+	assert.Equal(t, "base", comparison.Base.Summary)
+	if assert.Len(t, comparison.OnlyOurs, 1) {
+		assert.Equal(t, "Add main", comparison.OnlyOurs[0].Summary)
+		assert.Equal(t, []string{base}, comparison.OnlyOurs[0].Parents)
+		assert.Equal(t, "Test User", comparison.OnlyOurs[0].Author.Name)
+	}
+	if assert.Len(t, comparison.OnlyTheirs, 2) {
+		assert.Equal(t, "Add two", comparison.OnlyTheirs[0].Summary)
+		assert.Equal(t, "Add one", comparison.OnlyTheirs[1].Summary)
+	}
+
+	_, err = client.CompareBranches("HEAD", "no-such-branch")
+	assert.Error(t, err)
+}
+
+func TestParseLogCommits(t *testing.T) {
+	commits, err := parseLogCommits("abc\x00p1 p2\x00A U Thor\x00a@example.com\x0012\x00Merge\n")
+	require.NoError(t, err)
+	if assert.Len(t, commits, 1) {
+		assert.Equal(t, []string{"p1", "p2"}, commits[0].Parents)
+		assert.Equal(t, int64(12), commits[0].Author.Time.Unix())
+	}
+
+	_, err = parseLogCommits("abc\x00\x00name\n")
+	assert.Error(t, err)
+}
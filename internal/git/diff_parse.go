@@ -0,0 +1,278 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ParseDiff parses unified diff output as produced by git diff, git show
+// and git diff-tree -p into the structured Diff model. Text before the
+// first file header, such as a commit header, is ignored. Colored output
+// is parsed as if it was plain, keeping the colors of the hunk lines.
+func ParseDiff(text string) (*Diff, error) {
+	diff := &Diff{}
+
+	var file *DiffFile
+	var hunk *DiffHunk
+	oldLine, newLine := 0, 0
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	scanner.Split(scanRawLines)
+	for scanner.Scan() {
+		colored := scanner.Text()
+		line := StripANSI(colored)
+		if colored == line {
+			colored = ""
+		}
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			file = newDiffFile(strings.TrimPrefix(line, "diff --git "))
+			diff.Files = append(diff.Files, file)
+			hunk = nil
+			continue
+		case file == nil:
+			continue
+		}
+
+		if hunk != nil {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				hunk.Lines = append(hunk.Lines, &DiffLine{Type: DiffLineAddition, Content: line[1:], NewLine: newLine, Colored: colored})
+				newLine++
+				continue
+			case strings.HasPrefix(line, "-"):
+				hunk.Lines = append(hunk.Lines, &DiffLine{Type: DiffLineDeletion, Content: line[1:], OldLine: oldLine, Colored: colored})
+				oldLine++
+				continue
+			case strings.HasPrefix(line, " ") || line == "":
+				content := ""
+				if line != "" {
+					content = line[1:]
+				}
+				hunk.Lines = append(hunk.Lines, &DiffLine{Type: DiffLineContext, Content: content, OldLine: oldLine, NewLine: newLine, Colored: colored})
+				oldLine++
+				newLine++
+				continue
+			case strings.HasPrefix(line, `\`):
+				hunk.Lines = append(hunk.Lines, &DiffLine{Type: DiffLineNoNewline, Content: line, Colored: colored})
+				continue
+			}
+		}
+
+		switch {
+		case strings.HasPrefix(line, "@@ "):
+			parsed, err := parseHunkHeader(line)
+			if err != nil {
+				return nil, err
+			}
+			hunk = parsed
+			file.Hunks = append(file.Hunks, hunk)
+			oldLine, newLine = hunk.OldStart, hunk.NewStart
+		case strings.HasPrefix(line, "--- "):
+			if path := parseDiffPath(strings.TrimPrefix(line, "--- "), "a/"); path != "" {
+				file.OldPath = path
+			}
+		case strings.HasPrefix(line, "+++ "):
+			if path := parseDiffPath(strings.TrimPrefix(line, "+++ "), "b/"); path != "" {
+				file.NewPath = path
+			}
+		case strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch":
+			file.IsBinary = true
+		default:
+			parseExtendedHeader(file, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read diff: %w", err)
+	}
+
+	return diff, nil
+}
+
+// newDiffFile creates a file from the paths of a "diff --git" header
+func newDiffFile(paths string) *DiffFile {
+	file := &DiffFile{}
+
+	// Both paths are identical unless the file was renamed or copied, in
+	// which case the extended headers provide them unambiguously
+	if strings.HasPrefix(paths, `"`) {
+		if oldPath, rest, ok := cutQuoted(paths); ok {
+			file.OldPath = strings.TrimPrefix(oldPath, "a/")
+			file.NewPath = parseDiffPath(strings.TrimSpace(rest), "b/")
+		}
+		return file
+	}
+
+	if n := (len(paths) - 5) / 2; n > 0 && len(paths) == 2*n+5 &&
+		paths[:2] == "a/" && paths[2+n:5+n] == " b/" && paths[2:2+n] == paths[5+n:] {
+		file.OldPath = paths[2 : 2+n]
+		file.NewPath = file.OldPath
+		return file
+	}
+
+	if idx := strings.LastIndex(paths, " b/"); idx >= 0 {
+		file.OldPath = strings.TrimPrefix(paths[:idx], "a/")
+		file.NewPath = paths[idx+3:]
+	}
+	return file
+}
+
+// parseExtendedHeader applies a git extended header line to the file
+func parseExtendedHeader(file *DiffFile, line string) {
+	switch {
+	case strings.HasPrefix(line, "new file mode "):
+		file.IsNew = true
+		file.NewMode = parseFileMode(strings.TrimPrefix(line, "new file mode "))
+	case strings.HasPrefix(line, "deleted file mode "):
+		file.IsDeleted = true
+		file.OldMode = parseFileMode(strings.TrimPrefix(line, "deleted file mode "))
+	case strings.HasPrefix(line, "old mode "):
+		file.OldMode = parseFileMode(strings.TrimPrefix(line, "old mode "))
+	case strings.HasPrefix(line, "new mode "):
+		file.NewMode = parseFileMode(strings.TrimPrefix(line, "new mode "))
+	case strings.HasPrefix(line, "rename from "):
+		file.IsRenamed = true
+		file.OldPath = unquotePath(strings.TrimPrefix(line, "rename from "))
+	case strings.HasPrefix(line, "rename to "):
+		file.IsRenamed = true
+		file.NewPath = unquotePath(strings.TrimPrefix(line, "rename to "))
+	case strings.HasPrefix(line, "similarity index "):
+		file.Similarity, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(line, "similarity index "), "%"))
+	case strings.HasPrefix(line, "copy from "):
+		file.IsCopied = true
+		file.OldPath = unquotePath(strings.TrimPrefix(line, "copy from "))
+	case strings.HasPrefix(line, "copy to "):
+		file.IsCopied = true
+		file.NewPath = unquotePath(strings.TrimPrefix(line, "copy to "))
+	case strings.HasPrefix(line, "index "):
+		fields := strings.Fields(strings.TrimPrefix(line, "index "))
+		if len(fields) > 0 {
+			if hashes := strings.SplitN(fields[0], "..", 2); len(hashes) == 2 {
+				file.OldHash, file.NewHash = hashes[0], hashes[1]
+			}
+		}
+		if len(fields) > 1 {
+			mode := parseFileMode(fields[1])
+			file.OldMode, file.NewMode = mode, mode
+		}
+	default:
+		return
+	}
+	file.Headers = append(file.Headers, line)
+}
+
+// parseHunkHeader parses a "@@ -a,b +c,d @@ section" hunk header
+func parseHunkHeader(line string) (*DiffHunk, error) {
+	end := strings.Index(line[3:], " @@")
+	if end < 0 {
+		return nil, fmt.Errorf("invalid hunk header: %s", line)
+	}
+
+	ranges := strings.Fields(line[3 : 3+end])
+	if len(ranges) != 2 || !strings.HasPrefix(ranges[0], "-") || !strings.HasPrefix(ranges[1], "+") {
+		return nil, fmt.Errorf("invalid hunk header: %s", line)
+	}
+
+	hunk := &DiffHunk{Header: strings.TrimSpace(line[3+end+3:])}
+	var err error
+	if hunk.OldStart, hunk.OldLines, err = parseHunkRange(ranges[0][1:]); err != nil {
+		return nil, fmt.Errorf("invalid hunk header: %s", line)
+	}
+	if hunk.NewStart, hunk.NewLines, err = parseHunkRange(ranges[1][1:]); err != nil {
+		return nil, fmt.Errorf("invalid hunk header: %s", line)
+	}
+	return hunk, nil
+}
+
+// parseHunkRange parses "start[,count]"; the count defaults to one
+func parseHunkRange(text string) (int, int, error) {
+	start, count, found := strings.Cut(text, ",")
+	s, err := strconv.Atoi(start)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !found {
+		return s, 1, nil
+	}
+	c, err := strconv.Atoi(count)
+	if err != nil {
+		return 0, 0, err
+	}
+	return s, c, nil
+}
+
+// parseDiffPath parses a ---/+++ path, returning "" for /dev/null
+func parseDiffPath(path, prefix string) string {
+	path = strings.TrimSuffix(path, "\t")
+	if path == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(unquotePath(path), prefix)
+}
+
+// unquotePath unquotes a path git printed as a C-style quoted string
+func unquotePath(path string) string {
+	if strings.HasPrefix(path, `"`) {
+		if unquoted, err := strconv.Unquote(path); err == nil {
+			return unquoted
+		}
+	}
+	return path
+}
+
+// cutQuoted splits a leading quoted string from the rest of the text
+func cutQuoted(text string) (string, string, bool) {
+	for i := 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '"':
+			unquoted, err := strconv.Unquote(text[:i+1])
+			if err != nil {
+				return "", "", false
+			}
+			return unquoted, text[i+1:], true
+		}
+	}
+	return "", "", false
+}
+
+// parseFileMode parses an octal git file mode
+func parseFileMode(mode string) os.FileMode {
+	value, err := strconv.ParseUint(strings.TrimSpace(mode), 8, 32)
+	if err != nil {
+		return 0
+	}
+	return os.FileMode(value)
+}
+
+// Path returns the path identifying the file, preferring the new side
+func (f *DiffFile) Path() string {
+	if f.NewPath != "" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+// scanRawLines splits lines like bufio.ScanLines but keeps a carriage
+// return ending a line, as it is part of the content of files with CRLF
+// line endings
+func scanRawLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,139 @@
+package git
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDiff(t *testing.T) {
+	text := `commit abc123
+Author: Test User <test@example.com>
+
+diff --git a/file.txt b/file.txt
+index 1234567..abcdefg 100644
+--- a/file.txt
++++ b/file.txt
+@@ -1,3 +1,4 @@ func main()
+ line 1
+-line 2
++line 2 modified
++line 3 added
+
+@@ -10 +11 @@
+-old
++new
+\ No newline at end of file
+diff --git a/old name.txt b/new name.txt
+similarity index 90%
+rename from old name.txt
+rename to new name.txt
+diff --git a/image.png b/image.png
+new file mode 100644
+index 0000000..1234567
+Binary files /dev/null and b/image.png differ
+`
+
+	diff, err := ParseDiff(text)
+	assert.NoError(t, err)
+	assert.Len(t, diff.Files, 3)
+
+	file := diff.Files[0]
+	assert.Equal(t, "file.txt", file.OldPath)
+	assert.Equal(t, "file.txt", file.NewPath)
+	assert.Equal(t, "1234567", file.OldHash)
+	assert.Equal(t, "abcdefg", file.NewHash)
+	assert.Equal(t, os.FileMode(0100644), file.NewMode)
+	assert.Equal(t, []string{"index 1234567..abcdefg 100644"}, file.Headers)
+	assert.Len(t, file.Hunks, 2)
+
+	hunk := file.Hunks[0]
+	assert.Equal(t, 1, hunk.OldStart)
+	assert.Equal(t, 3, hunk.OldLines)
+	assert.Equal(t, 1, hunk.NewStart)
+	assert.Equal(t, 4, hunk.NewLines)
+	assert.Equal(t, "func main()", hunk.Header)
+	assert.Len(t, hunk.Lines, 5)
+	assert.Equal(t, &DiffLine{Type: DiffLineDeletion, Content: "line 2", OldLine: 2}, hunk.Lines[1])
+	assert.Equal(t, &DiffLine{Type: DiffLineAddition, Content: "line 3 added", NewLine: 3}, hunk.Lines[3])
+	assert.Equal(t, &DiffLine{Type: DiffLineContext, Content: "", OldLine: 3, NewLine: 4}, hunk.Lines[4])
+
+	hunk = file.Hunks[1]
+	assert.Equal(t, 1, hunk.OldLines)
+	assert.Equal(t, 11, hunk.NewStart)
+	assert.Len(t, hunk.Lines, 3)
+	assert.Equal(t, DiffLineNoNewline, hunk.Lines[2].Type)
+
+	file = diff.Files[1]
+	assert.True(t, file.IsRenamed)
+	assert.Equal(t, "old name.txt", file.OldPath)
+	assert.Equal(t, "new name.txt", file.NewPath)
+	assert.Equal(t, 90, file.Similarity)
+	assert.Empty(t, file.Hunks)
+
+	file = diff.Files[2]
+	assert.True(t, file.IsNew)
+	assert.True(t, file.IsBinary)
+	assert.Equal(t, "image.png", file.Path())
+}
+
+func TestParseDiffQuotedPaths(t *testing.T) {
+	diff, err := ParseDiff(`diff --git "a/tab\there.txt" "b/tab\there.txt"
+deleted file mode 100644
+index 1234567..0000000
+--- "a/tab\there.txt"
++++ /dev/null
+@@ -1 +0,0 @@
+-gone
+`)
+	assert.NoError(t, err)
+	assert.Len(t, diff.Files, 1)
+
+	file := diff.Files[0]
+	assert.True(t, file.IsDeleted)
+	assert.Equal(t, "tab\there.txt", file.OldPath)
+	assert.Equal(t, "tab\there.txt", file.Path())
+	assert.Equal(t, 0, file.Hunks[0].NewLines)
+}
+
+func TestParseDiffInvalidHunk(t *testing.T) {
+	_, err := ParseDiff("diff --git a/file.txt b/file.txt\n@@ -x +1 @@\n")
+	assert.Error(t, err)
+}
+
+func TestParseDiffCRLF(t *testing.T) {
+	diff, err := ParseDiff("diff --git a/file.txt b/file.txt\n--- a/file.txt\n+++ b/file.txt\n@@ -1 +1 @@\n-old\r\n+new\r\n")
+	assert.NoError(t, err)
+	lines := diff.Files[0].Hunks[0].Lines
+	assert.Equal(t, "old\r", lines[0].Content)
+	assert.Equal(t, "new\r", lines[1].Content)
+}
+
+func TestParseDiffColored(t *testing.T) {
+	text := "\x1b[1mdiff --git a/file.txt b/file.txt\x1b[m\n" +
+		"\x1b[1m--- a/file.txt\x1b[m\n" +
+		"\x1b[1m+++ b/file.txt\x1b[m\n" +
+		"\x1b[36m@@ -1,2 +1,2 @@\x1b[m func()\n" +
+		" same\n" +
+		"\x1b[31m-old\x1b[m\n" +
+		"\x1b[32m+\x1b[m\x1b[32mnew\x1b[m\x1b[41m \x1b[m\n"
+
+	diff, err := ParseDiff(text)
+	assert.NoError(t, err)
+	assert.Len(t, diff.Files, 1)
+	assert.Equal(t, "file.txt", diff.Files[0].NewPath)
+
+	hunk := diff.Files[0].Hunks[0]
+	assert.Equal(t, "func()", hunk.Header)
+	assert.Equal(t, &DiffLine{Type: DiffLineContext, Content: "same", OldLine: 1, NewLine: 1}, hunk.Lines[0])
+	assert.Equal(t, &DiffLine{Type: DiffLineDeletion, Content: "old", OldLine: 2, Colored: "\x1b[31m-old\x1b[m"}, hunk.Lines[1])
+	assert.Equal(t, "new ", hunk.Lines[2].Content)
+	assert.Equal(t, "\x1b[32m+\x1b[m\x1b[32mnew\x1b[m\x1b[41m \x1b[m", hunk.Lines[2].Colored)
+}
+
+func TestStripANSI(t *testing.T) {
+	assert.Equal(t, "plain", StripANSI("plain"))
+	assert.Equal(t, "+added", StripANSI("\x1b[1;32m+added\x1b[m"))
+	assert.Equal(t, "moved", StripANSI("\x1b[38;5;208mmoved\x1b[K\x1b[m"))
+}
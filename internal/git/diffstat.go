@@ -0,0 +1,75 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FileStat represents the number of changed lines of a file in a diff
+type FileStat struct {
+	Path      string
+	OldPath   string // Path before a rename or copy, empty otherwise
+	Additions int
+	Deletions int
+	IsBinary  bool
+}
+
+// ParseNumstat parses the output of git diff --numstat -z
+func ParseNumstat(output []byte) ([]*FileStat, error) {
+	var stats []*FileStat
+
+	fields := bytes.Split(output, []byte{0})
+	for i := 0; i < len(fields); i++ {
+		record := string(fields[i])
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+
+		parts := strings.SplitN(record, "\t", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid numstat record: %q", record)
+		}
+
+		stat := &FileStat{Path: parts[2]}
+		if parts[0] == "-" && parts[1] == "-" {
+			stat.IsBinary = true
+		} else {
+			var err error
+			if stat.Additions, err = strconv.Atoi(parts[0]); err != nil {
+				return nil, fmt.Errorf("invalid numstat record: %q", record)
+			}
+			if stat.Deletions, err = strconv.Atoi(parts[1]); err != nil {
+				return nil, fmt.Errorf("invalid numstat record: %q", record)
+			}
+		}
+
+		// Renames and copies have an empty path followed by the old and
+		// new paths as separate fields
+		if stat.Path == "" {
+			if i+2 >= len(fields) || len(fields[i+2]) == 0 {
+				return nil, fmt.Errorf("truncated numstat rename record")
+			}
+			stat.OldPath = string(fields[i+1])
+			stat.Path = string(fields[i+2])
+			i += 2
+		}
+
+		stats = append(stats, stat)
+	}
+
+	return stats, nil
+}
+
+// validateRevRange rejects revision ranges git would parse as options
+func validateRevRange(revRange string) error {
+	if revRange == "" {
+		return fmt.Errorf("no revision range given")
+	}
+	if strings.HasPrefix(revRange, "-") {
+		return fmt.Errorf("invalid revision range: %s", revRange)
+	}
+	return nil
+}
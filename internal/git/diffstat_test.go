@@ -0,0 +1,33 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNumstat(t *testing.T) {
+	output := []byte("3\t1\tmain.go\x00-\t-\tlogo.png\x000\t0\t\x00old name.go\x00new name.go\x00")
+
+	stats, err := ParseNumstat(output)
+	assert.NoError(t, err)
+	assert.Equal(t, []*FileStat{
+		{Path: "main.go", Additions: 3, Deletions: 1},
+		{Path: "logo.png", IsBinary: true},
+		{Path: "new name.go", OldPath: "old name.go"},
+	}, stats)
+}
+
+func TestParseNumstatInvalid(t *testing.T) {
+	_, err := ParseNumstat([]byte("x\t1\tmain.go\x00"))
+	assert.Error(t, err)
+
+	_, err = ParseNumstat([]byte("1\t1\t\x00old.go\x00"))
+	assert.Error(t, err)
+}
+
+func TestValidateRevRange(t *testing.T) {
+	assert.NoError(t, validateRevRange("v1.0..v1.1"))
+	assert.Error(t, validateRevRange(""))
+	assert.Error(t, validateRevRange("--output=/tmp/x"))
+}
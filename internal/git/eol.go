@@ -0,0 +1,196 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sameButEOL returns whether two lines are the same once their carriage
+// returns are left out, as lines whose line ending alone changed are
+func sameButEOL(a, b string) bool {
+	return strings.TrimSuffix(a, "\r") == strings.TrimSuffix(b, "\r")
+}
+
+// markedLine is a line of a hunk along with the no newline marker
+// following it, if any
+type markedLine struct {
+	line   *DiffLine
+	marker *DiffLine
+}
+
+// changeBlock is a run of deleted lines followed by the lines added in
+// their place
+type changeBlock struct {
+	deleted []markedLine
+	added   []markedLine
+}
+
+// eolPairs returns how many deleted lines at the start and at the end of
+// the block only differ from their added counterpart by their line ending
+func (b changeBlock) eolPairs() (leading, trailing int) {
+	n := min(len(b.deleted), len(b.added))
+	for leading < n && sameButEOL(b.deleted[leading].line.Content, b.added[leading].line.Content) {
+		leading++
+	}
+	for trailing < n-leading && sameButEOL(b.deleted[len(b.deleted)-1-trailing].line.Content, b.added[len(b.added)-1-trailing].line.Content) {
+		trailing++
+	}
+	return leading, trailing
+}
+
+// eolOnly returns whether the block changes line endings alone
+func (b changeBlock) eolOnly() bool {
+	leading, trailing := b.eolPairs()
+	return len(b.deleted) == len(b.added) && leading+trailing == len(b.deleted)
+}
+
+// splitHunk splits the lines of a hunk into its context lines, kept as
+// they are, and its blocks of changes
+func splitHunk(hunk *DiffHunk) []any {
+	var parts []any
+	var block *changeBlock
+	var last *markedLine
+	for _, line := range hunk.Lines {
+		switch line.Type {
+		case DiffLineContext:
+			parts = append(parts, &markedLine{line: line})
+			last = parts[len(parts)-1].(*markedLine)
+			block = nil
+		case DiffLineDeletion, DiffLineAddition:
+			if block == nil || (line.Type == DiffLineDeletion && len(block.added) > 0) {
+				block = &changeBlock{}
+				parts = append(parts, block)
+			}
+			if line.Type == DiffLineDeletion {
+				block.deleted = append(block.deleted, markedLine{line: line})
+				last = &block.deleted[len(block.deleted)-1]
+			} else {
+				block.added = append(block.added, markedLine{line: line})
+				last = &block.added[len(block.added)-1]
+			}
+		case DiffLineNoNewline:
+			if last != nil {
+				last.marker = line
+			}
+		}
+	}
+	return parts
+}
+
+// EOLOnly returns whether the changes of the file change nothing but line
+// endings, such as a file converted to CRLF
+func (f *DiffFile) EOLOnly() bool {
+	changed := false
+	for _, hunk := range f.Hunks {
+		for _, part := range splitHunk(hunk) {
+			if block, ok := part.(*changeBlock); ok {
+				if !block.eolOnly() {
+					return false
+				}
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// HideEOLChanges turns the lines of a diff whose line ending alone changed
+// into context lines, dropping the hunks left without changes. The lines
+// hidden are counted in the HiddenEOLChanges of their file.
+func HideEOLChanges(diff *Diff) {
+	if diff == nil {
+		return
+	}
+	for _, file := range diff.Files {
+		var hunks []*DiffHunk
+		for _, hunk := range file.Hunks {
+			var lines []*DiffLine
+			add := func(l markedLine) {
+				lines = append(lines, l.line)
+				if l.marker != nil {
+					lines = append(lines, l.marker)
+				}
+			}
+			// The line added is kept as context, the old line number
+			// being the one of the line it replaces
+			keep := func(deleted, added markedLine) {
+				context := *added.line
+				context.Type = DiffLineContext
+				context.OldLine = deleted.line.OldLine
+				lines = append(lines, &context)
+				file.HiddenEOLChanges++
+			}
+
+			changed := false
+			for _, part := range splitHunk(hunk) {
+				block, ok := part.(*changeBlock)
+				if !ok {
+					add(*part.(*markedLine))
+					continue
+				}
+				leading, trailing := block.eolPairs()
+				for i := 0; i < leading; i++ {
+					keep(block.deleted[i], block.added[i])
+				}
+				for _, l := range block.deleted[leading : len(block.deleted)-trailing] {
+					add(l)
+					changed = true
+				}
+				for _, l := range block.added[leading : len(block.added)-trailing] {
+					add(l)
+					changed = true
+				}
+				for i := trailing; i > 0; i-- {
+					keep(block.deleted[len(block.deleted)-i], block.added[len(block.added)-i])
+				}
+			}
+			if changed {
+				hunk.Lines = lines
+				hunks = append(hunks, hunk)
+			}
+		}
+		file.Hunks = hunks
+	}
+}
+
+// EOLOnlyChanges returns the files of the worktree, or of the index with
+// staged, whose changes only concern line endings. Git leaves them out of
+// the files it counts changed lines of when carriage returns are ignored.
+func (c *GoGitClient) EOLOnlyChanges(staged bool) ([]string, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+
+	numstat := func(extra ...string) ([]*FileStat, error) {
+		args := []string{"diff", "--numstat", "-z", "--no-renames"}
+		if staged {
+			args = append(args, "--cached")
+		}
+		output, err := c.runGit(c.path, append(append(args, extra...), "--")...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get diffstat of the changes: %w", err)
+		}
+		return ParseNumstat(output)
+	}
+
+	all, err := numstat()
+	if err != nil || len(all) == 0 {
+		return nil, err
+	}
+	changed, err := numstat("--ignore-cr-at-eol")
+	if err != nil {
+		return nil, err
+	}
+	other := make(map[string]bool, len(changed))
+	for _, stat := range changed {
+		other[stat.Path] = true
+	}
+
+	var paths []string
+	for _, stat := range all {
+		if !stat.IsBinary && !other[stat.Path] {
+			paths = append(paths, stat.Path)
+		}
+	}
+	return paths, nil
+}
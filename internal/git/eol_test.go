@@ -0,0 +1,65 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHideEOLChanges(t *testing.T) {
+	// Line endings alone changing around a real change
+	hunks := "@@ -1,4 +1,4 @@\n a\n-b\n-c\n-d\n+b\r\n+three\n+d\r\n@@ -9 +9 @@\n-i\n+i\r\n"
+	diff, err := ParseDiff("diff --git a/file b/file\n--- a/file\n+++ b/file\n" + hunks)
+	require.NoError(t, err)
+	file := diff.Files[0]
+	assert.False(t, file.EOLOnly())
+
+	HideEOLChanges(diff)
+	assert.Equal(t, 3, file.HiddenEOLChanges)
+	require.Len(t, file.Hunks, 1)
+	prefixes := map[DiffLineType]string{DiffLineContext: " ", DiffLineAddition: "+", DiffLineDeletion: "-"}
+	var lines []string
+	for _, line := range file.Hunks[0].Lines {
+		lines = append(lines, prefixes[line.Type]+line.Content)
+	}
+	assert.Equal(t, []string{" a", " b\r", "-c", "+three", " d\r"}, lines)
+	assert.Equal(t, 4, file.Hunks[0].Lines[4].OldLine)
+
+	// Files whose line endings alone changed are left without hunks
+	diff, err = ParseDiff("diff --git a/file b/file\n--- a/file\n+++ b/file\n@@ -1,2 +1,2 @@\n-a\n-b\n\\ No newline at end of file\n+a\r\n+b\r\n")
+	require.NoError(t, err)
+	assert.True(t, diff.Files[0].EOLOnly())
+	HideEOLChanges(diff)
+	assert.Empty(t, diff.Files[0].Hunks)
+	assert.Equal(t, 2, diff.Files[0].HiddenEOLChanges)
+}
+
+func TestEOLOnlyChanges(t *testing.T) {
+	dir := newTestRepo(t)
+	writeTestFile(t, dir, "crlf.txt", "one\ntwo\n")
+	writeTestFile(t, dir, "changed.txt", "one\ntwo\n")
+	gitIn(t, dir, "add", ".")
+	gitIn(t, dir, "commit", "--quiet", "-m", "files")
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	writeTestFile(t, dir, "crlf.txt", "one\r\ntwo\r\n")
+	writeTestFile(t, dir, "changed.txt", "one\r\n2\r\n")
+	paths, err := client.EOLOnlyChanges(false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"crlf.txt"}, paths)
+
+	paths, err = client.EOLOnlyChanges(true)
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+	gitIn(t, dir, "add", ".")
+	paths, err = client.EOLOnlyChanges(true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"crlf.txt"}, paths)
+
+	diff, err := client.GetStagedDiff("crlf.txt")
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	assert.True(t, diff.Files[0].EOLOnly())
+}
@@ -0,0 +1,66 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ConfigValue returns the value of a git configuration key as seen from the
+// repository, empty when it is not set
+func (c *GoGitClient) ConfigValue(key string) string {
+	if c.repo == nil {
+		return ""
+	}
+	output, err := c.runGit(c.path, "config", "--get", key)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// Fetch fetches all remotes, passing the progress lines git reports to
+// progress as they come. Only remote-tracking branches and tags are
+// updated, local branches are left alone. Cancelling the context stops git.
+func (c *GoGitClient) Fetch(ctx context.Context, progress func(line string)) error {
+	if c.repo == nil {
+		return fmt.Errorf("repository not opened")
+	}
+
+	return runWithProgress(ctx, c.remoteCommand(ctx, "fetch", "--all", "--progress"), progress)
+}
+
+// Pull fetches the upstream of the current branch and fast-forwards the
+// branch to it, passing the progress lines git reports to progress as they
+// come. A branch which diverged from its upstream is left alone, to be
+// merged or rebased. Cancelling the context stops git.
+func (c *GoGitClient) Pull(ctx context.Context, progress func(line string)) error {
+	if c.repo == nil {
+		return fmt.Errorf("repository not opened")
+	}
+	return runWithProgress(ctx, c.remoteCommand(ctx, "pull", "--ff-only", "--progress"), progress)
+}
+
+// SetAskPass makes the git commands talking to remotes ask for credentials,
+// and ssh for passphrases and unknown host keys, by running program with
+// env added to its environment. Without one they fail instead of reading
+// the terminal, which the UI owns. Keys held by an ssh agent are used
+// either way.
+func (c *GoGitClient) SetAskPass(program string, env []string) {
+	c.askPass, c.askPassEnv = program, env
+}
+
+// remoteCommand returns a git command talking to remotes, stopped when ctx
+// is cancelled
+func (c *GoGitClient) remoteCommand(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = c.path
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if c.askPass != "" {
+		cmd.Env = append(cmd.Env, "GIT_ASKPASS="+c.askPass, "SSH_ASKPASS="+c.askPass, "SSH_ASKPASS_REQUIRE=force")
+		cmd.Env = append(cmd.Env, c.askPassEnv...)
+	}
+	return cmd
+}
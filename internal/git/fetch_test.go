@@ -0,0 +1,65 @@
+package git
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetch(t *testing.T) {
+	upstream := newTestRepo(t)
+	dir := t.TempDir()
+	gitIn(t, dir, "clone", "--quiet", upstream, ".")
+	local := gitIn(t, dir, "rev-parse", "main")
+
+	writeTestFile(t, upstream, "new.txt", "new\n")
+	gitIn(t, upstream, "add", "new.txt")
+	gitIn(t, upstream, "commit", "--quiet", "-m", "new")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+	assert.Empty(t, client.ConfigValue("tig.autoFetch"))
+	gitIn(t, dir, "config", "tig.autoFetch", "10")
+	assert.Equal(t, "10", client.ConfigValue("tig.autofetch"))
+
+	require.NoError(t, client.Fetch(context.Background(), nil))
+	assert.Equal(t, gitIn(t, upstream, "rev-parse", "main"), gitIn(t, dir, "rev-parse", "origin/main"))
+
+	// The local branch stays where it was
+	assert.Equal(t, local, gitIn(t, dir, "rev-parse", "main"))
+	status, err := client.GetBranchStatus()
+	require.NoError(t, err)
+	assert.Equal(t, 1, status.Behind)
+}
+
+func TestPull(t *testing.T) {
+	upstream := newTestRepo(t)
+	dir := t.TempDir()
+	gitIn(t, dir, "clone", "--quiet", upstream, ".")
+
+	writeTestFile(t, upstream, "new.txt", "new\n")
+	gitIn(t, upstream, "add", "new.txt")
+	gitIn(t, upstream, "commit", "--quiet", "-m", "new")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+	require.NoError(t, client.Pull(context.Background(), nil))
+	assert.Equal(t, gitIn(t, upstream, "rev-parse", "main"), gitIn(t, dir, "rev-parse", "main"))
+	assert.FileExists(t, filepath.Join(dir, "new.txt"))
+
+	// A branch which diverged is not merged
+	writeTestFile(t, upstream, "upstream.txt", "upstream\n")
+	gitIn(t, upstream, "add", "upstream.txt")
+	gitIn(t, upstream, "commit", "--quiet", "-m", "upstream")
+	writeTestFile(t, dir, "local.txt", "local\n")
+	gitIn(t, dir, "config", "user.name", "Test User")
+	gitIn(t, dir, "config", "user.email", "test@example.com")
+	gitIn(t, dir, "add", "local.txt")
+	gitIn(t, dir, "commit", "--quiet", "-m", "local")
+	local := gitIn(t, dir, "rev-parse", "main")
+	assert.Error(t, client.Pull(context.Background(), nil))
+	assert.Equal(t, local, gitIn(t, dir, "rev-parse", "main"))
+}
@@ -0,0 +1,22 @@
+package git
+
+import "fmt"
+
+// FileLog lists the commits of HEAD touching a file, newest first and
+// following its renames, none before the first commit. A maxCount of 0
+// lists all of them.
+func (c *GoGitClient) FileLog(path string, maxCount int) ([]*Commit, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no file given")
+	}
+
+	args := []string{"--follow"}
+	if maxCount > 0 {
+		args = append(args, fmt.Sprintf("--max-count=%d", maxCount))
+	}
+	commits, err := c.logCommits(append(args, "--", path)...)
+	if err != nil && c.UnbornBranch() != "" {
+		return nil, nil
+	}
+	return commits, err
+}
@@ -0,0 +1,42 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileLog(t *testing.T) {
+	dir := newTestRepo(t)
+	base := gitIn(t, dir, "rev-parse", "HEAD")
+	writeTestFile(t, dir, "other.txt", "other\n")
+	gitIn(t, dir, "add", "other.txt")
+	gitIn(t, dir, "commit", "--quiet", "-m", "other")
+	gitIn(t, dir, "mv", "file.txt", "renamed.txt")
+	gitIn(t, dir, "commit", "--quiet", "-m", "rename")
+	rename := gitIn(t, dir, "rev-parse", "HEAD")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	// The history goes on past the rename, skipping the other commit
+	commits, err := client.FileLog("renamed.txt", 0)
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	assert.Equal(t, rename, commits[0].Hash)
+	assert.Equal(t, "rename", commits[0].Summary)
+	assert.Equal(t, base, commits[1].Hash)
+
+	commits, err = client.FileLog("renamed.txt", 1)
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	assert.Equal(t, rename, commits[0].Hash)
+
+	commits, err = client.FileLog("untracked.txt", 0)
+	require.NoError(t, err)
+	assert.Empty(t, commits)
+
+	_, err = client.FileLog("", 0)
+	assert.Error(t, err)
+}
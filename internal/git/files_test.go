@@ -0,0 +1,46 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFiles(t *testing.T) {
+	dir := newTestRepo(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "docs", "api"), 0755))
+	writeTestFile(t, dir, "docs/readme.md", "readme\n")
+	writeTestFile(t, dir, "docs/api/index.md", "api\n")
+	gitIn(t, dir, "add", ".")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	names := func(files []*File) map[string]bool {
+		result := make(map[string]bool)
+		for _, file := range files {
+			result[file.Path] = file.IsDir
+		}
+		return result
+	}
+
+	files, err := client.GetFiles("")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"file.txt": false, "docs": true}, names(files))
+
+	files, err = client.GetFiles("docs")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"readme.md": false, "api": true}, names(files))
+	for _, file := range files {
+		if file.Path == "readme.md" {
+			assert.Equal(t, int64(7), file.Size)
+		}
+	}
+
+	files, err = client.GetFiles("docs/api/")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"index.md": false}, names(files))
+}
@@ -0,0 +1,144 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// autosquashSearchDepth bounds how far back fixup commits and their targets
+// are looked for when there is no upstream branch
+const autosquashSearchDepth = 200
+
+// CommitFixup commits the staged changes as a fixup! commit for the given
+// commit and returns the abbreviated hash of the new commit
+func (c *GoGitClient) CommitFixup(hash string) (string, error) {
+	if c.repo == nil {
+		return "", fmt.Errorf("repository not opened")
+	}
+	if err := validateRevRange(hash); err != nil {
+		return "", err
+	}
+
+	// diff --cached --quiet exits with 1 when there are staged changes
+	if _, err := c.runGit(c.path, "diff", "--cached", "--quiet"); err == nil {
+		return "", fmt.Errorf("no staged changes to commit as a fixup")
+	}
+
+	if _, err := c.runGit(c.path, "commit", "--quiet", "--no-edit", "--fixup="+hash); err != nil {
+		return "", err
+	}
+
+	head, err := c.runGit(c.path, "rev-parse", "--short", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(head)), nil
+}
+
+// Autosquash folds pending fixup!, squash! and amend! commits into their
+// targets with a non-interactive autosquash rebase starting at the oldest
+// target. It returns the base the rebase started from. A failed rebase is
+// aborted, leaving the branch as it was.
+func (c *GoGitClient) Autosquash() (string, error) {
+	if c.repo == nil {
+		return "", fmt.Errorf("repository not opened")
+	}
+
+	base, err := c.autosquashBase()
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"rebase", "--interactive", "--autosquash", "--autostash"}
+	if base == "" {
+		args = append(args, "--root")
+	} else {
+		args = append(args, base)
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = c.path
+	cmd.Env = append(os.Environ(), "GIT_SEQUENCE_EDITOR=:", "GIT_EDITOR=:")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		c.runGit(c.path, "rebase", "--abort")
+		return "", fmt.Errorf("autosquash failed and was aborted: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	if base == "" {
+		return "root", nil
+	}
+	return base[:min(7, len(base))], nil
+}
+
+// autosquashBase finds the parent of the oldest commit targeted by a fixup
+// commit, or "" if that commit is the root commit
+func (c *GoGitClient) autosquashBase() (string, error) {
+	args := []string{"log", "--format=%H%x00%P%x00%s"}
+	if _, err := c.runGit(c.path, "rev-parse", "--verify", "--quiet", "@{upstream}"); err == nil {
+		args = append(args, "@{upstream}..HEAD")
+	} else {
+		args = append(args, fmt.Sprintf("--max-count=%d", autosquashSearchDepth), "HEAD")
+	}
+
+	output, err := c.runGit(c.path, args...)
+	if err != nil {
+		return "", err
+	}
+
+	// Walk from the newest commit, collecting the subjects fixups refer to
+	// until every one of them has been matched with an older commit
+	pending := make(map[string]bool)
+	var base string
+	found := false
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.SplitN(line, "\x00", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		hash, parents, subject := fields[0], fields[1], fields[2]
+
+		if target, ok := fixupTarget(subject); ok {
+			pending[target] = true
+			continue
+		}
+		for target := range pending {
+			if target == subject || (len(target) >= 7 && strings.HasPrefix(hash, target)) {
+				delete(pending, target)
+				found = true
+				base = ""
+				if parents != "" {
+					base = strings.Fields(parents)[0]
+				}
+			}
+		}
+	}
+
+	for target := range pending {
+		return "", fmt.Errorf("no commit found for fixup of %q", target)
+	}
+	if !found {
+		return "", fmt.Errorf("no fixup commits to squash")
+	}
+	return base, nil
+}
+
+// fixupTarget returns the subject or hash a fixup commit subject refers to
+func fixupTarget(subject string) (string, bool) {
+	found := false
+	for {
+		trimmed := subject
+		for _, prefix := range []string{"fixup! ", "squash! ", "amend! "} {
+			trimmed = strings.TrimPrefix(trimmed, prefix)
+		}
+		if trimmed == subject {
+			return subject, found
+		}
+		subject = trimmed
+		found = true
+	}
+}
@@ -0,0 +1,83 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixupAndAutosquash(t *testing.T) {
+	dir := newTestRepo(t)
+	writeTestFile(t, dir, "other.txt", "other\n")
+	gitIn(t, dir, "add", "other.txt")
+	gitIn(t, dir, "commit", "--quiet", "-m", "other")
+	other := gitIn(t, dir, "rev-parse", "HEAD")
+
+	writeTestFile(t, dir, "last.txt", "last\n")
+	gitIn(t, dir, "add", "last.txt")
+	gitIn(t, dir, "commit", "--quiet", "-m", "last")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	// Nothing is staged yet
+	_, err := client.CommitFixup(other)
+	assert.Error(t, err)
+	_, err = client.Autosquash()
+	assert.Error(t, err)
+
+	writeTestFile(t, dir, "other.txt", "fixed\n")
+	gitIn(t, dir, "add", "other.txt")
+	hash, err := client.CommitFixup(other)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, hash)
+	assert.Equal(t, "fixup! other", gitIn(t, dir, "log", "-1", "--format=%s"))
+
+	// Unstaged changes survive the rebase
+	writeTestFile(t, dir, "file.txt", "dirty\n")
+
+	_, err = client.Autosquash()
+	assert.NoError(t, err)
+	assert.Equal(t, "last\nother\nbase", gitIn(t, dir, "log", "--format=%s"))
+	assert.Equal(t, "fixed", gitIn(t, dir, "show", "HEAD~1:other.txt"))
+	assert.Equal(t, "M file.txt", gitIn(t, dir, "status", "--porcelain"))
+}
+
+func TestAutosquashRoot(t *testing.T) {
+	dir := newTestRepo(t)
+	base := gitIn(t, dir, "rev-parse", "HEAD")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	writeTestFile(t, dir, "file.txt", "fixed\n")
+	gitIn(t, dir, "add", "file.txt")
+	_, err := client.CommitFixup(base)
+	require.NoError(t, err)
+
+	onto, err := client.Autosquash()
+	assert.NoError(t, err)
+	assert.Equal(t, "root", onto)
+	assert.Equal(t, "base", gitIn(t, dir, "log", "--format=%s"))
+	assert.Equal(t, "fixed", gitIn(t, dir, "show", "HEAD:file.txt"))
+}
+
+func TestFixupTarget(t *testing.T) {
+	tests := []struct {
+		subject string
+		target  string
+		ok      bool
+	}{
+		{"fixup! Add feature", "Add feature", true},
+		{"squash! fixup! Add feature", "Add feature", true},
+		{"amend! 1234567", "1234567", true},
+		{"Add feature", "Add feature", false},
+	}
+
+	for _, tt := range tests {
+		target, ok := fixupTarget(tt.subject)
+		assert.Equal(t, tt.target, target, tt.subject)
+		assert.Equal(t, tt.ok, ok, tt.subject)
+	}
+}
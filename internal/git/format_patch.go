@@ -0,0 +1,36 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatPatches writes the commits after base up to tip as patches in dir,
+// relative to the root of the worktree, and returns the files written. An
+// empty base starts the patches at the root commit.
+func (c *GoGitClient) FormatPatches(base, tip, dir string) ([]string, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+	if err := validateRevRange(tip); err != nil {
+		return nil, err
+	}
+
+	args := []string{"format-patch", "--output-directory", dir}
+	if base == "" {
+		args = append(args, "--root", tip)
+	} else {
+		if err := validateRevRange(base); err != nil {
+			return nil, err
+		}
+		args = append(args, base+".."+tip)
+	}
+	output, err := c.runGit(c.path, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(strings.TrimSpace(string(output))) == 0 {
+		return nil, nil
+	}
+	return strings.Split(strings.TrimSpace(string(output)), "\n"), nil
+}
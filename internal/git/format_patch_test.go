@@ -0,0 +1,33 @@
+package git
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatPatches(t *testing.T) {
+	dir := newTestRepo(t)
+	root := gitIn(t, dir, "rev-parse", "HEAD")
+	for _, content := range []string{"two\n", "three\n"} {
+		writeTestFile(t, dir, "file.txt", content)
+		gitIn(t, dir, "commit", "--quiet", "-am", content)
+	}
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	files, err := client.FormatPatches(root, "HEAD", "out")
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("out", "0001-two.patch"), filepath.Join("out", "0002-three.patch")}, files)
+	assert.FileExists(t, filepath.Join(dir, "out", "0002-three.patch"))
+
+	// Without a base the patches start at the root commit
+	files, err = client.FormatPatches("", root, "root")
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("root", "0001-base.patch")}, files)
+
+	_, err = client.FormatPatches(root, "--all", "out")
+	assert.Error(t, err)
+}
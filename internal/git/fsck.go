@@ -0,0 +1,117 @@
+package git
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// FsckResult is what git fsck found in the repository
+type FsckResult struct {
+	Lines    []string // Everything git fsck said, in order
+	Dangling int      // Unreachable objects, which git gc prunes in time
+	Broken   int      // Missing or corrupt objects and broken links
+}
+
+// FsckProblem tells whether a line of git fsck reports a dangling object or
+// a broken one, neither for notices and warnings
+func FsckProblem(line string) (dangling, broken bool) {
+	switch {
+	case strings.HasPrefix(line, "dangling "):
+		return true, false
+	case strings.HasPrefix(line, "missing "), strings.HasPrefix(line, "broken link"),
+		strings.HasPrefix(line, "error"), strings.HasPrefix(line, "fatal:"),
+		strings.Contains(line, "corrupt"), strings.HasPrefix(line, "bad "):
+		return false, true
+	}
+	return false, false
+}
+
+// Fsck checks the connectivity and validity of the objects of the
+// repository, passing each line git fsck says to report as it comes. Finding
+// broken objects is not an error, only failing to check is.
+func (c *GoGitClient) Fsck(ctx context.Context, report func(line string)) (*FsckResult, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "fsck", "--no-progress", "--name-objects")
+	cmd.Dir = c.path
+	output, input := io.Pipe()
+	cmd.Stdout, cmd.Stderr = input, input
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("git fsck: %w", err)
+	}
+	waited := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		input.Close()
+		waited <- err
+	}()
+
+	result := &FsckResult{}
+	scanner := bufio.NewScanner(output)
+	for scanner.Scan() {
+		line := scanner.Text()
+		result.Lines = append(result.Lines, line)
+		dangling, broken := FsckProblem(line)
+		if dangling {
+			result.Dangling++
+		}
+		if broken {
+			result.Broken++
+		}
+		report(line)
+	}
+	// Keep git from blocking on output nobody reads anymore
+	_, _ = io.Copy(io.Discard, output)
+
+	err := <-waited
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && result.Broken > 0) {
+		if len(result.Lines) > 0 {
+			return nil, fmt.Errorf("git fsck: %s", result.Lines[len(result.Lines)-1])
+		}
+		return nil, fmt.Errorf("git fsck: %w", err)
+	}
+	return result, nil
+}
+
+// corruptionMessages are parts of the errors git and go-git give when an
+// object of the repository is missing or damaged
+var corruptionMessages = []string{
+	"is corrupt",
+	"corrupt loose object",
+	"unable to read",
+	"inflate:",
+	"zlib",
+	"bad object",
+	"broken link",
+	"missing blob",
+	"missing tree",
+	"missing commit",
+	"packfile",
+	"hash mismatch",
+}
+
+// IsCorruption returns whether an error suggests that the repository has
+// missing or damaged objects, which git fsck can tell more about
+func IsCorruption(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	for _, part := range corruptionMessages {
+		if strings.Contains(message, part) {
+			return true
+		}
+	}
+	return false
+}
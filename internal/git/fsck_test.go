@@ -0,0 +1,61 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFsckProblem(t *testing.T) {
+	for line, want := range map[string][2]bool{
+		"dangling blob 1234":                          {true, false},
+		"missing blob 1234 (:file.txt)":               {false, true},
+		"broken link from    tree 12 (HEAD^{tree})":   {false, true},
+		"error: object file .git/objects/12 is empty": {false, true},
+		"notice: HEAD points to an unborn branch":     {false, false},
+		"warning in tree 12: zeroPaddedFilemode":      {false, false},
+	} {
+		dangling, broken := FsckProblem(line)
+		assert.Equal(t, want, [2]bool{dangling, broken}, line)
+	}
+}
+
+func TestIsCorruption(t *testing.T) {
+	assert.True(t, IsCorruption(errors.New("git log: fatal: loose object 1234 (stored in .git/objects/12/34) is corrupt")))
+	assert.True(t, IsCorruption(errors.New("git show: fatal: bad object HEAD")))
+	assert.False(t, IsCorruption(errors.New("git push: rejected")))
+	assert.False(t, IsCorruption(nil))
+}
+
+func TestFsck(t *testing.T) {
+	dir := newTestRepo(t)
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	var reported []string
+	result, err := client.Fsck(context.Background(), func(line string) { reported = append(reported, line) })
+	require.NoError(t, err)
+	assert.Zero(t, result.Broken)
+	assert.Zero(t, result.Dangling)
+	assert.Equal(t, result.Lines, reported)
+
+	// A blob nothing refers to dangles
+	blob := gitIn(t, dir, "hash-object", "-w", "--stdin")
+	result, err = client.Fsck(context.Background(), func(string) {})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Dangling)
+	assert.Contains(t, result.Lines, "dangling blob "+blob)
+
+	// Losing the blob of a commit and the index breaks them
+	file := gitIn(t, dir, "rev-parse", "HEAD:file.txt")
+	require.NoError(t, os.Remove(filepath.Join(dir, ".git", "objects", file[:2], file[2:])))
+	result, err = client.Fsck(context.Background(), func(string) {})
+	require.NoError(t, err)
+	assert.Positive(t, result.Broken)
+	assert.Contains(t, result.Lines, "missing blob "+file+" (:file.txt)")
+}
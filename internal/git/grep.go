@@ -0,0 +1,113 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// GrepOptions represents options for content searches
+type GrepOptions struct {
+	Rev        string   // Revision searched, the worktree when empty
+	Context    int      // Lines shown around each match
+	IgnoreCase bool     // Match regardless of case
+	Paths      []string // Pathspecs the search is limited to
+}
+
+// GrepLine is a line found by a content search, either matching or shown
+// around a match
+type GrepLine struct {
+	Path   string // Path relative to the repository root
+	Line   int    // Line number, starting at 1
+	Column int    // Column of the first match, 0 for context lines
+	Text   string
+}
+
+// IsMatch returns whether the line matched, rather than being context
+func (l *GrepLine) IsMatch() bool {
+	return l.Column > 0
+}
+
+// Grep searches the tracked files for lines matching a regular expression
+// with git grep. Binary files are skipped. No match is not an error.
+func (c *GoGitClient) Grep(pattern string, opts *GrepOptions) ([]*GrepLine, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+	if opts == nil {
+		opts = &GrepOptions{}
+	}
+	if opts.Rev != "" {
+		if err := validateRevRange(opts.Rev); err != nil {
+			return nil, err
+		}
+	}
+
+	// Columns are only written for matches, which tells them from context
+	// lines as --null leaves no : or - separators
+	args := []string{"grep", "--null", "--line-number", "--column", "--full-name", "-I", "--no-color"}
+	if opts.Context > 0 {
+		args = append(args, "--context="+strconv.Itoa(opts.Context))
+	}
+	if opts.IgnoreCase {
+		args = append(args, "--ignore-case")
+	}
+	args = append(args, "-e", pattern)
+	if opts.Rev != "" {
+		args = append(args, opts.Rev)
+	}
+	args = append(append(args, "--"), opts.Paths...)
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = c.path
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	// git grep exits with 1 when nothing matches
+	output, err := cmd.Output()
+	msg := strings.TrimSpace(stderr.String())
+	var exitErr *exec.ExitError
+	if err != nil && (msg != "" || !errors.As(err, &exitErr) || exitErr.ExitCode() != 1) {
+		if msg != "" {
+			return nil, fmt.Errorf("git grep: %s", msg)
+		}
+		return nil, fmt.Errorf("git grep: %w", err)
+	}
+	return parseGrep(string(output), opts.Rev)
+}
+
+// parseGrep parses the output of git grep --null --line-number --column,
+// the paths of which are prefixed by the revision searched
+func parseGrep(output, rev string) ([]*GrepLine, error) {
+	var lines []*GrepLine
+	for _, line := range strings.Split(output, "\n") {
+		// Groups of lines are separated by --
+		if !strings.Contains(line, "\x00") {
+			continue
+		}
+		fields := strings.SplitN(line, "\x00", 4)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("invalid grep line: %q", line)
+		}
+		number, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid grep line: %q", line)
+		}
+
+		grepLine := &GrepLine{Path: fields[0], Line: number, Text: fields[2]}
+		if rev != "" {
+			grepLine.Path = strings.TrimPrefix(grepLine.Path, rev+":")
+		}
+		if len(fields) == 4 {
+			if grepLine.Column, err = strconv.Atoi(fields[2]); err != nil {
+				return nil, fmt.Errorf("invalid grep line: %q", line)
+			}
+			grepLine.Text = fields[3]
+		}
+		lines = append(lines, grepLine)
+	}
+	return lines, nil
+}
@@ -0,0 +1,45 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrep(t *testing.T) {
+	dir := newTestRepo(t)
+	writeTestFile(t, dir, "code.go", "one\ntwo Needle\nthree\nfour\nfive\nsix needle\n")
+	gitIn(t, dir, "add", ".")
+	gitIn(t, dir, "commit", "--quiet", "-m", "code")
+	writeTestFile(t, dir, "code.go", "needle\n")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	// The worktree is searched, context lines having no column
+	lines, err := client.Grep("needle", &GrepOptions{Context: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []*GrepLine{{Path: "code.go", Line: 1, Column: 1, Text: "needle"}}, lines)
+
+	lines, err = client.Grep("needle", &GrepOptions{Rev: "HEAD", Context: 1, IgnoreCase: true})
+	require.NoError(t, err)
+	assert.Equal(t, []*GrepLine{
+		{Path: "code.go", Line: 1, Text: "one"},
+		{Path: "code.go", Line: 2, Column: 5, Text: "two Needle"},
+		{Path: "code.go", Line: 3, Text: "three"},
+		{Path: "code.go", Line: 5, Text: "five"},
+		{Path: "code.go", Line: 6, Column: 5, Text: "six needle"},
+	}, lines)
+	assert.False(t, lines[0].IsMatch())
+	assert.True(t, lines[1].IsMatch())
+
+	// Nothing matching is no error, unlike a bad pattern
+	lines, err = client.Grep("missing", &GrepOptions{Paths: []string{"file.txt"}})
+	require.NoError(t, err)
+	assert.Empty(t, lines)
+	_, err = client.Grep("[a", nil)
+	assert.Error(t, err)
+	_, err = client.Grep("needle", &GrepOptions{Rev: "--cached"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Init creates a new repository at path, or reinitializes an existing one,
+// and opens it. Without a branch name the initial branch is named after
+// init.defaultBranch.
+func (c *GoGitClient) Init(path, branch string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	if err := os.MkdirAll(absPath, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", absPath, err)
+	}
+
+	args := []string{"init", "--quiet"}
+	if branch != "" {
+		args = append(args, "--initial-branch="+branch)
+	}
+	if _, err := c.runGit(absPath, args...); err != nil {
+		return err
+	}
+	return c.Open(absPath)
+}
+
+// Clone clones the repository at url into dir and opens the clone. Without
+// a dir the clone is made in the current directory, named after the url as
+// git clone does. The path of the clone is returned.
+func (c *GoGitClient) Clone(url, dir string) (string, error) {
+	if url == "" || strings.HasPrefix(url, "-") {
+		return "", fmt.Errorf("invalid repository url: %q", url)
+	}
+	if dir == "" {
+		dir = cloneDir(url)
+		if dir == "" {
+			return "", fmt.Errorf("cannot guess a directory name from %s", url)
+		}
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(absDir), 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(absDir), err)
+	}
+
+	if _, err := c.runGit(filepath.Dir(absDir), "clone", "--quiet", "--", url, absDir); err != nil {
+		return "", err
+	}
+	return absDir, c.Open(absDir)
+}
+
+// cloneDir returns the directory name git clone picks for a url, the last
+// path component without a .git suffix
+func cloneDir(url string) string {
+	url = strings.TrimRight(url, "/")
+	url = strings.TrimSuffix(url, "/.git")
+	url = strings.TrimSuffix(url, ".git")
+
+	// Both host:path and regular paths separate components
+	if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+		url = url[i+1:]
+	}
+	if url == "." || url == ".." {
+		return ""
+	}
+	return url
+}
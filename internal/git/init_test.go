@@ -0,0 +1,55 @@
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := filepath.Join(t.TempDir(), "new")
+
+	client := NewClient()
+	require.NoError(t, client.Init(dir, ""))
+	assert.True(t, client.IsRepository())
+	assert.Equal(t, dir, client.GetRootPath())
+	assert.DirExists(t, filepath.Join(dir, ".git"))
+
+	other := filepath.Join(t.TempDir(), "other")
+	require.NoError(t, client.Init(other, "trunk"))
+	assert.Equal(t, other, client.GetRootPath())
+	assert.Equal(t, "refs/heads/trunk", gitIn(t, other, "symbolic-ref", "HEAD"))
+
+	assert.Error(t, client.Init(t.TempDir(), "bad..name"))
+}
+
+func TestClone(t *testing.T) {
+	origin := newTestRepo(t)
+	parent := t.TempDir()
+
+	client := NewClient()
+	path, err := client.Clone(origin, filepath.Join(parent, "copy"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(parent, "copy"), path)
+	assert.True(t, client.IsRepository())
+	assert.FileExists(t, filepath.Join(path, "file.txt"))
+
+	_, err = client.Clone("--upload-pack=true", "")
+	assert.Error(t, err)
+	_, err = client.Clone(filepath.Join(parent, "missing"), filepath.Join(parent, "other"))
+	assert.Error(t, err)
+}
+
+func TestCloneDir(t *testing.T) {
+	assert.Equal(t, "tig", cloneDir("https://github.com/jonas/tig.git"))
+	assert.Equal(t, "tig", cloneDir("https://github.com/jonas/tig/"))
+	assert.Equal(t, "tig", cloneDir("git@github.com:tig.git"))
+	assert.Equal(t, "repo", cloneDir("/srv/repo/.git"))
+	assert.Equal(t, "", cloneDir(".."))
+}
@@ -0,0 +1,101 @@
+package git
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// LargeObject is one of the largest blobs of the history, with the commit
+// which introduced it
+type LargeObject struct {
+	BlobSize
+	Commit  string // Empty when no commit adds it, as for blobs only tags refer to
+	Summary string
+}
+
+// LargeObjects returns the n largest blobs reachable from any ref, largest
+// first, each with the oldest commit adding it and the path it was added
+// at. progress is told how many commits were walked to find those commits.
+func (c *GoGitClient) LargeObjects(ctx context.Context, n int, progress func(line string)) ([]LargeObject, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+
+	blobs, err := c.largestBlobs(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]LargeObject, len(blobs))
+	wanted := make(map[string]int, len(blobs))
+	for i, blob := range blobs {
+		objects[i] = LargeObject{BlobSize: blob}
+		wanted[blob.Hash] = i
+	}
+	if len(wanted) == 0 {
+		return objects, nil
+	}
+
+	// Walk the history from its start, stopping once every blob was seen
+	walk, cancel := context.WithCancel(ctx)
+	defer cancel()
+	cmd := exec.CommandContext(walk, "git", "log", "--all", "--reverse", "--no-renames",
+		"--raw", "--no-abbrev", "--format=%x00%H %s")
+	cmd.Dir = c.path
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+
+	var hash, summary string
+	walked := 0
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() && len(wanted) > 0 {
+		line := scanner.Text()
+		if rest, ok := strings.CutPrefix(line, "\x00"); ok {
+			hash, summary, _ = strings.Cut(rest, " ")
+			if walked++; walked%1000 == 0 {
+				progress(fmt.Sprintf("%d commits walked", walked))
+			}
+			continue
+		}
+		if i, ok := wanted[rawBlob(line)]; ok {
+			objects[i].Commit, objects[i].Summary = hash, summary
+			if _, path, ok := strings.Cut(line, "\t"); ok {
+				objects[i].Path = path
+			}
+			delete(wanted, objects[i].Hash)
+		}
+	}
+
+	if len(wanted) == 0 {
+		// Done early: git is stopped rather than left to finish the walk
+		cancel()
+		_ = cmd.Wait()
+		return objects, nil
+	}
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+	return objects, nil
+}
+
+// rawBlob returns the blob a line of git diff --raw leaves at its path,
+// empty for deletions and lines of other kinds
+func rawBlob(line string) string {
+	// :100644 100644 <old> <new> M\tpath
+	fields := strings.Fields(strings.SplitN(line, "\t", 2)[0])
+	if !strings.HasPrefix(line, ":") || len(fields) < 5 || strings.HasPrefix(fields[4], "D") {
+		return ""
+	}
+	return fields[3]
+}
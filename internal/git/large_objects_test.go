@@ -0,0 +1,59 @@
+package git
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawBlob(t *testing.T) {
+	assert.Equal(t, "bbb", rawBlob(":100644 100644 aaa bbb M\tfile.txt"))
+	assert.Equal(t, "bbb", rawBlob(":000000 100644 000 bbb A\tnew file.txt"))
+	assert.Empty(t, rawBlob(":100644 000000 aaa 000 D\tgone.txt"))
+	assert.Empty(t, rawBlob(""))
+	assert.Empty(t, rawBlob("\x00abc summary"))
+}
+
+func TestLargeObjects(t *testing.T) {
+	dir := newTestRepo(t)
+	writeTestFile(t, dir, "assets.bin", strings.Repeat("x", 5000))
+	gitIn(t, dir, "add", "assets.bin")
+	gitIn(t, dir, "commit", "--quiet", "-m", "add assets")
+	introduced := gitIn(t, dir, "rev-parse", "HEAD")
+
+	// Moving the blob doesn't change where it came from
+	gitIn(t, dir, "mv", "assets.bin", "moved.bin")
+	gitIn(t, dir, "commit", "--quiet", "-m", "move assets")
+
+	// Blobs of other branches are found too, even once deleted
+	gitIn(t, dir, "checkout", "--quiet", "-b", "topic")
+	writeTestFile(t, dir, "dump.sql", strings.Repeat("y", 3000))
+	gitIn(t, dir, "add", "dump.sql")
+	gitIn(t, dir, "commit", "--quiet", "-m", "add dump")
+	dumped := gitIn(t, dir, "rev-parse", "HEAD")
+	gitIn(t, dir, "rm", "--quiet", "dump.sql")
+	gitIn(t, dir, "commit", "--quiet", "-m", "remove dump")
+	gitIn(t, dir, "checkout", "--quiet", "main")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	objects, err := client.LargeObjects(context.Background(), 2, func(string) {})
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, int64(5000), objects[0].Size)
+	assert.Equal(t, "assets.bin", objects[0].Path)
+	assert.Equal(t, introduced, objects[0].Commit)
+	assert.Equal(t, "add assets", objects[0].Summary)
+	assert.Equal(t, int64(3000), objects[1].Size)
+	assert.Equal(t, "dump.sql", objects[1].Path)
+	assert.Equal(t, dumped, objects[1].Commit)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.LargeObjects(ctx, 2, func(string) {})
+	assert.Error(t, err)
+}
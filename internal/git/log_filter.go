@@ -0,0 +1,59 @@
+package git
+
+import "fmt"
+
+// LogFilter limits the log to the commits of an author, made within a date
+// range or touching a path, reachable from a branch
+type LogFilter struct {
+	Author string // Pattern matched against author names and emails
+	Path   string // File or directory the commits touch
+	Since  string // Dates in any format git log understands, like "2 weeks ago"
+	Until  string
+	Branch string // Revision the log starts from, HEAD when empty
+}
+
+// IsEmpty returns whether the filter lets every commit through
+func (f LogFilter) IsEmpty() bool {
+	return f == LogFilter{}
+}
+
+// logArgs returns the git log arguments applying the filter
+func (f LogFilter) logArgs() []string {
+	var args []string
+	if f.Author != "" {
+		args = append(args, "--author="+f.Author)
+	}
+	if f.Since != "" {
+		args = append(args, "--since="+f.Since)
+	}
+	if f.Until != "" {
+		args = append(args, "--until="+f.Until)
+	}
+	rev := "HEAD"
+	if f.Branch != "" {
+		rev = f.Branch
+	}
+	args = append(args, "--end-of-options", rev, "--")
+	if f.Path != "" {
+		args = append(args, f.Path)
+	}
+	return args
+}
+
+// FilteredLog lists the commits matching the filter, newest first. A
+// maxCount of 0 lists all of them.
+func (c *GoGitClient) FilteredLog(filter LogFilter, maxCount int) ([]*Commit, error) {
+	var args []string
+	if maxCount > 0 {
+		args = append(args, fmt.Sprintf("--max-count=%d", maxCount))
+	}
+	commits, err := c.logCommits(append(args, filter.logArgs()...)...)
+	if err != nil && filter.Branch == "" && c.UnbornBranch() != "" {
+		// HEAD has no commits yet
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %w", err)
+	}
+	return commits, nil
+}
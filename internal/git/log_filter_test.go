@@ -0,0 +1,55 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilteredLog(t *testing.T) {
+	dir := newTestRepo(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "docs"), 0755))
+	// Commits by two authors, a year apart
+	commit := func(author, date, file, message string) string {
+		writeTestFile(t, dir, file, message+"\n")
+		gitIn(t, dir, "add", file)
+		cmd := exec.Command("git", "commit", "--quiet", "--author="+author, "-m", message)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "%s", output)
+		return gitIn(t, dir, "rev-parse", "HEAD")
+	}
+	old := commit("Jane Doe <jane@example.com>", "2020-01-01T12:00:00Z", "src/old.txt", "old")
+	recent := commit("Jane Doe <jane@example.com>", "2021-01-01T12:00:00Z", "docs/recent.txt", "recent")
+	other := commit("John Roe <john@example.com>", "2021-01-02T12:00:00Z", "src/other.txt", "other")
+	gitIn(t, dir, "branch", "topic", recent)
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+	hashes := func(filter LogFilter, maxCount int) []string {
+		commits, err := client.FilteredLog(filter, maxCount)
+		require.NoError(t, err)
+		var hashes []string
+		for _, commit := range commits {
+			hashes = append(hashes, commit.Hash)
+		}
+		return hashes
+	}
+
+	assert.Equal(t, []string{recent, old}, hashes(LogFilter{Author: "jane@"}, 0))
+	assert.Equal(t, []string{recent}, hashes(LogFilter{Author: "Jane", Since: "2020-06-01"}, 0))
+	assert.Equal(t, []string{old}, hashes(LogFilter{Until: "2020-06-01", Path: "src"}, 0))
+	assert.Equal(t, []string{other, old}, hashes(LogFilter{Path: "src/"}, 0))
+	assert.Equal(t, []string{recent}, hashes(LogFilter{Branch: "topic"}, 1))
+	assert.Len(t, hashes(LogFilter{}, 0), 4)
+	assert.True(t, LogFilter{}.IsEmpty())
+
+	_, err := client.FilteredLog(LogFilter{Branch: "no-such-branch"}, 0)
+	assert.Error(t, err)
+}
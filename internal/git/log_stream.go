@@ -0,0 +1,112 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// OrderArgs returns the git log arguments listing commits in the order the
+// commit-order option names
+func OrderArgs(order string) []string {
+	switch order {
+	case "topo":
+		return []string{"--topo-order"}
+	case "date":
+		return []string{"--date-order"}
+	case "reverse":
+		return []string{"--reverse"}
+	}
+	return nil
+}
+
+// StreamCommits lists the commits of a log through git log, sending them
+// to out as git lists them, and closes out once they are all sent. Sending
+// blocks while nobody takes the commits, which holds git log back rather
+// than walking the whole history up front. The walk stops once ctx is done.
+func (c *GoGitClient) StreamCommits(ctx context.Context, opts *LogOptions, out chan<- *Commit) error {
+	defer close(out)
+	if c.repo == nil {
+		return fmt.Errorf("repository not opened")
+	}
+
+	args := append([]string{"log", cliLogFormat}, OrderArgs(opts.Order)...)
+	if opts.Follow {
+		args = append(args, "--follow")
+	}
+	if !opts.Filter.IsEmpty() {
+		args = append(args, opts.Filter.logArgs()...)
+	} else {
+		switch {
+		case opts.All:
+			// The commits tig keeps for itself and the stashes are left out
+			args = append(args, "--exclude=refs/tig/*", "--exclude=refs/stash", "--all")
+		case opts.Branch != "":
+			if err := validateRevRange(opts.Branch); err != nil {
+				return err
+			}
+			args = append(args, opts.Branch)
+		default:
+			args = append(args, "HEAD")
+		}
+		args = append(args, "--")
+		if opts.Path != "" {
+			args = append(args, opts.Path)
+		}
+	}
+
+	// Stopping early kills git log
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = c.path
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("git log: %w", err)
+	}
+
+	reader := bufio.NewReader(stdout)
+	for {
+		record, readErr := reader.ReadString('\x1e')
+		commits, err := parseCLICommits(record)
+		if err != nil {
+			cancel()
+			_ = cmd.Wait()
+			return err
+		}
+		for _, commit := range commits {
+			select {
+			case out <- commit:
+			case <-ctx.Done():
+				_ = cmd.Wait()
+				return ctx.Err()
+			}
+		}
+		if readErr != nil {
+			break
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !opts.All && opts.Branch == "" && opts.Filter.Branch == "" && c.UnbornBranch() != "" {
+			// HEAD has no commits yet
+			return nil
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("git log: %s", msg)
+		}
+		return fmt.Errorf("git log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package git
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamCommits(t *testing.T) {
+	dir := newTestRepo(t)
+	for _, message := range []string{"two", "three"} {
+		gitIn(t, dir, "commit", "--quiet", "--allow-empty", "-m", message)
+	}
+	writeTestFile(t, dir, "file.txt", "stashed\n")
+	gitIn(t, dir, "stash", "--quiet")
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	// Stashes are left out of the whole log
+	out := make(chan *Commit)
+	errc := make(chan error, 1)
+	go func() { errc <- client.StreamCommits(context.Background(), &LogOptions{All: true}, out) }()
+	var summaries []string
+	for commit := range out {
+		summaries = append(summaries, commit.Summary)
+	}
+	require.NoError(t, <-errc)
+	assert.Equal(t, []string{"three", "two", "base"}, summaries)
+
+	// The order of the commit-order option is kept
+	out = make(chan *Commit)
+	go func() { errc <- client.StreamCommits(context.Background(), &LogOptions{Order: "reverse"}, out) }()
+	summaries = nil
+	for commit := range out {
+		summaries = append(summaries, commit.Summary)
+	}
+	require.NoError(t, <-errc)
+	assert.Equal(t, []string{"base", "two", "three"}, summaries)
+
+	// Files are followed through renames, filters limit the log
+	gitIn(t, dir, "mv", "file.txt", "renamed.txt")
+	gitIn(t, dir, "commit", "--quiet", "-m", "rename")
+	for _, opts := range []*LogOptions{
+		{Path: "renamed.txt", Follow: true},
+		{Filter: LogFilter{Path: "renamed.txt"}, Path: "ignored.txt"},
+	} {
+		out = make(chan *Commit)
+		go func() { errc <- client.StreamCommits(context.Background(), opts, out) }()
+		summaries = nil
+		for commit := range out {
+			summaries = append(summaries, commit.Summary)
+		}
+		require.NoError(t, <-errc)
+		if opts.Follow {
+			assert.Equal(t, []string{"rename", "base"}, summaries)
+		} else {
+			assert.Equal(t, []string{"rename"}, summaries)
+		}
+	}
+
+	// Nobody taking the commits anymore stops git log
+	ctx, cancel := context.WithCancel(context.Background())
+	out = make(chan *Commit)
+	go func() { errc <- client.StreamCommits(ctx, &LogOptions{}, out) }()
+	assert.Equal(t, "rename", (<-out).Summary)
+	cancel()
+	assert.ErrorIs(t, <-errc, context.Canceled)
+	_, open := <-out
+	assert.False(t, open)
+
+	out = make(chan *Commit, 10)
+	assert.Error(t, client.StreamCommits(context.Background(), &LogOptions{Branch: "missing"}, out))
+	_, open = <-out
+	assert.False(t, open)
+}
@@ -0,0 +1,198 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaintenanceTasks are the tasks Maintain runs, in the order offered
+var MaintenanceTasks = []string{"gc", "repack", "prune"}
+
+// RepoStats describes the object database of a repository, as git
+// count-objects does, along with what git gc --auto makes of it
+type RepoStats struct {
+	LooseObjects  int
+	LooseSize     int64 // Bytes
+	PackedObjects int
+	Packs         int
+	PackSize      int64 // Bytes
+	PrunePackable int   // Loose objects also found in packs
+	Garbage       int   // Files of the object database which are neither
+	GarbageSize   int64 // Bytes
+	GCAuto        int   // Loose objects from which gc --auto runs, 0 if disabled
+	GCAutoPacks   int   // Packs from which gc --auto runs, 0 if disabled
+	GCLog         string
+	LargestBlobs  []BlobSize
+}
+
+// BlobSize is the size of a blob, with a path it is known by
+type BlobSize struct {
+	Hash string
+	Path string
+	Size int64
+}
+
+// NeedsGC returns whether git gc --auto would repack the repository
+func (s *RepoStats) NeedsGC() bool {
+	return (s.GCAuto > 0 && s.LooseObjects > s.GCAuto) || (s.GCAutoPacks > 0 && s.Packs > s.GCAutoPacks)
+}
+
+// RepoStats returns the statistics of the object database, with the given
+// number of largest blobs reachable from any ref
+func (c *GoGitClient) RepoStats(largest int) (*RepoStats, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+
+	output, err := c.runGit(c.path, "count-objects", "-v")
+	if err != nil {
+		return nil, err
+	}
+	stats, err := parseCountObjects(string(output))
+	if err != nil {
+		return nil, err
+	}
+
+	stats.GCAuto = c.configInt("gc.auto", 6700)
+	stats.GCAutoPacks = c.configInt("gc.autoPackLimit", 50)
+	if gitDir, err := c.runGit(c.path, "rev-parse", "--absolute-git-dir"); err == nil {
+		// A failed gc --auto leaves its output there, and won't run again
+		// until it is removed
+		if data, err := os.ReadFile(filepath.Join(strings.TrimSpace(string(gitDir)), "gc.log")); err == nil {
+			stats.GCLog = strings.TrimSpace(string(data))
+		}
+	}
+
+	if largest > 0 {
+		if stats.LargestBlobs, err = c.largestBlobs(context.Background(), largest); err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
+// parseCountObjects parses the output of git count-objects -v, whose sizes
+// are in KiB
+func parseCountObjects(output string) (*RepoStats, error) {
+	stats := &RepoStats{}
+	fields := map[string]*int{
+		"count":          &stats.LooseObjects,
+		"in-pack":        &stats.PackedObjects,
+		"packs":          &stats.Packs,
+		"prune-packable": &stats.PrunePackable,
+		"garbage":        &stats.Garbage,
+	}
+	sizes := map[string]*int64{
+		"size":         &stats.LooseSize,
+		"size-pack":    &stats.PackSize,
+		"size-garbage": &stats.GarbageSize,
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return nil, fmt.Errorf("invalid count-objects line: %q", line)
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid count-objects line: %q", line)
+		}
+		if field, ok := fields[key]; ok {
+			*field = int(n)
+		} else if size, ok := sizes[key]; ok {
+			*size = n * 1024
+		}
+	}
+	return stats, nil
+}
+
+// configInt returns an integer setting of git config, def when unset or
+// invalid
+func (c *GoGitClient) configInt(key string, def int) int {
+	output, err := c.runGit(c.path, "config", "--int", "--get", key)
+	if err != nil {
+		return def
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// largestBlobs returns the n largest blobs reachable from any ref, largest
+// first
+func (c *GoGitClient) largestBlobs(ctx context.Context, n int) ([]BlobSize, error) {
+	list := exec.CommandContext(ctx, "git", "rev-list", "--objects", "--all")
+	list.Dir = c.path
+	objects, err := list.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git rev-list: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "cat-file", "--batch-check=%(objecttype) %(objectname) %(objectsize) %(rest)")
+	cmd.Dir = c.path
+	cmd.Stdin = bytes.NewReader(objects)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file: %w", err)
+	}
+
+	var blobs []BlobSize
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), " ", 4)
+		if len(fields) < 3 || fields[0] != "blob" {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		blob := BlobSize{Hash: fields[1], Size: size}
+		if len(fields) == 4 {
+			blob.Path = fields[3]
+		}
+		blobs = append(blobs, blob)
+	}
+
+	sort.SliceStable(blobs, func(i, j int) bool { return blobs[i].Size > blobs[j].Size })
+	if len(blobs) > n {
+		blobs = blobs[:n]
+	}
+	return blobs, nil
+}
+
+// Maintain runs one of MaintenanceTasks, passing the progress lines git
+// reports to progress as they come. Unreachable objects are only pruned
+// once older than two weeks, as git gc does, so that objects of operations
+// in progress are kept. Cancelling the context stops git.
+func (c *GoGitClient) Maintain(ctx context.Context, task string, progress func(line string)) error {
+	if c.repo == nil {
+		return fmt.Errorf("repository not opened")
+	}
+
+	var args []string
+	switch task {
+	case "gc":
+		args = []string{"gc"}
+	case "repack":
+		args = []string{"repack", "-a", "-d"}
+	case "prune":
+		args = []string{"prune", "--progress", "--expire=2.weeks.ago"}
+	default:
+		return fmt.Errorf("unknown maintenance task: %s", task)
+	}
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = c.path
+	return runWithProgress(ctx, cmd, progress)
+}
@@ -0,0 +1,82 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCountObjects(t *testing.T) {
+	stats, err := parseCountObjects("count: 12\nsize: 48\nin-pack: 300\npacks: 2\nsize-pack: 1024\n" +
+		"prune-packable: 3\ngarbage: 1\nsize-garbage: 4\n")
+	require.NoError(t, err)
+	assert.Equal(t, &RepoStats{
+		LooseObjects:  12,
+		LooseSize:     48 * 1024,
+		PackedObjects: 300,
+		Packs:         2,
+		PackSize:      1024 * 1024,
+		PrunePackable: 3,
+		Garbage:       1,
+		GarbageSize:   4 * 1024,
+	}, stats)
+
+	_, err = parseCountObjects("count twelve\n")
+	assert.Error(t, err)
+}
+
+func TestRepoStatsNeedsGC(t *testing.T) {
+	assert.False(t, (&RepoStats{LooseObjects: 10, GCAuto: 6700, GCAutoPacks: 50}).NeedsGC())
+	assert.True(t, (&RepoStats{LooseObjects: 7000, GCAuto: 6700, GCAutoPacks: 50}).NeedsGC())
+	assert.True(t, (&RepoStats{Packs: 51, GCAuto: 6700, GCAutoPacks: 50}).NeedsGC())
+	assert.False(t, (&RepoStats{LooseObjects: 7000, Packs: 51}).NeedsGC())
+}
+
+func TestRepoStatsAndMaintain(t *testing.T) {
+	dir := newTestRepo(t)
+	writeTestFile(t, dir, "big.bin", strings.Repeat("large blob\n", 1000))
+	gitIn(t, dir, "add", "big.bin")
+	gitIn(t, dir, "commit", "--quiet", "-m", "big")
+	gitIn(t, dir, "config", "gc.auto", "2")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	stats, err := client.RepoStats(1)
+	require.NoError(t, err)
+	assert.Positive(t, stats.LooseObjects)
+	assert.Zero(t, stats.Packs)
+	assert.Equal(t, 2, stats.GCAuto)
+	assert.Equal(t, 50, stats.GCAutoPacks)
+	assert.True(t, stats.NeedsGC())
+	require.Len(t, stats.LargestBlobs, 1)
+	assert.Equal(t, "big.bin", stats.LargestBlobs[0].Path)
+	assert.Equal(t, int64(11000), stats.LargestBlobs[0].Size)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "gc.log"), []byte("warning: too many loose objects\n"), 0644))
+	stats, err = client.RepoStats(0)
+	require.NoError(t, err)
+	assert.Equal(t, "warning: too many loose objects", stats.GCLog)
+	assert.Empty(t, stats.LargestBlobs)
+	require.NoError(t, os.Remove(filepath.Join(dir, ".git", "gc.log")))
+
+	require.NoError(t, client.Maintain(context.Background(), "repack", func(string) {}))
+	stats, err = client.RepoStats(0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Packs)
+	assert.Positive(t, stats.PackedObjects)
+	assert.Equal(t, stats.LooseObjects, stats.PrunePackable)
+
+	require.NoError(t, client.Maintain(context.Background(), "gc", func(string) {}))
+	stats, err = client.RepoStats(0)
+	require.NoError(t, err)
+	assert.Zero(t, stats.LooseObjects)
+	assert.False(t, stats.NeedsGC())
+
+	assert.Error(t, client.Maintain(context.Background(), "fsck", func(string) {}))
+}
@@ -0,0 +1,83 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MergeBase returns the full hash of the best common ancestor of two
+// revisions
+func (c *GoGitClient) MergeBase(a, b string) (string, error) {
+	if c.repo == nil {
+		return "", fmt.Errorf("repository not opened")
+	}
+	for _, rev := range []string{a, b} {
+		if err := validateRevRange(rev); err != nil {
+			return "", err
+		}
+	}
+
+	output, err := c.runGit(c.path, "merge-base", a, b)
+	if err != nil {
+		if len(output) == 0 && strings.HasSuffix(err.Error(), "exit status 1") {
+			return "", fmt.Errorf("%s and %s have no common ancestor", a, b)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// BranchTopology tells where a branch stands against another, such as the
+// mainline
+type BranchTopology struct {
+	MergeBase string // Empty when the branches have no common ancestor
+	Ahead     int    // Commits of the branch the other doesn't have
+	Behind    int    // Commits of the other branch the branch doesn't have
+	Tag       string // Closest tag the merge base descends from, empty when none
+}
+
+// Topology returns where a branch stands against base, from their merge
+// base: how many commits each has that the other doesn't, and which tag
+// the branch is based on
+func (c *GoGitClient) Topology(branch, base string) (*BranchTopology, error) {
+	ahead, behind, err := c.AheadBehind(branch, base)
+	if err != nil {
+		return nil, err
+	}
+	topology := &BranchTopology{Ahead: ahead, Behind: behind}
+
+	mergeBase, err := c.MergeBase(branch, base)
+	if err != nil {
+		// Unrelated histories share nothing to be based on
+		return topology, nil
+	}
+	topology.MergeBase = mergeBase
+	if tag, err := c.runGit(c.path, "describe", "--tags", "--abbrev=0", mergeBase); err == nil {
+		topology.Tag = strings.TrimSpace(string(tag))
+	}
+	return topology, nil
+}
+
+// DefaultBranch returns the local branch others branch off from: the one
+// origin/HEAD names, or else init.defaultBranch, main or master, whichever
+// exists first. It is empty when there is none.
+func (c *GoGitClient) DefaultBranch() string {
+	if c.repo == nil {
+		return ""
+	}
+	candidates := []string{}
+	if remoteHead, err := c.runGit(c.path, "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		candidates = append(candidates, strings.TrimPrefix(strings.TrimSpace(string(remoteHead)), "origin/"))
+	}
+	if name := c.ConfigValue("init.defaultBranch"); name != "" {
+		candidates = append(candidates, name)
+	}
+	candidates = append(candidates, "main", "master")
+
+	for _, name := range candidates {
+		if _, err := c.runGit(c.path, "rev-parse", "--verify", "--quiet", "refs/heads/"+name); err == nil {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeBase(t *testing.T) {
+	dir := newTestRepo(t)
+	base := gitIn(t, dir, "rev-parse", "HEAD")
+
+	gitIn(t, dir, "checkout", "--quiet", "-b", "topic")
+	writeTestFile(t, dir, "topic.txt", "topic\n")
+	gitIn(t, dir, "add", "topic.txt")
+	gitIn(t, dir, "commit", "--quiet", "-m", "topic")
+
+	gitIn(t, dir, "checkout", "--quiet", "main")
+	writeTestFile(t, dir, "main.txt", "main\n")
+	gitIn(t, dir, "add", "main.txt")
+	gitIn(t, dir, "commit", "--quiet", "-m", "main")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	hash, err := client.MergeBase("main", "topic")
+	assert.NoError(t, err)
+	assert.Equal(t, base, hash)
+
+	// Unrelated histories have no merge base
+	gitIn(t, dir, "checkout", "--quiet", "--orphan", "unrelated")
+	gitIn(t, dir, "commit", "--quiet", "-m", "unrelated")
+	_, err = client.MergeBase("main", "unrelated")
+	assert.EqualError(t, err, "main and unrelated have no common ancestor")
+
+	_, err = client.MergeBase("main", "--all")
+	assert.Error(t, err)
+	_, err = client.MergeBase("main", "no-such-branch")
+	assert.Error(t, err)
+}
+
+func TestTopology(t *testing.T) {
+	dir := newTestRepo(t)
+	gitIn(t, dir, "tag", "v1.0")
+	gitIn(t, dir, "checkout", "--quiet", "-b", "topic")
+	for _, name := range []string{"a.txt", "b.txt"} {
+		writeTestFile(t, dir, name, name+"\n")
+		gitIn(t, dir, "add", name)
+		gitIn(t, dir, "commit", "--quiet", "-m", name)
+	}
+	gitIn(t, dir, "checkout", "--quiet", "main")
+	writeTestFile(t, dir, "main.txt", "main\n")
+	gitIn(t, dir, "add", "main.txt")
+	gitIn(t, dir, "commit", "--quiet", "-m", "main")
+	gitIn(t, dir, "tag", "v1.1")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+	assert.Equal(t, "main", client.DefaultBranch())
+
+	topology, err := client.Topology("topic", "main")
+	require.NoError(t, err)
+	assert.Equal(t, &BranchTopology{MergeBase: gitIn(t, dir, "rev-parse", "v1.0"), Ahead: 2, Behind: 1, Tag: "v1.0"}, topology)
+
+	// Unrelated histories have no merge base to be based on
+	gitIn(t, dir, "checkout", "--quiet", "--orphan", "unrelated")
+	gitIn(t, dir, "commit", "--quiet", "-m", "unrelated")
+	topology, err = client.Topology("unrelated", "main")
+	require.NoError(t, err)
+	assert.Equal(t, &BranchTopology{Ahead: 1, Behind: 2}, topology)
+
+	_, err = client.Topology("--all", "main")
+	assert.Error(t, err)
+
+	// The default branch is the one the remote names
+	gitIn(t, dir, "branch", "-m", "main", "trunk")
+	assert.Empty(t, client.DefaultBranch())
+	gitIn(t, dir, "update-ref", "refs/remotes/origin/trunk", "trunk")
+	gitIn(t, dir, "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/trunk")
+	assert.Equal(t, "trunk", client.DefaultBranch())
+}
@@ -0,0 +1,282 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+)
+
+// Object formats, the hash algorithms naming the objects of a repository
+const (
+	ObjectFormatSHA1   = "sha1"
+	ObjectFormatSHA256 = "sha256"
+)
+
+// HashLength returns the number of hex digits of the full hashes of an
+// object format
+func HashLength(format string) int {
+	if format == ObjectFormatSHA256 {
+		return 64
+	}
+	return 40
+}
+
+// detectObjectFormat returns the object format a repository was created
+// with, which its config records from the version of the format up
+func detectObjectFormat(repo *git.Repository) string {
+	cfg, err := repo.Config()
+	if err != nil {
+		return ObjectFormatSHA1
+	}
+	if format := strings.ToLower(cfg.Raw.Section("extensions").Option("objectformat")); format != "" {
+		return format
+	}
+	return ObjectFormatSHA1
+}
+
+// ObjectFormat returns the object format of the open repository
+func (c *GoGitClient) ObjectFormat() string {
+	if c.objectFormat == "" {
+		return ObjectFormatSHA1
+	}
+	return c.objectFormat
+}
+
+// cliBackend returns whether objects are read with git rather than go-git,
+// which only knows SHA-1 and would truncate other hashes
+func (c *GoGitClient) cliBackend() bool {
+	return c.ObjectFormat() != ObjectFormatSHA1
+}
+
+// cliHead returns HEAD as git sees it, named HEAD when detached
+func (c *GoGitClient) cliHead() (*Ref, error) {
+	hash, err := c.runGit(c.path, "rev-parse", "--verify", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	name, err := c.runGit(c.path, "rev-parse", "--symbolic-full-name", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	return &Ref{
+		Name: strings.TrimSpace(string(name)),
+		Type: RefTypeHEAD,
+		Hash: strings.TrimSpace(string(hash)),
+	}, nil
+}
+
+// cliRefs returns the refs under a prefix such as refs/heads/, as the given
+// type
+func (c *GoGitClient) cliRefs(prefix string, refType RefType) ([]*Ref, error) {
+	output, err := c.runGit(c.path, "for-each-ref", "--format=%(refname)%00%(objectname)", prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []*Ref
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		name, hash, ok := strings.Cut(line, "\x00")
+		if !ok {
+			continue
+		}
+		refs = append(refs, &Ref{Name: name, Type: refType, Hash: hash})
+	}
+	return refs, nil
+}
+
+// cliLogFormat writes the fields of commits models are made of, each commit
+// ending with a record separator as messages span lines
+const cliLogFormat = "--format=%H%x00%P%x00%T%x00%an%x00%ae%x00%at%x00%cn%x00%ce%x00%ct%x00%B%x1e"
+
+// cliCommits returns the commits git log lists with the given arguments
+func (c *GoGitClient) cliCommits(args ...string) ([]*Commit, error) {
+	output, err := c.runGit(c.path, append([]string{"log", cliLogFormat}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	return parseCLICommits(string(output))
+}
+
+// parseCLICommits parses the commits written by git log using cliLogFormat
+func parseCLICommits(output string) ([]*Commit, error) {
+	var commits []*Commit
+	for _, record := range strings.Split(output, "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+		fields := strings.SplitN(record, "\x00", 10)
+		if len(fields) < 10 {
+			return nil, fmt.Errorf("invalid log record: %q", record)
+		}
+
+		author, err := cliSignature(fields[3], fields[4], fields[5])
+		if err != nil {
+			return nil, err
+		}
+		committer, err := cliSignature(fields[6], fields[7], fields[8])
+		if err != nil {
+			return nil, err
+		}
+		message := fields[9]
+		summary, body := splitMessage(message)
+		_, trailers := SplitTrailers(message)
+		commits = append(commits, &Commit{
+			Hash:      fields[0],
+			Parents:   strings.Fields(fields[1]),
+			Tree:      fields[2],
+			Author:    author,
+			Committer: committer,
+			Message:   message,
+			Summary:   summary,
+			Body:      body,
+			Stats:     &DiffStats{},
+			Trailers:  trailers,
+		})
+	}
+	return commits, nil
+}
+
+// cliSignature makes a signature of a name, email and Unix time
+func cliSignature(name, email, seconds string) (Signature, error) {
+	n, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return Signature{}, fmt.Errorf("invalid commit time %q", seconds)
+	}
+	return Signature{Name: name, Email: email, Time: time.Unix(n, 0)}, nil
+}
+
+// cliLog returns the commits GetCommits does, listed by git
+func (c *GoGitClient) cliLog(opts *LogOptions) ([]*Commit, error) {
+	args := []string{}
+	if opts.MaxCount > 0 {
+		args = append(args, "--max-count="+strconv.Itoa(opts.MaxCount))
+	}
+	if opts.Skip > 0 {
+		args = append(args, "--skip="+strconv.Itoa(opts.Skip))
+	}
+	rev := "HEAD"
+	if opts.Branch != "" {
+		rev = opts.Branch
+	}
+	args = append(args, rev, "--")
+	if opts.Path != "" {
+		args = append(args, opts.Path)
+	}
+
+	commits, err := c.cliCommits(args...)
+	if err != nil {
+		if opts.Branch == "" && c.UnbornBranch() != "" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get commits: %w", err)
+	}
+	if opts.Reverse {
+		for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+			commits[i], commits[j] = commits[j], commits[i]
+		}
+	}
+	return commits, nil
+}
+
+// cliStatus returns the status of the worktree as git status tells it
+func (c *GoGitClient) cliStatus() (*Status, error) {
+	output, err := c.runGit(c.path, "status", "--porcelain", "-z", "--no-renames", "--untracked-files=all")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+
+	result := &Status{}
+	if head, err := c.runGit(c.path, "symbolic-ref", "--quiet", "--short", "HEAD"); err == nil {
+		result.Branch = strings.TrimSpace(string(head))
+	} else {
+		result.Branch = "HEAD"
+	}
+	result.NoCommits = c.UnbornBranch() != ""
+	c.setUpstream(result)
+
+	for _, entry := range strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00") {
+		if len(entry) < 4 {
+			continue
+		}
+		x, y := entry[0], entry[1]
+		// Unmerged paths are marked both added or deleted, or with a U
+		if x == 'U' || y == 'U' || (x == y && (x == 'A' || x == 'D')) {
+			x, y = 'U', 'U'
+		}
+		result.add(entry[3:], x, y)
+	}
+	return result, nil
+}
+
+// cliFiles returns what GetFiles does, read from the index by git
+func (c *GoGitClient) cliFiles(path string) ([]*File, error) {
+	prefix := strings.Trim(path, "/")
+	args := []string{"ls-files", "--stage", "-z", "--full-name"}
+	if prefix != "" {
+		args = append(args, "--", prefix+"/")
+		prefix += "/"
+	}
+	output, err := c.runGit(c.path, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	files := []*File{}
+	blobs := make(map[string][]*File)
+	for _, entry := range strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00") {
+		// <mode> <hash> <stage>\t<path>
+		info, fullPath, ok := strings.Cut(entry, "\t")
+		if !ok || !strings.HasPrefix(fullPath, prefix) {
+			continue
+		}
+		name := strings.TrimPrefix(fullPath, prefix)
+		isDir := false
+		if i := strings.Index(name, "/"); i >= 0 {
+			name, isDir = name[:i], true
+		}
+		// Conflicted files have an entry for each stage
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		file := &File{Path: name, IsDir: isDir}
+		if isDir {
+			file.Mode = os.ModeDir | 0755
+		} else {
+			fields := strings.Fields(info)
+			if mode, err := filemode.New(fields[0]); err == nil {
+				file.Mode, _ = mode.ToOSFileMode()
+				if mode == filemode.Regular || mode == filemode.Executable {
+					blobs[fields[1]] = append(blobs[fields[1]], file)
+				}
+			}
+			// The index records the size of the file in the worktree
+			if stat, err := os.Lstat(filepath.Join(c.path, fullPath)); err == nil {
+				file.Size = stat.Size()
+			}
+		}
+		files = append(files, file)
+	}
+	return files, c.markBinary(blobs)
+}
+
+// cliCommit commits the index, and the changes to tracked files with All
+func (c *GoGitClient) cliCommit(message string, opts *CommitOptions) error {
+	args := []string{"commit", "--quiet", "--cleanup=verbatim", "--message", message}
+	if opts != nil && opts.All {
+		args = append(args, "--all")
+	}
+	if _, err := c.runGit(c.path, args...); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
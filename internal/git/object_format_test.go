@@ -0,0 +1,122 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFormatTestRepo creates a repository of the given object format with a
+// commit on main, skipping the test where git doesn't know the format
+func newFormatTestRepo(t *testing.T, format string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	cmd := exec.Command("git", "init", "--quiet", "--initial-branch=main", "--object-format="+format, dir)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("git can't create %s repositories: %s", format, output)
+	}
+	gitIn(t, dir, "config", "user.name", "Test User")
+	gitIn(t, dir, "config", "user.email", "test@example.com")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src"), 0755))
+	writeTestFile(t, dir, "file.txt", "base\n")
+	writeTestFile(t, dir, "src/main.go", "package main\n")
+	gitIn(t, dir, "add", ".")
+	gitIn(t, dir, "commit", "--quiet", "-m", "base\n\nThe first commit.\n\nSigned-off-by: Test User <test@example.com>")
+	return dir
+}
+
+func TestObjectFormats(t *testing.T) {
+	for _, format := range []string{ObjectFormatSHA1, ObjectFormatSHA256} {
+		t.Run(format, func(t *testing.T) {
+			dir := newFormatTestRepo(t, format)
+			gitIn(t, dir, "tag", "v1")
+			base := gitIn(t, dir, "rev-parse", "HEAD")
+			require.Len(t, base, HashLength(format))
+
+			client := NewClient()
+			require.NoError(t, client.Open(dir))
+			assert.Equal(t, format, client.ObjectFormat())
+
+			head, err := client.GetHead()
+			require.NoError(t, err)
+			assert.Equal(t, &Ref{Name: "refs/heads/main", Type: RefTypeHEAD, Hash: base}, head)
+			branches, err := client.GetBranches()
+			require.NoError(t, err)
+			assert.Equal(t, []*Ref{{Name: "refs/heads/main", Type: RefTypeBranch, Hash: base}}, branches)
+			tags, err := client.GetTags()
+			require.NoError(t, err)
+			assert.Equal(t, []*Ref{{Name: "refs/tags/v1", Type: RefTypeTag, Hash: base}}, tags)
+
+			commit, err := client.GetCommit(base)
+			require.NoError(t, err)
+			assert.Equal(t, base, commit.Hash)
+			assert.Equal(t, gitIn(t, dir, "rev-parse", "HEAD^{tree}"), commit.Tree)
+			assert.Equal(t, "base", commit.Summary)
+			assert.Equal(t, "The first commit.\n\nSigned-off-by: Test User <test@example.com>", commit.Body)
+			assert.Equal(t, []string{"Test User <test@example.com>"}, commit.Trailer("Signed-off-by"))
+			assert.Equal(t, "Test User", commit.Committer.Name)
+
+			abbrev := client.AbbrevHash(base)
+			assert.Equal(t, base[:len(abbrev)], abbrev)
+			assert.Less(t, len(abbrev), len(base))
+
+			// Changes are listed, staged and committed with either hash
+			writeTestFile(t, dir, "file.txt", "changed\n")
+			writeTestFile(t, dir, "new.txt", "new\n")
+			status, err := client.GetStatus()
+			require.NoError(t, err)
+			assert.Equal(t, "main", status.Branch)
+			require.Len(t, status.Modified, 1)
+			assert.Equal(t, "file.txt", status.Modified[0].Path)
+			require.Len(t, status.Untracked, 1)
+			assert.Equal(t, "new.txt", status.Untracked[0].Path)
+
+			require.NoError(t, client.StageFile("file.txt"))
+			require.NoError(t, client.StageAll())
+			status, err = client.GetStatus()
+			require.NoError(t, err)
+			assert.Len(t, status.Staged, 2)
+			require.NoError(t, client.Commit("second", nil))
+
+			commits, err := client.GetCommits(&LogOptions{MaxCount: 10})
+			require.NoError(t, err)
+			require.Len(t, commits, 2)
+			assert.Equal(t, "second", commits[0].Summary)
+			assert.Equal(t, []string{base}, commits[0].Parents)
+			assert.Len(t, commits[0].Hash, HashLength(format))
+			count, err := client.GetLogCount()
+			require.NoError(t, err)
+			assert.Equal(t, 2, count)
+
+			files, err := client.GetFiles("")
+			require.NoError(t, err)
+			var names []string
+			for _, file := range files {
+				names = append(names, file.Path)
+			}
+			assert.ElementsMatch(t, []string{"file.txt", "new.txt", "src"}, names)
+			files, err = client.GetFiles("src")
+			require.NoError(t, err)
+			require.Len(t, files, 1)
+			assert.Equal(t, "main.go", files[0].Path)
+			assert.Equal(t, int64(len("package main\n")), files[0].Size)
+			assert.Equal(t, os.FileMode(0644), files[0].Mode)
+
+			diff, err := client.GetCommitDiff(commits[0].Hash, &DiffOptions{})
+			require.NoError(t, err)
+			assert.NotEmpty(t, diff.Files)
+			blame, err := client.Blame("HEAD", "file.txt", nil)
+			require.NoError(t, err)
+			require.Len(t, blame, 1)
+			assert.Equal(t, commits[0].Hash, blame[0].Commit)
+		})
+	}
+}
@@ -0,0 +1,441 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultFuzz is how many context lines at either end of a hunk may not
+// match the content it is applied to, as with the fuzz factor of patch
+const DefaultFuzz = 2
+
+// patchLine is a line of content along with its end of line, "" for a last
+// line without one
+type patchLine struct {
+	text string
+	eol  string
+}
+
+// splitPatchLines splits content into lines, telling apart the CRLF and LF
+// ends of line
+func splitPatchLines(content []byte) []patchLine {
+	var lines []patchLine
+	for len(content) > 0 {
+		text, rest, found := bytes.Cut(content, []byte("\n"))
+		line := patchLine{text: string(text)}
+		if found {
+			line.eol = "\n"
+			if strings.HasSuffix(line.text, "\r") {
+				line.text, line.eol = strings.TrimSuffix(line.text, "\r"), "\r\n"
+			}
+		}
+		lines = append(lines, line)
+		content = rest
+	}
+	return lines
+}
+
+// joinPatchLines joins lines back into content
+func joinPatchLines(lines []patchLine) []byte {
+	var b bytes.Buffer
+	for _, line := range lines {
+		b.WriteString(line.text)
+		b.WriteString(line.eol)
+	}
+	return b.Bytes()
+}
+
+// lineEnding returns the end of line most lines of the content use, LF
+// when there is no telling
+func lineEnding(lines []patchLine) string {
+	crlf, lf := 0, 0
+	for _, line := range lines {
+		switch line.eol {
+		case "\r\n":
+			crlf++
+		case "\n":
+			lf++
+		}
+	}
+	if crlf > lf {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// ReverseHunk returns the hunk undoing the changes of a hunk, for it to be
+// unstaged or discarded
+func ReverseHunk(hunk *DiffHunk) *DiffHunk {
+	reversed := &DiffHunk{
+		OldStart: hunk.NewStart,
+		OldLines: hunk.NewLines,
+		NewStart: hunk.OldStart,
+		NewLines: hunk.OldLines,
+		Header:   hunk.Header,
+	}
+	for _, line := range hunk.Lines {
+		r := *line
+		r.OldLine, r.NewLine = line.NewLine, line.OldLine
+		switch line.Type {
+		case DiffLineAddition:
+			r.Type = DiffLineDeletion
+		case DiffLineDeletion:
+			r.Type = DiffLineAddition
+		}
+		reversed.Lines = append(reversed.Lines, &r)
+	}
+	return reversed
+}
+
+// HunkLines returns the part of a hunk changing its lines from first to
+// last alone, indexes of its lines. Lines deleted outside of them are kept
+// as context and lines added outside of them are left out, for the part to
+// apply where the whole hunk would.
+func HunkLines(hunk *DiffHunk, first, last int) (*DiffHunk, error) {
+	if first > last {
+		first, last = last, first
+	}
+	part := &DiffHunk{
+		OldStart: hunk.OldStart,
+		NewStart: hunk.NewStart,
+		Header:   hunk.Header,
+	}
+
+	changed, dropped := false, false
+	for i, line := range hunk.Lines {
+		selected := i >= first && i <= last
+		switch line.Type {
+		case DiffLineContext:
+			part.OldLines++
+			part.NewLines++
+		case DiffLineDeletion:
+			if !selected {
+				context := *line
+				context.Type = DiffLineContext
+				line = &context
+				part.NewLines++
+			}
+			part.OldLines++
+		case DiffLineAddition:
+			if !selected {
+				dropped = true
+				continue
+			}
+			part.NewLines++
+		case DiffLineNoNewline:
+			// Markers follow their line, dropped along with it
+			if dropped {
+				continue
+			}
+		}
+		changed = changed || (selected && line.Type != DiffLineContext && line.Type != DiffLineNoNewline)
+		dropped = false
+		part.Lines = append(part.Lines, line)
+	}
+	if !changed {
+		return nil, fmt.Errorf("no changed lines selected")
+	}
+	return part, nil
+}
+
+// hunkBody returns the lines of a hunk without the no newline markers,
+// and whether the last line of the content it expects and of the content
+// it leaves have no end of line
+func hunkBody(hunk *DiffHunk) (body []*DiffLine, oldNoEOL, newNoEOL bool) {
+	for _, line := range hunk.Lines {
+		if line.Type != DiffLineNoNewline {
+			body = append(body, line)
+			continue
+		}
+		if len(body) > 0 {
+			previous := body[len(body)-1].Type
+			oldNoEOL = oldNoEOL || previous != DiffLineAddition
+			newNoEOL = newNoEOL || previous != DiffLineDeletion
+		}
+	}
+	return body, oldNoEOL, newNoEOL
+}
+
+// hunkSide returns the lines of a hunk body the content has before the
+// hunk is applied, or after with new
+func hunkSide(body []*DiffLine, new bool) []*DiffLine {
+	dropped := DiffLineAddition
+	if new {
+		dropped = DiffLineDeletion
+	}
+	var side []*DiffLine
+	for _, line := range body {
+		if line.Type != dropped {
+			side = append(side, line)
+		}
+	}
+	return side
+}
+
+// contextLines returns how many context lines a hunk body starts and ends
+// with
+func contextLines(body []*DiffLine) (leading, trailing int) {
+	for leading < len(body) && body[leading].Type == DiffLineContext {
+		leading++
+	}
+	for trailing < len(body)-leading && body[len(body)-1-trailing].Type == DiffLineContext {
+		trailing++
+	}
+	return leading, trailing
+}
+
+// matchesAt returns whether lines of a hunk are found at the given line of
+// the content, whatever their end of line
+func matchesAt(lines []patchLine, at int, expected []*DiffLine) bool {
+	if at < 0 || at+len(expected) > len(lines) {
+		return false
+	}
+	for i, line := range expected {
+		if lines[at+i].text != strings.TrimSuffix(line.Content, "\r") {
+			return false
+		}
+	}
+	return true
+}
+
+// findHunk looks for the lines a hunk body expects in the content, from
+// the given line outwards and never before floor. Up to fuzz context lines
+// at either end are left unmatched when it is not found as a whole. It
+// returns the line the matched lines start at and how many context lines
+// were left unmatched at the start and at the end.
+func findHunk(lines []patchLine, body []*DiffLine, expected, floor, fuzz int) (at, skipStart, skipEnd int, found bool) {
+	leading, trailing := contextLines(body)
+	for trim := 0; trim <= fuzz && (trim == 0 || trim <= max(leading, trailing)); trim++ {
+		skipStart, skipEnd = min(trim, leading), min(trim, trailing)
+		old := hunkSide(body[skipStart:len(body)-skipEnd], false)
+		for distance := 0; distance <= len(lines); distance++ {
+			for _, at := range []int{expected + skipStart + distance, expected + skipStart - distance} {
+				if at >= floor && matchesAt(lines, at, old) {
+					return at, skipStart, skipEnd, true
+				}
+				if distance == 0 {
+					break
+				}
+			}
+		}
+	}
+	return 0, 0, 0, false
+}
+
+// sameEOLAt returns whether the lines of a hunk found at the given line of
+// the content end the way the content's lines do, a carriage return ending
+// the lines of CRLF content alone
+func sameEOLAt(lines []patchLine, at int, expected []*DiffLine) bool {
+	for i, line := range expected {
+		if strings.HasSuffix(line.Content, "\r") != (lines[at+i].eol == "\r\n") {
+			return false
+		}
+	}
+	return true
+}
+
+// samePatchLines returns whether two runs of lines are the same, ends of
+// line included
+func samePatchLines(a, b []patchLine) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyHunks applies the hunks of a file to its content, in order, as patch
+// would. A hunk is looked for at the line it starts at, shifted by what
+// the previous hunks added or removed, then at the nearest lines around,
+// then with up to fuzz context lines at either end left unmatched. Ends of
+// line are matched loosely. When the lines a hunk expects end as the
+// content's do, the lines added keep their own end of line, so hunks
+// changing ends of line alone apply. Otherwise they take the end of line
+// most of the content uses, which keeps CRLF files CRLF when the hunks
+// were made without carriage returns. A hunk which leaves the content as
+// it was fails to apply.
+func ApplyHunks(content []byte, hunks []*DiffHunk, fuzz int) ([]byte, error) {
+	lines := splitPatchLines(content)
+	eol := lineEnding(lines)
+
+	// delta is how far the content moved from where the hunks expect it,
+	// and floor the first line the next hunk may change
+	delta, floor := 0, 0
+	for i, hunk := range hunks {
+		body, _, newNoEOL := hunkBody(hunk)
+		start := hunk.OldStart - 1
+		if hunk.OldLines == 0 {
+			// Hunks only adding lines name the line they come after
+			start = hunk.OldStart
+		}
+
+		at, skipStart, skipEnd, found := findHunk(lines, body, start+delta, floor, fuzz)
+		if !found {
+			return nil, fmt.Errorf("hunk %d does not apply: its context is not found around line %d", i+1, hunk.OldStart)
+		}
+
+		// Context lines are kept as the content has them
+		matched := body[skipStart : len(body)-skipEnd]
+		ownEOL := sameEOLAt(lines, at, hunkSide(matched, false))
+		var replacement []patchLine
+		from := at
+		for _, line := range matched {
+			switch line.Type {
+			case DiffLineContext:
+				replacement = append(replacement, lines[from])
+				from++
+			case DiffLineDeletion:
+				from++
+			case DiffLineAddition:
+				added := patchLine{text: strings.TrimSuffix(line.Content, "\r"), eol: eol}
+				if ownEOL {
+					added.eol = "\n"
+					if added.text != line.Content {
+						added.eol = "\r\n"
+					}
+				}
+				replacement = append(replacement, added)
+			}
+		}
+		if from == len(lines) && skipEnd == 0 && len(replacement) > 0 {
+			last := &replacement[len(replacement)-1]
+			if newNoEOL {
+				last.eol = ""
+			} else if last.eol == "" {
+				last.eol = eol
+			}
+		}
+
+		if samePatchLines(replacement, lines[at:from]) {
+			return nil, fmt.Errorf("hunk %d does not apply: it changes nothing around line %d", i+1, hunk.OldStart)
+		}
+
+		delta = at - skipStart - start + len(hunkSide(body, true)) - len(hunkSide(body, false))
+		floor = at + len(replacement)
+		lines = append(lines[:at], append(replacement, lines[from:]...)...)
+	}
+	return joinPatchLines(lines), nil
+}
+
+// ApplyHunkToIndex applies a hunk of the changes of a file to its content
+// in the index, staging the hunk, or unstaging it once reversed. Files
+// missing from the index are added with the hunk alone.
+func (c *GoGitClient) ApplyHunkToIndex(path string, hunk *DiffHunk) error {
+	if c.repo == nil {
+		return fmt.Errorf("repository not opened")
+	}
+
+	entry, err := c.runGit(c.path, "ls-files", "--stage", "-z", "--", path)
+	if err != nil {
+		return err
+	}
+	var content []byte
+	mode := "100644"
+	if info, _, ok := strings.Cut(string(entry), "\t"); ok {
+		fields := strings.Fields(info)
+		if len(fields) < 2 {
+			return fmt.Errorf("malformed index entry of %s", path)
+		}
+		mode = fields[0]
+		if content, err = c.runGit(c.path, "cat-file", "blob", fields[1]); err != nil {
+			return err
+		}
+	} else if stat, err := os.Stat(filepath.Join(c.path, path)); err == nil && stat.Mode()&0111 != 0 {
+		mode = "100755"
+	}
+
+	content, err = ApplyHunks(content, []*DiffHunk{hunk}, DefaultFuzz)
+	if err != nil {
+		return err
+	}
+
+	// The blob goes through the filters git add applies, such as autocrlf
+	cmd := exec.Command("git", "hash-object", "-w", "--stdin", "--path="+path)
+	cmd.Dir = c.path
+	cmd.Stdin = bytes.NewReader(content)
+	blob, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("git hash-object: %w", err)
+	}
+	cacheInfo := fmt.Sprintf("%s,%s,%s", mode, strings.TrimSpace(string(blob)), path)
+	_, err = c.runGit(c.path, "update-index", "--add", "--cacheinfo", cacheInfo)
+	return err
+}
+
+// StageHunk stages a hunk of the unstaged changes of a file
+func (c *GoGitClient) StageHunk(path string, hunk *DiffHunk) error {
+	return c.ApplyHunkToIndex(path, hunk)
+}
+
+// UnstageHunk unstages a hunk of the staged changes of a file
+func (c *GoGitClient) UnstageHunk(path string, hunk *DiffHunk) error {
+	return c.ApplyHunkToIndex(path, ReverseHunk(hunk))
+}
+
+// StageHunkLines stages the changes of a hunk of the unstaged changes of a
+// file from its first to its last line, as HunkLines selects them
+func (c *GoGitClient) StageHunkLines(path string, hunk *DiffHunk, first, last int) error {
+	part, err := HunkLines(hunk, first, last)
+	if err != nil {
+		return err
+	}
+	return c.ApplyHunkToIndex(path, part)
+}
+
+// UnstageHunkLines unstages the changes of a hunk of the staged changes of
+// a file from its first to its last line. The hunk is reversed before the
+// lines are selected, the other changes being in the index already.
+func (c *GoGitClient) UnstageHunkLines(path string, hunk *DiffHunk, first, last int) error {
+	part, err := HunkLines(ReverseHunk(hunk), first, last)
+	if err != nil {
+		return err
+	}
+	return c.ApplyHunkToIndex(path, part)
+}
+
+// ApplyHunkToWorktree applies a hunk to a file of the worktree, such as a
+// hunk of a stash or of a commit, creating the file if it is missing
+func (c *GoGitClient) ApplyHunkToWorktree(path string, hunk *DiffHunk) error {
+	if c.repo == nil {
+		return fmt.Errorf("repository not opened")
+	}
+
+	full := filepath.Join(c.path, path)
+	content, err := os.ReadFile(full)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(full); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	content, err = ApplyHunks(content, []*DiffHunk{hunk}, DefaultFuzz)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(full, content, perm)
+}
+
+// DiscardHunk undoes a hunk of the unstaged changes of a file, keeping the
+// file in the trash first for UndoDiscard to restore it
+func (c *GoGitClient) DiscardHunk(path string, hunk *DiffHunk) error {
+	if c.repo == nil {
+		return fmt.Errorf("repository not opened")
+	}
+	if err := c.trashFile(path); err != nil {
+		return fmt.Errorf("failed to keep the discarded changes: %w", err)
+	}
+	return c.ApplyHunkToWorktree(path, ReverseHunk(hunk))
+}
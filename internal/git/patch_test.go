@@ -0,0 +1,293 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseTestHunks parses the hunks of a file diff given without headers
+func parseTestHunks(t *testing.T, hunks string) []*DiffHunk {
+	t.Helper()
+	diff, err := ParseDiff("diff --git a/file b/file\n--- a/file\n+++ b/file\n" + hunks)
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	return diff.Files[0].Hunks
+}
+
+// numberedLines returns the lines "line 01" to "line n", each ended by eol
+func numberedLines(n int, eol string) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "line %02d%s", i, eol)
+	}
+	return b.String()
+}
+
+func TestApplyHunks(t *testing.T) {
+	content := numberedLines(12, "\n")
+
+	tests := []struct {
+		name     string
+		content  string
+		hunks    string
+		fuzz     int
+		expected string
+		err      string
+	}{
+		{
+			name:     "exact",
+			content:  content,
+			hunks:    "@@ -2,3 +2,3 @@\n line 02\n-line 03\n+three\n line 04\n",
+			expected: strings.Replace(content, "line 03\n", "three\n", 1),
+		},
+		{
+			name:     "several hunks with lines added and removed",
+			content:  content,
+			hunks:    "@@ -1,2 +1,3 @@\n line 01\n+one and a half\n line 02\n@@ -10,3 +11,2 @@\n line 10\n-line 11\n line 12\n",
+			expected: strings.Replace(strings.Replace(content, "line 11\n", "", 1), "line 02\n", "one and a half\nline 02\n", 1),
+		},
+		{
+			name:     "content moved since the diff",
+			content:  "new first\nnew second\n" + content,
+			hunks:    "@@ -6,3 +6,3 @@\n line 06\n-line 07\n+seven\n line 08\n",
+			expected: "new first\nnew second\n" + strings.Replace(content, "line 07\n", "seven\n", 1),
+		},
+		{
+			name:    "context mismatch",
+			content: content,
+			hunks:   "@@ -6,3 +6,3 @@\n line 06\n-line 07\n+seven\n changed\n",
+			err:     "hunk 1 does not apply",
+		},
+		{
+			name:     "context mismatch within fuzz",
+			content:  content,
+			hunks:    "@@ -5,5 +5,5 @@\n line 05\n line 06\n-line 07\n+seven\n changed\n line 09\n",
+			fuzz:     2,
+			expected: strings.Replace(content, "line 07\n", "seven\n", 1),
+		},
+		{
+			name:    "changed lines never fuzzed",
+			content: content,
+			hunks:   "@@ -6,3 +6,3 @@\n line 06\n-line seven\n+seven\n line 08\n",
+			fuzz:    2,
+			err:     "hunk 1 does not apply",
+		},
+		{
+			name:     "adding to an empty file",
+			content:  "",
+			hunks:    "@@ -0,0 +1,2 @@\n+first\n+second\n",
+			expected: "first\nsecond\n",
+		},
+		{
+			name:     "CRLF content with LF hunks",
+			content:  numberedLines(4, "\r\n"),
+			hunks:    "@@ -2,2 +2,3 @@\n line 02\n-line 03\n+three\n+three and a half\n",
+			expected: "line 01\r\nline 02\r\nthree\r\nthree and a half\r\nline 04\r\n",
+		},
+		{
+			name:     "LF content with CRLF hunks",
+			content:  numberedLines(3, "\n"),
+			hunks:    "@@ -1,3 +1,3 @@\n line 01\r\n-line 02\r\n+two\r\n line 03\r\n",
+			expected: "line 01\ntwo\nline 03\n",
+		},
+		{
+			name:     "line ending alone changed",
+			content:  "a\nb\nc\n",
+			hunks:    "@@ -1,3 +1,3 @@\n a\n-b\n+b\r\n c\n",
+			expected: "a\nb\r\nc\n",
+		},
+		{
+			name:    "hunk changing nothing",
+			content: "a\r\nb\r\nc\r\n",
+			hunks:   "@@ -1,3 +1,3 @@\n a\n-b\n+b\r\n c\n",
+			err:     "hunk 1 does not apply: it changes nothing",
+		},
+		{
+			name:     "last line losing its end of line",
+			content:  "a\nb\n",
+			hunks:    "@@ -1,2 +1,2 @@\n a\n-b\n+b\n\\ No newline at end of file\n",
+			expected: "a\nb",
+		},
+		{
+			name:     "last line getting an end of line",
+			content:  "a\r\nb",
+			hunks:    "@@ -1,2 +1,3 @@\n a\n-b\n\\ No newline at end of file\n+b\n+c\n",
+			expected: "a\r\nb\r\nc\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ApplyHunks([]byte(tt.content), parseTestHunks(t, tt.hunks), tt.fuzz)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(result))
+		})
+	}
+}
+
+func TestReverseHunk(t *testing.T) {
+	content := numberedLines(6, "\n")
+	hunks := parseTestHunks(t, "@@ -2,3 +2,4 @@\n line 02\n-line 03\n+three\n+three and a half\n line 04\n")
+
+	changed, err := ApplyHunks([]byte(content), hunks, 0)
+	require.NoError(t, err)
+	reversed := ReverseHunk(hunks[0])
+	assert.Equal(t, 2, reversed.OldStart)
+	assert.Equal(t, 4, reversed.OldLines)
+	back, err := ApplyHunks(changed, []*DiffHunk{reversed}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(back))
+
+	// The end of line markers keep following their lines
+	hunks = parseTestHunks(t, "@@ -1 +1 @@\n-a\n+b\n\\ No newline at end of file\n")
+	back, err = ApplyHunks([]byte("b"), []*DiffHunk{ReverseHunk(hunks[0])}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "a\n", string(back))
+}
+
+func TestHunkStagingAndDiscarding(t *testing.T) {
+	dir := newTestRepo(t)
+	writeTestFile(t, dir, "file.txt", numberedLines(20, "\n"))
+	gitIn(t, dir, "commit", "--quiet", "-am", "lines")
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	changed := strings.Replace(strings.Replace(numberedLines(20, "\n"), "line 02\n", "two\n", 1), "line 18\n", "eighteen\n", 1)
+	writeTestFile(t, dir, "file.txt", changed)
+	diff, err := client.GetDiff("file.txt")
+	require.NoError(t, err)
+	require.Len(t, diff.Files[0].Hunks, 2)
+	first, second := diff.Files[0].Hunks[0], diff.Files[0].Hunks[1]
+
+	// Staging the second hunk leaves the first one unstaged
+	require.NoError(t, client.ApplyHunkToIndex("file.txt", second))
+	assert.Contains(t, gitIn(t, dir, "diff", "--cached"), "+eighteen")
+	assert.NotContains(t, gitIn(t, dir, "diff", "--cached"), "+two")
+	assert.Contains(t, gitIn(t, dir, "diff"), "+two")
+
+	// and unstaging it leaves the index as it was
+	require.NoError(t, client.ApplyHunkToIndex("file.txt", ReverseHunk(second)))
+	assert.Empty(t, gitIn(t, dir, "diff", "--cached"))
+
+	// Discarding the first hunk keeps the file in the trash
+	require.NoError(t, client.DiscardHunk("file.txt", first))
+	data, err := os.ReadFile(filepath.Join(dir, "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(numberedLines(20, "\n"), "line 18\n", "eighteen\n", 1), string(data))
+	gitIn(t, dir, "checkout", "--", "file.txt")
+	_, err = client.UndoDiscard()
+	require.NoError(t, err)
+	data, err = os.ReadFile(filepath.Join(dir, "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, changed, string(data))
+
+	// Hunks of other changes, such as stashes, apply to the worktree
+	gitIn(t, dir, "stash", "--quiet")
+	stash, err := client.GetRangeDiff("stash@{0}^..stash@{0}")
+	require.NoError(t, err)
+	require.NoError(t, client.ApplyHunkToWorktree("file.txt", stash.Files[0].Hunks[1]))
+	assert.Contains(t, gitIn(t, dir, "diff"), "+eighteen")
+	assert.NotContains(t, gitIn(t, dir, "diff"), "+two")
+
+	// and create the files they add
+	require.NoError(t, client.ApplyHunkToWorktree("new/file.txt", parseTestHunks(t, "@@ -0,0 +1 @@\n+new\n")[0]))
+	data, err = os.ReadFile(filepath.Join(dir, "new/file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "new\n", string(data))
+}
+
+func TestHunkStagingFilters(t *testing.T) {
+	dir := newTestRepo(t)
+	writeTestFile(t, dir, ".gitattributes", "*.txt text\n")
+	writeTestFile(t, dir, "file.txt", "a\nb\n")
+	gitIn(t, dir, "add", ".")
+	gitIn(t, dir, "commit", "--quiet", "-m", "text")
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	// The staged blob is normalized as git add would store it
+	hunk := parseTestHunks(t, "@@ -1,2 +1,3 @@\n a\n+c\r\n b\n")[0]
+	require.NoError(t, client.ApplyHunkToIndex("file.txt", hunk))
+	assert.Equal(t, "a\nc\nb", gitIn(t, dir, "show", ":file.txt"))
+}
+
+func TestHunkLines(t *testing.T) {
+	content := numberedLines(5, "\n")
+	hunk := parseTestHunks(t, "@@ -2,3 +2,3 @@\n line 02\n-line 03\n-line 04\n+three\n+four\n")[0]
+
+	tests := []struct {
+		name        string
+		first, last int
+		expected    string
+		err         string
+	}{
+		{name: "one deletion", first: 1, last: 1, expected: "line 01\nline 02\nline 04\nline 05\n"},
+		{name: "one addition", first: 4, last: 4, expected: "line 01\nline 02\nline 03\nline 04\nfour\nline 05\n"},
+		{name: "range given backwards", first: 3, last: 2, expected: "line 01\nline 02\nline 03\nthree\nline 05\n"},
+		{name: "whole hunk", first: 0, last: 4, expected: "line 01\nline 02\nthree\nfour\nline 05\n"},
+		{name: "context alone", first: 0, last: 0, err: "no changed lines selected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			part, err := HunkLines(hunk, tt.first, tt.last)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			result, err := ApplyHunks([]byte(content), []*DiffHunk{part}, 0)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(result))
+		})
+	}
+}
+
+func TestLineStaging(t *testing.T) {
+	dir := newTestRepo(t)
+	writeTestFile(t, dir, "file.txt", numberedLines(5, "\n"))
+	gitIn(t, dir, "commit", "--quiet", "-am", "lines")
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	writeTestFile(t, dir, "file.txt", strings.Replace(numberedLines(5, "\n"), "line 03\n", "three\nthree and a half\n", 1))
+	diff, err := client.GetDiff("file.txt")
+	require.NoError(t, err)
+	hunk := diff.Files[0].Hunks[0]
+	first := slices.IndexFunc(hunk.Lines, func(l *DiffLine) bool { return l.Content == "three and a half" })
+
+	// Only the second line added is staged
+	require.NoError(t, client.StageHunkLines("file.txt", hunk, first, first))
+	staged := gitIn(t, dir, "diff", "--cached")
+	assert.Contains(t, staged, "+three and a half")
+	assert.NotContains(t, staged, "-line 03")
+
+	// and unstaged from the staged changes
+	diff, err = client.GetStagedDiff("file.txt")
+	require.NoError(t, err)
+	hunk = diff.Files[0].Hunks[0]
+	first = slices.IndexFunc(hunk.Lines, func(l *DiffLine) bool { return l.Type == DiffLineAddition })
+	require.NoError(t, client.UnstageHunkLines("file.txt", hunk, first, first))
+	assert.Empty(t, gitIn(t, dir, "diff", "--cached"))
+
+	// Whole hunks go back and forth as well
+	diff, err = client.GetDiff("file.txt")
+	require.NoError(t, err)
+	require.NoError(t, client.StageHunk("file.txt", diff.Files[0].Hunks[0]))
+	assert.Empty(t, gitIn(t, dir, "diff"))
+	diff, err = client.GetStagedDiff("file.txt")
+	require.NoError(t, err)
+	require.NoError(t, client.UnstageHunk("file.txt", diff.Files[0].Hunks[0]))
+	assert.Empty(t, gitIn(t, dir, "diff", "--cached"))
+}
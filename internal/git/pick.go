@@ -0,0 +1,62 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PickResult describes the outcome of cherry-picking or reverting a commit
+// on the current branch
+type PickResult struct {
+	Commit    string   // Full hash of the new commit on success
+	Conflicts []string // Conflicting paths if the change did not apply
+}
+
+// CherryPick applies the changes of a commit to the current branch as a
+// new commit, noting which one it was picked from. Conflicts abort it,
+// leaving the branch as it was, and are reported in the result rather
+// than as an error.
+func (c *GoGitClient) CherryPick(hash string) (*PickResult, error) {
+	return c.pick("cherry-pick", hash, "-x")
+}
+
+// Revert undoes the changes of a commit on the current branch with a new
+// commit. Conflicts abort it as with CherryPick.
+func (c *GoGitClient) Revert(hash string) (*PickResult, error) {
+	return c.pick("revert", hash, "--no-edit")
+}
+
+// pick runs git cherry-pick or revert on a commit, aborting it when it
+// stops half way
+func (c *GoGitClient) pick(command, hash string, flags ...string) (*PickResult, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+	if err := validateRevRange(hash); err != nil {
+		return nil, err
+	}
+	// One in progress is the user's to finish, not to be aborted here
+	for _, head := range []string{"CHERRY_PICK_HEAD", "REVERT_HEAD"} {
+		if _, err := c.runGit(c.path, "rev-parse", "--verify", "--quiet", head); err == nil {
+			return nil, fmt.Errorf("a cherry-pick or revert is already in progress")
+		}
+	}
+
+	args := append(append([]string{command}, flags...), hash)
+	if _, err := c.runGit(c.path, args...); err != nil {
+		conflicts, _ := c.runGit(c.path, "diff", "--name-only", "--diff-filter=U")
+		// Nothing is left to abort when it refused to start
+		c.runGit(c.path, command, "--abort")
+		result := &PickResult{Conflicts: strings.Fields(string(conflicts))}
+		if len(result.Conflicts) == 0 {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	head, err := c.runGit(c.path, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	return &PickResult{Commit: strings.TrimSpace(string(head))}, nil
+}
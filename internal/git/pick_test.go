@@ -0,0 +1,59 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCherryPickAndRevert(t *testing.T) {
+	dir := newTestRepo(t)
+	gitIn(t, dir, "checkout", "--quiet", "-b", "topic")
+	writeTestFile(t, dir, "topic.txt", "topic\n")
+	gitIn(t, dir, "add", "topic.txt")
+	gitIn(t, dir, "commit", "--quiet", "-m", "topic")
+	topic := gitIn(t, dir, "rev-parse", "HEAD")
+	writeTestFile(t, dir, "file.txt", "topic\n")
+	gitIn(t, dir, "commit", "--quiet", "-am", "change file")
+	change := gitIn(t, dir, "rev-parse", "HEAD")
+	gitIn(t, dir, "checkout", "--quiet", "main")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	result, err := client.CherryPick(topic)
+	require.NoError(t, err)
+	assert.Equal(t, gitIn(t, dir, "rev-parse", "HEAD"), result.Commit)
+	assert.Contains(t, gitIn(t, dir, "log", "-1", "--format=%B"), "(cherry picked from commit "+topic+")")
+	assert.FileExists(t, filepath.Join(dir, "topic.txt"))
+
+	result, err = client.Revert("HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, gitIn(t, dir, "rev-parse", "HEAD"), result.Commit)
+	assert.Contains(t, gitIn(t, dir, "log", "-1", "--format=%s"), `Revert "topic"`)
+	assert.NoFileExists(t, filepath.Join(dir, "topic.txt"))
+
+	// Conflicts abort it, leaving the branch as it was
+	writeTestFile(t, dir, "file.txt", "main\n")
+	gitIn(t, dir, "commit", "--quiet", "-am", "main change")
+	head := gitIn(t, dir, "rev-parse", "HEAD")
+	result, err = client.CherryPick(change)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"file.txt"}, result.Conflicts)
+	assert.Equal(t, head, gitIn(t, dir, "rev-parse", "HEAD"))
+	assert.Empty(t, gitIn(t, dir, "status", "--porcelain"))
+	_, err = os.Stat(filepath.Join(dir, ".git", "CHERRY_PICK_HEAD"))
+	assert.True(t, os.IsNotExist(err))
+
+	// As does picking a change the branch already has
+	_, err = client.CherryPick(head)
+	assert.Error(t, err)
+	assert.Equal(t, head, gitIn(t, dir, "rev-parse", "HEAD"))
+	assert.Empty(t, gitIn(t, dir, "status", "--porcelain"))
+
+	_, err = client.Revert("--all")
+	assert.Error(t, err)
+}
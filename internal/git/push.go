@@ -0,0 +1,127 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// PushRemote returns the remote a branch is pushed to the way git push
+// picks it: the branch's pushRemote, remote.pushDefault, the branch's
+// remote, and otherwise origin or the only remote. An empty branch, as
+// for tags, skips the branch settings.
+func (c *GoGitClient) PushRemote(branch string) (string, error) {
+	if c.repo == nil {
+		return "", fmt.Errorf("repository not opened")
+	}
+
+	keys := []string{"remote.pushDefault"}
+	if branch != "" {
+		keys = []string{"branch." + branch + ".pushRemote", "remote.pushDefault", "branch." + branch + ".remote"}
+	}
+	for _, key := range keys {
+		// Unset keys make git config fail
+		output, err := c.runGit(c.path, "config", "--get", key)
+		if remote := strings.TrimSpace(string(output)); err == nil && remote != "" && remote != "." {
+			return remote, nil
+		}
+	}
+
+	remotes, err := c.GetRemotes()
+	if err != nil {
+		return "", err
+	}
+	for _, remote := range remotes {
+		if remote.Name == "origin" {
+			return remote.Name, nil
+		}
+	}
+	if len(remotes) == 1 {
+		return remotes[0].Name, nil
+	}
+	if len(remotes) == 0 {
+		return "", fmt.Errorf("no remote to push to")
+	}
+	return "", fmt.Errorf("no default remote to push to, name one")
+}
+
+// Push pushes refspecs to a remote, passing the progress lines git reports
+// to progress as they come. A refspec of the form :ref deletes the ref on
+// the remote. Cancelling the context stops git.
+func (c *GoGitClient) Push(ctx context.Context, remote string, refspecs []string, progress func(line string)) error {
+	if c.repo == nil {
+		return fmt.Errorf("repository not opened")
+	}
+	if remote == "" || strings.HasPrefix(remote, "-") {
+		return fmt.Errorf("invalid remote: %q", remote)
+	}
+	for _, refspec := range refspecs {
+		if refspec == "" || strings.HasPrefix(refspec, "-") {
+			return fmt.Errorf("invalid refspec: %q", refspec)
+		}
+	}
+
+	args := append([]string{"push", "--progress", remote}, refspecs...)
+	return runWithProgress(ctx, c.remoteCommand(ctx, args...), progress)
+}
+
+// runWithProgress runs a git command which reports its progress on stderr,
+// passing each line to progress. Errors say what git said went wrong.
+func runWithProgress(ctx context.Context, cmd *exec.Cmd, progress func(line string)) error {
+	name := "git " + cmd.Args[1]
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	// Progress counters are redrawn with carriage returns. The first error
+	// git reports says best what went wrong.
+	var failure, last string
+	scanner := bufio.NewScanner(stderr)
+	scanner.Split(scanProgressLines)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if progress != nil {
+			progress(line)
+		}
+		last = line
+		if failure == "" && (strings.HasPrefix(line, "fatal:") || strings.HasPrefix(line, "error:") || strings.HasPrefix(line, "! ")) {
+			failure = line
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s: %w", name, ctx.Err())
+		}
+		if failure == "" {
+			failure = last
+		}
+		if failure != "" {
+			return fmt.Errorf("%s: %s", name, failure)
+		}
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// scanProgressLines splits output into lines ending with either a newline
+// or a carriage return
+func scanProgressLines(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
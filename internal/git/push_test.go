@@ -0,0 +1,70 @@
+package git
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPush(t *testing.T) {
+	dir := newTestRepo(t)
+	remote := t.TempDir()
+	gitIn(t, remote, "init", "--quiet", "--bare")
+	gitIn(t, dir, "branch", "topic")
+	gitIn(t, dir, "tag", "v1")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	_, err := client.PushRemote("topic")
+	assert.EqualError(t, err, "no remote to push to")
+	gitIn(t, dir, "remote", "add", "upstream", remote)
+	name, err := client.PushRemote("topic")
+	require.NoError(t, err)
+	assert.Equal(t, "upstream", name)
+	gitIn(t, dir, "config", "remote.pushDefault", "mirror")
+	name, err = client.PushRemote("")
+	require.NoError(t, err)
+	assert.Equal(t, "mirror", name)
+	gitIn(t, dir, "config", "branch.topic.pushRemote", "fork")
+	name, err = client.PushRemote("topic")
+	require.NoError(t, err)
+	assert.Equal(t, "fork", name)
+
+	assert.Error(t, client.Push(context.Background(), "--mirror", nil, nil))
+	assert.Error(t, client.Push(context.Background(), "upstream", []string{"--force"}, nil))
+
+	var lines []string
+	require.NoError(t, client.Push(context.Background(), "upstream", []string{"refs/heads/topic", "refs/tags/v1"}, func(line string) {
+		lines = append(lines, line)
+	}))
+	assert.NotEmpty(t, lines)
+	assert.Equal(t, "topic\nv1", gitIn(t, remote, "for-each-ref", "--format=%(refname:short)"))
+
+	// Deleting remote refs is pushing nothing to them
+	require.NoError(t, client.Push(context.Background(), "upstream", []string{":refs/tags/v1"}, nil))
+	assert.Equal(t, "topic", gitIn(t, remote, "for-each-ref", "--format=%(refname:short)"))
+
+	err = client.Push(context.Background(), "nowhere", []string{"refs/heads/main"}, nil)
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "git push: "))
+	assert.Contains(t, err.Error(), "nowhere")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, client.Push(ctx, "upstream", []string{"refs/heads/main"}, nil), context.Canceled)
+}
+
+func TestScanProgressLines(t *testing.T) {
+	advance, token, err := scanProgressLines([]byte("Writing objects:  50%\rWriting"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, 22, advance)
+	assert.Equal(t, "Writing objects:  50%", string(token))
+
+	advance, token, _ = scanProgressLines([]byte("done"), true)
+	assert.Equal(t, 4, advance)
+	assert.Equal(t, "done", string(token))
+}
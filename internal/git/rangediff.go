@@ -0,0 +1,85 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RangeDiffStatus describes how a commit of a patch series changed between
+// two versions of the series
+type RangeDiffStatus rune
+
+const (
+	RangeDiffUnchanged RangeDiffStatus = '='
+	RangeDiffModified  RangeDiffStatus = '!'
+	RangeDiffDropped   RangeDiffStatus = '<'
+	RangeDiffAdded     RangeDiffStatus = '>'
+)
+
+// RangeDiffEntry represents a pair of matched commits in a range-diff.
+// Dropped commits have no new side and added commits no old side, which
+// is indicated by a zero index and an empty hash.
+type RangeDiffEntry struct {
+	OldIndex int
+	OldHash  string
+	NewIndex int
+	NewHash  string
+	Status   RangeDiffStatus
+	Subject  string
+	Diff     []string // Difference between the two patches, if modified
+}
+
+// rangeDiffPairRe matches a "1:  abc1234 ! 1:  def5678 subject" line
+var rangeDiffPairRe = regexp.MustCompile(`^\s*(\d+|-):\s+([0-9a-f]+|-+) ([=!<>])\s+(\d+|-):\s+([0-9a-f]+|-+) ?(.*)$`)
+
+// ParseRangeDiff parses the output of git range-diff --no-color
+func ParseRangeDiff(output string) ([]*RangeDiffEntry, error) {
+	var entries []*RangeDiffEntry
+
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		if line == "" && len(entries) == 0 {
+			continue
+		}
+
+		match := rangeDiffPairRe.FindStringSubmatch(line)
+		if match == nil {
+			if len(entries) == 0 {
+				return nil, fmt.Errorf("invalid range-diff line: %q", line)
+			}
+			// Patch differences are indented by four spaces
+			entry := entries[len(entries)-1]
+			entry.Diff = append(entry.Diff, strings.TrimPrefix(line, "    "))
+			continue
+		}
+
+		entries = append(entries, &RangeDiffEntry{
+			OldIndex: parseRangeDiffIndex(match[1]),
+			OldHash:  parseRangeDiffHash(match[2]),
+			Status:   RangeDiffStatus(match[3][0]),
+			NewIndex: parseRangeDiffIndex(match[4]),
+			NewHash:  parseRangeDiffHash(match[5]),
+			Subject:  match[6],
+		})
+	}
+
+	return entries, nil
+}
+
+// parseRangeDiffIndex parses a series position, "-" meaning none
+func parseRangeDiffIndex(text string) int {
+	index, err := strconv.Atoi(text)
+	if err != nil {
+		return 0
+	}
+	return index
+}
+
+// parseRangeDiffHash parses an abbreviated hash, dashes meaning none
+func parseRangeDiffHash(text string) string {
+	if strings.Trim(text, "-") == "" {
+		return ""
+	}
+	return text
+}
@@ -0,0 +1,55 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRangeDiff(t *testing.T) {
+	output := `1:  8c96931 = 1:  beb3ffc c1
+2:  0bcc87e ! 2:  c1f1239 c2
+    @@ Metadata
+      ## Commit message ##
+    -    c2
+    +    c2 reworded
+3:  ff7c184 < -:  ------- c3
+-:  ------- > 3:  fdae856 c4
+`
+
+	entries, err := ParseRangeDiff(output)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 4)
+
+	assert.Equal(t, &RangeDiffEntry{
+		OldIndex: 1, OldHash: "8c96931",
+		NewIndex: 1, NewHash: "beb3ffc",
+		Status: RangeDiffUnchanged, Subject: "c1",
+	}, entries[0])
+
+	assert.Equal(t, RangeDiffModified, entries[1].Status)
+	assert.Equal(t, []string{
+		"@@ Metadata",
+		"  ## Commit message ##",
+		"-    c2",
+		"+    c2 reworded",
+	}, entries[1].Diff)
+
+	assert.Equal(t, RangeDiffDropped, entries[2].Status)
+	assert.Equal(t, 0, entries[2].NewIndex)
+	assert.Equal(t, "", entries[2].NewHash)
+
+	assert.Equal(t, RangeDiffAdded, entries[3].Status)
+	assert.Equal(t, 0, entries[3].OldIndex)
+	assert.Equal(t, "fdae856", entries[3].NewHash)
+	assert.Equal(t, "c4", entries[3].Subject)
+}
+
+func TestParseRangeDiffInvalid(t *testing.T) {
+	_, err := ParseRangeDiff("fatal: not a range\n")
+	assert.Error(t, err)
+
+	entries, err := ParseRangeDiff("")
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
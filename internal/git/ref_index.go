@@ -0,0 +1,304 @@
+package git
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RefIndex holds all the refs of the repository, read with a single git
+// for-each-ref, for the views to look refs up by commit or branch without
+// asking git once per row
+type RefIndex struct {
+	Refs       []*Ref // Branches, tags and remote branches, sorted by name
+	Head       string // Full name of the current branch, empty when HEAD is detached
+	HeadCommit string // Commit HEAD points to, empty before the first commit
+
+	byCommit map[string][]*Ref // Refs pointing at a commit, tags peeled
+	commits  map[string]string // Commit of each ref by full name
+	upstream map[string]string // Upstream of each branch by full name
+	tracking map[string][2]int // Commits ahead and behind the upstream of each branch by full name
+
+	readTags func() (string, error) // Runs the git log the tag topology is read from
+	tagsOnce sync.Once
+	previous map[string][]string // Commits of the nearest tags each tagged commit descends from
+}
+
+// tagTopologyArgs are the git log arguments listing the tagged commits
+// with, as parents, the nearest tagged commits they descend from
+var tagTopologyArgs = []string{"log", "--simplify-by-decoration", "--decorate-refs=refs/tags/", "--parents", "--format=%H %P", "--tags"}
+
+// refIndexFormat writes whether the ref is the current branch, its name,
+// the object it points to, the commit an annotated tag points to, the
+// upstream of a branch and how far ahead and behind of it the branch is
+const refIndexFormat = "--format=%(HEAD)%00%(refname)%00%(objectname)%00%(*objectname)%00%(upstream)%00%(upstream:track,nobracket)"
+
+// RefIndex reads all the refs at once. The refs tig keeps for itself and
+// the stashes are left out, as in the log.
+func (c *GoGitClient) RefIndex() (*RefIndex, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+
+	output, err := c.runGit(c.path, "for-each-ref", refIndexFormat)
+	if err != nil {
+		return nil, err
+	}
+	index, err := parseRefIndex(string(output))
+	if err != nil {
+		return nil, err
+	}
+	if head, err := c.GetHead(); err == nil {
+		index.HeadCommit = head.Hash
+	}
+	index.readTags = func() (string, error) {
+		output, err := c.runGit(c.path, tagTopologyArgs...)
+		return string(output), err
+	}
+	return index, nil
+}
+
+// parseRefIndex parses the output of git for-each-ref with refIndexFormat
+func parseRefIndex(output string) (*RefIndex, error) {
+	index := &RefIndex{
+		byCommit: make(map[string][]*Ref),
+		commits:  make(map[string]string),
+		upstream: make(map[string]string),
+		tracking: make(map[string][2]int),
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 6 {
+			return nil, fmt.Errorf("invalid ref line: %q", line)
+		}
+		current, name, hash, peeled, upstream, track := fields[0], fields[1], fields[2], fields[3], fields[4], fields[5]
+		if strings.HasPrefix(name, "refs/tig/") || name == "refs/stash" {
+			continue
+		}
+
+		ref := &Ref{Name: name, Type: refType(name), Hash: hash}
+		index.Refs = append(index.Refs, ref)
+		commit := hash
+		if peeled != "" {
+			commit = peeled
+		}
+		index.commits[name] = commit
+		index.byCommit[commit] = append(index.byCommit[commit], ref)
+		if upstream != "" {
+			index.upstream[name] = upstream
+		}
+		// Such as "ahead 1, behind 2", empty when up to date, or "gone"
+		if track != "" {
+			var counts [2]int
+			for _, part := range strings.Split(track, ", ") {
+				if n, ok := strings.CutPrefix(part, "ahead "); ok {
+					counts[0], _ = strconv.Atoi(n)
+				} else if n, ok := strings.CutPrefix(part, "behind "); ok {
+					counts[1], _ = strconv.Atoi(n)
+				}
+			}
+			index.tracking[name] = counts
+		}
+		if current == "*" {
+			index.Head = name
+		}
+	}
+	return index, nil
+}
+
+// refType returns the type of a ref from its full name
+func refType(name string) RefType {
+	switch {
+	case strings.HasPrefix(name, "refs/heads/"):
+		return RefTypeBranch
+	case strings.HasPrefix(name, "refs/tags/"):
+		return RefTypeTag
+	case strings.HasPrefix(name, "refs/remotes/"):
+		return RefTypeRemote
+	}
+	return RefTypeOther
+}
+
+// At returns the refs pointing at a commit, annotated tags included
+func (i *RefIndex) At(hash string) []*Ref {
+	if i == nil {
+		return nil
+	}
+	return i.byCommit[hash]
+}
+
+// Commit returns the commit a ref points to by full name, peeling
+// annotated tags, empty when there is no such ref
+func (i *RefIndex) Commit(name string) string {
+	if i == nil {
+		return ""
+	}
+	return i.commits[name]
+}
+
+// Upstream returns the full name of the upstream of a branch, empty when
+// it has none
+func (i *RefIndex) Upstream(branch string) string {
+	if i == nil {
+		return ""
+	}
+	return i.upstream[branch]
+}
+
+// Tracking returns how many commits a branch has that its upstream
+// doesn't, and the other way around, by full name of the branch. Both are
+// zero when it has no upstream.
+func (i *RefIndex) Tracking(branch string) (int, int) {
+	if i == nil {
+		return 0, 0
+	}
+	counts := i.tracking[branch]
+	return counts[0], counts[1]
+}
+
+// PreviousTags returns the tags nearest to a tag, by full name, among those
+// its commit descends from: those of the release before it, or of each of
+// the releases merged into it that the others don't already contain. The whole tag topology is read with a single git
+// log the first time it is asked for, and kept along with the index.
+func (i *RefIndex) PreviousTags(tag string) []*Ref {
+	if i == nil || refType(tag) != RefTypeTag || i.commits[tag] == "" {
+		return nil
+	}
+	i.tagsOnce.Do(func() {
+		i.previous = make(map[string][]string)
+		if i.readTags == nil {
+			return
+		}
+		output, err := i.readTags()
+		if err != nil {
+			return
+		}
+		for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+			if commit, parents, ok := strings.Cut(line, " "); ok {
+				i.previous[commit] = strings.Fields(parents)
+			}
+		}
+	})
+
+	var tags []*Ref
+	for _, commit := range i.previous[i.commits[tag]] {
+		for _, ref := range i.byCommit[commit] {
+			if ref.Type == RefTypeTag {
+				tags = append(tags, ref)
+			}
+		}
+	}
+	return tags
+}
+
+// OfType returns the refs of a type, sorted by name
+func (i *RefIndex) OfType(t RefType) []*Ref {
+	if i == nil {
+		return nil
+	}
+	var refs []*Ref
+	for _, ref := range i.Refs {
+		if ref.Type == t {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// ShortRefName returns the name of a ref as shown to users, without the
+// refs/heads/, refs/tags/ or refs/remotes/ prefix
+func ShortRefName(name string) string {
+	for _, prefix := range []string{"refs/heads/", "refs/tags/", "refs/remotes/"} {
+		if short, ok := strings.CutPrefix(name, prefix); ok {
+			return short
+		}
+	}
+	return name
+}
+
+// RefsStamp returns a stamp of the files git keeps the refs in: HEAD,
+// packed-refs and the loose refs, which changes whenever a ref is updated.
+// Only the files are looked at, git being run once to find them, so it can
+// be polled to refresh what shows refs without reading them again.
+func (c *GoGitClient) RefsStamp() (uint64, error) {
+	if c.repo == nil {
+		return 0, fmt.Errorf("repository not opened")
+	}
+	if c.refDirs == nil {
+		output, err := c.runGit(c.path, "rev-parse", "--absolute-git-dir", "--git-common-dir")
+		if err != nil {
+			return 0, err
+		}
+		dirs := strings.Split(strings.TrimSpace(string(output)), "\n")
+		if len(dirs) != 2 {
+			return 0, fmt.Errorf("unexpected output of git rev-parse: %q", output)
+		}
+		if !filepath.IsAbs(dirs[1]) {
+			dirs[1] = filepath.Join(c.path, dirs[1])
+		}
+		c.refDirs = dirs
+	}
+	gitDir, commonDir := c.refDirs[0], c.refDirs[1]
+
+	hash := fnv.New64a()
+	stamp := func(path string, info fs.FileInfo) {
+		fmt.Fprintf(hash, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+	}
+	for _, path := range []string{filepath.Join(gitDir, "HEAD"), filepath.Join(commonDir, "packed-refs")} {
+		if info, err := os.Stat(path); err == nil {
+			stamp(path, info)
+		}
+	}
+
+	// The refs tig keeps for itself don't show anywhere
+	refsDir := filepath.Join(commonDir, "refs")
+	tigRefs := filepath.Join(refsDir, "tig")
+	err := filepath.WalkDir(refsDir, func(path string, entry fs.DirEntry, err error) error {
+		switch {
+		case err != nil:
+			// Refs deleted while walking show in the next stamp
+			return nil
+		case entry.IsDir() && path == tigRefs:
+			return filepath.SkipDir
+		case entry.IsDir():
+			return nil
+		}
+		if info, err := entry.Info(); err == nil {
+			stamp(path, info)
+		}
+		return nil
+	})
+	return hash.Sum64(), err
+}
+
+// AheadBehind returns how many commits a branch has that its upstream
+// doesn't, and the other way around
+func (c *GoGitClient) AheadBehind(branch, upstream string) (int, int, error) {
+	if c.repo == nil {
+		return 0, 0, fmt.Errorf("repository not opened")
+	}
+	if err := validateRevRange(branch); err != nil {
+		return 0, 0, err
+	}
+	if err := validateRevRange(upstream); err != nil {
+		return 0, 0, err
+	}
+
+	output, err := c.runGit(c.path, "rev-list", "--left-right", "--count", branch+"..."+upstream, "--")
+	if err != nil {
+		return 0, 0, err
+	}
+	var ahead, behind int
+	if _, err := fmt.Sscan(string(output), &ahead, &behind); err != nil {
+		return 0, 0, fmt.Errorf("unexpected output of git rev-list: %q", output)
+	}
+	return ahead, behind, nil
+}
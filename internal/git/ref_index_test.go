@@ -0,0 +1,160 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefIndex(t *testing.T) {
+	dir := newTestRepo(t)
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	head := gitIn(t, dir, "rev-parse", "HEAD")
+	gitIn(t, dir, "tag", "-a", "-m", "Release", "v1.0")
+	gitIn(t, dir, "tag", "light")
+	gitIn(t, dir, "branch", "topic")
+	gitIn(t, dir, "update-ref", "refs/remotes/origin/main", head)
+	gitIn(t, dir, "remote", "add", "origin", dir)
+	gitIn(t, dir, "config", "branch.main.remote", "origin")
+	gitIn(t, dir, "config", "branch.main.merge", "refs/heads/main")
+	gitIn(t, dir, "update-ref", "refs/tig/kept", head)
+
+	index, err := client.RefIndex()
+	require.NoError(t, err)
+	assert.Equal(t, "refs/heads/main", index.Head)
+	assert.Equal(t, head, index.HeadCommit)
+
+	// Annotated tags decorate the commit they point to, and the refs of tig
+	// are left out
+	var names []string
+	for _, ref := range index.At(head) {
+		names = append(names, ref.Name)
+	}
+	assert.Equal(t, []string{"refs/heads/main", "refs/heads/topic", "refs/remotes/origin/main", "refs/tags/light", "refs/tags/v1.0"}, names)
+	assert.Equal(t, head, index.Commit("refs/tags/v1.0"))
+	assert.NotEqual(t, head, index.OfType(RefTypeTag)[1].Hash)
+	assert.Len(t, index.OfType(RefTypeBranch), 2)
+
+	assert.Equal(t, "refs/remotes/origin/main", index.Upstream("refs/heads/main"))
+	assert.Empty(t, index.Upstream("refs/heads/topic"))
+
+	gitIn(t, dir, "checkout", "--quiet", "--detach")
+	index, err = client.RefIndex()
+	require.NoError(t, err)
+	assert.Empty(t, index.Head)
+	assert.Equal(t, head, index.HeadCommit)
+
+	// A nil index has no refs
+	var none *RefIndex
+	assert.Nil(t, none.At(head))
+	assert.Empty(t, none.Upstream("refs/heads/main"))
+
+	_, err = parseRefIndex("*\x00refs/heads/main\n")
+	assert.Error(t, err)
+}
+
+func TestRefIndexPreviousTags(t *testing.T) {
+	dir := newTestRepo(t)
+	commit := func(content string) {
+		writeTestFile(t, dir, "file.txt", content)
+		gitIn(t, dir, "commit", "--quiet", "-am", content)
+	}
+	gitIn(t, dir, "tag", "v1")
+	gitIn(t, dir, "branch", "topic")
+	commit("two")
+	gitIn(t, dir, "tag", "-a", "-m", "Release", "v2")
+	commit("three")
+	gitIn(t, dir, "checkout", "--quiet", "topic")
+	writeTestFile(t, dir, "other.txt", "other\n")
+	gitIn(t, dir, "add", "other.txt")
+	gitIn(t, dir, "commit", "--quiet", "-m", "other")
+	gitIn(t, dir, "tag", "v2.1")
+	gitIn(t, dir, "checkout", "--quiet", "main")
+	gitIn(t, dir, "merge", "--quiet", "--no-edit", "topic")
+	gitIn(t, dir, "tag", "v3")
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	index, err := client.RefIndex()
+	require.NoError(t, err)
+	names := func(tag string) []string {
+		var names []string
+		for _, ref := range index.PreviousTags(tag) {
+			names = append(names, ref.Name)
+		}
+		return names
+	}
+	// Branches are not releases, and tags merged in are followed
+	assert.Equal(t, []string{"refs/tags/v2", "refs/tags/v2.1"}, names("refs/tags/v3"))
+	assert.Equal(t, []string{"refs/tags/v1"}, names("refs/tags/v2.1"))
+	assert.Equal(t, []string{"refs/tags/v1"}, names("refs/tags/v2"))
+	assert.Empty(t, names("refs/tags/v1"))
+	assert.Empty(t, names("refs/heads/main"))
+}
+
+func TestShortRefName(t *testing.T) {
+	assert.Equal(t, "main", ShortRefName("refs/heads/main"))
+	assert.Equal(t, "v1.0", ShortRefName("refs/tags/v1.0"))
+	assert.Equal(t, "origin/main", ShortRefName("refs/remotes/origin/main"))
+	assert.Equal(t, "refs/notes/commits", ShortRefName("refs/notes/commits"))
+}
+
+func TestRefsStamp(t *testing.T) {
+	dir := newTestRepo(t)
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	stamp, err := client.RefsStamp()
+	require.NoError(t, err)
+	again, err := client.RefsStamp()
+	require.NoError(t, err)
+	assert.Equal(t, stamp, again)
+
+	// New refs, packed ones and deleted ones change the stamp
+	gitIn(t, dir, "branch", "topic")
+	next, err := client.RefsStamp()
+	require.NoError(t, err)
+	assert.NotEqual(t, stamp, next)
+
+	gitIn(t, dir, "pack-refs", "--all")
+	packed, err := client.RefsStamp()
+	require.NoError(t, err)
+	assert.NotEqual(t, next, packed)
+
+	gitIn(t, dir, "branch", "--delete", "topic")
+	deleted, err := client.RefsStamp()
+	require.NoError(t, err)
+	assert.NotEqual(t, packed, deleted)
+
+	// The refs of tig are left out
+	gitIn(t, dir, "update-ref", "refs/tig/kept", "HEAD")
+	kept, err := client.RefsStamp()
+	require.NoError(t, err)
+	assert.Equal(t, deleted, kept)
+}
+
+func TestAheadBehind(t *testing.T) {
+	dir := newTestRepo(t)
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	gitIn(t, dir, "branch", "upstream")
+	writeTestFile(t, dir, "file.txt", "ahead\n")
+	gitIn(t, dir, "commit", "--quiet", "-am", "ahead")
+
+	ahead, behind, err := client.AheadBehind("refs/heads/main", "refs/heads/upstream")
+	require.NoError(t, err)
+	assert.Equal(t, 1, ahead)
+	assert.Equal(t, 0, behind)
+
+	ahead, behind, err = client.AheadBehind("upstream", "main")
+	require.NoError(t, err)
+	assert.Equal(t, 0, ahead)
+	assert.Equal(t, 1, behind)
+
+	_, _, err = client.AheadBehind("--all", "main")
+	assert.Error(t, err)
+}
@@ -0,0 +1,137 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// RefDeletion is a branch or tag to delete, along with the commit it
+// points to so it can be restored
+type RefDeletion struct {
+	Name     string // Full name, such as refs/heads/topic
+	Hash     string
+	Unmerged bool // Branch with commits not merged into HEAD
+}
+
+// ShortName returns the name of the ref without its refs/heads/ or
+// refs/tags/ prefix
+func (d *RefDeletion) ShortName() string {
+	if name, ok := strings.CutPrefix(d.Name, "refs/heads/"); ok {
+		return name
+	}
+	return strings.TrimPrefix(d.Name, "refs/tags/")
+}
+
+// IsBranch returns whether the ref is a local branch
+func (d *RefDeletion) IsBranch() bool {
+	return strings.HasPrefix(d.Name, "refs/heads/")
+}
+
+// PlanRefDeletion works out what deleting the given branches and tags
+// would do without changing anything. Branches checked out in this or a
+// linked worktree can't be deleted.
+func (c *GoGitClient) PlanRefDeletion(refs []string) ([]*RefDeletion, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+
+	head, _ := c.runGit(c.path, "symbolic-ref", "--quiet", "HEAD")
+	current := strings.TrimSpace(string(head))
+	worktrees, err := c.checkedOutBranches()
+	if err != nil {
+		return nil, err
+	}
+
+	unmerged := make(map[string]bool)
+	output, err := c.runGit(c.path, "branch", "--no-merged", "HEAD", "--format=%(refname)")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range strings.Fields(string(output)) {
+		unmerged[name] = true
+	}
+
+	var plan []*RefDeletion
+	for _, ref := range refs {
+		if !strings.HasPrefix(ref, "refs/heads/") && !strings.HasPrefix(ref, "refs/tags/") {
+			return nil, fmt.Errorf("%s is not a branch or tag", ref)
+		}
+		if ref == current {
+			return nil, fmt.Errorf("cannot delete the checked out branch %s", strings.TrimPrefix(ref, "refs/heads/"))
+		}
+		if worktree, ok := worktrees[ref]; ok {
+			return nil, fmt.Errorf("cannot delete branch %s checked out at %s", strings.TrimPrefix(ref, "refs/heads/"), worktree)
+		}
+		hash, err := c.runGit(c.path, "rev-parse", "--verify", "--quiet", ref)
+		if err != nil {
+			return nil, fmt.Errorf("no such ref: %s", ref)
+		}
+		plan = append(plan, &RefDeletion{
+			Name:     ref,
+			Hash:     strings.TrimSpace(string(hash)),
+			Unmerged: unmerged[ref],
+		})
+	}
+	return plan, nil
+}
+
+// checkedOutBranches returns the branches checked out in the worktrees of
+// the repository, with the worktree of each
+func (c *GoGitClient) checkedOutBranches() (map[string]string, error) {
+	output, err := c.runGit(c.path, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+
+	branches := make(map[string]string)
+	worktree := ""
+	for _, line := range strings.Split(string(output), "\n") {
+		if path, ok := strings.CutPrefix(line, "worktree "); ok {
+			worktree = path
+		} else if branch, ok := strings.CutPrefix(line, "branch "); ok {
+			branches[branch] = worktree
+		}
+	}
+	return branches, nil
+}
+
+// DeleteRefs deletes the refs of a plan in a single transaction, which
+// fails without deleting anything if any of them moved since it was
+// planned. The configuration of deleted branches is removed as well.
+func (c *GoGitClient) DeleteRefs(plan []*RefDeletion) error {
+	if c.repo == nil {
+		return fmt.Errorf("repository not opened")
+	}
+	if len(plan) == 0 {
+		return nil
+	}
+
+	var input bytes.Buffer
+	input.WriteString("start\n")
+	for _, ref := range plan {
+		fmt.Fprintf(&input, "delete %s %s\n", ref.Name, ref.Hash)
+	}
+	input.WriteString("commit\n")
+
+	cmd := exec.Command("git", "update-ref", "--stdin")
+	cmd.Dir = c.path
+	cmd.Stdin = &input
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("git update-ref: %s", msg)
+		}
+		return fmt.Errorf("git update-ref: %w", err)
+	}
+
+	// Upstream settings of the branches would otherwise linger
+	for _, ref := range plan {
+		if ref.IsBranch() {
+			_, _ = c.runGit(c.path, "config", "--remove-section", "branch."+ref.ShortName())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package git
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteRefs(t *testing.T) {
+	dir := newTestRepo(t)
+	gitIn(t, dir, "branch", "merged")
+	gitIn(t, dir, "tag", "v1")
+	gitIn(t, dir, "checkout", "--quiet", "-b", "topic")
+	writeTestFile(t, dir, "topic.txt", "topic\n")
+	gitIn(t, dir, "add", "topic.txt")
+	gitIn(t, dir, "commit", "--quiet", "-m", "topic")
+	topic := gitIn(t, dir, "rev-parse", "HEAD")
+	gitIn(t, dir, "config", "branch.topic.remote", "origin")
+	gitIn(t, dir, "checkout", "--quiet", "main")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	_, err := client.PlanRefDeletion([]string{"refs/heads/main"})
+	assert.EqualError(t, err, "cannot delete the checked out branch main")
+	_, err = client.PlanRefDeletion([]string{"refs/heads/missing"})
+	assert.Error(t, err)
+	_, err = client.PlanRefDeletion([]string{"refs/remotes/origin/main"})
+	assert.Error(t, err)
+
+	plan, err := client.PlanRefDeletion([]string{"refs/heads/merged", "refs/heads/topic", "refs/tags/v1"})
+	require.NoError(t, err)
+	require.Len(t, plan, 3)
+	assert.False(t, plan[0].Unmerged)
+	assert.Equal(t, &RefDeletion{Name: "refs/heads/topic", Hash: topic, Unmerged: true}, plan[1])
+	assert.Equal(t, "v1", plan[2].ShortName())
+	assert.False(t, plan[2].IsBranch())
+
+	// Planning changes nothing
+	assert.Equal(t, topic, gitIn(t, dir, "rev-parse", "topic"))
+
+	require.NoError(t, client.DeleteRefs(plan))
+	assert.Equal(t, "main", gitIn(t, dir, "branch", "--format=%(refname:short)"))
+	assert.Empty(t, gitIn(t, dir, "tag"))
+	assert.NotContains(t, gitIn(t, dir, "config", "--list", "--local", "--name-only"), "branch.topic.remote")
+}
+
+func TestDeleteRefsMoved(t *testing.T) {
+	dir := newTestRepo(t)
+	gitIn(t, dir, "branch", "one")
+	gitIn(t, dir, "branch", "two")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+	plan, err := client.PlanRefDeletion([]string{"refs/heads/one", "refs/heads/two"})
+	require.NoError(t, err)
+
+	// A branch moving after the preview aborts the whole deletion
+	writeTestFile(t, dir, "file.txt", "changed\n")
+	gitIn(t, dir, "commit", "--quiet", "-am", "move")
+	gitIn(t, dir, "branch", "--force", "two", "HEAD")
+
+	assert.Error(t, client.DeleteRefs(plan))
+	assert.Equal(t, "main\none\ntwo", gitIn(t, dir, "branch", "--format=%(refname:short)"))
+}
+
+func TestDeleteRefsLinkedWorktree(t *testing.T) {
+	dir := newTestRepo(t)
+	linked := filepath.Join(t.TempDir(), "linked")
+	gitIn(t, dir, "worktree", "add", "--quiet", "-b", "elsewhere", linked)
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	// The branch of a linked worktree is as good as checked out
+	_, err := client.PlanRefDeletion([]string{"refs/heads/elsewhere"})
+	assert.EqualError(t, err, "cannot delete branch elsewhere checked out at "+gitIn(t, linked, "rev-parse", "--show-toplevel"))
+
+	gitIn(t, dir, "worktree", "remove", linked)
+	_, err = client.PlanRefDeletion([]string{"refs/heads/elsewhere"})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,73 @@
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitDiffRenames(t *testing.T) {
+	dir := newTestRepo(t)
+	lines := strings.Repeat("line\n", 20)
+	writeTestFile(t, dir, "old.txt", lines)
+	gitIn(t, dir, "add", "old.txt")
+	gitIn(t, dir, "commit", "--quiet", "-m", "add old")
+	gitIn(t, dir, "mv", "old.txt", "new.txt")
+	writeTestFile(t, dir, "new.txt", lines+"more\n")
+	writeTestFile(t, dir, "old.txt", lines+"changed\n")
+	writeTestFile(t, dir, "copy.txt", lines)
+	gitIn(t, dir, "add", "--all")
+	gitIn(t, dir, "commit", "--quiet", "-m", "move")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	paths := func(diff *Diff) []string {
+		var result []string
+		for _, file := range diff.Files {
+			kind := "M"
+			switch {
+			case file.IsRenamed:
+				kind = "R"
+			case file.IsCopied:
+				kind = "C"
+			case file.IsNew:
+				kind = "A"
+			}
+			result = append(result, kind+" "+file.OldPath+" "+file.NewPath)
+		}
+		return result
+	}
+
+	// old.txt was rewritten in place, so nothing is renamed but copies
+	// of it are found when asked for
+	diff, err := client.GetCommitDiff("HEAD", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A copy.txt copy.txt", "A new.txt new.txt", "M old.txt old.txt"}, paths(diff))
+
+	diff, err = client.GetCommitDiff("HEAD", &DiffOptions{FindCopies: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"C old.txt copy.txt", "C old.txt new.txt", "M old.txt old.txt"}, paths(diff))
+	assert.Equal(t, 95, diff.Files[1].Similarity)
+	assert.Len(t, diff.Files[0].Hunks, 0, "only the real changes are shown")
+
+	// Copies less similar than the threshold are additions
+	diff, err = client.GetCommitDiff("HEAD", &DiffOptions{FindCopies: true, RenameThreshold: 100})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"C old.txt copy.txt", "A new.txt new.txt", "M old.txt old.txt"}, paths(diff))
+
+	gitIn(t, dir, "mv", "copy.txt", "moved.txt")
+	gitIn(t, dir, "commit", "--quiet", "-m", "rename")
+	diff, err = client.GetCommitDiff("HEAD", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"R copy.txt moved.txt"}, paths(diff))
+	assert.Equal(t, 100, diff.Files[0].Similarity)
+}
+
+func TestRenameArgs(t *testing.T) {
+	var opts *DiffOptions
+	assert.Equal(t, []string{"-M"}, opts.renameArgs())
+	assert.Equal(t, []string{"-M80%", "-C80%"}, (&DiffOptions{RenameThreshold: 80, FindCopies: true}).renameArgs())
+}
@@ -0,0 +1,121 @@
+package git
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ReviewNote is a note left on a file, or on a hunk of it, of a commit or
+// range under review
+type ReviewNote struct {
+	Revision string    `json:"revision"` // Commit or range reviewed
+	Path     string    `json:"path"`
+	Hunk     string    `json:"hunk,omitempty"` // Header of the hunk, empty for the whole file
+	Line     int       `json:"line,omitempty"` // First line of the hunk on the new side
+	Text     string    `json:"text"`
+	Time     time.Time `json:"time"`
+}
+
+// reviewNotesPath returns the file the review notes of the repository are
+// kept in, under its git directory as they are nobody else's business
+func (c *GoGitClient) reviewNotesPath() (string, error) {
+	if c.repo == nil {
+		return "", fmt.Errorf("repository not opened")
+	}
+	gitDir, err := c.runGit(c.path, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(strings.TrimSpace(string(gitDir)), "tig", "review.json"), nil
+}
+
+// ReviewNotes returns the review notes left in the repository, in the
+// order they were written
+func (c *GoGitClient) ReviewNotes() ([]*ReviewNote, error) {
+	path, err := c.reviewNotesPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read review notes: %w", err)
+	}
+	var notes []*ReviewNote
+	if err := json.Unmarshal(data, &notes); err != nil {
+		return nil, fmt.Errorf("failed to read review notes: %w", err)
+	}
+	return notes, nil
+}
+
+// SaveReviewNotes replaces the review notes of the repository, removing
+// the file keeping them once there are none
+func (c *GoGitClient) SaveReviewNotes(notes []*ReviewNote) error {
+	path, err := c.reviewNotesPath()
+	if err != nil {
+		return err
+	}
+	if len(notes) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to save review notes: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(notes, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to save review notes: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to save review notes: %w", err)
+	}
+	return nil
+}
+
+// FormatReview writes review notes as markdown, grouped by the revision
+// and then the file they were left on, in the order they were first
+// noted. Notes on hunks are headed by the hunk and its line.
+func FormatReview(notes []*ReviewNote) string {
+	var revisions []string
+	paths := make(map[string][]string)
+	byFile := make(map[[2]string][]*ReviewNote)
+	for _, note := range notes {
+		if _, ok := paths[note.Revision]; !ok {
+			revisions = append(revisions, note.Revision)
+		}
+		key := [2]string{note.Revision, note.Path}
+		if _, ok := byFile[key]; !ok {
+			paths[note.Revision] = append(paths[note.Revision], note.Path)
+		}
+		byFile[key] = append(byFile[key], note)
+	}
+
+	var b strings.Builder
+	b.WriteString("# Review\n")
+	for _, revision := range revisions {
+		fmt.Fprintf(&b, "\n## %s\n", revision)
+		for _, path := range paths[revision] {
+			fmt.Fprintf(&b, "\n### `%s`\n\n", path)
+			for _, note := range byFile[[2]string{revision, path}] {
+				text := strings.ReplaceAll(strings.TrimSpace(note.Text), "\n", "\n  ")
+				if note.Hunk == "" {
+					fmt.Fprintf(&b, "- %s\n", text)
+					continue
+				}
+				fmt.Fprintf(&b, "- `%s` line %d: %s\n", note.Hunk, note.Line, text)
+			}
+		}
+	}
+	return b.String()
+}
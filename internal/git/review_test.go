@@ -0,0 +1,51 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewNotes(t *testing.T) {
+	dir := newTestRepo(t)
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	notes, err := client.ReviewNotes()
+	require.NoError(t, err)
+	assert.Empty(t, notes)
+
+	note := &ReviewNote{Revision: "abc", Path: "file.txt", Hunk: "@@ -1 +1,2 @@", Line: 1, Text: "why?", Time: time.Unix(1700000000, 0)}
+	require.NoError(t, client.SaveReviewNotes([]*ReviewNote{note}))
+	notes, err = client.ReviewNotes()
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	assert.Equal(t, "why?", notes[0].Text)
+	assert.True(t, note.Time.Equal(notes[0].Time))
+
+	// The notes are kept out of the worktree
+	assert.Empty(t, gitIn(t, dir, "status", "--porcelain"))
+
+	require.NoError(t, client.SaveReviewNotes(nil))
+	notes, err = client.ReviewNotes()
+	require.NoError(t, err)
+	assert.Empty(t, notes)
+}
+
+func TestFormatReview(t *testing.T) {
+	notes := []*ReviewNote{
+		{Revision: "abc", Path: "a.go", Hunk: "@@ -1,2 +1,3 @@", Line: 1, Text: "Needs a test"},
+		{Revision: "abc..def", Path: "b.go", Text: "Split this file\ninto two"},
+		{Revision: "abc", Path: "a.go", Hunk: "@@ -10 +11 @@", Line: 11, Text: "Typo"},
+	}
+	assert.Equal(t, "# Review\n"+
+		"\n## abc\n"+
+		"\n### `a.go`\n\n"+
+		"- `@@ -1,2 +1,3 @@` line 1: Needs a test\n"+
+		"- `@@ -10 +11 @@` line 11: Typo\n"+
+		"\n## abc..def\n"+
+		"\n### `b.go`\n\n"+
+		"- Split this file\n  into two\n", FormatReview(notes))
+}
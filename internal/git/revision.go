@@ -0,0 +1,23 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResolveRevision returns the full hash of the commit a revision names,
+// failing with a plain message when it names none
+func (c *GoGitClient) ResolveRevision(rev string) (string, error) {
+	if c.repo == nil {
+		return "", fmt.Errorf("repository not opened")
+	}
+	if err := validateRevRange(rev); err != nil {
+		return "", err
+	}
+
+	output, err := c.runGit(c.path, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown revision: %s", rev)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
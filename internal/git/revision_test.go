@@ -0,0 +1,30 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveRevision(t *testing.T) {
+	dir := newTestRepo(t)
+	head := gitIn(t, dir, "rev-parse", "HEAD")
+	gitIn(t, dir, "tag", "-a", "-m", "annotated", "v1")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	for _, rev := range []string{"HEAD", "main", "v1", head[:7]} {
+		hash, err := client.ResolveRevision(rev)
+		assert.NoError(t, err, rev)
+		assert.Equal(t, head, hash, rev)
+	}
+
+	_, err := client.ResolveRevision("missing")
+	assert.EqualError(t, err, "unknown revision: missing")
+	_, err = client.ResolveRevision("HEAD^")
+	assert.EqualError(t, err, "unknown revision: HEAD^")
+	_, err = client.ResolveRevision("--all")
+	assert.Error(t, err)
+}
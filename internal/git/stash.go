@@ -0,0 +1,149 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseStashSubject splits the reflog subject of a stash, such as "On main:
+// message" or "WIP on main: abc1234 summary", into its branch and message
+func parseStashSubject(subject string) (branch, message string) {
+	rest, ok := strings.CutPrefix(subject, "WIP on ")
+	if !ok {
+		if rest, ok = strings.CutPrefix(subject, "On "); !ok {
+			return "", subject
+		}
+	}
+	branch, message, ok = strings.Cut(rest, ": ")
+	if !ok {
+		return "", subject
+	}
+	return branch, message
+}
+
+// GetStashes returns the stashes, the most recent first
+func (c *GoGitClient) GetStashes() ([]*Stash, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+
+	output, err := c.runGit(c.path, "stash", "list", "-z", "--format=%H%x1f%P%x1f%an%x1f%ae%x1f%at%x1f%gs")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stashes: %w", err)
+	}
+
+	var stashes []*Stash
+	for i, record := range strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00") {
+		fields := strings.Split(strings.TrimPrefix(record, "\n"), "\x1f")
+		if len(fields) < 6 {
+			continue
+		}
+		seconds, _ := strconv.ParseInt(fields[4], 10, 64)
+		author := Signature{Name: fields[2], Email: fields[3], Time: time.Unix(seconds, 0)}
+		branch, message := parseStashSubject(fields[5])
+		stashes = append(stashes, &Stash{
+			Index:   i,
+			Message: message,
+			Branch:  branch,
+			Commit: &Commit{
+				Hash:      fields[0],
+				Author:    author,
+				Committer: author,
+				Message:   fields[5],
+				Summary:   fields[5],
+				Parents:   strings.Fields(fields[1]),
+			},
+		})
+	}
+	return stashes, nil
+}
+
+// stashRef returns the stash@{n} name of the stash with the given hash,
+// which changes as stashes are pushed and dropped, and whether it is found
+func (c *GoGitClient) stashRef(hash string) (string, bool, error) {
+	output, err := c.runGit(c.path, "stash", "list", "--format=%H")
+	if err != nil {
+		return "", false, err
+	}
+	for i, stash := range strings.Fields(string(output)) {
+		if stash == hash {
+			return fmt.Sprintf("stash@{%d}", i), true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// PushStash stashes the local changes with the given message, untracked
+// files too with untracked, and returns the hash of the stash
+func (c *GoGitClient) PushStash(message string, untracked bool) (string, error) {
+	return c.pushStash(message, untracked, nil)
+}
+
+// StashUntracked stashes the given untracked files alone, removing them
+// from the worktree, and returns the hash of the stash
+func (c *GoGitClient) StashUntracked(message string, paths []string) (string, error) {
+	if len(paths) == 0 {
+		return "", fmt.Errorf("no untracked files to stash")
+	}
+	return c.pushStash(message, true, paths)
+}
+
+// pushStash stashes the local changes to the given paths, all of them when
+// there are none
+func (c *GoGitClient) pushStash(message string, untracked bool, paths []string) (string, error) {
+	if c.repo == nil {
+		return "", fmt.Errorf("repository not opened")
+	}
+
+	args := []string{"stash", "push", "--quiet"}
+	if untracked {
+		args = append(args, "--include-untracked")
+	}
+	if message != "" {
+		args = append(args, "--message", message)
+	}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+
+	before, _ := c.runGit(c.path, "rev-parse", "--verify", "--quiet", "refs/stash")
+	if _, err := c.runGit(c.path, args...); err != nil {
+		return "", err
+	}
+	after, err := c.runGit(c.path, "rev-parse", "--verify", "--quiet", "refs/stash")
+	if err != nil || string(after) == string(before) {
+		return "", fmt.Errorf("no local changes to stash")
+	}
+	return strings.TrimSpace(string(after)), nil
+}
+
+// ApplyStash applies the stash with the given hash to the worktree,
+// keeping it. Conflicts are left in the worktree.
+func (c *GoGitClient) ApplyStash(hash string) error {
+	if c.repo == nil {
+		return fmt.Errorf("repository not opened")
+	}
+	if err := validateRevRange(hash); err != nil {
+		return err
+	}
+	_, err := c.runGit(c.path, "stash", "apply", "--quiet", hash)
+	return err
+}
+
+// DropStash drops the stash with the given hash
+func (c *GoGitClient) DropStash(hash string) error {
+	if c.repo == nil {
+		return fmt.Errorf("repository not opened")
+	}
+	ref, found, err := c.stashRef(hash)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no stash %s", hash)
+	}
+	_, err = c.runGit(c.path, "stash", "drop", "--quiet", ref)
+	return err
+}
@@ -0,0 +1,71 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStashSubject(t *testing.T) {
+	branch, message := parseStashSubject("WIP on main: abc1234 base")
+	assert.Equal(t, "main", branch)
+	assert.Equal(t, "abc1234 base", message)
+
+	branch, message = parseStashSubject("On feature/x: my: message")
+	assert.Equal(t, "feature/x", branch)
+	assert.Equal(t, "my: message", message)
+
+	branch, message = parseStashSubject("autostash")
+	assert.Empty(t, branch)
+	assert.Equal(t, "autostash", message)
+}
+
+func TestStashes(t *testing.T) {
+	dir := newTestRepo(t)
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	stashes, err := client.GetStashes()
+	require.NoError(t, err)
+	assert.Empty(t, stashes)
+
+	writeTestFile(t, dir, "file.txt", "first\n")
+	first, err := client.PushStash("", false)
+	require.NoError(t, err)
+
+	// Untracked files are only stashed when asked to
+	writeTestFile(t, dir, "file.txt", "second\n")
+	writeTestFile(t, dir, "new.txt", "new\n")
+	second, err := client.PushStash("with new file", true)
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "new.txt"))
+	assert.True(t, os.IsNotExist(err))
+
+	stashes, err = client.GetStashes()
+	require.NoError(t, err)
+	require.Len(t, stashes, 2)
+	assert.Equal(t, 0, stashes[0].Index)
+	assert.Equal(t, second, stashes[0].Commit.Hash)
+	assert.Equal(t, "main", stashes[0].Branch)
+	assert.Equal(t, "with new file", stashes[0].Message)
+	assert.Equal(t, "Test User", stashes[0].Commit.Author.Name)
+	assert.Len(t, stashes[0].Commit.Parents, 3)
+	assert.Equal(t, 1, stashes[1].Index)
+	assert.Equal(t, first, stashes[1].Commit.Hash)
+	assert.Contains(t, stashes[1].Message, "base")
+
+	// Applying keeps the stash, dropping finds it by its hash
+	require.NoError(t, client.ApplyStash(first))
+	data, err := os.ReadFile(filepath.Join(dir, "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "first\n", string(data))
+	require.NoError(t, client.DropStash(first))
+	stashes, err = client.GetStashes()
+	require.NoError(t, err)
+	require.Len(t, stashes, 1)
+	assert.Equal(t, second, stashes[0].Commit.Hash)
+	assert.ErrorContains(t, client.DropStash(first), "no stash")
+}
@@ -0,0 +1,141 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultStatusDebounce is how long a status is reused without checking the
+// repository for changes
+const DefaultStatusDebounce = 500 * time.Millisecond
+
+// DefaultStatusMaxAge is how long a status is reused while the index and
+// HEAD are unchanged. Files edited in the worktree touch neither, so past
+// it git status is run again, its untracked cache and fsmonitor keeping
+// that cheap when enabled.
+const DefaultStatusMaxAge = 5 * time.Second
+
+// StatusCache keeps the last status of a worktree and only computes it again
+// when the index or HEAD was modified since, or the status got old. Checks
+// following each other closely are debounced and return the cached status.
+type StatusCache struct {
+	Debounce time.Duration
+	MaxAge   time.Duration
+
+	root    string
+	status  *Status
+	stamp   repoStamp
+	loaded  time.Time
+	checked time.Time
+	now     func() time.Time
+}
+
+// repoStamp summarizes the modification times of the index, HEAD and the
+// branch it points to; when it is unchanged nothing was staged, committed
+// or checked out
+type repoStamp struct {
+	index     time.Time
+	indexSize int64
+	head      time.Time
+	headSize  int64
+	branch    time.Time // Loose ref of the branch, or packed-refs
+}
+
+// NewStatusCache creates a status cache for the worktree at root
+func NewStatusCache(root string) *StatusCache {
+	return &StatusCache{
+		Debounce: DefaultStatusDebounce,
+		MaxAge:   DefaultStatusMaxAge,
+		root:     root,
+		now:      time.Now,
+	}
+}
+
+// Root returns the worktree the cache is for
+func (c *StatusCache) Root() string {
+	return c.root
+}
+
+// Get returns the cached status, calling load to compute it when nothing
+// was cached yet, the index or HEAD changed or the status got old
+func (c *StatusCache) Get(load func() (*Status, error)) (*Status, error) {
+	now := c.now()
+	if c.status != nil && now.Sub(c.checked) < c.Debounce {
+		return c.status, nil
+	}
+
+	stamp := stampRepo(findGitDir(c.root))
+	if c.status != nil && stamp == c.stamp && now.Sub(c.loaded) < c.MaxAge {
+		c.checked = now
+		return c.status, nil
+	}
+
+	status, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.status = status
+	c.stamp = stamp
+	c.loaded = now
+	c.checked = now
+	return status, nil
+}
+
+// Invalidate forgets the cached status, as done after changing the index
+func (c *StatusCache) Invalidate() {
+	c.status = nil
+}
+
+// stampRepo collects the modification times of the index, HEAD and its
+// branch in a git directory, those missing being left zero
+func stampRepo(gitDir string) repoStamp {
+	var stamp repoStamp
+	if info, err := os.Stat(filepath.Join(gitDir, "index")); err == nil {
+		stamp.index, stamp.indexSize = info.ModTime(), info.Size()
+	}
+	if info, err := os.Stat(filepath.Join(gitDir, "HEAD")); err == nil {
+		stamp.head, stamp.headSize = info.ModTime(), info.Size()
+	}
+
+	// The branch lives in the common directory of linked worktrees
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	ref, ok := strings.CutPrefix(strings.TrimSpace(string(head)), "ref: ")
+	if err != nil || !ok {
+		return stamp
+	}
+	commonDir := gitDir
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir = strings.TrimSpace(string(data))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(commonDir, filepath.FromSlash(ref))); err == nil {
+		stamp.branch = info.ModTime()
+	} else if info, err := os.Stat(filepath.Join(commonDir, "packed-refs")); err == nil {
+		stamp.branch = info.ModTime()
+	}
+	return stamp
+}
+
+// findGitDir returns the git directory of a worktree, following the .git
+// file of linked worktrees and submodules
+func findGitDir(root string) string {
+	dotGit := filepath.Join(root, ".git")
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return dotGit
+	}
+
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return dotGit
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(root, gitDir)
+	}
+	return gitDir
+}
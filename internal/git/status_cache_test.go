@@ -0,0 +1,84 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusCache(t *testing.T) {
+	dir := newTestRepo(t)
+
+	clock := time.Now()
+	cache := NewStatusCache(dir)
+	cache.now = func() time.Time { return clock }
+
+	loads := 0
+	load := func() (*Status, error) {
+		loads++
+		return &Status{Branch: "main"}, nil
+	}
+
+	status, err := cache.Get(load)
+	require.NoError(t, err)
+	assert.Equal(t, "main", status.Branch)
+	assert.Equal(t, 1, loads)
+
+	// Repeated refreshes reuse the status, even after the debounce as long
+	// as the index and HEAD are unchanged
+	_, err = cache.Get(load)
+	require.NoError(t, err)
+	clock = clock.Add(time.Second)
+	_, err = cache.Get(load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, loads)
+
+	// Editing a file touches neither, the status being loaded again once
+	// it got old
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("edited\n"), 0644))
+	_, err = cache.Get(load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, loads)
+	clock = clock.Add(cache.MaxAge)
+	_, err = cache.Get(load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads)
+
+	// Staging is noticed once the debounce passed
+	later := time.Now().Add(time.Minute)
+	gitIn(t, dir, "add", "file.txt")
+	require.NoError(t, os.Chtimes(filepath.Join(dir, ".git", "index"), later, later))
+	_, err = cache.Get(load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads)
+	clock = clock.Add(time.Second)
+	_, err = cache.Get(load)
+	require.NoError(t, err)
+	assert.Equal(t, 3, loads)
+
+	// So is committing, which moves the branch HEAD points to
+	clock = clock.Add(time.Second)
+	branch := filepath.Join(dir, ".git", "refs", "heads", "main")
+	require.NoError(t, os.Chtimes(branch, later, later))
+	_, err = cache.Get(load)
+	require.NoError(t, err)
+	assert.Equal(t, 4, loads)
+
+	// Staging changes invalidates the cache explicitly
+	cache.Invalidate()
+	_, err = cache.Get(load)
+	require.NoError(t, err)
+	assert.Equal(t, 5, loads)
+}
+
+func TestFindGitDir(t *testing.T) {
+	dir := t.TempDir()
+	assert.Equal(t, filepath.Join(dir, ".git"), findGitDir(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git"), []byte("gitdir: ../main/.git/worktrees/wt\n"), 0644))
+	assert.Equal(t, filepath.Join(dir, "../main/.git/worktrees/wt"), findGitDir(dir))
+}
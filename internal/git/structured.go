@@ -0,0 +1,134 @@
+package git
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// structuredNormalizers turn the contents of structured files into text
+// whose diffs tell what changed, by structured diff mode
+var structuredNormalizers = map[string]func(data []byte) ([]byte, error){
+	"json":     NormalizeJSON,
+	"notebook": NormalizeNotebook,
+}
+
+// NormalizeStructured normalizes the contents of a file with a structured
+// diff mode. Empty contents, those of a missing side, stay empty.
+func NormalizeStructured(mode string, data []byte) ([]byte, error) {
+	normalize, ok := structuredNormalizers[mode]
+	if !ok {
+		return nil, fmt.Errorf("unknown structured diff mode: %s", mode)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	return normalize(data)
+}
+
+// decodeJSON decodes a single JSON value, keeping numbers as they are
+// written
+func decodeJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return value, nil
+}
+
+// encodeJSON writes a JSON value indented by two spaces, with the keys of
+// objects sorted
+func encodeJSON(value any) ([]byte, error) {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// NormalizeJSON rewrites a JSON document with its keys sorted and a
+// consistent indentation, so that diffs only show changes of values
+func NormalizeJSON(data []byte) ([]byte, error) {
+	value, err := decodeJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return encodeJSON(value)
+}
+
+// NormalizeNotebook normalizes a Jupyter notebook as NormalizeJSON does,
+// dropping the outputs and execution counts of its cells, which change
+// whenever it is run
+func NormalizeNotebook(data []byte) ([]byte, error) {
+	value, err := decodeJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	notebook, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("not a notebook")
+	}
+	cells, _ := notebook["cells"].([]any)
+	for _, cell := range cells {
+		if cell, ok := cell.(map[string]any); ok {
+			if _, ok := cell["outputs"]; ok {
+				cell["outputs"] = []any{}
+			}
+			if _, ok := cell["execution_count"]; ok {
+				cell["execution_count"] = nil
+			}
+		}
+	}
+	return encodeJSON(notebook)
+}
+
+// DiffTexts returns the hunks turning a text into another, as git diff
+// shows them
+func DiffTexts(old, new []byte) ([]*DiffHunk, error) {
+	dir, err := os.MkdirTemp("", "tig-diff-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	if err := os.WriteFile(filepath.Join(dir, "old"), old, 0600); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "new"), new, 0600); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("git", "diff", "--no-index", "--no-color", "--no-ext-diff", "--no-textconv", "--", "old", "new")
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	// git diff --no-index exits with 1 when the files differ
+	output, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if err != nil && (!errors.As(err, &exitErr) || exitErr.ExitCode() != 1) {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git diff: %s", msg)
+		}
+		return nil, fmt.Errorf("git diff: %w", err)
+	}
+
+	diff, err := ParseDiff(string(output))
+	if err != nil || len(diff.Files) == 0 {
+		return nil, err
+	}
+	return diff.Files[0].Hunks, nil
+}
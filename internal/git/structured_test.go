@@ -0,0 +1,75 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeStructured(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		data     string
+		expected string
+		err      bool
+	}{
+		{
+			name:     "json keys sorted and indented",
+			mode:     "json",
+			data:     `{"b": [1,2], "a": {"y": 1.50, "x": "<tag>"}}`,
+			expected: "{\n  \"a\": {\n    \"x\": \"<tag>\",\n    \"y\": 1.50\n  },\n  \"b\": [\n    1,\n    2\n  ]\n}\n",
+		},
+		{
+			name: "invalid json",
+			mode: "json",
+			data: `{"a": 1} trailing`,
+			err:  true,
+		},
+		{
+			name:     "missing side",
+			mode:     "json",
+			data:     "",
+			expected: "",
+		},
+		{
+			name:     "notebook outputs stripped",
+			mode:     "notebook",
+			data:     `{"cells": [{"cell_type": "code", "execution_count": 3, "outputs": [{"text": "42"}], "source": ["6 * 7"]}], "nbformat": 4}`,
+			expected: "{\n  \"cells\": [\n    {\n      \"cell_type\": \"code\",\n      \"execution_count\": null,\n      \"outputs\": [],\n      \"source\": [\n        \"6 * 7\"\n      ]\n    }\n  ],\n  \"nbformat\": 4\n}\n",
+		},
+		{
+			name: "not a notebook",
+			mode: "notebook",
+			data: `[1, 2]`,
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NormalizeStructured(tt.mode, []byte(tt.data))
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(result))
+		})
+	}
+}
+
+func TestDiffTexts(t *testing.T) {
+	hunks, err := DiffTexts([]byte("a\nb\nc\n"), []byte("a\nB\nc\n"))
+	require.NoError(t, err)
+	require.Len(t, hunks, 1)
+	assert.Equal(t, 1, hunks[0].OldStart)
+	require.Len(t, hunks[0].Lines, 4)
+	assert.Equal(t, DiffLineDeletion, hunks[0].Lines[1].Type)
+	assert.Equal(t, "B", hunks[0].Lines[2].Content)
+
+	hunks, err = DiffTexts([]byte("same\n"), []byte("same\n"))
+	require.NoError(t, err)
+	assert.Empty(t, hunks)
+}
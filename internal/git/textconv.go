@@ -0,0 +1,66 @@
+package git
+
+import (
+	"strings"
+)
+
+// parseDiff parses the output of git diff, marking the files whose contents
+// were converted to text by the textconv command of their diff driver
+func (c *GoGitClient) parseDiff(output []byte) (*Diff, error) {
+	diff, err := ParseDiff(string(output))
+	if err != nil {
+		return nil, err
+	}
+	c.markTextconv(diff)
+	return diff, nil
+}
+
+// markTextconv sets the textconv command of the files of a diff whose diff
+// driver, set by the diff attribute of .gitattributes, has one configured.
+// Files are left unmarked when git fails to tell their attributes.
+func (c *GoGitClient) markTextconv(diff *Diff) {
+	var paths []string
+	for _, file := range diff.Files {
+		paths = append(paths, file.Path())
+	}
+	if len(paths) == 0 {
+		return
+	}
+
+	drivers, err := c.diffDrivers(paths)
+	if err != nil {
+		return
+	}
+	commands := make(map[string]string)
+	for _, file := range diff.Files {
+		driver := drivers[file.Path()]
+		if driver == "" {
+			continue
+		}
+		if _, ok := commands[driver]; !ok {
+			commands[driver] = c.ConfigValue("diff." + driver + ".textconv")
+		}
+		file.Textconv = commands[driver]
+	}
+}
+
+// diffDrivers returns the diff drivers the diff attribute of the paths
+// names, leaving out paths diffed as text or binary by git itself
+func (c *GoGitClient) diffDrivers(paths []string) (map[string]string, error) {
+	output, err := c.runGit(c.path, append([]string{"check-attr", "-z", "diff", "--"}, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	// The output is made of path, attribute and value triples
+	drivers := make(map[string]string)
+	fields := strings.Split(string(output), "\x00")
+	for i := 0; i+2 < len(fields); i += 3 {
+		switch value := fields[i+2]; value {
+		case "unspecified", "set", "unset":
+		default:
+			drivers[fields[i]] = value
+		}
+	}
+	return drivers, nil
+}
@@ -0,0 +1,43 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextconvDiffs(t *testing.T) {
+	dir := newTestRepo(t)
+	writeTestFile(t, dir, ".gitattributes", "*.bin diff=octal\n*.dat diff=plain\n")
+	writeTestFile(t, dir, "file.bin", "ab\x00cd")
+	writeTestFile(t, dir, "file.dat", "one\n")
+	gitIn(t, dir, "config", "diff.octal.textconv", "od -An -c")
+	gitIn(t, dir, "add", ".")
+	gitIn(t, dir, "commit", "--quiet", "-m", "files")
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	// Binary files are diffed as their textconv output
+	writeTestFile(t, dir, "file.bin", "ab\x00ce")
+	diff, err := client.GetDiff("file.bin")
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	assert.Equal(t, "od -An -c", diff.Files[0].Textconv)
+	assert.False(t, diff.Files[0].IsBinary)
+	require.Len(t, diff.Files[0].Hunks, 1)
+	assert.Contains(t, diff.Files[0].Hunks[0].Lines[1].Content, "c   e")
+
+	// while drivers without textconv leave files as they are
+	writeTestFile(t, dir, "file.dat", "two\n")
+	gitIn(t, dir, "add", ".")
+	gitIn(t, dir, "commit", "--quiet", "-m", "changes")
+	diff, err = client.GetCommitDiff("HEAD", nil)
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 2)
+	textconv := map[string]string{}
+	for _, file := range diff.Files {
+		textconv[file.Path()] = file.Textconv
+	}
+	assert.Equal(t, map[string]string{"file.bin": "od -An -c", "file.dat": ""}, textconv)
+}
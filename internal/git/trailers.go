@@ -0,0 +1,99 @@
+package git
+
+import (
+	"net/mail"
+	"strings"
+)
+
+// Trailer is a "Key: value" line of the last paragraph of a commit
+// message, such as Signed-off-by or Co-authored-by
+type Trailer struct {
+	Key   string
+	Value string
+}
+
+// SplitTrailers separates the trailers ending a commit message from the
+// rest of it. Like git interpret-trailers, only a last paragraph made of
+// trailers alone counts, continuation lines being folded into the trailer
+// they indent. The summary line never holds trailers.
+func SplitTrailers(message string) (string, []Trailer) {
+	message = strings.TrimRight(message, " \t\n")
+	start := strings.LastIndex(message, "\n\n")
+	if start < 0 {
+		return message, nil
+	}
+
+	var trailers []Trailer
+	for _, line := range strings.Split(message[start+2:], "\n") {
+		if line != "" && (line[0] == ' ' || line[0] == '\t') && len(trailers) > 0 {
+			trailers[len(trailers)-1].Value += " " + strings.TrimSpace(line)
+			continue
+		}
+		trailer, ok := parseTrailer(line)
+		if !ok {
+			return message, nil
+		}
+		trailers = append(trailers, trailer)
+	}
+	return strings.TrimRight(message[:start], "\n"), trailers
+}
+
+// parseTrailer parses a single trailer line, whose key is made of letters,
+// digits and dashes
+func parseTrailer(line string) (Trailer, bool) {
+	key, value, ok := strings.Cut(line, ":")
+	if !ok || key == "" {
+		return Trailer{}, false
+	}
+	for _, r := range key {
+		if !(r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return Trailer{}, false
+		}
+	}
+	return Trailer{Key: key, Value: strings.TrimSpace(value)}, true
+}
+
+// parseLogTrailers parses the unfolded trailers written by git log for
+// %(trailers:unfold,only,separator=%x01)
+func parseLogTrailers(text string) []Trailer {
+	var trailers []Trailer
+	for _, line := range strings.Split(text, "\x01") {
+		if trailer, ok := parseTrailer(line); ok {
+			trailers = append(trailers, trailer)
+		}
+	}
+	return trailers
+}
+
+// Trailer returns the values of the trailers with the given key, compared
+// without regard to case
+func (c *Commit) Trailer(key string) []string {
+	var values []string
+	for _, trailer := range c.Trailers {
+		if strings.EqualFold(trailer.Key, key) {
+			values = append(values, trailer.Value)
+		}
+	}
+	return values
+}
+
+// CoAuthors returns the people credited by Co-authored-by trailers, other
+// than the author. Values which are not "Name <email>" are kept as names.
+func (c *Commit) CoAuthors() []Signature {
+	var coAuthors []Signature
+	for _, value := range c.Trailer("Co-authored-by") {
+		signature := Signature{Name: value}
+		if address, err := mail.ParseAddress(value); err == nil {
+			signature = Signature{Name: address.Name, Email: address.Address}
+			if signature.Name == "" {
+				signature.Name = address.Address
+			}
+		}
+		if signature.Email != "" && strings.EqualFold(signature.Email, c.Author.Email) {
+			continue
+		}
+		signature.Time = c.Author.Time
+		coAuthors = append(coAuthors, signature)
+	}
+	return coAuthors
+}
@@ -0,0 +1,69 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitTrailers(t *testing.T) {
+	message := "Fix the parser\n\nIt choked on: colons.\n\n" +
+		"Signed-off-by: A U Thor <a@example.com>\n" +
+		"Co-authored-by: Other Person\n  <other@example.com>\n" +
+		"Fixes: #12\n"
+	body, trailers := SplitTrailers(message)
+	assert.Equal(t, "Fix the parser\n\nIt choked on: colons.", body)
+	assert.Equal(t, []Trailer{
+		{Key: "Signed-off-by", Value: "A U Thor <a@example.com>"},
+		{Key: "Co-authored-by", Value: "Other Person <other@example.com>"},
+		{Key: "Fixes", Value: "#12"},
+	}, trailers)
+
+	// A last paragraph with prose in it holds no trailers
+	for _, message := range []string{
+		"Summary: only",
+		"Fix\n\nSee: the docs\nfor details",
+		"Fix\n\nNot a key: value",
+	} {
+		body, trailers := SplitTrailers(message)
+		assert.Equal(t, message, body)
+		assert.Empty(t, trailers, message)
+	}
+}
+
+func TestCommitCoAuthors(t *testing.T) {
+	commit := &Commit{
+		Author: Signature{Name: "A U Thor", Email: "a@example.com"},
+		Trailers: []Trailer{
+			{Key: "Co-authored-by", Value: "Other Person <other@example.com>"},
+			{Key: "co-authored-by", Value: "Just A Name"},
+			{Key: "Co-authored-by", Value: "Thor <A@example.com>"},
+			{Key: "Reviewed-by", Value: "Reviewer <r@example.com>"},
+		},
+	}
+	assert.Equal(t, []Signature{
+		{Name: "Other Person", Email: "other@example.com"},
+		{Name: "Just A Name"},
+	}, commit.CoAuthors())
+	assert.Equal(t, []string{"Reviewer <r@example.com>"}, commit.Trailer("reviewed-by"))
+}
+
+func TestLogCommitsTrailers(t *testing.T) {
+	dir := newTestRepo(t)
+	writeTestFile(t, dir, "file.txt", "paired\n")
+	gitIn(t, dir, "commit", "--quiet", "-am", "pair\n\nCo-authored-by: Other <other@example.com>")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	commits, err := client.FileLog("file.txt", 0)
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	assert.Empty(t, commits[1].Trailers)
+	assert.Equal(t, []Trailer{{Key: "Co-authored-by", Value: "Other <other@example.com>"}}, commits[0].Trailers)
+
+	commit, err := client.GetCommit(commits[0].Hash)
+	require.NoError(t, err)
+	assert.Equal(t, commits[0].Trailers, commit.Trailers)
+}
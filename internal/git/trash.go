@@ -0,0 +1,180 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// trashRef is the ref the content of discarded files is kept under. Each
+// discard is a commit on top of the previous one holding the file as it
+// was, and the reflog of the ref keeps track of them all.
+const trashRef = "refs/tig/trash"
+
+// trashLimit is the number of discards the trash holds. Past it, the older
+// half is dropped, so that the trash does not grow forever.
+var trashLimit = 100
+
+// trashIdentity is the author and committer of the trash commits, which
+// are no one's work and have to be made without an identity set up
+var trashIdentity = []string{
+	"GIT_AUTHOR_NAME=tig", "GIT_AUTHOR_EMAIL=tig@localhost",
+	"GIT_COMMITTER_NAME=tig", "GIT_COMMITTER_EMAIL=tig@localhost",
+}
+
+// trashFile keeps the content of a file of the worktree in the trash before
+// its changes are discarded. Files missing from the worktree have no
+// content to lose, and neither have symbolic links and directories which
+// git checks out from their target alone.
+func (c *GoGitClient) trashFile(path string) error {
+	info, err := os.Lstat(filepath.Join(c.path, path))
+	if os.IsNotExist(err) || (err == nil && !info.Mode().IsRegular()) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	blob, err := c.runGit(c.path, "hash-object", "-w", "--", path)
+	if err != nil {
+		return err
+	}
+	mode := "100644"
+	if info.Mode()&0111 != 0 {
+		mode = "100755"
+	}
+
+	// The file is put alone in a tree through an index of its own, leaving
+	// the one of the repository alone
+	dir, err := os.MkdirTemp("", "tig-trash")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	index := []string{"GIT_INDEX_FILE=" + filepath.Join(dir, "index")}
+	cacheInfo := fmt.Sprintf("%s,%s,%s", mode, strings.TrimSpace(string(blob)), path)
+	if _, err := c.runGitEnv(c.path, index, "update-index", "--add", "--cacheinfo", cacheInfo); err != nil {
+		return err
+	}
+	tree, err := c.runGitEnv(c.path, index, "write-tree")
+	if err != nil {
+		return err
+	}
+
+	message := "discard " + path
+	args := []string{"commit-tree", strings.TrimSpace(string(tree)), "-m", message}
+	previous, err := c.pruneTrash()
+	if err != nil {
+		return err
+	}
+	if previous != "" {
+		args = append(args, "-p", previous)
+	}
+	commit, err := c.runGitEnv(c.path, trashIdentity, args...)
+	if err != nil {
+		return err
+	}
+	_, err = c.runGit(c.path, "update-ref", "--create-reflog", "-m", message, trashRef, strings.TrimSpace(string(commit)))
+	return err
+}
+
+// pruneTrash returns the last commit of the trash, the one the next discard
+// goes on top of, or nothing when the trash is empty. A full trash is first
+// made again out of its newest discards alone, and its reflog, which would
+// otherwise keep the dropped ones alive, starts over.
+func (c *GoGitClient) pruneTrash() (string, error) {
+	head, err := c.runGit(c.path, "rev-parse", "--verify", "--quiet", trashRef)
+	if err != nil {
+		return "", nil
+	}
+	previous := strings.TrimSpace(string(head))
+	count, err := c.runGit(c.path, "rev-list", "--count", trashRef)
+	if err != nil {
+		return "", err
+	}
+	if n, _ := strconv.Atoi(strings.TrimSpace(string(count))); n < trashLimit {
+		return previous, nil
+	}
+
+	keep := trashLimit / 2
+	out, err := c.runGit(c.path, "log", "-z", "--reverse", "--format=%T %s", fmt.Sprintf("-%d", keep), trashRef)
+	if err != nil {
+		return "", err
+	}
+	parent := ""
+	for _, entry := range strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00") {
+		tree, message, _ := strings.Cut(entry, " ")
+		args := []string{"commit-tree", tree, "-m", message}
+		if parent != "" {
+			args = append(args, "-p", parent)
+		}
+		commit, err := c.runGitEnv(c.path, trashIdentity, args...)
+		if err != nil {
+			return "", err
+		}
+		parent = strings.TrimSpace(string(commit))
+	}
+	if _, err := c.runGit(c.path, "update-ref", "-d", trashRef, previous); err != nil {
+		return "", err
+	}
+	return parent, nil
+}
+
+// UndoDiscard writes back the file whose changes were discarded last, as
+// it was then, and takes it out of the trash. It returns the path of the
+// file. A file changed again since is left alone, not to lose these
+// changes in turn.
+func (c *GoGitClient) UndoDiscard() (string, error) {
+	if c.repo == nil {
+		return "", fmt.Errorf("repository not opened")
+	}
+
+	head, err := c.runGit(c.path, "rev-parse", "--verify", "--quiet", trashRef)
+	if err != nil {
+		return "", fmt.Errorf("no discarded changes to restore")
+	}
+	commit := strings.TrimSpace(string(head))
+
+	entry, err := c.runGit(c.path, "ls-tree", "-r", "-z", commit)
+	if err != nil {
+		return "", err
+	}
+	info, path, ok := strings.Cut(strings.TrimSuffix(string(entry), "\x00"), "\t")
+	if !ok {
+		return "", fmt.Errorf("malformed trash commit %s", commit)
+	}
+
+	// diff --quiet exits with 1 when the file has unstaged changes
+	if _, err := c.runGit(c.path, "diff", "--quiet", "--", path); err != nil {
+		return "", fmt.Errorf("%s has changed since its changes were discarded", path)
+	}
+
+	// The content is checked out as git would, through the filters
+	content, err := c.runGit(c.path, "cat-file", "--filters", commit+":"+path)
+	if err != nil {
+		return "", err
+	}
+	perm := os.FileMode(0644)
+	if strings.HasPrefix(info, "100755") {
+		perm = 0755
+	}
+	full := filepath.Join(c.path, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(full, content, perm); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(full, perm); err != nil {
+		return "", err
+	}
+
+	message := "undo discard " + path
+	if parent, err := c.runGit(c.path, "rev-parse", "--verify", "--quiet", commit+"^"); err == nil {
+		_, err = c.runGit(c.path, "update-ref", "-m", message, trashRef, strings.TrimSpace(string(parent)), commit)
+		return path, err
+	}
+	_, err = c.runGit(c.path, "update-ref", "-d", trashRef, commit)
+	return path, err
+}
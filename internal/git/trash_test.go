@@ -0,0 +1,94 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUndoDiscard(t *testing.T) {
+	dir := newTestRepo(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	writeTestFile(t, dir, "sub/other.txt", "other\n")
+	gitIn(t, dir, "add", ".")
+	gitIn(t, dir, "commit", "--quiet", "-m", "other")
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	_, err := client.UndoDiscard()
+	assert.ErrorContains(t, err, "no discarded changes")
+
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	// Discards are undone the last first
+	writeTestFile(t, dir, "file.txt", "mine\n")
+	require.NoError(t, client.DiscardChanges("file.txt"))
+	assert.Equal(t, "base\n", read("file.txt"))
+	writeTestFile(t, dir, "sub/other.txt", "changed\n")
+	require.NoError(t, os.Chmod(filepath.Join(dir, "sub/other.txt"), 0755))
+	require.NoError(t, client.DiscardChanges("sub/other.txt"))
+	assert.Equal(t, "other\n", read("sub/other.txt"))
+	assert.Contains(t, gitIn(t, dir, "reflog", "show", trashRef), "discard sub/other.txt")
+
+	path, err := client.UndoDiscard()
+	require.NoError(t, err)
+	assert.Equal(t, "sub/other.txt", path)
+	assert.Equal(t, "changed\n", read("sub/other.txt"))
+	info, err := os.Stat(filepath.Join(dir, "sub/other.txt"))
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode()&0100)
+
+	// A file changed again is not overwritten
+	writeTestFile(t, dir, "file.txt", "newer\n")
+	_, err = client.UndoDiscard()
+	assert.ErrorContains(t, err, "has changed since")
+	assert.Equal(t, "newer\n", read("file.txt"))
+
+	gitIn(t, dir, "checkout", "--", "file.txt")
+	path, err = client.UndoDiscard()
+	require.NoError(t, err)
+	assert.Equal(t, "file.txt", path)
+	assert.Equal(t, "mine\n", read("file.txt"))
+	_, err = client.UndoDiscard()
+	assert.ErrorContains(t, err, "no discarded changes")
+
+	// Deleted files have nothing to keep
+	require.NoError(t, os.Remove(filepath.Join(dir, "file.txt")))
+	require.NoError(t, client.DiscardChanges("file.txt"))
+	assert.Equal(t, "base\n", read("file.txt"))
+	_, err = client.UndoDiscard()
+	assert.ErrorContains(t, err, "no discarded changes")
+}
+
+func TestTrashLimit(t *testing.T) {
+	saved := trashLimit
+	trashLimit = 4
+	defer func() { trashLimit = saved }()
+
+	dir := newTestRepo(t)
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	for _, content := range []string{"1\n", "2\n", "3\n", "4\n", "5\n"} {
+		writeTestFile(t, dir, "file.txt", content)
+		require.NoError(t, client.DiscardChanges("file.txt"))
+	}
+
+	// The trash was made again of its newest half before the last discard
+	assert.Equal(t, "3", gitIn(t, dir, "rev-list", "--count", trashRef))
+	assert.Equal(t, "5", gitIn(t, dir, "cat-file", "-p", trashRef+":file.txt"))
+	assert.Equal(t, "3", gitIn(t, dir, "cat-file", "-p", trashRef+"~2:file.txt"))
+	assert.Equal(t, "discard file.txt", gitIn(t, dir, "log", "-1", "--format=%s", trashRef+"~2"))
+	assert.NotContains(t, gitIn(t, dir, "reflog", "show", trashRef), "\n")
+
+	path, err := client.UndoDiscard()
+	require.NoError(t, err)
+	assert.Equal(t, "file.txt", path)
+}
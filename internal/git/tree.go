@@ -0,0 +1,238 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+)
+
+// binarySniffLen is how much of the start of a file is looked at for a
+// NUL, as git does to tell binary files
+const binarySniffLen = 8000
+
+// LooksBinary returns whether content is binary as git tells it, by a NUL
+// early in it
+func LooksBinary(content []byte) bool {
+	return bytes.IndexByte(content[:min(len(content), binarySniffLen)], 0) >= 0
+}
+
+// GetTree returns the files and directories of the tree of a revision
+// directly in the given path, relative to the repository root. Their paths
+// are the names within the path, as with GetFiles.
+func (c *GoGitClient) GetTree(rev, path string) ([]*File, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+	if err := validateRevRange(rev); err != nil {
+		return nil, err
+	}
+
+	prefix := strings.Trim(path, "/")
+	args := []string{"ls-tree", "-z", "--long", "--full-name", rev}
+	if prefix != "" {
+		args = append(args, "--", prefix+"/")
+		prefix += "/"
+	}
+	output, err := c.runGit(c.path, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tree of %s: %w", rev, err)
+	}
+
+	files := []*File{}
+	blobs := make(map[string][]*File)
+	for _, entry := range strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00") {
+		// <mode> <type> <hash> <size>\t<path>
+		info, fullPath, ok := strings.Cut(entry, "\t")
+		fields := strings.Fields(info)
+		if !ok || len(fields) != 4 || !strings.HasPrefix(fullPath, prefix) {
+			continue
+		}
+
+		file := &File{Path: strings.TrimPrefix(fullPath, prefix)}
+		switch fields[1] {
+		case "tree":
+			file.IsDir = true
+			file.Mode = os.ModeDir | 0755
+		default:
+			if mode, err := filemode.New(fields[0]); err == nil {
+				file.Mode, _ = mode.ToOSFileMode()
+			}
+			// Submodules have no size
+			file.Size, _ = strconv.ParseInt(fields[3], 10, 64)
+		}
+		if fields[1] == "blob" && file.Mode&os.ModeSymlink == 0 {
+			blobs[fields[2]] = append(blobs[fields[2]], file)
+		}
+		files = append(files, file)
+	}
+
+	if err := c.markBinary(blobs); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// markBinary marks the files of the binary ones of the given blobs
+func (c *GoGitClient) markBinary(blobs map[string][]*File) error {
+	binary, err := c.binaryBlobs(blobs)
+	if err != nil {
+		return err
+	}
+	for hash, files := range blobs {
+		for _, file := range files {
+			file.IsBinary = binary[hash]
+		}
+	}
+	return nil
+}
+
+// binaryCache remembers which blobs are binary, which never changes for a
+// blob once found
+type binaryCache struct {
+	mutex  sync.Mutex
+	binary map[string]bool
+}
+
+// reset forgets the blobs, as done when another repository is opened
+func (b *binaryCache) reset() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.binary = nil
+}
+
+// binaryBlobs returns which of the given blobs are binary. Only the start
+// of each is read: blobs no larger than what is looked at are read whole
+// in a single git cat-file, and larger ones through BlobHead. Blobs seen
+// before are not read again.
+func (c *GoGitClient) binaryBlobs(blobs map[string][]*File) (map[string]bool, error) {
+	binary := make(map[string]bool)
+	var unknown []string
+	c.binaries.mutex.Lock()
+	for hash := range blobs {
+		if known, ok := c.binaries.binary[hash]; ok {
+			binary[hash] = known
+		} else {
+			unknown = append(unknown, hash)
+		}
+	}
+	c.binaries.mutex.Unlock()
+	if len(unknown) == 0 {
+		return binary, nil
+	}
+
+	sizes, err := c.objectSizes(unknown)
+	if err != nil {
+		return nil, err
+	}
+	var small []string
+	for hash, size := range sizes {
+		if size <= binarySniffLen {
+			small = append(small, hash)
+			continue
+		}
+		head, _, err := c.BlobHead(hash, binarySniffLen)
+		if err != nil {
+			return nil, err
+		}
+		binary[hash] = LooksBinary(head)
+	}
+	if err := c.sniffBlobs(small, binary); err != nil {
+		return nil, err
+	}
+
+	c.binaries.mutex.Lock()
+	defer c.binaries.mutex.Unlock()
+	if c.binaries.binary == nil {
+		c.binaries.binary = make(map[string]bool)
+	}
+	for _, hash := range unknown {
+		if known, ok := binary[hash]; ok {
+			c.binaries.binary[hash] = known
+		}
+	}
+	return binary, nil
+}
+
+// objectSizes returns the sizes of the given objects, those missing being
+// left out, with a single git cat-file --batch-check
+func (c *GoGitClient) objectSizes(hashes []string) (map[string]int64, error) {
+	cmd := exec.Command("git", "cat-file", "--batch-check")
+	cmd.Dir = c.path
+	cmd.Stdin = strings.NewReader(strings.Join(hashes, "\n") + "\n")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file: %w", err)
+	}
+
+	sizes := make(map[string]int64)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		// <hash> <type> <size>, or <hash> missing
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("git cat-file: bad header %q", line)
+		}
+		sizes[fields[0]] = size
+	}
+	return sizes, nil
+}
+
+// sniffBlobs reads the given blobs whole in a single git cat-file and
+// records in binary whether each is binary
+func (c *GoGitClient) sniffBlobs(hashes []string, binary map[string]bool) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+	cmd := exec.Command("git", "cat-file", "--batch")
+	cmd.Dir = c.path
+	cmd.Stdin = strings.NewReader(strings.Join(hashes, "\n") + "\n")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("git cat-file: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("git cat-file: %w", err)
+	}
+	// Waiting alone would block on a git cat-file left writing when the
+	// output is not read to the end
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	reader := bufio.NewReader(stdout)
+	for range hashes {
+		// <hash> <type> <size>, or <hash> missing
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("git cat-file: %w", err)
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("git cat-file: bad header %q", header)
+		}
+
+		// The content and the newline ending it
+		content := make([]byte, size+1)
+		if _, err := io.ReadFull(reader, content); err != nil {
+			return fmt.Errorf("git cat-file: %w", err)
+		}
+		binary[fields[0]] = LooksBinary(content[:size])
+	}
+	return nil
+}
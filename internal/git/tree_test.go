@@ -0,0 +1,76 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTree(t *testing.T) {
+	dir := newTestRepo(t)
+	base := gitIn(t, dir, "rev-parse", "HEAD")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "src"), 0755))
+	writeTestFile(t, dir, "src/code.go", "package main\n")
+	writeTestFile(t, dir, "src/run.sh", "#!/bin/sh\n")
+	require.NoError(t, os.Chmod(filepath.Join(dir, "src", "run.sh"), 0755))
+	writeTestFile(t, dir, "image.bin", "\x89PNG\x00\x01"+strings.Repeat("x", 10000))
+	writeTestFile(t, dir, "icon.bin", "\x00\x01")
+	gitIn(t, dir, "add", ".")
+	gitIn(t, dir, "commit", "--quiet", "-m", "tree")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+	files, err := client.GetTree("HEAD", "")
+	require.NoError(t, err)
+	byName := make(map[string]*File)
+	for _, file := range files {
+		byName[file.Path] = file
+	}
+	require.Len(t, byName, 4)
+	assert.True(t, byName["src"].IsDir)
+	assert.True(t, byName["src"].Mode.IsDir())
+	assert.True(t, byName["image.bin"].IsBinary)
+	assert.True(t, byName["icon.bin"].IsBinary)
+	assert.Equal(t, int64(10006), byName["image.bin"].Size)
+	assert.False(t, byName["file.txt"].IsBinary)
+
+	// Directories are listed by the names within them
+	files, err = client.GetTree("HEAD", "src/")
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "code.go", files[0].Path)
+	assert.Equal(t, int64(13), files[0].Size)
+	assert.Equal(t, "run.sh", files[1].Path)
+	assert.Equal(t, os.FileMode(0755), files[1].Mode.Perm())
+
+	// Older commits have their own tree, unlike the index
+	files, err = client.GetTree(base, "")
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "file.txt", files[0].Path)
+	files, err = client.GetFiles("")
+	require.NoError(t, err)
+	assert.Len(t, files, 4)
+	for _, file := range files {
+		assert.Equal(t, strings.HasSuffix(file.Path, ".bin"), file.IsBinary, file.Path)
+	}
+
+	// Blobs already seen are not read again
+	binaries := &client.(*GoGitClient).binaries
+	assert.Len(t, binaries.binary, 5)
+	binaries.binary[gitIn(t, dir, "rev-parse", "HEAD:file.txt")] = true
+	files, err = client.GetTree("HEAD", "")
+	require.NoError(t, err)
+	for _, file := range files {
+		assert.Equal(t, file.Path != "src", file.IsBinary, file.Path)
+	}
+
+	_, err = client.GetTree("missing", "")
+	assert.Error(t, err)
+	_, err = client.GetTree("--all", "")
+	assert.Error(t, err)
+}
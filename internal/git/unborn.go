@@ -0,0 +1,22 @@
+package git
+
+import (
+	"strings"
+)
+
+// UnbornBranch returns the branch HEAD points to while it has no commit
+// yet, as in a repository just initialized or after git checkout --orphan,
+// and an empty string once it has one or when HEAD is detached
+func (c *GoGitClient) UnbornBranch() string {
+	if c.repo == nil {
+		return ""
+	}
+	if _, err := c.runGit(c.path, "rev-parse", "--verify", "--quiet", "HEAD"); err == nil {
+		return ""
+	}
+	name, err := c.runGit(c.path, "symbolic-ref", "--quiet", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(name)), "refs/heads/")
+}
@@ -0,0 +1,62 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnbornBranch(t *testing.T) {
+	dir := t.TempDir()
+	gitIn(t, dir, "init", "--quiet", "--initial-branch=main")
+	gitIn(t, dir, "config", "user.name", "Test User")
+	gitIn(t, dir, "config", "user.email", "test@example.com")
+	writeTestFile(t, dir, "file.txt", "base\n")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+	assert.Equal(t, "main", client.UnbornBranch())
+
+	// The log of HEAD is empty rather than an error
+	commits, err := client.GetCommits(&LogOptions{MaxCount: 10})
+	require.NoError(t, err)
+	assert.Empty(t, commits)
+	count, err := client.GetLogCount()
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	commits, err = client.FileLog("file.txt", 0)
+	require.NoError(t, err)
+	assert.Empty(t, commits)
+	commits, err = client.FilteredLog(LogFilter{Author: "Test"}, 0)
+	require.NoError(t, err)
+	assert.Empty(t, commits)
+
+	status, err := client.GetStatus()
+	require.NoError(t, err)
+	assert.Equal(t, "main", status.Branch)
+	assert.True(t, status.NoCommits)
+	require.Len(t, status.Untracked, 1)
+	assert.True(t, status.Untracked[0].IsUntracked)
+
+	// Files are staged and unstaged without HEAD
+	require.NoError(t, client.StageAll())
+	require.NoError(t, client.UnstageAll())
+	status, err = client.GetStatus()
+	require.NoError(t, err)
+	assert.Empty(t, status.Staged)
+	require.NoError(t, client.StageFile("file.txt"))
+
+	require.NoError(t, client.Commit("first", nil))
+	assert.Empty(t, client.UnbornBranch())
+	commits, err = client.GetCommits(&LogOptions{MaxCount: 10})
+	require.NoError(t, err)
+	assert.Len(t, commits, 1)
+	status, err = client.GetStatus()
+	require.NoError(t, err)
+	assert.False(t, status.NoCommits)
+
+	// A detached HEAD has no branch to be unborn
+	gitIn(t, dir, "checkout", "--quiet", "--detach")
+	assert.Empty(t, client.UnbornBranch())
+}
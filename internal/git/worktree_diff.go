@@ -0,0 +1,69 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// GetStagedDiff returns the changes staged for the given path
+func (c *GoGitClient) GetStagedDiff(path string) (*Diff, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+
+	output, err := c.runGit(c.path, "diff", "--cached", "--patch", "--textconv", "--color=always", "-M", "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staged diff of %s: %w", path, err)
+	}
+
+	return c.parseDiff(output)
+}
+
+// GetChangeStat returns the per-file line counts of the changes staged, or
+// of those not staged yet
+func (c *GoGitClient) GetChangeStat(staged bool) ([]*FileStat, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+
+	args := []string{"diff", "--numstat", "-z", "-M"}
+	if staged {
+		args = append(args, "--cached")
+	}
+	output, err := c.runGit(c.path, append(args, "--")...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diffstat of the changes: %w", err)
+	}
+
+	return ParseNumstat(output)
+}
+
+// GetUntrackedDiff returns the content of an untracked file as a diff
+// adding all of its lines, as git diff --no-index /dev/null shows it
+func (c *GoGitClient) GetUntrackedDiff(path string) (*Diff, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not opened")
+	}
+
+	cmd := exec.Command("git", "diff", "--no-index", "--patch", "--textconv", "--color=always", "--", "/dev/null", path)
+	cmd.Dir = c.path
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	// git diff --no-index exits with 1 when the files differ, which they
+	// always do here, as well as on errors, which come with a message
+	output, err := cmd.Output()
+	msg := strings.TrimSpace(stderr.String())
+	var exitErr *exec.ExitError
+	if err != nil && (msg != "" || !errors.As(err, &exitErr) || exitErr.ExitCode() != 1) {
+		if msg != "" {
+			return nil, fmt.Errorf("failed to get diff of %s: %s", path, msg)
+		}
+		return nil, fmt.Errorf("failed to get diff of %s: %w", path, err)
+	}
+
+	return c.parseDiff(output)
+}
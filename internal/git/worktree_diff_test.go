@@ -0,0 +1,80 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorktreeDiffs(t *testing.T) {
+	dir := newTestRepo(t)
+	writeTestFile(t, dir, "file.txt", "staged\n")
+	gitIn(t, dir, "add", "file.txt")
+	writeTestFile(t, dir, "file.txt", "unstaged\n")
+	writeTestFile(t, dir, "new.txt", "one\ntwo\n")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	lines := func(diff *Diff) []string {
+		var result []string
+		for _, file := range diff.Files {
+			for _, hunk := range file.Hunks {
+				for _, line := range hunk.Lines {
+					prefix := map[DiffLineType]string{DiffLineAddition: "+", DiffLineDeletion: "-"}[line.Type]
+					result = append(result, prefix+line.Content)
+				}
+			}
+		}
+		return result
+	}
+
+	diff, err := client.GetStagedDiff("file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-base", "+staged"}, lines(diff))
+
+	diff, err = client.GetDiff("file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-staged", "+unstaged"}, lines(diff))
+	assert.NotEmpty(t, diff.Files[0].Hunks[0].Lines[1].Colored, "git colors the added line")
+
+	diff, err = client.GetUntrackedDiff("new.txt")
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	assert.True(t, diff.Files[0].IsNew)
+	assert.Equal(t, "new.txt", diff.Files[0].Path())
+	assert.Equal(t, []string{"+one", "+two"}, lines(diff))
+
+	_, err = client.GetUntrackedDiff("missing.txt")
+	assert.Error(t, err)
+
+	// Unstaging leaves the changes in the worktree
+	require.NoError(t, client.UnstageFile("file.txt"))
+	diff, err = client.GetStagedDiff("file.txt")
+	require.NoError(t, err)
+	assert.Empty(t, diff.Files)
+	diff, err = client.GetDiff("file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-base", "+unstaged"}, lines(diff))
+}
+
+func TestGetChangeStat(t *testing.T) {
+	dir := newTestRepo(t)
+	writeTestFile(t, dir, "file.txt", "staged\nmore\n")
+	gitIn(t, dir, "add", "file.txt")
+	writeTestFile(t, dir, "file.txt", "unstaged\n")
+
+	client := NewClient()
+	require.NoError(t, client.Open(dir))
+
+	stats, err := client.GetChangeStat(true)
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, FileStat{Path: "file.txt", Additions: 2, Deletions: 1}, *stats[0])
+
+	stats, err = client.GetChangeStat(false)
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, FileStat{Path: "file.txt", Additions: 1, Deletions: 2}, *stats[0])
+}
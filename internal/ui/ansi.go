@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// ansiCells turns a line colored with ANSI escape sequences, as printed by
+// git --color=always, into cells. Text outside of any color, and the text
+// following a reset, is drawn in the base style, so colored output looks
+// like the rows rendered natively where git leaves it plain. Escape
+// sequences other than colors and attributes are dropped.
+func ansiCells(text string, base tcell.Style) []cell {
+	cells := make([]cell, 0, len(text))
+	style := base
+	for i := 0; i < len(text); {
+		if text[i] != '\x1b' {
+			end := strings.IndexByte(text[i:], '\x1b')
+			if end < 0 {
+				end = len(text) - i
+			}
+			cells = append(cells, textCells(text[i:i+end], style)...)
+			i += end
+			continue
+		}
+
+		if i+1 >= len(text) || text[i+1] != '[' {
+			i++
+			continue
+		}
+		end := i + 2
+		for end < len(text) && (text[end] < 0x40 || text[end] > 0x7e) {
+			end++
+		}
+		if end < len(text) && text[end] == 'm' {
+			style = applySGR(style, base, text[i+2:end])
+		}
+		i = end + 1
+	}
+	return cells
+}
+
+// applySGR applies the parameters of a Select Graphic Rendition sequence
+// to a style. Resets go back to the base style rather than the terminal
+// defaults.
+func applySGR(style, base tcell.Style, params string) tcell.Style {
+	baseFg, baseBg, _ := base.Decompose()
+
+	var codes []int
+	for _, param := range strings.Split(params, ";") {
+		code, err := strconv.Atoi(param)
+		if err != nil {
+			code = 0 // An empty parameter means 0
+		}
+		codes = append(codes, code)
+	}
+
+	for i := 0; i < len(codes); i++ {
+		switch code := codes[i]; {
+		case code == 0:
+			style = base
+		case code == 1:
+			style = style.Bold(true)
+		case code == 2:
+			style = style.Dim(true)
+		case code == 3:
+			style = style.Italic(true)
+		case code == 4:
+			style = style.Underline(true)
+		case code == 5:
+			style = style.Blink(true)
+		case code == 7:
+			style = style.Reverse(true)
+		case code == 9:
+			style = style.StrikeThrough(true)
+		case code == 22:
+			style = style.Bold(false).Dim(false)
+		case code == 23:
+			style = style.Italic(false)
+		case code == 24:
+			style = style.Underline(false)
+		case code == 25:
+			style = style.Blink(false)
+		case code == 27:
+			style = style.Reverse(false)
+		case code == 29:
+			style = style.StrikeThrough(false)
+		case code >= 30 && code <= 37:
+			style = style.Foreground(tcell.PaletteColor(code - 30))
+		case code >= 90 && code <= 97:
+			style = style.Foreground(tcell.PaletteColor(code - 90 + 8))
+		case code == 39:
+			style = style.Foreground(baseFg)
+		case code >= 40 && code <= 47:
+			style = style.Background(tcell.PaletteColor(code - 40))
+		case code >= 100 && code <= 107:
+			style = style.Background(tcell.PaletteColor(code - 100 + 8))
+		case code == 49:
+			style = style.Background(baseBg)
+		case code == 38 || code == 48:
+			var color tcell.Color
+			color, i = extendedColor(codes, i)
+			if color == tcell.ColorDefault {
+				continue
+			}
+			if code == 38 {
+				style = style.Foreground(color)
+			} else {
+				style = style.Background(color)
+			}
+		}
+	}
+	return style
+}
+
+// extendedColor reads a 256 color or RGB color following the 38 or 48
+// code at index i, returning it along with the index of its last code
+func extendedColor(codes []int, i int) (tcell.Color, int) {
+	switch {
+	case i+2 < len(codes) && codes[i+1] == 5:
+		return tcell.PaletteColor(codes[i+2]), i + 2
+	case i+4 < len(codes) && codes[i+1] == 2:
+		return tcell.NewRGBColor(int32(codes[i+2]), int32(codes[i+3]), int32(codes[i+4])), i + 4
+	}
+	return tcell.ColorDefault, len(codes)
+}
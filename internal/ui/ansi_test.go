@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestANSICells(t *testing.T) {
+	base := tcell.StyleDefault.Foreground(tcell.ColorYellow)
+
+	cells := ansiCells("a\x1b[1;31mb\x1b[22;4mc\x1b[39;44md\x1b[me\x1b[K", base)
+	assert.Equal(t, []cell{
+		{'a', base},
+		{'b', base.Bold(true).Foreground(tcell.ColorMaroon)},
+		{'c', base.Foreground(tcell.ColorMaroon).Underline(true)},
+		{'d', base.Underline(true).Background(tcell.ColorNavy)},
+		{'e', base},
+	}, cells)
+
+	cells = ansiCells("\x1b[38;5;208mx\x1b[48;2;1;2;3my\x1b[97mz", tcell.StyleDefault)
+	assert.Equal(t, []cell{
+		{'x', tcell.StyleDefault.Foreground(tcell.PaletteColor(208))},
+		{'y', tcell.StyleDefault.Foreground(tcell.PaletteColor(208)).Background(tcell.NewRGBColor(1, 2, 3))},
+		{'z', tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.NewRGBColor(1, 2, 3))},
+	}, cells)
+
+	// Unfinished sequences are dropped
+	assert.Equal(t, textCells("ab", base), ansiCells("ab\x1b[3", base))
+}
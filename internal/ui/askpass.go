@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// askPassSocketEnv names, in the environment of the git commands talking to
+// remotes, the socket of the tig prompting for the credentials they ask
+const askPassSocketEnv = "TIG_ASKPASS_SOCKET"
+
+// AskPassHelper returns whether tig was run by git or ssh to prompt for
+// credentials, which RunAskPass does
+func AskPassHelper() bool {
+	return os.Getenv(askPassSocketEnv) != ""
+}
+
+// RunAskPass passes the prompt git or ssh ran tig with to the tig listening
+// on the socket named in the environment and prints what was typed there.
+// It returns the exit status, failing when the prompt was cancelled.
+func RunAskPass(args []string, stdout io.Writer) int {
+	// ssh only tells, such as to touch a security key
+	if os.Getenv("SSH_ASKPASS_PROMPT") == "none" {
+		return 0
+	}
+
+	conn, err := net.Dial("unix", os.Getenv(askPassSocketEnv))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tig: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+	if _, err := io.WriteString(conn, strings.Join(args, " ")); err != nil {
+		fmt.Fprintf(os.Stderr, "tig: %v\n", err)
+		return 1
+	}
+	if unix, ok := conn.(*net.UnixConn); ok {
+		_ = unix.CloseWrite()
+	}
+
+	// Nothing comes back when the prompt is cancelled, a line otherwise
+	answer, err := io.ReadAll(conn)
+	if err != nil || len(answer) == 0 {
+		return 1
+	}
+	if _, err := stdout.Write(answer); err != nil {
+		return 1
+	}
+	return 0
+}
+
+// listenAskPass makes the git commands talking to remotes prompt for the
+// credentials they need in tig, through tig run as askpass helper, which
+// connects to a socket listened to here. The returned function stops
+// listening.
+func (vm *ViewManager) listenAskPass() (func(), error) {
+	program, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "tig-askpass-")
+	if err != nil {
+		return nil, err
+	}
+	socket := filepath.Join(dir, "socket")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go vm.answerAskPass(conn)
+		}
+	}()
+	vm.client.SetAskPass(program, []string{askPassSocketEnv + "=" + socket})
+
+	return func() {
+		vm.client.SetAskPass("", nil)
+		listener.Close()
+		os.RemoveAll(dir)
+	}, nil
+}
+
+// answerAskPass prompts for what the askpass helper connected on conn asks
+// and sends back what is typed
+func (vm *ViewManager) answerAskPass(conn net.Conn) {
+	defer conn.Close()
+	request, err := io.ReadAll(conn)
+	if err != nil {
+		return
+	}
+
+	// ssh explains what it asks on the lines before the question, such as
+	// the fingerprint of an unknown host key
+	lines := strings.Split(strings.TrimSpace(string(request)), "\n")
+	prompt, details := lines[len(lines)-1], lines[:len(lines)-1]
+
+	answers := make(chan string, 1)
+	vm.postJobEvent(true, func() {
+		vm.askInput(prompt, details, secretPrompt(prompt), func(text string, ok bool) {
+			if ok {
+				answers <- text
+			}
+			close(answers)
+		})
+	})
+	if text, ok := <-answers; ok {
+		_, _ = io.WriteString(conn, text+"\n")
+	}
+}
+
+// secretPrompt returns whether what a prompt asks is to be masked as it is
+// typed, all but user names and yes or no answers
+func secretPrompt(prompt string) bool {
+	prompt = strings.ToLower(prompt)
+	return !strings.Contains(prompt, "username") && !strings.Contains(prompt, "yes/no")
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+
+	"github.com/azhao1981/tig/internal/git"
+)
+
+// auditedClient is implemented by clients recording the changes they make
+// to the repository
+type auditedClient interface {
+	AuditLog() *git.AuditLog
+}
+
+// auditLog returns the log changes to the repository are recorded in, nil
+// when they aren't recorded
+func (vm *ViewManager) auditLog() *git.AuditLog {
+	if audited, ok := vm.client.(auditedClient); ok {
+		return audited.AuditLog()
+	}
+	return nil
+}
+
+// recordExternal records an external command run in the repository, which
+// may have changed it without the client knowing
+func (vm *ViewManager) recordExternal(args []string, err error) {
+	if log := vm.auditLog(); log != nil {
+		_ = log.Record(vm.client.GetRootPath(), "!"+args[0], args[1:], "", err)
+	}
+}
+
+// AuditLogCommand handles the :audit-log command, opening the changes made
+// to the repository through tig in the pager
+func (vm *ViewManager) AuditLogCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if len(args) > 0 {
+		return fmt.Errorf("usage: audit-log")
+	}
+	if !vm.client.IsRepository() {
+		return fmt.Errorf("not in a git repository")
+	}
+	log := vm.auditLog()
+	if log == nil {
+		return fmt.Errorf("changes aren't recorded")
+	}
+
+	file, err := os.Open(log.Path(vm.client.GetRootPath()))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("no changes recorded for this repository")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer file.Close()
+
+	pagerCmd := exec.Command("sh", "-c", vm.pagerProgram())
+	pagerCmd.Dir = vm.client.GetRootPath()
+	pagerCmd.Stdin = file
+	pagerCmd.Stdout = os.Stdout
+	pagerCmd.Stderr = os.Stderr
+
+	return vm.suspend(func() error {
+		if err := pagerCmd.Run(); err != nil {
+			return fmt.Errorf("pager failed: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogCommand(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	out := filepath.Join(t.TempDir(), "paged")
+	cfg := &config.Config{}
+	cfg.General.Pager = "cat > " + out
+
+	client := git.NewAuditClient(openTestRepo(t), git.NewAuditLog(t.TempDir()))
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+	vm.SetSize(80, 24)
+	vm.SetRepoPath(client.GetRootPath())
+
+	assert.EqualError(t, vm.AuditLogCommand(nil), "no changes recorded for this repository")
+	assert.Error(t, vm.AuditLogCommand([]string{"extra"}))
+
+	// External commands may change the repository, so they are recorded
+	// with the changes made through the client
+	require.NoError(t, vm.ExternalCommand([]string{"git", "checkout", "--quiet", "-b", "topic"}))
+	assert.Error(t, vm.ExternalCommand([]string{"git", "checkout", "--quiet", "missing"}))
+	require.NoError(t, vm.AuditLogCommand(nil))
+	paged, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Regexp(t, `^\S+\t!git checkout --quiet -b topic\tok\n\S+\t!git checkout --quiet missing\tfailed: git checkout --quiet missing failed: exit status 1\n$`, string(paged))
+
+	// Without an audit log there's nothing to show
+	vm = NewViewManager(screen, cfg, openTestRepo(t), NewKeyBindingManager(cfg))
+	assert.EqualError(t, vm.AuditLogCommand(nil), "changes aren't recorded")
+}
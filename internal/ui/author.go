@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"hash/fnv"
+	"strings"
+	"unicode"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// authorColors are the colors authors are told apart by, chosen to stay
+// readable on both dark and light backgrounds
+var authorColors = []tcell.Color{
+	tcell.ColorRed,
+	tcell.ColorGreen,
+	tcell.ColorOlive,
+	tcell.ColorTeal,
+	tcell.ColorPurple,
+	tcell.ColorFuchsia,
+	tcell.ColorAqua,
+	tcell.ColorOrange,
+	tcell.ColorLime,
+	tcell.ColorNavy,
+	tcell.ColorMaroon,
+	tcell.ColorDarkCyan,
+}
+
+// formatAuthor returns an author as the author-display option asks for:
+// the name, the email or the initials of the name
+func formatAuthor(cfg *config.Config, author git.Signature) string {
+	switch cfg.Git.AuthorDisplay {
+	case "email":
+		if author.Email != "" {
+			return author.Email
+		}
+	case "initials":
+		return initials(author.Name)
+	}
+	return author.Name
+}
+
+// initials abbreviates a name to the first letters of its words
+func initials(name string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-' || r == '.'
+	}) {
+		b.WriteRune(unicode.ToUpper([]rune(word)[0]))
+	}
+	return b.String()
+}
+
+// authorStyle returns the style to draw an author in. With author-colors
+// set each author gets a color of their own, derived from their email so
+// it is the same in every view and session.
+func authorStyle(cfg *config.Config, author git.Signature, style tcell.Style) tcell.Style {
+	if !cfg.Git.AuthorColors {
+		return style
+	}
+	return style.Foreground(authorColor(author))
+}
+
+// authorColor returns the color of an author, derived from their email or
+// their name when there is none
+func authorColor(author git.Signature) tcell.Color {
+	key := strings.ToLower(author.Email)
+	if key == "" {
+		key = author.Name
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return authorColors[h.Sum32()%uint32(len(authorColors))]
+}
+
+// lightAuthorColors are the author colors dark text is readable on
+var lightAuthorColors = map[tcell.Color]bool{
+	tcell.ColorOlive:   true,
+	tcell.ColorFuchsia: true,
+	tcell.ColorAqua:    true,
+	tcell.ColorOrange:  true,
+	tcell.ColorLime:    true,
+}
+
+// avatarInitials returns the two letters standing for an author in the
+// avatar column: the initials of their first and last names, or the first
+// two letters of a single name
+func avatarInitials(name string) string {
+	letters := []rune(initials(name))
+	switch {
+	case len(letters) >= 2:
+		return string([]rune{letters[0], letters[len(letters)-1]})
+	case len(letters) == 1:
+		word := []rune(strings.TrimSpace(name))
+		if len(word) >= 2 && unicode.IsLetter(word[1]) {
+			return string(letters[0]) + string(unicode.ToUpper(word[1]))
+		}
+		return string(letters[0]) + " "
+	}
+	return "??"
+}
+
+// avatarStyle returns the style of the avatar of an author, on the color
+// of the author whatever the author-colors option
+func avatarStyle(author git.Signature) tcell.Style {
+	color := authorColor(author)
+	text := tcell.ColorWhite
+	if lightAuthorColors[color] {
+		text = tcell.ColorBlack
+	}
+	return tcell.StyleDefault.Background(color).Foreground(text).Bold(true)
+}
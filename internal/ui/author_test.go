@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatAuthor(t *testing.T) {
+	cfg := &config.Config{}
+	author := git.Signature{Name: "Jean-Luc de la Tour", Email: "jl@example.com"}
+
+	assert.Equal(t, "Jean-Luc de la Tour", formatAuthor(cfg, author))
+	cfg.Git.AuthorDisplay = "email"
+	assert.Equal(t, "jl@example.com", formatAuthor(cfg, author))
+	assert.Equal(t, "Anonymous", formatAuthor(cfg, git.Signature{Name: "Anonymous"}))
+	cfg.Git.AuthorDisplay = "initials"
+	assert.Equal(t, "JLDLT", formatAuthor(cfg, author))
+	assert.Equal(t, "", formatAuthor(cfg, git.Signature{}))
+}
+
+func TestAuthorStyle(t *testing.T) {
+	cfg := &config.Config{}
+	alice := git.Signature{Name: "Alice", Email: "alice@example.com"}
+	bob := git.Signature{Name: "Bob", Email: "bob@example.com"}
+	assert.Equal(t, tcell.StyleDefault, authorStyle(cfg, alice, tcell.StyleDefault))
+
+	// The color follows the email, whatever the name or case
+	cfg.Git.AuthorColors = true
+	aliceStyle := authorStyle(cfg, alice, tcell.StyleDefault)
+	assert.NotEqual(t, tcell.StyleDefault, aliceStyle)
+	assert.Equal(t, aliceStyle, authorStyle(cfg, git.Signature{Name: "A.", Email: "Alice@example.com"}, tcell.StyleDefault))
+	assert.NotEqual(t, aliceStyle, authorStyle(cfg, bob, tcell.StyleDefault))
+}
+
+func TestMainViewAuthorDisplay(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	cfg.Views.Main.ShowAuthor = true
+	cfg.Git.AuthorDisplay = "email"
+	cfg.Git.AuthorColors = true
+
+	view := NewMainView(cfg, git.NewClient())
+	view.commits = []*git.Commit{
+		{Hash: "a", Summary: "First", Author: git.Signature{Name: "Alice", Email: "alice@example.com"}},
+		{Hash: "b", Summary: "Second", Author: git.Signature{Name: "Bob", Email: "bob@example.com"}},
+	}
+	require.NoError(t, view.Render(screen, 0, 0, 80, 24))
+
+	lines := screenLines(screen)
+	assert.True(t, strings.HasPrefix(lines[2], "│ bob@example.com"), lines[2])
+
+	// Unselected authors are drawn in their color
+	_, _, style, _ := screen.GetContent(2, 2)
+	assert.Equal(t, authorStyle(cfg, view.commits[1].Author, tcell.StyleDefault), style)
+}
+
+func TestAvatarInitials(t *testing.T) {
+	assert.Equal(t, "JT", avatarInitials("Jean-Luc de la Tour"))
+	assert.Equal(t, "AS", avatarInitials("ada smith"))
+	assert.Equal(t, "AL", avatarInitials("alice"))
+	assert.Equal(t, "X ", avatarInitials("X"))
+	assert.Equal(t, "??", avatarInitials(""))
+}
+
+func TestMainViewAvatar(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	cfg.Views.Main.ShowAvatar = true
+
+	view := NewMainView(cfg, git.NewClient())
+	alice := git.Signature{Name: "Alice Smith", Email: "alice@example.com"}
+	view.commits = []*git.Commit{
+		{Hash: "a", Summary: "First", Author: alice},
+		{Hash: "b", Summary: "Second", Author: git.Signature{Name: "Bob", Email: "bob@example.com"}},
+	}
+	require.NoError(t, view.Render(screen, 0, 0, 80, 24))
+
+	lines := screenLines(screen)
+	assert.True(t, strings.HasPrefix(lines[1], "│ AS First"), lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], "│ BO Second"), lines[2])
+
+	// The avatar keeps the colors of the author, selected or not, and
+	// whatever the author-colors option
+	_, _, style, _ := screen.GetContent(2, 1)
+	assert.Equal(t, avatarStyle(alice), style)
+	_, bg, _ := style.Decompose()
+	assert.Equal(t, authorColor(alice), bg)
+	assert.Equal(t, avatarStyle(alice), avatarStyle(git.Signature{Name: "A.", Email: "ALICE@example.com"}))
+}
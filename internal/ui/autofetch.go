@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"strconv"
+	"time"
+)
+
+// resetAutoFetch starts over the wait for the next auto-fetch of a newly
+// opened repository, looking up how often it wants to be fetched
+func (vm *ViewManager) resetAutoFetch() {
+	vm.lastFetch = time.Now()
+	vm.repoAutoFetch = -1
+	if minutes, err := strconv.Atoi(vm.client.ConfigValue("tig.autoFetch")); err == nil && minutes >= 0 {
+		vm.repoAutoFetch = minutes
+	}
+}
+
+// autoFetchInterval returns how often to fetch in the background, zero when
+// not at all. The tig.autoFetch git configuration of the repository takes
+// precedence over the auto-fetch option.
+func (vm *ViewManager) autoFetchInterval() time.Duration {
+	minutes := vm.config.General.AutoFetch
+	if vm.repoAutoFetch >= 0 {
+		minutes = vm.repoAutoFetch
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// AutoFetchIfDue fetches the remotes in the background once the auto-fetch
+// interval passed since the last time, unless a fetch is still running
+func (vm *ViewManager) AutoFetchIfDue() {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	interval := vm.autoFetchInterval()
+	if interval <= 0 || !vm.client.IsRepository() || time.Since(vm.lastFetch) < interval {
+		return
+	}
+	for _, j := range vm.jobs {
+		if j.kind == jobFetch && j.running() {
+			return
+		}
+	}
+	vm.lastFetch = time.Now()
+	vm.autoFetch()
+}
+
+// autoFetch fetches the remotes without showing progress, pointing out
+// commits which arrived for the upstream of the current branch
+func (vm *ViewManager) autoFetch() {
+	vm.fetch("Auto-fetch", true)
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoFetch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	upstream, dir := t.TempDir(), t.TempDir()
+	refsTestGit(t, upstream, "init", "--quiet", "--initial-branch=main")
+	refsTestGit(t, upstream, "commit", "--quiet", "--allow-empty", "-m", "initial")
+	refsTestGit(t, dir, "clone", "--quiet", upstream, ".")
+	refsTestGit(t, upstream, "commit", "--quiet", "--allow-empty", "-m", "new")
+	local := refsTestGit(t, dir, "rev-parse", "main")
+	refsTestGit(t, dir, "config", "tig.autoFetch", "0")
+
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	cfg.General.AutoFetch = 5
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+	vm.SetSize(80, 24)
+	vm.SetRepoPath(dir)
+
+	// The repository turned auto-fetch off
+	vm.lastFetch = time.Now().Add(-time.Hour)
+	vm.AutoFetchIfDue()
+	assert.Empty(t, vm.jobs)
+
+	refsTestGit(t, dir, "config", "tig.autoFetch", "2")
+	vm.SetRepoPath(dir)
+	assert.Equal(t, 2*time.Minute, vm.autoFetchInterval())
+	vm.AutoFetchIfDue()
+	assert.Empty(t, vm.jobs)
+
+	vm.lastFetch = time.Now().Add(-3 * time.Minute)
+	vm.AutoFetchIfDue()
+	require.Len(t, vm.jobs, 1)
+	assert.True(t, vm.jobs[0].quiet)
+
+	// A fetch still running is not started again
+	vm.lastFetch = time.Now().Add(-3 * time.Minute)
+	vm.AutoFetchIfDue()
+	assert.Len(t, vm.jobs, 1)
+
+	for vm.jobs[0].running() {
+		runJobEvent(t, screen)
+	}
+	assert.Equal(t, "1 new upstream commit on origin/main", vm.GetMessage())
+	assert.Contains(t, vm.GetTitle(), "↓1")
+
+	// Only the remote-tracking branch moved
+	assert.Equal(t, local, refsTestGit(t, dir, "rev-parse", "main"))
+	assert.Equal(t, refsTestGit(t, upstream, "rev-parse", "main"), refsTestGit(t, dir, "rev-parse", "origin/main"))
+}
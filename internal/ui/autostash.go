@@ -0,0 +1,257 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azhao1981/tig/internal/git"
+)
+
+// autostashOp is an operation changing the checkout, which local changes
+// can get in the way of
+type autostashOp struct {
+	name     string // Such as "checkout main", for the messages
+	done     string // Message shown once it succeeded
+	discards bool   // Runs over local changes, losing them, rather than refusing to
+	run      func() error
+}
+
+// runAutostashed runs an operation changing the checkout. When it would
+// lose the local changes, or refused to run because of them, it is offered
+// to stash them, run it and apply them back after.
+func (vm *ViewManager) runAutostashed(op autostashOp) error {
+	changes, err := vm.client.LocalChanges()
+	if err != nil {
+		return err
+	}
+
+	var refusal error
+	if len(changes) == 0 || !op.discards {
+		refusal = vm.bulk(op.run)
+		if refusal == nil {
+			vm.setMessage("%s", op.done)
+			return vm.refreshAll()
+		}
+		if len(changes) == 0 {
+			return refusal
+		}
+	}
+
+	var details []string
+	if refusal != nil {
+		reason, _, _ := strings.Cut(refusal.Error(), "\n")
+		details = append(details, fmt.Sprintf("The %s failed: %s", op.name, reason), "")
+	}
+	details = append(details, fmt.Sprintf("Local changes to %s:", countFiles(len(changes))))
+	for _, path := range changes {
+		details = append(details, "  "+path)
+	}
+	details = append(details, "", "They are stashed, then applied back once it is done.")
+
+	vm.askConfirmation(fmt.Sprintf("Stash the local changes and %s?", op.name), details, func() error {
+		return vm.autostash(op, len(changes))
+	})
+	return nil
+}
+
+// autostash runs an operation between stashing the local changes and
+// applying them back, keeping the stash when they don't apply cleanly
+func (vm *ViewManager) autostash(op autostashOp, files int) error {
+	vm.pauseRefresh()
+	defer vm.resumeRefresh()
+
+	hash, err := vm.client.Stash("tig autostash before " + op.name)
+	if err != nil {
+		return err
+	}
+	stash := vm.client.AbbrevHash(hash)
+
+	if err := op.run(); err != nil {
+		if popErr := vm.client.PopStash(hash); popErr != nil {
+			return fmt.Errorf("%v, local changes kept in stash %s", err, stash)
+		}
+		return err
+	}
+	defer vm.refreshAll()
+
+	if err := vm.client.PopStash(hash); err != nil {
+		vm.setMessage("%s, but the stashed changes didn't apply cleanly: they are kept in stash %s", op.done, stash)
+		return nil
+	}
+	vm.setMessage("%s, and applied back the stashed changes to %s", op.done, countFiles(files))
+	return nil
+}
+
+// countFiles returns how many files there are, such as "1 file"
+func countFiles(n int) string {
+	if n == 1 {
+		return "1 file"
+	}
+	return fmt.Sprintf("%d files", n)
+}
+
+// countCommits returns how many commits there are, such as "1 commit"
+func countCommits(n int) string {
+	if n == 1 {
+		return "1 commit"
+	}
+	return fmt.Sprintf("%d commits", n)
+}
+
+// CheckoutCommand handles the :checkout command, offering to stash the
+// local changes the checkout would overwrite. With --orphan it starts a
+// new branch without history instead.
+func (vm *ViewManager) CheckoutCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if len(args) == 2 && args[0] == "--orphan" {
+		return vm.checkoutOrphan(args[1])
+	}
+	if len(args) != 1 || args[0] == "--orphan" {
+		return fmt.Errorf("usage: checkout [--orphan] <branch|rev>")
+	}
+	if err := vm.checkRevisions(args[0]); err != nil {
+		return err
+	}
+	return vm.checkout(args[0], "Checked out "+args[0])
+}
+
+// checkout checks out a revision, showing done once it is, after asking
+// what to do with the local changes and untracked files in the way
+func (vm *ViewManager) checkout(rev, done string) error {
+	op := autostashOp{
+		name: "checkout " + rev,
+		done: done,
+		run:  func() error { return vm.client.Checkout(rev) },
+	}
+	conflicts, err := vm.client.UntrackedConflicts(rev)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		vm.askUntrackedConflicts(op, rev, conflicts)
+		return nil
+	}
+	return vm.runAutostashed(op)
+}
+
+// askUntrackedConflicts lists the untracked files a checkout would
+// overwrite, which git refuses to, and offers to stash them or to
+// overwrite them, keeping them in the trash, before checking out
+func (vm *ViewManager) askUntrackedConflicts(op autostashOp, rev string, files []string) {
+	details := []string{fmt.Sprintf("Untracked files the %s would overwrite:", op.name)}
+	for _, path := range files {
+		details = append(details, "  "+path)
+	}
+	details = append(details, "", "Stashing keeps them in a stash, forcing in the trash.")
+
+	vm.askChoice(fmt.Sprintf("Stash or overwrite %s?", countFiles(len(files))), details,
+		confirmOption{name: "stash", action: func() error {
+			hash, err := vm.client.StashUntracked("tig: untracked files in the way of "+op.name, files)
+			if err != nil {
+				return err
+			}
+			op.done += fmt.Sprintf(", the untracked files in the way kept in stash %s", vm.client.AbbrevHash(hash))
+			return vm.runAutostashed(op)
+		}},
+		confirmOption{name: "force", action: func() error {
+			op.run = func() error { return vm.client.CheckoutOverwriting(rev, files) }
+			op.done += fmt.Sprintf(", overwriting %s kept in the trash", countFiles(len(files)))
+			return vm.runAutostashed(op)
+		}},
+	)
+}
+
+// checkoutOrphan switches to a new branch without history, then shows the
+// status view where its first commit is built from the files left
+// untracked
+func (vm *ViewManager) checkoutOrphan(name string) error {
+	if err := vm.client.CheckoutOrphan(name); err != nil {
+		return err
+	}
+	if err := vm.refreshAll(); err != nil {
+		return err
+	}
+	if statusView, ok := vm.views[ViewTypeStatus].(*StatusView); ok {
+		_ = statusView.refreshChanged()
+	}
+	vm.setMessage("Switched to orphan branch %s, stage its files with a and commit with c", name)
+	return vm.switchView(ViewTypeStatus)
+}
+
+// RebaseCommand handles the :rebase command, offering to stash the local
+// changes a rebase refuses to run over
+func (vm *ViewManager) RebaseCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if len(args) != 1 {
+		return fmt.Errorf("usage: rebase <upstream>")
+	}
+	if err := vm.checkRevisions(args[0]); err != nil {
+		return err
+	}
+
+	upstream := args[0]
+	return vm.runAutostashed(autostashOp{
+		name: "rebase onto " + upstream,
+		done: "Rebased onto " + upstream,
+		run:  func() error { return vm.client.Rebase(upstream) },
+	})
+}
+
+// ResetBranchCommand handles the :reset-branch command, which resets the
+// current branch to a revision once confirmed. Local changes a hard reset
+// would lose are offered to be stashed first.
+func (vm *ViewManager) ResetBranchCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	mode := "mixed"
+	if len(args) > 0 && strings.HasPrefix(args[0], "--") {
+		mode, args = strings.TrimPrefix(args[0], "--"), args[1:]
+	}
+	valid := false
+	for _, m := range git.ResetModes {
+		valid = valid || m == mode
+	}
+	if !valid || len(args) != 1 {
+		return fmt.Errorf("usage: reset-branch [--soft|--mixed|--hard] <rev>")
+	}
+
+	rev := args[0]
+	if err := vm.checkRevisions(rev); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("reset --%s to %s", mode, vm.client.AbbrevHash(rev))
+	reset := func() error {
+		return vm.runAutostashed(autostashOp{
+			name:     name,
+			done:     fmt.Sprintf("Reset --%s to %s", mode, vm.client.AbbrevHash(rev)),
+			discards: mode == "hard",
+			run:      func() error { return vm.client.Reset(rev, mode) },
+		})
+	}
+
+	// The branch is the one to type, HEAD when detached
+	status, err := vm.client.GetBranchStatus()
+	if err != nil {
+		return err
+	}
+	branch := status.Branch
+	if branch == "" {
+		branch = "HEAD"
+	}
+	action, details := "reset", []string{"Commits only reachable from " + branch + " are left to the reflog."}
+	switch mode {
+	case "hard":
+		action = "reset-hard"
+	case "mixed":
+		details = append(details, "Staged changes are unstaged, the worktree is left alone.")
+	case "soft":
+		details = append(details, "The index and the worktree are left alone.")
+	}
+	return vm.confirmAction(action, fmt.Sprintf("Reset %s --%s to %s?", branch, mode, vm.client.AbbrevHash(rev)), branch, details, reset)
+}
@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// autostashTestRepo creates a repository whose main and topic branches
+// change file.txt differently, with main checked out
+func autostashTestRepo(t *testing.T) (string, *ViewManager) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	refsTestGit(t, dir, "init", "--quiet", "--initial-branch=main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("base\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes\n"), 0644))
+	refsTestGit(t, dir, "add", ".")
+	refsTestGit(t, dir, "commit", "--quiet", "-m", "base")
+	refsTestGit(t, dir, "checkout", "--quiet", "-b", "topic")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("topic\n"), 0644))
+	refsTestGit(t, dir, "commit", "--quiet", "-am", "topic")
+	refsTestGit(t, dir, "checkout", "--quiet", "main")
+
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+	vm.SetSize(100, 30)
+	vm.SetRepoPath(dir)
+	return dir, vm
+}
+
+func TestViewManagerCheckoutAutostash(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+
+	// Without local changes in the way nothing is asked
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("mine\n"), 0644))
+	require.NoError(t, vm.CheckoutCommand([]string{"topic"}))
+	assert.False(t, vm.Confirming())
+	assert.Equal(t, "Checked out topic", vm.GetMessage())
+	assert.Equal(t, "topic", refsTestGit(t, dir, "branch", "--show-current"))
+
+	// Changes the checkout would overwrite are offered to be stashed
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("topic\nmine\n"), 0644))
+	require.NoError(t, vm.CheckoutCommand([]string{"main"}))
+	require.True(t, vm.Confirming())
+	assert.Contains(t, vm.confirmation.prompt, "Stash the local changes and checkout main")
+	assert.Contains(t, vm.confirmation.details[0], "The checkout main failed")
+	assert.Contains(t, vm.confirmation.details, "  file.txt")
+	assert.Contains(t, vm.confirmation.details, "  notes.txt")
+
+	// The stashed changes conflict with main and are kept
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'y', 0))
+	assert.Equal(t, "main", refsTestGit(t, dir, "branch", "--show-current"))
+	assert.Contains(t, vm.GetMessage(), "didn't apply cleanly: they are kept in stash")
+	assert.Contains(t, refsTestGit(t, dir, "stash", "list"), "tig autostash before checkout main")
+}
+
+func TestViewManagerCheckoutUntrackedConflicts(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	refsTestGit(t, dir, "checkout", "--quiet", "topic")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "todo.txt"), []byte("topic\n"), 0644))
+	refsTestGit(t, dir, "add", "todo.txt")
+	refsTestGit(t, dir, "commit", "--quiet", "-m", "todo")
+	refsTestGit(t, dir, "checkout", "--quiet", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "todo.txt"), []byte("mine\n"), 0644))
+
+	// The files in the way are listed before git refuses
+	require.NoError(t, vm.CheckoutCommand([]string{"topic"}))
+	require.True(t, vm.Confirming())
+	assert.Equal(t, "Stash or overwrite 1 file?", vm.confirmation.prompt)
+	assert.Contains(t, vm.confirmation.details, "  todo.txt")
+	vm.Render()
+	assert.Contains(t, strings.Join(screenLines(vm.screen), "\n"), "[s]tash [f]orce [A]bort")
+
+	vm.HandleKey(tcell.KeyRune, 'a', 0)
+	assert.Equal(t, "Aborted", vm.GetMessage())
+	assert.Equal(t, "main", refsTestGit(t, dir, "branch", "--show-current"))
+
+	// Stashed, they are kept out of the way
+	require.NoError(t, vm.CheckoutCommand([]string{"topic"}))
+	vm.HandleKey(tcell.KeyRune, 's', 0)
+	assert.Equal(t, "topic", refsTestGit(t, dir, "branch", "--show-current"))
+	assert.Contains(t, vm.GetMessage(), "Checked out topic, the untracked files in the way kept in stash ")
+	assert.Contains(t, refsTestGit(t, dir, "stash", "list"), "tig: untracked files in the way of checkout topic")
+
+	// Forced, they are overwritten and kept in the trash
+	refsTestGit(t, dir, "checkout", "--quiet", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "todo.txt"), []byte("mine again\n"), 0644))
+	require.NoError(t, vm.CheckoutCommand([]string{"topic"}))
+	vm.HandleKey(tcell.KeyRune, 'f', 0)
+	assert.Equal(t, "Checked out topic, overwriting 1 file kept in the trash", vm.GetMessage())
+	content, err := os.ReadFile(filepath.Join(dir, "todo.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "topic\n", string(content))
+}
+
+func TestViewManagerCheckoutOrphan(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	statusView := vm.views[ViewTypeStatus].(*StatusView)
+
+	assert.EqualError(t, vm.CheckoutCommand([]string{"--orphan"}), "usage: checkout [--orphan] <branch|rev>")
+	assert.Error(t, vm.CheckoutCommand([]string{"--orphan", "topic"}))
+
+	// The status view shows the files to build the first commit from
+	require.NoError(t, vm.CheckoutCommand([]string{"--orphan", "gh-pages"}))
+	assert.Equal(t, ViewTypeStatus, vm.GetCurrentView())
+	assert.Equal(t, "Switched to orphan branch gh-pages, stage its files with a and commit with c", vm.GetMessage())
+	assert.Equal(t, "gh-pages", refsTestGit(t, dir, "symbolic-ref", "--short", "HEAD"))
+	assert.True(t, statusView.status.NoCommits)
+	assert.Empty(t, statusView.status.Staged)
+	assert.Len(t, statusView.status.Untracked, 2)
+}
+
+func TestViewManagerResetAutostash(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	base := refsTestGit(t, dir, "rev-parse", "HEAD")
+	refsTestGit(t, dir, "reset", "--quiet", "--hard", "topic")
+
+	// A hard reset over local changes applies them back after
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("mine\n"), 0644))
+	require.NoError(t, vm.ResetBranchCommand([]string{"--hard", base}))
+	require.True(t, vm.Confirming())
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'y', 0))
+	require.True(t, vm.Confirming())
+	assert.Equal(t, "Local changes to 1 file:", vm.confirmation.details[0])
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'y', 0))
+	assert.Equal(t, base, refsTestGit(t, dir, "rev-parse", "HEAD"))
+	assert.Contains(t, vm.GetMessage(), "applied back the stashed changes to 1 file")
+	data, err := os.ReadFile(filepath.Join(dir, "notes.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "mine\n", string(data))
+	assert.Empty(t, refsTestGit(t, dir, "stash", "list"))
+
+	// Declining leaves everything as it was
+	require.NoError(t, vm.ResetBranchCommand([]string{"--hard", "topic"}))
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'y', 0))
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'n', 0))
+	assert.Equal(t, base, refsTestGit(t, dir, "rev-parse", "HEAD"))
+
+	// Mixed resets keep the changes without offering to stash them
+	require.NoError(t, vm.ResetBranchCommand([]string{"topic"}))
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'y', 0))
+	assert.False(t, vm.Confirming())
+	assert.Equal(t, "M notes.txt", strings.TrimSpace(refsTestGit(t, dir, "status", "--porcelain", "notes.txt")))
+
+	assert.Error(t, vm.ResetBranchCommand([]string{"--keep", "topic"}))
+	assert.Error(t, vm.ResetBranchCommand(nil), "the revision is required")
+	assert.Error(t, vm.RebaseCommand(nil))
+}
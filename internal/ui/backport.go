@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BackportCommand handles the :backport command, which cherry-picks a
+// commit or stash onto another branch without touching the checkout. The
+// commit defaults to the one selected in the main view.
+func (vm *ViewManager) BackportCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("usage: backport <branch> [commit|stash@{n}]")
+	}
+	if err := vm.checkRevisions(args...); err != nil {
+		return err
+	}
+
+	branch := args[0]
+	var rev string
+	if len(args) == 2 {
+		rev = args[1]
+	} else if commit := vm.getSelectedCommit(); commit != nil {
+		rev = commit.Hash
+	} else {
+		return fmt.Errorf("no commit selected")
+	}
+
+	result, err := vm.client.Backport(rev, branch)
+	if err != nil {
+		return err
+	}
+	if len(result.Conflicts) > 0 {
+		vm.setMessage("Backport of %s to %s aborted, conflicts in: %s", vm.client.AbbrevHash(rev), branch, strings.Join(result.Conflicts, ", "))
+		return nil
+	}
+
+	vm.setMessage("Backported %s to %s as %s", vm.client.AbbrevHash(rev), branch, result.Commit)
+	return vm.refreshAll()
+}
@@ -0,0 +1,289 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// BlobView shows the content of a file, as in the worktree or as of a
+// revision, with a cursor on one of its lines
+type BlobView struct {
+	*BaseView
+	*Scrollable
+	config   *config.Config
+	client   git.Client
+	rev      string // Revision the file is read from, the worktree when empty
+	path     string // Path relative to the repository root
+	lines    []string
+	err      error
+	blame    *blobBlame // Commits of the lines, nil while hidden
+	selected int
+	repoPath string
+	frame    *Frame
+}
+
+// blobBlame holds the commits which last changed the lines of the file,
+// shown in a gutter left of the lines
+type blobBlame struct {
+	lines []git.BlameLine // Commits by line, empty for lines not committed
+	width int             // Width of the abbreviated commits
+}
+
+// NewBlobView creates a new blob view
+func NewBlobView(config *config.Config, client git.Client) *BlobView {
+	return &BlobView{
+		BaseView:   NewBaseView(ViewTypeBlob),
+		Scrollable: NewScrollable(),
+		config:     config,
+		client:     client,
+		frame:      NewFrame(config, "Blob"),
+	}
+}
+
+// SetFile shows a file as in the worktree, or as of rev if set, with the
+// cursor on the given line. The file shown is left alone when the new one
+// can't be read.
+func (v *BlobView) SetFile(rev, path string, line int) error {
+	lines, err := v.read(rev, path)
+	if err != nil {
+		return err
+	}
+
+	v.rev, v.path = rev, path
+	v.lines, v.err = lines, nil
+	if v.blame != nil {
+		v.loadBlame()
+	}
+	v.selected = max(0, min(line-1, len(v.lines)-1))
+	v.ScrollToTop()
+	v.frame.Title = path
+	if rev != "" {
+		v.frame.Title = fmt.Sprintf("%s at %s", path, v.client.AbbrevHash(rev))
+	}
+	return nil
+}
+
+// read returns the lines of a file, read from the worktree when rev is
+// empty and from the object store otherwise
+func (v *BlobView) read(rev, path string) ([]string, error) {
+	var content []byte
+	var err error
+	if rev == "" {
+		content, err = os.ReadFile(filepath.Join(v.client.GetRootPath(), path))
+	} else {
+		content, err = v.client.GetBlob(rev + ":" + path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if git.LooksBinary(content) {
+		return nil, fmt.Errorf("%s is a binary file of %s", path, git.FormatSize(int64(len(content))))
+	}
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n"), nil
+}
+
+// toggleBlame shows or hides the commits which last changed the lines
+func (v *BlobView) toggleBlame() {
+	if v.blame != nil {
+		v.blame = nil
+		return
+	}
+	v.loadBlame()
+}
+
+// loadBlame blames the file shown, as of the revision it is read from or
+// as in the worktree. The gutter is hidden when the file can't be blamed,
+// as when it isn't tracked.
+func (v *BlobView) loadBlame() {
+	lines, err := v.client.Blame(v.rev, v.path, &git.BlameOptions{
+		IgnoreRevs: strings.Fields(v.config.Git.BlameIgnoreRevs),
+	})
+	if err != nil {
+		v.blame = nil
+		return
+	}
+
+	v.blame = &blobBlame{lines: lines, width: git.DefaultAbbrev}
+	for i := range lines {
+		if lines[i].Commit == git.NotCommitted {
+			lines[i].Commit = ""
+			continue
+		}
+		lines[i].Commit = v.client.AbbrevHash(lines[i].Commit)
+		v.blame.width = max(v.blame.width, len(lines[i].Commit))
+	}
+}
+
+// Render renders the blob view
+func (v *BlobView) Render(screen tcell.Screen, x, y, width, height int) error {
+	v.SetPosition(x, y, width, height)
+	v.SetHeight(height - 2) // Account for borders
+
+	contentX, contentY, contentWidth, contentHeight := v.frame.Draw(screen, x, y, width, height)
+	defer v.frame.DrawScrollbar(screen, v.Scrollable)
+
+	if contentWidth <= 0 || contentHeight <= 0 {
+		return nil
+	}
+
+	if v.err != nil || v.path == "" {
+		msg := "No file, press Enter on a file of the tree or a line of :grep"
+		if v.err != nil {
+			msg = v.err.Error()
+		}
+		msgX := contentX + (contentWidth-len(msg))/2
+		msgY := contentY + contentHeight/2
+		drawCells(screen, max(contentX, msgX), msgY, contentWidth, textCells(msg, tcell.StyleDefault.Dim(true)), 0, tcell.StyleDefault)
+		return nil
+	}
+
+	// The cursor is kept in view, centered when it was out of it
+	v.SetMaxOffset(len(v.lines) - contentHeight)
+	if v.selected < v.GetOffset() || v.selected >= v.GetOffset()+contentHeight {
+		v.SetOffset(v.selected - contentHeight/2)
+	}
+
+	start := v.GetOffset()
+	end := min(start+contentHeight, len(v.lines))
+	numberWidth := len(fmt.Sprint(len(v.lines)))
+	marks := newWhitespaceMarks(v.config)
+	for i := start; i < end; i++ {
+		numberStyle := tcell.StyleDefault.Foreground(tcell.ColorYellow)
+		textStyle := tcell.StyleDefault
+		if i == v.selected {
+			background := tcell.ColorDarkBlue
+			if v.IsFocused() {
+				background = tcell.ColorBlue
+			}
+			numberStyle = numberStyle.Background(background)
+			textStyle = textStyle.Background(background)
+		}
+
+		cells := textCells(fmt.Sprintf("%*d ", numberWidth, i+1), numberStyle)
+		if v.blame != nil {
+			var line git.BlameLine
+			if i < len(v.blame.lines) {
+				line = v.blame.lines[i]
+			}
+			cells = append(cells, textCells(blameGutter(line, v.blame.width)+" ", blameStyle)...)
+		}
+		cells = append(cells, marks.cells(v.lines[i], textStyle, -1)...)
+		if i == v.selected {
+			for cellsWidth(cells) < contentWidth {
+				cells = append(cells, cell{' ', textStyle})
+			}
+		}
+		drawCells(screen, contentX, contentY+(i-start), contentWidth, cells, 0, textStyle)
+	}
+	return nil
+}
+
+// HandleKey handles keyboard input
+func (v *BlobView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
+	if !v.IsFocused() {
+		return false
+	}
+
+	switch key {
+	case tcell.KeyUp:
+		v.moveTo(v.selected - 1)
+		return true
+	case tcell.KeyDown:
+		v.moveTo(v.selected + 1)
+		return true
+	case tcell.KeyPgUp:
+		v.moveTo(v.selected - v.getPageSize())
+		return true
+	case tcell.KeyPgDn:
+		v.moveTo(v.selected + v.getPageSize())
+		return true
+	case tcell.KeyHome:
+		v.moveTo(0)
+		return true
+	case tcell.KeyEnd:
+		v.moveTo(len(v.lines) - 1)
+		return true
+	}
+
+	switch ch {
+	case 'j':
+		v.moveTo(v.selected + 1)
+		return true
+	case 'k':
+		v.moveTo(v.selected - 1)
+		return true
+	case 'g':
+		v.moveTo(0)
+		return true
+	case 'G':
+		v.moveTo(len(v.lines) - 1)
+		return true
+	case 'b':
+		v.toggleBlame()
+		return true
+	}
+
+	return false
+}
+
+// moveTo moves the cursor to a line, scrolling by as little as needed
+func (v *BlobView) moveTo(index int) {
+	v.selected = max(0, min(index, len(v.lines)-1))
+	pageSize := v.getPageSize()
+	switch {
+	case v.selected < v.GetOffset():
+		v.SetOffset(v.selected)
+	case pageSize > 0 && v.selected >= v.GetOffset()+pageSize:
+		v.SetOffset(v.selected - pageSize + 1)
+	}
+}
+
+// getPageSize returns the number of visible lines
+func (v *BlobView) getPageSize() int {
+	_, _, _, height := v.GetPosition()
+	return height - 2 // Account for borders
+}
+
+// Refresh reads the file again, keeping the cursor on the same line
+func (v *BlobView) Refresh() error {
+	if v.path == "" {
+		return nil
+	}
+	v.lines, v.err = v.read(v.rev, v.path)
+	if v.blame != nil {
+		v.loadBlame()
+	}
+	v.selected = max(0, min(v.selected, len(v.lines)-1))
+	return nil
+}
+
+// EditorTarget returns the file and the line under the cursor
+func (v *BlobView) EditorTarget() (string, int, error) {
+	if v.path == "" {
+		return "", 0, fmt.Errorf("no file shown")
+	}
+	return v.path, v.selected + 1, nil
+}
+
+// Selection returns the file, line and revision shown for placeholder
+// expansion
+func (v *BlobView) Selection() Selection {
+	return Selection{Commit: v.rev, File: v.path, Lineno: v.selected + 1}
+}
+
+// SetRepoPath sets the repository path
+func (v *BlobView) SetRepoPath(path string) {
+	v.repoPath = path
+	v.rev, v.path = "", ""
+	v.lines, v.err = nil, nil
+	v.blame = nil
+	v.selected = 0
+	v.frame.Title = "Blob"
+	v.ScrollToTop()
+}
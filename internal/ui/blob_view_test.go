@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlobViewFromTree(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	treeView := vm.views[ViewTypeTree].(*TreeView)
+	blobView := vm.views[ViewTypeBlob].(*BlobView)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "src"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "code.go"), []byte("func f() {\n\treturn\n}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "image.bin"), []byte("\x00\x01\x02"), 0644))
+	refsTestGit(t, dir, "add", ".")
+	refsTestGit(t, dir, "commit", "--quiet", "-m", "code")
+	// The worktree is not what is shown
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "code.go"), []byte("changed\n"), 0644))
+	vm.config.UI.TabSize = 4
+
+	require.NoError(t, vm.SwitchView(ViewTypeTree))
+	require.NoError(t, treeView.Refresh())
+	selectFile := func(name string) {
+		treeView.selected = findSelection(len(treeView.files), 0, func(i int) bool {
+			return treeView.files[i].Path == name
+		})
+		require.Equal(t, name, treeView.files[treeView.selected].Path)
+	}
+
+	// Directories are entered, files shown as of HEAD
+	selectFile("src")
+	vm.HandleKey(tcell.KeyEnter, 0, 0)
+	assert.Equal(t, "src", treeView.currentPath)
+	selectFile("code.go")
+	vm.HandleKey(tcell.KeyEnter, 0, 0)
+	assert.Equal(t, ViewTypeBlob, vm.GetCurrentView())
+	assert.Equal(t, []string{"func f() {", "\treturn", "}"}, blobView.lines)
+	path, line, err := blobView.EditorTarget()
+	require.NoError(t, err)
+	assert.Equal(t, "src/code.go", path)
+	assert.Equal(t, 1, line)
+
+	// Lines are numbered and tabs expanded to tab-size
+	vm.Render()
+	lines := screenLines(vm.screen)
+	assert.Contains(t, lines[1], "src/code.go at ")
+	assert.Equal(t, "│2     return", strings.TrimRight(lines[3], " │"))
+
+	// Binary files are not shown
+	require.NoError(t, vm.SwitchView(ViewTypeTree))
+	vm.HandleKey(tcell.KeyLeft, 0, 0)
+	selectFile("image.bin")
+	vm.HandleKey(tcell.KeyEnter, 0, 0)
+	assert.Equal(t, ViewTypeTree, vm.GetCurrentView())
+	assert.Equal(t, "image.bin is a binary file of 3 B", vm.GetMessage())
+	assert.Equal(t, "src/code.go", blobView.path)
+}
+
+func TestTreeViewRevision(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	treeView := vm.views[ViewTypeTree].(*TreeView)
+	blobView := vm.views[ViewTypeBlob].(*BlobView)
+	topic := refsTestGit(t, dir, "rev-parse", "topic")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new\n"), 0644))
+	refsTestGit(t, dir, "add", "new.txt")
+
+	// The index lists the staged file, a revision only its own
+	require.NoError(t, vm.SwitchView(ViewTypeTree))
+	require.NoError(t, treeView.Refresh())
+	assert.Len(t, treeView.files, 3)
+	require.NoError(t, vm.TreeCommand([]string{"topic"}))
+	assert.Equal(t, topic, treeView.rev)
+	require.Len(t, treeView.files, 2)
+	vm.Render()
+	lines := screenLines(vm.screen)
+	assert.Contains(t, lines[1], "Repository Tree at "+vm.client.AbbrevHash(topic))
+	assert.Contains(t, strings.Join(lines, "\n"), "-rw-r--r--        6 B 📄 file.txt")
+
+	// Files are shown as of that revision
+	treeView.selected = findSelection(len(treeView.files), 0, func(i int) bool {
+		return treeView.files[i].Path == "file.txt"
+	})
+	vm.HandleKey(tcell.KeyEnter, 0, 0)
+	assert.Equal(t, ViewTypeBlob, vm.GetCurrentView())
+	assert.Equal(t, []string{"topic"}, blobView.lines)
+
+	assert.Error(t, vm.TreeCommand([]string{"missing"}))
+	assert.Equal(t, topic, treeView.rev)
+	require.NoError(t, vm.TreeCommand(nil))
+	assert.Empty(t, treeView.rev)
+
+	// From the main view t shows the tree of the selected commit
+	require.NoError(t, vm.SwitchView(ViewTypeMain))
+	mainView := vm.views[ViewTypeMain].(*MainView)
+	require.NoError(t, mainView.Refresh())
+	vm.HandleKey(tcell.KeyRune, 't', 0)
+	assert.Equal(t, ViewTypeTree, vm.GetCurrentView())
+	assert.Equal(t, mainView.GetSelectedCommit().Hash, treeView.rev)
+}
+
+func TestBlobViewBlame(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	blobView := vm.views[ViewTypeBlob].(*BlobView)
+	base := vm.client.AbbrevHash(refsTestGit(t, dir, "rev-parse", "HEAD"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes\nmine\n"), 0644))
+
+	// Lines of the worktree not committed yet have no commit
+	require.NoError(t, blobView.SetFile("", "notes.txt", 1))
+	require.NoError(t, vm.SwitchView(ViewTypeBlob))
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'b', 0))
+	require.NoError(t, vm.Render())
+	lines := screenLines(vm.screen)
+	assert.Equal(t, "│1 "+base+"  notes", strings.TrimRight(lines[2], " │"))
+	assert.Equal(t, "│2 "+strings.Repeat(" ", len(base))+"  mine", strings.TrimRight(lines[3], " │"))
+
+	// The gutter follows the file shown until hidden again
+	topic := refsTestGit(t, dir, "rev-parse", "topic")
+	require.NoError(t, blobView.SetFile(topic, "file.txt", 1))
+	require.NoError(t, vm.Render())
+	lines = screenLines(vm.screen)
+	assert.Equal(t, "│1 "+vm.client.AbbrevHash(topic)+"  topic", strings.TrimRight(lines[2], " │"))
+
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'b', 0))
+	require.NoError(t, vm.Render())
+	lines = screenLines(vm.screen)
+	assert.Equal(t, "│1 topic", strings.TrimRight(lines[2], " │"))
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azhao1981/tig/internal/config"
+)
+
+// BranchCommand handles the :branch command, which creates a branch at a
+// revision, by default the branch or tag selected in the refs view when it
+// is shown and HEAD otherwise, renames one with -m, and deletes one with
+// -d, or -D to force it
+func (vm *ViewManager) BranchCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	switch {
+	case len(args) == 3 && args[0] == "-m":
+		if err := vm.client.RenameBranch(args[1], args[2]); err != nil {
+			return err
+		}
+		vm.setMessage("Renamed branch %s to %s", args[1], args[2])
+		return vm.refreshAll()
+	case len(args) == 2 && (args[0] == "-d" || args[0] == "-D"):
+		return vm.deleteBranch(args[1], args[0] == "-D")
+	case (len(args) == 1 || len(args) == 2) && !strings.HasPrefix(args[0], "-"):
+		start := "HEAD"
+		if len(args) == 2 {
+			start = args[1]
+		} else if view, ok := vm.views[ViewTypeRefs].(*RefsView); ok && vm.currentView == ViewTypeRefs {
+			if item := view.SelectedItem(); item != nil && refName(item) != "" {
+				start = refName(item)
+			}
+		}
+		if err := vm.checkRevisions(start); err != nil {
+			return err
+		}
+		if err := vm.client.CreateBranch(args[0], start); err != nil {
+			return err
+		}
+		hash, _ := vm.client.ResolveRevision(args[0])
+		vm.setMessage("Created branch %s at %s", args[0], vm.client.AbbrevHash(hash))
+		return vm.refreshAll()
+	}
+	return fmt.Errorf("usage: branch <name> [start] | -m <branch> <new-name> | -d|-D <branch>")
+}
+
+// deleteBranch asks to delete a branch as branch-delete is confirmed.
+// Commits not merged into HEAD are only kept in the reflog once it is
+// gone, so such a branch is always asked about and deleted with force.
+func (vm *ViewManager) deleteBranch(name string, force bool) error {
+	plan, err := vm.client.PlanRefDeletion([]string{"refs/heads/" + name})
+	if err != nil {
+		return err
+	}
+	branch := plan[0]
+	hash := vm.client.AbbrevHash(branch.Hash)
+
+	prompt := fmt.Sprintf("Delete branch %s?", name)
+	details := []string{fmt.Sprintf("branch %s at %s", name, hash)}
+	if branch.Unmerged {
+		prompt = fmt.Sprintf("Force delete branch %s, not merged into HEAD?", name)
+		details = append(details, "Its commits not merged into HEAD are only kept in the reflog once it is gone.")
+	}
+	run := func() error {
+		if err := vm.client.DeleteBranch(name, force || branch.Unmerged); err != nil {
+			return err
+		}
+		vm.setMessage("Deleted branch %s (was %s)", name, hash)
+		return vm.refreshAll()
+	}
+	if branch.Unmerged && vm.config.ConfirmLevel("branch-delete") == config.ConfirmNone {
+		vm.askConfirmation(prompt, details, run)
+		return nil
+	}
+	return vm.confirmAction("branch-delete", prompt, name, details, run)
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// clipboardCommands print the system clipboard, the first one installed
+// being used unless clipboard-command is set
+var clipboardCommands = [][]string{
+	{"pbpaste"},
+	{"wl-paste", "--no-newline"},
+	{"xclip", "-out", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--output"},
+	{"powershell.exe", "-NoProfile", "-Command", "Get-Clipboard"},
+}
+
+// copyCommands copy their input to the system clipboard, the first one
+// installed being used unless copy-command is set
+var copyCommands = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-in", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"clip.exe"},
+}
+
+// findCommand returns the words of a configured command, or the first of
+// the candidates installed
+func findCommand(command string, candidates [][]string) []string {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		for _, candidate := range candidates {
+			if _, err := exec.LookPath(candidate[0]); err == nil {
+				return candidate
+			}
+		}
+	}
+	return args
+}
+
+// readClipboard returns the text of the system clipboard
+func readClipboard(command string) (string, error) {
+	args := findCommand(command, clipboardCommands)
+	if len(args) == 0 {
+		return "", fmt.Errorf("no clipboard command found, set clipboard-command")
+	}
+
+	output, err := exec.Command(args[0], args[1:]...).Output()
+	if err != nil {
+		return "", fmt.Errorf("%s failed: %w", args[0], err)
+	}
+	return string(output), nil
+}
+
+// writeClipboard copies text to the system clipboard
+func writeClipboard(command, text string) error {
+	args := findCommand(command, copyCommands)
+	if len(args) == 0 {
+		return fmt.Errorf("no copy command found, set copy-command")
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// pasteClipboard inserts the system clipboard in the open prompt
+func (t *Terminal) pasteClipboard() {
+	text, err := readClipboard(t.config.General.ClipboardCommand)
+	if err != nil {
+		t.viewManager.SetMessage("%v", err)
+		return
+	}
+	t.insertPasted(text)
+}
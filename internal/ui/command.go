@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/uniseg"
 )
 
 // Command represents a command that can be executed
@@ -13,6 +14,10 @@ type Command struct {
 	Description string
 	Handler     func(args []string) error
 	Usage       string
+	Mutating    bool // Changes the repository, refused in read-only mode
+	// Complete returns the candidates for the argument being typed, nil
+	// for commands whose arguments aren't completed
+	Complete func(word string) []string
 }
 
 // CommandManager manages the command system
@@ -23,6 +28,8 @@ type CommandManager struct {
 	cursor   int
 	history  []string
 	historyIndex int
+	readOnly func() bool // Whether mutating commands are refused
+	suggestions []string // Candidates of an ambiguous completion
 }
 
 // NewCommandManager creates a new command manager
@@ -62,13 +69,6 @@ func (cm *CommandManager) registerCommands() {
 		Usage:       "diff",
 	})
 
-	cm.Register(&Command{
-		Name:        "tree",
-		Description: "Show tree view",
-		Handler:     cm.handleViewCommand,
-		Usage:       "tree",
-	})
-
 	cm.Register(&Command{
 		Name:        "refs",
 		Description: "Show refs view",
@@ -84,18 +84,12 @@ func (cm *CommandManager) registerCommands() {
 	})
 
 	// Git commands
-	cm.Register(&Command{
-		Name:        "commit",
-		Description: "Commit changes",
-		Handler:     cm.handleCommitCommand,
-		Usage:       "commit [message]",
-	})
-
 	cm.Register(&Command{
 		Name:        "add",
 		Description: "Add files to staging area",
 		Handler:     cm.handleAddCommand,
 		Usage:       "add [files...]",
+		Mutating:    true,
 	})
 
 	cm.Register(&Command{
@@ -103,6 +97,7 @@ func (cm *CommandManager) registerCommands() {
 		Description: "Reset files from staging area",
 		Handler:     cm.handleResetCommand,
 		Usage:       "reset [files...]",
+		Mutating:    true,
 	})
 
 	cm.Register(&Command{
@@ -120,13 +115,6 @@ func (cm *CommandManager) registerCommands() {
 		Usage:       "search [pattern]",
 	})
 
-	cm.Register(&Command{
-		Name:        "grep",
-		Description: "Search files by content",
-		Handler:     cm.handleGrepCommand,
-		Usage:       "grep [pattern]",
-	})
-
 	// System commands
 	cm.Register(&Command{
 		Name:        "refresh",
@@ -172,6 +160,15 @@ func (cm *CommandManager) StartCommandMode() {
 	cm.buffer = ""
 	cm.cursor = 0
 	cm.historyIndex = -1
+	cm.suggestions = nil
+}
+
+// StartCommandModeWith starts command mode with the buffer prefilled, so
+// actions can prompt for the remaining arguments of a command
+func (cm *CommandManager) StartCommandModeWith(text string) {
+	cm.StartCommandMode()
+	cm.buffer = text
+	cm.cursor = len(text)
 }
 
 // StopCommandMode stops command mode
@@ -180,6 +177,7 @@ func (cm *CommandManager) StopCommandMode() {
 	cm.buffer = ""
 	cm.cursor = 0
 	cm.historyIndex = -1
+	cm.suggestions = nil
 }
 
 // IsActive returns whether command mode is active
@@ -192,47 +190,71 @@ func (cm *CommandManager) GetBuffer() string {
 	return cm.buffer
 }
 
-// GetCursor returns the cursor position
+// GetCursor returns the cursor position, as a byte offset in the buffer
+// which is always at the start of a character
 func (cm *CommandManager) GetCursor() int {
 	return cm.cursor
 }
 
 // InsertChar inserts a character at the cursor position
 func (cm *CommandManager) InsertChar(ch rune) {
-	if cm.cursor == len(cm.buffer) {
-		cm.buffer += string(ch)
-	} else {
-		cm.buffer = cm.buffer[:cm.cursor] + string(ch) + cm.buffer[cm.cursor:]
-	}
-	cm.cursor++
+	cm.InsertText(string(ch))
+}
+
+// InsertText inserts text at the cursor position, such as a paste
+func (cm *CommandManager) InsertText(text string) {
+	cm.buffer = cm.buffer[:cm.cursor] + text + cm.buffer[cm.cursor:]
+	cm.cursor += len(text)
 }
 
 // Backspace removes the character before the cursor
 func (cm *CommandManager) Backspace() {
 	if cm.cursor > 0 {
-		cm.buffer = cm.buffer[:cm.cursor-1] + cm.buffer[cm.cursor:]
-		cm.cursor--
+		start := clusterBefore(cm.buffer, cm.cursor)
+		cm.buffer = cm.buffer[:start] + cm.buffer[cm.cursor:]
+		cm.cursor = start
 	}
 }
 
 // Delete removes the character at the cursor
 func (cm *CommandManager) Delete() {
 	if cm.cursor < len(cm.buffer) {
-		cm.buffer = cm.buffer[:cm.cursor] + cm.buffer[cm.cursor+1:]
+		cm.buffer = cm.buffer[:cm.cursor] + cm.buffer[clusterAfter(cm.buffer, cm.cursor):]
 	}
 }
 
-// MoveCursor moves the cursor position
+// MoveCursor moves the cursor by a number of characters
 func (cm *CommandManager) MoveCursor(delta int) {
-	cm.cursor += delta
-	if cm.cursor < 0 {
-		cm.cursor = 0
+	for ; delta < 0 && cm.cursor > 0; delta++ {
+		cm.cursor = clusterBefore(cm.buffer, cm.cursor)
 	}
-	if cm.cursor > len(cm.buffer) {
-		cm.cursor = len(cm.buffer)
+	for ; delta > 0 && cm.cursor < len(cm.buffer); delta-- {
+		cm.cursor = clusterAfter(cm.buffer, cm.cursor)
 	}
 }
 
+// clusterBefore returns where the character ending at byte i of s starts.
+// Characters are grapheme clusters, so that a letter and its accents or
+// the parts of an emoji are edited as one.
+func clusterBefore(s string, i int) int {
+	start, state := 0, -1
+	for start < i {
+		var cluster string
+		cluster, _, _, state = uniseg.FirstGraphemeClusterInString(s[start:], state)
+		if start+len(cluster) >= i {
+			break
+		}
+		start += len(cluster)
+	}
+	return start
+}
+
+// clusterAfter returns where the character starting at byte i of s ends
+func clusterAfter(s string, i int) int {
+	cluster, _, _, _ := uniseg.FirstGraphemeClusterInString(s[i:], -1)
+	return i + len(cluster)
+}
+
 // MoveCursorToStart moves the cursor to the start
 func (cm *CommandManager) MoveCursorToStart() {
 	cm.cursor = 0
@@ -261,8 +283,13 @@ func (cm *CommandManager) Execute() error {
 		cm.history = cm.history[1:]
 	}
 
-	// Parse command
-	parts := strings.Fields(cm.buffer)
+	return cm.Run(cm.buffer)
+}
+
+// Run runs a command line as if typed at the prompt, without remembering
+// it in the history
+func (cm *CommandManager) Run(line string) error {
+	parts := strings.Fields(line)
 	if len(parts) == 0 {
 		return nil
 	}
@@ -270,14 +297,85 @@ func (cm *CommandManager) Execute() error {
 	cmdName := parts[0]
 	args := parts[1:]
 
+	// External commands may be written without a space, as in !git show
+	if strings.HasPrefix(cmdName, "!") && len(cmdName) > 1 {
+		args = append([]string{cmdName[1:]}, args...)
+		cmdName = "!"
+	}
+
 	// Find and execute command
 	if cmd, ok := cm.commands[cmdName]; ok {
+		if cmd.Mutating && cm.readOnly != nil && cm.readOnly() {
+			return fmt.Errorf("%s is disabled in read-only mode", cmdName)
+		}
 		return cmd.Handler(args)
 	}
 
 	return fmt.Errorf("unknown command: %s", cmdName)
 }
 
+// SetReadOnly sets how to tell whether mutating commands are refused
+func (cm *CommandManager) SetReadOnly(readOnly func() bool) {
+	cm.readOnly = readOnly
+}
+
+// GetSuggestions returns the candidates offered by the last ambiguous
+// completion
+func (cm *CommandManager) GetSuggestions() []string {
+	return cm.suggestions
+}
+
+// complete completes the command name, or the argument being typed for
+// commands completing their arguments. Ambiguous arguments are completed
+// up to the prefix the candidates share, which are offered as suggestions.
+func (cm *CommandManager) complete() {
+	if cm.buffer == "" || cm.cursor != len(cm.buffer) {
+		return
+	}
+
+	parts := strings.Fields(cm.buffer)
+	if len(parts) == 1 && !strings.HasSuffix(cm.buffer, " ") {
+		suggestions := cm.AutoComplete(parts[0])
+		if len(suggestions) == 1 {
+			cm.buffer = suggestions[0]
+			cm.cursor = len(cm.buffer)
+		}
+		return
+	}
+
+	cmd, ok := cm.commands[parts[0]]
+	if !ok || cmd.Complete == nil {
+		return
+	}
+	start := strings.LastIndex(cm.buffer, " ") + 1
+	candidates := cmd.Complete(cm.buffer[start:])
+	switch len(candidates) {
+	case 0:
+		return
+	case 1:
+		cm.buffer = cm.buffer[:start] + candidates[0]
+		// Completed directories are left open for their entries
+		if !strings.HasSuffix(candidates[0], "/") {
+			cm.buffer += " "
+		}
+	default:
+		cm.buffer = cm.buffer[:start] + commonPrefix(candidates)
+		cm.suggestions = candidates
+	}
+	cm.cursor = len(cm.buffer)
+}
+
+// commonPrefix returns the longest prefix shared by all words
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
 // AutoComplete returns auto-completion suggestions
 func (cm *CommandManager) AutoComplete(prefix string) []string {
 	var matches []string
@@ -294,6 +392,9 @@ func (cm *CommandManager) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) b
 	if !cm.active {
 		return false
 	}
+	if key != tcell.KeyTab {
+		cm.suggestions = nil
+	}
 
 	switch key {
 	case tcell.KeyEnter:
@@ -348,17 +449,7 @@ func (cm *CommandManager) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) b
 		}
 		return true
 	case tcell.KeyTab:
-		// Auto-complete
-		if cm.buffer != "" {
-			parts := strings.Fields(cm.buffer)
-			if len(parts) == 1 {
-				suggestions := cm.AutoComplete(parts[0])
-				if len(suggestions) == 1 {
-					cm.buffer = suggestions[0]
-					cm.cursor = len(cm.buffer)
-				}
-			}
-		}
+		cm.complete()
 		return true
 	default:
 		if key == tcell.KeyRune && ch != 0 {
@@ -376,12 +467,6 @@ func (cm *CommandManager) handleViewCommand(args []string) error {
 	return nil
 }
 
-func (cm *CommandManager) handleCommitCommand(args []string) error {
-	_ = args
-	// This would be implemented by the git client
-	return nil
-}
-
 func (cm *CommandManager) handleAddCommand(args []string) error {
 	_ = args
 	// This would be implemented by the git client
@@ -406,12 +491,6 @@ func (cm *CommandManager) handleSearchCommand(args []string) error {
 	return nil
 }
 
-func (cm *CommandManager) handleGrepCommand(args []string) error {
-	_ = args
-	// This would be implemented by the git client
-	return nil
-}
-
 func (cm *CommandManager) handleRefreshCommand(args []string) error {
 	_ = args
 	// This would be implemented by the view manager
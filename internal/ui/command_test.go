@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandManagerWideEditing(t *testing.T) {
+	cm := NewCommandManager()
+	cm.StartCommandMode()
+	for _, ch := range "log 日本語" {
+		assert.True(t, cm.HandleKey(tcell.KeyRune, ch, 0))
+	}
+	assert.Equal(t, "log 日本語", cm.GetBuffer())
+	assert.Equal(t, len("log 日本語"), cm.GetCursor())
+
+	// The cursor moves by characters, not bytes
+	cm.HandleKey(tcell.KeyLeft, 0, 0)
+	cm.HandleKey(tcell.KeyBackspace2, 0, 0)
+	assert.Equal(t, "log 日語", cm.GetBuffer())
+	cm.HandleKey(tcell.KeyRune, 'x', 0)
+	assert.Equal(t, "log 日x語", cm.GetBuffer())
+	cm.HandleKey(tcell.KeyDelete, 0, 0)
+	assert.Equal(t, "log 日x", cm.GetBuffer())
+	cm.HandleKey(tcell.KeyRight, 0, 0)
+	assert.Equal(t, len(cm.GetBuffer()), cm.GetCursor())
+
+	// An accented letter typed as a letter and its accent is one character,
+	// and so is an emoji made of several
+	cm.StartCommandModeWith("cafe\u0301 \U0001F469\u200d\U0001F4BB")
+	cm.HandleKey(tcell.KeyBackspace2, 0, 0)
+	assert.Equal(t, "cafe\u0301 ", cm.GetBuffer())
+	cm.HandleKey(tcell.KeyLeft, 0, 0)
+	cm.HandleKey(tcell.KeyLeft, 0, 0)
+	cm.HandleKey(tcell.KeyDelete, 0, 0)
+	assert.Equal(t, "caf ", cm.GetBuffer())
+	cm.MoveCursor(-10)
+	assert.Equal(t, 0, cm.GetCursor())
+}
+
+func TestDrawCommandLineWide(t *testing.T) {
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	term := newTestTerminal(t, cfg)
+	term.commandMode = true
+	term.commandMgr.StartCommandModeWith("log 日本")
+	term.commandMgr.MoveCursor(-1)
+	term.draw()
+
+	assert.Equal(t, ":log 日本", screenLines(term.screen)[23])
+	x, y, visible := term.screen.(tcell.SimulationScreen).GetCursor()
+	assert.True(t, visible)
+	assert.Equal(t, []int{7, 23}, []int{x, y})
+
+	// Long prompts scroll to keep the cursor on screen
+	term.commandMgr.StartCommandModeWith(strings.Repeat("語", 50))
+	term.draw()
+	x, _, _ = term.screen.(tcell.SimulationScreen).GetCursor()
+	assert.Equal(t, 79, x)
+	assert.Equal(t, strings.Repeat("語", 39), strings.TrimSpace(screenLines(term.screen)[23]))
+}
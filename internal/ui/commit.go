@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// startCommit prompts for the message of a commit of the staged changes
+func (vm *ViewManager) startCommit() error {
+	if err := vm.checkStaged(); err != nil {
+		return err
+	}
+	vm.commandRequest = "commit "
+	return nil
+}
+
+// checkStaged returns an error when there is nothing staged to commit
+func (vm *ViewManager) checkStaged() error {
+	if !vm.client.IsRepository() {
+		return fmt.Errorf("not in a git repository")
+	}
+	status, err := vm.client.GetStatus()
+	if err != nil {
+		return err
+	}
+	if len(status.Staged) == 0 {
+		return fmt.Errorf("nothing staged to commit, stage files with a or A in the status view")
+	}
+	return nil
+}
+
+// CommitCommand handles the :commit command, committing the staged
+// changes, the first commit of a new repository included
+func (vm *ViewManager) CommitCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	message := strings.TrimSpace(strings.Join(args, " "))
+	if message == "" {
+		return fmt.Errorf("usage: commit <message>")
+	}
+	if err := vm.checkStaged(); err != nil {
+		return err
+	}
+
+	if err := vm.client.Commit(message, nil); err != nil {
+		return err
+	}
+	if statusView, ok := vm.views[ViewTypeStatus].(*StatusView); ok {
+		_ = statusView.refreshChanged()
+	}
+	if head, err := vm.client.ResolveRevision("HEAD"); err == nil {
+		vm.setMessage("Committed %s %s", vm.client.AbbrevHash(head), message)
+	}
+	return vm.refreshAll()
+}
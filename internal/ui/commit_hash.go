@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"fmt"
+)
+
+// cursorCommitSource is implemented by views showing commits other than
+// the one they are about, such as the blame gutter of the diff view
+type cursorCommitSource interface {
+	// cursorCommit returns the commit shown under the cursor, empty when
+	// none is
+	cursorCommit() string
+}
+
+// cursorCommit returns the commit of the blame gutter at the top of the
+// diff, empty when blame is hidden or the line is an added one
+func (v *DiffView) cursorCommit() string {
+	if v.blame == nil || len(v.rows) == 0 {
+		return ""
+	}
+	row := v.rows[min(v.GetOffset(), len(v.rows)-1)]
+	if row.line == nil || row.line.OldLine <= 0 {
+		return ""
+	}
+	if commits := v.blame.commits[row.file.OldPath]; row.line.OldLine <= len(commits) {
+		return commits[row.line.OldLine-1].Commit
+	}
+	return ""
+}
+
+// commitAtCursor returns the full hash of the commit under the cursor of
+// the current view: the one of the blame gutter if shown, otherwise the
+// commit of the selection
+func (vm *ViewManager) commitAtCursor() (string, error) {
+	var commit string
+	if source, ok := vm.views[vm.currentView].(cursorCommitSource); ok {
+		commit = source.cursorCommit()
+	}
+	if commit == "" {
+		if source, ok := vm.views[vm.currentView].(SelectionSource); ok {
+			commit = source.Selection().Commit
+		}
+	}
+	if commit == "" {
+		return "", fmt.Errorf("no commit under the cursor")
+	}
+	return vm.client.ResolveRevision(commit)
+}
+
+// copyCommitHash copies the hash of the commit under the cursor to the
+// system clipboard
+func (vm *ViewManager) copyCommitHash() error {
+	hash, err := vm.commitAtCursor()
+	if err != nil {
+		return err
+	}
+	if err := writeClipboard(vm.config.General.CopyCommand, hash); err != nil {
+		return err
+	}
+	vm.setMessage("Copied %s", hash)
+	return nil
+}
+
+// showCommitAtCursor opens the commit under the cursor in the diff view
+func (vm *ViewManager) showCommitAtCursor() error {
+	hash, err := vm.commitAtCursor()
+	if err != nil {
+		return err
+	}
+	diffView, ok := vm.views[ViewTypeDiff].(*DiffView)
+	if !ok {
+		return fmt.Errorf("diff view not found")
+	}
+	diffView.SetCommitHash(hash)
+	return vm.switchView(ViewTypeDiff)
+}
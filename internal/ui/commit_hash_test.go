@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitAtCursor(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	mainView := vm.views[ViewTypeMain].(*MainView)
+	diffView := vm.views[ViewTypeDiff].(*DiffView)
+	require.NoError(t, vm.refreshAll())
+	require.NotEmpty(t, mainView.commits)
+	base := refsTestGit(t, dir, "rev-parse", "main")
+	topic := refsTestGit(t, dir, "rev-parse", "topic")
+
+	// The hash of the selected commit is piped to copy-command
+	copied := filepath.Join(t.TempDir(), "copied")
+	vm.config.General.CopyCommand = "tee " + copied
+	selected := mainView.commits[mainView.selected].Hash
+	vm.HandleKey(tcell.KeyRune, 'y', 0)
+	data, err := os.ReadFile(copied)
+	require.NoError(t, err)
+	assert.Equal(t, selected, string(data))
+	assert.Equal(t, "Copied "+selected, vm.GetMessage())
+
+	// The selected commit is opened in the diff view
+	vm.HandleKey(tcell.KeyRune, 'o', 0)
+	assert.Equal(t, ViewTypeDiff, vm.currentView)
+	assert.Equal(t, selected, diffView.GetCommitHash())
+
+	// The blamed commit of the line at the top of the diff is preferred to
+	// the commit the diff is about
+	vm.SetSize(100, 3)
+	diffView.SetCommitHash(topic)
+	vm.HandleKey(tcell.KeyRune, 'b', 0)
+	require.NotNil(t, diffView.blame)
+	for i := 0; diffView.cursorCommit() == ""; i++ {
+		require.Less(t, i, len(diffView.rows))
+		vm.HandleKey(tcell.KeyRune, 'j', 0)
+	}
+	assert.True(t, strings.HasPrefix(base, diffView.cursorCommit()))
+	vm.HandleKey(tcell.KeyRune, 'o', 0)
+	assert.Equal(t, base, diffView.GetCommitHash())
+
+	// Views without commits say so
+	require.NoError(t, vm.SwitchView(ViewTypeHelp))
+	vm.HandleKey(tcell.KeyRune, 'y', 0)
+	assert.Equal(t, "no commit under the cursor", vm.GetMessage())
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFirstCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	refsTestGit(t, dir, "init", "--quiet", "--initial-branch=main")
+	refsTestGit(t, dir, "config", "user.name", "Test User")
+	refsTestGit(t, dir, "config", "user.email", "test@example.com")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("first\n"), 0644))
+
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+	vm.SetSize(100, 30)
+	vm.SetRepoPath(dir)
+	mainView := vm.views[ViewTypeMain].(*MainView)
+	statusView := vm.views[ViewTypeStatus].(*StatusView)
+
+	// Views are empty, not in error, before the first commit
+	require.NoError(t, mainView.Refresh())
+	vm.Render()
+	assert.Contains(t, strings.Join(screenLines(screen), "\n"), "No commits yet — create the first commit")
+
+	require.NoError(t, vm.SwitchView(ViewTypeStatus))
+	require.NoError(t, statusView.refreshChanged())
+	vm.Render()
+	assert.Contains(t, strings.Join(screenLines(screen), "\n"), "No commits yet")
+
+	// Nothing is committed until staged
+	vm.HandleKey(tcell.KeyRune, 'c', 0)
+	assert.Empty(t, vm.commandRequest)
+	assert.Error(t, vm.CommitCommand([]string{"first"}))
+
+	vm.HandleKey(tcell.KeyRune, 'a', 0)
+	require.Len(t, statusView.status.Staged, 1)
+	vm.HandleKey(tcell.KeyRune, 'c', 0)
+	assert.Equal(t, "commit ", vm.commandRequest)
+
+	assert.EqualError(t, vm.CommitCommand(nil), "usage: commit <message>")
+	require.NoError(t, vm.CommitCommand([]string{"first", "commit"}))
+	assert.True(t, strings.HasPrefix(vm.GetMessage(), "Committed "))
+	assert.True(t, strings.HasSuffix(vm.GetMessage(), " first commit"))
+	assert.Empty(t, statusView.status.Staged)
+	assert.False(t, statusView.status.NoCommits)
+	require.NoError(t, mainView.Refresh())
+	require.Len(t, mainView.commits, 1)
+	assert.Equal(t, "first commit", mainView.commits[0].Message)
+}
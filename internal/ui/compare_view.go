@@ -0,0 +1,324 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// compareRow is a line of the compare view, either a section header or a
+// commit
+type compareRow struct {
+	text   string
+	commit *git.Commit
+}
+
+// CompareView shows the commits two branches don't share and their merge
+// base
+type CompareView struct {
+	*BaseView
+	*Scrollable
+	config     *config.Config
+	client     git.Client
+	comparison *git.BranchComparison
+	rows       []compareRow
+	selected   int
+	repoPath   string
+	frame      *Frame
+}
+
+// NewCompareView creates a new branch comparison view
+func NewCompareView(config *config.Config, client git.Client) *CompareView {
+	return &CompareView{
+		BaseView:   NewBaseView(ViewTypeCompare),
+		Scrollable: NewScrollable(),
+		config:     config,
+		client:     client,
+		frame:      NewFrame(config, "Compare"),
+	}
+}
+
+// SetComparison shows the given comparison
+func (v *CompareView) SetComparison(comparison *git.BranchComparison) {
+	v.comparison = comparison
+	v.rows = v.compareRows(comparison)
+	v.selected = 0
+	v.ScrollToTop()
+	v.selectNext(0, 1)
+
+	v.frame.Title = "Compare"
+	if comparison != nil {
+		v.frame.Title = fmt.Sprintf("Compare %s with %s", comparison.Ours, comparison.Theirs)
+	}
+}
+
+// compareRows lays out a comparison as a summary followed by the three
+// sections
+func (v *CompareView) compareRows(comparison *git.BranchComparison) []compareRow {
+	if comparison == nil {
+		return nil
+	}
+
+	rows := []compareRow{{text: compareSummary(comparison)}, {}}
+	addSection := func(title string, commits []*git.Commit) {
+		rows = append(rows, compareRow{text: fmt.Sprintf("%s (%d)", title, len(commits))})
+		for _, commit := range commits {
+			rows = append(rows, compareRow{text: "  " + v.client.AbbrevHash(commit.Hash) + " " + commit.Summary, commit: commit})
+		}
+		rows = append(rows, compareRow{})
+	}
+	addSection("Only in "+comparison.Ours, comparison.OnlyOurs)
+	addSection("Only in "+comparison.Theirs, comparison.OnlyTheirs)
+
+	rows = append(rows, compareRow{text: "Merge base"})
+	if base := comparison.Base; base != nil {
+		rows = append(rows, compareRow{text: "  " + v.client.AbbrevHash(base.Hash) + " " + base.Summary, commit: base})
+	}
+	return rows
+}
+
+// compareSummary describes how the branches relate and how they can be
+// combined
+func compareSummary(c *git.BranchComparison) string {
+	ours, theirs := len(c.OnlyOurs), len(c.OnlyTheirs)
+	switch {
+	case ours == 0 && theirs == 0:
+		return fmt.Sprintf("%s and %s point to the same commit", c.Ours, c.Theirs)
+	case theirs == 0:
+		return fmt.Sprintf("%s is already merged, %s is %d commits ahead", c.Theirs, c.Ours, ours)
+	case ours == 0:
+		return fmt.Sprintf("%s is %d commits behind %s and can be fast-forwarded", c.Ours, theirs, c.Theirs)
+	}
+	return fmt.Sprintf("%s is %d commits ahead and %d behind %s, merge or rebase to combine them", c.Ours, ours, theirs, c.Theirs)
+}
+
+// Render renders the compare view
+func (v *CompareView) Render(screen tcell.Screen, x, y, width, height int) error {
+	v.SetPosition(x, y, width, height)
+	v.SetHeight(height - 2) // Account for borders
+
+	// Draw the frame, and its scrollbar once the content is laid out
+	contentX, contentY, contentWidth, contentHeight := v.frame.Draw(screen, x, y, width, height)
+	defer v.frame.DrawScrollbar(screen, v.Scrollable)
+
+	if contentWidth <= 0 || contentHeight <= 0 {
+		return nil
+	}
+
+	if len(v.rows) == 0 {
+		msg := "No comparison, use :compare <branch>"
+		msgX := contentX + (contentWidth-len(msg))/2
+		msgY := contentY + contentHeight/2
+		if msgX >= contentX {
+			for i, char := range msg {
+				screen.SetContent(msgX+i, msgY, char, nil, tcell.StyleDefault)
+			}
+		}
+		return nil
+	}
+
+	v.SetMaxOffset(len(v.rows) - contentHeight)
+
+	start := v.GetOffset()
+	end := start + contentHeight
+	if end > len(v.rows) {
+		end = len(v.rows)
+	}
+
+	for i := start; i < end; i++ {
+		v.drawRow(screen, contentX, contentY+(i-start), contentWidth, i)
+	}
+
+	return nil
+}
+
+// drawRow draws a header or commit row
+func (v *CompareView) drawRow(screen tcell.Screen, x, y, width, index int) {
+	row := v.rows[index]
+
+	style := tcell.StyleDefault
+	if row.commit == nil {
+		style = style.Bold(true)
+	}
+	if index == 0 {
+		style = tcell.StyleDefault.Foreground(tcell.ColorYellow)
+	}
+	if index == v.selected && row.commit != nil {
+		if v.IsFocused() {
+			style = style.Background(tcell.ColorBlue).Foreground(tcell.ColorWhite)
+		} else {
+			style = style.Background(tcell.ColorDarkBlue).Foreground(tcell.ColorWhite)
+		}
+	}
+
+	var cells []cell
+	for _, ch := range row.text {
+		cells = append(cells, cell{ch, style})
+	}
+	if index == v.selected {
+		for cellsWidth(cells) < width {
+			cells = append(cells, cell{' ', style})
+		}
+	}
+	drawCells(screen, x, y, width, cells, 0, style)
+}
+
+// HandleKey handles keyboard input
+func (v *CompareView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
+	if !v.IsFocused() {
+		return false
+	}
+
+	switch key {
+	case tcell.KeyUp:
+		v.selectNext(v.selected-1, -1)
+		return true
+	case tcell.KeyDown:
+		v.selectNext(v.selected+1, 1)
+		return true
+	case tcell.KeyHome:
+		v.selectNext(0, 1)
+		return true
+	case tcell.KeyEnd:
+		v.selectNext(len(v.rows)-1, -1)
+		return true
+	}
+
+	switch ch {
+	case 'j':
+		v.selectNext(v.selected+1, 1)
+		return true
+	case 'k':
+		v.selectNext(v.selected-1, -1)
+		return true
+	case 'g':
+		v.selectNext(0, 1)
+		return true
+	case 'G':
+		v.selectNext(len(v.rows)-1, -1)
+		return true
+	}
+
+	return false
+}
+
+// selectNext selects the first commit row from index on in the given
+// direction, keeping the selection if there is none
+func (v *CompareView) selectNext(index, direction int) {
+	for i := index; i >= 0 && i < len(v.rows); i += direction {
+		if v.rows[i].commit == nil {
+			continue
+		}
+		v.selected = i
+
+		pageSize := v.getPageSize()
+		if v.selected < v.GetOffset() {
+			v.SetOffset(v.selected)
+		} else if pageSize > 0 && v.selected >= v.GetOffset()+pageSize {
+			v.SetOffset(v.selected - pageSize + 1)
+		}
+		return
+	}
+}
+
+// getPageSize returns the number of visible lines
+func (v *CompareView) getPageSize() int {
+	_, _, _, height := v.GetPosition()
+	return height - 2 // Account for borders
+}
+
+// Refresh compares the branches again
+func (v *CompareView) Refresh() error {
+	if v.comparison == nil || !v.client.IsRepository() {
+		return nil
+	}
+
+	var selectedHash string
+	if commit := v.GetSelectedCommit(); commit != nil {
+		selectedHash = commit.Hash
+	}
+	selected := v.selected
+
+	comparison, err := v.client.CompareBranches(v.comparison.Ours, v.comparison.Theirs)
+	if err != nil {
+		return err
+	}
+	v.SetComparison(comparison)
+
+	// Keep the selected commit selected when it is still listed
+	v.selected = findSelection(len(v.rows), selected, func(i int) bool {
+		return v.rows[i].commit != nil && v.rows[i].commit.Hash == selectedHash
+	})
+	v.selectNext(v.selected, 1)
+	return nil
+}
+
+// GetSelectedCommit returns the selected commit
+func (v *CompareView) GetSelectedCommit() *git.Commit {
+	if v.selected < 0 || v.selected >= len(v.rows) {
+		return nil
+	}
+	return v.rows[v.selected].commit
+}
+
+// DiffRange returns the range showing the changes of the selected commit
+func (v *CompareView) DiffRange() (string, []string, error) {
+	commit := v.GetSelectedCommit()
+	if commit == nil {
+		return "", nil, fmt.Errorf("no commit selected")
+	}
+	return commit.Hash + "^!", nil, nil
+}
+
+// Selection returns the selected commit for placeholder expansion
+func (v *CompareView) Selection() Selection {
+	var sel Selection
+	if commit := v.GetSelectedCommit(); commit != nil {
+		sel.Commit = commit.Hash
+	}
+	if v.comparison != nil {
+		sel.Ref = v.comparison.Theirs
+	}
+	return sel
+}
+
+// SetRepoPath sets the repository path
+func (v *CompareView) SetRepoPath(path string) {
+	v.repoPath = path
+	v.SetComparison(nil)
+}
+
+// CompareCommand handles the :compare command, comparing the current branch
+// with the given one
+func (vm *ViewManager) CompareCommand(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: compare <branch>")
+	}
+
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	view, ok := vm.views[ViewTypeCompare].(*CompareView)
+	if !ok {
+		return fmt.Errorf("compare view not found")
+	}
+	if err := vm.checkRevisions(args[0]); err != nil {
+		return err
+	}
+
+	// Name the current branch rather than HEAD where there is one
+	ours := "HEAD"
+	if head, err := vm.client.GetHead(); err == nil && strings.HasPrefix(head.Name, "refs/heads/") {
+		ours = strings.TrimPrefix(head.Name, "refs/heads/")
+	}
+
+	comparison, err := vm.client.CompareBranches(ours, args[0])
+	if err != nil {
+		return err
+	}
+	view.SetComparison(comparison)
+	return vm.switchView(ViewTypeCompare)
+}
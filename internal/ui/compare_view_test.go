@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareSummary(t *testing.T) {
+	c := &git.BranchComparison{Ours: "main", Theirs: "topic"}
+	assert.Equal(t, "main and topic point to the same commit", compareSummary(c))
+
+	c.OnlyTheirs = []*git.Commit{{Hash: "a"}}
+	assert.Equal(t, "main is 1 commits behind topic and can be fast-forwarded", compareSummary(c))
+
+	c.OnlyOurs = []*git.Commit{{Hash: "b"}, {Hash: "c"}}
+	assert.Equal(t, "main is 2 commits ahead and 1 behind topic, merge or rebase to combine them", compareSummary(c))
+
+	c.OnlyTheirs = nil
+	assert.Equal(t, "topic is already merged, main is 2 commits ahead", compareSummary(c))
+}
+
+func TestViewManagerCompare(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, output)
+	}
+	commit := func(name string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name+"\n"), 0644))
+		run("add", name)
+		run("commit", "--quiet", "-m", "Add "+name)
+	}
+
+	run("init", "--quiet", "--initial-branch=main")
+	run("config", "user.name", "Test User")
+	run("config", "user.email", "test@example.com")
+	commit("base")
+	run("checkout", "--quiet", "-b", "topic")
+	commit("one")
+	commit("two")
+	run("checkout", "--quiet", "main")
+	commit("three")
+
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+	vm.SetSize(80, 24)
+
+	assert.Error(t, vm.CompareCommand(nil))
+	require.NoError(t, vm.CompareCommand([]string{"topic"}))
+	assert.Equal(t, ViewTypeCompare, vm.GetCurrentView())
+
+	view := vm.GetView(ViewTypeCompare).(*CompareView)
+	var texts []string
+	for _, row := range view.rows {
+		if row.commit != nil {
+			texts = append(texts, row.commit.Summary)
+		} else {
+			texts = append(texts, row.text)
+		}
+	}
+	assert.Equal(t, []string{
+		"main is 1 commits ahead and 2 behind topic, merge or rebase to combine them",
+		"",
+		"Only in main (1)",
+		"Add three",
+		"",
+		"Only in topic (2)",
+		"Add two",
+		"Add one",
+		"",
+		"Merge base",
+		"Add base",
+	}, texts)
+
+	// Headers are skipped when moving the selection
+	assert.Equal(t, "Add three", view.GetSelectedCommit().Summary)
+	assert.True(t, vm.HandleKey(tcell.KeyDown, 0, 0))
+	assert.Equal(t, "Add two", view.GetSelectedCommit().Summary)
+
+	// A refresh keeps the selection
+	require.NoError(t, view.Refresh())
+	assert.Equal(t, "Add two", view.GetSelectedCommit().Summary)
+
+	// Enter shows the diff of the selected commit
+	assert.True(t, vm.HandleKey(tcell.KeyEnter, 0, 0))
+	assert.Equal(t, ViewTypeDiff, vm.GetCurrentView())
+	diffView := vm.GetView(ViewTypeDiff).(*DiffView)
+	if assert.NotNil(t, diffView.diff) && assert.Len(t, diffView.diff.Files, 1) {
+		assert.Equal(t, "two", diffView.diff.Files[0].Path())
+	}
+}
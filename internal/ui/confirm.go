@@ -0,0 +1,210 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/gdamore/tcell/v2"
+)
+
+// confirmation is an action waiting for the user to agree to it after
+// seeing exactly what it will do
+type confirmation struct {
+	prompt  string
+	details []string
+	action  func() error
+	options []confirmOption // Answers offered instead of yes or no
+	name    string          // Typed to confirm instead of y, when set
+	typed   string
+	input   func(text string, ok bool) // Passed what is typed instead of running an action, when set
+	secret  bool                       // What is typed is masked
+}
+
+// confirmOption is one of several answers of a confirmation, run when the
+// first letter of its name is pressed
+type confirmOption struct {
+	name   string // Such as "stash"
+	action func() error
+}
+
+// askConfirmation shows the details of an action and runs it once y is
+// pressed, any other key cancelling it
+func (vm *ViewManager) askConfirmation(prompt string, details []string, action func() error) {
+	vm.confirmation = &confirmation{prompt: prompt, details: details, action: action}
+}
+
+// askName shows the details of an action and runs it once name, that of
+// what it destroys, is typed and Enter pressed, Esc cancelling it
+func (vm *ViewManager) askName(prompt, name string, details []string, action func() error) {
+	vm.confirmation = &confirmation{prompt: prompt, details: details, action: action, name: name}
+}
+
+// askInput shows a prompt and passes what is typed to answer once Enter is
+// pressed, or nothing with ok false once Esc is. Secret answers are
+// masked. A prompt still waiting for its answer is cancelled.
+func (vm *ViewManager) askInput(prompt string, details []string, secret bool, answer func(text string, ok bool)) {
+	if pending := vm.confirmation; pending != nil && pending.input != nil {
+		pending.input("", false)
+	}
+	vm.confirmation = &confirmation{prompt: prompt, details: details, input: answer, secret: secret}
+}
+
+// confirmAction runs a destructive action as confirmed at the level the
+// configuration sets for it: straight away, once y is pressed, or once the
+// name of what it destroys is typed
+func (vm *ViewManager) confirmAction(action, prompt, name string, details []string, run func() error) error {
+	switch vm.config.ConfirmLevel(action) {
+	case config.ConfirmNone:
+		return run()
+	case config.ConfirmName:
+		vm.askName(prompt, name, details, run)
+	default:
+		vm.askConfirmation(prompt, details, run)
+	}
+	return nil
+}
+
+// askChoice shows the details of an action and runs the option whose first
+// letter is pressed, any other key aborting
+func (vm *ViewManager) askChoice(prompt string, details []string, options ...confirmOption) {
+	vm.confirmation = &confirmation{prompt: prompt, details: details, options: options}
+}
+
+// Confirming returns whether an action is waiting for confirmation
+func (vm *ViewManager) Confirming() bool {
+	vm.mutex.RLock()
+	defer vm.mutex.RUnlock()
+
+	return vm.confirmation != nil
+}
+
+// SecretInput returns whether a prompt masking what is typed is open
+func (vm *ViewManager) SecretInput() bool {
+	vm.mutex.RLock()
+	defer vm.mutex.RUnlock()
+
+	return vm.confirmation != nil && vm.confirmation.secret
+}
+
+// answerConfirmation runs the pending action when the key confirms it
+func (vm *ViewManager) answerConfirmation(key tcell.Key, ch rune) {
+	pending := vm.confirmation
+	vm.confirmation = nil
+
+	if pending.name != "" || pending.input != nil {
+		switch {
+		case key == tcell.KeyEsc:
+			if pending.input != nil {
+				pending.input("", false)
+			}
+			vm.setMessage("Cancelled")
+			return
+		case key == tcell.KeyEnter && pending.input != nil:
+			pending.input(pending.typed, true)
+			return
+		case key == tcell.KeyEnter && pending.typed != pending.name:
+			vm.setMessage("Cancelled, %q is not %q", pending.typed, pending.name)
+			return
+		case key == tcell.KeyBackspace || key == tcell.KeyBackspace2:
+			typed := []rune(pending.typed)
+			pending.typed = string(typed[:max(0, len(typed)-1)])
+			vm.confirmation = pending
+			return
+		case key == tcell.KeyRune:
+			pending.typed += string(ch)
+			vm.confirmation = pending
+			return
+		case key != tcell.KeyEnter:
+			vm.confirmation = pending
+			return
+		}
+		if err := pending.action(); err != nil {
+			vm.setMessage("%v", err)
+		}
+		return
+	}
+	if len(pending.options) > 0 {
+		for _, option := range pending.options {
+			if key == tcell.KeyRune && unicode.ToLower(ch) == rune(option.name[0]) {
+				if err := option.action(); err != nil {
+					vm.setMessage("%v", err)
+				}
+				return
+			}
+		}
+		vm.setMessage("Aborted")
+		return
+	}
+	if key != tcell.KeyRune || (ch != 'y' && ch != 'Y') {
+		vm.setMessage("Cancelled")
+		return
+	}
+	if err := pending.action(); err != nil {
+		vm.setMessage("%v", err)
+	}
+}
+
+// drawConfirmation draws the pending confirmation over the current view
+func (vm *ViewManager) drawConfirmation() {
+	c := vm.confirmation
+	boxWidth := min(72, vm.width-4)
+	boxHeight := min(len(c.details)+5, vm.height-2)
+	if boxWidth < 20 || boxHeight < 5 || (len(c.details) > 0 && boxHeight < 6) {
+		return
+	}
+	x := (vm.width - boxWidth) / 2
+	y := max(1, (vm.height-boxHeight)/2)
+
+	title := "Confirm"
+	if c.input != nil {
+		title = "Input"
+	}
+	drawPopupBox(vm.screen, title, tcell.StyleDefault.Foreground(tcell.ColorYellow), x, y, boxWidth, boxHeight)
+
+	// The details are cut short when they don't fit, saying how many are left
+	innerX, innerWidth := x+2, boxWidth-4
+	rows := boxHeight - 5
+	details := c.details
+	if len(details) > rows {
+		more := len(details) - rows + 1
+		details = append(details[:rows-1:rows-1], fmt.Sprintf("... and %d more", more))
+	}
+	for i, line := range details {
+		drawCells(vm.screen, innerX, y+1+i, innerWidth, textCells(line, tcell.StyleDefault), 0, tcell.StyleDefault)
+	}
+
+	answers := "[y/N]"
+	if len(c.options) > 0 {
+		// Such as [s]tash [f]orce [A]bort
+		answers = ""
+		for _, option := range c.options {
+			answers += fmt.Sprintf("[%c]%s ", option.name[0], option.name[1:])
+		}
+		answers += "[A]bort"
+	}
+	if c.name != "" {
+		// Such as Type main to confirm: ma
+		answers = fmt.Sprintf("Type %s to confirm: %s", c.name, c.typed)
+	}
+	if c.input != nil {
+		answers = c.typed
+		if c.secret {
+			answers = strings.Repeat("*", len([]rune(c.typed)))
+		}
+	}
+	prompt := textCells(c.prompt+" ", tcell.StyleDefault.Bold(true))
+	prompt = append(prompt, textCells(answers, tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true))...)
+	drawCells(vm.screen, innerX, y+boxHeight-3, innerWidth, prompt, 0, tcell.StyleDefault)
+}
+
+// drawPopupBox draws an empty box over whatever is below it
+func drawPopupBox(screen tcell.Screen, title string, style tcell.Style, x, y, width, height int) {
+	for row := y; row < y+height; row++ {
+		for col := x; col < x+width; col++ {
+			screen.SetContent(col, row, ' ', nil, tcell.StyleDefault)
+		}
+	}
+	NewDrawBox(title, style).Draw(screen, x, y, width, height)
+}
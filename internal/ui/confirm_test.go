@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmLevels(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	base := refsTestGit(t, dir, "rev-parse", "HEAD")
+	refsTestGit(t, dir, "commit", "--quiet", "--allow-empty", "-m", "extra")
+
+	// A reset asks y/n by default, whatever its mode
+	require.NoError(t, vm.ResetBranchCommand([]string{"--soft", base}))
+	require.True(t, vm.Confirming())
+	assert.Equal(t, "Reset main --soft to "+vm.client.AbbrevHash(base)+"?", vm.confirmation.prompt)
+	vm.HandleKey(tcell.KeyRune, 'n', 0)
+	assert.NotEqual(t, base, refsTestGit(t, dir, "rev-parse", "HEAD"))
+	require.NoError(t, vm.ResetBranchCommand([]string{"--hard", base}))
+	vm.HandleKey(tcell.KeyRune, 'y', 0)
+	assert.False(t, vm.Confirming())
+	assert.Equal(t, base, refsTestGit(t, dir, "rev-parse", "HEAD"))
+
+	// Asked to, it waits for the branch to be typed, anything else
+	// cancelling it
+	refsTestGit(t, dir, "reset", "--quiet", "--hard", "topic")
+	vm.config.General.Confirm = map[string]string{"reset-hard": "name", "discard": "simple"}
+	require.NoError(t, vm.ResetBranchCommand([]string{"--hard", base}))
+	require.True(t, vm.Confirming())
+	assert.Equal(t, "Reset main --hard to "+vm.client.AbbrevHash(base)+"?", vm.confirmation.prompt)
+	for _, ch := range "mian" {
+		vm.HandleKey(tcell.KeyRune, ch, 0)
+	}
+	vm.HandleKey(tcell.KeyBackspace2, 0, 0)
+	vm.HandleKey(tcell.KeyBackspace2, 0, 0)
+	assert.Equal(t, "mi", vm.confirmation.typed)
+	vm.HandleKey(tcell.KeyEnter, 0, 0)
+	assert.False(t, vm.Confirming())
+	assert.Equal(t, `Cancelled, "mi" is not "main"`, vm.GetMessage())
+
+	require.NoError(t, vm.ResetBranchCommand([]string{"--hard", base}))
+	for _, ch := range "main" {
+		vm.HandleKey(tcell.KeyRune, ch, 0)
+	}
+	vm.HandleKey(tcell.KeyEnter, 0, 0)
+	assert.Equal(t, base, refsTestGit(t, dir, "rev-parse", "HEAD"))
+
+	// Discarding the changes to a file asks y/n once set to simple
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("mine\n"), 0644))
+	require.NoError(t, vm.SwitchView(ViewTypeStatus))
+	statusView := vm.views[ViewTypeStatus].(*StatusView)
+	require.NoError(t, statusView.refreshChanged())
+	require.Equal(t, "file.txt", statusView.GetSelectedFile().Path)
+	vm.HandleKey(tcell.KeyRune, 'd', 0)
+	require.True(t, vm.Confirming())
+	assert.Equal(t, "Discard the changes to file.txt?", vm.confirmation.prompt)
+	vm.HandleKey(tcell.KeyRune, 'y', 0)
+	content, err := os.ReadFile(filepath.Join(dir, "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "base\n", string(content))
+}
@@ -0,0 +1,210 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// controlCall is posted to the event loop by the control socket to run a
+// command line as if typed at the prompt, sending back how it went
+type controlCall struct {
+	line  string
+	reply chan error
+}
+
+// ErrNoInstance is returned by SendControl when no tig listens on the
+// control socket
+var ErrNoInstance = errors.New("no tig listens")
+
+// ErrBusy is returned by SendControl when the tig listening on the control
+// socket doesn't answer in time, as when it runs an editor
+var ErrBusy = errors.New("tig is busy")
+
+// controlTimeout is how long SendControl waits for a command to be run
+var controlTimeout = 5 * time.Second
+
+// errControlClosed answers a command sent to a tig which quit before
+// getting to it
+var errControlClosed = errors.New("tig quit before running the command")
+
+// ControlSocket returns the control socket the control-socket option sets
+// for the repository opened by client, or for repoPath outside of one,
+// empty when there is none. single-instance needs one, auto unless set.
+func ControlSocket(cfg *config.Config, client git.Client, repoPath string) (string, error) {
+	root := repoPath
+	if client.IsRepository() {
+		root = client.GetRootPath()
+	}
+	value := cfg.General.ControlSocket
+	if value == "" && cfg.General.SingleInstance {
+		value = "auto"
+	}
+	return config.ControlSocketPath(value, root)
+}
+
+// HandOver shows the view startup-view asks for in the tig already running
+// for the repository, when single-instance is set, returning whether there
+// was one to take over. A busy one is not taken over, nor is another
+// started.
+func HandOver(cfg *config.Config, client git.Client, repoPath string) (bool, error) {
+	if !cfg.General.SingleInstance {
+		return false, nil
+	}
+	path, err := ControlSocket(cfg, client, repoPath)
+	if err != nil {
+		return false, err
+	}
+	err = SendControl(path, "focus "+cfg.General.StartupView)
+	switch {
+	case errors.Is(err, ErrNoInstance):
+		return false, nil
+	case errors.Is(err, ErrBusy):
+		// Starting another one would fight the busy one for the socket
+		return true, fmt.Errorf("%w, as when it runs an editor; try again once it is done", err)
+	}
+	return true, err
+}
+
+// SendControl sends a command line to the tig listening on a control
+// socket, returning the error it ran into. A tig which doesn't run the
+// command within controlTimeout gives ErrBusy.
+func SendControl(path, line string) error {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return fmt.Errorf("%w on %s: %v", ErrNoInstance, path, err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(controlTimeout)); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(conn, strings.TrimSpace(line)+"\n"); err != nil {
+		return controlError(path, err)
+	}
+	if unix, ok := conn.(*net.UnixConn); ok {
+		_ = unix.CloseWrite()
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && reply == "" {
+		return controlError(path, err)
+	}
+	reply = strings.TrimSpace(reply)
+	if message, ok := strings.CutPrefix(reply, "error: "); ok {
+		return errors.New(message)
+	}
+	if reply != "ok" {
+		return fmt.Errorf("unexpected reply: %s", reply)
+	}
+	return nil
+}
+
+// controlError explains an error talking to the tig listening on path,
+// running out of time meaning it is busy
+func controlError(path string, err error) error {
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return fmt.Errorf("%w on %s", ErrBusy, path)
+	}
+	return fmt.Errorf("no reply from %s: %w", path, err)
+}
+
+// listenControl runs the command lines sent to the socket at path, one per
+// line, as if typed at the prompt, so that editors and other programs can
+// drive this tig. Each line is answered with ok or error: and what went
+// wrong. The returned function stops listening.
+func (t *Terminal) listenControl(path string) (func(), error) {
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("another tig listens on %s", path)
+	}
+	// A socket left behind by a tig which did not exit cleanly
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		os.Remove(path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	// Only the user may drive their tig
+	listener, err := listenUnix(path)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go t.serveControl(conn)
+		}
+	}()
+	return func() { listener.Close() }, nil
+}
+
+// serveControl runs the command lines sent on conn until it is closed, or
+// until the event loop stops and no longer runs them
+func (t *Terminal) serveControl(conn net.Conn) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		err := t.callControl(line)
+		answer := "ok"
+		if err != nil {
+			answer = "error: " + strings.ReplaceAll(err.Error(), "\n", " ")
+		}
+		if _, err := io.WriteString(conn, answer+"\n"); err != nil {
+			return
+		}
+		if err == errControlClosed {
+			return
+		}
+	}
+}
+
+// callControl posts a command line to the event loop and waits until it
+// has run, waiting while the queue is full. It gives errControlClosed once
+// the event loop stopped.
+func (t *Terminal) callControl(line string) error {
+	reply := make(chan error, 1)
+	ev := tcell.NewEventInterrupt(controlCall{line: line, reply: reply})
+	for t.screen.PostEvent(ev) != nil {
+		select {
+		case <-t.done:
+			return errControlClosed
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	select {
+	case err := <-reply:
+		return err
+	case <-t.done:
+		return errControlClosed
+	}
+}
+
+// runControl runs a command line sent to the control socket, showing what
+// went wrong as the prompt does
+func (t *Terminal) runControl(line string) error {
+	err := t.commandMgr.Run(line)
+	if err != nil {
+		t.viewManager.SetMessage("%v", err)
+	}
+	return err
+}
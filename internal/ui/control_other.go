@@ -0,0 +1,11 @@
+//go:build !unix
+
+package ui
+
+import "net"
+
+// listenUnix listens on a socket at path, which is as private as the
+// directory it is in here
+func listenUnix(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestControlSocket(t *testing.T) {
+	dir, _ := autostashTestRepo(t)
+	base := refsTestGit(t, dir, "rev-parse", "--short", "main")
+
+	socket := filepath.Join(t.TempDir(), "tig.sock")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	cfg.General.RefreshInterval = 0
+	cfg.General.ControlSocket = socket
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	driver, err := NewDriver(cfg, client, dir, 100, 30)
+	require.NoError(t, err)
+	require.NoError(t, driver.Start())
+
+	// Only the user may drive their tig
+	info, err := os.Stat(socket)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	require.NoError(t, SendControl(socket, "show commit topic"))
+	view, err := driver.CurrentView()
+	require.NoError(t, err)
+	assert.Equal(t, ViewTypeDiff, view)
+
+	require.NoError(t, SendControl(socket, "blame "+filepath.Join(dir, "file.txt")+":1"))
+	message, err := driver.Message()
+	require.NoError(t, err)
+	assert.Equal(t, "Line 1 of file.txt was last changed by "+base, message)
+
+	// Lines not committed yet are shown in the file
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes\nmore\n"), 0644))
+	require.NoError(t, SendControl(socket, "blame notes.txt:2"))
+	view, err = driver.CurrentView()
+	require.NoError(t, err)
+	assert.Equal(t, ViewTypeBlob, view)
+	message, err = driver.Message()
+	require.NoError(t, err)
+	assert.Equal(t, "Line 2 of notes.txt is not committed yet", message)
+
+	assert.EqualError(t, SendControl(socket, "blame notes.txt:9"), "notes.txt has no line 9")
+	assert.EqualError(t, SendControl(socket, "frobnicate"), "unknown command: frobnicate")
+
+	// A single tig listens on a socket
+	_, err = driver.terminal.listenControl(socket)
+	assert.EqualError(t, err, "another tig listens on "+socket)
+
+	require.NoError(t, driver.Stop())
+	_, err = os.Stat(socket)
+	assert.True(t, os.IsNotExist(err))
+	assert.Error(t, SendControl(socket, "show commit main"))
+}
+
+func TestControlSocketBusy(t *testing.T) {
+	saved := controlTimeout
+	controlTimeout = 50 * time.Millisecond
+	defer func() { controlTimeout = saved }()
+
+	// A tig running an editor takes the command but doesn't get to it
+	socket := filepath.Join(t.TempDir(), "tig.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			_, _ = io.Copy(io.Discard, conn)
+			time.Sleep(time.Second)
+		}
+	}()
+
+	err = SendControl(socket, "show commit main")
+	assert.ErrorIs(t, err, ErrBusy)
+	assert.EqualError(t, err, "tig is busy on "+socket)
+}
+
+func TestControlSocketQuit(t *testing.T) {
+	dir, _ := autostashTestRepo(t)
+	socket := filepath.Join(t.TempDir(), "tig.sock")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	cfg.General.RefreshInterval = 0
+	cfg.General.ControlSocket = socket
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	driver, err := NewDriver(cfg, client, dir, 100, 30)
+	require.NoError(t, err)
+	require.NoError(t, driver.Start())
+
+	// The command is still queued when tig quits
+	release := make(chan struct{})
+	require.NoError(t, driver.post(tcell.NewEventInterrupt(driverCall(func() {
+		<-release
+		driver.terminal.running.Store(false)
+	}))))
+	conn, err := net.Dial("unix", socket)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = io.WriteString(conn, "show commit main\n")
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond) // Let tig take the command
+	close(release)
+
+	// The peer is told instead of being left waiting
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "error: tig quit before running the command\n", reply)
+	assert.NoError(t, driver.Stop())
+}
+
+func TestHandOver(t *testing.T) {
+	dir, _ := autostashTestRepo(t)
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	cfg.General.RefreshInterval = 0
+	cfg.General.SingleInstance = true
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+
+	// Without a tig running for the repository, this one starts
+	handed, err := HandOver(cfg, client, dir)
+	require.NoError(t, err)
+	assert.False(t, handed)
+
+	driver, err := NewDriver(cfg, client, dir, 100, 30)
+	require.NoError(t, err)
+	require.NoError(t, driver.Start())
+	defer driver.Stop()
+
+	// Another one started for the status view switches this one to it
+	other, err := config.Load()
+	require.NoError(t, err)
+	other.General.SingleInstance = true
+	require.NoError(t, other.Set("startup-view", "status"))
+	handed, err = HandOver(other, client, filepath.Join(dir, "."))
+	require.NoError(t, err)
+	assert.True(t, handed)
+	view, err := driver.CurrentView()
+	require.NoError(t, err)
+	assert.Equal(t, ViewTypeStatus, view)
+	message, err := driver.Message()
+	require.NoError(t, err)
+	assert.Equal(t, "Another tig was started for this repository", message)
+
+	// A busy tig is left alone, without starting another one
+	saved := controlTimeout
+	controlTimeout = 50 * time.Millisecond
+	defer func() { controlTimeout = saved }()
+	done := make(chan struct{})
+	require.NoError(t, driver.post(tcell.NewEventInterrupt(driverCall(func() { <-done }))))
+	handed, err = HandOver(other, client, dir)
+	close(done)
+	assert.True(t, handed)
+	assert.ErrorIs(t, err, ErrBusy)
+
+	// Starting another tig is up to the option
+	other.General.SingleInstance = false
+	handed, err = HandOver(other, client, dir)
+	require.NoError(t, err)
+	assert.False(t, handed)
+}
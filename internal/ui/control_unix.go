@@ -0,0 +1,17 @@
+//go:build unix
+
+package ui
+
+import (
+	"net"
+	"syscall"
+)
+
+// listenUnix listens on a socket at path which only the user may connect
+// to. The umask is narrowed while the socket is created, so that it never
+// exists with wider permissions.
+func listenUnix(path string) (net.Listener, error) {
+	mask := syscall.Umask(0177)
+	defer syscall.Umask(mask)
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/azhao1981/tig/internal/config"
+)
+
+// relativeDateWidth is the width of the date column showing relative
+// dates, enough for the longest label but a few
+const relativeDateWidth = 14
+
+// dateUnits are the units relative dates are counted in, each used up to
+// the limit where the next one takes over
+var dateUnits = []struct {
+	name  string
+	size  time.Duration
+	limit time.Duration
+}{
+	{"second", time.Second, time.Minute},
+	{"minute", time.Minute, time.Hour},
+	{"hour", time.Hour, 24 * time.Hour},
+	{"day", 24 * time.Hour, 14 * 24 * time.Hour},
+	{"week", 7 * 24 * time.Hour, 60 * 24 * time.Hour},
+	{"month", 30 * 24 * time.Hour, 365 * 24 * time.Hour},
+	{"year", 365 * 24 * time.Hour, 0},
+}
+
+// formatDate returns a commit date as the date-display option asks for: in
+// the time zone of the commit, in the local time zone, or relative to now
+func formatDate(cfg *config.Config, date, now time.Time) string {
+	switch cfg.Git.DateDisplay {
+	case "local":
+		return date.Local().Format("2006-01-02")
+	case "relative":
+		return fmt.Sprintf("%-*s", relativeDateWidth, relativeDate(date, now))
+	}
+	return date.Format("2006-01-02")
+}
+
+// relativeDate returns how long before now a date is, such as 5 minutes
+// ago, in the largest unit it counts at least one of
+func relativeDate(date, now time.Time) string {
+	d := now.Sub(date)
+	format := "%d %s ago"
+	if d < 0 {
+		d, format = -d, "in %d %s"
+	}
+
+	for _, unit := range dateUnits {
+		if unit.limit != 0 && d >= unit.limit {
+			continue
+		}
+		n := int(d / unit.size)
+		name := unit.name
+		if n != 1 {
+			name += "s"
+		}
+		return fmt.Sprintf(format, n, name)
+	}
+	return ""
+}
+
+// ShowsRelativeDates returns whether the current view shows dates relative
+// to now, which need redrawing as time passes
+func (vm *ViewManager) ShowsRelativeDates() bool {
+	vm.mutex.RLock()
+	defer vm.mutex.RUnlock()
+	return vm.currentView == ViewTypeMain && vm.config.Views.Main.ShowDate && vm.config.Git.DateDisplay == "relative"
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelativeDate(t *testing.T) {
+	now := time.Date(2024, 5, 12, 12, 0, 0, 0, time.UTC)
+	for ago, want := range map[time.Duration]string{
+		0:                    "0 seconds ago",
+		time.Second:          "1 second ago",
+		5 * time.Minute:      "5 minutes ago",
+		90 * time.Minute:     "1 hour ago",
+		3 * 24 * time.Hour:   "3 days ago",
+		20 * 24 * time.Hour:  "2 weeks ago",
+		100 * 24 * time.Hour: "3 months ago",
+		800 * 24 * time.Hour: "2 years ago",
+		-2 * time.Hour:       "in 2 hours",
+	} {
+		assert.Equal(t, want, relativeDate(now.Add(-ago), now), ago)
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	cfg := &config.Config{}
+	zone := time.FixedZone("east", 14*60*60)
+	date := time.Date(2024, 5, 12, 23, 0, 0, 0, zone)
+	now := date.Add(5 * time.Minute)
+
+	assert.Equal(t, "2024-05-12", formatDate(cfg, date, now))
+	cfg.Git.DateDisplay = "local"
+	assert.Equal(t, date.Local().Format("2006-01-02"), formatDate(cfg, date, now))
+	cfg.Git.DateDisplay = "relative"
+	assert.Equal(t, "5 minutes ago ", formatDate(cfg, date, now))
+}
+
+func TestMainViewRelativeDates(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	cfg.Views.Main.ShowDate = true
+	cfg.Git.DateDisplay = "relative"
+
+	vm := NewViewManager(screen, cfg, git.NewClient(), NewKeyBindingManager(cfg))
+	mainView := vm.views[ViewTypeMain].(*MainView)
+	mainView.commits = []*git.Commit{
+		{Hash: "a", Summary: "First", Author: git.Signature{Time: time.Now().Add(-3 * time.Hour)}},
+	}
+	require.NoError(t, mainView.Render(screen, 0, 0, 80, 24))
+	assert.True(t, strings.HasPrefix(screenLines(screen)[1], "│ 3 hours ago    First"), screenLines(screen)[1])
+
+	// Only views showing relative dates need redrawing as time passes
+	require.NoError(t, vm.SwitchView(ViewTypeMain))
+	assert.True(t, vm.ShowsRelativeDates())
+	require.NoError(t, vm.SwitchView(ViewTypeStatus))
+	assert.False(t, vm.ShowsRelativeDates())
+	require.NoError(t, vm.SwitchView(ViewTypeMain))
+	cfg.Git.DateDisplay = "absolute"
+	assert.False(t, vm.ShowsRelativeDates())
+}
+
+func TestRequestDraw(t *testing.T) {
+	cfg := &config.Config{}
+	term := newTestTerminal(t, cfg)
+
+	// Redraws asked for from other goroutines happen on the event loop
+	term.requestDraw()
+	ev, ok := term.screen.PollEvent().(*tcell.EventInterrupt)
+	require.True(t, ok)
+	assert.Equal(t, redraw{}, ev.Data())
+	assert.NoError(t, term.handleEvent(ev))
+}
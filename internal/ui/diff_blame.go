@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// diffBlame holds the commits which last changed the lines of the old side
+// of a diff, shown in a gutter left of the rows
+type diffBlame struct {
+	base    string                     // Revision the old side was taken from
+	commits map[string][]git.BlameLine // Commits by old line, keyed by old path
+	width   int                        // Width of the abbreviated commits
+}
+
+// blameStyle is the style of the blame gutter
+var blameStyle = tcell.StyleDefault.Foreground(tcell.ColorBlue)
+
+// toggleBlame shows or hides the commits which last changed the old lines
+func (v *DiffView) toggleBlame() {
+	if v.blame != nil {
+		v.blame = nil
+		return
+	}
+	v.blame = &diffBlame{commits: make(map[string][]git.BlameLine), width: 7}
+	v.loadBlame()
+}
+
+// loadBlame blames the files of the diff not blamed yet. Files which can't
+// be blamed, such as those of root commits, are left unannotated.
+func (v *DiffView) loadBlame() {
+	if v.blame == nil || v.diff == nil {
+		return
+	}
+
+	base, err := v.blameBase()
+	if err != nil {
+		return
+	}
+	if base != v.blame.base {
+		v.blame.base = base
+		v.blame.commits = make(map[string][]git.BlameLine)
+	}
+
+	for _, file := range v.diff.Files {
+		if file.IsNew || file.IsBinary || file.OldPath == "" {
+			continue
+		}
+		if _, ok := v.blame.commits[file.OldPath]; ok {
+			continue
+		}
+		commits, err := v.client.Blame(base, file.OldPath, &git.BlameOptions{
+			IgnoreRevs: strings.Fields(v.config.Git.BlameIgnoreRevs),
+		})
+		if err != nil {
+			continue
+		}
+		for i := range commits {
+			commits[i].Commit = v.client.AbbrevHash(commits[i].Commit)
+			v.blame.width = max(v.blame.width, len(commits[i].Commit))
+		}
+		v.blame.commits[file.OldPath] = commits
+	}
+}
+
+// blameBase returns the revision the old side of the diff was taken from
+func (v *DiffView) blameBase() (string, error) {
+	if v.stage != nil {
+		// Lines of the worktree and the index come from HEAD at best
+		return "HEAD", nil
+	}
+	if v.revRange == "" {
+		return v.commitHash + "^", nil
+	}
+
+	orHead := func(rev string) string {
+		if rev == "" {
+			return "HEAD"
+		}
+		return rev
+	}
+	if left, right, ok := strings.Cut(v.revRange, "..."); ok {
+		return v.client.MergeBase(orHead(left), orHead(right))
+	}
+	if left, _, ok := strings.Cut(v.revRange, ".."); ok {
+		return orHead(left), nil
+	}
+	return v.revRange, nil
+}
+
+// blameGutter returns the gutter of a row, holding the commit which last
+// changed the line for lines of the old side. Like git blame does with
+// blame.markIgnoredLines, a ? follows the commits lines were attributed to
+// past an ignored revision.
+func (v *DiffView) blameGutter(row diffRow) string {
+	var line git.BlameLine
+	if row.line != nil && row.line.OldLine > 0 {
+		if commits := v.blame.commits[row.file.OldPath]; row.line.OldLine <= len(commits) {
+			line = commits[row.line.OldLine-1]
+		}
+	}
+	return blameGutter(line, v.blame.width)
+}
+
+// blameGutter returns the gutter of a blamed line, its commit padded to
+// width followed by ? when it was attributed past an ignored revision
+func blameGutter(line git.BlameLine, width int) string {
+	mark := " "
+	if line.Ignored {
+		mark = "?"
+	}
+	return fmt.Sprintf("%-*s%s", width, line.Commit, mark)
+}
+
+// blameWidth returns the columns taken by the blame gutter, 0 when hidden
+func (v *DiffView) blameWidth() int {
+	if v.blame == nil {
+		return 0
+	}
+	return v.blame.width + 1
+}
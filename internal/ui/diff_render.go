@@ -0,0 +1,267 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// diffRow is a rendered line of a diff along with the model it came from
+type diffRow struct {
+	text    string
+	style   tcell.Style
+	colored string        // Text as colored by git, drawn instead of text if set
+	file    *git.DiffFile // File the row belongs to
+	hunk    int           // Index of the hunk within the file, -1 for file headers
+	line    *git.DiffLine // Diff line of the row, nil for headers
+}
+
+// cells returns the cells of the row with the whitespace indicators, in
+// the colors of git if it colored the row
+func (r diffRow) cells(marks whitespaceMarks, trailingFrom int) []cell {
+	if r.colored != "" {
+		return marks.markCells(ansiCells(r.colored, r.style), trailingFrom)
+	}
+	return marks.cells(r.text, r.style, trailingFrom)
+}
+
+// newLine returns the new side line number the row maps to, 0 if none.
+// Deleted lines map to the line that replaced them.
+func (r diffRow) newLine() int {
+	if r.line == nil {
+		if r.hunk >= 0 {
+			return r.file.Hunks[r.hunk].NewStart
+		}
+		return 0
+	}
+	if r.line.Type == git.DiffLineDeletion || r.line.Type == git.DiffLineNoNewline {
+		return r.nextNewLine()
+	}
+	return r.line.NewLine
+}
+
+// nextNewLine returns the new side line number following a row without one
+func (r diffRow) nextNewLine() int {
+	hunk := r.file.Hunks[r.hunk]
+	newLine := hunk.NewStart
+	for _, line := range hunk.Lines {
+		if line == r.line {
+			break
+		}
+		if line.Type == git.DiffLineAddition || line.Type == git.DiffLineContext {
+			newLine = line.NewLine + 1
+		}
+	}
+	return newLine
+}
+
+// renderDiff turns a diff into styled rows. Files folded in the given set
+// are reduced to their first header row, and binary files are followed by
+// their description, if any.
+func renderDiff(diff *git.Diff, folds foldSet, binaryInfo map[string][]string) []diffRow {
+	var rows []diffRow
+	if diff == nil {
+		return rows
+	}
+
+	for _, file := range diff.Files {
+		rows = append(rows, renderDiffFile(file, folds[file.Path()], binaryInfo[file.Path()])...)
+	}
+	return rows
+}
+
+// renderCommitHeader turns the commit a diff belongs to into rows like the
+// header of git show, its trailers set apart in a section of their own
+func renderCommitHeader(commit *git.Commit) []diffRow {
+	if commit == nil {
+		return nil
+	}
+	header := func(text string, style tcell.Style) diffRow {
+		return diffRow{text: text, style: style, hunk: -1}
+	}
+	labelStyle := tcell.StyleDefault.Bold(true)
+
+	author := commit.Author.Name
+	if commit.Author.Email != "" {
+		author += " <" + commit.Author.Email + ">"
+	}
+	rows := []diffRow{
+		header("commit "+commit.Hash, tcell.StyleDefault.Foreground(tcell.ColorYellow)),
+		header("Author: "+author, tcell.StyleDefault),
+		header("Date:   "+commit.Author.Time.Format("Mon Jan 2 15:04:05 2006 -0700"), tcell.StyleDefault),
+		header("", tcell.StyleDefault),
+	}
+
+	message, trailers := git.SplitTrailers(commit.Message)
+	for _, line := range strings.Split(message, "\n") {
+		rows = append(rows, header(strings.TrimRight("    "+line, " "), tcell.StyleDefault))
+	}
+	if len(trailers) > 0 {
+		rows = append(rows, header("", tcell.StyleDefault), header("Trailers:", labelStyle))
+		width := 0
+		for _, trailer := range trailers {
+			width = max(width, len(trailer.Key))
+		}
+		for _, trailer := range trailers {
+			text := fmt.Sprintf("    %-*s %s", width+1, trailer.Key+":", trailer.Value)
+			rows = append(rows, header(text, tcell.StyleDefault.Foreground(tcell.ColorTeal)))
+		}
+	}
+	return append(rows, header("", tcell.StyleDefault))
+}
+
+// renderDiffFile turns the headers and hunks of a file into styled rows
+func renderDiffFile(file *git.DiffFile, folded bool, binaryInfo []string) []diffRow {
+	header := func(text string, style tcell.Style) diffRow {
+		return diffRow{text: text, style: style, file: file, hunk: -1}
+	}
+
+	oldPath, newPath := file.OldPath, file.NewPath
+	if oldPath == "" {
+		oldPath = newPath
+	}
+	if newPath == "" {
+		newPath = oldPath
+	}
+
+	title := fmt.Sprintf("diff --git a/%s b/%s", oldPath, newPath)
+	titleStyle := tcell.StyleDefault.Foreground(tcell.ColorBlue).Bold(true)
+	if folded {
+		return []diffRow{header(fmt.Sprintf("%s [folded, %d hunks]", title, len(file.Hunks)), titleStyle)}
+	}
+
+	rows := []diffRow{header(title, titleStyle)}
+	if summary := renameSummary(file); summary != "" {
+		rows = append(rows, header(summary, tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true)))
+	}
+	if file.Textconv != "" {
+		rows = append(rows, header("converted to text by textconv: "+file.Textconv, tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true)))
+	}
+	if file.Structured != "" {
+		rows = append(rows, header(structuredSummaries[file.Structured], tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true)))
+	}
+	if summary := eolSummary(file); summary != "" {
+		rows = append(rows, header(summary, tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true)))
+	}
+	for _, text := range file.Headers {
+		if isRenameHeader(text) {
+			continue
+		}
+		rows = append(rows, header(text, tcell.StyleDefault.Foreground(tcell.ColorYellow)))
+	}
+
+	if file.IsBinary {
+		rows = append(rows, header(fmt.Sprintf("Binary files a/%s and b/%s differ", oldPath, newPath), tcell.StyleDefault))
+		for _, text := range binaryInfo {
+			rows = append(rows, header("    "+text, tcell.StyleDefault.Foreground(tcell.ColorAqua)))
+		}
+		return rows
+	}
+	if len(file.Hunks) == 0 {
+		return rows
+	}
+
+	from, to := "a/"+oldPath, "b/"+newPath
+	if file.IsNew {
+		from = "/dev/null"
+	}
+	if file.IsDeleted {
+		to = "/dev/null"
+	}
+	pathStyle := tcell.StyleDefault.Foreground(tcell.ColorAqua)
+	rows = append(rows, header("--- "+from, pathStyle), header("+++ "+to, pathStyle))
+
+	for i, hunk := range file.Hunks {
+		text := fmt.Sprintf("@@ -%s +%s @@", formatHunkRange(hunk.OldStart, hunk.OldLines), formatHunkRange(hunk.NewStart, hunk.NewLines))
+		if hunk.Header != "" {
+			text += " " + hunk.Header
+		}
+		rows = append(rows, diffRow{
+			text:  text,
+			style: tcell.StyleDefault.Foreground(tcell.ColorPurple).Bold(true),
+			file:  file,
+			hunk:  i,
+		})
+
+		for _, line := range hunk.Lines {
+			rows = append(rows, renderDiffLine(file, i, line))
+		}
+	}
+	return rows
+}
+
+// eolSummary returns the line telling the changes of a file are line
+// endings alone, or how many lines changing their line ending are hidden
+func eolSummary(file *git.DiffFile) string {
+	switch {
+	case file.HiddenEOLChanges > 0 && len(file.Hunks) == 0:
+		return fmt.Sprintf("only line endings changed, %d lines hidden", file.HiddenEOLChanges)
+	case file.HiddenEOLChanges > 0:
+		return fmt.Sprintf("%d lines changing line endings alone hidden", file.HiddenEOLChanges)
+	case file.EOLOnly():
+		return "only line endings changed"
+	}
+	return ""
+}
+
+// renderDiffLine turns a hunk line into a styled row
+func renderDiffLine(file *git.DiffFile, hunk int, line *git.DiffLine) diffRow {
+	row := diffRow{style: tcell.StyleDefault, colored: line.Colored, file: file, hunk: hunk, line: line}
+
+	switch line.Type {
+	case git.DiffLineAddition:
+		row.text = "+" + line.Content
+		row.style = row.style.Foreground(tcell.ColorGreen)
+	case git.DiffLineDeletion:
+		row.text = "-" + line.Content
+		row.style = row.style.Foreground(tcell.ColorRed)
+	case git.DiffLineNoNewline:
+		row.text = line.Content
+		row.style = row.style.Foreground(tcell.ColorGray)
+	default:
+		row.text = " " + line.Content
+	}
+	return row
+}
+
+// renameSummary describes the rename or copy of a file on a single line,
+// empty for other files
+func renameSummary(file *git.DiffFile) string {
+	var kind string
+	switch {
+	case file.IsRenamed:
+		kind = "renamed"
+	case file.IsCopied:
+		kind = "copied"
+	default:
+		return ""
+	}
+
+	summary := fmt.Sprintf("%s: %s → %s", kind, file.OldPath, file.NewPath)
+	if file.Similarity > 0 {
+		summary += fmt.Sprintf(" (%d%%)", file.Similarity)
+	}
+	return summary
+}
+
+// isRenameHeader returns whether an extended header is part of the ones
+// the rename summary stands for
+func isRenameHeader(text string) bool {
+	for _, prefix := range []string{"similarity index ", "rename from ", "rename to ", "copy from ", "copy to "} {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatHunkRange formats a hunk range the way git does, omitting a count
+// of one
+func formatHunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -16,10 +16,21 @@ type DiffView struct {
 	config     *config.Config
 	client     git.Client
 	commitHash string
-	diff       string
-	lines      []string
+	commit     *git.Commit // Commit shown above its diff, nil for ranges
+	revRange   string
+	paths      []string
+	stage      *stageFile // File of the status view, nil for commits and ranges
+	diff       *git.Diff
+	rows       []diffRow
+	folds      foldSet
+	foldKeys   foldPrefix
+	binaryInfo map[string][]string
+	binaries   map[string][]string // Descriptions of binary changes by old..new blob
+	hscroll    int           // Columns scrolled to the right
+	blame      *diffBlame    // Commits of the old lines, nil while hidden
+	lineMark   *git.DiffLine // Line marked as one end of the lines to stage
 	repoPath   string
-	box        *DrawBox
+	frame      *Frame
 }
 
 // NewDiffView creates a new diff view
@@ -29,24 +40,19 @@ func NewDiffView(config *config.Config, client git.Client) *DiffView {
 		Scrollable: NewScrollable(),
 		config:     config,
 		client:     client,
-		lines:      make([]string, 0),
-		box:        NewDrawBox("Diff", tcell.StyleDefault.Foreground(tcell.ColorWhite)),
+		rows:       make([]diffRow, 0),
+		folds:      make(foldSet),
+		frame:      NewFrame(config, "Diff"),
 	}
 }
 
 // Render renders the diff view
 func (v *DiffView) Render(screen tcell.Screen, x, y, width, height int) error {
 	v.SetPosition(x, y, width, height)
-	v.SetHeight(height - 2) // Account for borders
-	
-	// Draw box
-	v.box.Draw(screen, x, y, width, height)
 	
-	// Draw content area
-	contentX := x + 1
-	contentY := y + 1
-	contentWidth := width - 2
-	contentHeight := height - 2
+	// Draw the frame, and its scrollbar once the content is laid out
+	contentX, contentY, contentWidth, contentHeight := v.frame.Draw(screen, x, y, width, height)
+	defer v.frame.DrawScrollbar(screen, v.Scrollable)
 	
 	if contentWidth <= 0 || contentHeight <= 0 {
 		return nil
@@ -60,9 +66,9 @@ func (v *DiffView) Render(screen tcell.Screen, x, y, width, height int) error {
 
 // renderDiff renders the diff content
 func (v *DiffView) renderDiff(screen tcell.Screen, x, y, width, height int) {
-	if len(v.lines) == 0 {
+	if len(v.rows) == 0 {
 		msg := "No diff to display"
-		if v.commitHash == "" {
+		if v.commitHash == "" && v.revRange == "" {
 			msg = "No commit selected"
 		} else if !v.client.IsRepository() {
 			msg = "Not in a git repository"
@@ -79,91 +85,58 @@ func (v *DiffView) renderDiff(screen tcell.Screen, x, y, width, height int) {
 	}
 
 	// Calculate visible range
-	maxVisible := len(v.lines)
-	if maxVisible > height {
-		maxVisible = height
-	}
-	
-	v.SetMaxOffset(len(v.lines) - height)
-	if v.GetOffset() > len(v.lines)-height {
-		v.SetMaxOffset(len(v.lines) - height)
-	}
+	v.SetMaxOffset(len(v.rows) - height)
 	
 	start := v.GetOffset()
 	end := start + height
-	if end > len(v.lines) {
-		end = len(v.lines)
+	if end > len(v.rows) {
+		end = len(v.rows)
 	}
 
-	// Render each line
+	// Render each row
 	for i := start; i < end; i++ {
-		line := v.lines[i]
-		lineY := y + (i - start)
-		
-		if lineY >= y+height {
-			break
-		}
-		
-		// Format and render the line
-		v.renderDiffLine(screen, x, lineY, width, line)
+		v.drawRow(screen, x, y+(i-start), width, v.rows[i])
 	}
 }
 
-// renderDiffLine renders a single diff line with syntax highlighting
-func (v *DiffView) renderDiffLine(screen tcell.Screen, x, y, width int, line string) {
-	if width <= 0 {
-		return
+// drawRow draws a single rendered diff row, scrolled horizontally
+func (v *DiffView) drawRow(screen tcell.Screen, x, y, width int, row diffRow) {
+	trailingFrom := -1
+	if row.line != nil && row.line.Type == git.DiffLineAddition {
+		trailingFrom = 1 // Skip the + prefix
 	}
-
-	// Determine line style based on diff content
-	style := tcell.StyleDefault
-	
-	// Apply syntax highlighting for diff
-	if strings.HasPrefix(line, "+") {
-		// Added lines
-		if strings.HasPrefix(line, "+++ ") {
-			style = style.Foreground(tcell.ColorAqua)
-		} else {
-			style = style.Foreground(tcell.ColorGreen)
-		}
-	} else if strings.HasPrefix(line, "-") {
-		// Removed lines
-		if strings.HasPrefix(line, "--- ") {
-			style = style.Foreground(tcell.ColorAqua)
-		} else {
-			style = style.Foreground(tcell.ColorRed)
-		}
-	} else if strings.HasPrefix(line, "@@ ") {
-		// Hunk headers
-		style = style.Foreground(tcell.ColorPurple).Bold(true)
-	} else if strings.HasPrefix(line, "index ") {
-		// Index lines
-		style = style.Foreground(tcell.ColorYellow)
-	} else if strings.HasPrefix(line, "diff ") {
-		// Diff headers
-		style = style.Foreground(tcell.ColorBlue).Bold(true)
-	} else if strings.HasPrefix(line, "new file mode ") || strings.HasPrefix(line, "deleted file mode ") {
-		// File mode changes
-		style = style.Foreground(tcell.ColorYellow)
-	}
-
-	// Handle line truncation if needed
-	if len(line) > width {
-		line = line[:width-3] + "..."
-	}
-
-	// Draw the line
-	for i, char := range line {
-		if x+i >= x+width {
-			break
+	if v.blame != nil {
+		// The gutter stays put while scrolling sideways
+		gutter := v.blameGutter(row)
+		for i, ch := range gutter {
+			if i < width {
+				screen.SetContent(x+i, y, ch, nil, blameStyle)
+			}
 		}
-		screen.SetContent(x+i, y, char, nil, style)
+		x += len(gutter)
+		width -= len(gutter)
 	}
+	if row.line != nil && row.line == v.lineMark {
+		row.style = row.style.Reverse(true)
+	}
+	cells := row.cells(newWhitespaceMarks(v.config), trailingFrom)
+	drawCells(screen, x, y, width, cells, v.hscroll, row.style)
+}
 
-	// Fill remaining space with background
-	for i := len(line); i < width; i++ {
-		screen.SetContent(x+i, y, ' ', nil, tcell.StyleDefault)
+// scrollHorizontally scrolls the rows sideways by half the view width,
+// stopping once the longest row is fully visible
+func (v *DiffView) scrollHorizontally(direction int) {
+	_, _, width, _ := v.GetPosition()
+	width -= 2 + v.blameWidth() // Account for borders and the gutter
+	step := max(1, width/2)
+
+	widest := 0
+	marks := newWhitespaceMarks(v.config)
+	for _, row := range v.rows {
+		widest = max(widest, cellsWidth(marks.cells(row.text, row.style, -1)))
 	}
+
+	v.hscroll = min(max(0, v.hscroll+direction*step), max(0, widest-width))
 }
 
 // HandleKey handles keyboard input
@@ -172,6 +145,15 @@ func (v *DiffView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
 		return false
 	}
 
+	v.updateMaxOffset()
+
+	if key != tcell.KeyRune {
+		v.foldKeys.reset()
+	} else if action, ok := v.foldKeys.handle(ch); ok {
+		v.fold(action)
+		return true
+	}
+
 	switch key {
 	case tcell.KeyUp:
 		v.ScrollUp()
@@ -191,6 +173,12 @@ func (v *DiffView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
 	case tcell.KeyEnd:
 		v.ScrollToBottom()
 		return true
+	case tcell.KeyLeft:
+		v.scrollHorizontally(-1)
+		return true
+	case tcell.KeyRight:
+		v.scrollHorizontally(1)
+		return true
 	}
 
 	switch ch {
@@ -206,6 +194,9 @@ func (v *DiffView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
 	case 'G':
 		v.ScrollToBottom()
 		return true
+	case 'b':
+		v.toggleBlame()
+		return true
 	}
 
 	return false
@@ -213,52 +204,296 @@ func (v *DiffView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
 
 // Refresh refreshes the diff content
 func (v *DiffView) Refresh() error {
-	if !v.client.IsRepository() {
-		v.diff = ""
-		v.lines = []string{}
+	v.commit = nil
+	if !v.client.IsRepository() || (v.commitHash == "" && v.revRange == "" && v.stage == nil) {
+		v.binaryInfo = nil
+		v.setDiff(nil)
 		return nil
 	}
 
-	if v.commitHash == "" {
-		v.diff = ""
-		v.lines = []string{}
-		return nil
+	var diff *git.Diff
+	var err error
+	switch {
+	case v.stage != nil:
+		diff, err = v.stage.diff(v.client)
+	case v.revRange != "":
+		diff, err = v.client.GetRangeDiff(v.revRange, v.paths...)
+	default:
+		diff, err = v.client.GetCommitDiff(v.commitHash, &git.DiffOptions{
+			RenameThreshold: v.config.Views.Diff.RenameThreshold,
+			FindCopies:      v.config.Views.Diff.FindCopies,
+		})
+		if err == nil {
+			// The diff is still worth showing without its header
+			v.commit, _ = v.client.GetCommit(v.commitHash)
+		}
 	}
-
-	repo, err := v.client.GetRepository()
 	if err != nil {
-		return fmt.Errorf("failed to get repository: %w", err)
+		return fmt.Errorf("failed to get diff: %w", err)
 	}
 
-	// Get diff for the commit
-	diff, err := repo.GetCommitDiff(v.commitHash)
-	if err != nil {
-		return fmt.Errorf("failed to get commit diff: %w", err)
+	v.structureDiff(diff)
+	if v.config.Views.Diff.HideEOLChanges {
+		git.HideEOLChanges(diff)
+	}
+
+	// Keep the same line of the same file at the top of the view
+	path, delta := v.topAnchor()
+	v.binaryInfo = v.describeBinaries(diff)
+	v.setDiff(diff)
+	v.restoreAnchor(path, delta)
+	v.loadBlame()
+	return nil
+}
+
+// topAnchor returns the file of the row at the top of the view and how far
+// below the first row of that file it is
+func (v *DiffView) topAnchor() (string, int) {
+	offset := v.GetOffset()
+	if offset >= len(v.rows) {
+		return "", offset
+	}
+
+	file := v.rows[offset].file
+	first := offset
+	for first > 0 && v.rows[first-1].file == file {
+		first--
+	}
+	if file == nil {
+		return "", offset
+	}
+	return file.Path(), offset - first
+}
+
+// restoreAnchor scrolls back to the row found by topAnchor, staying put if
+// the file is no longer part of the diff
+func (v *DiffView) restoreAnchor(path string, delta int) {
+	if path == "" {
+		return
+	}
+	for i, row := range v.rows {
+		if row.file == nil || row.file.Path() != path {
+			continue
+		}
+		offset := i + delta
+		for offset > i && (offset >= len(v.rows) || v.rows[offset].file != row.file) {
+			offset--
+		}
+		v.SetOffset(offset)
+		return
+	}
+}
+
+// describeBinaries describes the binary files of a diff, keyed by path.
+// Only the start of each version is read, and the descriptions of the
+// versions already described are reused as blobs never change.
+func (v *DiffView) describeBinaries(diff *git.Diff) map[string][]string {
+	info := make(map[string][]string)
+	described := make(map[string][]string)
+	for _, file := range diff.Files {
+		if !file.IsBinary || (file.OldHash == "" && file.NewHash == "") {
+			continue
+		}
+		key := file.OldHash + ".." + file.NewHash
+		if lines, ok := v.binaries[key]; ok {
+			info[file.Path()], described[key] = lines, lines
+			continue
+		}
+		oldHead, oldSize, oldErr := v.readBlobHead(file.OldHash)
+		newHead, newSize, newErr := v.readBlobHead(file.NewHash)
+		if oldErr != nil || newErr != nil {
+			continue
+		}
+		info[file.Path()] = git.DescribeBinaryHeads(oldHead, oldSize, newHead, newSize)
+		described[key] = info[file.Path()]
+	}
+	v.binaries = described
+	return info
+}
+
+// readBlob reads a blob of a diff, returning nil for the null hash of a
+// missing side
+func (v *DiffView) readBlob(hash string) ([]byte, error) {
+	if strings.Trim(hash, "0") == "" {
+		return nil, nil
 	}
+	return v.client.GetBlob(hash)
+}
 
+// readBlobHead reads the start of a blob of a diff, returning nil for the
+// null hash of a missing side
+func (v *DiffView) readBlobHead(hash string) ([]byte, int64, error) {
+	if strings.Trim(hash, "0") == "" {
+		return nil, 0, nil
+	}
+	return v.client.BlobHead(hash, git.BinaryDescribeLimit)
+}
+
+// setDiff sets the diff model and renders it into rows
+func (v *DiffView) setDiff(diff *git.Diff) {
 	v.diff = diff
-	v.lines = strings.Split(diff, "\n")
-	
-	// Reset scroll position
-	v.SetMaxOffset(len(v.lines) - v.getPageSize())
-	if v.GetOffset() > len(v.lines)-v.getPageSize() {
-		v.SetMaxOffset(len(v.lines) - v.getPageSize())
+	v.lineMark = nil
+	v.rows = renderCommitHeader(v.commit)
+	v.rows = append(v.rows, renderDiff(diff, v.folds, v.binaryInfo)...)
+	v.updateMaxOffset()
+}
+
+// fold folds or unfolds the file at the top of the view, or all files
+func (v *DiffView) fold(action foldAction) {
+	if action == foldNone || len(v.rows) == 0 {
+		return
 	}
 
-	return nil
+	offset := v.GetOffset()
+	if offset >= len(v.rows) {
+		offset = len(v.rows) - 1
+	}
+	current := v.rows[offset].file
+	if current == nil && action != foldOpenAll && action != foldCloseAll {
+		return // On the commit header
+	}
+
+	var paths []string
+	for _, file := range v.diff.Files {
+		paths = append(paths, file.Path())
+	}
+	key := ""
+	if current != nil {
+		key = current.Path()
+	}
+	v.folds.apply(action, key, paths)
+	v.setDiff(v.diff)
+
+	// Keep the file that was acted on at the top of the view
+	for i, row := range v.rows {
+		if row.file == current {
+			v.SetOffset(i)
+			break
+		}
+	}
+}
+
+// EditorTarget returns the file and new side line number of the hunk line
+// at the top of the view
+func (v *DiffView) EditorTarget() (string, int, error) {
+	if len(v.rows) == 0 {
+		return "", 0, fmt.Errorf("no diff to edit")
+	}
+
+	offset := v.GetOffset()
+	if offset >= len(v.rows) {
+		offset = len(v.rows) - 1
+	}
+
+	row := v.rows[offset]
+	if row.file == nil {
+		return "", 0, fmt.Errorf("no file under the cursor")
+	}
+	path := row.file.Path()
+	if path == "" {
+		return "", 0, fmt.Errorf("no file under the cursor")
+	}
+	if row.hunk < 0 {
+		// On a file header, jump to the first hunk of the file
+		if len(row.file.Hunks) > 0 {
+			return path, row.file.Hunks[0].NewStart, nil
+		}
+		return path, 1, nil
+	}
+	return path, row.newLine(), nil
+}
+
+// SetPosition sets the view position and size
+func (v *DiffView) SetPosition(x, y, width, height int) {
+	v.BaseView.SetPosition(x, y, width, height)
+	v.SetHeight(height - 2) // Account for borders
+	v.updateMaxOffset()
+}
+
+// updateMaxOffset bounds scrolling to the current content
+func (v *DiffView) updateMaxOffset() {
+	v.SetMaxOffset(len(v.rows) - v.getPageSize())
 }
 
-// SetCommitHash sets the commit hash to display diff for
+// PagerArgs returns the git arguments producing the raw patch
+func (v *DiffView) PagerArgs() ([]string, error) {
+	var args []string
+	if v.stage != nil {
+		args = v.stage.pagerArgs()
+	} else if v.revRange != "" {
+		args = []string{"diff", "--patch-with-stat", "-M"}
+	} else if v.commitHash != "" {
+		args = []string{"show", "--patch-with-stat"}
+	} else {
+		return nil, fmt.Errorf("no commit selected")
+	}
+
+	// The pager shows git's own output, so it can show word diffs which
+	// the view itself cannot render
+	if v.config.Views.Diff.WordDiff {
+		args = append(args, "--word-diff")
+	}
+
+	if v.stage != nil {
+		return append(args, v.stage.pagerPaths()...), nil
+	}
+	if v.revRange != "" {
+		return append(append(args, v.revRange, "--"), v.paths...), nil
+	}
+	return append(args, v.commitHash), nil
+}
+
+// SetCommitHash sets the commit hash to display diff for. Fold state is
+// kept across refreshes of the same commit only.
 func (v *DiffView) SetCommitHash(hash string) {
+	if hash != v.commitHash || v.revRange != "" || v.stage != nil {
+		v.folds = make(foldSet)
+		v.setDiff(nil)
+		v.ScrollToTop()
+		v.hscroll = 0
+	}
 	v.commitHash = hash
+	v.revRange = ""
+	v.paths = nil
+	v.stage = nil
 	v.Refresh()
 }
 
+// SetRange shows the diff between the two ends of a revision range instead
+// of a single commit, optionally limited to the given paths
+func (v *DiffView) SetRange(revRange string, paths []string) error {
+	v.folds = make(foldSet)
+	v.commitHash = ""
+	v.revRange = revRange
+	v.paths = paths
+	v.stage = nil
+	v.setDiff(nil)
+	v.ScrollToTop()
+	v.hscroll = 0
+	return v.Refresh()
+}
+
+// GetRange returns the revision range and paths being shown, if any
+func (v *DiffView) GetRange() (string, []string) {
+	return v.revRange, v.paths
+}
+
 // GetCommitHash returns the current commit hash
 func (v *DiffView) GetCommitHash() string {
 	return v.commitHash
 }
 
+// Selection returns the commit, and the file and line at the top of the
+// view, for placeholder expansion
+func (v *DiffView) Selection() Selection {
+	sel := Selection{Commit: v.commitHash}
+	if path, line, err := v.EditorTarget(); err == nil {
+		sel.File = path
+		sel.Lineno = line
+	}
+	return sel
+}
+
 // SetRepoPath sets the repository path
 func (v *DiffView) SetRepoPath(path string) {
 	v.repoPath = path
@@ -273,13 +508,15 @@ func (v *DiffView) getPageSize() int {
 // Clear clears the diff content
 func (v *DiffView) Clear() {
 	v.commitHash = ""
-	v.diff = ""
-	v.lines = []string{}
+	v.revRange = ""
+	v.paths = nil
+	v.stage = nil
+	v.setDiff(nil)
 	v.SetMaxOffset(0)
 	v.ScrollToTop()
 }
 
-// GetDiffContent returns the raw diff content
-func (v *DiffView) GetDiffContent() string {
+// GetDiff returns the diff being displayed
+func (v *DiffView) GetDiff() *git.Diff {
 	return v.diff
 }
\ No newline at end of file
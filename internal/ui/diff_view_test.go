@@ -2,12 +2,17 @@ package ui
 
 import (
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/azhao1981/tig/internal/config"
 	"github.com/azhao1981/tig/internal/git"
 	"github.com/gdamore/tcell/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewDiffView(t *testing.T) {
@@ -48,19 +53,8 @@ index 1234567..abcdefg 100644
 +line 3 modified
 +line 4 added
 `
-	view.diff = diff
-	view.lines = []string{
-		"diff --git a/file.txt b/file.txt",
-		"index 1234567..abcdefg 100644",
-		"--- a/file.txt",
-		"+++ b/file.txt",
-		"@@ -1,3 +1,4 @@",
-		" line 1",
-		" line 2",
-		"-line 3",
-		"+line 3 modified",
-		"+line 4 added",
-	}
+	view.setDiff(parseTestDiff(t, diff))
+	assert.Len(t, view.rows, 10)
 
 	err = view.Render(screen, 0, 0, 80, 24)
 	assert.NoError(t, err)
@@ -74,11 +68,7 @@ func TestDiffViewHandleKey(t *testing.T) {
 	view.Focus()
 
 	// Create test diff content
-	lines := make([]string, 100)
-	for i := 0; i < 100; i++ {
-		lines[i] = fmt.Sprintf("Line %d", i+1)
-	}
-	view.lines = lines
+	view.setDiff(longTestDiff(t, 100))
 	view.SetPosition(0, 0, 80, 24)
 
 	// Test initial state
@@ -141,7 +131,7 @@ func TestDiffViewBoundaryConditions(t *testing.T) {
 	view.SetPosition(0, 0, 80, 24)
 
 	// Test with no lines
-	view.lines = []string{}
+	view.setDiff(nil)
 
 	// Test navigation with no content
 	handled := view.HandleKey(tcell.KeyDown, 0, 0)
@@ -153,7 +143,8 @@ func TestDiffViewBoundaryConditions(t *testing.T) {
 	assert.Equal(t, 0, view.GetOffset()) // Should stay at 0
 
 	// Test with single line
-	view.lines = []string{"Single line"}
+	view.setDiff(&git.Diff{Files: []*git.DiffFile{{OldPath: "file.txt", NewPath: "file.txt"}}})
+	assert.Len(t, view.rows, 1)
 
 	handled = view.HandleKey(tcell.KeyDown, 0, 0)
 	assert.True(t, handled)
@@ -187,8 +178,7 @@ func TestDiffViewClear(t *testing.T) {
 
 	// Set some content
 	view.SetCommitHash("abc123")
-	view.diff = "some diff content"
-	view.lines = []string{"line1", "line2"}
+	view.setDiff(longTestDiff(t, 2))
 	view.SetMaxOffset(10)
 	view.ScrollToBottom()
 
@@ -197,28 +187,28 @@ func TestDiffViewClear(t *testing.T) {
 
 	// Verify everything is cleared
 	assert.Equal(t, "", view.GetCommitHash())
-	assert.Equal(t, "", view.GetDiffContent())
-	assert.Empty(t, view.lines)
+	assert.Nil(t, view.GetDiff())
+	assert.Empty(t, view.rows)
 	assert.Equal(t, 0, view.GetOffset())
 	assert.Equal(t, 0, view.getMaxOffset())
 }
 
-func TestDiffViewGetDiffContent(t *testing.T) {
+func TestDiffViewGetDiff(t *testing.T) {
 	cfg := &config.Config{}
 	client := git.NewClient()
 
 	view := NewDiffView(cfg, client)
 
 	// Test empty diff
-	assert.Equal(t, "", view.GetDiffContent())
+	assert.Nil(t, view.GetDiff())
 
 	// Test with content
-	diff := "diff --git a/file.txt b/file.txt"
-	view.diff = diff
-	assert.Equal(t, diff, view.GetDiffContent())
+	diff := longTestDiff(t, 1)
+	view.setDiff(diff)
+	assert.Equal(t, diff, view.GetDiff())
 }
 
-func TestDiffViewRenderDiffLine(t *testing.T) {
+func TestDiffViewDrawRow(t *testing.T) {
 	cfg := &config.Config{}
 	client := git.NewClient()
 
@@ -229,28 +219,42 @@ func TestDiffViewRenderDiffLine(t *testing.T) {
 	err := screen.Init()
 	assert.NoError(t, err)
 
-	testCases := []struct {
-		name string
-		line string
-	}{
-		{"diff header", "diff --git a/file.txt b/file.txt"},
-		{"index line", "index 1234567..abcdefg 100644"},
-		{"old file", "--- a/file.txt"},
-		{"new file", "+++ b/file.txt"},
-		{"hunk header", "@@ -1,3 +1,4 @@"},
-		{"context line", " line 1"},
-		{"added line", "+new line"},
-		{"removed line", "-old line"},
-		{"file mode", "new file mode 100644"},
-		{"deleted file", "deleted file mode 100644"},
-		{"empty line", ""},
+	view.setDiff(parseTestDiff(t, `diff --git a/file.txt b/file.txt
+new file mode 100644
+index 0000000..abcdefg
+--- /dev/null
++++ b/file.txt
+@@ -0,0 +1,2 @@
++new line
++last line
+\ No newline at end of file
+diff --git a/image.png b/image.png
+deleted file mode 100644
+index 1234567..0000000
+Binary files a/image.png and /dev/null differ
+`))
+
+	expected := []string{
+		"diff --git a/file.txt b/file.txt",
+		"new file mode 100644",
+		"index 0000000..abcdefg",
+		"--- /dev/null",
+		"+++ b/file.txt",
+		"@@ -0,0 +1,2 @@",
+		"+new line",
+		"+last line",
+		"\\ No newline at end of file",
+		"diff --git a/image.png b/image.png",
+		"deleted file mode 100644",
+		"index 1234567..0000000",
+		"Binary files a/image.png and b/image.png differ",
 	}
+	assert.Len(t, view.rows, len(expected))
 
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			view.renderDiffLine(screen, 0, 0, 80, tc.line)
-			// Just verify it doesn't panic
-		})
+	for i, row := range view.rows {
+		assert.Equal(t, expected[i], row.text)
+		view.drawRow(screen, 0, 0, 80, row)
+		// Just verify it doesn't panic
 	}
 }
 
@@ -304,11 +308,7 @@ func TestDiffViewScrollableIntegration(t *testing.T) {
 	view := NewDiffView(cfg, client)
 
 	// Set up content and dimensions
-	lines := make([]string, 100)
-	for i := 0; i < 100; i++ {
-		lines[i] = fmt.Sprintf("Line %d", i)
-	}
-	view.lines = lines
+	view.setDiff(longTestDiff(t, 96))
 	view.SetPosition(0, 0, 80, 24)
 
 	// Test scrollable integration
@@ -316,7 +316,7 @@ func TestDiffViewScrollableIntegration(t *testing.T) {
 	assert.Equal(t, 22, view.getPageSize()) // 24 - 2 for borders
 
 	// Test max offset calculation
-	expectedMax := len(lines) - view.getPageSize()
+	expectedMax := len(view.rows) - view.getPageSize()
 	assert.Equal(t, expectedMax, view.getMaxOffset())
 
 	// Test scrolling to bottom
@@ -332,3 +332,453 @@ func TestDiffViewScrollableIntegration(t *testing.T) {
 func (v *DiffView) getMaxOffset() int {
 	return v.Scrollable.maxOffset
 }
+
+func TestDiffViewEditorTarget(t *testing.T) {
+	cfg := &config.Config{}
+	client := git.NewClient()
+
+	view := NewDiffView(cfg, client)
+	view.SetPosition(0, 0, 80, 3) // One content line, so every line is reachable
+
+	_, _, err := view.EditorTarget()
+	assert.Error(t, err)
+
+	view.setDiff(parseTestDiff(t, `diff --git a/file.txt b/file.txt
+index 1234567..abcdefg 100644
+--- a/file.txt
++++ b/file.txt
+@@ -1,3 +1,4 @@
+ line 1
+-line 2
++line 2 modified
+@@ -10,2 +11,3 @@ func main()
+ line 10
++line 11 added
+diff --git a/old.txt b/new.txt
+similarity index 90%
+rename from old.txt
+rename to new.txt
+--- a/old.txt
++++ b/new.txt
+@@ -5 +7 @@
+-gone
++here
+`))
+
+	testCases := []struct {
+		offset int
+		path   string
+		line   int
+	}{
+		{0, "file.txt", 1},   // file header jumps to the first hunk
+		{4, "file.txt", 1},   // hunk header
+		{6, "file.txt", 2},   // deleted line maps to where it was removed
+		{7, "file.txt", 2},   // added line
+		{10, "file.txt", 12}, // second hunk
+		{11, "new.txt", 7},
+		{18, "new.txt", 7},
+	}
+
+	for _, tc := range testCases {
+		view.updateMaxOffset()
+		view.SetOffset(tc.offset)
+		path, line, err := view.EditorTarget()
+		assert.NoError(t, err)
+		assert.Equal(t, tc.path, path, "offset %d", tc.offset)
+		assert.Equal(t, tc.line, line, "offset %d", tc.offset)
+	}
+
+	assert.Equal(t, 1, view.rows[10].hunk)
+	assert.Equal(t, -1, view.rows[1].hunk)
+	assert.Equal(t, "@@ -10,2 +11,3 @@ func main()", view.rows[8].text)
+}
+
+// parseTestDiff parses diff text, failing the test on errors
+func parseTestDiff(t *testing.T, text string) *git.Diff {
+	diff, err := git.ParseDiff(text)
+	assert.NoError(t, err)
+	return diff
+}
+
+// longTestDiff returns a diff whose rendering has the given number of
+// context lines after its four header rows
+func longTestDiff(t *testing.T, lines int) *git.Diff {
+	text := fmt.Sprintf("diff --git a/file.txt b/file.txt\n--- a/file.txt\n+++ b/file.txt\n@@ -1,%d +1,%d @@\n", lines, lines)
+	for i := 0; i < lines; i++ {
+		text += fmt.Sprintf(" Line %d\n", i+1)
+	}
+	return parseTestDiff(t, text)
+}
+
+func TestDiffViewKeepsPosition(t *testing.T) {
+	view := NewDiffView(&config.Config{}, git.NewClient())
+	view.SetPosition(0, 0, 80, 12)
+
+	two := "diff --git a/a.txt b/a.txt\n--- a/a.txt\n+++ b/a.txt\n@@ -1 +1 @@\n-a\n+b\n" +
+		"diff --git a/b.txt b/b.txt\n--- a/b.txt\n+++ b/b.txt\n@@ -1,3 +1,3 @@\n x\n-y\n+z\n w\n"
+	view.setDiff(parseTestDiff(t, two))
+	view.SetMaxOffset(len(view.rows))
+	view.SetOffset(9) // The hunk header of b.txt
+
+	path, delta := view.topAnchor()
+	assert.Equal(t, "b.txt", path)
+	assert.Equal(t, 3, delta)
+
+	// When a.txt is no longer changed, b.txt stays at the top of the view
+	view.setDiff(parseTestDiff(t, "diff --git a/b.txt b/b.txt\n--- a/b.txt\n+++ b/b.txt\n@@ -1,3 +1,3 @@\n x\n-y\n+z\n w\n"))
+	view.SetMaxOffset(len(view.rows))
+	view.restoreAnchor(path, delta)
+	assert.Equal(t, 3, view.GetOffset())
+	assert.Equal(t, "@@ -1,3 +1,3 @@", view.rows[view.GetOffset()].text)
+
+	// Showing another commit starts at the top
+	view.SetCommitHash("abc123")
+	assert.Equal(t, 0, view.GetOffset())
+}
+
+func TestDiffViewFold(t *testing.T) {
+	cfg := &config.Config{}
+	client := git.NewClient()
+
+	view := NewDiffView(cfg, client)
+	view.Focus()
+	view.SetPosition(0, 0, 80, 3)
+
+	diff := parseTestDiff(t, `diff --git a/a.txt b/a.txt
+--- a/a.txt
++++ b/a.txt
+@@ -1 +1 @@
+-a
++b
+diff --git a/b.txt b/b.txt
+--- a/b.txt
++++ b/b.txt
+@@ -1 +1 @@
+-c
++d
+`)
+	view.setDiff(diff)
+	assert.Len(t, view.rows, 12)
+
+	// Fold the second file from within its hunk
+	view.SetOffset(9)
+	assert.True(t, view.HandleKey(tcell.KeyRune, 'z', 0))
+	assert.True(t, view.HandleKey(tcell.KeyRune, 'a', 0))
+	assert.Len(t, view.rows, 7)
+	assert.Equal(t, 6, view.GetOffset())
+	assert.Equal(t, "diff --git a/b.txt b/b.txt [folded, 1 hunks]", view.rows[6].text)
+
+	// Fold state survives a refresh of the same diff
+	view.setDiff(diff)
+	assert.Len(t, view.rows, 7)
+
+	view.HandleKey(tcell.KeyRune, 'z', 0)
+	view.HandleKey(tcell.KeyRune, 'M', 0)
+	assert.Len(t, view.rows, 2)
+
+	view.HandleKey(tcell.KeyRune, 'z', 0)
+	view.HandleKey(tcell.KeyRune, 'R', 0)
+	assert.Len(t, view.rows, 12)
+
+	// A non-rune key cancels the prefix
+	view.HandleKey(tcell.KeyRune, 'z', 0)
+	view.HandleKey(tcell.KeyDown, 0, 0)
+	view.HandleKey(tcell.KeyRune, 'a', 0)
+	assert.Len(t, view.rows, 12)
+}
+
+func TestDiffViewBinaryInfo(t *testing.T) {
+	cfg := &config.Config{}
+	client := git.NewClient()
+
+	view := NewDiffView(cfg, client)
+	view.binaryInfo = map[string][]string{
+		"logo.png": {"Size: 1.0 KiB -> 2.0 KiB", "Image: png 16x16 -> png 32x32"},
+	}
+	view.setDiff(parseTestDiff(t, `diff --git a/logo.png b/logo.png
+index 1234567..abcdefg 100644
+Binary files a/logo.png and b/logo.png differ
+`))
+
+	assert.Len(t, view.rows, 5)
+	assert.Equal(t, "    Size: 1.0 KiB -> 2.0 KiB", view.rows[3].text)
+	assert.Equal(t, "    Image: png 16x16 -> png 32x32", view.rows[4].text)
+}
+
+func TestDiffViewRenameSummary(t *testing.T) {
+	cfg := &config.Config{}
+	client := git.NewClient()
+
+	view := NewDiffView(cfg, client)
+	view.setDiff(parseTestDiff(t, "diff --git a/old.txt b/new.txt\nsimilarity index 97%\nrename from old.txt\nrename to new.txt\nindex 1234567..abcdefg 100644\n--- a/old.txt\n+++ b/new.txt\n@@ -1 +1 @@\n-a\n+b\n"))
+
+	var texts []string
+	for _, row := range view.rows[:3] {
+		texts = append(texts, row.text)
+	}
+	assert.Equal(t, []string{
+		"diff --git a/old.txt b/new.txt",
+		"renamed: old.txt → new.txt (97%)",
+		"index 1234567..abcdefg 100644",
+	}, texts)
+}
+
+func TestDiffViewWhitespace(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.UI.TabSize = 4
+	cfg.UI.ShowTrailingSpace = true
+	cfg.UI.ShowTabs = true
+	cfg.UI.ShowCR = true
+	client := git.NewClient()
+
+	view := NewDiffView(cfg, client)
+
+	screen := tcell.NewSimulationScreen("")
+	err := screen.Init()
+	assert.NoError(t, err)
+
+	view.setDiff(parseTestDiff(t, "diff --git a/file.txt b/file.txt\n--- a/file.txt\n+++ b/file.txt\n@@ -1,2 +1,2 @@\n-old  \n+\tnew  \n context\r\n"))
+
+	readRow := func(y int) (string, []tcell.Style) {
+		var text []rune
+		var styles []tcell.Style
+		for x := 0; x < 12; x++ {
+			ch, _, style, _ := screen.GetContent(x, y)
+			text = append(text, ch)
+			styles = append(styles, style)
+		}
+		return strings.TrimRight(string(text), " "), styles
+	}
+
+	// Removed lines are not checked for trailing whitespace
+	view.drawRow(screen, 0, 0, 20, view.rows[4])
+	text, styles := readRow(0)
+	assert.Equal(t, "-old", text)
+	assert.Equal(t, view.rows[4].style, styles[4])
+
+	// Tabs expand to the next tab stop and trailing spaces are marked
+	view.drawRow(screen, 0, 1, 20, view.rows[5])
+	text, styles = readRow(1)
+	assert.Equal(t, "+→  new", text)
+	assert.Equal(t, tabMarkStyle, styles[1])
+	assert.Equal(t, trailingMarkStyle, styles[7])
+	assert.Equal(t, trailingMarkStyle, styles[8])
+
+	view.drawRow(screen, 0, 2, 20, view.rows[6])
+	text, styles = readRow(2)
+	assert.Equal(t, " context^M", text)
+	assert.Equal(t, crMarkStyle, styles[8])
+
+	// Without indicators tabs are still expanded
+	cfg.UI.ShowTabs = false
+	cfg.UI.ShowCR = false
+	view.drawRow(screen, 0, 1, 20, view.rows[5])
+	text, _ = readRow(1)
+	assert.Equal(t, "+   new", text)
+	view.drawRow(screen, 0, 2, 20, view.rows[6])
+	text, _ = readRow(2)
+	assert.Equal(t, " context", text)
+}
+
+func TestDiffViewGitColors(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.UI.ShowTabs = true
+	client := git.NewClient()
+
+	view := NewDiffView(cfg, client)
+
+	screen := tcell.NewSimulationScreen("")
+	err := screen.Init()
+	assert.NoError(t, err)
+
+	view.setDiff(parseTestDiff(t, "diff --git a/file.txt b/file.txt\n--- a/file.txt\n+++ b/file.txt\n@@ -1 +1 @@\n\x1b[31m-old\x1b[m\n\x1b[32m+\x1b[m\x1b[1;32m\tnew\x1b[m\n"))
+
+	// The colors of git are drawn, tabs and all
+	view.drawRow(screen, 0, 0, 20, view.rows[4])
+	view.drawRow(screen, 0, 1, 20, view.rows[5])
+	assert.Equal(t, []string{"-old", "+→      new"}, screenLines(screen)[:2])
+
+	_, _, style, _ := screen.GetContent(0, 0)
+	assert.Equal(t, view.rows[4].style.Foreground(tcell.ColorMaroon), style)
+	_, _, style, _ = screen.GetContent(0, 1)
+	assert.Equal(t, view.rows[5].style.Foreground(tcell.ColorGreen), style)
+	_, _, style, _ = screen.GetContent(8, 1)
+	assert.Equal(t, view.rows[5].style.Foreground(tcell.ColorGreen).Bold(true), style)
+	_, _, style, _ = screen.GetContent(1, 1)
+	assert.Equal(t, tabMarkStyle, style)
+}
+
+func TestDiffViewHorizontalScroll(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.UI.TabSize = 4
+	client := git.NewClient()
+
+	view := NewDiffView(cfg, client)
+	view.Focus()
+	view.SetPosition(0, 0, 12, 10) // 10 columns inside the borders
+
+	screen := tcell.NewSimulationScreen("")
+	err := screen.Init()
+	assert.NoError(t, err)
+
+	view.setDiff(parseTestDiff(t, "diff --git a/f b/f\n--- a/f\n+++ b/f\n@@ -1 +1 @@\n+\t日本語 text\n"))
+	row := view.rows[4]
+
+	readRow := func() string {
+		var text []rune
+		for x := 0; x < 10; x++ {
+			ch, _, _, width := screen.GetContent(x, 0)
+			text = append(text, ch)
+			if width == 2 {
+				x++
+			}
+		}
+		return string(text)
+	}
+
+	// Tabs and wide characters are measured in display columns
+	view.drawRow(screen, 0, 0, 10, row)
+	assert.Equal(t, "+   日 ...", readRow())
+
+	// Scrolling moves by half the width; a wide character cut by the left
+	// edge is blanked
+	assert.True(t, view.HandleKey(tcell.KeyRight, 0, 0))
+	assert.Equal(t, 5, view.hscroll)
+	view.drawRow(screen, 0, 0, 10, row)
+	assert.Equal(t, " 本語 text", readRow())
+
+	// The longest row, the 18 column file header, stops the scrolling
+	assert.True(t, view.HandleKey(tcell.KeyRight, 0, 0))
+	assert.Equal(t, 8, view.hscroll)
+	assert.True(t, view.HandleKey(tcell.KeyLeft, 0, 0))
+	assert.True(t, view.HandleKey(tcell.KeyLeft, 0, 0))
+	assert.Equal(t, 0, view.hscroll)
+}
+
+func TestDiffViewBlame(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	refsTestGit(t, dir, "init", "--quiet")
+	commit := func(content, message string) string {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte(content), 0644))
+		refsTestGit(t, dir, "add", "file.txt")
+		refsTestGit(t, dir, "commit", "--quiet", "-m", message)
+		return refsTestGit(t, dir, "rev-parse", "--short=7", "HEAD")
+	}
+	first := commit("one\ntwo\nthree\n", "first")
+	second := commit("one\ntwo\nthree\nfour\n", "second")
+	commit("one\n2\nthree\nfour\n", "third")
+
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	view := NewDiffView(&config.Config{}, client)
+	view.Focus()
+	view.SetCommitHash("HEAD")
+
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	screen.SetSize(60, 20)
+	rendered := func() []string {
+		require.NoError(t, view.Render(screen, 0, 0, 60, 20))
+		var lines []string
+		for _, line := range screenLines(screen)[1:] {
+			line = strings.TrimSpace(strings.Trim(line, "│"))
+			if strings.HasSuffix(line, "one") || strings.HasSuffix(line, "2") ||
+				strings.HasSuffix(line, "two") || strings.HasSuffix(line, "four") {
+				lines = append(lines, line)
+			}
+		}
+		return lines
+	}
+	assert.Equal(t, []string{"one", "-two", "+2", "four"}, rendered())
+
+	// Added lines have no old commit
+	assert.True(t, view.HandleKey(tcell.KeyRune, 'b', 0))
+	assert.Equal(t, []string{
+		first + "  one",
+		first + " -two",
+		"+2",
+		second + "  four",
+	}, rendered())
+
+	// Refreshing keeps the commits
+	require.NoError(t, view.Refresh())
+	assert.Len(t, view.blame.commits, 1)
+
+	assert.True(t, view.HandleKey(tcell.KeyRune, 'b', 0))
+	assert.Nil(t, view.blame)
+	assert.Equal(t, []string{"one", "-two", "+2", "four"}, rendered())
+
+	// The root commit has nothing to blame
+	view.SetCommitHash(first)
+	view.HandleKey(tcell.KeyRune, 'b', 0)
+	assert.Empty(t, view.blame.commits)
+}
+
+func TestDiffViewBlameIgnoredMark(t *testing.T) {
+	view := NewDiffView(&config.Config{}, git.NewClient())
+	view.setDiff(parseTestDiff(t, "diff --git a/file.txt b/file.txt\n--- a/file.txt\n+++ b/file.txt\n@@ -1,2 +1,2 @@\n one\n-two\n+2\n"))
+	view.blame = &diffBlame{
+		commits: map[string][]git.BlameLine{"file.txt": {{Commit: "abc1234", Ignored: true}, {Commit: "def5678"}}},
+		width:   7,
+	}
+
+	// Lines blamed past an ignored revision are marked like git does
+	assert.Equal(t, "abc1234?", view.blameGutter(view.rows[4]))
+	assert.Equal(t, "def5678 ", view.blameGutter(view.rows[5]))
+	assert.Equal(t, "        ", view.blameGutter(view.rows[6]))
+}
+
+func TestDiffViewCommitHeader(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	refsTestGit(t, dir, "init", "--quiet")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("one\n"), 0644))
+	refsTestGit(t, dir, "add", "file.txt")
+	refsTestGit(t, dir, "commit", "--quiet", "-m",
+		"Add the file\n\nWith a body.\n\nSigned-off-by: A U Thor <a@example.com>\nFixes: #7")
+	hash := refsTestGit(t, dir, "rev-parse", "HEAD")
+
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	view := NewDiffView(&config.Config{}, client)
+	view.SetPosition(0, 0, 80, 3)
+	view.SetCommitHash(hash)
+
+	var texts []string
+	for _, row := range view.rows {
+		texts = append(texts, row.text)
+	}
+	require.Greater(t, len(texts), 12)
+	assert.Equal(t, "commit "+hash, texts[0])
+	assert.True(t, strings.HasPrefix(texts[1], "Author: "))
+	assert.Equal(t, []string{
+		"",
+		"    Add the file",
+		"",
+		"    With a body.",
+		"",
+		"Trailers:",
+		"    Signed-off-by: A U Thor <a@example.com>",
+		"    Fixes:         #7",
+		"",
+		"diff --git a/file.txt b/file.txt",
+	}, texts[3:13])
+
+	// The header rows belong to no file
+	_, _, err := view.EditorTarget()
+	assert.Error(t, err)
+	_, _, err = view.selectedHunk()
+	assert.Error(t, err)
+	view.fold(foldToggle)
+	assert.Empty(t, view.folds)
+
+	view.SetOffset(12)
+	path, _, err := view.EditorTarget()
+	require.NoError(t, err)
+	assert.Equal(t, "file.txt", path)
+}
@@ -0,0 +1,369 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+// DiffStatView shows the aggregated per-file diffstat between two revisions
+type DiffStatView struct {
+	*BaseView
+	*Scrollable
+	config   *config.Config
+	client   git.Client
+	revRange string
+	stats    []*git.FileStat
+	selected int
+	repoPath string
+	frame    *Frame
+}
+
+// NewDiffStatView creates a new diffstat view
+func NewDiffStatView(config *config.Config, client git.Client) *DiffStatView {
+	return &DiffStatView{
+		BaseView:   NewBaseView(ViewTypeDiffStat),
+		Scrollable: NewScrollable(),
+		config:     config,
+		client:     client,
+		frame:      NewFrame(config, "Diffstat"),
+	}
+}
+
+// Render renders the diffstat view
+func (v *DiffStatView) Render(screen tcell.Screen, x, y, width, height int) error {
+	v.SetPosition(x, y, width, height)
+	v.SetHeight(height - 2) // Account for borders
+
+	// Draw the frame, and its scrollbar once the content is laid out
+	contentX, contentY, contentWidth, contentHeight := v.frame.Draw(screen, x, y, width, height)
+	defer v.frame.DrawScrollbar(screen, v.Scrollable)
+
+	if contentWidth <= 0 || contentHeight <= 0 {
+		return nil
+	}
+
+	v.renderStats(screen, contentX, contentY, contentWidth, contentHeight)
+
+	return nil
+}
+
+// renderStats renders one line per file followed by a summary line
+func (v *DiffStatView) renderStats(screen tcell.Screen, x, y, width, height int) {
+	if len(v.stats) == 0 {
+		msg := "No changes in range"
+		if v.revRange == "" {
+			msg = "No revision range given"
+		} else if !v.client.IsRepository() {
+			msg = "Not in a git repository"
+		}
+
+		msgX := x + (width-len(msg))/2
+		msgY := y + height/2
+		if msgX >= x && msgY >= y {
+			for i, char := range msg {
+				screen.SetContent(msgX+i, msgY, char, nil, tcell.StyleDefault)
+			}
+		}
+		return
+	}
+
+	lines := v.buildStatLines(width)
+	v.SetMaxOffset(len(lines) - height)
+
+	start := v.GetOffset()
+	end := start + height
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	for i := start; i < end; i++ {
+		v.renderStatLine(screen, x, y+(i-start), width, lines[i], i == v.selected)
+	}
+}
+
+// statSegment is a run of text drawn with a single style
+type statSegment struct {
+	text  string
+	style tcell.Style
+}
+
+// buildStatLines formats the stats the way git diff --stat does, scaling
+// the histogram bars to the available width
+func (v *DiffStatView) buildStatLines(width int) [][]statSegment {
+	nameWidth, countWidth, maxChange := 0, 0, 0
+	insertions, deletions := 0, 0
+	for _, stat := range v.stats {
+		nameWidth = max(nameWidth, runewidth.StringWidth(statName(stat)))
+		changes := stat.Additions + stat.Deletions
+		maxChange = max(maxChange, changes)
+		countWidth = max(countWidth, len(fmt.Sprint(changes)))
+		insertions += stat.Additions
+		deletions += stat.Deletions
+	}
+	countWidth = max(countWidth, len("Bin"))
+
+	// " name | count bar", keeping at least some room for the bar
+	barWidth := width - nameWidth - countWidth - 5
+	if barWidth < 10 {
+		nameWidth = max(0, nameWidth-(10-barWidth))
+		barWidth = 10
+	}
+
+	lines := make([][]statSegment, 0, len(v.stats)+1)
+	for _, stat := range v.stats {
+		name := padText(statName(stat), nameWidth)
+
+		if stat.IsBinary {
+			lines = append(lines, []statSegment{
+				{text: fmt.Sprintf(" %s | %*s", name, countWidth, "Bin"), style: tcell.StyleDefault},
+			})
+			continue
+		}
+
+		added, deleted := stat.Additions, stat.Deletions
+		if maxChange > barWidth {
+			added = scaleStat(added, barWidth, maxChange)
+			deleted = scaleStat(deleted, barWidth, maxChange)
+		}
+		lines = append(lines, []statSegment{
+			{text: fmt.Sprintf(" %s | %*d ", name, countWidth, stat.Additions+stat.Deletions), style: tcell.StyleDefault},
+			{text: strings.Repeat("+", added), style: tcell.StyleDefault.Foreground(tcell.ColorGreen)},
+			{text: strings.Repeat("-", deleted), style: tcell.StyleDefault.Foreground(tcell.ColorRed)},
+		})
+	}
+
+	summary := fmt.Sprintf(" %d files changed, %d insertions(+), %d deletions(-)", len(v.stats), insertions, deletions)
+	lines = append(lines, []statSegment{{text: summary, style: tcell.StyleDefault}})
+	return lines
+}
+
+// statName returns the display name of a file, showing renames like git
+func statName(stat *git.FileStat) string {
+	if stat.OldPath != "" && stat.OldPath != stat.Path {
+		return stat.OldPath + " => " + stat.Path
+	}
+	return stat.Path
+}
+
+// scaleStat scales a change count to the bar width like git, so that any
+// non-zero count gets at least one character
+func scaleStat(count, width, maxChange int) int {
+	if count == 0 {
+		return 0
+	}
+	return 1 + (count*(width-1))/maxChange
+}
+
+// renderStatLine draws the segments of a line, highlighting the selection
+func (v *DiffStatView) renderStatLine(screen tcell.Screen, x, y, width int, segments []statSegment, selected bool) {
+	col := 0
+	for _, segment := range segments {
+		style := segment.style
+		if selected {
+			bg := tcell.ColorDarkBlue
+			if v.IsFocused() {
+				bg = tcell.ColorBlue
+			}
+			style = style.Background(bg)
+		}
+		for _, char := range segment.text {
+			if col >= width {
+				return
+			}
+			screen.SetContent(x+col, y, char, nil, style)
+			col++
+		}
+	}
+
+	// Fill remaining space with background
+	for ; col < width; col++ {
+		screen.SetContent(x+col, y, ' ', nil, tcell.StyleDefault)
+	}
+}
+
+// HandleKey handles keyboard input
+func (v *DiffStatView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
+	if !v.IsFocused() {
+		return false
+	}
+
+	switch key {
+	case tcell.KeyUp:
+		v.moveUp()
+		return true
+	case tcell.KeyDown:
+		v.moveDown()
+		return true
+	case tcell.KeyHome:
+		v.ScrollToTop()
+		v.selected = 0
+		return true
+	case tcell.KeyEnd:
+		v.ScrollToBottom()
+		v.selected = max(0, len(v.stats)-1)
+		return true
+	}
+
+	switch ch {
+	case 'j':
+		v.moveDown()
+		return true
+	case 'k':
+		v.moveUp()
+		return true
+	case 'g':
+		v.ScrollToTop()
+		v.selected = 0
+		return true
+	case 'G':
+		v.ScrollToBottom()
+		v.selected = max(0, len(v.stats)-1)
+		return true
+	}
+
+	return false
+}
+
+// moveUp moves selection up
+func (v *DiffStatView) moveUp() {
+	if v.selected > 0 {
+		v.selected--
+		if v.selected < v.GetOffset() {
+			v.ScrollUp()
+		}
+	}
+}
+
+// moveDown moves selection down, stopping at the last file
+func (v *DiffStatView) moveDown() {
+	if v.selected < len(v.stats)-1 {
+		v.selected++
+		visibleEnd := v.GetOffset() + v.getPageSize()
+		if v.selected >= visibleEnd {
+			v.ScrollDown()
+		}
+	}
+}
+
+// getPageSize returns the number of visible lines
+func (v *DiffStatView) getPageSize() int {
+	_, _, _, height := v.GetPosition()
+	return height - 2 // Account for borders
+}
+
+// Refresh reloads the diffstat of the current range
+func (v *DiffStatView) Refresh() error {
+	if !v.client.IsRepository() || v.revRange == "" {
+		v.stats = nil
+		v.selected = 0
+		return nil
+	}
+
+	stats, err := v.client.GetDiffStat(v.revRange)
+	if err != nil {
+		return err
+	}
+
+	v.stats = stats
+	if v.selected >= len(v.stats) {
+		v.selected = max(0, len(v.stats)-1)
+	}
+	return nil
+}
+
+// SetRange sets the revision range to show the diffstat of
+func (v *DiffStatView) SetRange(revRange string) error {
+	v.revRange = revRange
+	v.frame.Title = "Diffstat " + revRange
+	v.selected = 0
+	v.ScrollToTop()
+	return v.Refresh()
+}
+
+// GetRange returns the revision range being shown
+func (v *DiffStatView) GetRange() string {
+	return v.revRange
+}
+
+// GetSelectedStat returns the stat of the selected file
+func (v *DiffStatView) GetSelectedStat() *git.FileStat {
+	if v.selected < 0 || v.selected >= len(v.stats) {
+		return nil
+	}
+	return v.stats[v.selected]
+}
+
+// DiffRange returns the range and paths of the diff of the selected file.
+// Both sides of a rename are included so git can pair them up again.
+func (v *DiffStatView) DiffRange() (string, []string, error) {
+	stat := v.GetSelectedStat()
+	if stat == nil {
+		return "", nil, fmt.Errorf("no file selected")
+	}
+
+	paths := []string{stat.Path}
+	if stat.OldPath != "" && stat.OldPath != stat.Path {
+		paths = []string{stat.OldPath, stat.Path}
+	}
+	return v.revRange, paths, nil
+}
+
+// PagerArgs returns the git arguments producing the raw diffstat
+func (v *DiffStatView) PagerArgs() ([]string, error) {
+	if v.revRange == "" {
+		return nil, fmt.Errorf("no revision range given")
+	}
+	return []string{"diff", "--stat", "-M", v.revRange, "--"}, nil
+}
+
+// Selection returns the selected file for placeholder expansion
+func (v *DiffStatView) Selection() Selection {
+	var sel Selection
+	if stat := v.GetSelectedStat(); stat != nil {
+		sel.File = stat.Path
+	}
+	return sel
+}
+
+// SetRepoPath sets the repository path
+func (v *DiffStatView) SetRepoPath(path string) {
+	v.repoPath = path
+}
+
+// DiffStatCommand handles the :diffstat command, showing the diffstat of
+// a range given as "rev1..rev2" or as two separate revisions
+func (vm *ViewManager) DiffStatCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	var revRange string
+	switch len(args) {
+	case 1:
+		revRange = args[0]
+	case 2:
+		revRange = args[0] + ".." + args[1]
+	default:
+		return fmt.Errorf("usage: diffstat <rev1>..<rev2>")
+	}
+	if !strings.Contains(revRange, "..") {
+		return fmt.Errorf("usage: diffstat <rev1>..<rev2>")
+	}
+
+	if err := vm.checkRevisions(revRange); err != nil {
+		return err
+	}
+
+	view, ok := vm.views[ViewTypeDiffStat].(*DiffStatView)
+	if !ok {
+		return fmt.Errorf("diffstat view not found")
+	}
+	if err := view.SetRange(revRange); err != nil {
+		return err
+	}
+	return vm.switchView(ViewTypeDiffStat)
+}
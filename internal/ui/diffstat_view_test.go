@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDiffStatView(t *testing.T) {
+	cfg := &config.Config{}
+	client := git.NewClient()
+
+	view := NewDiffStatView(cfg, client)
+	assert.NotNil(t, view)
+	assert.Equal(t, ViewTypeDiffStat, view.GetType())
+	assert.Equal(t, "", view.GetRange())
+}
+
+func TestDiffStatViewRender(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	err := screen.Init()
+	assert.NoError(t, err)
+
+	cfg := &config.Config{}
+	client := git.NewClient()
+
+	view := NewDiffStatView(cfg, client)
+
+	// Test rendering without a range
+	err = view.Render(screen, 0, 0, 80, 24)
+	assert.NoError(t, err)
+
+	view.revRange = "v1..v2"
+	view.stats = []*git.FileStat{
+		{Path: "main.go", Additions: 3, Deletions: 1},
+		{Path: "logo.png", IsBinary: true},
+	}
+	err = view.Render(screen, 0, 0, 80, 24)
+	assert.NoError(t, err)
+}
+
+func TestDiffStatViewBuildStatLines(t *testing.T) {
+	cfg := &config.Config{}
+	client := git.NewClient()
+
+	view := NewDiffStatView(cfg, client)
+	view.stats = []*git.FileStat{
+		{Path: "main.go", Additions: 3, Deletions: 1},
+		{Path: "big.go", Additions: 100, Deletions: 100},
+		{Path: "logo.png", IsBinary: true},
+		{Path: "new.go", OldPath: "old.go"},
+	}
+
+	lines := view.buildStatLines(40)
+	assert.Len(t, lines, 5)
+
+	text := func(line []statSegment) string {
+		s := ""
+		for _, segment := range line {
+			s += segment.text
+		}
+		return s
+	}
+
+	// Bars are scaled to the 40 - 16 - 3 - 5 = 16 columns left
+	assert.Equal(t, " main.go          |   4 +-", text(lines[0]))
+	assert.Equal(t, " big.go           | 200 ++++++++--------", text(lines[1]))
+	assert.Equal(t, " logo.png         | Bin", text(lines[2]))
+	assert.Equal(t, " old.go => new.go |   0 ", text(lines[3]))
+	assert.Equal(t, " 4 files changed, 103 insertions(+), 101 deletions(-)", text(lines[4]))
+
+	// Names too long for the room left are cut by display width, not
+	// through the bytes of a character
+	view.stats = []*git.FileStat{{Path: "文档/说明/很长的文件名.md", Additions: 1}}
+	lines = view.buildStatLines(30)
+	assert.Equal(t, " 文档/说明/…  |   1 +", text(lines[0]))
+}
+
+func TestDiffStatViewDiffRange(t *testing.T) {
+	cfg := &config.Config{}
+	client := git.NewClient()
+
+	view := NewDiffStatView(cfg, client)
+	view.Focus()
+	view.SetPosition(0, 0, 80, 24)
+
+	_, _, err := view.DiffRange()
+	assert.Error(t, err)
+
+	view.revRange = "v1..v2"
+	view.stats = []*git.FileStat{
+		{Path: "main.go", Additions: 3, Deletions: 1},
+		{Path: "new.go", OldPath: "old.go"},
+	}
+
+	revRange, paths, err := view.DiffRange()
+	assert.NoError(t, err)
+	assert.Equal(t, "v1..v2", revRange)
+	assert.Equal(t, []string{"main.go"}, paths)
+
+	// Selection stops at the last file
+	view.HandleKey(tcell.KeyDown, 0, 0)
+	view.HandleKey(tcell.KeyDown, 0, 0)
+	assert.Equal(t, 1, view.selected)
+
+	_, paths, err = view.DiffRange()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"old.go", "new.go"}, paths)
+}
+
+func TestDiffStatCommand(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	err := screen.Init()
+	assert.NoError(t, err)
+	cfg := &config.Config{}
+	client := git.NewClient()
+	keyBindingMgr := NewKeyBindingManager(cfg)
+
+	vm := NewViewManager(screen, cfg, client, keyBindingMgr)
+
+	assert.Error(t, vm.DiffStatCommand(nil))
+	assert.Error(t, vm.DiffStatCommand([]string{"HEAD"}))
+	assert.Error(t, vm.DiffStatCommand([]string{"v1..v2"}))
+	assert.Equal(t, ViewTypeMain, vm.GetCurrentView())
+}
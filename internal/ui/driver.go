@@ -0,0 +1,195 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// driverCall is posted to the event loop to run a function between events,
+// where it can safely look at the screen and views
+type driverCall func()
+
+// ErrNotRunning is returned by Driver methods once the application quit
+var ErrNotRunning = errors.New("application is not running")
+
+// Driver runs the full application on a simulation screen so it can be
+// scripted, as done by end-to-end tests
+type Driver struct {
+	terminal *Terminal
+	screen   tcell.SimulationScreen
+	config   *config.Config
+	client   git.Client
+	repoPath string
+	done     chan error
+}
+
+// NewDriver creates a driver for the application showing the repository at
+// repoPath on a screen of the given size
+func NewDriver(cfg *config.Config, client git.Client, repoPath string, width, height int) (*Driver, error) {
+	screen := tcell.NewSimulationScreen("")
+	terminal, err := NewTerminalWithScreen(screen)
+	if err != nil {
+		return nil, err
+	}
+	screen.SetSize(width, height)
+	terminal.width, terminal.height = width, height
+
+	return &Driver{
+		terminal: terminal,
+		screen:   screen,
+		config:   cfg,
+		client:   client,
+		repoPath: repoPath,
+	}, nil
+}
+
+// Start runs the application and waits until it handles events
+func (d *Driver) Start() error {
+	if d.done != nil {
+		return fmt.Errorf("application already started")
+	}
+
+	d.done = make(chan error, 1)
+	go func() {
+		d.done <- d.terminal.Run(d.config, d.client, d.repoPath)
+		d.terminal.Close()
+	}()
+	return d.call(func() {})
+}
+
+// Stop quits the application and returns the error it exited with
+func (d *Driver) Stop() error {
+	if d.done == nil {
+		return ErrNotRunning
+	}
+	if err := d.call(func() { d.terminal.running.Store(false) }); err != nil && err != ErrNotRunning {
+		return err
+	}
+	return d.wait()
+}
+
+// SendKeys types the given keys and waits until the application handled
+// them. Keys in angle brackets, such as <Enter>, <Esc> or <C-l>, are special
+// keys; anything else is typed one character at a time. Keys which quit
+// the application are not an error.
+func (d *Driver) SendKeys(keys ...string) error {
+	for _, key := range keys {
+		for _, ev := range keyEvents(key) {
+			if err := d.post(ev); err != nil {
+				return err
+			}
+		}
+	}
+	if err := d.call(func() {}); err != nil && err != ErrNotRunning {
+		return err
+	}
+	return nil
+}
+
+// Screenshot returns the text on the screen with one line per row
+func (d *Driver) Screenshot() (string, error) {
+	var lines []string
+	err := d.call(func() { lines = screenLines(d.screen) })
+	return strings.Join(lines, "\n"), err
+}
+
+// CurrentView returns the type of the view shown
+func (d *Driver) CurrentView() (ViewType, error) {
+	var viewType ViewType
+	err := d.call(func() { viewType = d.terminal.viewManager.GetCurrentView() })
+	return viewType, err
+}
+
+// SelectedItem returns what is selected in the current view
+func (d *Driver) SelectedItem() (Selection, error) {
+	var sel Selection
+	err := d.call(func() {
+		vm := d.terminal.viewManager
+		if source, ok := vm.GetView(vm.GetCurrentView()).(SelectionSource); ok {
+			sel = source.Selection()
+		}
+	})
+	return sel, err
+}
+
+// Message returns the message shown in the status line
+func (d *Driver) Message() (string, error) {
+	var message string
+	err := d.call(func() { message = d.terminal.viewManager.GetMessage() })
+	return message, err
+}
+
+// call runs fn on the event loop after the events queued before it
+func (d *Driver) call(fn func()) error {
+	if d.done == nil {
+		return ErrNotRunning
+	}
+
+	called := make(chan struct{})
+	if err := d.post(tcell.NewEventInterrupt(driverCall(func() {
+		fn()
+		close(called)
+	}))); err != nil {
+		return err
+	}
+
+	select {
+	case <-called:
+		return nil
+	case err := <-d.done:
+		d.done <- err
+		return ErrNotRunning
+	}
+}
+
+// post queues an event for the application, waiting while the queue is full
+func (d *Driver) post(ev tcell.Event) error {
+	if d.done == nil {
+		return ErrNotRunning
+	}
+	for d.screen.PostEvent(ev) != nil {
+		select {
+		case err := <-d.done:
+			d.done <- err
+			return ErrNotRunning
+		case <-time.After(time.Millisecond):
+		}
+	}
+	return nil
+}
+
+// wait waits for the application to exit
+func (d *Driver) wait() error {
+	err := <-d.done
+	d.done <- err
+	return err
+}
+
+// keyEvents converts a SendKeys argument into key events
+func keyEvents(key string) []*tcell.EventKey {
+	if len(key) > 2 && strings.HasPrefix(key, "<") && strings.HasSuffix(key, ">") {
+		return []*tcell.EventKey{specialKeyEvent(key)}
+	}
+
+	var events []*tcell.EventKey
+	for _, ch := range key {
+		events = append(events, tcell.NewEventKey(tcell.KeyRune, ch, tcell.ModNone))
+	}
+	return events
+}
+
+// specialKeyEvent parses the tigrc notation of a key, turning control
+// letters into the control keys the terminal reports for them
+func specialKeyEvent(key string) *tcell.EventKey {
+	k, ch, mods := (&KeyBindingManager{}).parseBinding(key)
+	if k == tcell.KeyRune && mods&tcell.ModCtrl != 0 && ch >= 'a' && ch <= 'z' {
+		return tcell.NewEventKey(tcell.KeyCtrlA+tcell.Key(ch-'a'), 0, mods)
+	}
+	return tcell.NewEventKey(k, ch, mods)
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver(t *testing.T) {
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	cfg.General.RefreshInterval = 0
+
+	driver, err := NewDriver(cfg, git.NewClient(), t.TempDir(), 80, 24)
+	require.NoError(t, err)
+	assert.ErrorIs(t, driver.SendKeys("h"), ErrNotRunning)
+	require.NoError(t, driver.Start())
+
+	// Outside of a repository the start screen is shown
+	view, err := driver.CurrentView()
+	assert.NoError(t, err)
+	assert.Equal(t, ViewTypeStart, view)
+
+	require.NoError(t, driver.SendKeys("h"))
+	view, err = driver.CurrentView()
+	assert.NoError(t, err)
+	assert.Equal(t, ViewTypeHelp, view)
+
+	screen, err := driver.Screenshot()
+	assert.NoError(t, err)
+	assert.Contains(t, screen, "Go Tig Help")
+
+	// Commands are typed on the command line like any other keys
+	require.NoError(t, driver.SendKeys("l", ":", "set tab-size 3", "<Enter>"))
+	message, err := driver.Message()
+	assert.NoError(t, err)
+	assert.Equal(t, "tab-size = 3", message)
+
+	sel, err := driver.SelectedItem()
+	assert.NoError(t, err)
+	assert.Equal(t, Selection{}, sel)
+
+	// Quitting the application ends the session
+	require.NoError(t, driver.SendKeys("q"))
+	_, err = driver.Screenshot()
+	assert.ErrorIs(t, err, ErrNotRunning)
+	assert.NoError(t, driver.Stop())
+}
+
+func TestKeyEvents(t *testing.T) {
+	events := keyEvents("ab")
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, 'a', events[0].Rune())
+		assert.Equal(t, 'b', events[1].Rune())
+	}
+
+	events = keyEvents("<Enter>")
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, tcell.KeyEnter, events[0].Key())
+	}
+
+	events = keyEvents("<C-l>")
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, tcell.KeyCtrlL, events[0].Key())
+	}
+
+	// A lone angle bracket is typed as is
+	events = keyEvents("<")
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, '<', events[0].Rune())
+	}
+}
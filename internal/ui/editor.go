@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"github.com/azhao1981/tig/internal/config"
+)
+
+// EditorSource is implemented by views which can open the item under the
+// cursor in the external editor
+type EditorSource interface {
+	// EditorTarget returns the repository relative path and line to open
+	EditorTarget() (path string, line int, err error)
+}
+
+// editCurrentView opens the editor target of the current view
+func (vm *ViewManager) editCurrentView() error {
+	source, ok := vm.views[vm.currentView].(EditorSource)
+	if !ok {
+		return fmt.Errorf("nothing to edit in this view")
+	}
+
+	path, line, err := source.EditorTarget()
+	if err != nil {
+		return err
+	}
+	return vm.openEditor(path, line)
+}
+
+// openEditor opens a repository file in the external editor at the given line
+func (vm *ViewManager) openEditor(path string, line int) error {
+	if !vm.client.IsRepository() {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	fullPath := filepath.Join(vm.client.GetRootPath(), path)
+	args := []string{}
+	if line > 0 {
+		args = append(args, "+"+strconv.Itoa(line))
+	}
+	args = append(args, fullPath)
+	return vm.runEditor(args...)
+}
+
+// runEditor runs the external editor with the given arguments in the
+// foreground
+func (vm *ViewManager) runEditor(args ...string) error {
+	// Run through the shell so editors configured with arguments work
+	cmdArgs := append([]string{"-c", vm.editorProgram() + ` "$@"`, "sh"}, args...)
+	cmd := exec.Command("sh", cmdArgs...)
+	cmd.Dir = vm.client.GetRootPath()
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return vm.suspend(func() error {
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("editor failed: %w", err)
+		}
+		return nil
+	})
+}
+
+// editorProgram returns the editor command line to use: GIT_EDITOR, the
+// editor option when set, core.editor, then the default of the option,
+// which is VISUAL, EDITOR or vi
+func (vm *ViewManager) editorProgram() string {
+	if editor := os.Getenv("GIT_EDITOR"); editor != "" {
+		return editor
+	}
+	editor := vm.config.General.Editor
+	if editor != "" && editor != config.DefaultEditor() {
+		return editor
+	}
+	if editor := vm.client.ConfigValue("core.editor"); editor != "" {
+		return editor
+	}
+	if editor != "" {
+		return editor
+	}
+	return "vi"
+}
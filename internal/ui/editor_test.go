@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditorProgram(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	refsTestGit(t, dir, "init", "--quiet")
+	t.Setenv("GIT_EDITOR", "")
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "joe")
+
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	cfg.General.Editor = config.DefaultEditor()
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+	assert.Equal(t, "joe", vm.editorProgram())
+
+	// core.editor comes before the editor of the environment, as with git
+	refsTestGit(t, dir, "config", "core.editor", "emacs")
+	assert.Equal(t, "emacs", vm.editorProgram())
+
+	// The editor option of tig comes before core.editor once set
+	cfg.General.Editor = "nano"
+	assert.Equal(t, "nano", vm.editorProgram())
+	t.Setenv("GIT_EDITOR", "ed")
+	assert.Equal(t, "ed", vm.editorProgram())
+
+	// vi when nothing says otherwise
+	t.Setenv("GIT_EDITOR", "")
+	cfg.General.Editor = ""
+	refsTestGit(t, dir, "config", "--unset", "core.editor")
+	assert.Equal(t, "vi", vm.editorProgram())
+}
@@ -0,0 +1,19 @@
+package ui
+
+// toggleEOLChanges hides the lines of diffs whose line ending alone changed,
+// or shows them again, as setting hide-eol-changes does
+func (vm *ViewManager) toggleEOLChanges() error {
+	hide := !vm.config.Views.Diff.HideEOLChanges
+	vm.config.Views.Diff.HideEOLChanges = hide
+	if diffView, ok := vm.views[ViewTypeDiff].(*DiffView); ok {
+		if err := diffView.Refresh(); err != nil {
+			return err
+		}
+	}
+	if hide {
+		vm.setMessage("Hiding lines whose line ending alone changed")
+	} else {
+		vm.setMessage("Showing lines whose line ending alone changed")
+	}
+	return nil
+}
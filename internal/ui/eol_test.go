@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEOLOnlyChanges(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	data, err := os.ReadFile(filepath.Join(dir, "notes.txt"))
+	require.NoError(t, err)
+	crlf := strings.ReplaceAll(string(data), "\n", "\r\n")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(crlf), 0644))
+
+	// The status flags files whose line endings alone changed
+	statusView := vm.views[ViewTypeStatus].(*StatusView)
+	require.NoError(t, statusView.refreshChanged())
+	lines, _ := statusView.buildStatusContent()
+	assert.Contains(t, lines, "\tmodified: notes.txt (line endings only)")
+
+	// and so does their diff, which L hides them from
+	diffView := vm.views[ViewTypeDiff].(*DiffView)
+	require.NoError(t, vm.showStageFile("notes.txt", statusSectionModified))
+	require.NotEmpty(t, diffView.GetDiff().Files[0].Hunks)
+	assert.Equal(t, "only line endings changed", eolSummary(diffView.GetDiff().Files[0]))
+
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'L', 0))
+	assert.Equal(t, "Hiding lines whose line ending alone changed", vm.GetMessage())
+	assert.True(t, vm.config.Views.Diff.HideEOLChanges)
+	assert.Empty(t, diffView.GetDiff().Files[0].Hunks)
+	assert.Contains(t, eolSummary(diffView.GetDiff().Files[0]), "lines hidden")
+
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'L', 0))
+	assert.False(t, vm.config.Views.Diff.HideEOLChanges)
+	assert.NotEmpty(t, diffView.GetDiff().Files[0].Hunks)
+}
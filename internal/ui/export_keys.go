@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// tigrcKeyNames are the names tigrc bind lines use for keys other than
+// characters
+var tigrcKeyNames = map[tcell.Key]string{
+	tcell.KeyUp:        "Up",
+	tcell.KeyDown:      "Down",
+	tcell.KeyLeft:      "Left",
+	tcell.KeyRight:     "Right",
+	tcell.KeyPgUp:      "PgUp",
+	tcell.KeyPgDn:      "PgDn",
+	tcell.KeyHome:      "Home",
+	tcell.KeyEnd:       "End",
+	tcell.KeyEnter:     "Enter",
+	tcell.KeyEsc:       "Esc",
+	tcell.KeyTab:       "Tab",
+	tcell.KeyBackspace: "Backspace",
+	tcell.KeyDelete:    "Delete",
+}
+
+// sortedActions returns the bound actions in sorted order
+func (k *KeyBindingManager) sortedActions() []string {
+	actions := make([]string, 0, len(k.bindings))
+	for action := range k.bindings {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+// tigrcKey returns a binding in the notation of tigrc bind lines, false
+// for keys which can't be written down
+func (k *KeyBindingManager) tigrcKey(binding *KeyBinding) (string, bool) {
+	name := ""
+	switch {
+	case binding.Key == tcell.KeyRune && binding.Rune == ' ':
+		name = "Space"
+	case binding.Key == tcell.KeyRune && binding.Rune != 0:
+		// # starts a comment wherever it is
+		if binding.Rune == '#' {
+			return "", false
+		}
+		name = string(binding.Rune)
+		if binding.Mods == 0 {
+			return name, true
+		}
+	default:
+		var ok bool
+		if name, ok = tigrcKeyNames[binding.Key]; !ok {
+			return "", false
+		}
+	}
+
+	if binding.Mods&tcell.ModShift != 0 {
+		name = "Shift-" + name
+	}
+	if binding.Mods&tcell.ModAlt != 0 {
+		name = "Alt-" + name
+	}
+	if binding.Mods&tcell.ModCtrl != 0 {
+		name = "C-" + name
+	}
+	return "<" + name + ">", true
+}
+
+// ExportTigrc writes the bindings in effect as tigrc bind lines, which
+// reproduce them when added to a tigrc. It returns how many were written.
+func (k *KeyBindingManager) ExportTigrc(w io.Writer) int {
+	count := 0
+	for _, action := range k.sortedActions() {
+		if key, ok := k.tigrcKey(k.bindings[action]); ok {
+			fmt.Fprintf(w, "bind generic %s %s\n", key, action)
+			count++
+		}
+	}
+	return count
+}
+
+// ExportMarkdown writes the bindings in effect as a markdown table and
+// returns how many were written
+func (k *KeyBindingManager) ExportMarkdown(w io.Writer) int {
+	escape := strings.NewReplacer("|", `\|`, "`", "\\`").Replace
+
+	fmt.Fprintln(w, "| Key | Action | Description |")
+	fmt.Fprintln(w, "| --- | --- | --- |")
+	for _, action := range k.sortedActions() {
+		binding := k.bindings[action]
+		fmt.Fprintf(w, "| %s | %s | %s |\n", escape(k.bindingToString(binding)), escape(action), escape(binding.Help))
+	}
+	return len(k.bindings)
+}
+
+// exportKeysCommand handles the :export-keys command, writing the key
+// bindings in effect to a file, as a markdown table for .md files and as
+// tigrc bind lines otherwise
+func (t *Terminal) exportKeysCommand(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: export-keys <file>")
+	}
+	path := args[0]
+
+	var buf bytes.Buffer
+	var count int
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		count = t.keyBindingMgr.ExportMarkdown(&buf)
+	default:
+		count = t.keyBindingMgr.ExportTigrc(&buf)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to export key bindings: %w", err)
+	}
+	t.viewManager.SetMessage("Exported %d key bindings to %s", count, path)
+	return nil
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportKeysCommand(t *testing.T) {
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	cfg.Keymaps.Bindings["refresh"] = "<C-r>"
+	cfg.Keymaps.Bindings["!git gc"] = "<alt-g>"
+	cfg.Keymaps.Bindings["!git fsck"] = "#"
+	term := newTestTerminal(t, cfg)
+	term.keyBindingMgr.Reload()
+	dir := t.TempDir()
+
+	assert.Error(t, term.exportKeysCommand(nil))
+
+	// The tigrc bind lines reproduce the bindings, overrides included
+	tigrc := filepath.Join(dir, "keys.tigrc")
+	require.NoError(t, term.exportKeysCommand([]string{tigrc}))
+	assert.Contains(t, term.viewManager.GetMessage(), "key bindings to "+tigrc)
+	data, err := os.ReadFile(tigrc)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "bind generic <C-r> refresh\n")
+	assert.Contains(t, string(data), "bind generic <Alt-g> !git gc\n")
+	assert.NotContains(t, string(data), "!git fsck", "# can't be bound in tigrc")
+	assert.Contains(t, string(data), "bind generic <Enter> enter\n")
+
+	loaded := &config.Config{}
+	require.NoError(t, loaded.LoadFile(tigrc))
+	assert.Empty(t, loaded.Warnings)
+	reloaded := NewKeyBindingManager(loaded)
+	for action, binding := range term.keyBindingMgr.GetAllBindings() {
+		if action == "!git fsck" {
+			continue
+		}
+		got, ok := reloaded.GetBinding(action)
+		if assert.True(t, ok, action) {
+			assert.Equal(t, [3]any{binding.Key, binding.Rune, binding.Mods}, [3]any{got.Key, got.Rune, got.Mods}, action)
+		}
+	}
+
+	markdown := filepath.Join(dir, "keys.md")
+	require.NoError(t, term.exportKeysCommand([]string{markdown}))
+	data, err = os.ReadFile(markdown)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, "| Key | Action | Description |", lines[0])
+	assert.Len(t, lines, len(term.keyBindingMgr.GetAllBindings())+2)
+	assert.Contains(t, lines, "| # | !git fsck | Run git fsck |")
+	assert.Contains(t, lines, "| Ctrl+r | refresh | Refresh all views |")
+}
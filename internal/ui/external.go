@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ExternalCommand handles the :! command, which runs a program in the
+// foreground with placeholders such as %(commit) expanded
+func (vm *ViewManager) ExternalCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if len(args) == 0 {
+		return fmt.Errorf("usage: !<command> [args...]")
+	}
+	return vm.runExternal(args)
+}
+
+// runExternal runs an external command in the repository root and
+// refreshes the views afterwards, as the command may have changed the
+// repository
+func (vm *ViewManager) runExternal(args []string) error {
+	if !vm.client.IsRepository() {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	expanded, err := ExpandPlaceholders(args, vm.selection())
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(expanded[0], expanded[1:]...)
+	cmd.Dir = vm.client.GetRootPath()
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err = vm.suspend(func() error {
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s failed: %w", strings.Join(expanded, " "), err)
+		}
+		return nil
+	})
+	vm.recordExternal(expanded, err)
+	if err != nil {
+		return err
+	}
+	return vm.refreshAll()
+}
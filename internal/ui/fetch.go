@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+)
+
+// FetchCommand handles the :fetch command, which fetches all remotes in
+// the background
+func (vm *ViewManager) FetchCommand(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("usage: fetch")
+	}
+
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	return vm.startFetch()
+}
+
+// startFetch fetches all remotes in the background, showing its progress,
+// unless a fetch is already running
+func (vm *ViewManager) startFetch() error {
+	if !vm.client.IsRepository() {
+		return fmt.Errorf("not in a git repository")
+	}
+	for _, j := range vm.jobs {
+		if j.kind == jobFetch && j.running() {
+			return fmt.Errorf("%s is still running", j.name)
+		}
+	}
+	// Fetching by hand counts for the auto-fetch
+	vm.lastFetch = time.Now()
+	vm.fetch("Fetching", false)
+	return nil
+}
+
+// fetch fetches the remotes as the job of the given name, pointing out
+// commits which arrived for the upstream of the current branch. Quiet
+// fetches show no progress and say nothing when none did.
+func (vm *ViewManager) fetch(name string, quiet bool) {
+	behind := 0
+	if status, err := vm.client.GetBranchStatus(); err == nil {
+		behind = status.Behind
+	}
+
+	vm.startJob(&job{kind: jobFetch, name: name, quiet: quiet}, vm.client.Fetch, func(err error) string {
+		if err != nil {
+			return fmt.Sprintf("%s failed: %v", name, err)
+		}
+		vm.updateTitle()
+		vm.updateRefIndex()
+		if view, ok := vm.views[vm.currentView]; ok {
+			_ = view.Refresh()
+		}
+
+		status, err := vm.client.GetBranchStatus()
+		switch {
+		case err == nil && status.Behind-behind == 1:
+			return fmt.Sprintf("1 new upstream commit on %s", status.Upstream)
+		case err == nil && status.Behind > behind:
+			return fmt.Sprintf("%d new upstream commits on %s", status.Behind-behind, status.Upstream)
+		case !quiet:
+			return "Fetched all remotes"
+		}
+		return ""
+	})
+}
+
+// PullCommand handles the :pull command, which fast-forwards the current
+// branch to its upstream in the background
+func (vm *ViewManager) PullCommand(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("usage: pull")
+	}
+
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	status, err := vm.client.GetBranchStatus()
+	if err != nil {
+		return err
+	}
+	if status.Branch == "" {
+		return fmt.Errorf("HEAD is detached, there is no branch to pull")
+	}
+	if status.Upstream == "" {
+		return fmt.Errorf("branch %s has no upstream", status.Branch)
+	}
+	before := ""
+	if head, err := vm.client.GetHead(); err == nil {
+		before = head.Hash
+	}
+
+	name := fmt.Sprintf("Pulling %s into %s", status.Upstream, status.Branch)
+	vm.startJob(&job{kind: jobPull, name: name}, vm.client.Pull, func(err error) string {
+		if err != nil {
+			return err.Error()
+		}
+		vm.refreshAll()
+		head, err := vm.client.GetHead()
+		switch {
+		case err != nil:
+			return err.Error()
+		case head.Hash == before:
+			return fmt.Sprintf("%s is up to date with %s", status.Branch, status.Upstream)
+		default:
+			return fmt.Sprintf("Fast-forwarded %s to %s", status.Branch, vm.client.AbbrevHash(head.Hash))
+		}
+	})
+	return nil
+}
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runJobsToEnd runs the events background jobs post until none is running
+func runJobsToEnd(t *testing.T, vm *ViewManager, screen tcell.Screen) {
+	t.Helper()
+	for _, j := range vm.jobs {
+		for j.running() {
+			runJobEvent(t, screen)
+		}
+	}
+}
+
+func TestFetchAndPull(t *testing.T) {
+	upstream, _ := autostashTestRepo(t)
+	dir := t.TempDir()
+	refsTestGit(t, dir, "clone", "--quiet", upstream, ".")
+
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	screen.SetSize(100, 30)
+	cfg := &config.Config{}
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+	vm.SetSize(100, 30)
+	vm.SetRepoPath(dir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(upstream, "new.txt"), []byte("new\n"), 0644))
+	refsTestGit(t, upstream, "add", "new.txt")
+	refsTestGit(t, upstream, "commit", "--quiet", "-m", "new")
+
+	// The progress of the fetch is shown over the view until it is done
+	vm.HandleKey(tcell.KeyRune, 'F', 0)
+	assert.EqualError(t, vm.FetchCommand(nil), "Fetching is still running")
+	require.NoError(t, vm.Render())
+	screenText := strings.Join(screenLines(screen), "\n")
+	assert.Contains(t, screenText, "Fetching")
+	assert.Contains(t, screenText, "Waiting for the remote")
+	runJobsToEnd(t, vm, screen)
+	assert.Equal(t, "1 new upstream commit on origin/main", vm.GetMessage())
+	require.NoError(t, vm.Render())
+	assert.NotContains(t, strings.Join(screenLines(screen), "\n"), "Waiting for the remote")
+
+	require.NoError(t, vm.FetchCommand(nil))
+	runJobsToEnd(t, vm, screen)
+	assert.Equal(t, "Fetched all remotes", vm.GetMessage())
+
+	require.NoError(t, vm.PullCommand(nil))
+	runJobsToEnd(t, vm, screen)
+	assert.Equal(t, "Fast-forwarded main to "+vm.client.AbbrevHash(refsTestGit(t, upstream, "rev-parse", "main")), vm.GetMessage())
+	assert.FileExists(t, filepath.Join(dir, "new.txt"))
+	require.NoError(t, vm.PullCommand(nil))
+	runJobsToEnd(t, vm, screen)
+	assert.Equal(t, "main is up to date with origin/main", vm.GetMessage())
+
+	refsTestGit(t, dir, "checkout", "--quiet", "--detach")
+	assert.EqualError(t, vm.PullCommand(nil), "HEAD is detached, there is no branch to pull")
+}
+
+// askPassClient keeps the askpass the git commands are given
+type askPassClient struct {
+	git.Client
+	env []string
+}
+
+func (c *askPassClient) SetAskPass(program string, env []string) {
+	c.env = env
+	c.Client.SetAskPass(program, env)
+}
+
+func TestAskPass(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	screen.SetSize(100, 30)
+	cfg := &config.Config{}
+	client := &askPassClient{Client: git.NewClient()}
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+	vm.SetSize(100, 30)
+
+	stop, err := vm.listenAskPass()
+	require.NoError(t, err)
+	defer stop()
+	require.Len(t, client.env, 1)
+	socket := strings.TrimPrefix(client.env[0], askPassSocketEnv+"=")
+	t.Setenv(askPassSocketEnv, socket)
+	assert.True(t, AskPassHelper())
+
+	ask := func(prompt string) (chan int, *bytes.Buffer) {
+		status, output := make(chan int, 1), &bytes.Buffer{}
+		go func() { status <- RunAskPass([]string{prompt}, output) }()
+		runJobEvent(t, screen)
+		return status, output
+	}
+
+	// Passwords are masked as they are typed
+	status, output := ask("Password for 'https://example.com': ")
+	require.True(t, vm.Confirming())
+	for _, ch := range "s3cret" {
+		vm.HandleKey(tcell.KeyRune, ch, 0)
+	}
+	require.NoError(t, vm.Render())
+	screenText := strings.Join(screenLines(screen), "\n")
+	assert.Contains(t, screenText, "Password for 'https://example.com': ******")
+	assert.NotContains(t, screenText, "s3cret")
+	vm.HandleKey(tcell.KeyEnter, 0, 0)
+	assert.Equal(t, 0, <-status)
+	assert.Equal(t, "s3cret\n", output.String())
+
+	status, output = ask("Username for 'https://example.com': ")
+	vm.HandleKey(tcell.KeyRune, 'm', 0)
+	vm.HandleKey(tcell.KeyRune, 'e', 0)
+	require.NoError(t, vm.Render())
+	assert.Contains(t, strings.Join(screenLines(screen), "\n"), "Username for 'https://example.com': me")
+	vm.HandleKey(tcell.KeyEnter, 0, 0)
+	assert.Equal(t, 0, <-status)
+	assert.Equal(t, "me\n", output.String())
+
+	// Cancelling the prompt fails the helper
+	status, output = ask("Enter passphrase for key '/home/me/.ssh/id_ed25519': ")
+	vm.HandleKey(tcell.KeyEsc, 0, 0)
+	assert.Equal(t, 1, <-status)
+	assert.Empty(t, output.String())
+	assert.False(t, vm.Confirming())
+}
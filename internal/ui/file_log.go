@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// fileLogCount is the number of commits listed by the file log popup
+const fileLogCount = 10
+
+// fileLog is the popup listing the last commits touching a file, shown over
+// the view it was opened from
+type fileLog struct {
+	path     string
+	commits  []*git.Commit
+	selected int
+}
+
+// showFileLog opens the popup listing the last commits touching the file
+// selected in the status or tree view
+func (vm *ViewManager) showFileLog() error {
+	source, ok := vm.views[vm.currentView].(SelectionSource)
+	if !ok || (vm.currentView != ViewTypeStatus && vm.currentView != ViewTypeTree) {
+		return fmt.Errorf("the file log is shown from the status and tree views")
+	}
+	path := source.Selection().File
+	if path == "" {
+		return fmt.Errorf("no file selected")
+	}
+
+	commits, err := vm.client.FileLog(path, fileLogCount)
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("no commits touch %s", path)
+	}
+	vm.fileLog = &fileLog{path: path, commits: commits}
+	return nil
+}
+
+// answerFileLog moves through the file log popup, Enter opening the full
+// history of the file at the selected commit and Escape closing it
+func (vm *ViewManager) answerFileLog(key tcell.Key, ch rune) {
+	popup := vm.fileLog
+	switch {
+	case key == tcell.KeyDown || (key == tcell.KeyRune && ch == 'j'):
+		popup.selected = min(popup.selected+1, len(popup.commits)-1)
+	case key == tcell.KeyUp || (key == tcell.KeyRune && ch == 'k'):
+		popup.selected = max(popup.selected-1, 0)
+	case key == tcell.KeyEnter:
+		vm.fileLog = nil
+		if err := vm.showLog(popup.path, popup.commits[popup.selected].Hash); err != nil {
+			vm.setMessage("%v", err)
+		}
+	case key == tcell.KeyEscape || (key == tcell.KeyRune && (ch == 'q' || ch == 'H')):
+		vm.fileLog = nil
+	}
+}
+
+// showLog switches to the main view, limited to the commits touching path
+// unless it is empty, and selects the given commit when it is listed
+func (vm *ViewManager) showLog(path, hash string) error {
+	mainView, ok := vm.views[ViewTypeMain].(*MainView)
+	if !ok {
+		return fmt.Errorf("main view not found")
+	}
+	if path != mainView.path {
+		mainView.SetPath(path)
+		if err := mainView.Refresh(); err != nil {
+			return err
+		}
+	}
+	if hash != "" {
+		mainView.selectCommit(hash)
+	}
+	return vm.switchView(ViewTypeMain)
+}
+
+// drawFileLog draws the file log popup over the current view
+func (vm *ViewManager) drawFileLog() {
+	popup := vm.fileLog
+	boxWidth := min(80, vm.width-4)
+	boxHeight := min(len(popup.commits)+4, vm.height-2)
+	if boxWidth < 20 || boxHeight < 5 {
+		return
+	}
+	x := (vm.width - boxWidth) / 2
+	y := max(1, (vm.height-boxHeight)/2)
+	drawPopupBox(vm.screen, "Log of "+popup.path, tcell.StyleDefault.Foreground(tcell.ColorYellow), x, y, boxWidth, boxHeight)
+
+	// The list scrolls to keep the selected commit in the box
+	innerX, innerWidth := x+2, boxWidth-4
+	rows := boxHeight - 4
+	start := max(0, popup.selected-rows+1)
+	now := time.Now()
+	for i := start; i < len(popup.commits) && i < start+rows; i++ {
+		commit := popup.commits[i]
+		style := tcell.StyleDefault
+		if i == popup.selected {
+			style = style.Background(tcell.ColorBlue).Foreground(tcell.ColorWhite)
+		}
+
+		cells := textCells(vm.client.AbbrevHash(commit.Hash)+" ", style.Foreground(tcell.ColorYellow))
+		cells = append(cells, textCells(fmt.Sprintf("%-15s ", relativeDate(commit.Author.Time, now)), style)...)
+		cells = append(cells, textCells(commit.Summary, style)...)
+		for cellsWidth(cells) < innerWidth {
+			cells = append(cells, cell{' ', style})
+		}
+		drawCells(vm.screen, innerX, y+1+i-start, innerWidth, cells, 0, style)
+	}
+
+	hint := "Enter: full history  Esc: close"
+	drawCells(vm.screen, innerX, y+boxHeight-2, innerWidth, textCells(hint, tcell.StyleDefault.Dim(true)), 0, tcell.StyleDefault)
+}
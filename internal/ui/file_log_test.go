@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewManagerFileLog(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	refsTestGit(t, dir, "init", "--quiet", "--initial-branch=main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("one\n"), 0644))
+	refsTestGit(t, dir, "add", "file.txt")
+	refsTestGit(t, dir, "commit", "--quiet", "-m", "add file")
+	first := refsTestGit(t, dir, "rev-parse", "HEAD")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.txt"), []byte("other\n"), 0644))
+	refsTestGit(t, dir, "add", "other.txt")
+	refsTestGit(t, dir, "commit", "--quiet", "-m", "add other")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("two\n"), 0644))
+	refsTestGit(t, dir, "commit", "--quiet", "-am", "change file")
+
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+	vm.SetSize(100, 30)
+	vm.SetRepoPath(dir)
+	treeView := vm.views[ViewTypeTree].(*TreeView)
+	require.NoError(t, vm.SwitchView(ViewTypeTree))
+	require.Equal(t, "file.txt", treeView.Selection().File)
+
+	// The popup lists the commits of the file over the tree view
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'H', 0))
+	require.NoError(t, vm.Render())
+	screen.Show()
+	text := strings.Join(screenLines(screen), "\n")
+	assert.Contains(t, text, "Log of file.txt")
+	assert.Contains(t, text, "change file")
+	assert.Contains(t, text, "add file")
+	assert.NotContains(t, text, "add other")
+	assert.Equal(t, ViewTypeTree, vm.GetCurrentView())
+
+	// Enter opens the history of the file at the selected commit
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'j', 0))
+	assert.True(t, vm.HandleKey(tcell.KeyEnter, 0, 0))
+	assert.Equal(t, ViewTypeMain, vm.GetCurrentView())
+	mainView := vm.views[ViewTypeMain].(*MainView)
+	assert.Len(t, mainView.commits, 2)
+	assert.Equal(t, first, mainView.GetSelectedCommit().Hash)
+	revRange, paths, err := mainView.DiffRange()
+	require.NoError(t, err)
+	assert.Equal(t, first+"^!", revRange)
+	assert.Equal(t, []string{"file.txt"}, paths)
+
+	// The log view shows every commit again
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'l', 0))
+	assert.Empty(t, mainView.path)
+
+	// Escape closes the popup, and files without commits have none
+	require.NoError(t, vm.SwitchView(ViewTypeTree))
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'H', 0))
+	assert.True(t, vm.HandleKey(tcell.KeyEscape, 0, 0))
+	assert.Equal(t, ViewTypeTree, vm.GetCurrentView())
+	assert.Nil(t, vm.fileLog)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new\n"), 0644))
+	statusView := vm.views[ViewTypeStatus].(*StatusView)
+	statusView.status = &git.Status{Untracked: []git.FileStatus{{Path: "new.txt", Y: "?"}}}
+	require.NoError(t, vm.SwitchView(ViewTypeStatus))
+	require.Equal(t, "new.txt", statusView.Selection().File)
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'H', 0))
+	assert.Equal(t, "no commits touch new.txt", vm.GetMessage())
+}
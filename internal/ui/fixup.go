@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"fmt"
+)
+
+// createFixup commits the staged changes as a fixup! commit for the commit
+// selected in the main view
+func (vm *ViewManager) createFixup() error {
+	if !vm.client.IsRepository() {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	commit := vm.getSelectedCommit()
+	if commit == nil {
+		return fmt.Errorf("no commit selected")
+	}
+
+	hash, err := vm.client.CommitFixup(commit.Hash)
+	if err != nil {
+		return err
+	}
+
+	vm.setMessage("Created fixup %s for %s, run :autosquash to squash it", hash, vm.client.AbbrevHash(commit.Hash))
+	return vm.refreshAll()
+}
+
+// AutosquashCommand handles the :autosquash command, which squashes the
+// pending fixup commits into their targets
+func (vm *ViewManager) AutosquashCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if len(args) > 0 {
+		return fmt.Errorf("usage: autosquash")
+	}
+	if !vm.client.IsRepository() {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	base, err := vm.client.Autosquash()
+	if err != nil {
+		return err
+	}
+
+	vm.setMessage("Squashed fixup commits onto %s", base)
+	return vm.refreshAll()
+}
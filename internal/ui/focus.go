@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/azhao1981/tig/internal/git"
+)
+
+// focus is where the focus of a view was: the file selected and, in the
+// diff of a file, the hunk under the cursor
+type focus struct {
+	path    string
+	section string // Section of the status view listing the file
+	hunk    int    // -1 when the cursor was not on a hunk
+}
+
+// focusMemory is the registry of where the focus of each view was, keyed by
+// the item the view showed, "" standing for the view as a whole. Going back
+// to a view, or opening the same item again, resumes where it was.
+type focusMemory map[ViewType]map[string]focus
+
+// remember records the focus of a view on an item
+func (m focusMemory) remember(view ViewType, key string, f focus) {
+	if m[view] == nil {
+		m[view] = make(map[string]focus)
+	}
+	m[view][key] = f
+}
+
+// recall returns the focus a view had on an item
+func (m focusMemory) recall(view ViewType, key string) (focus, bool) {
+	f, ok := m[view][key]
+	return f, ok
+}
+
+// take returns the focus a view had on an item and forgets it, for focus
+// to restore only once
+func (m focusMemory) take(view ViewType, key string) (focus, bool) {
+	f, ok := m.recall(view, key)
+	delete(m[view], key)
+	return f, ok
+}
+
+// stageFocusKey is the key of the focus in the diff of a status file, the
+// same file being shown apart on either side of the index
+func stageFocusKey(path, section string) string {
+	return section + ":" + path
+}
+
+// rememberStageFocus records the hunk under the cursor of the diff of a
+// status file, for the diff to resume there when the file is opened again,
+// and the file itself, for the status view to select it again
+func (vm *ViewManager) rememberStageFocus() {
+	diffView, ok := vm.views[ViewTypeDiff].(*DiffView)
+	if !ok || diffView.stage == nil {
+		return
+	}
+	path, section := diffView.stage.path, diffView.stage.section
+	f := focus{path: path, section: section, hunk: diffView.hunkIndex()}
+	vm.focus.remember(ViewTypeDiff, stageFocusKey(path, section), f)
+	vm.focus.remember(ViewTypeStatus, "", f)
+}
+
+// restoreStatusFocus selects in the status view the file whose diff was
+// left last
+func (vm *ViewManager) restoreStatusFocus() {
+	statusView, ok := vm.views[ViewTypeStatus].(*StatusView)
+	if !ok {
+		return
+	}
+	if f, ok := vm.focus.take(ViewTypeStatus, ""); ok {
+		statusView.selectFile(f.path, f.section)
+	}
+}
+
+// showStageFile shows the changes of a status file in the diff view,
+// resuming at the hunk the diff was left at the last time
+func (vm *ViewManager) showStageFile(path, section string) error {
+	diffView, ok := vm.views[ViewTypeDiff].(*DiffView)
+	if !ok {
+		return fmt.Errorf("diff view not found")
+	}
+	if vm.currentView == ViewTypeDiff {
+		vm.rememberStageFocus()
+	}
+	if err := diffView.SetStageFile(path, section); err != nil {
+		return err
+	}
+	if f, ok := vm.focus.recall(ViewTypeDiff, stageFocusKey(path, section)); ok {
+		diffView.scrollToHunk(f.hunk)
+	}
+	return vm.switchView(ViewTypeDiff)
+}
+
+// selectFile selects the line of a file, in the given section if it is
+// listed there, and scrolls it into view
+func (v *StatusView) selectFile(path, section string) bool {
+	if v.status == nil {
+		return false
+	}
+	lines, sections := v.buildStatusContent()
+	found := -1
+	for i := range lines {
+		file, fileSection := v.entryAt(lines, sections, i)
+		if file == nil || file.Path != path {
+			continue
+		}
+		if fileSection == section {
+			found = i
+			break
+		}
+		if found < 0 {
+			found = i
+		}
+	}
+	if found < 0 {
+		return false
+	}
+	v.selected = found
+	v.scrollToSelection()
+	return true
+}
+
+// hunkIndex returns the index of the hunk under the cursor within its
+// file, -1 when the cursor is elsewhere
+func (v *DiffView) hunkIndex() int {
+	offset := v.GetOffset()
+	if offset >= len(v.rows) || v.rows[offset].file == nil {
+		return -1
+	}
+	return v.rows[offset].hunk
+}
+
+// scrollToHunk scrolls the first hunk of the given index to the top, the
+// last hunk of the file when it has fewer
+func (v *DiffView) scrollToHunk(index int) {
+	if index < 0 {
+		return
+	}
+	var file *git.DiffFile
+	target := -1
+	for i, row := range v.rows {
+		if row.file == nil || row.hunk < 0 || (file != nil && row.file != file) {
+			continue
+		}
+		file = row.file
+		if row.hunk > index {
+			break
+		}
+		if target < 0 || v.rows[target].hunk != row.hunk {
+			target = i
+		}
+	}
+	if target >= 0 {
+		v.SetOffset(target)
+	}
+}
@@ -0,0 +1,75 @@
+package ui
+
+// foldAction is a folding command entered with a vim style z prefix
+type foldAction int
+
+const (
+	foldNone foldAction = iota
+	foldToggle
+	foldOpen
+	foldClose
+	foldOpenAll
+	foldCloseAll
+)
+
+// foldPrefix tracks the z prefix of two key folding commands
+type foldPrefix struct {
+	pending bool
+}
+
+// handle feeds a typed rune to the prefix. It reports whether the rune was
+// consumed and which folding command, if any, was completed.
+func (p *foldPrefix) handle(ch rune) (foldAction, bool) {
+	if !p.pending {
+		if ch == 'z' {
+			p.pending = true
+			return foldNone, true
+		}
+		return foldNone, false
+	}
+
+	p.pending = false
+	switch ch {
+	case 'a':
+		return foldToggle, true
+	case 'o':
+		return foldOpen, true
+	case 'c':
+		return foldClose, true
+	case 'R':
+		return foldOpenAll, true
+	case 'M':
+		return foldCloseAll, true
+	}
+	return foldNone, true
+}
+
+// reset cancels a pending prefix
+func (p *foldPrefix) reset() {
+	p.pending = false
+}
+
+// foldSet remembers which sections are folded by a stable key, so the fold
+// state survives rebuilding the content on refresh
+type foldSet map[string]bool
+
+// apply runs a folding command on the section under the cursor, or on all
+// sections for the global commands
+func (f foldSet) apply(action foldAction, key string, all []string) {
+	switch action {
+	case foldToggle:
+		f[key] = !f[key]
+	case foldOpen:
+		f[key] = false
+	case foldClose:
+		f[key] = true
+	case foldOpenAll:
+		for k := range f {
+			delete(f, k)
+		}
+	case foldCloseAll:
+		for _, k := range all {
+			f[k] = true
+		}
+	}
+}
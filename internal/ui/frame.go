@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/gdamore/tcell/v2"
+)
+
+// Frame draws the chrome every view is drawn in: the title on the top row,
+// the status line on the bottom row and a scrollbar on the right edge while
+// the content doesn't fit. With borders, which the borderless option turns
+// off, the rows and the scrollbar are part of a box around the content.
+type Frame struct {
+	Title  string
+	Status string
+	Style  tcell.Style
+	config *config.Config
+
+	// Area of the content as of the last draw
+	contentX, contentY, contentWidth, contentHeight int
+}
+
+// NewFrame creates a new frame with the given title
+func NewFrame(config *config.Config, title string) *Frame {
+	return &Frame{
+		Title:  title,
+		Style:  tcell.StyleDefault.Foreground(tcell.ColorWhite),
+		config: config,
+	}
+}
+
+// borderless returns whether the frame is drawn without borders
+func (f *Frame) borderless() bool {
+	return f.config != nil && f.config.UI.Borderless
+}
+
+// Content returns the area left for the content of a view of the given
+// size. It is the same height with or without borders, so views can count
+// on the frame taking two rows.
+func (f *Frame) Content(x, y, width, height int) (int, int, int, int) {
+	if f.borderless() {
+		// The right column is kept for the scrollbar
+		return x, y + 1, width - 1, height - 2
+	}
+	return x + 1, y + 1, width - 2, height - 2
+}
+
+// Draw draws the frame of a view of the given size and returns the area
+// left for its content
+func (f *Frame) Draw(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
+	f.contentX, f.contentY, f.contentWidth, f.contentHeight = f.Content(x, y, width, height)
+	if width <= 0 || height <= 0 {
+		return f.contentX, f.contentY, f.contentWidth, f.contentHeight
+	}
+
+	if f.borderless() {
+		// The title is set apart as a bar, the status line is dimmed
+		titleStyle := f.Style.Reverse(true).Bold(true)
+		drawCells(screen, x, y, width, padCells(textCells(" "+f.Title, titleStyle), width, titleStyle), 0, titleStyle)
+		if height > 1 {
+			statusStyle := f.Style.Dim(true)
+			drawCells(screen, x, y+height-1, width, padCells(textCells(f.Status, statusStyle), width, statusStyle), 0, statusStyle)
+		}
+		return f.contentX, f.contentY, f.contentWidth, f.contentHeight
+	}
+
+	for i := 0; i < width; i++ {
+		screen.SetContent(x+i, y, tcell.RuneHLine, nil, f.Style)
+		if height > 1 {
+			screen.SetContent(x+i, y+height-1, tcell.RuneHLine, nil, f.Style)
+		}
+	}
+	for i := 0; i < height; i++ {
+		screen.SetContent(x, y+i, tcell.RuneVLine, nil, f.Style)
+		if width > 1 {
+			screen.SetContent(x+width-1, y+i, tcell.RuneVLine, nil, f.Style)
+		}
+	}
+	screen.SetContent(x, y, tcell.RuneULCorner, nil, f.Style)
+	screen.SetContent(x+width-1, y, tcell.RuneURCorner, nil, f.Style)
+	if height > 1 {
+		screen.SetContent(x, y+height-1, tcell.RuneLLCorner, nil, f.Style)
+		screen.SetContent(x+width-1, y+height-1, tcell.RuneLRCorner, nil, f.Style)
+	}
+
+	// The title is centered in the top border, the status line starts the
+	// bottom one
+	if f.Title != "" && width > 2 {
+		title := textCells(f.Title, f.Style)
+		titleWidth := min(cellsWidth(title), width-2)
+		drawCells(screen, x+(width-titleWidth)/2, y, titleWidth, title, 0, f.Style)
+	}
+	if f.Status != "" && height > 1 && width > 4 {
+		status := textCells(" "+f.Status+" ", f.Style)
+		drawCells(screen, x+2, y+height-1, min(cellsWidth(status), width-4), status, 0, f.Style)
+	}
+	return f.contentX, f.contentY, f.contentWidth, f.contentHeight
+}
+
+// DrawScrollbar draws the position of a scrolled view next to the content
+// drawn last, nothing when all of it fits
+func (f *Frame) DrawScrollbar(screen tcell.Screen, scroll *Scrollable) {
+	track := f.contentHeight
+	if scroll == nil || scroll.maxOffset <= 0 || track <= 0 || f.contentWidth < 0 {
+		return
+	}
+
+	total := scroll.maxOffset + track
+	thumb := max(1, track*track/total)
+	top := scroll.offset * (track - thumb) / scroll.maxOffset
+
+	column := f.contentX + f.contentWidth
+	for i := 0; i < track; i++ {
+		char, style := tcell.RuneVLine, f.Style.Dim(true)
+		if i >= top && i < top+thumb {
+			char, style = '█', f.Style
+		}
+		screen.SetContent(column, f.contentY+i, char, nil, style)
+	}
+}
+
+// padCells pads cells with blanks up to the given width
+func padCells(cells []cell, width int, style tcell.Style) []cell {
+	for cellsWidth(cells) < width {
+		cells = append(cells, cell{' ', style})
+	}
+	return cells
+}
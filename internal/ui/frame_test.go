@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameDraw(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	screen.SetSize(20, 5)
+
+	cfg := &config.Config{}
+	frame := NewFrame(cfg, "Log")
+	frame.Status = "ok"
+
+	x, y, width, height := frame.Draw(screen, 0, 0, 20, 5)
+	assert.Equal(t, []int{1, 1, 18, 3}, []int{x, y, width, height})
+	lines := screenLines(screen)
+	assert.Equal(t, "┌───────Log────────┐", lines[0])
+	assert.Equal(t, "│                  │", lines[1])
+	assert.Equal(t, "└─ ok ─────────────┘", lines[4])
+
+	// Without borders the content is as high, and the scrollbar keeps the
+	// right column
+	cfg.UI.Borderless = true
+	screen.Clear()
+	x, y, width, height = frame.Draw(screen, 0, 0, 20, 5)
+	assert.Equal(t, []int{0, 1, 19, 3}, []int{x, y, width, height})
+	lines = screenLines(screen)
+	assert.Equal(t, " Log", lines[0])
+	assert.Equal(t, "", lines[1])
+	assert.Equal(t, "ok", lines[4])
+}
+
+func TestFrameScrollbar(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	screen.SetSize(10, 6)
+
+	frame := NewFrame(&config.Config{}, "")
+	scroll := NewScrollable()
+
+	// Nothing is drawn while everything fits
+	frame.Draw(screen, 0, 0, 10, 6)
+	frame.DrawScrollbar(screen, scroll)
+	for _, line := range screenLines(screen)[1:5] {
+		assert.Equal(t, "│        │", line)
+	}
+
+	// Four rows showing half of eight lines, scrolled to the end
+	scroll.SetMaxOffset(4)
+	scroll.SetOffset(4)
+	frame.DrawScrollbar(screen, scroll)
+	lines := screenLines(screen)
+	assert.Equal(t, "│        │", lines[1])
+	assert.Equal(t, "│        │", lines[2])
+	assert.Equal(t, "│        █", lines[3])
+	assert.Equal(t, "│        █", lines[4])
+}
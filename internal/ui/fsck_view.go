@@ -0,0 +1,204 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// FsckView shows what git fsck says about the repository as it checks it
+type FsckView struct {
+	*BaseView
+	*Scrollable
+	config   *config.Config
+	client   git.Client
+	lines    []string
+	result   *git.FsckResult
+	err      error
+	check    *job // Running git fsck for the lines shown
+	repoPath string
+	frame    *Frame
+}
+
+// NewFsckView creates a new fsck view
+func NewFsckView(config *config.Config, client git.Client) *FsckView {
+	return &FsckView{
+		BaseView:   NewBaseView(ViewTypeFsck),
+		Scrollable: NewScrollable(),
+		config:     config,
+		client:     client,
+		frame:      NewFrame(config, "fsck"),
+	}
+}
+
+// checking returns whether git fsck is running
+func (v *FsckView) checking() bool {
+	return v.check != nil && v.check.running()
+}
+
+// title sums up what git fsck found so far
+func (v *FsckView) title() string {
+	switch {
+	case v.checking():
+		return fmt.Sprintf("fsck: checking, %d lines", len(v.lines))
+	case v.err != nil || v.result == nil:
+		return "fsck"
+	case v.result.Broken > 0:
+		return fmt.Sprintf("fsck: %d broken, %d dangling", v.result.Broken, v.result.Dangling)
+	case v.result.Dangling > 0:
+		return fmt.Sprintf("fsck: %d dangling", v.result.Dangling)
+	}
+	return "fsck: no problems"
+}
+
+// Render renders the fsck view
+func (v *FsckView) Render(screen tcell.Screen, x, y, width, height int) error {
+	v.SetPosition(x, y, width, height)
+	v.SetHeight(height - 2) // Account for borders
+
+	v.frame.Title = v.title()
+	contentX, contentY, contentWidth, contentHeight := v.frame.Draw(screen, x, y, width, height)
+	defer v.frame.DrawScrollbar(screen, v.Scrollable)
+
+	if contentWidth <= 0 || contentHeight <= 0 {
+		return nil
+	}
+
+	if len(v.lines) == 0 {
+		msg := "The repository is fine"
+		switch {
+		case v.checking():
+			msg = "Checking objects..."
+		case v.check != nil && v.check.cancelled:
+			msg = "Cancelled"
+		case v.err != nil:
+			msg = v.err.Error()
+		case v.result == nil:
+			msg = "Run :fsck to check the repository"
+		}
+		msgX := contentX + (contentWidth-len(msg))/2
+		msgY := contentY + contentHeight/2
+		drawCells(screen, max(contentX, msgX), msgY, contentWidth, textCells(msg, tcell.StyleDefault.Dim(true)), 0, tcell.StyleDefault)
+		return nil
+	}
+
+	v.SetMaxOffset(len(v.lines) - contentHeight)
+
+	start := v.GetOffset()
+	end := min(start+contentHeight, len(v.lines))
+	for i := start; i < end; i++ {
+		style := tcell.StyleDefault
+		switch dangling, broken := git.FsckProblem(v.lines[i]); {
+		case broken:
+			style = style.Foreground(tcell.ColorRed)
+		case dangling:
+			style = style.Foreground(tcell.ColorYellow)
+		}
+		drawCells(screen, contentX, contentY+(i-start), contentWidth, textCells(v.lines[i], style), 0, tcell.StyleDefault)
+	}
+
+	return nil
+}
+
+// HandleKey handles keyboard input
+func (v *FsckView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
+	if !v.IsFocused() {
+		return false
+	}
+
+	switch key {
+	case tcell.KeyUp:
+		v.ScrollUp()
+		return true
+	case tcell.KeyDown:
+		v.ScrollDown()
+		return true
+	case tcell.KeyHome:
+		v.ScrollToTop()
+		return true
+	case tcell.KeyEnd:
+		v.ScrollToBottom()
+		return true
+	}
+
+	switch ch {
+	case 'j':
+		v.ScrollDown()
+		return true
+	case 'k':
+		v.ScrollUp()
+		return true
+	case 'g':
+		v.ScrollToTop()
+		return true
+	case 'G':
+		v.ScrollToBottom()
+		return true
+	}
+
+	return false
+}
+
+// Refresh keeps the lines of the last check, until :fsck is run again
+func (v *FsckView) Refresh() error {
+	return nil
+}
+
+// PagerArgs returns the git arguments running the check again
+func (v *FsckView) PagerArgs() ([]string, error) {
+	return []string{"fsck", "--no-progress", "--name-objects"}, nil
+}
+
+// SetRepoPath sets the repository path
+func (v *FsckView) SetRepoPath(path string) {
+	v.repoPath = path
+	v.lines, v.result, v.err, v.check = nil, nil, nil, nil
+}
+
+// FsckCommand handles the :fsck command, which checks the integrity of the
+// repository in the background, showing what git fsck says as it goes
+func (vm *ViewManager) FsckCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	view, ok := vm.views[ViewTypeFsck].(*FsckView)
+	if !ok {
+		return fmt.Errorf("fsck view not found")
+	}
+	if view.checking() {
+		return vm.switchView(ViewTypeFsck)
+	}
+
+	view.lines, view.result, view.err = nil, nil, nil
+	view.ScrollToTop()
+	view.check = &job{kind: jobMaintenance, name: "git fsck", quiet: true}
+	check := view.check
+	var result *git.FsckResult
+	vm.startJob(check, func(ctx context.Context, progress func(string)) error {
+		var err error
+		result, err = vm.client.Fsck(ctx, func(line string) {
+			// Lines may be dropped while the event queue is full, all of
+			// them are shown once done
+			vm.postJobEvent(false, func() {
+				if view.check == check {
+					view.lines = append(view.lines, line)
+				}
+			})
+		})
+		return err
+	}, func(err error) string {
+		if view.check != check {
+			return ""
+		}
+		view.result, view.err = result, err
+		if err != nil {
+			return err.Error()
+		}
+		view.lines = result.Lines
+		return vm.fsckChecked(result)
+	})
+	return vm.switchView(ViewTypeFsck)
+}
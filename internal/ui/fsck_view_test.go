@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runFsck runs :fsck to its end
+func runFsck(t *testing.T, vm *ViewManager) *FsckView {
+	t.Helper()
+	require.NoError(t, vm.FsckCommand(nil))
+	assert.Equal(t, ViewTypeFsck, vm.GetCurrentView())
+	view := vm.views[ViewTypeFsck].(*FsckView)
+	for view.checking() {
+		runJobEvent(t, vm.screen)
+	}
+	return view
+}
+
+func TestFsckView(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+
+	view := runFsck(t, vm)
+	require.NoError(t, view.err)
+	assert.Equal(t, "git fsck: no problems", vm.GetMessage())
+	assert.Nil(t, vm.integrity)
+	require.NoError(t, vm.Render())
+	assert.Contains(t, screenLines(vm.screen)[1], "fsck: no problems")
+
+	// Losing an object raises the banner until a check finds nothing wrong
+	file := strings.TrimSpace(refsTestGit(t, dir, "rev-parse", "topic:file.txt"))
+	object := filepath.Join(dir, ".git", "objects", file[:2], file[2:])
+	data, err := os.ReadFile(object)
+	require.NoError(t, err)
+	require.NoError(t, os.Chmod(object, 0644))
+	require.NoError(t, os.Remove(object))
+
+	view = runFsck(t, vm)
+	require.NoError(t, view.err)
+	assert.Positive(t, view.result.Broken)
+	assert.Contains(t, view.lines, "missing blob "+file+" (refs/heads/topic:file.txt)")
+	require.NotNil(t, vm.integrity)
+	assert.True(t, vm.integrity.broken)
+	require.NoError(t, vm.Render())
+	lines := screenLines(vm.screen)
+	assert.Contains(t, lines[1], "git fsck found 2 broken objects or links")
+	assert.Contains(t, lines[2], "fsck: 2 broken, 0 dangling")
+
+	require.NoError(t, os.WriteFile(object, data, 0444))
+	runFsck(t, vm)
+	assert.Nil(t, vm.integrity)
+}
+
+func TestIntegrityBannerFromErrors(t *testing.T) {
+	_, vm := autostashTestRepo(t)
+
+	vm.SetMessage("%v", errors.New("git push: rejected"))
+	assert.Nil(t, vm.integrity)
+	vm.SetMessage("%v", errors.New("git log: fatal: bad object HEAD"))
+	require.NotNil(t, vm.integrity)
+	require.NoError(t, vm.Render())
+	assert.Contains(t, screenLines(vm.screen)[1], "The repository may be corrupt, run :fsck to check it")
+
+	// Another repository has problems of its own
+	vm.SetRepoPath(t.TempDir())
+	assert.Nil(t, vm.integrity)
+}
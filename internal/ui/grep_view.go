@@ -0,0 +1,348 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// grepContext is the number of lines shown around each match
+const grepContext = 2
+
+// grepRow is a row of the grep view: the header of a file, a line found,
+// or the separator of two groups of lines of a file when line is nil and
+// path empty
+type grepRow struct {
+	path string
+	line *git.GrepLine
+}
+
+// GrepView lists the lines of the tracked files matching the last :grep,
+// with the lines around them, grouped by file
+type GrepView struct {
+	*BaseView
+	*Scrollable
+	config   *config.Config
+	client   git.Client
+	pattern  string
+	rows     []grepRow
+	matches  int
+	files    int
+	selected int
+	repoPath string
+	frame    *Frame
+}
+
+// NewGrepView creates a new grep results view
+func NewGrepView(config *config.Config, client git.Client) *GrepView {
+	return &GrepView{
+		BaseView:   NewBaseView(ViewTypeGrep),
+		Scrollable: NewScrollable(),
+		config:     config,
+		client:     client,
+		frame:      NewFrame(config, "Grep"),
+	}
+}
+
+// grepRows groups the lines found by file, separating the lines of a file
+// which don't follow each other
+func grepRows(lines []*git.GrepLine) (rows []grepRow, matches, files int) {
+	var previous *git.GrepLine
+	for _, line := range lines {
+		switch {
+		case previous == nil || previous.Path != line.Path:
+			rows = append(rows, grepRow{path: line.Path})
+			files++
+		case previous.Line+1 != line.Line:
+			rows = append(rows, grepRow{})
+		}
+		rows = append(rows, grepRow{path: line.Path, line: line})
+		if line.IsMatch() {
+			matches++
+		}
+		previous = line
+	}
+	return rows, matches, files
+}
+
+// Render renders the grep view
+func (v *GrepView) Render(screen tcell.Screen, x, y, width, height int) error {
+	v.SetPosition(x, y, width, height)
+	v.SetHeight(height - 2) // Account for borders
+
+	contentX, contentY, contentWidth, contentHeight := v.frame.Draw(screen, x, y, width, height)
+	defer v.frame.DrawScrollbar(screen, v.Scrollable)
+
+	if contentWidth <= 0 || contentHeight <= 0 {
+		return nil
+	}
+
+	if len(v.rows) == 0 {
+		msg := "No grep, use :grep <pattern> to search the files"
+		if v.pattern != "" {
+			msg = fmt.Sprintf("No lines match %q", v.pattern)
+		}
+		msgX := contentX + (contentWidth-len(msg))/2
+		msgY := contentY + contentHeight/2
+		drawCells(screen, max(contentX, msgX), msgY, contentWidth, textCells(msg, tcell.StyleDefault.Dim(true)), 0, tcell.StyleDefault)
+		return nil
+	}
+
+	v.SetMaxOffset(len(v.rows) - contentHeight)
+
+	start := v.GetOffset()
+	end := min(start+contentHeight, len(v.rows))
+	for i := start; i < end; i++ {
+		v.drawRow(screen, contentX, contentY+(i-start), contentWidth, i)
+	}
+	return nil
+}
+
+// drawRow draws a file header, a separator, or a line as its number and
+// text, the lines around the matches being dimmed
+func (v *GrepView) drawRow(screen tcell.Screen, x, y, width, index int) {
+	row := v.rows[index]
+
+	switch {
+	case row.line == nil && row.path != "":
+		style := tcell.StyleDefault.Foreground(tcell.ColorTeal).Bold(true)
+		drawCells(screen, x, y, width, textCells(row.path, style), 0, style)
+		return
+	case row.line == nil:
+		style := tcell.StyleDefault.Dim(true)
+		drawCells(screen, x, y, width, textCells("  --", style), 0, style)
+		return
+	}
+
+	numberStyle := tcell.StyleDefault.Foreground(tcell.ColorYellow)
+	textStyle := tcell.StyleDefault
+	if !row.line.IsMatch() {
+		numberStyle = tcell.StyleDefault.Dim(true)
+		textStyle = tcell.StyleDefault.Dim(true)
+	}
+	if index == v.selected {
+		background := tcell.ColorDarkBlue
+		if v.IsFocused() {
+			background = tcell.ColorBlue
+		}
+		numberStyle = numberStyle.Background(background)
+		textStyle = textStyle.Background(background)
+	}
+
+	cells := textCells(fmt.Sprintf("%6d ", row.line.Line), numberStyle)
+	cells = append(cells, newWhitespaceMarks(v.config).cells(row.line.Text, textStyle, -1)...)
+	if index == v.selected {
+		for cellsWidth(cells) < width {
+			cells = append(cells, cell{' ', textStyle})
+		}
+	}
+	drawCells(screen, x, y, width, cells, 0, textStyle)
+}
+
+// HandleKey handles keyboard input
+func (v *GrepView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
+	if !v.IsFocused() {
+		return false
+	}
+
+	switch key {
+	case tcell.KeyUp:
+		v.move(-1)
+		return true
+	case tcell.KeyDown:
+		v.move(1)
+		return true
+	case tcell.KeyHome:
+		v.moveTop()
+		return true
+	case tcell.KeyEnd:
+		v.moveBottom()
+		return true
+	}
+
+	switch ch {
+	case 'j':
+		v.move(1)
+		return true
+	case 'k':
+		v.move(-1)
+		return true
+	case 'g':
+		v.moveTop()
+		return true
+	case 'G':
+		v.moveBottom()
+		return true
+	}
+
+	return false
+}
+
+// move selects the next line in a direction, skipping file headers and
+// separators
+func (v *GrepView) move(direction int) {
+	for i := v.selected + direction; i >= 0 && i < len(v.rows); i += direction {
+		if v.rows[i].line != nil {
+			v.selected = i
+			break
+		}
+	}
+	v.scrollToSelected()
+}
+
+// moveTop selects the first line, showing the header of its file
+func (v *GrepView) moveTop() {
+	v.selected = 0
+	v.ScrollToTop()
+	v.move(1)
+}
+
+// moveBottom selects the last line
+func (v *GrepView) moveBottom() {
+	v.selected = len(v.rows)
+	v.move(-1)
+}
+
+// scrollToSelected scrolls the selected line into view
+func (v *GrepView) scrollToSelected() {
+	pageSize := v.getPageSize()
+	switch {
+	case v.selected < v.GetOffset():
+		v.SetOffset(v.selected)
+	case pageSize > 0 && v.selected >= v.GetOffset()+pageSize:
+		v.SetOffset(v.selected - pageSize + 1)
+	}
+}
+
+// getPageSize returns the number of visible lines
+func (v *GrepView) getPageSize() int {
+	_, _, _, height := v.GetPosition()
+	return height - 2 // Account for borders
+}
+
+// Refresh keeps the results, which stay until the next search
+func (v *GrepView) Refresh() error {
+	return nil
+}
+
+// setResults replaces the results with those of a new search, selecting
+// the first match
+func (v *GrepView) setResults(pattern string, lines []*git.GrepLine) {
+	v.pattern = pattern
+	v.rows, v.matches, v.files = grepRows(lines)
+	v.selected = 0
+	v.ScrollToTop()
+	for i, row := range v.rows {
+		if row.line != nil && row.line.IsMatch() {
+			v.selected = i
+			break
+		}
+	}
+	v.frame.Title = "Grep"
+	if pattern != "" {
+		v.frame.Title = fmt.Sprintf("Grep %q: %d matches in %d files", pattern, v.matches, v.files)
+	}
+}
+
+// GetSelectedLine returns the selected line
+func (v *GrepView) GetSelectedLine() *git.GrepLine {
+	if v.selected < 0 || v.selected >= len(v.rows) {
+		return nil
+	}
+	return v.rows[v.selected].line
+}
+
+// EditorTarget returns the file and line of the selected line
+func (v *GrepView) EditorTarget() (string, int, error) {
+	line := v.GetSelectedLine()
+	if line == nil {
+		return "", 0, fmt.Errorf("no line selected")
+	}
+	return line.Path, line.Line, nil
+}
+
+// Selection returns the selected file and line for placeholder expansion
+func (v *GrepView) Selection() Selection {
+	var sel Selection
+	if line := v.GetSelectedLine(); line != nil {
+		sel.File = line.Path
+		sel.Lineno = line.Line
+	}
+	return sel
+}
+
+// SetRepoPath sets the repository path
+func (v *GrepView) SetRepoPath(path string) {
+	v.repoPath = path
+	v.setResults("", nil)
+}
+
+// GrepCommand handles the :grep command, searching the tracked files of
+// the worktree. Without a pattern it shows the results of the last search
+// again.
+func (vm *ViewManager) GrepCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	view, ok := vm.views[ViewTypeGrep].(*GrepView)
+	if !ok {
+		return fmt.Errorf("grep view not found")
+	}
+	if len(args) == 0 {
+		if view.pattern == "" {
+			return fmt.Errorf("usage: grep [-i] <pattern> [-- <paths>...]")
+		}
+		return vm.switchView(ViewTypeGrep)
+	}
+
+	opts := &git.GrepOptions{Context: grepContext}
+	if args[0] == "-i" {
+		opts.IgnoreCase = true
+		args = args[1:]
+	}
+	for i, arg := range args {
+		if arg == "--" {
+			args, opts.Paths = args[:i], args[i+1:]
+			break
+		}
+	}
+	pattern := strings.Join(args, " ")
+	if pattern == "" {
+		return fmt.Errorf("usage: grep [-i] <pattern> [-- <paths>...]")
+	}
+
+	lines, err := vm.client.Grep(pattern, opts)
+	if err != nil {
+		return err
+	}
+	view.setResults(pattern, lines)
+	if len(lines) == 0 {
+		vm.setMessage("No lines match %q", pattern)
+		return nil
+	}
+	return vm.switchView(ViewTypeGrep)
+}
+
+// openGrepLine shows the file of the selected line of the grep view at
+// that line
+func (vm *ViewManager) openGrepLine() error {
+	view, ok := vm.views[ViewTypeGrep].(*GrepView)
+	if !ok {
+		return fmt.Errorf("grep view not found")
+	}
+	line := view.GetSelectedLine()
+	if line == nil {
+		return fmt.Errorf("no line selected")
+	}
+	blobView, ok := vm.views[ViewTypeBlob].(*BlobView)
+	if !ok {
+		return fmt.Errorf("blob view not found")
+	}
+	if err := blobView.SetFile("", line.Path, line.Line); err != nil {
+		return err
+	}
+	return vm.switchView(ViewTypeBlob)
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrepView(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	view := vm.views[ViewTypeGrep].(*GrepView)
+	blobView := vm.views[ViewTypeBlob].(*BlobView)
+	var content strings.Builder
+	for _, line := range []string{"1", "2", "3 needle", "4", "5", "6", "7", "8", "9", "10", "11 Needle", "12"} {
+		content.WriteString(line + "\n")
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(content.String()), 0644))
+
+	assert.EqualError(t, vm.GrepCommand(nil), "usage: grep [-i] <pattern> [-- <paths>...]")
+
+	// Nothing matching is reported without leaving the view
+	require.NoError(t, vm.GrepCommand([]string{"missing"}))
+	assert.Equal(t, ViewTypeMain, vm.GetCurrentView())
+	assert.Equal(t, `No lines match "missing"`, vm.GetMessage())
+
+	// Matches come with their context, grouped by file, the first one
+	// selected
+	require.NoError(t, vm.GrepCommand([]string{"-i", "needle", "--", "notes.txt"}))
+	assert.Equal(t, ViewTypeGrep, vm.GetCurrentView())
+	assert.Equal(t, `Grep "needle": 2 matches in 1 files`, view.frame.Title)
+	require.Len(t, view.rows, 11)
+	assert.Equal(t, "notes.txt", view.rows[0].path)
+	assert.Nil(t, view.rows[6].line)
+	assert.Equal(t, 3, view.GetSelectedLine().Line)
+
+	// Separators are skipped
+	for range 3 {
+		vm.HandleKey(tcell.KeyRune, 'j', 0)
+	}
+	assert.Equal(t, 9, view.GetSelectedLine().Line)
+	vm.HandleKey(tcell.KeyRune, 'G', 0)
+	assert.Equal(t, 12, view.GetSelectedLine().Line)
+
+	// Enter shows the file at the line
+	vm.HandleKey(tcell.KeyEnter, 0, 0)
+	assert.Equal(t, ViewTypeBlob, vm.GetCurrentView())
+	path, line, err := blobView.EditorTarget()
+	require.NoError(t, err)
+	assert.Equal(t, "notes.txt", path)
+	assert.Equal(t, 12, line)
+	vm.HandleKey(tcell.KeyRune, 'k', 0)
+	assert.Equal(t, 11, blobView.Selection().Lineno)
+
+	// Files are also shown as of a revision
+	require.NoError(t, blobView.SetFile("topic", "file.txt", 1))
+	assert.Equal(t, []string{"topic"}, blobView.lines)
+	assert.Error(t, blobView.SetFile("topic", "missing.txt", 1))
+
+	// The results are shown again without a pattern
+	require.NoError(t, vm.SwitchView(ViewTypeMain))
+	require.NoError(t, vm.GrepCommand(nil))
+	assert.Equal(t, ViewTypeGrep, vm.GetCurrentView())
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/azhao1981/tig/internal/config"
@@ -20,6 +21,8 @@ type HelpView struct {
 	selected       int
 	repoPath       string
 	screen         tcell.Screen
+	pattern        string // Lowercase text searched for, empty if none
+	frame          *Frame
 }
 
 // HelpSection represents a section in the help view
@@ -44,6 +47,7 @@ func NewHelpView(config *config.Config, client git.Client) *HelpView {
 		client:         client,
 		currentSection: 0,
 		selected:       0,
+		frame:          NewFrame(config, "Go Tig Help"),
 	}
 }
 
@@ -60,6 +64,10 @@ func (v *HelpView) Load() error {
 				{Key: "G", Description: "Go to bottom", Category: "navigation"},
 				{Key: "PgUp", Description: "Page up", Category: "navigation"},
 				{Key: "PgDn", Description: "Page down", Category: "navigation"},
+				{Key: ",", Description: "Go to parent commit", Category: "navigation"},
+				{Key: ":merge-base rev", Description: "Go to merge base with a revision", Category: "navigation"},
+				{Key: "m<letter>", Description: "Mark the commit, file or line under the cursor", Category: "navigation"},
+				{Key: "'<letter>", Description: "Jump back to a mark of the view", Category: "navigation"},
 			},
 		},
 		{
@@ -68,9 +76,22 @@ func (v *HelpView) Load() error {
 				{Key: "l", Description: "Log view (main)", Category: "view"},
 				{Key: "d", Description: "Diff view", Category: "view"},
 				{Key: "s", Description: "Status view", Category: "view"},
-				{Key: "t", Description: "Tree view", Category: "view"},
+				{Key: "t", Description: "Tree view, of the selected commit from the main view", Category: "view"},
 				{Key: "r", Description: "Refs view", Category: "view"},
+				{Key: "J", Description: "Background jobs view", Category: "view"},
 				{Key: "h", Description: "Help view", Category: "view"},
+				{Key: "H", Description: "Last commits of the selected file, Enter for its history (status and tree views)", Category: "view"},
+				{Key: ":diff rev", Description: "Diff of a commit or of a range A..B", Category: "view"},
+				{Key: ":show [commit] rev", Description: "Diff of a commit, as sent by editors through the control-socket", Category: "view"},
+				{Key: ":blame file:line", Description: "Diff of the commit which last changed a line of the work tree", Category: "view"},
+				{Key: ":log rev", Description: "Select a commit in the log", Category: "view"},
+				{Key: ":focus [view]", Description: "Show a view and draw attention, as a tig started with single-instance does", Category: "view"},
+				{Key: ":log-filter author=x", Description: "Filter the log by author, path, since, until and branch", Category: "view"},
+				{Key: ":log-view [name]", Description: "Pick a saved log view, or open the named one", Category: "view"},
+				{Key: ":save-log-view name", Description: "Save the filters of the log as a view in your tigrc", Category: "view"},
+				{Key: ":diffstat A..B", Description: "Diffstat between two revisions", Category: "view"},
+				{Key: ":range-diff A B", Description: "Compare two versions of a patch series", Category: "view"},
+				{Key: ":compare branch", Description: "Commits only in either branch and their merge base", Category: "view"},
 			},
 		},
 		{
@@ -78,31 +99,152 @@ func (v *HelpView) Load() error {
 			Items: []HelpItem{
 				{Key: "Enter", Description: "Select/open item", Category: "action"},
 				{Key: "R", Description: "Refresh current view", Category: "action"},
+				{Key: "y", Description: "Copy the hash of the commit under the cursor, see copy-command", Category: "action"},
+				{Key: "o", Description: "Open the commit under the cursor, blamed line included", Category: "action"},
+				{Key: "B", Description: "Cherry-pick commit onto another branch", Category: "action"},
+				{Key: "C, :cherry-pick", Description: "Cherry-pick commit onto the current branch, once confirmed", Category: "action"},
+				{Key: "b, :revert", Description: "Back out (revert) commit on the current branch, once confirmed; V is the visual range", Category: "action"},
+				{Key: "f", Description: "Commit staged changes as fixup of commit", Category: "action"},
+				{Key: ":checkout rev", Description: "Check out a revision, offering to stash local changes in the way", Category: "action"},
+				{Key: ":branch name [start]", Description: "Create a branch, renaming one with -m old new and deleting one with -d or -D", Category: "action"},
+				{Key: ":checkout --orphan name", Description: "Start a branch without history, its first commit built in the status view", Category: "action"},
+				{Key: ":tree [rev]", Description: "Browse the files of a revision, or of the index without one", Category: "action"},
+				{Key: ":rebase upstream", Description: "Rebase the current branch, offering to stash local changes", Category: "action"},
+				{Key: ":reset-branch --hard rev", Description: "Reset the branch to a revision, --soft, --mixed or --hard, once confirmed", Category: "action"},
+				{Key: ":autosquash", Description: "Squash fixup commits into their targets", Category: "action"},
+				{Key: ":!cmd %(commit)", Description: "Run external command on the selection", Category: "action"},
+				{Key: "Tab", Description: "Complete commands, revisions and paths at the prompt", Category: "action"},
+				{Key: "Ctrl+V", Description: "Paste the clipboard at the prompt, see clipboard-command", Category: "action"},
+				{Key: ":set opt value", Description: "Change an option, e.g. tab-size or theme", Category: "action"},
+				{Key: "confirm action level", Description: "tigrc line confirming discard, reset, reset-hard, branch-delete or force-push with none, simple (y/n) or name (typed)", Category: "action"},
+				{Key: ":save-config", Description: "Save the configuration to your tigrc", Category: "action"},
+				{Key: ":export-keys file", Description: "Write the key bindings as tigrc or markdown (.md)", Category: "action"},
+				{Key: ":audit-log", Description: "Page the changes made to the repository", Category: "action"},
+				{Key: ":init [branch]", Description: "Create a repository in the current directory", Category: "action"},
+				{Key: ":clone url [dir]", Description: "Clone a repository and open it", Category: "action"},
+				{Key: "F, :fetch", Description: "Fetch all remotes, showing progress", Category: "action"},
+				{Key: ":pull", Description: "Fast-forward the current branch to its upstream", Category: "action"},
+				{Key: "P, :push", Description: "Push the current branch to its remote, prompting for credentials git asks", Category: "action"},
 				{Key: "q", Description: "Quit application", Category: "action"},
 				{Key: "Ctrl+C", Description: "Quit application", Category: "action"},
 			},
 		},
+		{
+			Title: "Search",
+			Items: []HelpItem{
+				{Key: "/", Description: "Search commit messages and authors, this help, or filter the files of the status view or the refs", Category: "search"},
+				{Key: "n, N", Description: "Select next/previous match in the log or help", Category: "search"},
+				{Key: ":search", Description: "Show the results of the last search", Category: "search"},
+				{Key: ":clear-search", Description: "Forget the search results", Category: "search"},
+				{Key: ":grep [-i] pattern", Description: "Search the tracked files, Enter shows the file at the selected line", Category: "search"},
+			},
+		},
+		{
+			Title: "Main View",
+			Items: []HelpItem{
+				{Key: "V", Description: "Start or leave a visual range of commits, j and k extend it", Category: "main"},
+				{Key: "Enter", Description: "Diff the oldest and newest commits of the visual range", Category: "main"},
+				{Key: "s", Description: "Show the diffstat of the visual range", Category: "main"},
+				{Key: "p", Description: "Write the commits of the visual range as patches", Category: "main"},
+				{Key: "r", Description: "Rebase the visual range interactively", Category: "main"},
+			},
+		},
 		{
 			Title: "Tree View",
 			Items: []HelpItem{
-				{Key: "Enter", Description: "Enter directory", Category: "tree"},
+				{Key: "Enter", Description: "Enter directory, or show the file as of the revision shown", Category: "tree"},
 				{Key: "h, ←", Description: "Go up one directory", Category: "tree"},
 				{Key: "l, →", Description: "Enter directory", Category: "tree"},
 			},
 		},
+		{
+			Title: "Diff and Status Views",
+			Items: []HelpItem{
+				{Key: "za", Description: "Fold/unfold file or section", Category: "fold"},
+				{Key: "zo, zc", Description: "Unfold/fold file or section", Category: "fold"},
+				{Key: "zR, zM", Description: "Unfold/fold everything", Category: "fold"},
+				{Key: "←, →", Description: "Scroll diff left/right", Category: "navigation"},
+				{Key: "b", Description: "Show the commits which last changed the old lines, ? marking ignored revisions (diff view)", Category: "diff"},
+				{Key: "b", Description: "Show the commits which last changed the lines, ? marking ignored revisions (blob view)", Category: "diff"},
+				{Key: "Enter", Description: "Show the changes of the selected file, untracked files in full (status view)", Category: "status"},
+				{Key: "Enter", Description: "Go back to the file, the diff resuming at the same hunk when opened again (diff view)", Category: "diff"},
+				{Key: "a, u", Description: "Stage/unstage the file whose changes are shown (diff view)", Category: "diff"},
+				{Key: "E", Description: "Edit the hunk under the cursor in the editor and stage it (diff view)", Category: "diff"},
+				{Key: "S, !", Description: "Stage/unstage or discard the hunk under the cursor (diff view)", Category: "diff"},
+				{Key: "v, V", Description: "Mark a line, then stage/unstage the lines from it to the cursor, or the line under the cursor alone (diff view)", Category: "diff"},
+				{Key: "C", Description: "Apply the hunk under the cursor of a commit or stash to the worktree (diff view)", Category: "diff"},
+				{Key: "L", Description: "Hide/show lines whose line ending alone changed, as with the hide-eol-changes option (diff view)", Category: "diff"},
+				{Key: "1, 2, Tab", Description: "Show the unstaged/staged changes of the file, or switch between them", Category: "status"},
+				{Key: ":undo-discard", Description: "Restore the file whose changes were discarded last", Category: "status"},
+				{Key: "c", Description: "Commit the staged changes, prompting for the message", Category: "status"},
+			},
+		},
 		{
 			Title: "Refs View",
 			Items: []HelpItem{
 				{Key: "Tab", Description: "Cycle through sections", Category: "refs"},
+				{Key: ":refs-filter pattern", Description: "List the refs matching a glob or substring, the tabs counting them", Category: "refs"},
 				{Key: "1, b", Description: "Switch to branches", Category: "refs"},
 				{Key: "2, t", Description: "Switch to tags", Category: "refs"},
 				{Key: "3, r", Description: "Switch to remotes", Category: "refs"},
+				{Key: "Space", Description: "Mark/unmark branch or tag", Category: "refs"},
+				{Key: "D", Description: "Delete marked branches and tags", Category: "refs"},
+				{Key: "P", Description: "Push branch or tag to its remote", Category: "refs"},
+				{Key: "X", Description: "Delete branch or tag from its remote", Category: "refs"},
+				{Key: ":push --force", Description: "Force push branch or tag, confirmed as the force-push tigrc confirm line sets", Category: "refs"},
+				{Key: "Enter, c", Description: "Check out the selected branch, or tag detaching HEAD", Category: "refs"},
+				{Key: "n", Description: "Create a branch from the selected ref, prompting for its name", Category: "refs"},
+				{Key: "m", Description: "Rename the selected branch, prompting for the new name", Category: "refs"},
+				{Key: "d", Description: "Delete the selected branch, with force once confirmed when not merged", Category: "refs"},
+			},
+		},
+		{
+			Title: "Jobs View",
+			Items: []HelpItem{
+				{Key: "x", Description: "Cancel the selected running job", Category: "jobs"},
+			},
+		},
+		{
+			Title: "Maintenance View",
+			Items: []HelpItem{
+				{Key: ":maintenance", Description: "Show repository statistics and largest blobs", Category: "maintenance"},
+				{Key: "c", Description: "Run git gc in the background", Category: "maintenance"},
+				{Key: "r", Description: "Run git repack in the background", Category: "maintenance"},
+				{Key: "p", Description: "Prune unreachable objects older than two weeks", Category: "maintenance"},
+				{Key: "R", Description: "Count the objects again", Category: "maintenance"},
+				{Key: ":large-objects [n]", Description: "List the largest blobs of the history", Category: "maintenance"},
+				{Key: "Enter", Description: "Show the commit adding the selected blob", Category: "maintenance"},
+				{Key: ":fsck", Description: "Check the integrity of the repository", Category: "maintenance"},
+			},
+		},
+		{
+			Title: "Stash View",
+			Items: []HelpItem{
+				{Key: ":stash", Description: "List the stashes", Category: "stash"},
+				{Key: "Enter", Description: "Show the changes of the selected stash", Category: "stash"},
+				{Key: "a", Description: "Apply the selected stash, keeping it", Category: "stash"},
+				{Key: "p", Description: "Apply and drop the selected stash", Category: "stash"},
+				{Key: "d", Description: "Drop the selected stash", Category: "stash"},
+				{Key: "c", Description: "Stash the local changes", Category: "stash"},
+				{Key: "u", Description: "Stash the local changes and untracked files", Category: "stash"},
+			},
+		},
+		{
+			Title: "Review",
+			Items: []HelpItem{
+				{Key: "M", Description: "Leave a note on the hunk or file under the cursor of a diff", Category: "review"},
+				{Key: ":review", Description: "List the review notes", Category: "review"},
+				{Key: "Enter", Description: "Show the diff of the file the selected note is on", Category: "review"},
+				{Key: "d", Description: "Delete the selected note", Category: "review"},
+				{Key: ":review export <file>", Description: "Write the notes as markdown", Category: "review"},
+				{Key: ":review clear", Description: "Delete all the notes", Category: "review"},
 			},
 		},
 		{
 			Title: "General",
 			Items: []HelpItem{
 				{Key: "Ctrl+L", Description: "Redraw screen", Category: "general"},
+				{Key: "Ctrl+P", Description: "Pick a command or action from a list", Category: "general"},
 				{Key: "?", Description: "Show this help", Category: "general"},
 			},
 		},
@@ -116,56 +258,53 @@ func (v *HelpView) Render(screen tcell.Screen, x, y, width, height int) error {
 	if width == 0 || height == 0 {
 		return fmt.Errorf("invalid screen dimensions")
 	}
-
-	screen.Clear()
-
-	// Draw header
-	header := "Go Tig Help"
-	style := tcell.StyleDefault.Bold(true)
-	v.drawText(screen, 0, 0, style, header)
-	
-	// Draw version info
-	version := "Version: dev (Go implementation)"
-	if len(version) < width {
-		v.drawText(screen, width-len(version), 0, style, version)
-	}
-
-	// Draw separator
-	for x := 0; x < width; x++ {
-		screen.SetContent(x, 1, '-', nil, tcell.StyleDefault)
+	v.SetPosition(x, y, width, height)
+
+	// Draw the frame, and its scrollbar once the content is laid out
+	v.frame.Status = "Use ↑/↓ to navigate, Tab or 1-6 to switch sections, q/Esc to close"
+	x, y, width, height = v.frame.Draw(screen, x, y, width, height)
+	defer v.frame.DrawScrollbar(screen, v.Scrollable)
+	if width <= 0 || height <= 0 {
+		return nil
 	}
 
 	// Draw section tabs
-	v.drawSectionTabs(screen, width)
+	v.drawSectionTabs(screen, x, y)
 
 	// Draw content
-	contentStartY := 3
-	maxRows := height - contentStartY - 1
+	contentStartY := y + 1
+	maxRows := v.listRows()
 
 	if len(v.sections) == 0 {
 		msg := "No help content available"
-		msgX := (width - len(msg)) / 2
-		msgY := height / 2
-		v.drawText(screen, msgX, msgY, tcell.StyleDefault.Dim(true), msg)
+		msgX := x + (width-len(msg))/2
+		msgY := y + height/2
+		v.drawText(screen, msgX, msgY, width, tcell.StyleDefault.Dim(true), msg)
 	} else {
 		section := v.sections[v.currentSection]
 		
-		// Draw section title
-		v.drawText(screen, 0, contentStartY, tcell.StyleDefault.Bold(true), section.Title)
+		// Draw section title, and the version when there is room
+		style := tcell.StyleDefault.Bold(true)
+		v.drawText(screen, x, contentStartY, width, style, section.Title)
+		version := "Version: dev (Go implementation)"
+		if len(section.Title)+len(version) < width {
+			v.drawText(screen, x+width-len(version), contentStartY, len(version), style, version)
+		}
 		contentStartY += 2
 
 		// Calculate visible items
 		items := section.Items
+		v.SetMaxOffset(len(items) - maxRows)
 		visibleStart := 0
 		visibleEnd := len(items)
 		
-		if len(items) > maxRows-2 {
+		if len(items) > maxRows {
 			visibleStart = v.GetOffset()
-			visibleEnd = v.GetOffset() + maxRows - 2
+			visibleEnd = v.GetOffset() + maxRows
 			if visibleEnd > len(items) {
 				visibleEnd = len(items)
-				if visibleEnd-(maxRows-2) >= 0 {
-					visibleStart = visibleEnd - (maxRows - 2)
+				if visibleEnd-maxRows >= 0 {
+					visibleStart = visibleEnd - maxRows
 				}
 			}
 		}
@@ -177,7 +316,7 @@ func (v *HelpView) Render(screen tcell.Screen, x, y, width, height int) error {
 			
 			if i == v.selected {
 				// Highlight selected item
-				for xPos := 0; xPos < width; xPos++ {
+				for xPos := x; xPos < x+width; xPos++ {
 					screen.SetContent(xPos, itemY, ' ', nil, tcell.StyleDefault.Background(tcell.ColorBlue))
 				}
 			}
@@ -188,30 +327,37 @@ func (v *HelpView) Render(screen tcell.Screen, x, y, width, height int) error {
 
 			keyText := fmt.Sprintf("%-12s", item.Key)
 			descText := item.Description
+			if v.itemMatches(item) {
+				descStyle = descStyle.Underline(true)
+			}
 
 			// Ensure we don't overflow
 			maxDescLen := width - 15
-			if len(descText) > maxDescLen {
+			if maxDescLen > 3 && len(descText) > maxDescLen {
 				descText = descText[:maxDescLen-3] + "..."
 			}
 
-			v.drawText(screen, 2, itemY, keyStyle, keyText)
-			v.drawText(screen, 15, itemY, descStyle, descText)
+			v.drawText(screen, x+2, itemY, width-2, keyStyle, keyText)
+			v.drawText(screen, x+15, itemY, width-15, descStyle, descText)
 		}
 	}
 
-	// Draw status bar
-	v.drawStatusBar(screen, width, height)
-	
 	// Position cursor (hidden in help view)
 	screen.HideCursor()
 
 	return nil
 }
 
+// listRows returns the number of items shown at once, below the section
+// tabs and title
+func (v *HelpView) listRows() int {
+	_, _, _, height := v.GetPosition()
+	return height - 5 // Account for the frame, tabs and section title
+}
+
 // drawSectionTabs draws the section tabs
-func (v *HelpView) drawSectionTabs(screen tcell.Screen, width int) {
-	startX := 0
+func (v *HelpView) drawSectionTabs(screen tcell.Screen, x, y int) {
+	startX := x
 	for i, section := range v.sections {
 		style := tcell.StyleDefault
 		if i == v.currentSection {
@@ -219,36 +365,14 @@ func (v *HelpView) drawSectionTabs(screen tcell.Screen, width int) {
 		}
 		
 		label := fmt.Sprintf(" %s ", section.Title)
-		v.drawText(screen, startX, 2, style, label)
+		v.drawText(screen, startX, y, len(label), style, label)
 		startX += len(label) + 1
 	}
 }
 
-// drawText draws text at the specified position
-func (v *HelpView) drawText(screen tcell.Screen, x, y int, style tcell.Style, text string) {
-	for i, r := range text {
-		screen.SetContent(x+i, y, r, nil, style)
-	}
-}
-
-// drawStatusBar draws the status bar
-func (v *HelpView) drawStatusBar(screen tcell.Screen, width, height int) {
-	if height < 2 {
-		return
-	}
-
-	// Draw status bar background
-	statusStyle := tcell.StyleDefault.Background(tcell.ColorDarkGray).Foreground(tcell.ColorWhite)
-	for x := 0; x < width; x++ {
-		screen.SetContent(x, height-1, ' ', nil, statusStyle)
-	}
-
-	// Status text
-	status := "Help View - Use ↑/↓ to navigate, Tab or 1-6 to switch sections, q/Esc to close"
-	if len(status) > width {
-		status = "Help: ↑/↓ navigate, Tab/1-6 sections, q/Esc close"
-	}
-	v.drawText(screen, 0, height-1, statusStyle, status)
+// drawText draws text at the specified position, cut at the given width
+func (v *HelpView) drawText(screen tcell.Screen, x, y, width int, style tcell.Style, text string) {
+	drawCells(screen, x, y, width, textCells(text, style), 0, style)
 }
 
 // HandleKey handles key events for the help view
@@ -314,8 +438,7 @@ func (v *HelpView) moveDown() {
 	items := v.getCurrentItems()
 	if v.selected < len(items)-1 {
 		v.selected++
-		_, _, _, height := v.GetPosition()
-		maxRows := height - 6 // Account for header, tabs, etc.
+		maxRows := v.listRows()
 		if v.selected >= v.GetOffset()+maxRows {
 			v.SetOffset(v.selected - maxRows + 1)
 		}
@@ -354,6 +477,74 @@ func (v *HelpView) nextSection() {
 	v.SetOffset(0)
 }
 
+// itemMatches returns whether a help item contains the searched text
+func (v *HelpView) itemMatches(item HelpItem) bool {
+	if v.pattern == "" {
+		return false
+	}
+	return strings.Contains(strings.ToLower(item.Key), v.pattern) ||
+		strings.Contains(strings.ToLower(item.Description), v.pattern)
+}
+
+// search selects the first item containing the text, starting at the
+// selected one and continuing through the following sections
+func (v *HelpView) search(pattern string) bool {
+	v.pattern = strings.ToLower(pattern)
+	return v.findMatch(0)
+}
+
+// findMatch selects the next (1) or previous (-1) item matching the search
+// of all sections, wrapping around; 0 also considers the selected item
+func (v *HelpView) findMatch(direction int) bool {
+	type position struct{ section, item int }
+	var positions []position
+	current := 0
+	for s, section := range v.sections {
+		for i := range section.Items {
+			if s == v.currentSection && i == v.selected {
+				current = len(positions)
+			}
+			positions = append(positions, position{s, i})
+		}
+	}
+
+	step, start := direction, current+direction
+	if direction == 0 {
+		step, start = 1, current
+	}
+	for k := range positions {
+		n := len(positions)
+		pos := positions[((start+k*step)%n+n)%n]
+		if v.itemMatches(v.sections[pos.section].Items[pos.item]) {
+			v.currentSection = pos.section
+			v.selected = pos.item
+			v.adjustScroll()
+			return true
+		}
+	}
+	return false
+}
+
+// searchHelp handles :search in the help view, selecting the first item
+// containing the text. Without text it moves on to the next match.
+func (vm *ViewManager) searchHelp(view *HelpView, args []string) error {
+	if len(args) == 0 {
+		if view.pattern == "" {
+			return fmt.Errorf("usage: search <pattern>")
+		}
+		if !view.findMatch(1) {
+			vm.setMessage("No help matches %q", view.pattern)
+		}
+		return nil
+	}
+
+	pattern := strings.Join(args, " ")
+	if !view.search(pattern) {
+		vm.setMessage("No help matches %q", pattern)
+	}
+	return nil
+}
+
 // getCurrentItems returns the items for the current section
 func (v *HelpView) getCurrentItems() []HelpItem {
 	if v.currentSection >= 0 && v.currentSection < len(v.sections) {
@@ -365,8 +556,7 @@ func (v *HelpView) getCurrentItems() []HelpItem {
 // adjustScroll adjusts the scroll position based on selected index
 func (v *HelpView) adjustScroll() {
 	items := v.getCurrentItems()
-	_, _, _, height := v.GetPosition()
-	maxRows := height - 6 // Account for header, tabs, etc.
+	maxRows := v.listRows()
 	
 	if v.selected < v.GetOffset() {
 		v.SetOffset(v.selected)
@@ -384,8 +574,7 @@ func (v *HelpView) adjustScroll() {
 
 // pageUp moves up by one page
 func (v *HelpView) pageUp() {
-	_, _, _, height := v.GetPosition()
-	pageSize := height - 6
+	pageSize := v.listRows()
 	
 	if v.selected >= pageSize {
 		v.selected -= pageSize
@@ -401,8 +590,7 @@ func (v *HelpView) pageUp() {
 // pageDown moves down by one page
 func (v *HelpView) pageDown() {
 	items := v.getCurrentItems()
-	_, _, _, height := v.GetPosition()
-	pageSize := height - 6
+	pageSize := v.listRows()
 	
 	if v.selected+pageSize < len(items) {
 		v.selected += pageSize
@@ -421,7 +609,7 @@ func (v *HelpView) Refresh() error {
 // SetPosition sets the view position and size
 func (v *HelpView) SetPosition(x, y, width, height int) {
 	v.BaseView.SetPosition(x, y, width, height)
-	v.SetHeight(height - 6) // Account for header and status bar
+	v.SetHeight(v.listRows())
 }
 
 // GetType returns the view type
@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHelpViewSearch(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	vm := NewViewManager(screen, cfg, git.NewClient(), NewKeyBindingManager(cfg))
+	vm.SetSize(80, 24)
+	require.NoError(t, vm.SwitchView(ViewTypeHelp))
+	help := vm.views[ViewTypeHelp].(*HelpView)
+	require.NoError(t, help.Load())
+
+	selected := func() string {
+		return help.sections[help.currentSection].Items[help.selected].Description
+	}
+
+	// The search moves on to the sections containing matches
+	require.NoError(t, vm.SearchCommand([]string{"PARENT"}))
+	assert.Equal(t, "Go to parent commit", selected())
+	require.NoError(t, vm.SearchCommand([]string{"fixup"}))
+	assert.Equal(t, "Commit staged changes as fixup of commit", selected())
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'n', 0))
+	assert.Equal(t, "Squash fixup commits into their targets", selected())
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'n', 0))
+	assert.Equal(t, "Commit staged changes as fixup of commit", selected(), "wraps around")
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'N', 0))
+	assert.Equal(t, "Squash fixup commits into their targets", selected())
+	require.NoError(t, vm.SearchCommand(nil))
+	assert.Equal(t, "Commit staged changes as fixup of commit", selected())
+
+	require.NoError(t, vm.SearchCommand([]string{"no such thing"}))
+	assert.Equal(t, `No help matches "no such thing"`, vm.GetMessage())
+	assert.Equal(t, "Commit staged changes as fixup of commit", selected())
+}
@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// HintSource is implemented by views which show the actions relevant to
+// their selected item in a hint bar below the view
+type HintSource interface {
+	// HintContext returns the kind of the selected item, such as "commit",
+	// or "" when there is nothing to give hints for
+	HintContext() string
+}
+
+// Contexts of the items views give hints for
+const (
+	hintContextCommit    = "commit"
+	hintContextHeader    = "header"
+	hintContextStaged    = statusSectionStaged
+	hintContextModified  = statusSectionModified
+	hintContextUntracked = statusSectionUntracked
+	hintContextConflict  = statusSectionConflict
+	hintContextStash     = "stash"
+	hintContextRange     = "range"
+)
+
+// hintActions lists the actions worth pointing out for each context, most
+// relevant first
+var hintActions = map[string][]string{
+	hintContextCommit:    {"enter", "backport", "fixup", "parent", "search", "pager"},
+	hintContextHeader:    {"fold", "stage-all", "unstage-all", "commit"},
+	hintContextStaged:    {"unstage", "unstage-all", "commit"},
+	hintContextModified:  {"stage", "discard", "stage-all", "commit"},
+	hintContextUntracked: {"stage", "stage-all"},
+	hintContextConflict:  {"stage", "commit"},
+	hintContextStash:     {"enter", "stash-apply", "stash-pop", "stash-drop", "stash-push"},
+	hintContextRange:     {"enter", "range-diffstat", "format-patch", "rebase-range", "visual"},
+}
+
+// hintLabels names the actions briefly, the help texts of the bindings
+// being too long for the bar
+var hintLabels = map[string]string{
+	"enter":          "open",
+	"backport":       "backport",
+	"fixup":          "fixup",
+	"parent":         "parent",
+	"search":         "search",
+	"pager":          "pager",
+	"fold":           "fold",
+	"stage":          "stage",
+	"unstage":        "unstage",
+	"stage-all":      "stage all",
+	"unstage-all":    "unstage all",
+	"discard":        "discard",
+	"commit":         "commit",
+	"stash-apply":    "apply",
+	"stash-pop":      "pop",
+	"stash-drop":     "drop",
+	"stash-push":     "stash",
+	"range-diffstat": "diffstat",
+	"format-patch":   "patches",
+	"rebase-range":   "rebase",
+	"visual":         "leave",
+}
+
+// hintKeys are the keys of actions handled by the views themselves rather
+// than through the keymap
+var hintKeys = map[string]string{
+	"fold":           "za",
+	"stash-apply":    "a",
+	"stash-pop":      "p",
+	"stash-drop":     "d",
+	"stash-push":     "c",
+	"range-diffstat": "s",
+	"format-patch":   "p",
+	"rebase-range":   "r",
+	"visual":         "V",
+}
+
+// hint is an action shown in the hint bar along with its key
+type hint struct {
+	key   string
+	label string
+}
+
+// hintsFor returns the hints of a context with the keys currently bound
+// to their actions
+func (k *KeyBindingManager) hintsFor(context string) []hint {
+	var hints []hint
+	for _, action := range hintActions[context] {
+		// Actions which are refused aren't worth pointing out
+		if k.readOnly() && isMutatingAction(action) {
+			continue
+		}
+		key, ok := hintKeys[action]
+		if binding, bound := k.GetBinding(action); bound {
+			key, ok = k.bindingToString(binding), true
+		}
+		if !ok {
+			continue
+		}
+		label := hintLabels[action]
+		if label == "" {
+			label = action
+		}
+		hints = append(hints, hint{key, label})
+	}
+	return hints
+}
+
+// currentHints returns the hints for the selected item of the current view
+func (vm *ViewManager) currentHints() []hint {
+	source, ok := vm.views[vm.currentView].(HintSource)
+	if !ok {
+		return nil
+	}
+	return vm.keyBindingMgr.hintsFor(source.HintContext())
+}
+
+// drawHints draws the hint bar on the given line, keys highlighted
+func (vm *ViewManager) drawHints(y int, hints []hint) {
+	style := tcell.StyleDefault.Dim(true)
+	keyStyle := tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true)
+
+	cells := []cell{{' ', style}}
+	for i, h := range hints {
+		if i > 0 {
+			cells = append(cells, textCells("  ", style)...)
+		}
+		cells = append(cells, textCells(h.key, keyStyle)...)
+		cells = append(cells, textCells(" "+h.label, style)...)
+	}
+	drawCells(vm.screen, 0, y, vm.width, cells, 0, style)
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHintsFor(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Keymaps.Bindings = map[string]string{"stage": "S"}
+	keys := NewKeyBindingManager(cfg)
+
+	// Hints follow the keymap, and keys handled by views are listed too
+	assert.Equal(t, []hint{{"S", "stage"}, {"d", "discard"}, {"A", "stage all"}, {"c", "commit"}}, keys.hintsFor(hintContextModified))
+	assert.Equal(t, hint{"za", "fold"}, keys.hintsFor(hintContextHeader)[0])
+	assert.Empty(t, keys.hintsFor(""))
+}
+
+func TestStatusViewHintContext(t *testing.T) {
+	view := NewStatusView(&config.Config{}, git.NewClient())
+	assert.Equal(t, "", view.HintContext())
+
+	view.status = &git.Status{
+		Staged:    []git.FileStatus{{Path: "file1.txt", X: "M"}},
+		Untracked: []git.FileStatus{{Path: "file2.txt"}},
+	}
+	contexts := map[string]string{
+		"Changes to be committed:":      hintContextHeader,
+		"\tmodified: file1.txt":         hintContextStaged,
+		"\tfile2.txt":                   hintContextUntracked,
+		"1 staged, 1 untracked changes": "",
+	}
+	for i, line := range view.buildStatusLines() {
+		if context, ok := contexts[line]; ok {
+			view.selected = i
+			assert.Equal(t, context, view.HintContext(), line)
+		}
+	}
+}
+
+func TestViewManagerRenderHints(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	vm := NewViewManager(screen, cfg, git.NewClient(), NewKeyBindingManager(cfg))
+	vm.SetSize(80, 24)
+
+	status := vm.GetView(ViewTypeStatus).(*StatusView)
+	status.status = &git.Status{Modified: []git.FileStatus{{Path: "main.go", Y: "M"}}}
+	status.selected = 3
+	require.NoError(t, vm.SwitchView(ViewTypeStatus))
+	require.NoError(t, vm.Render())
+
+	lines := screenLines(screen)
+	assert.Equal(t, "a stage  d discard  A stage all  c commit", strings.TrimSpace(lines[23]))
+	assert.True(t, strings.HasPrefix(lines[22], "└"), lines[22])
+
+	// Views without hints keep the whole screen
+	require.NoError(t, vm.SwitchView(ViewTypeCompare))
+	require.NoError(t, vm.Render())
+	assert.True(t, strings.HasPrefix(screenLines(screen)[23], "└"))
+}
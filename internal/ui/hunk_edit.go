@@ -0,0 +1,185 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/azhao1981/tig/internal/git"
+)
+
+// hunkEditGuide follows the hunk given to the editor, as with the e
+// command of git add -p
+const hunkEditGuide = `# ---
+# To remove '-' lines, make them ' ' lines (context).
+# To remove '+' lines, delete them.
+# Lines starting with # will be removed.
+# If the patch applies cleanly, the edited hunk will be staged.
+# To leave the index alone, delete all lines.
+`
+
+// selectedHunk returns the hunk under the cursor of the diff view, the
+// first hunk of the file when on its headers
+func (v *DiffView) selectedHunk() (*git.DiffFile, *git.DiffHunk, error) {
+	offset := v.GetOffset()
+	if offset >= len(v.rows) {
+		return nil, nil, fmt.Errorf("no hunk under the cursor")
+	}
+
+	row := v.rows[offset]
+	switch {
+	case row.file == nil:
+	case row.hunk >= 0:
+		return row.file, row.file.Hunks[row.hunk], nil
+	case len(row.file.Hunks) > 0:
+		return row.file, row.file.Hunks[0], nil
+	}
+	return nil, nil, fmt.Errorf("no hunk under the cursor")
+}
+
+// hunkText returns a hunk as it appears in a patch, header included
+func hunkText(hunk *git.DiffHunk) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "@@ -%s +%s @@", formatHunkRange(hunk.OldStart, hunk.OldLines), formatHunkRange(hunk.NewStart, hunk.NewLines))
+	if hunk.Header != "" {
+		b.WriteString(" " + hunk.Header)
+	}
+	b.WriteString("\n")
+
+	for _, line := range hunk.Lines {
+		switch line.Type {
+		case git.DiffLineAddition:
+			b.WriteString("+")
+		case git.DiffLineDeletion:
+			b.WriteString("-")
+		case git.DiffLineContext:
+			b.WriteString(" ")
+		}
+		b.WriteString(line.Content + "\n")
+	}
+	return b.String()
+}
+
+// parseEditedHunk reads back a hunk edited by hand, dropping the comments.
+// Empty lines are taken for context lines whose blank was trimmed by the
+// editor. An empty result means the edit was given up.
+func parseEditedHunk(text string) (string, error) {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	if strings.TrimSpace(strings.Join(lines, "")) == "" {
+		return "", nil
+	}
+
+	if !strings.HasPrefix(lines[0], "@@ ") {
+		return "", fmt.Errorf("the edited hunk lost its @@ header")
+	}
+	changes := 0
+	for i, line := range lines[1:] {
+		switch {
+		case line == "":
+			lines[i+1] = " "
+		case line[0] == '+' || line[0] == '-':
+			changes++
+		case line[0] != ' ' && line[0] != '\\':
+			return "", fmt.Errorf("line %d of the edited hunk is neither context nor a change", i+2)
+		}
+	}
+	if changes == 0 {
+		return "", fmt.Errorf("the edited hunk has no changes left")
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// filePatch returns the patch of a file reduced to the given hunks
+func filePatch(file *git.DiffFile, hunks string) string {
+	oldPath, newPath := file.OldPath, file.NewPath
+	if oldPath == "" {
+		oldPath = newPath
+	}
+	if newPath == "" {
+		newPath = oldPath
+	}
+	from, to := "a/"+oldPath, "b/"+newPath
+	if file.IsNew {
+		from = "/dev/null"
+	}
+	if file.IsDeleted {
+		to = "/dev/null"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\n", oldPath, newPath)
+	for _, header := range file.Headers {
+		b.WriteString(header + "\n")
+	}
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", from, to)
+	b.WriteString(hunks)
+	return b.String()
+}
+
+// editHunk opens the hunk under the cursor of the diff view in the editor
+// and stages it as edited, like the e command of git add -p
+func (vm *ViewManager) editHunk() error {
+	diffView, ok := vm.views[ViewTypeDiff].(*DiffView)
+	if !ok || diffView.stage == nil || diffView.stage.section == statusSectionStaged {
+		return fmt.Errorf("only hunks of changes not staged yet can be edited")
+	}
+	file, hunk, err := diffView.selectedHunk()
+	if err != nil {
+		return err
+	}
+	if file.IsBinary {
+		return fmt.Errorf("hunks of binary files cannot be edited")
+	}
+	if file.Textconv != "" {
+		return fmt.Errorf("hunks of files converted to text by textconv cannot be edited")
+	}
+	if file.Structured != "" {
+		return fmt.Errorf("hunks of structured diffs cannot be edited")
+	}
+
+	tmp, err := os.CreateTemp("", "tig-hunk-*.diff")
+	if err != nil {
+		return fmt.Errorf("failed to create the hunk file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString("# Manual hunk edit mode -- see bottom for a quick guide.\n" + hunkText(hunk) + hunkEditGuide)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write the hunk file: %w", err)
+	}
+
+	if err := vm.runEditor(tmp.Name()); err != nil {
+		return err
+	}
+	text, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return fmt.Errorf("failed to read the edited hunk: %w", err)
+	}
+	edited, err := parseEditedHunk(string(text))
+	if err != nil {
+		return err
+	}
+	if edited == "" {
+		vm.setMessage("The edited hunk is empty, nothing was staged")
+		return nil
+	}
+	if err := vm.client.StagePatch(filePatch(file, edited)); err != nil {
+		return err
+	}
+
+	path := diffView.stage.path
+	if statusView, ok := vm.views[ViewTypeStatus].(*StatusView); ok {
+		_ = statusView.refreshChanged()
+	}
+	// Staging part of an untracked file adds it to the index
+	diffView.stage.section = unstagedSection(vm.client, path)
+	vm.setMessage("Staged the edited hunk of %s", path)
+	return diffView.Refresh()
+}
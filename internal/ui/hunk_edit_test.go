@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEditedHunk(t *testing.T) {
+	edited, err := parseEditedHunk("# comment\n@@ -1,2 +1,2 @@\n one\n\n-two\n+2\n# guide\n")
+	require.NoError(t, err)
+	assert.Equal(t, "@@ -1,2 +1,2 @@\n one\n \n-two\n+2\n", edited)
+
+	// Deleting everything gives up
+	edited, err = parseEditedHunk("# comment\n\n")
+	require.NoError(t, err)
+	assert.Empty(t, edited)
+
+	_, err = parseEditedHunk(" one\n+2\n")
+	assert.ErrorContains(t, err, "@@ header")
+	_, err = parseEditedHunk("@@ -1 +1 @@\n one\n*2\n")
+	assert.ErrorContains(t, err, "line 3")
+	_, err = parseEditedHunk("@@ -1 +1 @@\n one\n")
+	assert.ErrorContains(t, err, "no changes")
+}
+
+func TestDiffViewEditsHunk(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	refsTestGit(t, dir, "init", "--quiet", "--initial-branch=main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("one\ntwo\nthree\n"), 0644))
+	refsTestGit(t, dir, "add", "file.txt")
+	refsTestGit(t, dir, "commit", "--quiet", "-m", "first")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("one\n2\nthree\nfour\n"), 0644))
+
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+	vm.SetSize(100, 30)
+	vm.SetRepoPath(dir)
+	diffView := vm.views[ViewTypeDiff].(*DiffView)
+	require.NoError(t, vm.SwitchView(ViewTypeDiff))
+	require.NoError(t, diffView.SetStageFile("file.txt", statusSectionModified))
+
+	// The editor option is what runs, not an editor git was told to use
+	t.Setenv("GIT_EDITOR", "")
+
+	// Edits which don't apply leave the index alone
+	cfg.General.Editor = `sed -i 's/^ one$/ uno/'`
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'E', 0))
+	assert.Contains(t, vm.GetMessage(), "patch does not apply")
+	assert.Empty(t, refsTestGit(t, dir, "diff", "--cached"))
+
+	cfg.General.Editor = `sed -i d`
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'E', 0))
+	assert.Contains(t, vm.GetMessage(), "nothing was staged")
+
+	// Dropping an added line stages the rest of the hunk
+	cfg.General.Editor = `sed -i '/^+four$/d'`
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'E', 0))
+	assert.Equal(t, "Staged the edited hunk of file.txt", vm.GetMessage())
+	assert.Equal(t, "one\n2\nthree", refsTestGit(t, dir, "show", ":file.txt"))
+	_, section := diffView.GetStageFile()
+	assert.Equal(t, statusSectionModified, section)
+	lines := diffView.GetDiff().Files[0].Hunks[0].Lines
+	assert.Equal(t, git.DiffLineAddition, lines[len(lines)-1].Type)
+	assert.Equal(t, "four", lines[len(lines)-1].Content)
+
+	// Staged hunks are not edited
+	require.NoError(t, diffView.SetStageFile("file.txt", statusSectionStaged))
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'E', 0))
+	assert.Contains(t, vm.GetMessage(), "not staged yet")
+}
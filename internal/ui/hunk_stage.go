@@ -0,0 +1,199 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/azhao1981/tig/internal/git"
+)
+
+// stageHunk stages the hunk under the cursor of the diff of a status file,
+// or unstages it when the staged changes of the file are shown
+func (vm *ViewManager) stageHunk() error {
+	diffView, ok := vm.views[ViewTypeDiff].(*DiffView)
+	if !ok || diffView.stage == nil {
+		return fmt.Errorf("only hunks of changes of the worktree can be staged")
+	}
+	file, hunk, err := diffView.selectedHunk()
+	if err != nil {
+		return err
+	}
+	if file.IsBinary {
+		return fmt.Errorf("hunks of binary files cannot be staged")
+	}
+	if file.Textconv != "" {
+		return fmt.Errorf("hunks of files converted to text by textconv cannot be staged")
+	}
+	if file.Structured != "" {
+		return fmt.Errorf("hunks of structured diffs cannot be staged")
+	}
+
+	path := diffView.stage.path
+	if diffView.stage.section == statusSectionStaged {
+		if err := vm.client.UnstageHunk(path, hunk); err != nil {
+			return err
+		}
+		vm.setMessage("Unstaged the hunk of %s", path)
+	} else {
+		if err := vm.client.StageHunk(path, hunk); err != nil {
+			return err
+		}
+		// Staging part of an untracked file adds it to the index
+		diffView.stage.section = unstagedSection(vm.client, path)
+		vm.setMessage("Staged the hunk of %s", path)
+	}
+	return vm.refreshStageHunks(diffView)
+}
+
+// lineUnderCursor returns the changed line under the cursor of the diff
+// view along with its file, its hunk and its index within the hunk
+func (v *DiffView) lineUnderCursor() (*git.DiffFile, *git.DiffHunk, int, error) {
+	offset := v.GetOffset()
+	if offset < len(v.rows) {
+		row := v.rows[offset]
+		if row.line != nil && row.hunk >= 0 {
+			hunk := row.file.Hunks[row.hunk]
+			for i, line := range hunk.Lines {
+				if line == row.line {
+					return row.file, hunk, i, nil
+				}
+			}
+		}
+	}
+	return nil, nil, 0, fmt.Errorf("no line of a hunk under the cursor")
+}
+
+// markLine marks the line under the cursor as one end of the lines
+// stage-lines stages, or unmarks it when it is marked already
+func (vm *ViewManager) markLine() error {
+	diffView, ok := vm.views[ViewTypeDiff].(*DiffView)
+	if !ok || diffView.stage == nil {
+		return fmt.Errorf("only lines of changes of the worktree can be staged")
+	}
+	_, hunk, index, err := diffView.lineUnderCursor()
+	if err != nil {
+		return err
+	}
+	if line := hunk.Lines[index]; diffView.lineMark != line {
+		diffView.lineMark = line
+		vm.setMessage("Marked the line, V stages the lines from it to the cursor")
+	} else {
+		diffView.lineMark = nil
+		vm.setMessage("Unmarked the line")
+	}
+	return nil
+}
+
+// stageLines stages the changed lines from the line marked by mark-line to
+// the cursor, or the line under the cursor when none is marked, and
+// unstages them when the staged changes of the file are shown
+func (vm *ViewManager) stageLines() error {
+	diffView, ok := vm.views[ViewTypeDiff].(*DiffView)
+	if !ok || diffView.stage == nil {
+		return fmt.Errorf("only lines of changes of the worktree can be staged")
+	}
+	file, hunk, last, err := diffView.lineUnderCursor()
+	if err != nil {
+		return err
+	}
+	if file.IsBinary || file.Textconv != "" || file.Structured != "" {
+		return fmt.Errorf("lines of %s cannot be staged", file.Path())
+	}
+	first := last
+	if diffView.lineMark != nil {
+		first = slices.Index(hunk.Lines, diffView.lineMark)
+		if first < 0 {
+			return fmt.Errorf("the marked line is not in the hunk under the cursor")
+		}
+	}
+
+	path := diffView.stage.path
+	if diffView.stage.section == statusSectionStaged {
+		if err := vm.client.UnstageHunkLines(path, hunk, first, last); err != nil {
+			return err
+		}
+		vm.setMessage("Unstaged the selected lines of %s", path)
+	} else {
+		if err := vm.client.StageHunkLines(path, hunk, first, last); err != nil {
+			return err
+		}
+		diffView.stage.section = unstagedSection(vm.client, path)
+		vm.setMessage("Staged the selected lines of %s", path)
+	}
+	diffView.lineMark = nil
+	return vm.refreshStageHunks(diffView)
+}
+
+// discardHunk discards the hunk under the cursor of the unstaged changes of
+// a status file, which :undo-discard restores
+func (vm *ViewManager) discardHunk() error {
+	diffView, ok := vm.views[ViewTypeDiff].(*DiffView)
+	if !ok || diffView.stage == nil || diffView.stage.section == statusSectionStaged {
+		return fmt.Errorf("only hunks of changes not staged yet can be discarded")
+	}
+	file, hunk, err := diffView.selectedHunk()
+	if err != nil {
+		return err
+	}
+	if file.IsBinary {
+		return fmt.Errorf("hunks of binary files cannot be discarded")
+	}
+	if file.Textconv != "" {
+		return fmt.Errorf("hunks of files converted to text by textconv cannot be discarded")
+	}
+	if file.Structured != "" {
+		return fmt.Errorf("hunks of structured diffs cannot be discarded")
+	}
+
+	path := diffView.stage.path
+	if err := vm.client.DiscardHunk(path, hunk); err != nil {
+		return err
+	}
+	vm.setMessage("Discarded the hunk of %s, :undo-discard restores it", path)
+	return vm.refreshStageHunks(diffView)
+}
+
+// applyHunk applies the hunk under the cursor of the diff of a commit or a
+// stash to the worktree, picking changes out of it
+func (vm *ViewManager) applyHunk() error {
+	diffView, ok := vm.views[ViewTypeDiff].(*DiffView)
+	if !ok || diffView.stage != nil {
+		return fmt.Errorf("only hunks of commits and stashes can be applied to the worktree")
+	}
+	file, hunk, err := diffView.selectedHunk()
+	if err != nil {
+		return err
+	}
+	if file.IsBinary {
+		return fmt.Errorf("hunks of binary files cannot be applied")
+	}
+	if file.Textconv != "" {
+		return fmt.Errorf("hunks of files converted to text by textconv cannot be applied")
+	}
+	if file.Structured != "" {
+		return fmt.Errorf("hunks of structured diffs cannot be applied")
+	}
+
+	if err := vm.client.ApplyHunkToWorktree(file.Path(), hunk); err != nil {
+		return err
+	}
+	if statusView, ok := vm.views[ViewTypeStatus].(*StatusView); ok {
+		_ = statusView.refreshChanged()
+	}
+	vm.setMessage("Applied the hunk of %s to the worktree", file.Path())
+	return nil
+}
+
+// refreshStageHunks refreshes the diff of a status file after one of its
+// hunks was staged or discarded, staying at the hunk which took its place
+func (vm *ViewManager) refreshStageHunks(diffView *DiffView) error {
+	if statusView, ok := vm.views[ViewTypeStatus].(*StatusView); ok {
+		_ = statusView.refreshChanged()
+	}
+	index := diffView.hunkIndex()
+	if err := diffView.Refresh(); err != nil {
+		return err
+	}
+	diffView.scrollToHunk(index)
+	return nil
+}
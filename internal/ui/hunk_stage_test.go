@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHunkKeys(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, strings.Repeat("x", i))
+	}
+	base := strings.Join(lines, "\n") + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(base), 0644))
+	refsTestGit(t, dir, "commit", "--quiet", "-am", "notes")
+	lines[0], lines[19] = "first", "last"
+	changed := strings.Join(lines, "\n") + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(changed), 0644))
+
+	diffView := vm.views[ViewTypeDiff].(*DiffView)
+	require.NoError(t, vm.showStageFile("notes.txt", statusSectionModified))
+	require.Len(t, diffView.GetDiff().Files[0].Hunks, 2)
+
+	// The first hunk is staged, the second one taking its place
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'S', 0))
+	assert.Equal(t, "Staged the hunk of notes.txt", vm.GetMessage())
+	assert.Contains(t, refsTestGit(t, dir, "diff", "--cached"), "+first")
+	assert.NotContains(t, refsTestGit(t, dir, "diff", "--cached"), "+last")
+	require.Len(t, diffView.GetDiff().Files[0].Hunks, 1)
+
+	// then discarded, the file being kept in the trash
+	assert.True(t, vm.HandleKey(tcell.KeyRune, '!', 0))
+	assert.Contains(t, vm.GetMessage(), ":undo-discard")
+	assert.Empty(t, refsTestGit(t, dir, "diff"))
+	require.NoError(t, vm.UndoDiscardCommand(nil))
+	assert.Contains(t, refsTestGit(t, dir, "diff"), "+last")
+
+	// Staged hunks are unstaged
+	require.NoError(t, vm.showStageSide(statusSectionStaged))
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'S', 0))
+	assert.Equal(t, "Unstaged the hunk of notes.txt", vm.GetMessage())
+	assert.Empty(t, refsTestGit(t, dir, "diff", "--cached"))
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'C', 0))
+	assert.Contains(t, vm.GetMessage(), "only hunks of commits and stashes")
+
+	// Hunks of stashes are applied to the worktree
+	refsTestGit(t, dir, "stash", "--quiet")
+	vm.SetSize(100, 8)
+	require.NoError(t, vm.DiffCommand([]string{"stash@{0}"}))
+	assert.True(t, vm.HandleKey(tcell.KeyRune, '!', 0))
+	assert.Contains(t, vm.GetMessage(), "only hunks of changes not staged yet")
+	for i := 0; diffView.hunkIndex() < 0; i++ {
+		require.Less(t, i, 30)
+		require.True(t, vm.HandleKey(tcell.KeyRune, 'j', 0))
+	}
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'C', 0))
+	assert.Equal(t, "Applied the hunk of notes.txt to the worktree", vm.GetMessage())
+	assert.Contains(t, refsTestGit(t, dir, "diff"), "+first")
+	assert.NotContains(t, refsTestGit(t, dir, "diff"), "+last")
+}
+
+func TestHunkKeysRefuseTextconv(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte("notes.txt diff=octal\n"), 0644))
+	refsTestGit(t, dir, "config", "diff.octal.textconv", "od -An -c")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("changed\n"), 0644))
+
+	diffView := vm.views[ViewTypeDiff].(*DiffView)
+	require.NoError(t, vm.showStageFile("notes.txt", statusSectionModified))
+	require.Equal(t, "od -An -c", diffView.GetDiff().Files[0].Textconv)
+
+	// The hunks show converted contents, which would end up in the file
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'S', 0))
+	assert.Equal(t, "hunks of files converted to text by textconv cannot be staged", vm.GetMessage())
+	assert.True(t, vm.HandleKey(tcell.KeyRune, '!', 0))
+	assert.Equal(t, "hunks of files converted to text by textconv cannot be discarded", vm.GetMessage())
+	assert.Empty(t, refsTestGit(t, dir, "diff", "--cached"))
+}
+
+func TestLineKeys(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, strings.Repeat("x", i))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(strings.Join(lines, "\n")+"\n"), 0644))
+	refsTestGit(t, dir, "commit", "--quiet", "-am", "notes")
+	lines[1] = "second\nsecond and a half"
+	lines[19] = "last"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(strings.Join(lines, "\n")+"\n"), 0644))
+
+	vm.SetSize(100, 8)
+	diffView := vm.views[ViewTypeDiff].(*DiffView)
+	require.NoError(t, vm.showStageFile("notes.txt", statusSectionModified))
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'v', 0))
+	assert.Contains(t, vm.GetMessage(), "no line of a hunk")
+
+	// Lines are marked, then staged up to the cursor
+	for i := 0; ; i++ {
+		require.Less(t, i, 30)
+		if _, hunk, index, err := diffView.lineUnderCursor(); err == nil && hunk.Lines[index].Content == "second" {
+			break
+		}
+		require.True(t, vm.HandleKey(tcell.KeyRune, 'j', 0))
+	}
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'v', 0))
+	assert.Contains(t, vm.GetMessage(), "Marked the line")
+	require.True(t, vm.HandleKey(tcell.KeyRune, 'j', 0))
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'V', 0))
+	assert.Equal(t, "Staged the selected lines of notes.txt", vm.GetMessage())
+	staged := refsTestGit(t, dir, "diff", "--cached")
+	assert.Contains(t, staged, "+second\n+second and a half")
+	assert.NotContains(t, staged, "-xx\n")
+	assert.NotContains(t, staged, "+last")
+	assert.Nil(t, diffView.lineMark)
+
+	// A line alone is unstaged without a mark
+	vm.SetSize(100, 5)
+	require.NoError(t, vm.showStageSide(statusSectionStaged))
+	for i := 0; ; i++ {
+		require.Less(t, i, 30)
+		if _, hunk, index, err := diffView.lineUnderCursor(); err == nil && hunk.Lines[index].Content == "second and a half" {
+			break
+		}
+		require.True(t, vm.HandleKey(tcell.KeyRune, 'j', 0))
+	}
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'V', 0))
+	assert.Equal(t, "Unstaged the selected lines of notes.txt", vm.GetMessage())
+	staged = refsTestGit(t, dir, "diff", "--cached")
+	assert.Contains(t, staged, "+second\n")
+	assert.NotContains(t, staged, "second and a half")
+}
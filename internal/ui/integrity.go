@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// integrityWarning is the banner shown under the title bar once the
+// repository seems to have broken or dangling objects
+type integrityWarning struct {
+	text   string
+	broken bool // Objects are missing or damaged, rather than dangling
+}
+
+// noteCorruption raises the banner when an error reported to the user
+// suggests that objects of the repository are missing or damaged
+func (vm *ViewManager) noteCorruption(args []interface{}) {
+	for _, arg := range args {
+		if err, ok := arg.(error); ok && git.IsCorruption(err) {
+			vm.integrity = &integrityWarning{
+				text:   "The repository may be corrupt, run :fsck to check it",
+				broken: true,
+			}
+			return
+		}
+	}
+}
+
+// fsckChecked shows what git fsck found in the banner, lowering it when
+// nothing was, and returns a summary of it
+func (vm *ViewManager) fsckChecked(result *git.FsckResult) string {
+	switch {
+	case result.Broken > 0:
+		vm.integrity = &integrityWarning{
+			text:   fmt.Sprintf("git fsck found %d broken objects or links, see :fsck", result.Broken),
+			broken: true,
+		}
+		return fmt.Sprintf("git fsck: %d broken, %d dangling", result.Broken, result.Dangling)
+	case result.Dangling > 0:
+		vm.integrity = &integrityWarning{
+			text: fmt.Sprintf("git fsck found %d dangling objects, which git gc prunes once two weeks old", result.Dangling),
+		}
+		return fmt.Sprintf("git fsck: %d dangling", result.Dangling)
+	default:
+		vm.integrity = nil
+		return "git fsck: no problems"
+	}
+}
+
+// drawIntegrity draws the integrity banner on the given line
+func (vm *ViewManager) drawIntegrity(y int) {
+	style := tcell.StyleDefault.Background(tcell.ColorOlive).Foreground(tcell.ColorBlack)
+	if vm.integrity.broken {
+		style = tcell.StyleDefault.Background(tcell.ColorMaroon).Foreground(tcell.ColorWhite).Bold(true)
+	}
+	cells := textCells(" ⚠ "+vm.integrity.text, style)
+	for cellsWidth(cells) < vm.width {
+		cells = append(cells, cell{' ', style})
+	}
+	drawCells(vm.screen, 0, y, vm.width, cells, 0, style)
+}
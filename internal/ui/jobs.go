@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// maxFinishedJobs is how many finished jobs are kept for the jobs view
+const maxFinishedJobs = 20
+
+// jobEvent is posted to the event loop by background jobs, which touch the
+// views only from there
+type jobEvent func()
+
+// Kinds of background jobs, which the notify-on option picks from
+const (
+	jobFetch       = "fetch"
+	jobPull        = "pull"
+	jobPush        = "push"
+	jobMaintenance = "maintenance"
+)
+
+// job is an operation running in the background, such as a push
+type job struct {
+	kind      string
+	name      string
+	quiet     bool   // Progress is not shown in the status line
+	progress  string // Last progress line reported
+	started   time.Time
+	finished  time.Time // Zero while running
+	err       error
+	cancelled bool
+	cancel    context.CancelFunc
+	view      ViewType // View shown when the job started
+}
+
+// running returns whether the job is still going
+func (j *job) running() bool {
+	return j.finished.IsZero()
+}
+
+// duration returns how long the job ran, or has been running
+func (j *job) duration() time.Duration {
+	if j.running() {
+		return time.Since(j.started)
+	}
+	return j.finished.Sub(j.started)
+}
+
+// startJob runs work in the background as the given job, of which only the
+// kind, name and quietness need to be set. Unless quiet, the progress work
+// reports is shown in the status line. done is called on the event loop,
+// with the view manager locked, once the work finished unless it was
+// cancelled. It returns the outcome of the job, shown in the status line
+// and notified of unless empty.
+func (vm *ViewManager) startJob(j *job, work func(ctx context.Context, progress func(line string)) error, done func(err error) string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	j.started, j.cancel, j.view = time.Now(), cancel, vm.currentView
+	name := j.name
+	vm.jobs = append(vm.jobs, j)
+	vm.jobsChanged()
+	if !j.quiet {
+		vm.setMessage("%s...", name)
+	}
+
+	go func() {
+		err := work(ctx, func(line string) {
+			vm.postJobEvent(false, func() {
+				j.progress = line
+				if !j.cancelled && !j.quiet {
+					vm.setMessage("%s: %s", name, line)
+				}
+			})
+		})
+		cancel()
+		vm.postJobEvent(true, func() {
+			vm.finishJob(j, err)
+			if j.cancelled {
+				vm.setMessage("%s: cancelled", name)
+				return
+			}
+			outcome := done(err)
+			if outcome != "" {
+				vm.setMessage("%s", outcome)
+			}
+			vm.notifyJob(j, outcome)
+		})
+	}()
+}
+
+// finishJob records the end of a job, forgetting the oldest finished ones
+func (vm *ViewManager) finishJob(j *job, err error) {
+	j.finished = time.Now()
+	j.err = err
+
+	finished := 0
+	for i := len(vm.jobs) - 1; i >= 0; i-- {
+		if vm.jobs[i].running() {
+			continue
+		}
+		if finished++; finished > maxFinishedJobs {
+			vm.jobs = append(vm.jobs[:i], vm.jobs[i+1:]...)
+		}
+	}
+	vm.jobsChanged()
+}
+
+// jobsChanged shows the current jobs in the jobs view
+func (vm *ViewManager) jobsChanged() {
+	if view, ok := vm.views[ViewTypeJobs].(*JobsView); ok {
+		view.setJobs(vm.jobs)
+	}
+}
+
+// postJobEvent asks the event loop to run fn with the view manager locked.
+// Progress may be dropped while the event queue is full, the end of a job
+// waits for room.
+func (vm *ViewManager) postJobEvent(wait bool, fn func()) {
+	ev := tcell.NewEventInterrupt(jobEvent(func() {
+		vm.mutex.Lock()
+		defer vm.mutex.Unlock()
+		fn()
+	}))
+	for vm.screen.PostEvent(ev) != nil && wait {
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,247 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// JobsView lists the operations running in the background and those which
+// finished recently, newest first
+type JobsView struct {
+	*BaseView
+	*Scrollable
+	config   *config.Config
+	client   git.Client
+	jobs     []*job
+	selected int
+	repoPath string
+	frame    *Frame
+}
+
+// NewJobsView creates a new jobs view
+func NewJobsView(config *config.Config, client git.Client) *JobsView {
+	return &JobsView{
+		BaseView:   NewBaseView(ViewTypeJobs),
+		Scrollable: NewScrollable(),
+		config:     config,
+		client:     client,
+		frame:      NewFrame(config, "Jobs"),
+	}
+}
+
+// setJobs lists the given jobs, which are in the order they were started,
+// keeping the selected one selected
+func (v *JobsView) setJobs(jobs []*job) {
+	var selectedJob *job
+	if v.selected >= 0 && v.selected < len(v.jobs) {
+		selectedJob = v.jobs[v.selected]
+	}
+
+	v.jobs = make([]*job, 0, len(jobs))
+	for i := len(jobs) - 1; i >= 0; i-- {
+		v.jobs = append(v.jobs, jobs[i])
+	}
+	v.selected = findSelection(len(v.jobs), v.selected, func(i int) bool {
+		return v.jobs[i] == selectedJob
+	})
+}
+
+// Render renders the jobs view
+func (v *JobsView) Render(screen tcell.Screen, x, y, width, height int) error {
+	v.SetPosition(x, y, width, height)
+	v.SetHeight(height - 2) // Account for borders
+
+	// Draw the frame, and its scrollbar once the content is laid out
+	running := 0
+	for _, j := range v.jobs {
+		if j.running() {
+			running++
+		}
+	}
+	v.frame.Title = fmt.Sprintf("Jobs: %d running", running)
+	contentX, contentY, contentWidth, contentHeight := v.frame.Draw(screen, x, y, width, height)
+	defer v.frame.DrawScrollbar(screen, v.Scrollable)
+
+	if contentWidth <= 0 || contentHeight <= 0 {
+		return nil
+	}
+
+	if len(v.jobs) == 0 {
+		msg := "No background jobs"
+		msgX := contentX + (contentWidth-len(msg))/2
+		msgY := contentY + contentHeight/2
+		drawCells(screen, max(contentX, msgX), msgY, contentWidth, textCells(msg, tcell.StyleDefault.Dim(true)), 0, tcell.StyleDefault)
+		return nil
+	}
+
+	v.SetMaxOffset(len(v.jobs) - contentHeight)
+
+	start := v.GetOffset()
+	end := start + contentHeight
+	if end > len(v.jobs) {
+		end = len(v.jobs)
+	}
+
+	for i := start; i < end; i++ {
+		v.drawJob(screen, contentX, contentY+(i-start), contentWidth, i)
+	}
+
+	return nil
+}
+
+// jobState describes how a job is doing, colored accordingly
+func jobState(j *job) (string, tcell.Style) {
+	switch {
+	case j.running() && j.cancelled:
+		return "stopping", tcell.StyleDefault.Foreground(tcell.ColorYellow)
+	case j.running():
+		return "running", tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true)
+	case j.cancelled:
+		return "cancelled", tcell.StyleDefault.Dim(true)
+	case j.err != nil:
+		return "failed", tcell.StyleDefault.Foreground(tcell.ColorRed)
+	}
+	return "done", tcell.StyleDefault.Foreground(tcell.ColorGreen)
+}
+
+// formatDuration rounds a duration to what is worth showing
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(100 * time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
+
+// drawJob draws a job as its state, duration, name and either its last
+// progress or what went wrong
+func (v *JobsView) drawJob(screen tcell.Screen, x, y, width, index int) {
+	j := v.jobs[index]
+
+	state, stateStyle := jobState(j)
+	textStyle := tcell.StyleDefault
+	detailStyle := tcell.StyleDefault.Dim(true)
+	if index == v.selected {
+		background := tcell.ColorDarkBlue
+		if v.IsFocused() {
+			background = tcell.ColorBlue
+		}
+		stateStyle = stateStyle.Background(background)
+		textStyle = textStyle.Background(background)
+		detailStyle = detailStyle.Background(background)
+	}
+
+	cells := textCells(fmt.Sprintf("%-10s", state), stateStyle)
+	cells = append(cells, textCells(fmt.Sprintf("%8s  %s", formatDuration(j.duration()), j.name), textStyle)...)
+	detail := j.progress
+	if j.err != nil && !j.cancelled {
+		detail = j.err.Error()
+	}
+	if detail != "" {
+		cells = append(cells, textCells("  "+detail, detailStyle)...)
+	}
+	if index == v.selected {
+		for cellsWidth(cells) < width {
+			cells = append(cells, cell{' ', textStyle})
+		}
+	}
+
+	drawCells(screen, x, y, width, cells, 0, textStyle)
+}
+
+// HandleKey handles keyboard input
+func (v *JobsView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
+	if !v.IsFocused() {
+		return false
+	}
+
+	switch key {
+	case tcell.KeyUp:
+		v.moveUp()
+		return true
+	case tcell.KeyDown:
+		v.moveDown()
+		return true
+	case tcell.KeyHome:
+		v.ScrollToTop()
+		v.selected = 0
+		return true
+	case tcell.KeyEnd:
+		v.ScrollToBottom()
+		v.selected = max(0, len(v.jobs)-1)
+		return true
+	}
+
+	switch ch {
+	case 'j':
+		v.moveDown()
+		return true
+	case 'k':
+		v.moveUp()
+		return true
+	case 'g':
+		v.ScrollToTop()
+		v.selected = 0
+		return true
+	case 'G':
+		v.ScrollToBottom()
+		v.selected = max(0, len(v.jobs)-1)
+		return true
+	case 'x':
+		v.cancelSelected()
+		return true
+	}
+
+	return false
+}
+
+// cancelSelected stops the selected job if it is still running
+func (v *JobsView) cancelSelected() {
+	if v.selected < 0 || v.selected >= len(v.jobs) {
+		return
+	}
+	if j := v.jobs[v.selected]; j.running() && !j.cancelled {
+		j.cancelled = true
+		j.cancel()
+	}
+}
+
+// moveUp moves selection up
+func (v *JobsView) moveUp() {
+	if v.selected > 0 {
+		v.selected--
+		if v.selected < v.GetOffset() {
+			v.ScrollUp()
+		}
+	}
+}
+
+// moveDown moves selection down
+func (v *JobsView) moveDown() {
+	if v.selected < len(v.jobs)-1 {
+		v.selected++
+		visibleEnd := v.GetOffset() + v.getPageSize()
+		if v.selected >= visibleEnd {
+			v.ScrollDown()
+		}
+	}
+}
+
+// getPageSize returns the number of visible lines
+func (v *JobsView) getPageSize() int {
+	_, _, _, height := v.GetPosition()
+	return height - 2 // Account for borders
+}
+
+// Refresh does nothing, the jobs view being updated as jobs progress
+func (v *JobsView) Refresh() error {
+	return nil
+}
+
+// SetRepoPath sets the repository path
+func (v *JobsView) SetRepoPath(path string) {
+	v.repoPath = path
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runJobEvent runs the next event a background job posted
+func runJobEvent(t *testing.T, screen tcell.Screen) {
+	t.Helper()
+	ev, ok := screen.PollEvent().(*tcell.EventInterrupt)
+	require.True(t, ok)
+	data, ok := ev.Data().(jobEvent)
+	require.True(t, ok)
+	data()
+}
+
+func TestJobsView(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	vm := NewViewManager(screen, cfg, git.NewClient(), NewKeyBindingManager(cfg))
+	vm.SetSize(80, 24)
+
+	var results []error
+	vm.startJob(&job{kind: jobPush, name: "Failing"}, func(ctx context.Context, progress func(string)) error {
+		return errors.New("no network")
+	}, func(err error) string { results = append(results, err); return "" })
+	runJobEvent(t, screen)
+	assert.Equal(t, []error{errors.New("no network")}, results)
+
+	// Running jobs report their progress and can be cancelled
+	vm.startJob(&job{kind: jobPush, name: "Waiting"}, func(ctx context.Context, progress func(string)) error {
+		progress("started")
+		<-ctx.Done()
+		return ctx.Err()
+	}, func(err error) string { results = append(results, err); return "" })
+	runJobEvent(t, screen)
+	assert.Equal(t, "Waiting: started", vm.GetMessage())
+
+	vm.HandleKey(tcell.KeyRune, 'J', 0)
+	assert.Equal(t, ViewTypeJobs, vm.GetCurrentView())
+	assert.Len(t, vm.views[ViewTypeJobs].(*JobsView).jobs, 2)
+	require.NoError(t, vm.Render())
+	lines := screenLines(screen)
+	assert.Contains(t, lines[1], "running")
+	assert.Contains(t, lines[1], "Waiting  started")
+	assert.Contains(t, lines[2], "failed")
+	assert.Contains(t, lines[2], "Failing  no network")
+
+	// The selection stays on the job it was on as new ones are listed
+	assert.Equal(t, 1, vm.views[ViewTypeJobs].(*JobsView).selected)
+	vm.HandleKey(tcell.KeyRune, 'g', 0)
+	vm.HandleKey(tcell.KeyRune, 'x', 0)
+	runJobEvent(t, screen)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "Waiting: cancelled", vm.GetMessage())
+	require.NoError(t, vm.Render())
+	assert.Contains(t, screenLines(screen)[1], "cancelled")
+	assert.Contains(t, screenLines(screen)[0], "Jobs: 0 running")
+}
+
+func TestFinishJobForgetsOldJobs(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	vm := NewViewManager(screen, cfg, git.NewClient(), NewKeyBindingManager(cfg))
+
+	running := &job{name: "running", started: time.Now()}
+	vm.jobs = append(vm.jobs, running)
+	for i := 0; i < maxFinishedJobs+2; i++ {
+		j := &job{name: strings.Repeat("x", i+1), started: time.Now()}
+		vm.jobs = append(vm.jobs, j)
+		vm.finishJob(j, nil)
+	}
+	assert.Len(t, vm.jobs, maxFinishedJobs+1)
+	assert.Same(t, running, vm.jobs[0])
+	assert.Equal(t, "xxx", vm.jobs[1].name)
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "1.2s", formatDuration(1234*time.Millisecond))
+	assert.Equal(t, "2m3s", formatDuration(123456*time.Millisecond))
+}
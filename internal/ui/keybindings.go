@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/gdamore/tcell/v2"
@@ -33,6 +34,12 @@ func NewKeyBindingManager(config *config.Config) *KeyBindingManager {
 	return manager
 }
 
+// Reload rebuilds the bindings after the configuration changed
+func (k *KeyBindingManager) Reload() {
+	k.bindings = make(map[string]*KeyBinding)
+	k.loadDefaultBindings()
+}
+
 // loadDefaultBindings loads the default key bindings
 func (k *KeyBindingManager) loadDefaultBindings() {
 	// Global bindings
@@ -55,6 +62,75 @@ func (k *KeyBindingManager) loadDefaultBindings() {
 		Help:   "Show help",
 	}
 
+	k.bindings["pager"] = &KeyBinding{
+		Action: "pager",
+		Key:    tcell.KeyRune,
+		Rune:   '|',
+		Help:   "Open view content in the external pager",
+	}
+
+	k.bindings["edit"] = &KeyBinding{
+		Action: "edit",
+		Key:    tcell.KeyRune,
+		Rune:   'e',
+		Help:   "Open file under the cursor in the editor",
+	}
+
+	k.bindings["enter"] = &KeyBinding{
+		Action: "enter",
+		Key:    tcell.KeyEnter,
+		Help:   "Open the item under the cursor",
+	}
+
+	k.bindings["backport"] = &KeyBinding{
+		Action: "backport",
+		Key:    tcell.KeyRune,
+		Rune:   'B',
+		Help:   "Cherry-pick the selected commit onto another branch",
+	}
+
+	k.bindings["fixup"] = &KeyBinding{
+		Action: "fixup",
+		Key:    tcell.KeyRune,
+		Rune:   'f',
+		Help:   "Commit staged changes as a fixup of the selected commit",
+	}
+
+	k.bindings["delete-refs"] = &KeyBinding{
+		Action: "delete-refs",
+		Key:    tcell.KeyRune,
+		Rune:   'D',
+		Help:   "Delete the marked branches and tags after confirmation",
+	}
+
+	k.bindings["fetch"] = &KeyBinding{
+		Action: "fetch",
+		Key:    tcell.KeyRune,
+		Rune:   'F',
+		Help:   "Fetch all remotes",
+	}
+
+	k.bindings["push"] = &KeyBinding{
+		Action: "push",
+		Key:    tcell.KeyRune,
+		Rune:   'P',
+		Help:   "Push the selected branch or tag, or the current branch, to its remote",
+	}
+
+	k.bindings["delete-remote"] = &KeyBinding{
+		Action: "delete-remote",
+		Key:    tcell.KeyRune,
+		Rune:   'X',
+		Help:   "Delete the selected branch or tag from its remote",
+	}
+
+	k.bindings["file-log"] = &KeyBinding{
+		Action: "file-log",
+		Key:    tcell.KeyRune,
+		Rune:   'H',
+		Help:   "Show the last commits of the selected file, Enter opening its history",
+	}
+
 	// View switching
 	k.bindings["status"] = &KeyBinding{
 		Action: "status",
@@ -86,6 +162,32 @@ func (k *KeyBindingManager) loadDefaultBindings() {
 		Rune:   'r',
 		Help:   "Show refs view",
 	}
+	k.bindings["jobs"] = &KeyBinding{
+		Action: "jobs",
+		Key:    tcell.KeyRune,
+		Rune:   'J',
+		Help:   "Show background jobs view",
+	}
+
+	// Search
+	k.bindings["search"] = &KeyBinding{
+		Action: "search",
+		Key:    tcell.KeyRune,
+		Rune:   '/',
+		Help:   "Search commit messages and authors",
+	}
+	k.bindings["search-next"] = &KeyBinding{
+		Action: "search-next",
+		Key:    tcell.KeyRune,
+		Rune:   'n',
+		Help:   "Select the next search match",
+	}
+	k.bindings["search-prev"] = &KeyBinding{
+		Action: "search-prev",
+		Key:    tcell.KeyRune,
+		Rune:   'N',
+		Help:   "Select the previous search match",
+	}
 
 	// Navigation
 	k.bindings["up"] = &KeyBinding{
@@ -120,6 +222,12 @@ func (k *KeyBindingManager) loadDefaultBindings() {
 		Rune:   'G',
 		Help:   "Move to bottom",
 	}
+	k.bindings["parent"] = &KeyBinding{
+		Action: "parent",
+		Key:    tcell.KeyRune,
+		Rune:   ',',
+		Help:   "Move to the parent of the selected commit",
+	}
 
 	// Staging operations
 	k.bindings["stage"] = &KeyBinding{
@@ -140,6 +248,30 @@ func (k *KeyBindingManager) loadDefaultBindings() {
 		Rune:   'A',
 		Help:   "Stage all files",
 	}
+	k.bindings["edit-hunk"] = &KeyBinding{
+		Action: "edit-hunk",
+		Key:    tcell.KeyRune,
+		Rune:   'E',
+		Help:   "Edit the hunk before staging it",
+	}
+	k.bindings["stage-hunk"] = &KeyBinding{
+		Action: "stage-hunk",
+		Key:    tcell.KeyRune,
+		Rune:   'S',
+		Help:   "Stage/unstage the hunk under the cursor",
+	}
+	k.bindings["discard-hunk"] = &KeyBinding{
+		Action: "discard-hunk",
+		Key:    tcell.KeyRune,
+		Rune:   '!',
+		Help:   "Discard the hunk under the cursor",
+	}
+	k.bindings["apply-hunk"] = &KeyBinding{
+		Action: "apply-hunk",
+		Key:    tcell.KeyRune,
+		Rune:   'C',
+		Help:   "Apply the hunk of a commit or stash to the worktree",
+	}
 	k.bindings["unstage-all"] = &KeyBinding{
 		Action: "unstage-all",
 		Key:    tcell.KeyRune,
@@ -158,6 +290,59 @@ func (k *KeyBindingManager) loadDefaultBindings() {
 		Rune:   'c',
 		Help:   "Commit staged changes",
 	}
+	k.bindings["diff-unstaged"] = &KeyBinding{
+		Action: "diff-unstaged",
+		Key:    tcell.KeyRune,
+		Rune:   '1',
+		Help:   "Show the unstaged changes of the file",
+	}
+	k.bindings["diff-staged"] = &KeyBinding{
+		Action: "diff-staged",
+		Key:    tcell.KeyRune,
+		Rune:   '2',
+		Help:   "Show the staged changes of the file",
+	}
+	k.bindings["mark-line"] = &KeyBinding{
+		Action: "mark-line",
+		Key:    tcell.KeyRune,
+		Rune:   'v',
+		Help:   "Mark the line under the cursor as one end of the lines to stage",
+	}
+	k.bindings["stage-lines"] = &KeyBinding{
+		Action: "stage-lines",
+		Key:    tcell.KeyRune,
+		Rune:   'V',
+		Help:   "Stage/unstage the lines from the marked one to the cursor",
+	}
+	k.bindings["diff-toggle-staged"] = &KeyBinding{
+		Action: "diff-toggle-staged",
+		Key:    tcell.KeyTab,
+		Help:   "Switch between the staged and unstaged changes of the file",
+	}
+	k.bindings["toggle-eol-changes"] = &KeyBinding{
+		Action: "toggle-eol-changes",
+		Key:    tcell.KeyRune,
+		Rune:   'L',
+		Help:   "Hide or show lines whose line ending alone changed",
+	}
+	k.bindings["review-note"] = &KeyBinding{
+		Action: "review-note",
+		Key:    tcell.KeyRune,
+		Rune:   'M',
+		Help:   "Leave a review note on the hunk or file under the cursor",
+	}
+	k.bindings["copy-hash"] = &KeyBinding{
+		Action: "copy-hash",
+		Key:    tcell.KeyRune,
+		Rune:   'y',
+		Help:   "Copy the hash of the commit under the cursor",
+	}
+	k.bindings["show-commit"] = &KeyBinding{
+		Action: "show-commit",
+		Key:    tcell.KeyRune,
+		Rune:   'o',
+		Help:   "Open the commit under the cursor in the diff view",
+	}
 
 	// Load custom bindings from config
 	k.loadCustomBindings()
@@ -177,13 +362,31 @@ func (k *KeyBindingManager) loadCustomBindings() {
 			existing.Key = key
 			existing.Rune = rune
 			existing.Mods = mods
+		} else if strings.HasPrefix(action, "!") {
+			// External command, run with placeholders expanded
+			k.bindings[action] = &KeyBinding{
+				Action: action,
+				Key:    key,
+				Rune:   rune,
+				Mods:   mods,
+				Help:   "Run " + action[1:],
+			}
 		}
 	}
 }
 
 // parseBinding parses a binding string into key components
 func (k *KeyBindingManager) parseBinding(binding string) (tcell.Key, rune, tcell.ModMask) {
-	binding = strings.ToLower(binding)
+	// Single characters are case sensitive, as R and r are different keys
+	if len(binding) == 1 {
+		return tcell.KeyRune, rune(binding[0]), 0
+	}
+
+	// Accept the <Enter> and <C-x> notations of tigrc
+	binding = strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(binding, "<"), ">"))
+	if strings.HasPrefix(binding, "c-") {
+		binding = "ctrl-" + strings.TrimPrefix(binding, "c-")
+	}
 	
 	var mods tcell.ModMask
 	
@@ -252,8 +455,16 @@ func (k *KeyBindingManager) GetAllBindings() map[string]*KeyBinding {
 
 // MatchEvent matches a keyboard event to a key binding
 func (k *KeyBindingManager) MatchEvent(key tcell.Key, ch rune, mod tcell.ModMask) (string, bool) {
-	for action, binding := range k.bindings {
-		if k.matches(binding, key, ch, mod) {
+	// Walk the actions in a stable order so that keys shared by several
+	// actions always resolve the same way
+	actions := make([]string, 0, len(k.bindings))
+	for action := range k.bindings {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	for _, action := range actions {
+		if k.matches(k.bindings[action], key, ch, mod) {
 			return action, true
 		}
 	}
@@ -281,10 +492,14 @@ func (k *KeyBindingManager) GetHelpText() []string {
 	
 	// Group bindings by category
 	categories := map[string][]string{
-		"Global":    {"quit", "refresh", "help"},
-		"Views":     {"status", "diff", "log", "tree", "refs"},
-		"Navigation":{"up", "down", "page-up", "page-down", "top", "bottom"},
-		"Staging":   {"stage", "unstage", "stage-all", "unstage-all", "discard", "commit"},
+		"Global":    {"quit", "refresh", "help", "pager", "edit", "enter", "copy-hash", "show-commit"},
+		"Views":     {"status", "diff", "log", "tree", "refs", "jobs", "file-log", "toggle-eol-changes"},
+		"Navigation":{"up", "down", "page-up", "page-down", "top", "bottom", "parent"},
+		"Search":    {"search", "search-next", "search-prev"},
+		"Staging":   {"stage", "unstage", "edit-hunk", "stage-hunk", "mark-line", "stage-lines", "discard-hunk", "apply-hunk", "stage-all", "unstage-all", "discard", "commit"},
+		"History":   {"backport", "fixup"},
+		"Refs":      {"delete-refs", "fetch", "push", "delete-remote"},
+		"Review":    {"review-note"},
 	}
 	
 	for category, actions := range categories {
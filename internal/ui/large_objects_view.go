@@ -0,0 +1,293 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// defaultLargeObjects is how many blobs :large-objects lists unless told
+const defaultLargeObjects = 50
+
+// LargeObjectsView lists the largest blobs of the history with the commits
+// which introduced them, to tell what to purge or move to LFS
+type LargeObjectsView struct {
+	*BaseView
+	*Scrollable
+	config   *config.Config
+	client   git.Client
+	objects  []git.LargeObject
+	err      error
+	scan     *job // Walking the history for the blobs listed
+	selected int
+	repoPath string
+	frame    *Frame
+}
+
+// NewLargeObjectsView creates a new large objects view
+func NewLargeObjectsView(config *config.Config, client git.Client) *LargeObjectsView {
+	return &LargeObjectsView{
+		BaseView:   NewBaseView(ViewTypeLargeObjects),
+		Scrollable: NewScrollable(),
+		config:     config,
+		client:     client,
+		frame:      NewFrame(config, "Large objects"),
+	}
+}
+
+// Render renders the large objects view
+func (v *LargeObjectsView) Render(screen tcell.Screen, x, y, width, height int) error {
+	v.SetPosition(x, y, width, height)
+	v.SetHeight(height - 2) // Account for borders
+
+	scanning := v.scanning()
+	v.frame.Title = "Large objects"
+	if !scanning && len(v.objects) > 0 {
+		var total int64
+		for _, object := range v.objects {
+			total += object.Size
+		}
+		v.frame.Title = fmt.Sprintf("Large objects: %d blobs, %s", len(v.objects), git.FormatSize(total))
+	}
+	contentX, contentY, contentWidth, contentHeight := v.frame.Draw(screen, x, y, width, height)
+	defer v.frame.DrawScrollbar(screen, v.Scrollable)
+
+	if contentWidth <= 0 || contentHeight <= 0 {
+		return nil
+	}
+
+	if scanning || v.err != nil || len(v.objects) == 0 {
+		msg := "No blobs in the history"
+		switch {
+		case scanning && v.scan.progress != "":
+			msg = "Finding large objects: " + v.scan.progress
+		case scanning:
+			msg = "Finding large objects..."
+		case v.scan != nil && v.scan.cancelled:
+			msg = "Cancelled"
+		case v.err != nil:
+			msg = v.err.Error()
+		}
+		msgX := contentX + (contentWidth-len(msg))/2
+		msgY := contentY + contentHeight/2
+		drawCells(screen, max(contentX, msgX), msgY, contentWidth, textCells(msg, tcell.StyleDefault.Dim(true)), 0, tcell.StyleDefault)
+		return nil
+	}
+
+	v.SetMaxOffset(len(v.objects) - contentHeight)
+
+	start := v.GetOffset()
+	end := min(start+contentHeight, len(v.objects))
+	for i := start; i < end; i++ {
+		v.drawObject(screen, contentX, contentY+(i-start), contentWidth, i)
+	}
+
+	return nil
+}
+
+// scanning returns whether the history is being walked for the blobs to
+// list
+func (v *LargeObjectsView) scanning() bool {
+	return v.scan != nil && v.scan.running()
+}
+
+// drawObject draws a blob as its size, the commit which introduced it, its
+// path and the summary of that commit
+func (v *LargeObjectsView) drawObject(screen tcell.Screen, x, y, width, index int) {
+	object := v.objects[index]
+
+	sizeStyle := tcell.StyleDefault.Foreground(tcell.ColorYellow)
+	hashStyle := tcell.StyleDefault.Foreground(tcell.ColorTeal)
+	textStyle := tcell.StyleDefault
+	detailStyle := tcell.StyleDefault.Dim(true)
+	if index == v.selected {
+		background := tcell.ColorDarkBlue
+		if v.IsFocused() {
+			background = tcell.ColorBlue
+		}
+		sizeStyle = sizeStyle.Background(background)
+		hashStyle = hashStyle.Background(background)
+		textStyle = textStyle.Background(background)
+		detailStyle = detailStyle.Background(background)
+	}
+
+	commit := "-"
+	if object.Commit != "" {
+		commit = v.client.AbbrevHash(object.Commit)
+	}
+	cells := textCells(fmt.Sprintf("%10s  ", git.FormatSize(object.Size)), sizeStyle)
+	cells = append(cells, textCells(commit, hashStyle)...)
+	cells = append(cells, textCells("  "+object.Path, textStyle)...)
+	if object.Summary != "" {
+		cells = append(cells, textCells("  "+object.Summary, detailStyle)...)
+	}
+	if index == v.selected {
+		for cellsWidth(cells) < width {
+			cells = append(cells, cell{' ', textStyle})
+		}
+	}
+
+	drawCells(screen, x, y, width, cells, 0, textStyle)
+}
+
+// HandleKey handles keyboard input
+func (v *LargeObjectsView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
+	if !v.IsFocused() {
+		return false
+	}
+
+	switch key {
+	case tcell.KeyUp:
+		v.moveUp()
+		return true
+	case tcell.KeyDown:
+		v.moveDown()
+		return true
+	case tcell.KeyHome:
+		v.ScrollToTop()
+		v.selected = 0
+		return true
+	case tcell.KeyEnd:
+		v.ScrollToBottom()
+		v.selected = max(0, len(v.objects)-1)
+		return true
+	}
+
+	switch ch {
+	case 'j':
+		v.moveDown()
+		return true
+	case 'k':
+		v.moveUp()
+		return true
+	case 'g':
+		v.ScrollToTop()
+		v.selected = 0
+		return true
+	case 'G':
+		v.ScrollToBottom()
+		v.selected = max(0, len(v.objects)-1)
+		return true
+	}
+
+	return false
+}
+
+// moveUp moves selection up
+func (v *LargeObjectsView) moveUp() {
+	if v.selected > 0 {
+		v.selected--
+		if v.selected < v.GetOffset() {
+			v.ScrollUp()
+		}
+	}
+}
+
+// moveDown moves selection down
+func (v *LargeObjectsView) moveDown() {
+	if v.selected < len(v.objects)-1 {
+		v.selected++
+		visibleEnd := v.GetOffset() + v.getPageSize()
+		if v.selected >= visibleEnd {
+			v.ScrollDown()
+		}
+	}
+}
+
+// getPageSize returns the number of visible lines
+func (v *LargeObjectsView) getPageSize() int {
+	_, _, _, height := v.GetPosition()
+	return height - 2 // Account for borders
+}
+
+// Refresh keeps the results, walking the history again being slow: they
+// stay until :large-objects is run again
+func (v *LargeObjectsView) Refresh() error {
+	return nil
+}
+
+// GetSelectedObject returns the selected blob
+func (v *LargeObjectsView) GetSelectedObject() *git.LargeObject {
+	if v.selected < 0 || v.selected >= len(v.objects) {
+		return nil
+	}
+	return &v.objects[v.selected]
+}
+
+// DiffRange returns the commit introducing the selected blob, limited to
+// its path
+func (v *LargeObjectsView) DiffRange() (string, []string, error) {
+	object := v.GetSelectedObject()
+	if object == nil {
+		return "", nil, fmt.Errorf("no blob selected")
+	}
+	if object.Commit == "" {
+		return "", nil, fmt.Errorf("no commit adds %s", v.client.AbbrevHash(object.Hash))
+	}
+	return object.Commit + "^!", []string{object.Path}, nil
+}
+
+// Selection returns the selected blob's commit and path for placeholder
+// expansion
+func (v *LargeObjectsView) Selection() Selection {
+	var sel Selection
+	if object := v.GetSelectedObject(); object != nil {
+		sel.Commit = object.Commit
+		sel.File = object.Path
+	}
+	return sel
+}
+
+// setObjects lists the blobs found, or why they were not
+func (v *LargeObjectsView) setObjects(objects []git.LargeObject, err error) {
+	v.objects, v.err = objects, err
+	v.selected = 0
+	v.ScrollToTop()
+}
+
+// SetRepoPath sets the repository path
+func (v *LargeObjectsView) SetRepoPath(path string) {
+	v.repoPath = path
+	v.scan = nil
+	v.setObjects(nil, nil)
+}
+
+// LargeObjectsCommand handles the :large-objects command, which walks the
+// history for its largest blobs in the background
+func (vm *ViewManager) LargeObjectsCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	view, ok := vm.views[ViewTypeLargeObjects].(*LargeObjectsView)
+	if !ok {
+		return fmt.Errorf("large objects view not found")
+	}
+
+	n := defaultLargeObjects
+	if len(args) > 0 {
+		var err error
+		if n, err = strconv.Atoi(args[0]); err != nil || n <= 0 {
+			return fmt.Errorf("usage: large-objects [count]")
+		}
+	}
+	if view.scanning() {
+		return vm.switchView(ViewTypeLargeObjects)
+	}
+
+	view.setObjects(nil, nil)
+	view.scan = &job{kind: jobMaintenance, name: "Finding large objects", quiet: true}
+	var objects []git.LargeObject
+	vm.startJob(view.scan, func(ctx context.Context, progress func(string)) error {
+		var err error
+		objects, err = vm.client.LargeObjects(ctx, n, progress)
+		return err
+	}, func(err error) string {
+		view.setObjects(objects, err)
+		return ""
+	})
+	return vm.switchView(ViewTypeLargeObjects)
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLargeObjectsView(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data.bin"), []byte(strings.Repeat("z", 4096)), 0644))
+	refsTestGit(t, dir, "add", "data.bin")
+	refsTestGit(t, dir, "commit", "--quiet", "-m", "add data")
+	screen := vm.screen
+
+	assert.Error(t, vm.LargeObjectsCommand([]string{"none"}))
+	require.NoError(t, vm.LargeObjectsCommand([]string{"2"}))
+	assert.Equal(t, ViewTypeLargeObjects, vm.GetCurrentView())
+	require.NoError(t, vm.Render())
+	assert.Contains(t, strings.Join(screenLines(screen), "\n"), "Finding large objects...")
+
+	view := vm.views[ViewTypeLargeObjects].(*LargeObjectsView)
+	for view.scanning() {
+		runJobEvent(t, screen)
+	}
+	require.NoError(t, view.err)
+	require.Len(t, view.objects, 2)
+	require.NoError(t, vm.Render())
+	lines := screenLines(screen)
+	assert.Contains(t, lines[1], "Large objects: 2 blobs")
+	assert.Contains(t, lines[2], "4.0 KiB")
+	assert.Contains(t, lines[2], "data.bin  add data")
+
+	// Enter shows the commit adding the blob
+	vm.HandleKey(tcell.KeyEnter, 0, 0)
+	assert.Equal(t, ViewTypeDiff, vm.GetCurrentView())
+	revRange, paths, err := view.DiffRange()
+	require.NoError(t, err)
+	assert.Equal(t, refsTestGit(t, dir, "rev-parse", "HEAD")+"^!", revRange)
+	assert.Equal(t, []string{"data.bin"}, paths)
+}
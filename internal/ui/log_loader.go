@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"context"
+	"math"
+
+	"github.com/azhao1981/tig/internal/git"
+)
+
+// logPageSize is how many commits of the log are taken at a time
+const logPageSize = 100
+
+// logLoader walks the log with git log in the background, the main view
+// taking its commits a page at a time as it is scrolled towards the end.
+// git log is held back while nobody takes the commits, so the history is
+// only walked as far as it is shown.
+type logLoader struct {
+	commits chan *git.Commit
+	errc    chan error // Error of git log, sent once commits is closed
+	cancel  context.CancelFunc
+	opts    git.LogOptions // Options the log is walked with
+	stamp   uint64         // Stamp of the refs when the walk started, 0 if unknown
+	done    bool           // All the commits were taken
+	loading bool           // A page is being taken in the background
+}
+
+// startLogLoader starts walking the log with the given options
+func startLogLoader(client git.Client, opts *git.LogOptions) *logLoader {
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &logLoader{
+		commits: make(chan *git.Commit, logPageSize),
+		errc:    make(chan error, 1),
+		cancel:  cancel,
+		opts:    *opts,
+	}
+	l.stamp, _ = client.RefsStamp()
+	go func() {
+		l.errc <- client.StreamCommits(ctx, opts, l.commits)
+	}()
+	return l
+}
+
+// next takes up to n commits, waiting for git log to list them, and
+// returns whether git log listed them all. It doesn't touch the state of
+// the loader, being run off the event loop.
+func (l *logLoader) next(n int) ([]*git.Commit, bool, error) {
+	var page []*git.Commit
+	for len(page) < n {
+		commit, ok := <-l.commits
+		if !ok {
+			return page, true, <-l.errc
+		}
+		page = append(page, commit)
+	}
+	return page, false, nil
+}
+
+// current returns whether the loader walks the log the options give as it
+// is now, none of the refs having moved since the walk started
+func (l *logLoader) current(client git.Client, opts *git.LogOptions) bool {
+	if l.stamp == 0 || l.opts != *opts {
+		return false
+	}
+	stamp, err := client.RefsStamp()
+	return err == nil && stamp == l.stamp
+}
+
+// stop stops git log, the commits not taken yet being dropped
+func (l *logLoader) stop() {
+	l.cancel()
+}
+
+// loadMore takes the next page of the log in the background once the
+// selection gets near the last commit taken, the view being extended from
+// the event loop
+func (v *MainView) loadMore() {
+	l := v.loader
+	if l == nil || l.done || l.loading || v.post == nil || v.selected < len(v.commits)-logPageSize/2 {
+		return
+	}
+
+	l.loading = true
+	go func() {
+		page, done, err := l.next(logPageSize)
+		v.post(func() error {
+			l.loading = false
+			if v.loader != l {
+				return nil
+			}
+			l.done = done
+			v.commits = append(v.commits, page...)
+			// Keep taking pages when one was scrolled past meanwhile
+			v.loadMore()
+			return err
+		})
+	}()
+}
+
+// loadAll takes the rest of the log right away, for the whole history to
+// be searched. A page being taken in the background is dropped along with
+// its loader, the log being walked again by a new one.
+func (v *MainView) loadAll() error {
+	l := v.loader
+	if l == nil || l.done {
+		return nil
+	}
+
+	if !l.loading {
+		page, done, err := l.next(math.MaxInt)
+		l.done = done
+		v.commits = append(v.commits, page...)
+		return err
+	}
+
+	l.stop()
+	l = startLogLoader(v.client, v.logOptions())
+	v.loader = l
+	selected := v.GetSelectedCommit()
+	commits, done, err := l.next(math.MaxInt)
+	l.done = done
+	v.commits = commits
+	v.index = nil
+	if selected != nil {
+		v.selectCommit(selected.Hash)
+	}
+	return err
+}
+
+// postLogPage runs fn on the event loop, showing the error of taking a
+// page of the log
+func (vm *ViewManager) postLogPage(fn func() error) {
+	vm.postJobEvent(true, func() {
+		if err := fn(); err != nil {
+			vm.setMessage("%v", err)
+		}
+	})
+}
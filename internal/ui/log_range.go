@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/azhao1981/tig/internal/git"
+)
+
+// visualKeys are the keys of the main view acting on the visual range
+var visualKeys = map[rune]string{
+	's': "range-diffstat",
+	'p': "format-patch",
+	'r': "rebase-range",
+}
+
+// keyAction returns the action of a key the main view handles itself: V
+// starting or leaving visual mode, the keys of the visual range while in
+// it, and outside of it those acting on the selected commit
+func (v *MainView) keyAction(ch rune) (string, bool) {
+	if ch == 'V' {
+		return "visual", true
+	}
+	if v.anchor == "" {
+		action, ok := commitKeys[ch]
+		return action, ok
+	}
+	action, ok := visualKeys[ch]
+	return action, ok
+}
+
+// toggleVisual starts a visual range at the selected commit, which j and k
+// then extend, or leaves visual mode
+func (v *MainView) toggleVisual() bool {
+	if v.anchor != "" {
+		v.anchor = ""
+		return false
+	}
+	if commit := v.GetSelectedCommit(); commit != nil {
+		v.anchor = commit.Hash
+	}
+	return v.anchor != ""
+}
+
+// inVisualRange returns whether the commit at a position of the log is
+// part of the visual range
+func (v *MainView) inVisualRange(i int) bool {
+	if v.anchor == "" {
+		return false
+	}
+	anchor, ok := v.commitPosition(v.anchor)
+	return ok && i >= min(anchor, v.selected) && i <= max(anchor, v.selected)
+}
+
+// visualRange returns the oldest and newest commits of the visual range,
+// the selected commit alone outside of visual mode
+func (v *MainView) visualRange() (oldest, newest *git.Commit) {
+	first, last := v.selected, v.selected
+	if anchor, ok := v.commitPosition(v.anchor); ok && v.anchor != "" {
+		first, last = min(anchor, v.selected), max(anchor, v.selected)
+	}
+	return v.commits[last], v.commits[first]
+}
+
+// rangeBase returns the parent of the oldest commit of the visual range,
+// empty when the range starts at a root commit
+func rangeBase(oldest *git.Commit) string {
+	if len(oldest.Parents) == 0 {
+		return ""
+	}
+	return oldest.Parents[0]
+}
+
+// runRangeAction runs the action of a key of the main view starting or
+// acting on the visual range. Exporting the range prompts for the
+// directory of the patches with :format-patch.
+func (vm *ViewManager) runRangeAction(action string) {
+	mainView, ok := vm.views[ViewTypeMain].(*MainView)
+	if !ok || mainView.GetSelectedCommit() == nil {
+		return
+	}
+
+	var err error
+	switch action {
+	case "visual":
+		if mainView.toggleVisual() {
+			vm.setMessage("Visual mode, j and k extend the range: Enter diffs it, s shows its diffstat, p exports it, r rebases it")
+		} else {
+			vm.setMessage("Left visual mode")
+		}
+	case "range-diffstat":
+		err = vm.showRangeDiffStat(mainView)
+	case "format-patch":
+		vm.commandRequest = "format-patch "
+	case "rebase-range":
+		err = vm.rebaseRange(mainView)
+	}
+	if err != nil {
+		vm.setMessage("%v", err)
+	}
+}
+
+// showRangeDiffStat shows the files the visual range changes in the
+// diffstat view
+func (vm *ViewManager) showRangeDiffStat(mainView *MainView) error {
+	oldest, newest := mainView.visualRange()
+	base := rangeBase(oldest)
+	if base == "" {
+		return fmt.Errorf("%s has no parent to diff the range against", vm.client.AbbrevHash(oldest.Hash))
+	}
+
+	view, ok := vm.views[ViewTypeDiffStat].(*DiffStatView)
+	if !ok {
+		return fmt.Errorf("diffstat view not found")
+	}
+	if err := view.SetRange(base + ".." + newest.Hash); err != nil {
+		return err
+	}
+	return vm.switchView(ViewTypeDiffStat)
+}
+
+// rebaseRange rebases the visual range interactively, git taking over the
+// terminal. The range has to be part of the current branch.
+func (vm *ViewManager) rebaseRange(mainView *MainView) error {
+	oldest, newest := mainView.visualRange()
+	head, err := vm.client.ResolveRevision("HEAD")
+	if err != nil {
+		return err
+	}
+	if base, err := vm.client.MergeBase(newest.Hash, head); err != nil || base != newest.Hash {
+		return fmt.Errorf("%s is not part of the current branch", vm.client.AbbrevHash(newest.Hash))
+	}
+
+	args := []string{"git", "rebase", "--interactive", "--root"}
+	if base := rangeBase(oldest); base != "" {
+		args = []string{"git", "rebase", "--interactive", base}
+	}
+	mainView.anchor = ""
+	return vm.runExternal(args)
+}
+
+// FormatPatchCommand handles the :format-patch command, writing the
+// commits of the visual range, or the selected commit, as patches in the
+// given directory
+func (vm *ViewManager) FormatPatchCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if len(args) != 1 {
+		return fmt.Errorf("usage: format-patch <directory>")
+	}
+	mainView, ok := vm.views[ViewTypeMain].(*MainView)
+	if !ok {
+		return fmt.Errorf("main view not found")
+	}
+	if mainView.GetSelectedCommit() == nil {
+		return fmt.Errorf("no commit selected")
+	}
+
+	oldest, newest := mainView.visualRange()
+	files, err := vm.client.FormatPatches(rangeBase(oldest), newest.Hash, args[0])
+	if err != nil {
+		return err
+	}
+	vm.setMessage("Wrote %d patches to %s", len(files), args[0])
+	return nil
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVisualRange(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	refsTestGit(t, dir, "branch", "--quiet", "-D", "topic")
+	base := refsTestGit(t, dir, "rev-parse", "HEAD")
+	for _, name := range []string{"two", "three"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name+".txt"), []byte(name+"\n"), 0644))
+		refsTestGit(t, dir, "add", name+".txt")
+		refsTestGit(t, dir, "commit", "--quiet", "-m", name)
+	}
+	three := refsTestGit(t, dir, "rev-parse", "HEAD")
+	require.NoError(t, vm.refreshAll())
+	mainView := vm.views[ViewTypeMain].(*MainView)
+	require.True(t, mainView.selectCommit(three))
+
+	// The range extends from where V was pressed to the cursor
+	vm.HandleKey(tcell.KeyRune, 'V', 0)
+	assert.Equal(t, hintContextRange, mainView.HintContext())
+	vm.HandleKey(tcell.KeyRune, 'j', 0)
+	oldest, newest := mainView.visualRange()
+	assert.Equal(t, "two", oldest.Summary)
+	assert.Equal(t, three, newest.Hash)
+	assert.True(t, mainView.inVisualRange(0))
+	assert.False(t, mainView.inVisualRange(2))
+
+	vm.HandleKey(tcell.KeyEnter, 0, 0)
+	require.Equal(t, ViewTypeDiff, vm.GetCurrentView())
+	revRange, _ := vm.views[ViewTypeDiff].(*DiffView).GetRange()
+	assert.Equal(t, base+".."+three, revRange)
+
+	require.NoError(t, vm.SwitchView(ViewTypeMain))
+	vm.HandleKey(tcell.KeyRune, 's', 0)
+	require.Equal(t, ViewTypeDiffStat, vm.GetCurrentView())
+	assert.Len(t, vm.views[ViewTypeDiffStat].(*DiffStatView).stats, 2)
+
+	require.NoError(t, vm.SwitchView(ViewTypeMain))
+	vm.HandleKey(tcell.KeyRune, 'p', 0)
+	assert.Equal(t, "format-patch ", vm.commandRequest)
+	vm.commandRequest = ""
+	patches := t.TempDir()
+	require.NoError(t, vm.FormatPatchCommand([]string{patches}))
+	assert.Equal(t, "Wrote 2 patches to "+patches, vm.GetMessage())
+
+	// Rebasing is refused in read-only mode, V is not
+	vm.config.General.ReadOnly = true
+	vm.HandleKey(tcell.KeyRune, 'r', 0)
+	assert.Equal(t, "rebase-range is disabled in read-only mode", vm.GetMessage())
+	vm.HandleKey(tcell.KeyRune, 'V', 0)
+	assert.Equal(t, "Left visual mode", vm.GetMessage())
+	vm.HandleKey(tcell.KeyRune, 'k', 0)
+	vm.HandleKey(tcell.KeyRune, 'V', 0)
+	vm.config.General.ReadOnly = false
+
+	// The oldest commit of the range is dropped from the todo list
+	t.Setenv("GIT_SEQUENCE_EDITOR", "sed -i -e 1s/^pick/drop/")
+	for _, role := range []string{"AUTHOR", "COMMITTER"} {
+		t.Setenv("GIT_"+role+"_NAME", "Test")
+		t.Setenv("GIT_"+role+"_EMAIL", "test@example.com")
+	}
+	vm.HandleKey(tcell.KeyRune, 'j', 0)
+	vm.HandleKey(tcell.KeyRune, 'r', 0)
+	assert.Equal(t, "three", refsTestGit(t, dir, "log", "-1", "--format=%s"))
+	assert.Equal(t, base, refsTestGit(t, dir, "rev-parse", "HEAD~"))
+	assert.Empty(t, mainView.anchor)
+}
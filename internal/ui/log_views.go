@@ -0,0 +1,235 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// logViewPicker is the popup listing the saved log views, shown over the
+// view it was opened from
+type logViewPicker struct {
+	names    []string
+	selected int
+}
+
+// logFilter returns the filters of a saved view
+func logFilter(view config.LogView) git.LogFilter {
+	return git.LogFilter{
+		Author: view.Author,
+		Path:   view.Path,
+		Since:  view.Since,
+		Until:  view.Until,
+		Branch: view.Branch,
+	}
+}
+
+// savedLogView returns the filters of the log as they are saved
+func savedLogView(filter git.LogFilter) config.LogView {
+	return config.LogView{
+		Author: filter.Author,
+		Path:   filter.Path,
+		Since:  filter.Since,
+		Until:  filter.Until,
+		Branch: filter.Branch,
+	}
+}
+
+// filterArgs returns the git log options applying the filters other than
+// the branch, the path last
+func filterArgs(filter git.LogFilter) []string {
+	var args []string
+	if filter.Author != "" {
+		args = append(args, "--author="+filter.Author)
+	}
+	if filter.Since != "" {
+		args = append(args, "--since="+filter.Since)
+	}
+	if filter.Until != "" {
+		args = append(args, "--until="+filter.Until)
+	}
+	if filter.Path != "" {
+		args = append(args, "--", filter.Path)
+	}
+	return args
+}
+
+// SetFilter limits the log to the commits the filters let through, titled
+// with the name of the view they were saved as, if any. The file the log
+// was limited to is cleared.
+func (v *MainView) SetFilter(name string, filter git.LogFilter) {
+	v.path = ""
+	v.filter = filter
+	switch {
+	case filter.IsEmpty():
+		v.frame.Title = "Log"
+	case name != "":
+		v.frame.Title = "Log: " + name
+	default:
+		v.frame.Title = "Log: " + savedLogView(filter).String()
+	}
+}
+
+// showFilteredLog switches to the main view, filtered as given
+func (vm *ViewManager) showFilteredLog(name string, filter git.LogFilter) error {
+	mainView, ok := vm.views[ViewTypeMain].(*MainView)
+	if !ok {
+		return fmt.Errorf("main view not found")
+	}
+	mainView.SetFilter(name, filter)
+	mainView.selected = 0
+	mainView.SetOffset(0)
+	if err := mainView.Refresh(); err != nil {
+		return err
+	}
+	if len(mainView.commits) == 0 && !filter.IsEmpty() {
+		vm.setMessage("No commits match the filters")
+	}
+	return vm.switchView(ViewTypeMain)
+}
+
+// LogFilterCommand handles the :log-filter command, which filters the log
+// by the given author, path, since, until and branch, or shows the whole
+// log again without any
+func (vm *ViewManager) LogFilterCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	view, err := config.ParseLogView(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	return vm.showFilteredLog("", logFilter(view))
+}
+
+// LogViewCommand handles the :log-view command, which opens the saved view
+// of the given name, or the picker listing them all
+func (vm *ViewManager) LogViewCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if len(args) > 1 {
+		return fmt.Errorf("usage: log-view [name]")
+	}
+	if len(args) == 1 {
+		view, ok := vm.config.Views.Log[args[0]]
+		if !ok {
+			return fmt.Errorf("no log view named %s", args[0])
+		}
+		return vm.showFilteredLog(args[0], logFilter(view))
+	}
+
+	names := vm.config.LogViewNames()
+	if len(names) == 0 {
+		return fmt.Errorf("no log views saved, filter the log with :log-filter and save it with :save-log-view")
+	}
+	vm.logViewPicker = &logViewPicker{names: names}
+	return nil
+}
+
+// SaveLogViewCommand handles the :save-log-view command, which saves the
+// filters of the log under a name in the user's tigrc
+func (vm *ViewManager) SaveLogViewCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if len(args) != 1 {
+		return fmt.Errorf("usage: save-log-view <name>")
+	}
+	mainView, ok := vm.views[ViewTypeMain].(*MainView)
+	if !ok {
+		return fmt.Errorf("main view not found")
+	}
+	filter := mainView.filter
+	if mainView.path != "" {
+		filter = git.LogFilter{Path: mainView.path}
+	}
+	if filter.IsEmpty() {
+		return fmt.Errorf("the log is not filtered, see :log-filter")
+	}
+
+	if err := vm.config.SaveLogView(args[0], savedLogView(filter)); err != nil {
+		return err
+	}
+	mainView.SetFilter(args[0], filter)
+	vm.setMessage("Saved log view %s", args[0])
+	return nil
+}
+
+// CompleteLogView completes the names of the saved log views
+func (vm *ViewManager) CompleteLogView(word string) []string {
+	vm.mutex.RLock()
+	defer vm.mutex.RUnlock()
+
+	var names []string
+	for _, name := range vm.config.LogViewNames() {
+		if strings.HasPrefix(name, word) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// answerLogViewPicker moves through the log view picker, Enter opening the
+// selected view and Escape closing it
+func (vm *ViewManager) answerLogViewPicker(key tcell.Key, ch rune) {
+	picker := vm.logViewPicker
+	switch {
+	case key == tcell.KeyDown || (key == tcell.KeyRune && ch == 'j'):
+		picker.selected = min(picker.selected+1, len(picker.names)-1)
+	case key == tcell.KeyUp || (key == tcell.KeyRune && ch == 'k'):
+		picker.selected = max(picker.selected-1, 0)
+	case key == tcell.KeyEnter:
+		vm.logViewPicker = nil
+		name := picker.names[picker.selected]
+		if err := vm.showFilteredLog(name, logFilter(vm.config.Views.Log[name])); err != nil {
+			vm.setMessage("%v", err)
+		}
+	case key == tcell.KeyEscape || (key == tcell.KeyRune && ch == 'q'):
+		vm.logViewPicker = nil
+	}
+}
+
+// drawLogViewPicker draws the log view picker over the current view, each
+// view with its filters
+func (vm *ViewManager) drawLogViewPicker() {
+	picker := vm.logViewPicker
+	boxWidth := min(80, vm.width-4)
+	boxHeight := min(len(picker.names)+4, vm.height-2)
+	if boxWidth < 20 || boxHeight < 5 {
+		return
+	}
+	x := (vm.width - boxWidth) / 2
+	y := max(1, (vm.height-boxHeight)/2)
+	drawPopupBox(vm.screen, "Log views", tcell.StyleDefault.Foreground(tcell.ColorYellow), x, y, boxWidth, boxHeight)
+
+	nameWidth := 0
+	for _, name := range picker.names {
+		nameWidth = max(nameWidth, len(name))
+	}
+
+	// The list scrolls to keep the selected view in the box
+	innerX, innerWidth := x+2, boxWidth-4
+	rows := boxHeight - 4
+	start := max(0, picker.selected-rows+1)
+	for i := start; i < len(picker.names) && i < start+rows; i++ {
+		name := picker.names[i]
+		style := tcell.StyleDefault
+		if i == picker.selected {
+			style = style.Background(tcell.ColorBlue).Foreground(tcell.ColorWhite)
+		}
+
+		cells := textCells(fmt.Sprintf("%-*s  ", nameWidth, name), style.Bold(true))
+		cells = append(cells, textCells(vm.config.Views.Log[name].String(), style)...)
+		for cellsWidth(cells) < innerWidth {
+			cells = append(cells, cell{' ', style})
+		}
+		drawCells(vm.screen, innerX, y+1+i-start, innerWidth, cells, 0, style)
+	}
+
+	hint := "Enter: open  Esc: close"
+	drawCells(vm.screen, innerX, y+boxHeight-2, innerWidth, textCells(hint, tcell.StyleDefault.Dim(true)), 0, tcell.StyleDefault)
+}
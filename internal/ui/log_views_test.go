@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogViews(t *testing.T) {
+	_, vm := autostashTestRepo(t)
+	screen := vm.screen
+	vm.config.Path = filepath.Join(t.TempDir(), "tigrc")
+	mainView := vm.views[ViewTypeMain].(*MainView)
+
+	assert.Error(t, vm.LogViewCommand(nil))
+	assert.Error(t, vm.SaveLogViewCommand([]string{"topic-work"}))
+
+	// The filters recreate the log from the branch, of the path
+	require.NoError(t, vm.LogFilterCommand([]string{"branch=topic", "path=file.txt"}))
+	assert.Equal(t, ViewTypeMain, vm.GetCurrentView())
+	require.Len(t, mainView.commits, 2)
+	assert.Equal(t, "topic", mainView.commits[0].Summary)
+	assert.Equal(t, "Log: path=file.txt branch=topic", mainView.frame.Title)
+	_, paths, err := mainView.DiffRange()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"file.txt"}, paths)
+	args, err := mainView.PagerArgs()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--", "file.txt"}, args[len(args)-2:])
+
+	require.NoError(t, vm.SaveLogViewCommand([]string{"topic-work"}))
+	assert.Equal(t, "Saved log view topic-work", vm.GetMessage())
+	assert.Equal(t, "Log: topic-work", mainView.frame.Title)
+	saved, err := os.ReadFile(vm.config.Path)
+	require.NoError(t, err)
+	assert.Equal(t, "view topic-work path=file.txt branch=topic\n", string(saved))
+
+	require.NoError(t, vm.LogFilterCommand([]string{"until=2000-01-01"}))
+	assert.Empty(t, mainView.commits)
+	assert.Equal(t, "No commits match the filters", vm.GetMessage())
+	require.NoError(t, vm.SaveLogViewCommand([]string{"old"}))
+	assert.Equal(t, []string{"old", "topic-work"}, vm.CompleteLogView(""))
+
+	// The picker lists the saved views, Enter opening one
+	require.NoError(t, vm.LogViewCommand(nil))
+	require.NoError(t, vm.Render())
+	text := strings.Join(screenLines(screen), "\n")
+	assert.Contains(t, text, "Log views")
+	assert.Contains(t, text, "old         until=2000-01-01")
+	assert.Contains(t, text, "topic-work  path=file.txt branch=topic")
+	vm.HandleKey(tcell.KeyRune, 'j', 0)
+	vm.HandleKey(tcell.KeyEnter, 0, 0)
+	assert.Nil(t, vm.logViewPicker)
+	assert.Len(t, mainView.commits, 2)
+	assert.Equal(t, "Log: topic-work", mainView.frame.Title)
+
+	require.NoError(t, vm.LogViewCommand([]string{"old"}))
+	assert.Empty(t, mainView.commits)
+	assert.Error(t, vm.LogViewCommand([]string{"missing"}))
+
+	// Without filters the whole log is back
+	require.NoError(t, vm.LogFilterCommand(nil))
+	assert.True(t, mainView.filter.IsEmpty())
+	assert.Equal(t, "Log", mainView.frame.Title)
+	assert.Error(t, vm.LogFilterCommand([]string{"who=me"}))
+}
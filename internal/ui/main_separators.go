@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// separatorStyle is the style of the rows separating the commits of
+// different days
+var separatorStyle = tcell.StyleDefault.Foreground(tcell.ColorGray)
+
+// mainRow is a row of the main view, either a commit or the separator
+// naming the day of the commits below it
+type mainRow struct {
+	commit    int
+	separator string
+}
+
+// commitDay returns the day a commit was authored, as the date column
+// shows it
+func commitDay(commit *git.Commit) string {
+	return commit.Author.Time.Format("2006-01-02")
+}
+
+// separatorBefore returns whether a separator row goes between a commit
+// and the one above it, which is when they were authored on different days
+func (v *MainView) separatorBefore(i int) bool {
+	if !v.config.Views.Main.DateSeparators || i <= 0 || i >= len(v.commits) {
+		return false
+	}
+	return commitDay(v.commits[i-1]) != commitDay(v.commits[i])
+}
+
+// visibleRows returns the rows filling a view of the given height when
+// scrolled to the given commit. No separator is shown above the first
+// commit.
+func (v *MainView) visibleRows(start, height int) []mainRow {
+	var rows []mainRow
+	for i := start; i < len(v.commits) && len(rows) < height; i++ {
+		if i > start && v.separatorBefore(i) {
+			rows = append(rows, mainRow{commit: -1, separator: "── " + commitDay(v.commits[i]) + " ──"})
+			if len(rows) == height {
+				break
+			}
+		}
+		rows = append(rows, mainRow{commit: i})
+	}
+	return rows
+}
+
+// lastPageStart returns the commit the view is scrolled to when the last
+// commit is on the last row
+func (v *MainView) lastPageStart(height int) int {
+	rows := 0
+	for i := len(v.commits) - 1; i >= 0; i-- {
+		rows++
+		if v.separatorBefore(i + 1) {
+			rows++
+		}
+		if rows > height {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// isVisible returns whether a commit is shown when the view is scrolled
+// to the given commit
+func (v *MainView) isVisible(start, height, commit int) bool {
+	for _, row := range v.visibleRows(start, height) {
+		if row.commit == commit {
+			return true
+		}
+	}
+	return false
+}
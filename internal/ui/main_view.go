@@ -3,10 +3,12 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/azhao1981/tig/internal/config"
 	"github.com/azhao1981/tig/internal/git"
+	"github.com/mattn/go-runewidth"
 )
 
 // MainView represents the main commit log view
@@ -16,9 +18,23 @@ type MainView struct {
 	config   *config.Config
 	client   git.Client
 	commits  []*git.Commit
+	index    map[string]int // Positions of the commits by hash
+	indexKey logKey         // Log the index was built for
+	indexed  int            // Number of commits of the log in the index
 	selected int
 	repoPath string
-	box      *DrawBox
+	path     string        // File the log is limited to, empty for the whole log
+	filter   git.LogFilter // Filters of the log, set apart from path
+	anchor   string        // Commit the visual range was started from, empty outside of visual mode
+	loader   *logLoader    // Walks the log, nil until it is first refreshed
+	refs     *git.RefIndex // Refs decorating the commits
+	theme    *Theme        // Colors of the decorations, taken again on each render
+	unborn   bool          // HEAD has no commit yet and nothing else is listed
+	frame    *Frame
+
+	post func(fn func() error) // Runs fn on the event loop, for the pages of the log taken in the background
+
+	run func(action string) // Runs the action of a key starting or acting on the visual range
 }
 
 // NewMainView creates a new main view
@@ -29,7 +45,7 @@ func NewMainView(config *config.Config, client git.Client) *MainView {
 		config:    config,
 		client:    client,
 		commits:   make([]*git.Commit, 0),
-		box:       NewDrawBox("Log", tcell.StyleDefault.Foreground(tcell.ColorWhite)),
+		frame:     NewFrame(config, "Log"),
 	}
 }
 
@@ -38,20 +54,20 @@ func (v *MainView) Render(screen tcell.Screen, x, y, width, height int) error {
 	v.SetPosition(x, y, width, height)
 	v.SetHeight(height - 2) // Account for borders
 	
-	// Draw box
-	v.box.Draw(screen, x, y, width, height)
-	
-	// Draw content area
-	contentX := x + 1
-	contentY := y + 1
-	contentWidth := width - 2
-	contentHeight := height - 2
+	// The status line shows the subject of the selected commit when cut
+	v.theme = newSchemeTheme(v.config)
+	_, _, lineWidth, _ := v.frame.Content(x, y, width, height)
+	v.frame.Status = v.subjectTooltip(lineWidth)
+
+	// Draw the frame, and its scrollbar once the content is laid out
+	contentX, contentY, contentWidth, contentHeight := v.frame.Draw(screen, x, y, width, height)
+	defer v.frame.DrawScrollbar(screen, v.Scrollable)
 	
 	if contentWidth <= 0 || contentHeight <= 0 {
 		return nil
 	}
 
-	// Render commits
+	// Render commits, decorated in the colors of the current scheme
 	v.renderCommits(screen, contentX, contentY, contentWidth, contentHeight)
 	
 	return nil
@@ -64,14 +80,15 @@ func (v *MainView) renderCommits(screen tcell.Screen, x, y, width, height int) {
 		msg := "No commits found"
 		if !v.client.IsRepository() {
 			msg = "Not in a git repository"
+		} else if v.unborn {
+			msg = "No commits yet — create the first commit"
 		}
 		
-		msgX := x + (width-len(msg))/2
+		cells := textCells(msg, tcell.StyleDefault)
+		msgX := x + (width-cellsWidth(cells))/2
 		msgY := y + height/2
 		if msgX >= x && msgY >= y {
-			for i, char := range msg {
-				screen.SetContent(msgX+i, msgY, char, nil, tcell.StyleDefault)
-			}
+			drawCells(screen, msgX, msgY, width-(msgX-x), cells, 0, tcell.StyleDefault)
 		}
 		return
 	}
@@ -90,13 +107,9 @@ func (v *MainView) renderCommits(screen tcell.Screen, x, y, width, height int) {
 		maxVisible = height
 	}
 	
-	v.SetMaxOffset(max(0, len(v.commits) - height))
+	v.SetMaxOffset(v.lastPageStart(height))
 	
 	start := v.GetOffset()
-	end := start + height
-	if end > len(v.commits) {
-		end = len(v.commits)
-	}
 	
 	// Ensure start is not negative
 	if start < 0 {
@@ -109,18 +122,22 @@ func (v *MainView) renderCommits(screen tcell.Screen, x, y, width, height int) {
 		}
 	}
 
+	// Separators may push the selected commit below the view
+	for start < v.selected && !v.isVisible(start, height, v.selected) {
+		start++
+		v.SetOffset(start)
+	}
+
 	// Render each commit
-	for i := start; i < end; i++ {
-		if i < 0 || i >= len(v.commits) {
+	for row, entry := range v.visibleRows(start, height) {
+		lineY := y + row
+		if entry.commit < 0 {
+			drawCells(screen, x, lineY, width, textCells(entry.separator, separatorStyle), 0, tcell.StyleDefault)
 			continue
 		}
-		
+
+		i := entry.commit
 		commit := v.commits[i]
-		lineY := y + (i - start)
-		
-		if lineY >= y+height {
-			break
-		}
 		
 		// Determine style based on selection
 		style := tcell.StyleDefault
@@ -128,6 +145,8 @@ func (v *MainView) renderCommits(screen tcell.Screen, x, y, width, height int) {
 			style = style.Background(tcell.ColorBlue).Foreground(tcell.ColorWhite)
 		} else if i == v.selected {
 			style = style.Background(tcell.ColorDarkBlue).Foreground(tcell.ColorWhite)
+		} else if v.inVisualRange(i) {
+			style = style.Background(tcell.ColorDarkSlateGray).Foreground(tcell.ColorWhite)
 		}
 		
 		// Format commit line
@@ -135,90 +154,143 @@ func (v *MainView) renderCommits(screen tcell.Screen, x, y, width, height int) {
 	}
 }
 
-// renderCommitLine renders a single commit line
+// renderCommitLine renders a single commit line, its title cut to what is
+// left of the width
 func (v *MainView) renderCommitLine(screen tcell.Screen, x, y, width int, commit *git.Commit, style tcell.Style) {
 	if width <= 0 {
 		return
 	}
+
+	cells, title := v.commitLine(commit, style)
+	cells = append(cells, textCells(truncateText(title, width-cellsWidth(cells)), style)...)
 	
-	// Build the commit line
-	var parts []string
+	// Fill remaining space with background
+	for cellsWidth(cells) < width {
+		cells = append(cells, cell{' ', style})
+	}
+	drawCells(screen, x, y, width, cells, 0, style)
+}
+
+// commitLine returns the columns of the line of a commit before its title,
+// and the title
+func (v *MainView) commitLine(commit *git.Commit, style tcell.Style) ([]cell, string) {
+	var cells []cell
+	add := func(text string, style tcell.Style) {
+		cells = append(cells, textCells(text, style)...)
+	}
 	
 	// Show graph if enabled
 	if v.config.Views.Main.ShowGraph {
 		// For now, use a simple asterisk for commits
-		parts = append(parts, "*")
+		add("*", style)
 	} else {
-		parts = append(parts, " ")
+		add(" ", style)
 	}
 	
 	// Show refs if enabled
 	if v.config.Views.Main.ShowRefs {
-		refs := v.getCommitRefs(commit.Hash)
-		if len(refs) > 0 {
-			parts = append(parts, strings.Join(refs, ", ")+" ")
-		}
+		cells = append(cells, v.commitDecorations(commit.Hash, style)...)
 	}
 	
 	// Show ID if enabled
 	if v.config.Views.Main.ShowID {
-		id := commit.Hash
-		if len(id) > 7 {
-			id = id[:7]
-		}
-		parts = append(parts, id+" ")
+		add(v.client.AbbrevHash(commit.Hash)+" ", style)
 	}
 	
 	// Show date if enabled
 	if v.config.Views.Main.ShowDate {
-		date := commit.Author.Time.Format("2006-01-02")
-		parts = append(parts, date+" ")
+		date := formatDate(v.config, commit.Author.Time, time.Now())
+		add(date+" ", style)
 	}
 	
-	// Show author if enabled
+	// Show the avatar of the author if enabled, in its colors even when
+	// selected
+	if v.config.Views.Main.ShowAvatar {
+		add(avatarInitials(commit.Author.Name), avatarStyle(commit.Author))
+		add(" ", style)
+	}
+	
+	// Show author if enabled, in its own color unless selected
 	if v.config.Views.Main.ShowAuthor {
-		author := commit.Author.Name
-		if len(author) > 20 {
-			author = author[:17] + "..."
+		author := formatAuthor(v.config, commit.Author)
+		// Co-authors only show as a count, the name being cut first
+		if coAuthors := len(commit.CoAuthors()); coAuthors > 0 {
+			suffix := fmt.Sprintf(" +%d", coAuthors)
+			author = truncateText(author, 20-len(suffix)) + suffix
 		}
-		parts = append(parts, fmt.Sprintf("%-20s ", author))
+		nameStyle := style
+		if style == tcell.StyleDefault {
+			nameStyle = authorStyle(v.config, commit.Author, style)
+		}
+		add(padText(author, 20)+" ", nameStyle)
 	}
 	
-	// Show commit title
+	// The title is the subject, its lines joined as git does
 	title := commit.Summary
 	if title == "" {
-		title = commit.Message
-		if len(title) > 50 {
-			title = title[:47] + "..."
-		}
+		title, _, _ = strings.Cut(strings.TrimSpace(commit.Message), "\n")
 	}
-	parts = append(parts, title)
-	
-	// Combine parts
-	line := strings.Join(parts, "")
-	if len(line) > width {
-		line = line[:width]
+	return cells, title
+}
+
+// subjectTooltip returns the subject of the selected commit when it is cut
+// in lines of the given width and subject-tooltip is set, to be shown in
+// full on the status line
+func (v *MainView) subjectTooltip(width int) string {
+	if !v.config.Views.Main.SubjectTooltip || v.selected < 0 || v.selected >= len(v.commits) {
+		return ""
 	}
-	
-	// Draw the line
-	for i, char := range line {
-		if x+i >= x+width {
-			break
-		}
-		screen.SetContent(x+i, y, char, nil, style)
+	cells, title := v.commitLine(v.commits[v.selected], tcell.StyleDefault)
+	if cellsWidth(cells)+runewidth.StringWidth(title) <= width {
+		return ""
 	}
-	
-	// Fill remaining space with background
-	for i := len(line); i < width; i++ {
-		screen.SetContent(x+i, y, ' ', nil, style)
+	return title
+}
+
+// decoration is a ref decorating a commit along with the theme color it
+// is shown in
+type decoration struct {
+	text  string
+	color string
+	bold  bool // The current branch, or HEAD when detached
+}
+
+// getCommitRefs returns the refs pointing to a commit as they decorate it:
+// [branch], {remote branch} and <tag>, after HEAD when it is detached at
+// the commit
+func (v *MainView) getCommitRefs(hash string) []decoration {
+	var decorations []decoration
+	if v.refs != nil && v.refs.Head == "" && v.refs.HeadCommit == hash {
+		decorations = append(decorations, decoration{text: "HEAD", color: "head", bold: true})
 	}
+	for _, ref := range v.refs.At(hash) {
+		name := git.ShortRefName(ref.Name)
+		switch ref.Type {
+		case git.RefTypeBranch:
+			decorations = append(decorations, decoration{text: "[" + name + "]", color: "branch", bold: ref.Name == v.refs.Head})
+		case git.RefTypeRemote:
+			decorations = append(decorations, decoration{text: "{" + name + "}", color: "remote"})
+		case git.RefTypeTag:
+			decorations = append(decorations, decoration{text: "<" + name + ">", color: "tag"})
+		}
+	}
+	return decorations
 }
 
-// getCommitRefs returns refs (branches, tags) pointing to this commit
-func (v *MainView) getCommitRefs(hash string) []string {
-	// This is a placeholder - in real implementation, we'd query git for refs
-	// For now, return empty slice
-	return []string{}
+// commitDecorations returns the cells of the refs decorating a commit, in
+// the colors of the theme on the background of the row
+func (v *MainView) commitDecorations(hash string, style tcell.Style) []cell {
+	theme := v.theme
+	if theme == nil {
+		theme = newSchemeTheme(v.config)
+	}
+	var cells []cell
+	for _, d := range v.getCommitRefs(hash) {
+		decorationStyle := style.Foreground(theme.GetColor(d.color)).Bold(d.bold)
+		cells = append(cells, textCells(d.text, decorationStyle)...)
+		cells = append(cells, cell{' ', style})
+	}
+	return cells
 }
 
 // HandleKey handles keyboard input
@@ -247,6 +319,7 @@ func (v *MainView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
 		if v.selected >= len(v.commits) {
 			v.selected = len(v.commits) - 1
 		}
+		v.loadMore()
 		return true
 	case tcell.KeyHome:
 		v.ScrollToTop()
@@ -255,6 +328,14 @@ func (v *MainView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
 	case tcell.KeyEnd:
 		v.ScrollToBottom()
 		v.selected = len(v.commits) - 1
+		v.loadMore()
+		return true
+	}
+
+	if action, ok := v.keyAction(ch); ok && key == tcell.KeyRune {
+		if v.run != nil {
+			v.run(action)
+		}
 		return true
 	}
 
@@ -272,6 +353,7 @@ func (v *MainView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
 	case 'G':
 		v.ScrollToBottom()
 		v.selected = len(v.commits) - 1
+		v.loadMore()
 		return true
 	}
 
@@ -298,6 +380,22 @@ func (v *MainView) moveDown() {
 			v.ScrollDown()
 		}
 	}
+	v.loadMore()
+}
+
+// logOptions returns the options the log is walked with: the commits
+// touching the file the log is limited to, or those the filters let
+// through, or the whole log
+func (v *MainView) logOptions() *git.LogOptions {
+	opts := &git.LogOptions{Order: v.config.General.CommitOrder}
+	if v.path != "" {
+		opts.Path, opts.Follow = v.path, true
+	} else if !v.filter.IsEmpty() {
+		opts.Filter = v.filter
+	} else {
+		opts.All = true
+	}
+	return opts
 }
 
 // getPageSize returns the number of visible lines
@@ -308,32 +406,46 @@ func (v *MainView) getPageSize() int {
 
 // Refresh refreshes the commit list
 func (v *MainView) Refresh() error {
+	opts := v.logOptions()
+	if v.loader != nil && v.loader.current(v.client, opts) {
+		// Nothing moved, the commits taken so far are still the log
+		return nil
+	}
+	if v.loader != nil {
+		v.loader.stop()
+		v.loader = nil
+	}
 	if !v.client.IsRepository() {
 		v.commits = make([]*git.Commit, 0)
 		v.selected = 0
 		return nil
 	}
 
-	repo, err := v.client.GetRepository()
+	// Only the first page is taken again, the rest following as the log
+	// is scrolled
+	var commits []*git.Commit
+	var err error
+	v.loader = startLogLoader(v.client, opts)
+	commits, v.loader.done, err = v.loader.next(logPageSize)
 	if err != nil {
-		return fmt.Errorf("failed to get repository: %w", err)
+		return fmt.Errorf("failed to get commits: %w", err)
 	}
 
-	// Get commits from HEAD
-	commits, err := repo.GetCommits(&git.LogOptions{
-		MaxCount: 100, // Limit to 100 commits for performance
-		All:      true,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to get commits: %w", err)
+	// Keep the selected commit selected when it is still listed
+	var selectedHash string
+	if commit := v.GetSelectedCommit(); commit != nil {
+		selectedHash = commit.Hash
 	}
 
 	v.commits = commits
-	if v.selected >= len(v.commits) {
-		v.selected = len(v.commits) - 1
-	}
-	if v.selected < 0 {
-		v.selected = 0
+	v.index = nil // Refs other than the tip may have moved
+	v.unborn = len(commits) == 0 && v.client.UnbornBranch() != ""
+	v.selected = findSelection(len(v.commits), v.selected, func(i int) bool {
+		return v.commits[i].Hash == selectedHash
+	})
+	v.selectCommit(selectedHash)
+	if _, ok := v.commitPosition(v.anchor); !ok {
+		v.anchor = ""
 	}
 
 	return nil
@@ -347,7 +459,185 @@ func (v *MainView) GetSelectedCommit() *git.Commit {
 	return v.commits[v.selected]
 }
 
+// logKey identifies a log by the commit at its tip and what it was walked
+// with; logs with the same key only differ by how many pages were taken
+type logKey struct {
+	tip    string
+	path   string
+	filter git.LogFilter
+	order  string
+}
+
+// logKey returns the key of the listed log
+func (v *MainView) logKey() logKey {
+	key := logKey{path: v.path, filter: v.filter, order: v.config.General.CommitOrder}
+	if len(v.commits) > 0 {
+		key.tip = v.commits[0].Hash
+	}
+	return key
+}
+
+// commitPosition returns the position of the commit with the given hash in
+// the log. The index is rebuilt when another log is listed, and extended
+// as pages of the same log are taken.
+func (v *MainView) commitPosition(hash string) (int, bool) {
+	if key := v.logKey(); v.index == nil || key != v.indexKey || len(v.commits) < v.indexed {
+		v.index = make(map[string]int, len(v.commits))
+		v.indexKey = key
+		v.indexed = 0
+	}
+	for ; v.indexed < len(v.commits); v.indexed++ {
+		if _, ok := v.index[v.commits[v.indexed].Hash]; !ok {
+			v.index[v.commits[v.indexed].Hash] = v.indexed
+		}
+	}
+
+	i, ok := v.index[hash]
+	return i, ok
+}
+
+// selectCommit selects the listed commit with the given hash, scrolling it
+// into view
+func (v *MainView) selectCommit(hash string) bool {
+	i, ok := v.commitPosition(hash)
+	if !ok {
+		return false
+	}
+
+	v.selected = i
+	pageSize := v.getPageSize()
+	if i < v.GetOffset() || (pageSize > 0 && i >= v.GetOffset()+pageSize) {
+		v.SetOffset(i)
+	}
+	return true
+}
+
+// PagerArgs returns the git arguments producing the raw log
+func (v *MainView) PagerArgs() ([]string, error) {
+	args := append([]string{"log", "--decorate"}, git.OrderArgs(v.config.General.CommitOrder)...)
+	if commit := v.GetSelectedCommit(); commit != nil {
+		args = append(args, commit.Hash)
+	} else if v.filter.Branch != "" {
+		args = append(args, v.filter.Branch)
+	}
+	if v.path != "" {
+		args = append(args, "--follow", "--", v.path)
+	} else if !v.filter.IsEmpty() {
+		args = append(args, filterArgs(v.filter)...)
+	}
+	return args, nil
+}
+
+// SetPath limits the log to the commits touching a file, the whole log
+// being shown again for an empty path. Filters are cleared.
+func (v *MainView) SetPath(path string) {
+	v.path = path
+	v.filter = git.LogFilter{}
+	v.frame.Title = "Log"
+	if path != "" {
+		v.frame.Title = "History of " + path
+	}
+}
+
+// DiffRange returns the range showing the changes of the selected commit,
+// or of the visual range from its oldest to its newest commit, only to the
+// file when the log is limited to one
+func (v *MainView) DiffRange() (string, []string, error) {
+	commit := v.GetSelectedCommit()
+	if commit == nil {
+		return "", nil, fmt.Errorf("no commit selected")
+	}
+	revRange := commit.Hash + "^!"
+	if v.anchor != "" {
+		oldest, newest := v.visualRange()
+		if len(oldest.Parents) == 0 {
+			return "", nil, fmt.Errorf("%s has no parent to diff the range against", v.client.AbbrevHash(oldest.Hash))
+		}
+		revRange = oldest.Parents[0] + ".." + newest.Hash
+	}
+	if v.path != "" {
+		return revRange, []string{v.path}, nil
+	}
+	if v.filter.Path != "" {
+		return revRange, []string{v.filter.Path}, nil
+	}
+	return revRange, nil, nil
+}
+
+// Selection returns the selected commit for placeholder expansion
+func (v *MainView) Selection() Selection {
+	var sel Selection
+	if commit := v.GetSelectedCommit(); commit != nil {
+		sel.Commit = commit.Hash
+	}
+	return sel
+}
+
+// HintContext returns the kind of the selected item for the hint bar
+func (v *MainView) HintContext() string {
+	if v.GetSelectedCommit() == nil {
+		return ""
+	}
+	if v.anchor != "" {
+		return hintContextRange
+	}
+	return hintContextCommit
+}
+
 // SetRepoPath sets the repository path
 func (v *MainView) SetRepoPath(path string) {
 	v.repoPath = path
-}
\ No newline at end of file
+}
+
+// jumpToParent selects the first parent of the selected commit
+func (vm *ViewManager) jumpToParent() error {
+	mainView, ok := vm.views[ViewTypeMain].(*MainView)
+	if !ok {
+		return fmt.Errorf("main view not found")
+	}
+	commit := mainView.GetSelectedCommit()
+	if commit == nil {
+		return fmt.Errorf("no commit selected")
+	}
+	if len(commit.Parents) == 0 {
+		return fmt.Errorf("%s has no parent", vm.client.AbbrevHash(commit.Hash))
+	}
+
+	if !mainView.selectCommit(commit.Parents[0]) {
+		return fmt.Errorf("parent %s is not loaded", vm.client.AbbrevHash(commit.Parents[0]))
+	}
+	return nil
+}
+
+// MergeBaseCommand handles the :merge-base command, which selects the
+// merge base of the selected commit and the given revision
+func (vm *ViewManager) MergeBaseCommand(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: merge-base <rev>")
+	}
+
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	mainView, ok := vm.views[ViewTypeMain].(*MainView)
+	if !ok {
+		return fmt.Errorf("main view not found")
+	}
+	commit := mainView.GetSelectedCommit()
+	if commit == nil {
+		return fmt.Errorf("no commit selected")
+	}
+	if err := vm.checkRevisions(args[0]); err != nil {
+		return err
+	}
+
+	base, err := vm.client.MergeBase(commit.Hash, args[0])
+	if err != nil {
+		return err
+	}
+	if !mainView.selectCommit(base) {
+		return fmt.Errorf("merge base %s is not loaded", vm.client.AbbrevHash(base))
+	}
+	vm.setMessage("Merge base of %s and %s", vm.client.AbbrevHash(commit.Hash), args[0])
+	return vm.switchView(ViewTypeMain)
+}
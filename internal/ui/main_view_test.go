@@ -1,6 +1,9 @@
 package ui
 
 import (
+	"fmt"
+	"os/exec"
+	"strings"
 	"testing"
 	"time"
 
@@ -8,6 +11,7 @@ import (
 	"github.com/azhao1981/tig/internal/git"
 	"github.com/gdamore/tcell/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewMainView(t *testing.T) {
@@ -230,6 +234,55 @@ func TestMainViewRenderCommitLine(t *testing.T) {
 	view.renderCommitLine(screen, 0, 2, 20, commit, style)
 }
 
+func TestMainViewCoAuthors(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	assert.NoError(t, screen.Init())
+	screen.SetSize(60, 2)
+
+	cfg := &config.Config{}
+	cfg.Views.Main.ShowAuthor = true
+	view := NewMainView(cfg, git.NewClient())
+
+	commit := &git.Commit{Summary: "Pair", Author: git.Signature{Name: "Alice"}, Trailers: []git.Trailer{
+		{Key: "Co-authored-by", Value: "Bob <bob@example.com>"},
+		{Key: "Co-authored-by", Value: "Carol <carol@example.com>"},
+	}}
+	view.renderCommitLine(screen, 0, 0, 60, commit, tcell.StyleDefault)
+	commit.Author.Name = "Alexandra Constantinople"
+	view.renderCommitLine(screen, 0, 1, 60, commit, tcell.StyleDefault)
+
+	lines := screenLines(screen)
+	assert.Equal(t, " Alice +2             Pair", lines[0])
+	assert.Equal(t, " Alexandra Consta… +2 Pair", lines[1])
+}
+
+func TestMainViewTruncation(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	cfg.Views.Main.ShowAuthor = true
+	view := NewMainView(cfg, git.NewClient())
+	view.commits = []*git.Commit{
+		{Hash: "a", Summary: "Make the subject of this commit longer than the line", Author: git.Signature{Name: "山田太郎山田太郎山田太郎"}},
+		{Hash: "b", Summary: "Short", Author: git.Signature{Name: "Bob"}},
+	}
+	require.NoError(t, view.Render(screen, 0, 0, 50, 4))
+
+	// Wide characters are never split, the columns staying aligned
+	lines := screenLines(screen)
+	assert.Equal(t, "│ 山田太郎山田太郎山…  Make the subject of this …│", lines[1])
+	assert.Equal(t, "│ Bob                  Short                     │", lines[2])
+	assert.NotContains(t, lines[3], "Make the subject")
+
+	// The whole subject of the selected commit can be shown when cut
+	cfg.Views.Main.SubjectTooltip = true
+	require.NoError(t, view.Render(screen, 0, 0, 50, 4))
+	assert.Contains(t, screenLines(screen)[3], " Make the subject of this commit longer tha")
+	view.selected = 1
+	require.NoError(t, view.Render(screen, 0, 0, 50, 4))
+	assert.NotContains(t, screenLines(screen)[3], "Short")
+}
+
 func TestMainViewRefresh(t *testing.T) {
 	cfg := &config.Config{}
 	client := git.NewClient()
@@ -249,6 +302,144 @@ func TestMainViewRefresh(t *testing.T) {
 	assert.Equal(t, 0, view.selected) // Should adjust to valid range
 }
 
+func TestMainViewRefreshKeepsSelection(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	view := vm.views[ViewTypeMain].(*MainView)
+	main := refsTestGit(t, dir, "rev-parse", "main")
+
+	// The selected commit stays selected when commits above it go away
+	view.commits = []*git.Commit{{Hash: "0000000000"}, {Hash: main}}
+	view.selected = 1
+	assert.NoError(t, view.Refresh())
+	assert.Equal(t, main, view.GetSelectedCommit().Hash)
+	assert.Len(t, view.commits, 2)
+}
+
+func TestMainViewCommitOrder(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	refsTestGit(t, dir, "branch", "--quiet", "-D", "topic")
+	refsTestGit(t, dir, "commit", "--quiet", "--allow-empty", "-m", "second")
+	view := vm.views[ViewTypeMain].(*MainView)
+	vm.config.General.CommitOrder = "reverse"
+	require.NoError(t, view.Refresh())
+	require.Len(t, view.commits, 2)
+	assert.Equal(t, "base", view.commits[0].Summary)
+
+	args, err := view.PagerArgs()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"log", "--decorate", "--reverse"}, args[:3])
+}
+
+func TestMainViewLoadsLogInPages(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	refsTestGit(t, dir, "branch", "--quiet", "-D", "topic")
+	script := "for i in $(seq 150); do git -c user.name=Test -c user.email=test@example.com commit --quiet --allow-empty -m $i; done"
+	cmd := exec.Command("sh", "-c", script)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "%s", output)
+
+	view := vm.views[ViewTypeMain].(*MainView)
+	require.NoError(t, view.Refresh())
+	require.Len(t, view.commits, logPageSize)
+	assert.Equal(t, "150", view.commits[0].Summary)
+
+	// Getting near the end takes the rest of the log in the background
+	vm.HandleKey(tcell.KeyRune, 'G', 0)
+	require.True(t, view.loader.loading)
+	runJobEvent(t, vm.screen)
+	assert.Len(t, view.commits, 151)
+	assert.True(t, view.loader.done)
+	assert.Equal(t, "base", view.commits[150].Summary)
+
+	// A refresh with no ref moved keeps the log as it is
+	require.NoError(t, view.Refresh())
+	assert.Len(t, view.commits, 151)
+	assert.Equal(t, 99, view.selected)
+
+	// Otherwise only the first page is taken again
+	refsTestGit(t, dir, "commit", "--quiet", "--allow-empty", "-m", "151")
+	view.selected = 0
+	require.NoError(t, view.Refresh())
+	require.Len(t, view.commits, logPageSize)
+	assert.Equal(t, "151", view.commits[0].Summary)
+	assert.Equal(t, 1, view.selected)
+}
+
+func TestMainViewLoadsFileLogInPages(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	script := "for i in $(seq 150); do echo $i >file.txt; git -c user.name=Test -c user.email=test@example.com commit --quiet -am $i; done"
+	cmd := exec.Command("sh", "-c", script)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "%s", output)
+
+	view := vm.views[ViewTypeMain].(*MainView)
+	view.path = "file.txt"
+	require.NoError(t, view.Refresh())
+	require.Len(t, view.commits, logPageSize)
+	require.NoError(t, view.loadAll())
+	assert.Len(t, view.commits, 151)
+	assert.Equal(t, "base", view.commits[150].Summary)
+
+	view.path = ""
+	view.filter = git.LogFilter{Path: "file.txt"}
+	require.NoError(t, view.Refresh())
+	require.Len(t, view.commits, logPageSize)
+	require.NoError(t, view.loadAll())
+	assert.Len(t, view.commits, 151)
+}
+
+func TestMainViewCommitPosition(t *testing.T) {
+	view := NewMainView(&config.Config{}, git.NewClient())
+	view.commits = []*git.Commit{{Hash: "a"}, {Hash: "b"}, {Hash: "c"}}
+
+	i, ok := view.commitPosition("c")
+	assert.True(t, ok)
+	assert.Equal(t, 2, i)
+	_, ok = view.commitPosition("d")
+	assert.False(t, ok)
+
+	// A page taken for the same log extends the index
+	view.commits = append(view.commits, &git.Commit{Hash: "e"})
+	i, ok = view.commitPosition("e")
+	assert.True(t, ok)
+	assert.Equal(t, 3, i)
+
+	// Replacing the log rebuilds the index
+	view.commits = []*git.Commit{{Hash: "d"}, {Hash: "c"}}
+	i, ok = view.commitPosition("c")
+	assert.True(t, ok)
+	assert.Equal(t, 1, i)
+	_, ok = view.commitPosition("a")
+	assert.False(t, ok)
+}
+
+func TestViewManagerJumpToParent(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	assert.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	vm := NewViewManager(screen, cfg, git.NewClient(), NewKeyBindingManager(cfg))
+	vm.SetSize(80, 24)
+
+	mainView := vm.GetView(ViewTypeMain).(*MainView)
+	mainView.commits = []*git.Commit{
+		{Hash: "merge", Parents: []string{"first", "second"}},
+		{Hash: "second", Parents: []string{"first"}},
+		{Hash: "first", Parents: []string{"root"}},
+	}
+
+	assert.True(t, vm.HandleKey(tcell.KeyRune, ',', 0))
+	assert.Equal(t, "first", mainView.GetSelectedCommit().Hash)
+
+	assert.True(t, vm.HandleKey(tcell.KeyRune, ',', 0))
+	assert.Equal(t, "first", mainView.GetSelectedCommit().Hash)
+	assert.Equal(t, "parent root is not loaded", vm.GetMessage())
+
+	assert.Error(t, vm.MergeBaseCommand(nil))
+	assert.Error(t, vm.MergeBaseCommand([]string{"main"}))
+}
+
 func TestMainViewConfigIntegration(t *testing.T) {
 	cfg := &config.Config{}
 	cfg.Views.Main.ShowGraph = true
@@ -274,3 +465,86 @@ func TestMainViewConfigIntegration(t *testing.T) {
 	err = view.Render(screen, 0, 0, 80, 24)
 	assert.NoError(t, err)
 }
+
+func TestMainViewDateSeparators(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Views.Main.DateSeparators = true
+	client := git.NewClient()
+	view := NewMainView(cfg, client)
+
+	screen := tcell.NewSimulationScreen("")
+	assert.NoError(t, screen.Init())
+	screen.SetSize(40, 7)
+
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 12, 0, 0, 0, time.UTC) }
+	for i, when := range []time.Time{day(12), day(12), day(11), day(10), day(10)} {
+		view.commits = append(view.commits, &git.Commit{
+			Hash:    fmt.Sprintf("%040d", i),
+			Summary: fmt.Sprintf("commit %d", i),
+			Author:  git.Signature{Name: "Test", Time: when},
+		})
+	}
+
+	assert.NoError(t, view.Render(screen, 0, 0, 40, 7))
+	lines := screenLines(screen)
+	assert.Contains(t, lines[1], "commit 0")
+	assert.Contains(t, lines[2], "commit 1")
+	assert.Equal(t, "── 2024-05-11 ──", strings.TrimSpace(strings.Trim(lines[3], "│█")))
+	assert.Contains(t, lines[4], "commit 2")
+	assert.Contains(t, lines[5], "2024-05-10")
+
+	// Scrolling keeps the selected commit in view despite the separators
+	view.selected = 4
+	assert.NoError(t, view.Render(screen, 0, 0, 40, 7))
+	lines = screenLines(screen)
+	assert.Contains(t, lines[1], "commit 2")
+	assert.Contains(t, lines[2], "── 2024-05-10 ──")
+	assert.Contains(t, lines[4], "commit 4")
+	assert.Equal(t, 2, view.GetOffset())
+
+	// Without the option commits follow each other
+	cfg.Views.Main.DateSeparators = false
+	view.selected = 0
+	view.SetOffset(0)
+	assert.NoError(t, view.Render(screen, 0, 0, 40, 7))
+	lines = screenLines(screen)
+	assert.Contains(t, lines[3], "commit 2")
+}
+
+func TestMainViewDecorations(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	view := vm.views[ViewTypeMain].(*MainView)
+	vm.config.Views.Main.ShowRefs = true
+	refsTestGit(t, dir, "tag", "-a", "-m", "Release", "v1.2.0", "topic")
+	refsTestGit(t, dir, "update-ref", "refs/remotes/origin/main", "main")
+	refsTestGit(t, dir, "checkout", "--quiet", "--detach", "topic")
+	require.NoError(t, vm.RefreshAll())
+	require.NoError(t, vm.Render())
+
+	lines := screenLines(vm.screen)
+	assert.Contains(t, strings.Join(lines, "\n"), "│ HEAD [topic] <v1.2.0> topic")
+	assert.Contains(t, strings.Join(lines, "\n"), "│ [main] {origin/main} base")
+
+	// Each kind of ref is drawn in its color of the theme
+	theme := newSchemeTheme(vm.config)
+	colorAt := func(text string) tcell.Color {
+		for y, line := range lines {
+			if x := strings.Index(line, text); x >= 0 {
+				_, _, style, _ := vm.screen.GetContent(len([]rune(line[:x])), y)
+				fg, _, _ := style.Decompose()
+				return fg
+			}
+		}
+		t.Fatalf("%q not shown", text)
+		return tcell.ColorDefault
+	}
+	assert.Equal(t, theme.GetColor("head"), colorAt("HEAD"))
+	assert.Equal(t, theme.GetColor("branch"), colorAt("[topic]"))
+	assert.Equal(t, theme.GetColor("tag"), colorAt("<v1.2.0>"))
+	assert.Equal(t, theme.GetColor("remote"), colorAt("{origin/main}"))
+
+	// On a branch, HEAD is not shown apart from the branch
+	refsTestGit(t, dir, "checkout", "--quiet", "main")
+	require.NoError(t, vm.RefreshAll())
+	assert.Equal(t, []decoration{{text: "[main]", color: "branch", bold: true}, {text: "{origin/main}", color: "remote"}}, view.getCommitRefs(refsTestGit(t, dir, "rev-parse", "main")))
+}
@@ -0,0 +1,319 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// maintenanceBlobs is how many of the largest blobs the maintenance view
+// lists
+const maintenanceBlobs = 10
+
+// maintenanceKeys are the keys of the maintenance view running a task
+var maintenanceKeys = map[rune]string{
+	'c': "gc",
+	'r': "repack",
+	'p': "prune",
+}
+
+// maintenanceCommands are the git commands each maintenance task runs, as
+// shown to the user
+var maintenanceCommands = map[string]string{
+	"gc":     "git gc",
+	"repack": "git repack -a -d",
+	"prune":  "git prune --expire=2.weeks.ago",
+}
+
+// maintenanceLine is a line of the maintenance view
+type maintenanceLine struct {
+	text  string
+	style tcell.Style
+}
+
+// MaintenanceView shows the health of the object database of the
+// repository, and runs git gc, repack and prune in the background
+type MaintenanceView struct {
+	*BaseView
+	*Scrollable
+	config   *config.Config
+	client   git.Client
+	stats    *git.RepoStats
+	err      error
+	loading  bool
+	running  string // Task running, if any
+	repoPath string
+	frame    *Frame
+
+	load func()            // Loads the statistics in the background
+	run  func(task string) // Runs a maintenance task in the background
+}
+
+// NewMaintenanceView creates a new maintenance view
+func NewMaintenanceView(config *config.Config, client git.Client) *MaintenanceView {
+	return &MaintenanceView{
+		BaseView:   NewBaseView(ViewTypeMaintenance),
+		Scrollable: NewScrollable(),
+		config:     config,
+		client:     client,
+		frame:      NewFrame(config, "Maintenance"),
+	}
+}
+
+// lines lays out the statistics, the state of git gc --auto and the
+// largest blobs
+func (v *MaintenanceView) lines() []maintenanceLine {
+	heading := tcell.StyleDefault.Bold(true)
+	plain := tcell.StyleDefault
+	dim := tcell.StyleDefault.Dim(true)
+	warn := tcell.StyleDefault.Foreground(tcell.ColorYellow)
+
+	var lines []maintenanceLine
+	add := func(style tcell.Style, format string, args ...interface{}) {
+		lines = append(lines, maintenanceLine{fmt.Sprintf(format, args...), style})
+	}
+
+	s := v.stats
+	add(heading, "Objects")
+	add(plain, "  %-16s %s", "Format", v.client.ObjectFormat())
+	add(plain, "  %-16s %d (%s)", "Loose", s.LooseObjects, git.FormatSize(s.LooseSize))
+	add(plain, "  %-16s %d in %d packs (%s)", "Packed", s.PackedObjects, s.Packs, git.FormatSize(s.PackSize))
+	if s.PrunePackable > 0 {
+		add(plain, "  %-16s %d loose objects are packed already", "Prunable", s.PrunePackable)
+	}
+	if s.Garbage > 0 {
+		add(warn, "  %-16s %d files (%s)", "Garbage", s.Garbage, git.FormatSize(s.GarbageSize))
+	}
+
+	add(plain, "")
+	add(heading, "Garbage collection")
+	if s.GCAuto > 0 || s.GCAutoPacks > 0 {
+		add(plain, "  %-16s over %d loose objects or %d packs", "gc --auto runs", s.GCAuto, s.GCAutoPacks)
+	} else {
+		add(plain, "  %-16s never, gc.auto is 0", "gc --auto runs")
+	}
+	switch {
+	case s.GCLog != "":
+		add(warn, "  %-16s the last gc --auto failed, until gc.log is removed:", "Status")
+		for _, line := range strings.Split(s.GCLog, "\n") {
+			add(dim, "    %s", line)
+		}
+	case s.NeedsGC():
+		add(warn, "  %-16s due, press c to run git gc", "Status")
+	default:
+		add(plain, "  %-16s not needed", "Status")
+	}
+
+	if len(s.LargestBlobs) > 0 {
+		add(plain, "")
+		add(heading, "Largest blobs")
+		for _, blob := range s.LargestBlobs {
+			add(plain, "  %10s  %s  %s", git.FormatSize(blob.Size), v.client.AbbrevHash(blob.Hash), blob.Path)
+		}
+	}
+
+	add(plain, "")
+	add(heading, "Actions")
+	for _, key := range []rune{'c', 'r', 'p'} {
+		add(dim, "  %c  %s", key, maintenanceCommands[maintenanceKeys[key]])
+	}
+	return lines
+}
+
+// Render renders the maintenance view
+func (v *MaintenanceView) Render(screen tcell.Screen, x, y, width, height int) error {
+	v.SetPosition(x, y, width, height)
+	v.SetHeight(height - 2) // Account for borders
+
+	v.frame.Title = "Maintenance"
+	if v.running != "" {
+		v.frame.Title = fmt.Sprintf("Maintenance: running %s", maintenanceCommands[v.running])
+	}
+	contentX, contentY, contentWidth, contentHeight := v.frame.Draw(screen, x, y, width, height)
+	defer v.frame.DrawScrollbar(screen, v.Scrollable)
+
+	if contentWidth <= 0 || contentHeight <= 0 {
+		return nil
+	}
+
+	if v.stats == nil {
+		msg := "Counting objects..."
+		if v.err != nil {
+			msg = v.err.Error()
+		}
+		msgX := contentX + (contentWidth-len(msg))/2
+		msgY := contentY + contentHeight/2
+		drawCells(screen, max(contentX, msgX), msgY, contentWidth, textCells(msg, tcell.StyleDefault.Dim(true)), 0, tcell.StyleDefault)
+		return nil
+	}
+
+	lines := v.lines()
+	v.SetMaxOffset(len(lines) - contentHeight)
+
+	start := v.GetOffset()
+	end := min(start+contentHeight, len(lines))
+	for i := start; i < end; i++ {
+		drawCells(screen, contentX, contentY+(i-start), contentWidth, textCells(lines[i].text, lines[i].style), 0, tcell.StyleDefault)
+	}
+
+	return nil
+}
+
+// HandleKey handles keyboard input
+func (v *MaintenanceView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
+	if !v.IsFocused() {
+		return false
+	}
+
+	switch key {
+	case tcell.KeyUp:
+		v.ScrollUp()
+		return true
+	case tcell.KeyDown:
+		v.ScrollDown()
+		return true
+	case tcell.KeyHome:
+		v.ScrollToTop()
+		return true
+	case tcell.KeyEnd:
+		v.ScrollToBottom()
+		return true
+	}
+
+	if task, ok := maintenanceKeys[ch]; ok && key == tcell.KeyRune {
+		if v.run != nil {
+			v.run(task)
+		}
+		return true
+	}
+
+	switch ch {
+	case 'j':
+		v.ScrollDown()
+		return true
+	case 'k':
+		v.ScrollUp()
+		return true
+	case 'g':
+		v.ScrollToTop()
+		return true
+	case 'G':
+		v.ScrollToBottom()
+		return true
+	case 'R':
+		v.reload()
+		return true
+	}
+
+	return false
+}
+
+// reload counts the objects again in the background, unless already
+// counting
+func (v *MaintenanceView) reload() {
+	if v.loading || v.load == nil {
+		return
+	}
+	v.loading = true
+	v.load()
+}
+
+// Refresh does nothing, counting the objects being too slow for the
+// periodic refresh: the statistics are reloaded when the view is opened,
+// after each task and on R
+func (v *MaintenanceView) Refresh() error {
+	return nil
+}
+
+// SetRepoPath sets the repository path
+func (v *MaintenanceView) SetRepoPath(path string) {
+	v.repoPath = path
+	v.stats, v.err = nil, nil
+}
+
+// loadRepoStats counts the objects of the repository in the background for
+// the maintenance view
+func (vm *ViewManager) loadRepoStats() {
+	view, ok := vm.views[ViewTypeMaintenance].(*MaintenanceView)
+	if !ok {
+		return
+	}
+	var stats *git.RepoStats
+	vm.startJob(&job{kind: jobMaintenance, name: "Counting objects", quiet: true}, func(ctx context.Context, progress func(string)) error {
+		var err error
+		stats, err = vm.client.RepoStats(maintenanceBlobs)
+		return err
+	}, func(err error) string {
+		view.loading = false
+		view.stats, view.err = stats, err
+		return ""
+	})
+}
+
+// runMaintenance runs a maintenance task in the background, one at a time,
+// asking first for prune which deletes objects for good
+func (vm *ViewManager) runMaintenance(task string) {
+	view, ok := vm.views[ViewTypeMaintenance].(*MaintenanceView)
+	if !ok {
+		return
+	}
+	if view.running != "" {
+		vm.setMessage("%s is running already", maintenanceCommands[view.running])
+		return
+	}
+
+	command := maintenanceCommands[task]
+	start := func() error {
+		view.running = task
+		vm.startJob(&job{kind: jobMaintenance, name: command}, func(ctx context.Context, progress func(string)) error {
+			return vm.client.Maintain(ctx, task, progress)
+		}, func(err error) string {
+			view.running = ""
+			view.reload()
+			if err != nil {
+				return err.Error()
+			}
+			return command + ": done"
+		})
+		return nil
+	}
+
+	if task != "prune" {
+		_ = start()
+		return
+	}
+	vm.askConfirmation("Prune unreachable objects?", []string{
+		"Objects no ref, reflog or index refers to are deleted for good",
+		"once older than two weeks",
+		command,
+	}, start)
+}
+
+// MaintenanceCommand handles the :maintenance command, which shows the
+// maintenance view or, given a task, runs it
+func (vm *ViewManager) MaintenanceCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	view, ok := vm.views[ViewTypeMaintenance].(*MaintenanceView)
+	if !ok {
+		return fmt.Errorf("maintenance view not found")
+	}
+
+	if len(args) > 0 {
+		if _, ok := maintenanceCommands[args[0]]; !ok {
+			return fmt.Errorf("unknown maintenance task: %s, expected one of %s", args[0], strings.Join(git.MaintenanceTasks, ", "))
+		}
+		if vm.config.General.ReadOnly {
+			return fmt.Errorf("maintenance is disabled in read-only mode")
+		}
+		vm.runMaintenance(args[0])
+	} else if view.stats == nil {
+		view.reload()
+	}
+	return vm.switchView(ViewTypeMaintenance)
+}
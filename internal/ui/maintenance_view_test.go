@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceView(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	refsTestGit(t, dir, "config", "gc.auto", "1")
+	screen := vm.screen
+
+	require.NoError(t, vm.MaintenanceCommand(nil))
+	assert.Equal(t, ViewTypeMaintenance, vm.GetCurrentView())
+	require.NoError(t, vm.Render())
+	assert.Contains(t, strings.Join(screenLines(screen), "\n"), "Counting objects...")
+
+	runJobEvent(t, screen)
+	view := vm.views[ViewTypeMaintenance].(*MaintenanceView)
+	require.NotNil(t, view.stats)
+	require.NoError(t, vm.Render())
+	text := strings.Join(screenLines(screen), "\n")
+	assert.Contains(t, text, "Format           sha1")
+	assert.Contains(t, text, "Largest blobs")
+	assert.Contains(t, text, "notes.txt")
+	assert.Contains(t, text, "due, press c to run git gc")
+
+	// Tasks run one at a time, and the statistics are counted again after
+	vm.HandleKey(tcell.KeyRune, 'c', 0)
+	assert.Equal(t, "gc", view.running)
+	vm.HandleKey(tcell.KeyRune, 'r', 0)
+	assert.Equal(t, "git gc is running already", vm.GetMessage())
+	for view.running != "" {
+		runJobEvent(t, screen)
+	}
+	assert.Equal(t, "git gc: done", vm.GetMessage())
+	for view.loading {
+		runJobEvent(t, screen)
+	}
+	assert.Zero(t, view.stats.LooseObjects)
+	assert.False(t, view.stats.NeedsGC())
+
+	// Pruning asks first
+	vm.HandleKey(tcell.KeyRune, 'p', 0)
+	assert.True(t, vm.Confirming())
+	vm.HandleKey(tcell.KeyRune, 'n', 0)
+	assert.Empty(t, view.running)
+
+	assert.Error(t, vm.MaintenanceCommand([]string{"fsck"}))
+
+	vm.config.General.ReadOnly = true
+	vm.HandleKey(tcell.KeyRune, 'c', 0)
+	assert.Equal(t, "maintenance is disabled in read-only mode", vm.GetMessage())
+	assert.Empty(t, view.running)
+	assert.Error(t, vm.MaintenanceCommand([]string{"gc"}))
+}
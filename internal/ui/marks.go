@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// mark is a place in a view set with m and a letter: the commit of the
+// log, the file of the status view, or the line of a diff
+type mark struct {
+	item string // What the view showed, the diff for the diff view
+	key  string // Commit, file or file of the line marked
+	line int    // Line below the first line of the file in a diff
+}
+
+// markMemory is the registry of the marks of each view, keyed by letter
+type markMemory map[ViewType]map[rune]mark
+
+// markable is implemented by the views whose places can be marked
+type markable interface {
+	// markHere returns the place of the cursor, false when there is none
+	markHere() (mark, bool)
+	// jumpToMark goes back to a place, false when it is no longer shown
+	jumpToMark(m mark) bool
+}
+
+// markHere marks the selected commit
+func (v *MainView) markHere() (mark, bool) {
+	commit := v.GetSelectedCommit()
+	if commit == nil {
+		return mark{}, false
+	}
+	return mark{key: commit.Hash}, true
+}
+
+// jumpToMark selects the marked commit
+func (v *MainView) jumpToMark(m mark) bool {
+	return v.selectCommit(m.key)
+}
+
+// markHere marks the selected file
+func (v *StatusView) markHere() (mark, bool) {
+	file, section := v.selectedEntry()
+	if file == nil {
+		return mark{}, false
+	}
+	return mark{item: section, key: file.Path}, true
+}
+
+// jumpToMark selects the marked file, in the section it was marked in if
+// it is still listed there
+func (v *StatusView) jumpToMark(m mark) bool {
+	return v.selectFile(m.key, m.item)
+}
+
+// diffItem returns what the diff view shows, for marks to be left on the
+// diff they were set in
+func (v *DiffView) diffItem() string {
+	switch {
+	case v.stage != nil:
+		return stageFocusKey(v.stage.path, v.stage.section)
+	case v.revRange != "":
+		return v.revRange
+	}
+	return v.commitHash
+}
+
+// markHere marks the line at the top of the diff
+func (v *DiffView) markHere() (mark, bool) {
+	path, line := v.topAnchor()
+	if path == "" {
+		return mark{}, false
+	}
+	return mark{item: v.diffItem(), key: path, line: line}, true
+}
+
+// jumpToMark scrolls back to the marked line while the diff it was set in
+// is shown
+func (v *DiffView) jumpToMark(m mark) bool {
+	if m.item != v.diffItem() {
+		return false
+	}
+	for _, row := range v.rows {
+		if row.file != nil && row.file.Path() == m.key {
+			v.restoreAnchor(m.key, m.line)
+			return true
+		}
+	}
+	return false
+}
+
+// handleMarkKey handles m and ' followed by a letter in the views with
+// marks, setting a mark or jumping back to it. Marks are kept per view
+// until another repository is opened.
+func (vm *ViewManager) handleMarkKey(key tcell.Key, ch rune) bool {
+	view, ok := vm.views[vm.currentView].(markable)
+	prefix := vm.markPrefix
+	vm.markPrefix = 0
+	if !ok || key != tcell.KeyRune {
+		return false
+	}
+	if prefix == 0 {
+		if ch != 'm' && ch != '\'' {
+			return false
+		}
+		vm.markPrefix = ch
+		return true
+	}
+
+	if (ch < 'a' || ch > 'z') && (ch < 'A' || ch > 'Z') {
+		vm.setMessage("Marks are named by letters")
+		return true
+	}
+	if prefix == 'm' {
+		m, ok := view.markHere()
+		if !ok {
+			vm.setMessage("Nothing to mark here")
+			return true
+		}
+		if vm.marks[vm.currentView] == nil {
+			vm.marks[vm.currentView] = make(map[rune]mark)
+		}
+		vm.marks[vm.currentView][ch] = m
+		vm.setMessage("Set mark %c", ch)
+		return true
+	}
+
+	m, ok := vm.marks[vm.currentView][ch]
+	if !ok {
+		vm.setMessage("Mark %c is not set in this view", ch)
+	} else if !view.jumpToMark(m) {
+		vm.setMessage("Mark %c is no longer shown", ch)
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarks(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	mainView := vm.views[ViewTypeMain].(*MainView)
+	diffView := vm.views[ViewTypeDiff].(*DiffView)
+	require.NoError(t, vm.refreshAll())
+	require.Len(t, mainView.commits, 2)
+
+	press := func(keys string) {
+		for _, ch := range keys {
+			vm.HandleKey(tcell.KeyRune, ch, 0)
+		}
+	}
+
+	// Marks are set and jumped to in the log
+	press("ma")
+	assert.Equal(t, "Set mark a", vm.GetMessage())
+	press("j")
+	assert.Equal(t, 1, mainView.selected)
+	press("'a")
+	assert.Equal(t, 0, mainView.selected)
+	press("'b")
+	assert.Equal(t, "Mark b is not set in this view", vm.GetMessage())
+	press("m1")
+	assert.Equal(t, "Marks are named by letters", vm.GetMessage())
+
+	// Each view has its own marks
+	require.NoError(t, vm.SwitchView(ViewTypeDiff))
+	press("'a")
+	assert.Equal(t, "Mark a is not set in this view", vm.GetMessage())
+
+	// Marks of a diff are left on the line of the diff they were set in
+	vm.SetSize(100, 5)
+	topic := refsTestGit(t, dir, "rev-parse", "topic")
+	diffView.SetCommitHash(topic)
+	for i := 0; diffView.rows[diffView.GetOffset()].hunk < 0; i++ {
+		require.Less(t, i, 30)
+		press("j")
+	}
+	marked := diffView.GetOffset()
+	press("mx")
+	diffView.ScrollToTop()
+	press("'x")
+	assert.Equal(t, marked, diffView.GetOffset())
+
+	require.NoError(t, diffView.SetRange("topic..main", nil))
+	press("'x")
+	assert.Equal(t, "Mark x is no longer shown", vm.GetMessage())
+
+	// A key other than a letter after m is not taken as a mark
+	require.NoError(t, vm.SwitchView(ViewTypeMain))
+	press("m")
+	vm.HandleKey(tcell.KeyDown, 0, 0)
+	assert.Equal(t, 1, mainView.selected)
+}
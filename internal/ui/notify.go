@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azhao1981/tig/internal/config"
+)
+
+// notifyJob tells about a job which finished after the user moved on to
+// another view, in the ways the notify options ask for. The notification
+// gives the outcome of the job, or whether it failed when it has none.
+func (vm *ViewManager) notifyJob(j *job, outcome string) {
+	cfg := vm.config.General
+	if j.cancelled || j.view == vm.currentView {
+		return
+	}
+	if j.duration() < time.Duration(cfg.NotifyAfter)*time.Second {
+		return
+	}
+	if !slices.Contains(config.ListValues(cfg.NotifyOn), j.kind) {
+		return
+	}
+
+	text := outcome
+	switch {
+	case text != "":
+	case j.err != nil:
+		text = fmt.Sprintf("%s failed: %v", j.name, j.err)
+	default:
+		text = j.name + ": done"
+	}
+	vm.alert(text)
+}
+
+// alert draws attention to tig with text in the ways the notify options
+// ask for
+func (vm *ViewManager) alert(text string) {
+	cfg := vm.config.General
+	for _, method := range config.ListValues(cfg.Notify) {
+		switch method {
+		case "bell":
+			_ = vm.screen.Beep()
+		case "osc9":
+			if tty, ok := vm.screen.Tty(); ok && tty != nil {
+				fmt.Fprintf(tty, "\x1b]9;%s\x07", stripControl("tig: "+text))
+			}
+		}
+	}
+
+	if args := strings.Fields(cfg.NotifyCommand); len(args) > 0 {
+		cmd := exec.Command(args[0], append(args[1:], text)...)
+		if err := cmd.Start(); err != nil {
+			vm.setMessage("notify-command failed: %v", err)
+			return
+		}
+		go cmd.Wait()
+	}
+}
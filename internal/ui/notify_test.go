@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyJob(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "notify")
+	out := filepath.Join(dir, "out")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho \"$1\" >>"+out+"\n"), 0755))
+
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	cfg.General.Notify = "bell"
+	cfg.General.NotifyCommand = script
+	cfg.General.NotifyOn = "push"
+	vm := NewViewManager(screen, cfg, git.NewClient(), NewKeyBindingManager(cfg))
+	vm.SetSize(80, 24)
+
+	run := func(kind string) {
+		vm.startJob(&job{kind: kind, name: "Pushing"}, func(ctx context.Context, progress func(string)) error {
+			return nil
+		}, func(err error) string { return "Pushed " + kind })
+	}
+
+	// Jobs finishing in the view they started in need no notification
+	run(jobPush)
+	runJobEvent(t, screen)
+
+	// Nor do jobs of kinds not asked for
+	vm.SwitchView(ViewTypeRefs)
+	run(jobFetch)
+	vm.SwitchView(ViewTypeMain)
+	runJobEvent(t, screen)
+
+	vm.SwitchView(ViewTypeRefs)
+	run(jobPush)
+	vm.SwitchView(ViewTypeMain)
+	runJobEvent(t, screen)
+
+	assert.Eventually(t, func() bool {
+		data, _ := os.ReadFile(out)
+		return string(data) == "Pushed push\n"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Jobs without an outcome of their own are told to have failed or not
+	vm.SwitchView(ViewTypeRefs)
+	vm.startJob(&job{kind: jobPush, name: "Pushing"}, func(ctx context.Context, progress func(string)) error {
+		return errors.New("rejected")
+	}, func(err error) string { return "" })
+	vm.SwitchView(ViewTypeMain)
+	runJobEvent(t, screen)
+	assert.Eventually(t, func() bool {
+		data, _ := os.ReadFile(out)
+		return string(data) == "Pushed push\nPushing failed: rejected\n"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Quick jobs are not worth a notification
+	cfg.General.NotifyAfter = 60
+	vm.SwitchView(ViewTypeRefs)
+	run(jobPush)
+	vm.SwitchView(ViewTypeMain)
+	runJobEvent(t, screen)
+	time.Sleep(50 * time.Millisecond)
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "Pushed push\nPushing failed: rejected\n", string(data))
+}
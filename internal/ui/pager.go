@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// PagerSource is implemented by views whose raw output can be routed to
+// the external pager instead of being rendered internally
+type PagerSource interface {
+	// PagerArgs returns the git arguments producing the raw output of the view
+	PagerArgs() ([]string, error)
+}
+
+// PagerCommand handles the :pager command. Without arguments it pipes the
+// raw output of the current view, otherwise it runs the given git command.
+func (vm *ViewManager) PagerCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if len(args) == 0 {
+		return vm.pipeCurrentView()
+	}
+	return vm.runGitPager(args)
+}
+
+// pipeCurrentView routes the raw output of the current view to the pager
+func (vm *ViewManager) pipeCurrentView() error {
+	source, ok := vm.views[vm.currentView].(PagerSource)
+	if !ok {
+		return fmt.Errorf("current view has no raw output")
+	}
+
+	args, err := source.PagerArgs()
+	if err != nil {
+		return err
+	}
+	return vm.runGitPager(args)
+}
+
+// runGitPager streams the output of a git command into the external pager
+func (vm *ViewManager) runGitPager(args []string) error {
+	if !vm.client.IsRepository() {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	gitCmd := exec.Command("git", colorArgs(args)...)
+	gitCmd.Dir = vm.client.GetRootPath()
+	var gitStderr bytes.Buffer
+	gitCmd.Stderr = &gitStderr
+
+	pagerCmd := exec.Command("sh", "-c", vm.pagerProgram())
+	pagerCmd.Dir = gitCmd.Dir
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		// Let less show the colors, as git does when it runs the pager
+		pagerCmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+	pagerCmd.Stdout = os.Stdout
+	pagerCmd.Stderr = os.Stderr
+
+	output, err := gitCmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create pipe: %w", err)
+	}
+	pagerCmd.Stdin = output
+
+	return vm.suspend(func() error {
+		if err := gitCmd.Start(); err != nil {
+			return fmt.Errorf("failed to run git %s: %w", strings.Join(args, " "), err)
+		}
+		pagerErr := pagerCmd.Run()
+		gitErr := gitCmd.Wait()
+		if pagerErr != nil {
+			return fmt.Errorf("pager failed: %w", pagerErr)
+		}
+		// The pager may exit before consuming all output, which kills git
+		// with a silent broken pipe, so only report failures git explained
+		if gitErr != nil && gitStderr.Len() > 0 {
+			return fmt.Errorf("git %s failed: %s", strings.Join(args, " "), strings.TrimSpace(gitStderr.String()))
+		}
+		return nil
+	})
+}
+
+// colorArgs asks the git commands producing diffs and logs to keep their
+// colors when piped, unless the arguments already say otherwise
+func colorArgs(args []string) []string {
+	if len(args) == 0 {
+		return args
+	}
+	switch args[0] {
+	case "diff", "log", "show":
+	default:
+		return args
+	}
+	for _, arg := range args[1:] {
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "--color") || arg == "--no-color" {
+			return args
+		}
+	}
+	return append([]string{args[0], "--color=always"}, args[1:]...)
+}
+
+// pagerProgram returns the pager command line to use
+func (vm *ViewManager) pagerProgram() string {
+	if vm.config.General.Pager != "" {
+		return vm.config.General.Pager
+	}
+	if pager := os.Getenv("PAGER"); pager != "" {
+		return pager
+	}
+	return "less"
+}
+
+// suspend suspends the TUI while fn runs in the foreground of the terminal
+func (vm *ViewManager) suspend(fn func() error) error {
+	if err := vm.screen.Suspend(); err != nil {
+		return fmt.Errorf("failed to suspend screen: %w", err)
+	}
+	runErr := fn()
+	if err := vm.screen.Resume(); err != nil {
+		return fmt.Errorf("failed to resume screen: %w", err)
+	}
+	vm.screen.Sync()
+	return runErr
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagerProgram(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	err := screen.Init()
+	assert.NoError(t, err)
+	cfg := &config.Config{}
+	client := git.NewClient()
+	keyBindingMgr := NewKeyBindingManager(cfg)
+
+	vm := NewViewManager(screen, cfg, client, keyBindingMgr)
+
+	t.Setenv("PAGER", "more")
+	assert.Equal(t, "more", vm.pagerProgram())
+
+	cfg.General.Pager = "less -R"
+	assert.Equal(t, "less -R", vm.pagerProgram())
+}
+
+func TestPagerArgs(t *testing.T) {
+	cfg := &config.Config{}
+	client := git.NewClient()
+
+	diffView := NewDiffView(cfg, client)
+	_, err := diffView.PagerArgs()
+	assert.Error(t, err)
+
+	diffView.commitHash = "abc123"
+	args, err := diffView.PagerArgs()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"show", "--patch-with-stat", "abc123"}, args)
+
+	treeView := NewTreeView(cfg, client)
+	treeView.currentPath = "src"
+	treeView.files = []*git.File{{Path: "main.go"}}
+	args, err = treeView.PagerArgs()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"show", "HEAD:src/main.go"}, args)
+}
+
+func TestColorArgs(t *testing.T) {
+	assert.Equal(t, []string{"log", "--color=always", "--decorate"}, colorArgs([]string{"log", "--decorate"}))
+	assert.Equal(t, []string{"show", "--color=always", "HEAD"}, colorArgs([]string{"show", "HEAD"}))
+	assert.Equal(t, []string{"diff", "--no-color"}, colorArgs([]string{"diff", "--no-color"}))
+	assert.Equal(t, []string{"blame", "file"}, colorArgs([]string{"blame", "file"}))
+}
+
+func TestPagerCommandWithoutRepository(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	err := screen.Init()
+	assert.NoError(t, err)
+	cfg := &config.Config{}
+	client := git.NewClient()
+	keyBindingMgr := NewKeyBindingManager(cfg)
+
+	vm := NewViewManager(screen, cfg, client, keyBindingMgr)
+
+	err = vm.PagerCommand([]string{"log"})
+	assert.Error(t, err)
+
+	// The help view has no raw output to page
+	_ = vm.SwitchView(ViewTypeHelp)
+	err = vm.PagerCommand(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,316 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// paletteItem is a command or key-bound action offered by the palette
+type paletteItem struct {
+	name        string
+	description string
+	binding     string      // Key the action is bound to, empty for commands
+	usage       string      // Usage of commands, empty for actions
+	key         *KeyBinding // Binding run when an action is chosen
+}
+
+// paletteMatch is an item matching the palette query along with its score
+type paletteMatch struct {
+	item  *paletteItem
+	score int
+}
+
+// Palette is an overlay listing commands and actions which narrows down as
+// a query is typed, and runs the chosen one
+type Palette struct {
+	items    []*paletteItem
+	matches  []paletteMatch
+	query    string
+	selected int
+	offset   int
+	chosen   *paletteItem
+}
+
+// NewPalette creates a palette listing the given commands and the actions
+// with key bindings. Commands which share their name with an action, such
+// as log or refresh, are listed once as the action.
+func NewPalette(commands map[string]*Command, keys *KeyBindingManager) *Palette {
+	p := &Palette{}
+
+	// In read-only mode the actions and commands changing the repository are
+	// left out
+	for action, binding := range keys.GetAllBindings() {
+		if keys.readOnly() && isMutatingAction(action) {
+			continue
+		}
+		p.items = append(p.items, &paletteItem{
+			name:        action,
+			description: binding.Help,
+			binding:     keys.bindingToString(binding),
+			key:         binding,
+		})
+	}
+	for name, cmd := range commands {
+		if _, ok := keys.GetBinding(name); ok || (keys.readOnly() && cmd.Mutating) {
+			continue
+		}
+		p.items = append(p.items, &paletteItem{
+			name:        ":" + name,
+			description: cmd.Description,
+			usage:       cmd.Usage,
+		})
+	}
+	sort.Slice(p.items, func(i, j int) bool {
+		return strings.TrimPrefix(p.items[i].name, ":") < strings.TrimPrefix(p.items[j].name, ":")
+	})
+
+	p.filter()
+	return p
+}
+
+// filter lists the items matching the query, best matches first
+func (p *Palette) filter() {
+	p.matches = p.matches[:0]
+	for _, item := range p.items {
+		// Matches in the name count for more than those in the description
+		if score, ok := fuzzyMatch(p.query, item.name); ok {
+			p.matches = append(p.matches, paletteMatch{item, 2*score + 1})
+		} else if score, ok := fuzzyMatch(p.query, item.description); ok {
+			p.matches = append(p.matches, paletteMatch{item, score})
+		}
+	}
+	sort.SliceStable(p.matches, func(i, j int) bool {
+		return p.matches[i].score > p.matches[j].score
+	})
+	p.selected = 0
+	p.offset = 0
+}
+
+// fuzzyMatch returns whether the characters of the query appear in the
+// text in order, ignoring case. The score favours runs of consecutive
+// characters and characters starting words.
+func fuzzyMatch(query, text string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return 0, true
+	}
+
+	score, i, previous := 0, 0, -2
+	runes := []rune(strings.ToLower(text))
+	for j, r := range runes {
+		if r != q[i] {
+			continue
+		}
+		score++
+		if previous == j-1 {
+			score += 2
+		}
+		if j == 0 || !unicode.IsLetter(runes[j-1]) {
+			score += 3
+		}
+		previous = j
+		if i++; i == len(q) {
+			return score, true
+		}
+	}
+	return 0, false
+}
+
+// Query returns the text typed so far
+func (p *Palette) Query() string {
+	return p.query
+}
+
+// Selected returns the highlighted item, if any item matches
+func (p *Palette) Selected() *paletteItem {
+	if p.selected < 0 || p.selected >= len(p.matches) {
+		return nil
+	}
+	return p.matches[p.selected].item
+}
+
+// Chosen returns the item chosen with Enter, nil when the palette was
+// dismissed
+func (p *Palette) Chosen() *paletteItem {
+	return p.chosen
+}
+
+// HandleKey edits the query or moves the selection. It returns true once
+// the palette is done, either because an item was chosen or it was
+// dismissed.
+func (p *Palette) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
+	switch key {
+	case tcell.KeyEsc, tcell.KeyCtrlC:
+		return true
+	case tcell.KeyEnter:
+		p.chosen = p.Selected()
+		return true
+	case tcell.KeyUp, tcell.KeyCtrlP:
+		if p.selected > 0 {
+			p.selected--
+		}
+	case tcell.KeyDown, tcell.KeyCtrlN, tcell.KeyTab:
+		if p.selected < len(p.matches)-1 {
+			p.selected++
+		}
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if query := []rune(p.query); len(query) > 0 {
+			p.query = string(query[:len(query)-1])
+			p.filter()
+		}
+	case tcell.KeyCtrlU:
+		p.query = ""
+		p.filter()
+	case tcell.KeyRune:
+		p.query += string(ch)
+		p.filter()
+	}
+	return false
+}
+
+// InsertText adds text to the query, such as a paste
+func (p *Palette) InsertText(text string) {
+	p.query += text
+	p.filter()
+}
+
+// Draw draws the palette over the upper part of the screen
+func (p *Palette) Draw(screen tcell.Screen, width, height int) {
+	boxWidth := min(72, width-4)
+	boxHeight := min(len(p.items)+3, height*2/3)
+	if boxWidth < 20 || boxHeight < 4 {
+		return
+	}
+	x := (width - boxWidth) / 2
+	y := max(1, height/6)
+
+	style := tcell.StyleDefault.Foreground(tcell.ColorWhite)
+	for row := y; row < y+boxHeight; row++ {
+		for col := x; col < x+boxWidth; col++ {
+			screen.SetContent(col, row, ' ', nil, tcell.StyleDefault)
+		}
+	}
+	NewDrawBox("Commands", style).Draw(screen, x, y, boxWidth, boxHeight)
+
+	// The query is typed on the first line, the matches are listed below
+	innerX, innerWidth := x+1, boxWidth-2
+	prompt := "> " + p.query
+	var cells []cell
+	for _, ch := range prompt {
+		cells = append(cells, cell{ch, tcell.StyleDefault.Bold(true)})
+	}
+	drawCells(screen, innerX, y+1, innerWidth, cells, 0, tcell.StyleDefault)
+	screen.ShowCursor(innerX+min(cellsWidth(cells), innerWidth-1), y+1)
+
+	rows := boxHeight - 3
+	if p.selected < p.offset {
+		p.offset = p.selected
+	} else if p.selected >= p.offset+rows {
+		p.offset = p.selected - rows + 1
+	}
+
+	if len(p.matches) == 0 {
+		drawCells(screen, innerX, y+2, innerWidth, textCells("  No matching commands", tcell.StyleDefault.Dim(true)), 0, tcell.StyleDefault)
+		return
+	}
+	for i := p.offset; i < len(p.matches) && i < p.offset+rows; i++ {
+		p.drawItem(screen, innerX, y+2+i-p.offset, innerWidth, i)
+	}
+}
+
+// drawItem draws an item as its name, key binding and description
+func (p *Palette) drawItem(screen tcell.Screen, x, y, width, index int) {
+	item := p.matches[index].item
+
+	nameStyle := tcell.StyleDefault.Bold(true)
+	keyStyle := tcell.StyleDefault.Foreground(tcell.ColorYellow)
+	textStyle := tcell.StyleDefault
+	if index == p.selected {
+		nameStyle = nameStyle.Background(tcell.ColorBlue).Foreground(tcell.ColorWhite)
+		keyStyle = keyStyle.Background(tcell.ColorBlue)
+		textStyle = textStyle.Background(tcell.ColorBlue).Foreground(tcell.ColorWhite)
+	}
+
+	cells := textCells(" "+item.name, nameStyle)
+	for cellsWidth(cells) < 16 {
+		cells = append(cells, cell{' ', textStyle})
+	}
+	binding := item.binding
+	if binding == "" {
+		binding = "-"
+	}
+	cells = append(cells, textCells(binding, keyStyle)...)
+	for cellsWidth(cells) < 26 {
+		cells = append(cells, cell{' ', textStyle})
+	}
+	cells = append(cells, textCells(" "+item.description, textStyle)...)
+	for cellsWidth(cells) < width {
+		cells = append(cells, cell{' ', textStyle})
+	}
+	drawCells(screen, x, y, width, cells, 0, textStyle)
+}
+
+// textCells returns the cells of a text drawn in a single style
+func textCells(text string, style tcell.Style) []cell {
+	cells := make([]cell, 0, len(text))
+	for _, ch := range text {
+		cells = append(cells, cell{ch, style})
+	}
+	return cells
+}
+
+// openPalette shows the command palette
+func (t *Terminal) openPalette() {
+	t.palette = NewPalette(t.commandMgr.GetCommands(), t.keyBindingMgr)
+}
+
+// handlePaletteKey passes a key to the open palette and runs the chosen
+// item once it is closed
+func (t *Terminal) handlePaletteKey(ev *tcell.EventKey) error {
+	if ev.Key() == tcell.KeyCtrlV {
+		t.pasteClipboard()
+		t.draw()
+		return nil
+	}
+	if !t.palette.HandleKey(ev.Key(), ev.Rune(), ev.Modifiers()) {
+		t.draw()
+		return nil
+	}
+
+	item := t.palette.Chosen()
+	t.palette = nil
+	t.screen.HideCursor()
+	if item == nil {
+		t.draw()
+		return nil
+	}
+	return t.runPaletteItem(item)
+}
+
+// runPaletteItem runs an action as if its key was pressed, and a command
+// right away unless it needs arguments, which are prompted for on the
+// command line
+func (t *Terminal) runPaletteItem(item *paletteItem) error {
+	if item.key != nil {
+		return t.handleKeyEvent(tcell.NewEventKey(item.key.Key, item.key.Rune, item.key.Mods))
+	}
+
+	name := strings.TrimPrefix(item.name, ":")
+	if strings.Contains(item.usage, "<") {
+		t.commandMode = true
+		t.commandMgr.StartCommandModeWith(name + " ")
+		t.draw()
+		return nil
+	}
+
+	if cmd, ok := t.commandMgr.Get(name); ok {
+		if err := cmd.Handler(nil); err != nil {
+			t.viewManager.SetMessage("%v", err)
+		}
+	}
+	t.draw()
+	return nil
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	_, ok := fuzzyMatch("", "anything")
+	assert.True(t, ok)
+	_, ok = fuzzyMatch("stg", "stage")
+	assert.True(t, ok)
+	_, ok = fuzzyMatch("gts", "stage")
+	assert.False(t, ok)
+
+	// Word starts and consecutive characters rank higher
+	prefix, _ := fuzzyMatch("st", "stage")
+	scattered, _ := fuzzyMatch("st", "unstage")
+	assert.Greater(t, prefix, scattered)
+	words, _ := fuzzyMatch("mb", "merge-base")
+	inner, _ := fuzzyMatch("mb", "remembered")
+	assert.Greater(t, words, inner)
+}
+
+func TestPaletteFilter(t *testing.T) {
+	cfg := &config.Config{}
+	commands := map[string]*Command{
+		"refresh":    {Name: "refresh", Description: "Refresh all views"},
+		"merge-base": {Name: "merge-base", Description: "Select the merge base", Usage: "merge-base <rev>"},
+	}
+	p := NewPalette(commands, NewKeyBindingManager(cfg))
+
+	// Commands named like actions are listed once, as the action
+	count := 0
+	for _, item := range p.items {
+		if item.name == "refresh" || item.name == ":refresh" {
+			count++
+			assert.Equal(t, "R", item.binding)
+		}
+	}
+	assert.Equal(t, 1, count)
+
+	for _, ch := range "mergeb" {
+		p.HandleKey(tcell.KeyRune, ch, 0)
+	}
+	require.NotNil(t, p.Selected())
+	assert.Equal(t, ":merge-base", p.Selected().name)
+
+	p.HandleKey(tcell.KeyBackspace2, 0, 0)
+	assert.Equal(t, "merge", p.Query())
+
+	assert.True(t, p.HandleKey(tcell.KeyEsc, 0, 0))
+	assert.Nil(t, p.Chosen())
+}
+
+func TestTerminalPalette(t *testing.T) {
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	term := newTestTerminal(t, cfg)
+
+	// Choosing an action runs it like its key
+	require.NoError(t, term.handleKeyEvent(tcell.NewEventKey(tcell.KeyCtrlP, 0, 0)))
+	require.NotNil(t, term.palette)
+	for _, ch := range "show tree" {
+		require.NoError(t, term.handleKeyEvent(tcell.NewEventKey(tcell.KeyRune, ch, 0)))
+	}
+	assert.Equal(t, "tree", term.palette.Selected().name)
+	require.NoError(t, term.handleKeyEvent(tcell.NewEventKey(tcell.KeyEnter, 0, 0)))
+	assert.Nil(t, term.palette)
+	assert.Equal(t, ViewTypeTree, term.viewManager.GetCurrentView())
+
+	// Enter on an empty prompt opens the palette as well, and commands
+	// needing arguments prompt for them
+	require.NoError(t, term.handleKeyEvent(tcell.NewEventKey(tcell.KeyRune, ':', 0)))
+	require.NoError(t, term.handleKeyEvent(tcell.NewEventKey(tcell.KeyEnter, 0, 0)))
+	require.NotNil(t, term.palette)
+	for _, ch := range ":compare" {
+		require.NoError(t, term.handleKeyEvent(tcell.NewEventKey(tcell.KeyRune, ch, 0)))
+	}
+	require.NoError(t, term.handleKeyEvent(tcell.NewEventKey(tcell.KeyEnter, 0, 0)))
+	assert.True(t, term.commandMode)
+	assert.Equal(t, "compare ", term.commandMgr.GetBuffer())
+}
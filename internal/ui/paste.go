@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// pasteBuffer collects the keys of a bracketed paste, which are inserted
+// at once when it ends rather than run as key bindings
+type pasteBuffer struct {
+	active bool
+	text   strings.Builder
+}
+
+// handlePasteEvent starts or ends a bracketed paste
+func (t *Terminal) handlePasteEvent(ev *tcell.EventPaste) error {
+	if ev.Start() {
+		t.paste.active = true
+		t.paste.text.Reset()
+		return nil
+	}
+	if !t.paste.active {
+		return nil
+	}
+
+	t.paste.active = false
+	text := t.paste.text.String()
+	t.paste.text.Reset()
+	t.insertPasted(text)
+	t.draw()
+	return nil
+}
+
+// insertPasted inserts pasted text in the open prompt
+func (t *Terminal) insertPasted(text string) {
+	text = pastedLine(text)
+	switch {
+	case t.palette != nil:
+		t.palette.InsertText(text)
+	case t.commandMode:
+		t.commandMgr.InsertText(text)
+	default:
+		// Outside of prompts the pasted keys would run any binding they hit
+		t.viewManager.SetMessage("Paste ignored, open the prompt with : first")
+	}
+}
+
+// addPastedKey adds a key received during a bracketed paste
+func (t *Terminal) addPastedKey(ev *tcell.EventKey) {
+	switch ev.Key() {
+	case tcell.KeyRune:
+		t.paste.text.WriteRune(ev.Rune())
+	case tcell.KeyEnter, tcell.KeyLF:
+		t.paste.text.WriteRune('\n')
+	case tcell.KeyTab:
+		t.paste.text.WriteRune('\t')
+	}
+}
+
+// pastedLine turns pasted text into a single line for the prompts, line
+// breaks and tabs becoming spaces and other control characters dropped.
+// The line break ending a copied line is left out.
+func pastedLine(text string) string {
+	text = strings.TrimRight(text, "\r\n")
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\t':
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, text)
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPastedLine(t *testing.T) {
+	assert.Equal(t, "log HEAD", pastedLine("log HEAD\n"))
+	assert.Equal(t, "one two  three", pastedLine("one\ntwo\t three\r\n"))
+	assert.Equal(t, "bell", pastedLine("bel\al"))
+	assert.Equal(t, "日本語", pastedLine("日本語"))
+}
+
+// pasteKeys sends text to the terminal as a bracketed paste
+func pasteKeys(t *testing.T, term *Terminal, text string) {
+	t.Helper()
+	require.NoError(t, term.handleEvent(tcell.NewEventPaste(true)))
+	for _, ch := range text {
+		ev := tcell.NewEventKey(tcell.KeyRune, ch, 0)
+		if ch == '\n' {
+			ev = tcell.NewEventKey(tcell.KeyEnter, 0, 0)
+		}
+		require.NoError(t, term.handleEvent(ev))
+	}
+	require.NoError(t, term.handleEvent(tcell.NewEventPaste(false)))
+}
+
+func TestTerminalPaste(t *testing.T) {
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	term := newTestTerminal(t, cfg)
+
+	// Pasted keys don't run key bindings
+	pasteKeys(t, term, "h\n")
+	assert.Equal(t, ViewTypeMain, term.viewManager.GetCurrentView())
+	assert.Contains(t, term.viewManager.GetMessage(), "Paste ignored")
+
+	// The prompt takes them literally, Enter included
+	require.NoError(t, term.handleEvent(tcell.NewEventKey(tcell.KeyRune, ':', 0)))
+	pasteKeys(t, term, "compare\nmain\n")
+	assert.True(t, term.commandMode)
+	assert.Equal(t, "compare main", term.commandMgr.GetBuffer())
+	assert.Equal(t, len("compare main"), term.commandMgr.GetCursor())
+	require.NoError(t, term.handleEvent(tcell.NewEventKey(tcell.KeyEsc, 0, 0)))
+
+	require.NoError(t, term.handleEvent(tcell.NewEventKey(tcell.KeyCtrlP, 0, 0)))
+	pasteKeys(t, term, "tree")
+	require.NotNil(t, term.palette)
+	assert.Equal(t, "tree", term.palette.Query())
+
+	// Recordings replay the paste as one
+	var buf bytes.Buffer
+	term.SetRecorder(NewSessionRecorder(&buf))
+	pasteKeys(t, term, "x")
+	events, err := ReadSession(&buf)
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+	assert.Equal(t, []string{SessionPaste, SessionKey, SessionPaste, SessionFrame}, []string{events[0].Type, events[1].Type, events[2].Type, events[3].Type})
+	assert.True(t, events[0].tcellEvent().(*tcell.EventPaste).Start())
+	assert.True(t, events[2].tcellEvent().(*tcell.EventPaste).End())
+}
+
+func TestTerminalClipboardPaste(t *testing.T) {
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	cfg.General.ClipboardCommand = "echo 0123abc"
+	term := newTestTerminal(t, cfg)
+
+	require.NoError(t, term.handleEvent(tcell.NewEventKey(tcell.KeyRune, ':', 0)))
+	for _, ch := range "log " {
+		require.NoError(t, term.handleEvent(tcell.NewEventKey(tcell.KeyRune, ch, 0)))
+	}
+	require.NoError(t, term.handleEvent(tcell.NewEventKey(tcell.KeyCtrlV, 0, 0)))
+	assert.Equal(t, "log 0123abc", term.commandMgr.GetBuffer())
+	require.NoError(t, term.handleEvent(tcell.NewEventKey(tcell.KeyEsc, 0, 0)))
+
+	require.NoError(t, term.handleEvent(tcell.NewEventKey(tcell.KeyCtrlP, 0, 0)))
+	require.NoError(t, term.handleEvent(tcell.NewEventKey(tcell.KeyCtrlV, 0, 0)))
+	assert.Equal(t, "0123abc", term.palette.Query())
+	require.NoError(t, term.handleEvent(tcell.NewEventKey(tcell.KeyEsc, 0, 0)))
+
+	// Failing to read the clipboard is reported
+	cfg.General.ClipboardCommand = "false"
+	require.NoError(t, term.handleEvent(tcell.NewEventKey(tcell.KeyRune, ':', 0)))
+	require.NoError(t, term.handleEvent(tcell.NewEventKey(tcell.KeyCtrlV, 0, 0)))
+	assert.Empty(t, term.commandMgr.GetBuffer())
+	assert.Contains(t, term.viewManager.GetMessage(), "false failed")
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/azhao1981/tig/internal/git"
+)
+
+// pathIndex holds the entries of a directory of the repository, mapping
+// each name to whether it is a directory
+type pathIndex map[string]bool
+
+// buildPathIndex indexes the files of the index and of the worktree status
+// directly in dir, which is relative to the repository root
+func (vm *ViewManager) buildPathIndex(dir string) pathIndex {
+	index := make(pathIndex)
+	if files, err := vm.client.GetFiles(dir); err == nil {
+		for _, file := range files {
+			index[file.Path] = file.IsDir
+		}
+	}
+
+	// The status adds the files not in the index yet
+	status, err := vm.client.GetStatus()
+	if err != nil {
+		return index
+	}
+	prefix := ""
+	if dir != "" {
+		prefix = dir + "/"
+	}
+	for _, files := range [][]git.FileStatus{status.Untracked, status.Staged, status.Modified, status.Conflict} {
+		for _, file := range files {
+			if !strings.HasPrefix(file.Path, prefix) {
+				continue
+			}
+			name := strings.TrimPrefix(file.Path, prefix)
+			isDir := false
+			if i := strings.Index(name, "/"); i >= 0 {
+				name, isDir = name[:i], true
+			}
+			if name != "" {
+				index[name] = index[name] || isDir
+			}
+		}
+	}
+	return index
+}
+
+// CompletePath returns the files and directories of the index and the
+// worktree completing the path being typed. Paths are relative to the
+// repository root and directories end with a slash.
+func (vm *ViewManager) CompletePath(word string) []string {
+	vm.mutex.RLock()
+	defer vm.mutex.RUnlock()
+
+	if !vm.client.IsRepository() {
+		return nil
+	}
+
+	dir, base := "", word
+	if i := strings.LastIndex(word, "/"); i >= 0 {
+		dir, base = word[:i+1], word[i+1:]
+	}
+
+	var paths []string
+	for name, isDir := range vm.buildPathIndex(strings.Trim(dir, "/")) {
+		if !strings.HasPrefix(name, base) {
+			continue
+		}
+		path := dir + name
+		if isDir {
+			path += "/"
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
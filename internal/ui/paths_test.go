@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompletePath(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	refsTestGit(t, dir, "init", "--quiet", "--initial-branch=main")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "internal", "ui"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "internal", "ui", "view.go"), []byte("package ui\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "internal", "index.go"), []byte("package internal\n"), 0644))
+	refsTestGit(t, dir, "add", ".")
+	refsTestGit(t, dir, "commit", "--quiet", "-m", "first")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "internal", "new.go"), []byte("package internal\n"), 0644))
+
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+
+	assert.Equal(t, []string{"internal/"}, vm.CompletePath("in"))
+	assert.Equal(t, []string{"internal/index.go", "internal/new.go", "internal/ui/"}, vm.CompletePath("internal/"))
+	assert.Equal(t, []string{"internal/new.go"}, vm.CompletePath("internal/n"))
+	assert.Equal(t, []string{"internal/ui/view.go"}, vm.CompletePath("internal/ui/v"))
+	assert.Empty(t, vm.CompletePath("nope/"))
+
+	// Directories are completed without closing the argument
+	cm := NewCommandManager()
+	cm.Register(&Command{Name: "add", Handler: func([]string) error { return nil }, Complete: vm.CompletePath})
+	cm.StartCommandModeWith("add in")
+	cm.HandleKey(tcell.KeyTab, 0, 0)
+	assert.Equal(t, "add internal/", cm.GetBuffer())
+	cm.HandleKey(tcell.KeyRune, 'u', 0)
+	cm.HandleKey(tcell.KeyTab, 0, 0)
+	assert.Equal(t, "add internal/ui/", cm.GetBuffer())
+	cm.HandleKey(tcell.KeyTab, 0, 0)
+	assert.Equal(t, "add internal/ui/view.go ", cm.GetBuffer())
+}
@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// commitKeys are the keys of the main view acting on the selected commit
+// outside of visual mode. Revert is on b, to back the commit out, as V
+// starts the visual range and u unstages everywhere else.
+var commitKeys = map[rune]string{
+	'C': "cherry-pick",
+	'b': "revert",
+}
+
+// runMainAction runs the action of a key of the main view: cherry-picking
+// or reverting the selected commit, or acting on the visual range
+func (vm *ViewManager) runMainAction(action string) {
+	switch action {
+	case "cherry-pick", "revert":
+		commit := vm.getSelectedCommit()
+		if commit == nil {
+			return
+		}
+		if err := vm.pickCommit(commit.Hash, action == "revert"); err != nil {
+			vm.setMessage("%v", err)
+		}
+	default:
+		vm.runRangeAction(action)
+	}
+}
+
+// CherryPickCommand handles the :cherry-pick command, which applies the
+// changes of a commit to the current branch. The commit defaults to the
+// one selected in the main view.
+func (vm *ViewManager) CherryPickCommand(args []string) error {
+	return vm.pickCommand(args, false)
+}
+
+// RevertCommand handles the :revert command, which undoes the changes of a
+// commit on the current branch. The commit defaults to the one selected in
+// the main view.
+func (vm *ViewManager) RevertCommand(args []string) error {
+	return vm.pickCommand(args, true)
+}
+
+// pickCommand cherry-picks or reverts the commit given to a command, or
+// the selected one
+func (vm *ViewManager) pickCommand(args []string, revert bool) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if len(args) > 1 {
+		if revert {
+			return fmt.Errorf("usage: revert [commit]")
+		}
+		return fmt.Errorf("usage: cherry-pick [commit]")
+	}
+	rev := optionalArg(args)
+	if rev == "" {
+		commit := vm.getSelectedCommit()
+		if commit == nil {
+			return fmt.Errorf("no commit selected")
+		}
+		rev = commit.Hash
+	} else if err := vm.checkRevisions(rev); err != nil {
+		return err
+	}
+	return vm.pickCommit(rev, revert)
+}
+
+// pickCommit asks to cherry-pick a commit onto the current branch, or to
+// revert it there, and does so once confirmed, offering to stash the local
+// changes in the way. Conflicts leave the branch as it was.
+func (vm *ViewManager) pickCommit(rev string, revert bool) error {
+	hash, err := vm.client.ResolveRevision(rev)
+	if err != nil {
+		return err
+	}
+	commit, err := vm.client.GetCommit(hash)
+	if err != nil {
+		return err
+	}
+	status, err := vm.client.GetBranchStatus()
+	if err != nil {
+		return err
+	}
+	branch := status.Branch
+	if branch == "" {
+		branch = "HEAD"
+	}
+
+	abbrev := vm.client.AbbrevHash(hash)
+	name, command := "cherry-pick", "git cherry-pick -x "+abbrev
+	prompt := fmt.Sprintf("Cherry-pick %s onto %s?", abbrev, branch)
+	done := fmt.Sprintf("Cherry-picked %s onto %s", abbrev, branch)
+	pick := vm.client.CherryPick
+	if revert {
+		name, command = "revert", "git revert --no-edit "+abbrev
+		prompt = fmt.Sprintf("Revert %s on %s?", abbrev, branch)
+		done = fmt.Sprintf("Reverted %s on %s", abbrev, branch)
+		pick = vm.client.Revert
+	}
+	if len(commit.Parents) > 1 {
+		return fmt.Errorf("%s is a merge, which git %s needs told which parent to follow", abbrev, name)
+	}
+
+	details := []string{abbrev + " " + commit.Summary, command}
+	vm.askConfirmation(prompt, details, func() error {
+		return vm.runAutostashed(autostashOp{
+			name: name + " of " + abbrev,
+			done: done,
+			run: func() error {
+				result, err := pick(hash)
+				if err != nil {
+					return err
+				}
+				if len(result.Conflicts) > 0 {
+					return fmt.Errorf("%s of %s aborted, conflicts in: %s", strings.ToUpper(name[:1])+name[1:], abbrev, strings.Join(result.Conflicts, ", "))
+				}
+				return nil
+			},
+		})
+	})
+	return nil
+}
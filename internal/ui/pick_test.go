@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCherryPickAndRevert(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	refsTestGit(t, dir, "config", "user.name", "Test")
+	refsTestGit(t, dir, "config", "user.email", "test@example.com")
+	topic := vm.client.AbbrevHash(refsTestGit(t, dir, "rev-parse", "topic"))
+	readFile := func() string {
+		content, err := os.ReadFile(filepath.Join(dir, "file.txt"))
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	require.NoError(t, vm.CherryPickCommand([]string{"topic"}))
+	require.True(t, vm.Confirming())
+	assert.Equal(t, "Cherry-pick "+topic+" onto main?", vm.confirmation.prompt)
+	assert.Equal(t, []string{topic + " topic", "git cherry-pick -x " + topic}, vm.confirmation.details)
+	vm.HandleKey(tcell.KeyRune, 'y', 0)
+	assert.Equal(t, "Cherry-picked "+topic+" onto main", vm.GetMessage())
+	assert.Equal(t, "topic\n", readFile())
+
+	// Keys of the main view act on the selected commit
+	require.NoError(t, vm.SwitchView(ViewTypeMain))
+	mainView := vm.views[ViewTypeMain].(*MainView)
+	picked := refsTestGit(t, dir, "rev-parse", "HEAD")
+	require.True(t, mainView.selectCommit(picked))
+	// u is left to unstaging
+	vm.HandleKey(tcell.KeyRune, 'u', 0)
+	assert.False(t, vm.Confirming())
+	vm.HandleKey(tcell.KeyRune, 'b', 0)
+	require.True(t, vm.Confirming())
+	assert.Equal(t, "Revert "+vm.client.AbbrevHash(picked)+" on main?", vm.confirmation.prompt)
+	vm.HandleKey(tcell.KeyRune, 'y', 0)
+	assert.Equal(t, "Reverted "+vm.client.AbbrevHash(picked)+" on main", vm.GetMessage())
+	assert.Equal(t, "base\n", readFile())
+
+	// Conflicts leave the branch as it was
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("main\n"), 0644))
+	refsTestGit(t, dir, "commit", "--quiet", "-am", "main")
+	head := refsTestGit(t, dir, "rev-parse", "HEAD")
+	require.NoError(t, vm.CherryPickCommand([]string{"topic"}))
+	vm.HandleKey(tcell.KeyRune, 'y', 0)
+	assert.Equal(t, "Cherry-pick of "+topic+" aborted, conflicts in: file.txt", vm.GetMessage())
+	assert.Equal(t, head, refsTestGit(t, dir, "rev-parse", "HEAD"))
+	assert.Equal(t, "main\n", readFile())
+
+	assert.EqualError(t, vm.RevertCommand([]string{"a", "b"}), "usage: revert [commit]")
+	vm.config.General.ReadOnly = true
+	vm.HandleKey(tcell.KeyRune, 'C', 0)
+	assert.Equal(t, "cherry-pick is disabled in read-only mode", vm.GetMessage())
+}
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Selection describes what is selected in a view, as seen by the commands
+// placeholders are expanded for
+type Selection struct {
+	Commit    string
+	Branch    string
+	File      string
+	Directory string
+	Ref       string
+	Stash     string
+	Lineno    int
+}
+
+// SelectionSource is implemented by views which can describe their
+// selection for placeholder expansion
+type SelectionSource interface {
+	// Selection returns the state of the item under the cursor
+	Selection() Selection
+}
+
+// placeholderRe matches a %(name) placeholder
+var placeholderRe = regexp.MustCompile(`%\(([a-z]+)\)`)
+
+// Value returns the expansion of the named placeholder
+func (s Selection) Value(name string) (string, bool) {
+	switch name {
+	case "commit":
+		return s.Commit, true
+	case "branch":
+		return s.Branch, true
+	case "file":
+		return s.File, true
+	case "directory":
+		return s.Directory, true
+	case "ref":
+		return s.Ref, true
+	case "stash":
+		return s.Stash, true
+	case "lineno":
+		return strconv.Itoa(s.Lineno), true
+	}
+	return "", false
+}
+
+// ExpandPlaceholders replaces the placeholders in each argument with the
+// values of the selection. Arguments are expanded separately so values
+// containing spaces stay a single argument.
+func ExpandPlaceholders(args []string, sel Selection) ([]string, error) {
+	expanded := make([]string, 0, len(args))
+	for _, arg := range args {
+		var unknown string
+		arg = placeholderRe.ReplaceAllStringFunc(arg, func(match string) string {
+			name := match[2 : len(match)-1]
+			value, ok := sel.Value(name)
+			if !ok && unknown == "" {
+				unknown = match
+			}
+			return value
+		})
+		if unknown != "" {
+			return nil, fmt.Errorf("unknown placeholder: %s", unknown)
+		}
+		expanded = append(expanded, arg)
+	}
+	return expanded, nil
+}
+
+// ResolvePlaceholders expands placeholders against the selection of the
+// current view
+func (vm *ViewManager) ResolvePlaceholders(args []string) ([]string, error) {
+	vm.mutex.RLock()
+	defer vm.mutex.RUnlock()
+	return ExpandPlaceholders(args, vm.selection())
+}
+
+// selection returns the selection of the current view, filling in what the
+// view does not know from the repository
+func (vm *ViewManager) selection() Selection {
+	var sel Selection
+	if source, ok := vm.views[vm.currentView].(SelectionSource); ok {
+		sel = source.Selection()
+	}
+
+	if sel.Branch == "" && vm.client.IsRepository() {
+		if head, err := vm.client.GetHead(); err == nil && strings.HasPrefix(head.Name, "refs/heads/") {
+			sel.Branch = strings.TrimPrefix(head.Name, "refs/heads/")
+		}
+	}
+	if sel.Ref == "" {
+		sel.Ref = "HEAD"
+	}
+	if sel.Directory == "" && sel.File != "" {
+		sel.Directory = path.Dir(sel.File)
+	}
+	if sel.Directory == "" {
+		sel.Directory = "."
+	}
+	return sel
+}
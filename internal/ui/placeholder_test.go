@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandPlaceholders(t *testing.T) {
+	sel := Selection{
+		Commit:    "abc123",
+		Branch:    "main",
+		File:      "dir/my file.go",
+		Directory: "dir",
+		Ref:       "v1.0",
+		Stash:     "stash@{0}",
+		Lineno:    42,
+	}
+
+	args, err := ExpandPlaceholders([]string{"vim", "+%(lineno)", "%(file)"}, sel)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"vim", "+42", "dir/my file.go"}, args)
+
+	args, err = ExpandPlaceholders([]string{"%(branch)..%(ref)", "%(commit)", "%(directory)", "%(stash)"}, sel)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"main..v1.0", "abc123", "dir", "stash@{0}"}, args)
+
+	_, err = ExpandPlaceholders([]string{"%(unknown)"}, sel)
+	assert.Error(t, err)
+
+	// Text that merely looks like a format string is left alone
+	args, err = ExpandPlaceholders([]string{"--format=%h %s"}, sel)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"--format=%h %s"}, args)
+}
+
+func TestViewManagerSelection(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	err := screen.Init()
+	assert.NoError(t, err)
+	cfg := &config.Config{}
+	client := git.NewClient()
+	keyBindingMgr := NewKeyBindingManager(cfg)
+
+	vm := NewViewManager(screen, cfg, client, keyBindingMgr)
+
+	mainView := vm.views[ViewTypeMain].(*MainView)
+	mainView.commits = []*git.Commit{{Hash: "abc123"}}
+	mainView.selected = 0
+
+	args, err := vm.ResolvePlaceholders([]string{"%(commit)", "%(ref)", "%(directory)", "%(lineno)"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abc123", "HEAD", ".", "0"}, args)
+
+	statusView := vm.views[ViewTypeStatus].(*StatusView)
+	statusView.status = &git.Status{Modified: []git.FileStatus{{Path: "src/main.go"}}}
+	_ = vm.SwitchView(ViewTypeStatus)
+	statusView.selected = 0
+
+	args, err = vm.ResolvePlaceholders([]string{"%(file)", "%(directory)", "%(commit)"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"src/main.go", "src", ""}, args)
+}
+
+func TestExternalCommandBinding(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Keymaps.Bindings = map[string]string{"!git show %(commit)": "x"}
+	keyBindingMgr := NewKeyBindingManager(cfg)
+
+	action, ok := keyBindingMgr.MatchEvent(tcell.KeyRune, 'x', 0)
+	assert.True(t, ok)
+	assert.Equal(t, "!git show %(commit)", action)
+
+	cm := NewCommandManager()
+	var got []string
+	cm.Register(&Command{Name: "!", Handler: func(args []string) error {
+		got = args
+		return nil
+	}})
+	cm.StartCommandModeWith("!tig show %(commit)")
+	assert.NoError(t, cm.Execute())
+	assert.Equal(t, []string{"tig", "show", "%(commit)"}, got)
+}
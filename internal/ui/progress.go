@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// remoteJobs are the kinds of jobs talking to remotes, which may take a
+// while, their progress shown over the view as they run
+var remoteJobs = map[string]bool{jobFetch: true, jobPull: true, jobPush: true}
+
+// spinnerFrames animate the progress overlay, one a second
+var spinnerFrames = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+
+// progressJob returns the last started of the running jobs talking to a
+// remote whose progress is shown, nil when there is none
+func (vm *ViewManager) progressJob() *job {
+	for i := len(vm.jobs) - 1; i >= 0; i-- {
+		if j := vm.jobs[i]; j.running() && !j.quiet && remoteJobs[j.kind] {
+			return j
+		}
+	}
+	return nil
+}
+
+// ShowsProgress returns whether the progress of a job is shown, to be
+// redrawn as it goes
+func (vm *ViewManager) ShowsProgress() bool {
+	vm.mutex.RLock()
+	defer vm.mutex.RUnlock()
+
+	return vm.progressJob() != nil
+}
+
+// drawProgress draws the progress of the job talking to a remote in a box
+// over the bottom right of the view
+func (vm *ViewManager) drawProgress() {
+	j := vm.progressJob()
+	if j == nil {
+		return
+	}
+	boxWidth, boxHeight := min(60, vm.width-4), 3
+	if boxWidth < 20 || vm.height < boxHeight+3 {
+		return
+	}
+	x, y := vm.width-boxWidth-1, vm.height-boxHeight-1
+
+	drawPopupBox(vm.screen, j.name, tcell.StyleDefault.Foreground(tcell.ColorYellow), x, y, boxWidth, boxHeight)
+
+	progress := j.progress
+	if progress == "" {
+		progress = "Waiting for the remote"
+	}
+	frame := spinnerFrames[int(j.duration().Seconds())%len(spinnerFrames)]
+	line := textCells(string(frame)+" ", tcell.StyleDefault.Foreground(tcell.ColorYellow))
+	line = append(line, textCells(progress, tcell.StyleDefault)...)
+	drawCells(vm.screen, x+2, y+1, boxWidth-4, line, 0, tcell.StyleDefault)
+}
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+)
+
+// PushCommand handles the :push command, which pushes the branch or tag
+// selected in the refs view, or the current branch from other views, to a
+// remote, by default the one git push uses.
+// With --force the remote ref is overwritten even when it has commits the
+// pushed one hasn't.
+func (vm *ViewManager) PushCommand(args []string) error {
+	force := len(args) > 0 && args[0] == "--force"
+	if force {
+		args = args[1:]
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("usage: push [--force] [remote]")
+	}
+
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	return vm.pushSelectedRef(optionalArg(args), false, force)
+}
+
+// DeleteRemoteCommand handles the :delete-remote command, which deletes the
+// branch or tag selected in the refs view from a remote
+func (vm *ViewManager) DeleteRemoteCommand(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: delete-remote [remote]")
+	}
+
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	return vm.pushSelectedRef(optionalArg(args), true, false)
+}
+
+// optionalArg returns the only argument of a command, if given
+func optionalArg(args []string) string {
+	if len(args) == 1 {
+		return args[0]
+	}
+	return ""
+}
+
+// pushSelectedRef asks to push the branch or tag selected in the refs view,
+// or the current branch from other views, to remote, forcing it with force,
+// or with remove to delete it there, and does so in the background once
+// confirmed
+func (vm *ViewManager) pushSelectedRef(remote string, remove, force bool) error {
+	item, err := vm.refToPush(remove)
+	if err != nil {
+		return err
+	}
+
+	if remote == "" {
+		branch := ""
+		if item.Type == "branch" {
+			branch = item.Name
+		}
+		if remote, err = vm.client.PushRemote(branch); err != nil {
+			return err
+		}
+	}
+
+	ref := refName(item)
+	refspec := ref
+	prompt := fmt.Sprintf("Push %s %s to %s?", item.Type, item.Name, remote)
+	name := fmt.Sprintf("Pushing %s to %s", item.Name, remote)
+	result := fmt.Sprintf("Pushed %s %s to %s", item.Type, item.Name, remote)
+	if force {
+		refspec = "+" + ref
+		prompt = fmt.Sprintf("Force push %s %s to %s?", item.Type, item.Name, remote)
+		name = fmt.Sprintf("Force pushing %s to %s", item.Name, remote)
+		result = fmt.Sprintf("Force pushed %s %s to %s", item.Type, item.Name, remote)
+	}
+	if remove {
+		refspec = ":" + ref
+		prompt = fmt.Sprintf("Delete %s %s from %s?", item.Type, item.Name, remote)
+		name = fmt.Sprintf("Deleting %s from %s", item.Name, remote)
+		result = fmt.Sprintf("Deleted %s %s from %s", item.Type, item.Name, remote)
+	}
+	details := []string{
+		fmt.Sprintf("%s %s at %s", item.Type, item.Name, vm.client.AbbrevHash(item.Hash)),
+		"git push " + remote + " " + refspec,
+	}
+
+	push := func() error {
+		vm.startJob(&job{kind: jobPush, name: name}, func(ctx context.Context, progress func(string)) error {
+			return vm.client.Push(ctx, remote, []string{refspec}, progress)
+		}, func(err error) string {
+			if err != nil {
+				return err.Error()
+			}
+			vm.refreshAll()
+			return result
+		})
+		return nil
+	}
+	if force {
+		details = append(details, "Commits of "+remote+" the "+item.Type+" doesn't have are lost there.")
+		return vm.confirmAction("force-push", prompt, item.Name, details, push)
+	}
+	vm.askConfirmation(prompt, details, push)
+	return nil
+}
+
+// refToPush returns the branch or tag selected in the refs view, or from
+// other views the current branch. Refs are only deleted from remotes from
+// the refs view.
+func (vm *ViewManager) refToPush(remove bool) (*RefItem, error) {
+	if view, ok := vm.views[ViewTypeRefs].(*RefsView); ok && vm.currentView == ViewTypeRefs {
+		item := view.SelectedItem()
+		if item == nil || refName(item) == "" {
+			return nil, fmt.Errorf("no branch or tag selected")
+		}
+		return item, nil
+	}
+	if remove {
+		return nil, fmt.Errorf("select a branch or tag in the refs view first")
+	}
+
+	status, err := vm.client.GetBranchStatus()
+	if err != nil {
+		return nil, err
+	}
+	if status.Branch == "" {
+		return nil, fmt.Errorf("HEAD is detached, select a branch or tag in the refs view")
+	}
+	head, err := vm.client.GetHead()
+	if err != nil {
+		return nil, err
+	}
+	return &RefItem{Type: "branch", Name: status.Branch, Hash: head.Hash, Current: true}, nil
+}
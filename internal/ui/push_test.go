@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForMessage waits until the status line message starts with prefix
+func waitForMessage(t *testing.T, driver *Driver, prefix string) string {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		message, err := driver.Message()
+		require.NoError(t, err)
+		if strings.HasPrefix(message, prefix) || time.Now().After(deadline) {
+			return message
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPushSelectedRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir, remote := t.TempDir(), t.TempDir()
+	refsTestGit(t, remote, "init", "--quiet", "--bare")
+	refsTestGit(t, dir, "init", "--quiet", "--initial-branch=main")
+	refsTestGit(t, dir, "commit", "--quiet", "--allow-empty", "-m", "initial")
+	refsTestGit(t, dir, "branch", "topic")
+	refsTestGit(t, dir, "remote", "add", "origin", remote)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	cfg.General.RefreshInterval = 0
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	driver, err := NewDriver(cfg, client, dir, 100, 30)
+	require.NoError(t, err)
+	require.NoError(t, driver.Start())
+	defer driver.Stop()
+
+	// Outside of the refs view the current branch is pushed
+	require.NoError(t, driver.SendKeys("P"))
+	screen, err := driver.Screenshot()
+	require.NoError(t, err)
+	assert.Contains(t, screen, "Push branch main to origin? [y/N]")
+	require.NoError(t, driver.SendKeys("n"))
+	require.NoError(t, driver.SendKeys(":", "delete-remote", "<Enter>"))
+	message, err := driver.Message()
+	require.NoError(t, err)
+	assert.Equal(t, "select a branch or tag in the refs view first", message)
+
+	require.NoError(t, driver.SendKeys("r", "j", "P"))
+	screen, err = driver.Screenshot()
+	require.NoError(t, err)
+	assert.Contains(t, screen, "Push branch topic to origin? [y/N]")
+	assert.Contains(t, screen, "git push origin refs/heads/topic")
+
+	require.NoError(t, driver.SendKeys("y"))
+	assert.Equal(t, "Pushed branch topic to origin", waitForMessage(t, driver, "Pushed"))
+	assert.Equal(t, "topic", refsTestGit(t, remote, "for-each-ref", "--format=%(refname:short)"))
+
+	require.NoError(t, driver.SendKeys("X"))
+	screen, err = driver.Screenshot()
+	require.NoError(t, err)
+	assert.Contains(t, screen, "Delete branch topic from origin? [y/N]")
+	assert.Contains(t, screen, "git push origin :refs/heads/topic")
+
+	require.NoError(t, driver.SendKeys("y"))
+	assert.Equal(t, "Deleted branch topic from origin", waitForMessage(t, driver, "Deleted"))
+	assert.Empty(t, refsTestGit(t, remote, "for-each-ref"))
+
+	// Force pushing a rewritten branch asks for its name by default
+	refsTestGit(t, dir, "push", "--quiet", "origin", "topic")
+	refsTestGit(t, dir, "checkout", "--quiet", "topic")
+	refsTestGit(t, dir, "commit", "--quiet", "--amend", "--allow-empty", "-m", "rewritten")
+	require.NoError(t, driver.SendKeys(":", "push --force", "<Enter>"))
+	screen, err = driver.Screenshot()
+	require.NoError(t, err)
+	assert.Contains(t, screen, "Force push branch topic to origin? Type topic to confirm:")
+	assert.Contains(t, screen, "git push origin +refs/heads/topic")
+	require.NoError(t, driver.SendKeys("topic", "<Enter>"))
+	assert.Equal(t, "Force pushed branch topic to origin", waitForMessage(t, driver, "Force pushed"))
+	assert.Equal(t, refsTestGit(t, dir, "rev-parse", "topic"), refsTestGit(t, remote, "rev-parse", "topic"))
+
+	// Failures are reported once the push is done
+	require.NoError(t, driver.SendKeys(":", "push nowhere", "<Enter>", "y"))
+	assert.Contains(t, waitForMessage(t, driver, "git push"), "nowhere")
+}
@@ -0,0 +1,360 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// RangeDiffView compares two versions of a patch series, such as a branch
+// before and after a rebase
+type RangeDiffView struct {
+	*BaseView
+	*Scrollable
+	config   *config.Config
+	client   git.Client
+	ranges   []string
+	entries  []*git.RangeDiffEntry
+	expanded map[int]bool
+	rows     []rangeDiffRow
+	selected int
+	repoPath string
+	frame    *Frame
+}
+
+// rangeDiffRow is a rendered line of the range-diff
+type rangeDiffRow struct {
+	text  string
+	style tcell.Style
+	entry int // Index of the entry the row belongs to
+}
+
+// NewRangeDiffView creates a new range-diff view
+func NewRangeDiffView(config *config.Config, client git.Client) *RangeDiffView {
+	return &RangeDiffView{
+		BaseView:   NewBaseView(ViewTypeRangeDiff),
+		Scrollable: NewScrollable(),
+		config:     config,
+		client:     client,
+		expanded:   make(map[int]bool),
+		frame:      NewFrame(config, "Range-diff"),
+	}
+}
+
+// Render renders the range-diff view
+func (v *RangeDiffView) Render(screen tcell.Screen, x, y, width, height int) error {
+	v.SetPosition(x, y, width, height)
+	v.SetHeight(height - 2) // Account for borders
+
+	// Draw the frame, and its scrollbar once the content is laid out
+	contentX, contentY, contentWidth, contentHeight := v.frame.Draw(screen, x, y, width, height)
+	defer v.frame.DrawScrollbar(screen, v.Scrollable)
+
+	if contentWidth <= 0 || contentHeight <= 0 {
+		return nil
+	}
+
+	v.renderRows(screen, contentX, contentY, contentWidth, contentHeight)
+
+	return nil
+}
+
+// renderRows renders the visible rows
+func (v *RangeDiffView) renderRows(screen tcell.Screen, x, y, width, height int) {
+	if len(v.rows) == 0 {
+		msg := "No commits to compare"
+		if len(v.ranges) == 0 {
+			msg = "No ranges given"
+		} else if !v.client.IsRepository() {
+			msg = "Not in a git repository"
+		}
+
+		msgX := x + (width-len(msg))/2
+		msgY := y + height/2
+		if msgX >= x && msgY >= y {
+			for i, char := range msg {
+				screen.SetContent(msgX+i, msgY, char, nil, tcell.StyleDefault)
+			}
+		}
+		return
+	}
+
+	v.SetMaxOffset(len(v.rows) - height)
+
+	start := v.GetOffset()
+	end := start + height
+	if end > len(v.rows) {
+		end = len(v.rows)
+	}
+
+	for i := start; i < end; i++ {
+		row := v.rows[i]
+		style := row.style
+		if i == v.selected && v.IsFocused() {
+			style = style.Background(tcell.ColorBlue)
+		} else if i == v.selected {
+			style = style.Background(tcell.ColorDarkBlue)
+		}
+
+		cells := newWhitespaceMarks(v.config).cells(row.text, style, -1)
+		drawCells(screen, x, y+(i-start), width, cells, 0, style)
+	}
+}
+
+// buildRows renders the entries, expanding the patch differences of the
+// modified commits that were opened
+func (v *RangeDiffView) buildRows() {
+	v.rows = v.rows[:0]
+	for i, entry := range v.entries {
+		v.rows = append(v.rows, rangeDiffRow{
+			text:  formatRangeDiffEntry(entry, v.expanded[i]),
+			style: rangeDiffStatusStyle(entry.Status),
+			entry: i,
+		})
+		if !v.expanded[i] {
+			continue
+		}
+		for _, line := range entry.Diff {
+			v.rows = append(v.rows, rangeDiffRow{
+				text:  "      " + line,
+				style: rangeDiffLineStyle(line),
+				entry: i,
+			})
+		}
+	}
+	if v.selected >= len(v.rows) {
+		v.selected = max(0, len(v.rows)-1)
+	}
+}
+
+// formatRangeDiffEntry formats an entry like git range-diff does, with a
+// marker showing whether the patch difference can be expanded
+func formatRangeDiffEntry(entry *git.RangeDiffEntry, expanded bool) string {
+	side := func(index int, hash string) string {
+		if index == 0 {
+			return "-:  -------"
+		}
+		return fmt.Sprintf("%d:  %s", index, hash)
+	}
+
+	marker := " "
+	if len(entry.Diff) > 0 {
+		marker = "+"
+		if expanded {
+			marker = "-"
+		}
+	}
+
+	return fmt.Sprintf("%s %s %c %s %s", marker, side(entry.OldIndex, entry.OldHash), entry.Status, side(entry.NewIndex, entry.NewHash), entry.Subject)
+}
+
+// rangeDiffStatusStyle returns the style of an entry with the given status
+func rangeDiffStatusStyle(status git.RangeDiffStatus) tcell.Style {
+	switch status {
+	case git.RangeDiffModified:
+		return tcell.StyleDefault.Foreground(tcell.ColorYellow)
+	case git.RangeDiffDropped:
+		return tcell.StyleDefault.Foreground(tcell.ColorRed)
+	case git.RangeDiffAdded:
+		return tcell.StyleDefault.Foreground(tcell.ColorGreen)
+	}
+	return tcell.StyleDefault
+}
+
+// rangeDiffLineStyle returns the style of a patch difference line
+func rangeDiffLineStyle(line string) tcell.Style {
+	switch {
+	case strings.HasPrefix(line, "@@"):
+		return tcell.StyleDefault.Foreground(tcell.ColorPurple)
+	case strings.HasPrefix(line, "+"):
+		return tcell.StyleDefault.Foreground(tcell.ColorGreen)
+	case strings.HasPrefix(line, "-"):
+		return tcell.StyleDefault.Foreground(tcell.ColorRed)
+	}
+	return tcell.StyleDefault
+}
+
+// HandleKey handles keyboard input
+func (v *RangeDiffView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
+	if !v.IsFocused() {
+		return false
+	}
+
+	switch key {
+	case tcell.KeyUp:
+		v.moveUp()
+		return true
+	case tcell.KeyDown:
+		v.moveDown()
+		return true
+	case tcell.KeyEnter:
+		v.toggleSelected()
+		return true
+	case tcell.KeyHome:
+		v.ScrollToTop()
+		v.selected = 0
+		return true
+	case tcell.KeyEnd:
+		v.ScrollToBottom()
+		v.selected = max(0, len(v.rows)-1)
+		return true
+	}
+
+	switch ch {
+	case 'j':
+		v.moveDown()
+		return true
+	case 'k':
+		v.moveUp()
+		return true
+	case 'g':
+		v.ScrollToTop()
+		v.selected = 0
+		return true
+	case 'G':
+		v.ScrollToBottom()
+		v.selected = max(0, len(v.rows)-1)
+		return true
+	}
+
+	return false
+}
+
+// toggleSelected expands or collapses the patch difference of the entry
+// under the selection, keeping the selection on the entry
+func (v *RangeDiffView) toggleSelected() {
+	if v.selected >= len(v.rows) {
+		return
+	}
+
+	entry := v.rows[v.selected].entry
+	if len(v.entries[entry].Diff) == 0 {
+		return
+	}
+	v.expanded[entry] = !v.expanded[entry]
+	v.buildRows()
+
+	for i, row := range v.rows {
+		if row.entry == entry {
+			v.selected = i
+			if v.selected < v.GetOffset() {
+				v.SetOffset(v.selected)
+			}
+			break
+		}
+	}
+}
+
+// moveUp moves selection up
+func (v *RangeDiffView) moveUp() {
+	if v.selected > 0 {
+		v.selected--
+		if v.selected < v.GetOffset() {
+			v.ScrollUp()
+		}
+	}
+}
+
+// moveDown moves selection down
+func (v *RangeDiffView) moveDown() {
+	if v.selected < len(v.rows)-1 {
+		v.selected++
+		visibleEnd := v.GetOffset() + v.getPageSize()
+		if v.selected >= visibleEnd {
+			v.ScrollDown()
+		}
+	}
+}
+
+// getPageSize returns the number of visible lines
+func (v *RangeDiffView) getPageSize() int {
+	_, _, _, height := v.GetPosition()
+	return height - 2 // Account for borders
+}
+
+// Refresh reruns the range-diff, keeping expanded entries open
+func (v *RangeDiffView) Refresh() error {
+	if !v.client.IsRepository() || len(v.ranges) == 0 {
+		v.entries = nil
+		v.buildRows()
+		return nil
+	}
+
+	entries, err := v.client.CompareSeries(v.ranges...)
+	if err != nil {
+		return err
+	}
+
+	v.entries = entries
+	v.buildRows()
+	return nil
+}
+
+// SetRanges sets the ranges to compare
+func (v *RangeDiffView) SetRanges(ranges []string) error {
+	v.ranges = ranges
+	v.expanded = make(map[int]bool)
+	v.frame.Title = "Range-diff " + strings.Join(ranges, " ")
+	v.selected = 0
+	v.ScrollToTop()
+	return v.Refresh()
+}
+
+// GetRanges returns the ranges being compared
+func (v *RangeDiffView) GetRanges() []string {
+	return v.ranges
+}
+
+// PagerArgs returns the git arguments producing the raw range-diff
+func (v *RangeDiffView) PagerArgs() ([]string, error) {
+	if len(v.ranges) == 0 {
+		return nil, fmt.Errorf("no ranges given")
+	}
+	return append([]string{"range-diff"}, v.ranges...), nil
+}
+
+// Selection returns the commit of the selected entry for placeholder
+// expansion, preferring the new version of the patch
+func (v *RangeDiffView) Selection() Selection {
+	var sel Selection
+	if v.selected >= len(v.rows) {
+		return sel
+	}
+
+	entry := v.entries[v.rows[v.selected].entry]
+	sel.Commit = entry.NewHash
+	if sel.Commit == "" {
+		sel.Commit = entry.OldHash
+	}
+	return sel
+}
+
+// SetRepoPath sets the repository path
+func (v *RangeDiffView) SetRepoPath(path string) {
+	v.repoPath = path
+}
+
+// RangeDiffCommand handles the :range-diff command
+func (vm *ViewManager) RangeDiffCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if len(args) < 1 || len(args) > 3 {
+		return fmt.Errorf("usage: range-diff <old-range> <new-range>")
+	}
+	if err := vm.checkRevisions(args...); err != nil {
+		return err
+	}
+
+	view, ok := vm.views[ViewTypeRangeDiff].(*RangeDiffView)
+	if !ok {
+		return fmt.Errorf("range-diff view not found")
+	}
+	if err := view.SetRanges(args); err != nil {
+		return err
+	}
+	return vm.switchView(ViewTypeRangeDiff)
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRangeDiffView(t *testing.T) {
+	cfg := &config.Config{}
+	client := git.NewClient()
+
+	view := NewRangeDiffView(cfg, client)
+	assert.NotNil(t, view)
+	assert.Equal(t, ViewTypeRangeDiff, view.GetType())
+	assert.Empty(t, view.GetRanges())
+
+	_, err := view.PagerArgs()
+	assert.Error(t, err)
+}
+
+func TestRangeDiffViewToggle(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	err := screen.Init()
+	assert.NoError(t, err)
+
+	cfg := &config.Config{}
+	client := git.NewClient()
+
+	view := NewRangeDiffView(cfg, client)
+	view.Focus()
+	view.SetPosition(0, 0, 80, 24)
+	view.ranges = []string{"main..v1", "main..v2"}
+	view.entries = []*git.RangeDiffEntry{
+		{OldIndex: 1, OldHash: "aaaaaaa", NewIndex: 1, NewHash: "bbbbbbb", Status: git.RangeDiffUnchanged, Subject: "first"},
+		{OldIndex: 2, OldHash: "ccccccc", NewIndex: 2, NewHash: "ddddddd", Status: git.RangeDiffModified, Subject: "second", Diff: []string{"@@ f", "-old", "+new"}},
+		{NewIndex: 3, NewHash: "eeeeeee", Status: git.RangeDiffAdded, Subject: "third"},
+	}
+	view.buildRows()
+
+	assert.Len(t, view.rows, 3)
+	assert.Equal(t, "  1:  aaaaaaa = 1:  bbbbbbb first", view.rows[0].text)
+	assert.Equal(t, "+ 2:  ccccccc ! 2:  ddddddd second", view.rows[1].text)
+	assert.Equal(t, "  -:  ------- > 3:  eeeeeee third", view.rows[2].text)
+
+	// Unchanged entries have nothing to expand
+	view.HandleKey(tcell.KeyEnter, 0, 0)
+	assert.Len(t, view.rows, 3)
+
+	view.HandleKey(tcell.KeyDown, 0, 0)
+	view.HandleKey(tcell.KeyEnter, 0, 0)
+	assert.Len(t, view.rows, 6)
+	assert.Equal(t, "- 2:  ccccccc ! 2:  ddddddd second", view.rows[1].text)
+	assert.Equal(t, "      -old", view.rows[3].text)
+
+	err = view.Render(screen, 0, 0, 80, 24)
+	assert.NoError(t, err)
+
+	// Collapsing from a patch line moves the selection back to its entry
+	view.HandleKey(tcell.KeyDown, 0, 0)
+	view.HandleKey(tcell.KeyDown, 0, 0)
+	view.HandleKey(tcell.KeyEnter, 0, 0)
+	assert.Len(t, view.rows, 3)
+	assert.Equal(t, 1, view.selected)
+
+	args, err := view.PagerArgs()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"range-diff", "main..v1", "main..v2"}, args)
+}
+
+func TestRangeDiffCommand(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	err := screen.Init()
+	assert.NoError(t, err)
+	cfg := &config.Config{}
+	client := git.NewClient()
+	keyBindingMgr := NewKeyBindingManager(cfg)
+
+	vm := NewViewManager(screen, cfg, client, keyBindingMgr)
+
+	assert.Error(t, vm.RangeDiffCommand(nil))
+	assert.Error(t, vm.RangeDiffCommand([]string{"main..v1", "main..v2"}))
+	assert.Equal(t, ViewTypeMain, vm.GetCurrentView())
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// mutatingActions are the key-bound actions which change the repository,
+// refused in read-only mode
+var mutatingActions = map[string]bool{
+	"stage":           true,
+	"unstage":         true,
+	"edit-hunk":       true,
+	"stage-hunk":      true,
+	"discard-hunk":    true,
+	"apply-hunk":      true,
+	"stage-lines":     true,
+	"stage-all":       true,
+	"unstage-all":     true,
+	"discard":         true,
+	"commit":          true,
+	"backport":        true,
+	"fixup":           true,
+	"delete-refs":     true,
+	"push":            true,
+	"delete-remote":   true,
+	"checkout":        true,
+	"branch-create":   true,
+	"branch-rename":   true,
+	"branch-delete":   true,
+	"stash-apply":     true,
+	"stash-pop":       true,
+	"stash-drop":      true,
+	"stash-push":      true,
+	"stash-untracked": true,
+	"rebase-range":    true,
+	"cherry-pick":     true,
+	"revert":          true,
+}
+
+// statusKeyActions names the actions of the keys the status view handles
+// itself rather than through the keymap
+var statusKeyActions = map[rune]string{
+	'a': "stage",
+	'u': "unstage",
+	'd': "discard",
+	'A': "stage-all",
+	'U': "unstage-all",
+	'c': "commit",
+}
+
+// isMutatingAction returns whether an action may change the repository.
+// External commands are assumed to, there being no telling what they do.
+func isMutatingAction(action string) bool {
+	return mutatingActions[action] || strings.HasPrefix(action, "!")
+}
+
+// readOnly returns whether actions changing the repository are disabled
+func (k *KeyBindingManager) readOnly() bool {
+	return k.config != nil && k.config.General.ReadOnly
+}
+
+// refusedAction returns the action a key would run if it changes the
+// repository while in read-only mode
+func (vm *ViewManager) refusedAction(key tcell.Key, ch rune, mod tcell.ModMask) (string, bool) {
+	if !vm.config.General.ReadOnly {
+		return "", false
+	}
+	if vm.currentView == ViewTypeStatus && key == tcell.KeyRune {
+		if action, ok := statusKeyActions[ch]; ok {
+			return action, true
+		}
+	}
+	if vm.currentView == ViewTypeMaintenance && key == tcell.KeyRune {
+		if _, ok := maintenanceKeys[ch]; ok {
+			return "maintenance", true
+		}
+	}
+	// Keys of the main view take precedence over the keymap actions they
+	// share their key with
+	if mainView, ok := vm.views[ViewTypeMain].(*MainView); ok && vm.currentView == ViewTypeMain && key == tcell.KeyRune {
+		if action, ok := mainView.keyAction(ch); ok {
+			return action, isMutatingAction(action)
+		}
+	}
+	if vm.currentView == ViewTypeRefs && key == tcell.KeyEnter {
+		return "checkout", true
+	}
+	if vm.currentView == ViewTypeRefs && key == tcell.KeyRune {
+		if action, ok := refsKeys[ch]; ok {
+			return action, true
+		}
+	}
+	if vm.currentView == ViewTypeStash && key == tcell.KeyRune {
+		if action, ok := stashKeys[ch]; ok {
+			return action, true
+		}
+	}
+	if action, ok := vm.keyBindingMgr.MatchEvent(key, ch, mod); ok && isMutatingAction(action) {
+		return action, true
+	}
+	return "", false
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOnly(t *testing.T) {
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	cfg.General.ReadOnly = true
+	cfg.Keymaps.Bindings["!git gc"] = "G"
+	term := newTestTerminal(t, cfg)
+	term.keyBindingMgr.Reload()
+	vm := term.viewManager
+	vm.SetRepoPath(".")
+
+	// Keys of the status view and of the keymap changing the repository
+	require.NoError(t, vm.SwitchView(ViewTypeStatus))
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'c', 0))
+	assert.Equal(t, "commit is disabled in read-only mode", vm.GetMessage())
+	require.NoError(t, vm.SwitchView(ViewTypeMain))
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'B', 0))
+	assert.Equal(t, "backport is disabled in read-only mode", vm.GetMessage())
+	assert.Empty(t, vm.TakeCommandRequest())
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'G', 0))
+	assert.Equal(t, "!git gc is disabled in read-only mode", vm.GetMessage())
+
+	// Keys only reading the repository work as usual
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'd', 0))
+	assert.Equal(t, ViewTypeDiff, vm.GetCurrentView())
+
+	term.commandMgr.StartCommandModeWith("autosquash")
+	assert.EqualError(t, term.commandMgr.Execute(), "autosquash is disabled in read-only mode")
+	term.commandMgr.StartCommandModeWith("!git gc")
+	assert.EqualError(t, term.commandMgr.Execute(), "! is disabled in read-only mode")
+
+	// Refused actions are neither hinted at nor offered
+	for _, h := range term.keyBindingMgr.hintsFor(hintContextModified) {
+		assert.NotEqual(t, "stage", h.label)
+	}
+	palette := NewPalette(term.commandMgr.GetCommands(), term.keyBindingMgr)
+	for _, item := range palette.items {
+		assert.NotContains(t, []string{"stage", "push", ":autosquash", ":init", "!git gc"}, item.name)
+	}
+	assert.NotEmpty(t, term.keyBindingMgr.hintsFor(hintContextCommit))
+
+	// Turning read-only mode off offers them again
+	cfg.General.ReadOnly = false
+	assert.Contains(t, term.keyBindingMgr.hintsFor(hintContextModified), hint{"u", "stage"})
+}
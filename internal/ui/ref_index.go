@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"github.com/azhao1981/tig/internal/git"
+)
+
+// refIndexed is implemented by the views looking refs up in the index the
+// view manager reads once per refresh
+type refIndexed interface {
+	setRefIndex(index *git.RefIndex)
+}
+
+// updateRefIndex reads all the refs at once and hands them to the views
+// before they refresh, the title taking the state of the branch from them.
+// Views show no refs when they can't be read.
+func (vm *ViewManager) updateRefIndex() {
+	vm.refIndex, vm.refsStamp = nil, 0
+	// Objects come with moving refs, which may lengthen abbreviations
+	vm.client.ResetAbbrev()
+	if vm.client.IsRepository() {
+		// The stamp is taken first for refs changing meanwhile to be
+		// read again
+		vm.refsStamp, _ = vm.client.RefsStamp()
+		vm.refIndex, _ = vm.client.RefIndex()
+	}
+	for _, view := range vm.views {
+		if v, ok := view.(refIndexed); ok {
+			v.setRefIndex(vm.refIndex)
+		}
+	}
+	vm.updateTitleRefs()
+}
+
+// setRefIndex sets the refs decorating the commits
+func (v *MainView) setRefIndex(index *git.RefIndex) {
+	v.refs = index
+}
+
+// setRefIndex sets the refs listed, read again by the view itself when it
+// is refreshed on its own
+func (v *RefsView) setRefIndex(index *git.RefIndex) {
+	v.refs = index
+}
+
+// RefreshRefs refreshes what shows refs when refs changed since they were
+// last read, such as after a fetch from another terminal: the title, the
+// decorations of the log and the refs view. The status and the commits of
+// the log are left alone. It returns whether anything was refreshed.
+func (vm *ViewManager) RefreshRefs() bool {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if !vm.client.IsRepository() || vm.refreshPause.depth > 0 {
+		return false
+	}
+	stamp, err := vm.client.RefsStamp()
+	if err != nil || stamp == vm.refsStamp {
+		return false
+	}
+
+	vm.updateRefIndex()
+	if refsView, ok := vm.views[ViewTypeRefs].(*RefsView); ok {
+		_ = refsView.Refresh()
+	}
+	return true
+}
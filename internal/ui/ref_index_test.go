@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefIndexShared(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	vm.config.Views.Main.ShowRefs = true
+	require.NoError(t, vm.RefreshAll())
+
+	// The log is decorated with the refs read once for all views
+	require.NoError(t, vm.Render())
+	text := strings.Join(screenLines(vm.screen), "\n")
+	assert.Contains(t, text, "[topic] topic")
+	assert.Contains(t, text, "[main] base")
+
+	// The refs view shows the upstream of branches
+	main := refsTestGit(t, dir, "rev-parse", "main")
+	refsTestGit(t, dir, "remote", "add", "origin", dir)
+	refsTestGit(t, dir, "update-ref", "refs/remotes/origin/main", main)
+	refsTestGit(t, dir, "branch", "--set-upstream-to=origin/main", "main")
+	require.NoError(t, vm.RefreshAll())
+	require.NoError(t, vm.SwitchView(ViewTypeRefs))
+	require.NoError(t, vm.Render())
+	text = strings.Join(screenLines(vm.screen), "\n")
+	assert.Contains(t, text, "main [origin/main]")
+
+	require.NoError(t, vm.SwitchView(ViewTypeMain))
+	require.NoError(t, vm.Render())
+	text = strings.Join(screenLines(vm.screen), "\n")
+	assert.Contains(t, text, "[main] {origin/main} base")
+}
+
+func TestRefreshRefs(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	mainView := vm.views[ViewTypeMain].(*MainView)
+	refsView := vm.views[ViewTypeRefs].(*RefsView)
+	topic := refsTestGit(t, dir, "rev-parse", "topic")
+	refsTestGit(t, dir, "remote", "add", "origin", dir)
+	refsTestGit(t, dir, "update-ref", "refs/remotes/origin/main", "main")
+	refsTestGit(t, dir, "branch", "--set-upstream-to=origin/main", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("dirty\n"), 0644))
+	require.NoError(t, vm.RefreshAll())
+	assert.True(t, strings.HasSuffix(vm.GetTitle(), " main*"))
+	assert.False(t, vm.RefreshRefs())
+
+	// A fetch from elsewhere moves the remote branch and adds a branch
+	refsTestGit(t, dir, "update-ref", "refs/remotes/origin/main", topic)
+	refsTestGit(t, dir, "branch", "fetched", topic)
+	assert.True(t, vm.RefreshRefs())
+	assert.True(t, strings.HasSuffix(vm.GetTitle(), " main* ↓1"))
+	assert.Equal(t, []decoration{{text: "[fetched]", color: "branch"}, {text: "[topic]", color: "branch"}, {text: "{origin/main}", color: "remote"}}, mainView.getCommitRefs(topic))
+	var branches []string
+	for _, item := range refsView.branches {
+		branches = append(branches, item.Name)
+	}
+	assert.Equal(t, []string{"fetched", "main", "topic"}, branches)
+	assert.False(t, vm.RefreshRefs())
+
+	// The log is not reloaded
+	refsTestGit(t, dir, "commit", "--quiet", "--allow-empty", "-m", "new")
+	assert.True(t, vm.RefreshRefs())
+	assert.Len(t, mainView.commits, 2)
+
+	// Nor is it while tig changes the repository
+	refsTestGit(t, dir, "branch", "-D", "fetched")
+	vm.pauseRefresh()
+	assert.False(t, vm.RefreshRefs())
+	vm.resumeRefresh()
+	assert.True(t, vm.RefreshRefs())
+}
@@ -0,0 +1,61 @@
+package ui
+
+import "time"
+
+// refreshSettle is how long the periodic refresh leaves the views alone
+// after an operation of tig itself, which refreshed them when done
+const refreshSettle = 2 * time.Second
+
+// refreshPause keeps the periodic refresh from reloading the views while
+// tig changes many files at once, such as when staging everything or
+// checking out another branch
+type refreshPause struct {
+	depth   int       // Operations in progress, which may nest
+	resumed time.Time // When the last operation ended
+}
+
+// pauseRefresh pauses the periodic refresh until resumeRefresh is called
+// as many times
+func (vm *ViewManager) pauseRefresh() {
+	vm.refreshPause.depth++
+}
+
+// resumeRefresh ends an operation paused for. The refreshes which came due
+// meanwhile are folded into the one the operation did of the views it
+// changed, so the periodic refresh only starts again after a while.
+func (vm *ViewManager) resumeRefresh() {
+	if vm.refreshPause.depth == 0 {
+		return
+	}
+	vm.refreshPause.depth--
+	if vm.refreshPause.depth == 0 {
+		vm.refreshPause.resumed = time.Now()
+	}
+}
+
+// bulk runs an operation changing many files with the periodic refresh
+// paused
+func (vm *ViewManager) bulk(run func() error) error {
+	vm.pauseRefresh()
+	defer vm.resumeRefresh()
+	return run()
+}
+
+// AutoRefresh refreshes the current view for the periodic refresh, unless
+// an operation of tig is changing the files or just did. It returns
+// whether the view was refreshed.
+func (vm *ViewManager) AutoRefresh() bool {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if vm.refreshPause.depth > 0 || time.Since(vm.refreshPause.resumed) < refreshSettle {
+		return false
+	}
+
+	vm.updateTitle()
+	vm.updateRefIndex()
+	if view, exists := vm.views[vm.currentView]; exists {
+		_ = view.Refresh()
+	}
+	return true
+}
@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewManagerRefreshPause(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	assert.True(t, vm.AutoRefresh())
+
+	// Nested operations hold the refresh off until the outer one ends
+	vm.pauseRefresh()
+	vm.pauseRefresh()
+	assert.False(t, vm.AutoRefresh())
+	vm.resumeRefresh()
+	assert.False(t, vm.AutoRefresh())
+	vm.resumeRefresh()
+
+	// The views were just refreshed by the operation
+	assert.False(t, vm.AutoRefresh())
+	vm.refreshPause.resumed = time.Now().Add(-refreshSettle)
+	assert.True(t, vm.AutoRefresh())
+
+	// Unbalanced resumes are ignored
+	vm.resumeRefresh()
+	assert.Equal(t, 0, vm.refreshPause.depth)
+
+	// Checking out and staging everything pause it too
+	require.NoError(t, vm.CheckoutCommand([]string{"topic"}))
+	assert.False(t, vm.AutoRefresh())
+
+	vm.refreshPause.resumed = time.Time{}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("mine\n"), 0644))
+	vm.SwitchView(ViewTypeStatus)
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'A', 0))
+	assert.False(t, vm.AutoRefresh())
+	assert.Equal(t, "M  notes.txt", refsTestGit(t, dir, "status", "--short"))
+}
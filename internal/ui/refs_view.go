@@ -17,6 +17,30 @@ type RefItem struct {
 	Current  bool
 	Remote   string
 	Upstream string
+	Ahead    int // Commits of a branch its upstream doesn't have
+	Behind   int // Commits of the upstream of a branch the branch doesn't have
+}
+
+// tracking describes how far a branch is ahead and behind its upstream,
+// such as ↑1 ↓2, empty when it is up to date or has no upstream
+func (item *RefItem) tracking() string {
+	var parts []string
+	if item.Ahead > 0 {
+		parts = append(parts, fmt.Sprintf("↑%d", item.Ahead))
+	}
+	if item.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("↓%d", item.Behind))
+	}
+	return strings.Join(parts, " ")
+}
+
+// refsKeys are the keys of the refs view acting on the selected ref, Enter
+// checking it out too
+var refsKeys = map[rune]string{
+	'c': "checkout",
+	'n': "branch-create",
+	'm': "branch-rename",
+	'd': "branch-delete",
 }
 
 // RefsView represents the references view (branches, tags, remotes)
@@ -32,6 +56,23 @@ type RefsView struct {
 	currentSection int
 	selected       int
 	repoPath       string
+	marked         map[string]bool     // Full names of the refs marked for deletion
+	filter         string              // Glob or substring the names of the refs listed match, empty for all
+	shown          [3][]*RefItem       // Refs of each section matching the filter
+	refs           *git.RefIndex       // Refs handed by the view manager, nil to read them again
+	listed         *git.RefIndex       // Refs the listed ones were read from, for the tags they follow
+	run            func(action string) // Runs the action of a key on the selected ref
+	frame          *Frame
+	base           string                  // Default branch the others are compared to
+	baseHash       string
+	topology       map[string]*refTopology // Topology of branches against the base by name, worked out as they are shown
+}
+
+// refTopology is where a branch stands against the default branch, kept
+// while neither moves
+type refTopology struct {
+	hash, baseHash string
+	hint           string
 }
 
 // NewRefsView creates a new references view
@@ -46,6 +87,9 @@ func NewRefsView(config *config.Config, client git.Client) *RefsView {
 		remotes:        []*RefItem{},
 		sections:       []string{"Branches", "Tags", "Remotes"},
 		currentSection: 0,
+		marked:         make(map[string]bool),
+		frame:          NewFrame(config, "References"),
+		topology:       make(map[string]*refTopology),
 	}
 }
 
@@ -55,16 +99,22 @@ func (v *RefsView) Load() error {
 		return fmt.Errorf("no git client available")
 	}
 
-	// Load branches
-	branches, err := v.client.GetBranches()
-	if err != nil {
-		return fmt.Errorf("failed to get branches: %w", err)
+	if !v.client.IsRepository() {
+		v.branches = []*RefItem{}
+		v.tags = []*RefItem{}
+		v.remotes = []*RefItem{}
+		v.applyFilter()
+		return nil
 	}
 
-	// Load tags
-	tags, err := v.client.GetTags()
-	if err != nil {
-		return fmt.Errorf("failed to get tags: %w", err)
+	// Load branches and tags, read at once unless handed by the view
+	// manager
+	index := v.refs
+	if index == nil {
+		var err error
+		if index, err = v.client.RefIndex(); err != nil {
+			return fmt.Errorf("failed to get refs: %w", err)
+		}
 	}
 
 	// Load remotes
@@ -74,42 +124,50 @@ func (v *RefsView) Load() error {
 	}
 
 	// Convert to ref items
-	v.branches = v.convertRefs(branches, "branch")
-	v.tags = v.convertRefs(tags, "tag")
+	v.listed = index
+	v.branches = v.convertRefs(index, git.RefTypeBranch)
+	v.tags = v.convertRefs(index, git.RefTypeTag)
 	v.remotes = v.convertRemotes(remotes)
+	v.applyFilter()
+
+	// Forget the marks of refs which are gone
+	for name := range v.marked {
+		if v.findRef(name) == nil {
+			delete(v.marked, name)
+		}
+	}
+
+	// Branches are compared to the default branch, as of its commit
+	v.base = v.client.DefaultBranch()
+	v.baseHash = index.Commit("refs/heads/" + v.base)
+	for name := range v.topology {
+		if v.findRef("refs/heads/"+name) == nil {
+			delete(v.topology, name)
+		}
+	}
 
 	return nil
 }
 
-// convertRefs converts git refs to ref items
-func (v *RefsView) convertRefs(refs []*git.Ref, refType string) []*RefItem {
-	items := []*RefItem{}
-	
-	// Get current HEAD
-	var currentRef string
-	head, err := v.client.GetHead()
-	if err == nil {
-		currentRef = head.Name
+// convertRefs converts the branches or tags of the index to ref items
+func (v *RefsView) convertRefs(index *git.RefIndex, refType git.RefType) []*RefItem {
+	itemType := "branch"
+	if refType == git.RefTypeTag {
+		itemType = "tag"
 	}
 
-	for _, ref := range refs {
-		name := ref.Name
-		// Clean up branch names
-		if refType == "branch" {
-			name = strings.TrimPrefix(name, "refs/heads/")
-		} else if refType == "tag" {
-			name = strings.TrimPrefix(name, "refs/tags/")
-		}
-
+	items := []*RefItem{}
+	for _, ref := range index.OfType(refType) {
 		item := &RefItem{
-			Type:    refType,
-			Name:    name,
-			Hash:    ref.Hash,
-			Current: (refType == "branch" && ref.Name == currentRef),
+			Type:     itemType,
+			Name:     git.ShortRefName(ref.Name),
+			Hash:     ref.Hash,
+			Current:  ref.Name == index.Head,
+			Upstream: git.ShortRefName(index.Upstream(ref.Name)),
 		}
+		item.Ahead, item.Behind = index.Tracking(ref.Name)
 		items = append(items, item)
 	}
-
 	return items
 }
 
@@ -130,224 +188,224 @@ func (v *RefsView) convertRemotes(remotes []*git.Remote) []*RefItem {
 // Render renders the refs view
 func (v *RefsView) Render(screen tcell.Screen, x, y, width, height int) error {
 	v.SetPosition(x, y, width, height)
-	v.SetHeight(height - 5) // Account for header, tabs, and status bar
+	v.SetHeight(v.listRows())
 	
 	if width == 0 || height == 0 {
 		return fmt.Errorf("invalid screen dimensions")
 	}
 
-	screen.Clear()
-
-	// Draw header
-	header := "References"
-	style := tcell.StyleDefault.Bold(true)
-	v.drawText(screen, 0, 0, style, header)
-	
-	// Draw separator
-	for xPos := 0; xPos < width; xPos++ {
-		screen.SetContent(xPos, 1, '-', nil, tcell.StyleDefault)
+	// Draw the frame, and its scrollbar once the content is laid out
+	v.frame.Status = "Use ↑/↓ to navigate, 1/b for branches, 2/t for tags, 3/r for remotes, Tab to cycle, Space to mark, D to delete, R to refresh"
+	if v.filter != "" {
+		v.frame.Status = fmt.Sprintf("Filter: %s", v.filter)
+	}
+	x, y, width, height = v.frame.Draw(screen, x, y, width, height)
+	defer v.frame.DrawScrollbar(screen, v.Scrollable)
+	if width <= 0 || height <= 0 {
+		return nil
 	}
 
 	// Draw section tabs
-	v.drawSectionTabs(screen, width)
+	v.drawSectionTabs(screen, x, y)
 
 	// Draw content based on current section
-	contentStartY := 3
-	maxRows := height - contentStartY - 1
+	contentStartY := y + 1
+	maxRows := v.listRows()
 
-	var items []*RefItem
-	var title string
-	
-	switch v.currentSection {
-	case 0: // Branches
-		items = v.branches
-		title = fmt.Sprintf("Branches (%d)", len(v.branches))
-	case 1: // Tags
-		items = v.tags
-		title = fmt.Sprintf("Tags (%d)", len(v.tags))
-	case 2: // Remotes
-		items = v.remotes
-		title = fmt.Sprintf("Remotes (%d)", len(v.remotes))
+	// Such as Branches (3 of 12, 1 marked)
+	items := v.getCurrentItems()
+	all := v.sectionItems(v.currentSection)
+	count := fmt.Sprintf("%d", len(all))
+	if v.filter != "" {
+		count = fmt.Sprintf("%d of %d", len(items), len(all))
 	}
+	if n := v.countMarked(all); n > 0 {
+		count += fmt.Sprintf(", %d marked", n)
+	}
+	title := fmt.Sprintf("%s (%s)", v.sections[v.currentSection], count)
 
 	// Draw section title
-	v.drawText(screen, 0, contentStartY, tcell.StyleDefault.Bold(true), title)
+	v.drawText(screen, x, contentStartY, width, tcell.StyleDefault.Bold(true), title)
 	contentStartY++
 
-	// Draw separator
-	for xPos := 0; xPos < width; xPos++ {
-		screen.SetContent(xPos, contentStartY, '-', nil, tcell.StyleDefault)
-	}
-	contentStartY++
+	v.SetMaxOffset(len(items) - maxRows)
 
 	if len(items) == 0 {
 		msg := "No items found"
-		msgX := (width - len(msg)) / 2
-		msgY := height / 2
-		v.drawText(screen, msgX, msgY, tcell.StyleDefault.Dim(true), msg)
+		if v.filter != "" && len(all) > 0 {
+			msg = fmt.Sprintf("None of the %d match %q", len(all), v.filter)
+		}
+		msgX := x + (width-len(msg))/2
+		msgY := y + height/2
+		v.drawText(screen, msgX, msgY, width, tcell.StyleDefault.Dim(true), msg)
 	} else {
 		// Calculate visible range
 		visibleStart := 0
-		visibleEnd := len(items)
-		
 		if len(items) > maxRows {
-			visibleStart = v.GetOffset()
-			visibleEnd = v.GetOffset() + maxRows
-			if visibleEnd > len(items) {
-				visibleEnd = len(items)
-				if visibleEnd-maxRows >= 0 {
-					visibleStart = visibleEnd - maxRows
-				}
-			}
+			visibleStart = max(0, min(v.GetOffset(), len(items)-maxRows))
 		}
 
-		// Draw items
-		for i := visibleStart; i < visibleEnd; i++ {
-			item := items[i]
-			y := contentStartY + (i - visibleStart)
-			
-			if i == v.selected {
-				// Highlight selected item
-				for xPos := 0; xPos < width; xPos++ {
-					screen.SetContent(xPos, y, ' ', nil, tcell.StyleDefault.Background(tcell.ColorBlue))
-				}
-			}
+		table := v.table(items, visibleStart, maxRows)
+		table.Draw(screen, x+2, contentStartY, width-3, maxRows+1)
+	}
+	contentStartY++
 
-			// Item icon and name
-			var icon, prefix string
-			var itemStyle tcell.Style
-
-			switch item.Type {
-			case "branch":
-				icon = "🌿"
-				if item.Current {
-					icon = "🌿*"
-					itemStyle = tcell.StyleDefault.Bold(true).Foreground(tcell.ColorGreen)
-				}
-			case "tag":
-				icon = "🏷️"
-				itemStyle = tcell.StyleDefault.Foreground(tcell.ColorYellow)
-			case "remote":
-				icon = "🌐"
-				itemStyle = tcell.StyleDefault.Foreground(tcell.ColorBlue)
-			}
+	// Position cursor
+	cursorY := contentStartY + v.selected - v.GetOffset()
+	if cursorY >= contentStartY && cursorY < y+height {
+		screen.ShowCursor(x, cursorY)
+	}
 
-			if item.Current {
-				prefix = "* "
-			} else {
-				prefix = "  "
-			}
+	return nil
+}
 
-			line := fmt.Sprintf("%s%s %s", prefix, icon, item.Name)
-			
-			// Truncate if too long
-			maxLen := width - 4
-			if len(line) > maxLen {
-				line = line[:maxLen-3] + "..."
+// table lays out refs in columns: their mark and icon, their name and
+// upstream, how far branches are ahead and behind it, where they stand
+// against the default branch and the commit they point to. Those last
+// three are left out first when there isn't room. Only the rows shown
+// from first are worked out where they stand and their commit.
+func (v *RefsView) table(items []*RefItem, first, rows int) *Table {
+	dim := tcell.StyleDefault.Dim(true)
+	table := NewTable(
+		TableColumn{Priority: 2},
+		TableColumn{Title: "Name", Priority: 3, Expand: true},
+		TableColumn{Align: AlignRight, Style: dim},
+		TableColumn{Title: "Topology", Style: dim},
+		TableColumn{Title: "Commit", Align: AlignRight, Priority: 1, Style: dim},
+	)
+	table.Header = true
+	table.Selected = v.selected
+	table.Offset = first
+
+	for i, item := range items {
+		var icon, prefix string
+		var itemStyle tcell.Style
+
+		switch item.Type {
+		case "branch":
+			icon = "🌿"
+			if item.Current {
+				icon = "🌿*"
+				itemStyle = tcell.StyleDefault.Bold(true).Foreground(tcell.ColorGreen)
 			}
+		case "tag":
+			icon = "🏷️"
+			itemStyle = tcell.StyleDefault.Foreground(tcell.ColorYellow)
+		case "remote":
+			icon = "🌐"
+			itemStyle = tcell.StyleDefault.Foreground(tcell.ColorBlue)
+		}
 
-			v.drawText(screen, 2, y, itemStyle, line)
+		if item.Current {
+			prefix = "* "
+		} else if v.marked[refName(item)] {
+			prefix = "+ "
+			itemStyle = itemStyle.Reverse(true)
+		} else {
+			prefix = "  "
+		}
 
-			// Show hash for branches and tags
+		name := item.Name
+		if item.Upstream != "" {
+			name += " [" + item.Upstream + "]"
+		}
+		hash, topology := "", ""
+		if i >= first && i < first+rows {
 			if (item.Type == "branch" || item.Type == "tag") && item.Hash != "" {
-				hash := item.Hash[:8]
-				if len(hash)+len(line)+3 < width {
-					hashLine := fmt.Sprintf(" %s", hash)
-					v.drawText(screen, width-len(hashLine)-2, y, tcell.StyleDefault.Dim(true), hashLine)
-				}
+				hash = v.client.AbbrevHash(item.Hash)
+				// Hashes are left out rather than cut
+				table.Columns[4].MinWidth = max(table.Columns[4].MinWidth, len(hash))
 			}
+			topology = v.topologyHint(item)
 		}
+		table.Rows = append(table.Rows, TableRow{
+			Cells: []string{prefix + icon, name, item.tracking(), topology, hash},
+			Style: itemStyle,
+		})
+	}
+	return table
+}
 
-		// Draw scrollbar if needed
-		if len(items) > maxRows {
-			v.drawScrollbar(screen, len(items), maxRows, v.GetOffset())
+// topologyHint describes where a branch stands against the default branch,
+// such as "2 ahead of main, based on v1.4", worked out from their merge
+// base the first time the branch is shown at its commit, and which tags a
+// tag follows, such as "after v1.3"
+func (v *RefsView) topologyHint(item *RefItem) string {
+	if item.Type == "tag" {
+		var previous []string
+		for _, ref := range v.listed.PreviousTags("refs/tags/" + item.Name) {
+			previous = append(previous, git.ShortRefName(ref.Name))
+		}
+		if len(previous) == 0 {
+			return ""
 		}
+		return "after " + strings.Join(previous, ", ")
+	}
+	if item.Type != "branch" || v.base == "" || item.Name == v.base {
+		return ""
+	}
+	if known := v.topology[item.Name]; known != nil && known.hash == item.Hash && known.baseHash == v.baseHash {
+		return known.hint
 	}
 
-	// Draw status bar
-	v.drawStatusBar(screen, width, height)
-	
-	// Position cursor
-	cursorY := contentStartY + v.selected - v.GetOffset()
-	if cursorY >= contentStartY && cursorY < height-1 {
-		screen.ShowCursor(0, cursorY)
+	hint := ""
+	if topology, err := v.client.Topology(item.Name, v.base); err == nil {
+		hint = topologyHint(topology, v.base)
 	}
+	v.topology[item.Name] = &refTopology{hash: item.Hash, baseHash: v.baseHash, hint: hint}
+	return hint
+}
 
-	return nil
+// topologyHint describes the topology of a branch against base
+func topologyHint(topology *git.BranchTopology, base string) string {
+	var hint string
+	switch {
+	case topology.MergeBase == "":
+		return "unrelated to " + base
+	case topology.Ahead == 0 && topology.Behind == 0:
+		return "at " + base
+	case topology.Ahead == 0:
+		return "merged into " + base
+	case topology.Behind == 0:
+		hint = fmt.Sprintf("%d ahead of %s", topology.Ahead, base)
+	default:
+		hint = fmt.Sprintf("%d ahead, %d behind %s", topology.Ahead, topology.Behind, base)
+	}
+	if topology.Tag != "" {
+		hint += ", based on " + topology.Tag
+	}
+	return hint
+}
+
+// listRows returns the number of refs shown at once, below the section
+// tabs, title and separator
+func (v *RefsView) listRows() int {
+	_, _, _, height := v.GetPosition()
+	return height - 5 // Account for the frame, tabs and section title
 }
 
 // drawSectionTabs draws the section tabs
-func (v *RefsView) drawSectionTabs(screen tcell.Screen, width int) {
-	startX := 0
+func (v *RefsView) drawSectionTabs(screen tcell.Screen, x, y int) {
+	startX := x
 	for i, section := range v.sections {
 		style := tcell.StyleDefault
 		if i == v.currentSection {
 			style = style.Background(tcell.ColorBlue).Foreground(tcell.ColorWhite)
 		}
 		
-		label := fmt.Sprintf(" %s ", section)
-		v.drawText(screen, startX, 2, style, label)
+		// Such as Branches (12), or Branches (3/12) when filtered
+		label := fmt.Sprintf(" %s (%d) ", section, len(v.sectionItems(i)))
+		if v.filter != "" {
+			label = fmt.Sprintf(" %s (%d/%d) ", section, len(v.shown[i]), len(v.sectionItems(i)))
+		}
+		v.drawText(screen, startX, y, len(label), style, label)
 		startX += len(label) + 1
 	}
 }
 
-// drawText draws text at the specified position
-func (v *RefsView) drawText(screen tcell.Screen, x, y int, style tcell.Style, text string) {
-	for i, r := range text {
-		screen.SetContent(x+i, y, r, nil, style)
-	}
-}
-
-// drawStatusBar draws the status bar
-func (v *RefsView) drawStatusBar(screen tcell.Screen, width, height int) {
-	if height < 2 {
-		return
-	}
-
-	// Draw status bar background
-	statusStyle := tcell.StyleDefault.Background(tcell.ColorDarkGray).Foreground(tcell.ColorWhite)
-	for x := 0; x < width; x++ {
-		screen.SetContent(x, height-1, ' ', nil, statusStyle)
-	}
-
-	// Status text
-	status := "Refs View - Use ↑/↓ to navigate, 1/b for branches, 2/t for tags, 3/r for remotes, Tab to cycle, R to refresh"
-	if len(status) > width {
-		status = status[:width-1]
-	}
-	v.drawText(screen, 0, height-1, statusStyle, status)
-}
-
-// drawScrollbar draws the scrollbar if needed
-func (v *RefsView) drawScrollbar(screen tcell.Screen, totalItems, visibleItems, offset int) {
-	if totalItems <= visibleItems {
-		return
-	}
-
-	width, height := screen.Size()
-	if height < 5 {
-		return
-	}
-
-	scrollbarHeight := height - 4
-	scrollRatio := float64(visibleItems) / float64(totalItems)
-	thumbHeight := int(float64(scrollbarHeight) * scrollRatio)
-	if thumbHeight < 1 {
-		thumbHeight = 1
-	}
-
-	scrollPos := int(float64(offset) / float64(totalItems-visibleItems) * float64(scrollbarHeight-thumbHeight))
-
-	// Draw scrollbar background
-	scrollbarX := width - 1
-	for y := 2; y < height-2; y++ {
-		screen.SetContent(scrollbarX, y, '│', nil, tcell.StyleDefault.Dim(true))
-	}
-
-	// Draw scrollbar thumb
-	for y := 2 + scrollPos; y < 2+scrollPos+thumbHeight && y < height-2; y++ {
-		screen.SetContent(scrollbarX, y, '█', nil, tcell.StyleDefault)
-	}
+// drawText draws text at the specified position, cut at the given width
+func (v *RefsView) drawText(screen tcell.Screen, x, y, width int, style tcell.Style, text string) {
+	drawCells(screen, x, y, width, textCells(text, style), 0, style)
 }
 
 // HandleKey handles key events for the refs view
@@ -383,6 +441,19 @@ func (v *RefsView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
 	case key == tcell.KeyTab:
 		v.nextSection()
 		return true
+	case key == tcell.KeyRune && ch == ' ':
+		v.toggleMark()
+		return true
+	case key == tcell.KeyEnter:
+		if v.run != nil {
+			v.run("checkout")
+		}
+		return true
+	case key == tcell.KeyRune && refsKeys[ch] != "":
+		if v.run != nil {
+			v.run(refsKeys[ch])
+		}
+		return true
 	case ch == 'R':
 		v.refresh()
 		return true
@@ -411,8 +482,7 @@ func (v *RefsView) moveDown() {
 	items := v.getCurrentItems()
 	if v.selected < len(items)-1 {
 		v.selected++
-		_, _, _, height := v.GetPosition()
-		maxRows := height - 5 // Account for header, tabs, etc.
+		maxRows := v.listRows()
 		if v.selected >= v.GetOffset()+maxRows {
 			v.SetOffset(v.selected - maxRows + 1)
 		}
@@ -451,9 +521,245 @@ func (v *RefsView) nextSection() {
 	v.SetOffset(0)
 }
 
+// refName returns the full name of a branch or tag, such as refs/heads/main
+func refName(item *RefItem) string {
+	switch item.Type {
+	case "branch":
+		return "refs/heads/" + item.Name
+	case "tag":
+		return "refs/tags/" + item.Name
+	}
+	return ""
+}
+
+// findRef returns the branch or tag with the given full name
+func (v *RefsView) findRef(name string) *RefItem {
+	for _, items := range [][]*RefItem{v.branches, v.tags} {
+		for _, item := range items {
+			if refName(item) == name {
+				return item
+			}
+		}
+	}
+	return nil
+}
+
+// toggleMark marks the selected branch or tag for deletion, or unmarks it,
+// and moves on to the next one. The checked out branch can't be marked.
+func (v *RefsView) toggleMark() {
+	items := v.getCurrentItems()
+	if v.selected < 0 || v.selected >= len(items) {
+		return
+	}
+	item := items[v.selected]
+	if item.Current || refName(item) == "" {
+		return
+	}
+
+	name := refName(item)
+	if v.marked[name] {
+		delete(v.marked, name)
+	} else {
+		v.marked[name] = true
+	}
+	v.moveDown()
+}
+
+// countMarked returns how many of the items are marked
+func (v *RefsView) countMarked(items []*RefItem) int {
+	n := 0
+	for _, item := range items {
+		if v.marked[refName(item)] {
+			n++
+		}
+	}
+	return n
+}
+
+// MarkedRefs returns the full names of the marked branches and tags in the
+// order they are listed, or the selected one when none is marked
+func (v *RefsView) MarkedRefs() []string {
+	var refs []string
+	for _, items := range [][]*RefItem{v.branches, v.tags} {
+		for _, item := range items {
+			if v.marked[refName(item)] {
+				refs = append(refs, refName(item))
+			}
+		}
+	}
+	if len(refs) > 0 {
+		return refs
+	}
+
+	items := v.getCurrentItems()
+	if v.selected >= 0 && v.selected < len(items) {
+		if name := refName(items[v.selected]); name != "" {
+			refs = append(refs, name)
+		}
+	}
+	return refs
+}
+
+// SelectedItem returns the selected ref, if any
+func (v *RefsView) SelectedItem() *RefItem {
+	items := v.getCurrentItems()
+	if v.selected < 0 || v.selected >= len(items) {
+		return nil
+	}
+	return items[v.selected]
+}
+
+// ClearMarks unmarks all refs
+func (v *RefsView) ClearMarks() {
+	v.marked = make(map[string]bool)
+}
+
+// RefsFilterCommand handles the :refs-filter command, which narrows the
+// refs of the refs view to those whose name matches a glob or substring
+func (vm *ViewManager) RefsFilterCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	view, ok := vm.views[ViewTypeRefs].(*RefsView)
+	if !ok {
+		return fmt.Errorf("refs view not found")
+	}
+
+	filter := strings.Join(args, " ")
+	view.setFilter(filter)
+	if filter == "" {
+		vm.setMessage("Showing all refs")
+	} else if strings.ContainsAny(filter, "*?[") && !isGlob(filter) {
+		vm.setMessage("Invalid pattern %q, matched as a substring", filter)
+	}
+	return vm.switchView(ViewTypeRefs)
+}
+
+// runRefAction runs the action of a key of the refs view on the selected
+// ref: checking out a branch or, detaching HEAD, a tag, prompting for the
+// name of a branch to create from it or to rename it to, or deleting it
+func (vm *ViewManager) runRefAction(action string) {
+	view, ok := vm.views[ViewTypeRefs].(*RefsView)
+	if !ok {
+		return
+	}
+
+	item := view.SelectedItem()
+	if action == "branch-create" {
+		vm.commandRequest = "branch "
+		return
+	}
+	if action != "checkout" {
+		if item == nil || item.Type != "branch" {
+			vm.setMessage("No branch selected")
+			return
+		}
+		if action == "branch-rename" {
+			vm.commandRequest = "branch -m " + item.Name + " "
+		} else if err := vm.deleteBranch(item.Name, false); err != nil {
+			vm.setMessage("%v", err)
+		}
+		return
+	}
+
+	var err error
+	switch {
+	case item == nil:
+		vm.setMessage("No branch or tag selected")
+	case item.Type == "branch" && item.Current:
+		vm.setMessage("Already on %s", item.Name)
+	case item.Type == "branch":
+		err = vm.checkout(item.Name, "Switched to branch "+item.Name)
+	case item.Type == "tag":
+		// The tag rather than a branch of the same name
+		err = vm.checkout("tags/"+item.Name, fmt.Sprintf("Checked out tag %s, HEAD is now detached", item.Name))
+	default:
+		vm.setMessage("Only branches and tags can be checked out")
+	}
+	if err != nil {
+		vm.setMessage("%v", err)
+	}
+}
+
+// deleteMarkedRefs asks to delete the marked refs, listing exactly what
+// would be removed and where each ref pointed so it can be restored
+func (vm *ViewManager) deleteMarkedRefs() error {
+	view, ok := vm.views[ViewTypeRefs].(*RefsView)
+	if !ok {
+		return fmt.Errorf("refs view not found")
+	}
+	refs := view.MarkedRefs()
+	if len(refs) == 0 {
+		return fmt.Errorf("no branch or tag selected")
+	}
+
+	plan, err := vm.client.PlanRefDeletion(refs)
+	if err != nil {
+		return err
+	}
+
+	branches, tags := 0, 0
+	details := make([]string, 0, len(plan))
+	for _, ref := range plan {
+		kind := "tag"
+		if ref.IsBranch() {
+			kind = "branch"
+			branches++
+		} else {
+			tags++
+		}
+		line := fmt.Sprintf("%-6s %s at %s", kind, ref.ShortName(), vm.client.AbbrevHash(ref.Hash))
+		if ref.Unmerged {
+			line += " (not merged into HEAD)"
+		}
+		details = append(details, line)
+	}
+
+	// A single ref is confirmed by typing its name, several by typing delete
+	name := "delete"
+	if len(plan) == 1 {
+		name = plan[0].ShortName()
+	}
+	return vm.confirmAction("branch-delete", "Delete "+countRefs(branches, tags)+"?", name, details, func() error {
+		if err := vm.client.DeleteRefs(plan); err != nil {
+			return err
+		}
+		view.ClearMarks()
+		vm.refreshAll()
+		vm.setMessage("Deleted %s", countRefs(branches, tags))
+		return nil
+	})
+}
+
+// countRefs describes a number of branches and tags, such as "2 branches
+// and 1 tag"
+func countRefs(branches, tags int) string {
+	plural := func(n int, one, many string) string {
+		if n == 1 {
+			return "1 " + one
+		}
+		return fmt.Sprintf("%d %s", n, many)
+	}
+	switch {
+	case tags == 0:
+		return plural(branches, "branch", "branches")
+	case branches == 0:
+		return plural(tags, "tag", "tags")
+	}
+	return plural(branches, "branch", "branches") + " and " + plural(tags, "tag", "tags")
+}
+
 // getCurrentItems returns the items for the current section
 func (v *RefsView) getCurrentItems() []*RefItem {
-	switch v.currentSection {
+	if v.currentSection < 0 || v.currentSection >= len(v.shown) || v.shown[v.currentSection] == nil {
+		return []*RefItem{}
+	}
+	return v.shown[v.currentSection]
+}
+
+// sectionItems returns all the refs of a section, filtered or not
+func (v *RefsView) sectionItems(section int) []*RefItem {
+	switch section {
 	case 0:
 		return v.branches
 	case 1:
@@ -464,16 +770,46 @@ func (v *RefsView) getCurrentItems() []*RefItem {
 	return []*RefItem{}
 }
 
-// refresh refreshes the refs view
+// applyFilter works out the refs of each section matching the filter, once
+// they are read or the filter changes rather than every time they are drawn
+func (v *RefsView) applyFilter() {
+	for section := range v.shown {
+		items := v.sectionItems(section)
+		if v.filter == "" {
+			v.shown[section] = items
+			continue
+		}
+		v.shown[section] = []*RefItem{}
+		for _, item := range items {
+			if statusFilterMatches(v.filter, item.Name) {
+				v.shown[section] = append(v.shown[section], item)
+			}
+		}
+	}
+	if items := v.getCurrentItems(); v.selected >= len(items) {
+		v.selected = max(len(items)-1, 0)
+	}
+}
+
+// setFilter narrows the refs listed to those whose name matches a pattern,
+// all of them when empty
+func (v *RefsView) setFilter(filter string) {
+	v.filter = filter
+	v.selected = 0
+	v.SetOffset(0)
+	v.applyFilter()
+}
+
+// refresh reads the refs again and refreshes the refs view
 func (v *RefsView) refresh() {
+	v.refs = nil
 	v.Load()
 }
 
 // pageUp moves up by one page
 func (v *RefsView) pageUp() {
 	items := v.getCurrentItems()
-	_, _, _, height := v.GetPosition()
-	maxRows := height - 5 // Account for header, tabs, etc.
+	maxRows := v.listRows()
 	
 	if v.selected >= maxRows {
 		v.selected -= maxRows
@@ -493,8 +829,7 @@ func (v *RefsView) pageUp() {
 // pageDown moves down by one page
 func (v *RefsView) pageDown() {
 	items := v.getCurrentItems()
-	_, _, _, height := v.GetPosition()
-	maxRows := height - 5 // Account for header, tabs, etc.
+	maxRows := v.listRows()
 	
 	if v.selected+maxRows < len(items) {
 		v.selected += maxRows
@@ -508,8 +843,7 @@ func (v *RefsView) pageDown() {
 // adjustScroll adjusts the scroll position based on selected index
 func (v *RefsView) adjustScroll() {
 	items := v.getCurrentItems()
-	_, _, _, height := v.GetPosition()
-	maxRows := height - 5 // Account for header, tabs, etc.
+	maxRows := v.listRows()
 	
 	if v.selected < v.GetOffset() {
 		v.SetOffset(v.selected)
@@ -532,7 +866,41 @@ func (v *RefsView) GetType() ViewType {
 
 // Refresh refreshes the refs view
 func (v *RefsView) Refresh() error {
-	return v.Load()
+	var selectedName string
+	if items := v.getCurrentItems(); v.selected >= 0 && v.selected < len(items) {
+		selectedName = items[v.selected].Name
+	}
+
+	if err := v.Load(); err != nil {
+		return err
+	}
+
+	// Keep the selected ref selected when it still exists
+	items := v.getCurrentItems()
+	v.selected = findSelection(len(items), v.selected, func(i int) bool {
+		return items[i].Name == selectedName
+	})
+	v.adjustScroll()
+	return nil
+}
+
+// Selection returns the selected reference for placeholder expansion
+func (v *RefsView) Selection() Selection {
+	var sel Selection
+	items := v.getCurrentItems()
+	if v.selected < 0 || v.selected >= len(items) {
+		return sel
+	}
+
+	item := items[v.selected]
+	sel.Commit = item.Hash
+	if item.Type != "remote" {
+		sel.Ref = item.Name
+	}
+	if item.Type == "branch" {
+		sel.Branch = item.Name
+	}
+	return sel
 }
 
 // SetRepoPath sets the repository path
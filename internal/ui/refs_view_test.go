@@ -0,0 +1,299 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// refsTestGit runs git in a repository of the refs view tests
+func refsTestGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	args = append([]string{"-C", dir, "-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)
+	output, err := exec.Command("git", args...).CombinedOutput()
+	require.NoError(t, err, "git %v: %s", args, output)
+	return strings.TrimSpace(string(output))
+}
+
+func TestViewManagerDeleteMarkedRefs(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	refsTestGit(t, dir, "init", "--quiet", "--initial-branch=main")
+	refsTestGit(t, dir, "commit", "--quiet", "--allow-empty", "-m", "initial")
+	for _, branch := range []string{"old-1", "old-2", "keep"} {
+		refsTestGit(t, dir, "branch", branch)
+	}
+	refsTestGit(t, dir, "tag", "v0")
+
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+	vm.SetSize(100, 30)
+	vm.SetRepoPath(dir)
+	require.NoError(t, vm.SwitchView(ViewTypeRefs))
+	view := vm.views[ViewTypeRefs].(*RefsView)
+
+	var names []string
+	for _, item := range view.branches {
+		names = append(names, item.Name)
+	}
+	require.Equal(t, []string{"keep", "main", "old-1", "old-2"}, names)
+
+	// The checked out branch can't be marked, the others are marked in turn
+	vm.HandleKey(tcell.KeyRune, 'j', 0)
+	vm.HandleKey(tcell.KeyRune, ' ', 0)
+	assert.Equal(t, 1, view.selected)
+	vm.HandleKey(tcell.KeyRune, 'j', 0)
+	vm.HandleKey(tcell.KeyRune, ' ', 0)
+	vm.HandleKey(tcell.KeyRune, ' ', 0)
+	vm.HandleKey(tcell.KeyRune, '2', 0)
+	vm.HandleKey(tcell.KeyRune, ' ', 0)
+	assert.Equal(t, []string{"refs/heads/old-1", "refs/heads/old-2", "refs/tags/v0"}, view.MarkedRefs())
+
+	// Deleting previews exactly what goes, any key but y cancelling it
+	vm.HandleKey(tcell.KeyRune, 'D', 0)
+	require.NotNil(t, vm.confirmation)
+	assert.Equal(t, "Delete 2 branches and 1 tag?", vm.confirmation.prompt)
+	hash := client.AbbrevHash(refsTestGit(t, dir, "rev-parse", "HEAD"))
+	assert.Equal(t, []string{
+		"branch old-1 at " + hash,
+		"branch old-2 at " + hash,
+		"tag    v0 at " + hash,
+	}, vm.confirmation.details)
+	require.NoError(t, vm.Render())
+	assert.Contains(t, strings.Join(screenLines(screen), "\n"), "Delete 2 branches and 1 tag? [y/N]")
+
+	vm.HandleKey(tcell.KeyEsc, 0, 0)
+	assert.False(t, vm.Confirming())
+	assert.Equal(t, "Cancelled", vm.GetMessage())
+	assert.Equal(t, "keep\nmain\nold-1\nold-2", refsTestGit(t, dir, "branch", "--format=%(refname:short)"))
+
+	vm.HandleKey(tcell.KeyRune, 'D', 0)
+	vm.HandleKey(tcell.KeyRune, 'y', 0)
+	assert.Equal(t, "Deleted 2 branches and 1 tag", vm.GetMessage())
+	assert.Equal(t, "keep\nmain", refsTestGit(t, dir, "branch", "--format=%(refname:short)"))
+	assert.Empty(t, refsTestGit(t, dir, "tag"))
+	assert.Empty(t, view.tags)
+	assert.Empty(t, view.marked)
+}
+
+func TestCountRefs(t *testing.T) {
+	assert.Equal(t, "1 branch", countRefs(1, 0))
+	assert.Equal(t, "3 tags", countRefs(0, 3))
+	assert.Equal(t, "1 branch and 2 tags", countRefs(1, 2))
+}
+
+func TestAheadBehindUpstream(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	refsTestGit(t, dir, "remote", "add", "origin", dir)
+	refsTestGit(t, dir, "update-ref", "refs/remotes/origin/main", "topic")
+	refsTestGit(t, dir, "branch", "--quiet", "--set-upstream-to=origin/main")
+	refsTestGit(t, dir, "commit", "--quiet", "--allow-empty", "-m", "local")
+	refsTestGit(t, dir, "branch", "--quiet", "--set-upstream-to=main", "topic")
+
+	// The status view words it as git status does
+	statusView := vm.views[ViewTypeStatus].(*StatusView)
+	require.NoError(t, statusView.refreshChanged())
+	assert.Contains(t, statusView.buildStatusLines(), "Your branch and 'origin/main' have diverged, and have 1 and 1 different commits each, respectively.")
+	refsTestGit(t, dir, "update-ref", "refs/remotes/origin/main", "main")
+	require.NoError(t, statusView.refreshChanged())
+	assert.Contains(t, statusView.buildStatusLines(), "Your branch is up to date with 'origin/main'.")
+
+	// Branches show it in a column before their hash
+	refsView := vm.views[ViewTypeRefs].(*RefsView)
+	vm.screen.(tcell.SimulationScreen).SetSize(100, 30)
+	require.NoError(t, vm.SwitchView(ViewTypeRefs))
+	vm.updateRefIndex()
+	require.NoError(t, refsView.Refresh())
+	vm.Render()
+	var rows []string
+	for _, line := range screenLines(vm.screen) {
+		if strings.Contains(line, "main [origin/main]") || strings.Contains(line, "topic [main]") {
+			rows = append(rows, line)
+		}
+	}
+	require.Len(t, rows, 2)
+	main := vm.client.AbbrevHash(refsTestGit(t, dir, "rev-parse", "main"))
+	topic := vm.client.AbbrevHash(refsTestGit(t, dir, "rev-parse", "topic"))
+	assert.True(t, strings.HasSuffix(strings.TrimRight(rows[0], " │"), "      "+main), rows[0])
+	assert.True(t, strings.HasSuffix(strings.TrimRight(rows[1], " │"), "↑1 ↓1 1 ahead, 1 behind main "+topic), rows[1])
+}
+
+func TestRefsViewTopology(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	refsTestGit(t, dir, "tag", "v1", "main")
+	refsTestGit(t, dir, "branch", "merged", "main")
+	vm.screen.(tcell.SimulationScreen).SetSize(100, 30)
+	require.NoError(t, vm.SwitchView(ViewTypeRefs))
+	vm.refreshAll()
+	refsView := vm.views[ViewTypeRefs].(*RefsView)
+
+	// Branches say where they stand against the default branch
+	require.NoError(t, vm.Render())
+	screen := strings.Join(screenLines(vm.screen), "\n")
+	assert.Contains(t, screen, "1 ahead of main, based on v1 "+vm.client.AbbrevHash(refsTestGit(t, dir, "rev-parse", "topic")))
+	assert.Contains(t, screen, "at main")
+	assert.Equal(t, "", refsView.topologyHint(refsView.branches[0]))
+
+	// They are worked out again once the branch or the default branch moved
+	refsTestGit(t, dir, "commit", "--quiet", "--allow-empty", "-m", "main")
+	vm.refreshAll()
+	require.NoError(t, vm.Render())
+	screen = strings.Join(screenLines(vm.screen), "\n")
+	assert.Contains(t, screen, "1 ahead, 1 behind main, based on v1")
+	assert.Contains(t, screen, "merged into main")
+	assert.Len(t, refsView.topology, 2)
+
+	// Rows out of sight are neither abbreviated nor worked out
+	refsView.topology = make(map[string]*refTopology)
+	table := refsView.table(refsView.getCurrentItems(), 1, 1)
+	assert.Empty(t, table.Rows[0].Cells[4])
+	assert.NotEmpty(t, table.Rows[1].Cells[4])
+	assert.Empty(t, table.Rows[2].Cells[4])
+	assert.Len(t, refsView.topology, 1)
+
+	// Tags say which tags they follow
+	refsTestGit(t, dir, "tag", "v2", "topic")
+	vm.refreshAll()
+	assert.Equal(t, "after v1", refsView.topologyHint(&RefItem{Type: "tag", Name: "v2"}))
+	assert.Equal(t, "", refsView.topologyHint(&RefItem{Type: "tag", Name: "v1"}))
+}
+
+func TestRefsViewCheckout(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	refsTestGit(t, dir, "tag", "v1", "topic")
+	require.NoError(t, vm.SwitchView(ViewTypeRefs))
+	view := vm.views[ViewTypeRefs].(*RefsView)
+
+	// Enter checks out the selected branch, the views refreshed after
+	vm.HandleKey(tcell.KeyRune, 'j', 0)
+	require.Equal(t, "topic", view.SelectedItem().Name)
+	vm.HandleKey(tcell.KeyEnter, 0, 0)
+	assert.Equal(t, "Switched to branch topic", vm.GetMessage())
+	assert.Equal(t, "topic", refsTestGit(t, dir, "branch", "--show-current"))
+	assert.True(t, view.SelectedItem().Current)
+	vm.HandleKey(tcell.KeyRune, 'c', 0)
+	assert.Equal(t, "Already on topic", vm.GetMessage())
+
+	// Local changes in the way are offered to be stashed
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("mine\n"), 0644))
+	vm.HandleKey(tcell.KeyRune, 'k', 0)
+	vm.HandleKey(tcell.KeyRune, 'c', 0)
+	require.True(t, vm.Confirming())
+	assert.Contains(t, vm.confirmation.prompt, "Stash the local changes and checkout main")
+	vm.HandleKey(tcell.KeyEsc, 0, 0)
+	refsTestGit(t, dir, "checkout", "--quiet", "--", "file.txt")
+
+	// Tags detach HEAD, remotes can't be checked out
+	vm.HandleKey(tcell.KeyRune, '2', 0)
+	vm.HandleKey(tcell.KeyRune, 'c', 0)
+	assert.Equal(t, "Checked out tag v1, HEAD is now detached", vm.GetMessage())
+	assert.Empty(t, refsTestGit(t, dir, "branch", "--show-current"))
+	vm.HandleKey(tcell.KeyRune, '3', 0)
+	vm.HandleKey(tcell.KeyEnter, 0, 0)
+	assert.Equal(t, "No branch or tag selected", vm.GetMessage())
+
+	// Read-only mode refuses it
+	vm.config.General.ReadOnly = true
+	vm.HandleKey(tcell.KeyRune, '1', 0)
+	vm.HandleKey(tcell.KeyEnter, 0, 0)
+	assert.Equal(t, "checkout is disabled in read-only mode", vm.GetMessage())
+}
+
+func TestRefsViewBranchLifecycle(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	refsTestGit(t, dir, "tag", "v1", "topic")
+	require.NoError(t, vm.refreshAll())
+	require.NoError(t, vm.SwitchView(ViewTypeRefs))
+	view := vm.views[ViewTypeRefs].(*RefsView)
+
+	// New branches start at the selected ref
+	vm.HandleKey(tcell.KeyRune, '2', 0)
+	vm.HandleKey(tcell.KeyRune, 'n', 0)
+	assert.Equal(t, "branch ", vm.TakeCommandRequest())
+	require.NoError(t, vm.BranchCommand([]string{"release"}))
+	topic := refsTestGit(t, dir, "rev-parse", "topic")
+	assert.Equal(t, "Created branch release at "+vm.client.AbbrevHash(topic), vm.GetMessage())
+	assert.Equal(t, topic, refsTestGit(t, dir, "rev-parse", "release"))
+
+	// Renaming prompts with the selected branch
+	vm.HandleKey(tcell.KeyRune, '1', 0)
+	require.Equal(t, "main", view.SelectedItem().Name)
+	vm.HandleKey(tcell.KeyRune, 'j', 0)
+	require.Equal(t, "release", view.SelectedItem().Name)
+	vm.HandleKey(tcell.KeyRune, 'm', 0)
+	assert.Equal(t, "branch -m release ", vm.TakeCommandRequest())
+	require.NoError(t, vm.BranchCommand([]string{"-m", "release", "release-1"}))
+	assert.Equal(t, "Renamed branch release to release-1", vm.GetMessage())
+
+	// Branches not merged into HEAD are deleted with force once confirmed
+	require.Equal(t, "release-1", view.SelectedItem().Name)
+	vm.HandleKey(tcell.KeyRune, 'd', 0)
+	require.True(t, vm.Confirming())
+	assert.Equal(t, "Force delete branch release-1, not merged into HEAD?", vm.confirmation.prompt)
+	vm.HandleKey(tcell.KeyRune, 'y', 0)
+	assert.Equal(t, "Deleted branch release-1 (was "+vm.client.AbbrevHash(topic)+")", vm.GetMessage())
+	assert.Equal(t, "main\ntopic", refsTestGit(t, dir, "branch", "--format=%(refname:short)"))
+
+	// Merged ones straight away when so configured
+	refsTestGit(t, dir, "branch", "merged")
+	vm.config.General.Confirm = map[string]string{"branch-delete": "none"}
+	require.NoError(t, vm.BranchCommand([]string{"-d", "merged"}))
+	assert.False(t, vm.Confirming())
+	assert.Equal(t, "main\ntopic", refsTestGit(t, dir, "branch", "--format=%(refname:short)"))
+
+	assert.EqualError(t, vm.BranchCommand([]string{"-d", "main"}), "cannot delete the checked out branch main")
+	assert.Error(t, vm.BranchCommand([]string{"-m"}))
+}
+
+func TestRefsViewFilter(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	for _, tag := range []string{"v1", "v2", "release"} {
+		refsTestGit(t, dir, "tag", tag)
+	}
+	vm.screen.(tcell.SimulationScreen).SetSize(100, 30)
+	require.NoError(t, vm.SwitchView(ViewTypeRefs))
+	vm.refreshAll()
+	render := func() string {
+		require.NoError(t, vm.Render())
+		return strings.Join(screenLines(vm.screen), "\n")
+	}
+
+	// The tabs count the refs of each section
+	assert.Contains(t, render(), " Branches (2)   Tags (3)   Remotes (0) ")
+
+	// They count those matching the filter too, kept up to date as refs
+	// come and go
+	vm.HandleKey(tcell.KeyRune, '/', 0)
+	assert.Equal(t, "refs-filter ", vm.TakeCommandRequest())
+	require.NoError(t, vm.RefsFilterCommand([]string{"v*"}))
+	screen := render()
+	assert.Contains(t, screen, " Branches (0/2)   Tags (2/3)   Remotes (0/0) ")
+	assert.Contains(t, screen, `None of the 2 match "v*"`)
+	assert.Contains(t, screen, "Filter: v*")
+
+	vm.HandleKey(tcell.KeyRune, 't', 0)
+	refsTestGit(t, dir, "tag", "v3")
+	vm.refreshAll()
+	screen = render()
+	assert.Contains(t, screen, " Tags (3/4) ")
+	assert.Contains(t, screen, "Tags (3 of 4)")
+	assert.NotContains(t, screen, "release")
+
+	require.NoError(t, vm.RefsFilterCommand(nil))
+	assert.Equal(t, "Showing all refs", vm.GetMessage())
+	assert.Contains(t, render(), "Tags (4)")
+}
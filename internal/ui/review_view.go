@@ -0,0 +1,415 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// reviewKeys are the keys of the review view acting on notes
+var reviewKeys = map[rune]string{
+	'd': "review-delete",
+}
+
+// ReviewView lists the notes left on files and hunks of the commits and
+// ranges under review, which open the diff they were left on
+type ReviewView struct {
+	*BaseView
+	*Scrollable
+	config   *config.Config
+	client   git.Client
+	notes    []*git.ReviewNote
+	err      error
+	selected int
+	repoPath string
+	frame    *Frame
+
+	run func(action string) // Runs the action of a key on the selected note
+}
+
+// NewReviewView creates a new review view
+func NewReviewView(config *config.Config, client git.Client) *ReviewView {
+	return &ReviewView{
+		BaseView:   NewBaseView(ViewTypeReview),
+		Scrollable: NewScrollable(),
+		config:     config,
+		client:     client,
+		frame:      NewFrame(config, "Review"),
+	}
+}
+
+// Render renders the review view
+func (v *ReviewView) Render(screen tcell.Screen, x, y, width, height int) error {
+	v.SetPosition(x, y, width, height)
+	v.SetHeight(height - 2) // Account for borders
+
+	v.frame.Title = "Review"
+	if len(v.notes) > 0 {
+		v.frame.Title = fmt.Sprintf("Review: %d notes", len(v.notes))
+	}
+	contentX, contentY, contentWidth, contentHeight := v.frame.Draw(screen, x, y, width, height)
+	defer v.frame.DrawScrollbar(screen, v.Scrollable)
+
+	if contentWidth <= 0 || contentHeight <= 0 {
+		return nil
+	}
+
+	if v.err != nil || len(v.notes) == 0 {
+		msg := "No review notes, press M on a hunk of a diff to leave one"
+		if v.err != nil {
+			msg = v.err.Error()
+		}
+		msgX := contentX + (contentWidth-len(msg))/2
+		msgY := contentY + contentHeight/2
+		drawCells(screen, max(contentX, msgX), msgY, contentWidth, textCells(msg, tcell.StyleDefault.Dim(true)), 0, tcell.StyleDefault)
+		return nil
+	}
+
+	v.SetMaxOffset(len(v.notes) - contentHeight)
+
+	start := v.GetOffset()
+	end := min(start+contentHeight, len(v.notes))
+	for i := start; i < end; i++ {
+		v.drawNote(screen, contentX, contentY+(i-start), contentWidth, i)
+	}
+
+	return nil
+}
+
+// drawNote draws a note as its revision, file and line, and first line
+func (v *ReviewView) drawNote(screen tcell.Screen, x, y, width, index int) {
+	note := v.notes[index]
+
+	revisionStyle := tcell.StyleDefault.Foreground(tcell.ColorYellow)
+	pathStyle := tcell.StyleDefault.Foreground(tcell.ColorTeal)
+	textStyle := tcell.StyleDefault
+	if index == v.selected {
+		background := tcell.ColorDarkBlue
+		if v.IsFocused() {
+			background = tcell.ColorBlue
+		}
+		revisionStyle = revisionStyle.Background(background)
+		pathStyle = pathStyle.Background(background)
+		textStyle = textStyle.Background(background)
+	}
+
+	location := note.Path
+	if note.Hunk != "" {
+		location = fmt.Sprintf("%s:%d", note.Path, note.Line)
+	}
+	text, _, _ := strings.Cut(note.Text, "\n")
+
+	cells := textCells(v.abbrevRevision(note.Revision)+" ", revisionStyle)
+	cells = append(cells, textCells(location+" ", pathStyle)...)
+	cells = append(cells, textCells(text, textStyle)...)
+	if index == v.selected {
+		for cellsWidth(cells) < width {
+			cells = append(cells, cell{' ', textStyle})
+		}
+	}
+
+	drawCells(screen, x, y, width, cells, 0, textStyle)
+}
+
+// abbrevRevision abbreviates the hashes of a commit or range
+func (v *ReviewView) abbrevRevision(revision string) string {
+	from, to, isRange := strings.Cut(revision, "..")
+	if !isRange {
+		return v.client.AbbrevHash(revision)
+	}
+	return v.client.AbbrevHash(from) + ".." + v.client.AbbrevHash(to)
+}
+
+// HandleKey handles keyboard input
+func (v *ReviewView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
+	if !v.IsFocused() {
+		return false
+	}
+
+	switch key {
+	case tcell.KeyUp:
+		v.moveUp()
+		return true
+	case tcell.KeyDown:
+		v.moveDown()
+		return true
+	case tcell.KeyHome:
+		v.ScrollToTop()
+		v.selected = 0
+		return true
+	case tcell.KeyEnd:
+		v.ScrollToBottom()
+		v.selected = max(0, len(v.notes)-1)
+		return true
+	}
+
+	if action, ok := reviewKeys[ch]; ok && key == tcell.KeyRune {
+		if v.run != nil {
+			v.run(action)
+		}
+		return true
+	}
+
+	switch ch {
+	case 'j':
+		v.moveDown()
+		return true
+	case 'k':
+		v.moveUp()
+		return true
+	case 'g':
+		v.ScrollToTop()
+		v.selected = 0
+		return true
+	case 'G':
+		v.ScrollToBottom()
+		v.selected = max(0, len(v.notes)-1)
+		return true
+	}
+
+	return false
+}
+
+// moveUp moves selection up
+func (v *ReviewView) moveUp() {
+	if v.selected > 0 {
+		v.selected--
+		if v.selected < v.GetOffset() {
+			v.ScrollUp()
+		}
+	}
+}
+
+// moveDown moves selection down
+func (v *ReviewView) moveDown() {
+	if v.selected < len(v.notes)-1 {
+		v.selected++
+		visibleEnd := v.GetOffset() + v.getPageSize()
+		if v.selected >= visibleEnd {
+			v.ScrollDown()
+		}
+	}
+}
+
+// getPageSize returns the number of visible lines
+func (v *ReviewView) getPageSize() int {
+	_, _, _, height := v.GetPosition()
+	return height - 2 // Account for borders
+}
+
+// Refresh reads the notes again
+func (v *ReviewView) Refresh() error {
+	if !v.client.IsRepository() {
+		v.notes, v.err = nil, nil
+		v.selected = 0
+		return nil
+	}
+	v.notes, v.err = v.client.ReviewNotes()
+	v.selected = max(0, min(v.selected, len(v.notes)-1))
+	return nil
+}
+
+// GetSelectedNote returns the selected note
+func (v *ReviewView) GetSelectedNote() *git.ReviewNote {
+	if v.selected < 0 || v.selected >= len(v.notes) {
+		return nil
+	}
+	return v.notes[v.selected]
+}
+
+// DiffRange returns the diff of the file the selected note was left on
+func (v *ReviewView) DiffRange() (string, []string, error) {
+	note := v.GetSelectedNote()
+	if note == nil {
+		return "", nil, fmt.Errorf("no note selected")
+	}
+	revRange := note.Revision
+	if !strings.Contains(revRange, "..") {
+		revRange = note.Revision + "^.." + note.Revision
+	}
+	return revRange, []string{note.Path}, nil
+}
+
+// Selection returns the selected note for placeholder expansion
+func (v *ReviewView) Selection() Selection {
+	var sel Selection
+	if note := v.GetSelectedNote(); note != nil {
+		if !strings.Contains(note.Revision, "..") {
+			sel.Commit = note.Revision
+		}
+		sel.File = note.Path
+		sel.Lineno = note.Line
+	}
+	return sel
+}
+
+// SetRepoPath sets the repository path
+func (v *ReviewView) SetRepoPath(path string) {
+	v.repoPath = path
+	v.notes, v.err = nil, nil
+	v.selected = 0
+	v.ScrollToTop()
+}
+
+// reviewTarget returns a note without text on the hunk under the cursor,
+// or on the file when the cursor is on its header
+func (v *DiffView) reviewTarget() (*git.ReviewNote, error) {
+	revision := v.commitHash
+	if v.revRange != "" {
+		revision = v.revRange
+	}
+	if v.stage != nil || revision == "" {
+		return nil, fmt.Errorf("review notes are left on commits and ranges")
+	}
+
+	offset := v.GetOffset()
+	if offset >= len(v.rows) || v.rows[offset].file == nil {
+		return nil, fmt.Errorf("no file under the cursor")
+	}
+	row := v.rows[offset]
+	note := &git.ReviewNote{Revision: revision, Path: row.file.Path()}
+	if row.hunk >= 0 {
+		hunk := row.file.Hunks[row.hunk]
+		note.Hunk = fmt.Sprintf("@@ -%s +%s @@", formatHunkRange(hunk.OldStart, hunk.OldLines), formatHunkRange(hunk.NewStart, hunk.NewLines))
+		note.Line = hunk.NewStart
+	}
+	return note, nil
+}
+
+// noteHunk prompts for a note on the hunk or file under the cursor of the
+// diff view
+func (vm *ViewManager) noteHunk() error {
+	diffView, ok := vm.views[ViewTypeDiff].(*DiffView)
+	if !ok {
+		return fmt.Errorf("diff view not found")
+	}
+	if _, err := diffView.reviewTarget(); err != nil {
+		return err
+	}
+	vm.commandRequest = "review-note "
+	return nil
+}
+
+// ReviewNoteCommand handles the :review-note command, leaving a note on
+// the hunk or file under the cursor of the diff view
+func (vm *ViewManager) ReviewNoteCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	text := strings.TrimSpace(strings.Join(args, " "))
+	if text == "" {
+		return fmt.Errorf("usage: review-note <text>")
+	}
+	diffView, ok := vm.views[ViewTypeDiff].(*DiffView)
+	if !ok {
+		return fmt.Errorf("diff view not found")
+	}
+	note, err := diffView.reviewTarget()
+	if err != nil {
+		return err
+	}
+	note.Text = text
+	note.Time = time.Now()
+
+	notes, err := vm.client.ReviewNotes()
+	if err != nil {
+		return err
+	}
+	if err := vm.client.SaveReviewNotes(append(notes, note)); err != nil {
+		return err
+	}
+	vm.setMessage("Noted %s, %d notes in the review", note.Path, len(notes)+1)
+	if view, ok := vm.views[ViewTypeReview].(*ReviewView); ok {
+		return view.Refresh()
+	}
+	return nil
+}
+
+// runReviewAction runs the action of a key of the review view on the
+// selected note, deleting it once confirmed
+func (vm *ViewManager) runReviewAction(action string) {
+	view, ok := vm.views[ViewTypeReview].(*ReviewView)
+	if !ok || action != "review-delete" {
+		return
+	}
+	note := view.GetSelectedNote()
+	if note == nil {
+		vm.setMessage("No note selected")
+		return
+	}
+
+	vm.askConfirmation("Delete the note?", []string{note.Text}, func() error {
+		notes, err := vm.client.ReviewNotes()
+		if err != nil {
+			return err
+		}
+		kept := notes[:0]
+		for _, n := range notes {
+			if !n.Time.Equal(note.Time) || n.Text != note.Text {
+				kept = append(kept, n)
+			}
+		}
+		if err := vm.client.SaveReviewNotes(kept); err != nil {
+			return err
+		}
+		vm.setMessage("Deleted the note on %s", note.Path)
+		return view.Refresh()
+	})
+}
+
+// ReviewCommand handles the :review command, which shows the review notes,
+// writes them as markdown with export, and deletes them all with clear
+func (vm *ViewManager) ReviewCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	view, ok := vm.views[ViewTypeReview].(*ReviewView)
+	if !ok {
+		return fmt.Errorf("review view not found")
+	}
+	if len(args) == 0 {
+		if err := view.Refresh(); err != nil {
+			return err
+		}
+		return vm.switchView(ViewTypeReview)
+	}
+
+	notes, err := vm.client.ReviewNotes()
+	if err != nil {
+		return err
+	}
+	switch {
+	case args[0] == "export" && len(args) == 2:
+		if len(notes) == 0 {
+			return fmt.Errorf("no review notes to export")
+		}
+		path := args[1]
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(vm.client.GetRootPath(), path)
+		}
+		if err := os.WriteFile(path, []byte(git.FormatReview(notes)), 0644); err != nil {
+			return fmt.Errorf("failed to export the review: %w", err)
+		}
+		vm.setMessage("Wrote %d notes to %s", len(notes), args[1])
+		return nil
+	case args[0] == "clear" && len(args) == 1:
+		if len(notes) == 0 {
+			return fmt.Errorf("no review notes to clear")
+		}
+		vm.askConfirmation(fmt.Sprintf("Delete all %d review notes?", len(notes)), nil, func() error {
+			if err := vm.client.SaveReviewNotes(nil); err != nil {
+				return err
+			}
+			vm.setMessage("Deleted the review notes")
+			return view.Refresh()
+		})
+		return nil
+	}
+	return fmt.Errorf("usage: review [export <file>|clear]")
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewNotes(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	view := vm.views[ViewTypeReview].(*ReviewView)
+	diffView := vm.views[ViewTypeDiff].(*DiffView)
+	topic := strings.TrimSpace(refsTestGit(t, dir, "rev-parse", "topic"))
+
+	// Notes are left on commits and ranges only
+	require.NoError(t, vm.showStageFile("file.txt", statusSectionModified))
+	vm.HandleKey(tcell.KeyRune, 'M', 0)
+	assert.Equal(t, "review notes are left on commits and ranges", vm.GetMessage())
+
+	vm.SetSize(100, 5)
+	diffView.SetCommitHash(topic)
+	require.NoError(t, vm.SwitchView(ViewTypeDiff))
+	for i := 0; diffView.rows[diffView.GetOffset()].hunk < 0; i++ {
+		require.Less(t, i, 30)
+		vm.HandleKey(tcell.KeyRune, 'j', 0)
+	}
+	vm.HandleKey(tcell.KeyRune, 'M', 0)
+	assert.Equal(t, "review-note ", vm.commandRequest)
+	vm.commandRequest = ""
+	require.NoError(t, vm.ReviewNoteCommand([]string{"Why", "topic?"}))
+	assert.Equal(t, "Noted file.txt, 1 notes in the review", vm.GetMessage())
+
+	require.NoError(t, diffView.SetRange("main..topic", nil))
+	require.NoError(t, vm.ReviewNoteCommand([]string{"Fine"}))
+	assert.Error(t, vm.ReviewNoteCommand(nil))
+
+	vm.SetSize(100, 30)
+	require.NoError(t, vm.ReviewCommand(nil))
+	assert.Equal(t, ViewTypeReview, vm.GetCurrentView())
+	require.Len(t, view.notes, 2)
+	assert.Equal(t, "@@ -1 +1 @@", view.notes[0].Hunk)
+	assert.Empty(t, view.notes[1].Hunk)
+	require.NoError(t, vm.Render())
+	text := strings.Join(screenLines(vm.screen), "\n")
+	assert.Contains(t, text, "Review: 2 notes")
+	assert.Contains(t, text, "file.txt:1 Why topic?")
+
+	require.NoError(t, vm.ReviewCommand([]string{"export", "review.md"}))
+	data, err := os.ReadFile(filepath.Join(dir, "review.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "- `@@ -1 +1 @@` line 1: Why topic?\n")
+	assert.Contains(t, string(data), "## main..topic\n")
+
+	// Enter shows the diff of the file of the note
+	vm.HandleKey(tcell.KeyEnter, 0, 0)
+	require.Equal(t, ViewTypeDiff, vm.GetCurrentView())
+	revRange, paths := diffView.GetRange()
+	assert.Equal(t, topic+"^.."+topic, revRange)
+	assert.Equal(t, []string{"file.txt"}, paths)
+
+	require.NoError(t, vm.SwitchView(ViewTypeReview))
+	vm.HandleKey(tcell.KeyRune, 'd', 0)
+	require.True(t, vm.Confirming())
+	vm.HandleKey(tcell.KeyRune, 'y', 0)
+	require.Len(t, view.notes, 1)
+	assert.Equal(t, "Fine", view.notes[0].Text)
+
+	require.NoError(t, vm.ReviewCommand([]string{"clear"}))
+	vm.HandleKey(tcell.KeyRune, 'y', 0)
+	assert.Empty(t, view.notes)
+	assert.Error(t, vm.ReviewCommand([]string{"export", "review.md"}))
+}
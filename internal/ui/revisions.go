@@ -0,0 +1,229 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/azhao1981/tig/internal/git"
+)
+
+// recentCommitCandidates is how many of the latest commits of the main
+// view are offered when completing revisions
+const recentCommitCandidates = 20
+
+// splitRevRange returns the revisions of a range such as A..B or A...B,
+// leaving out the sides left empty
+func splitRevRange(revRange string) []string {
+	var revs []string
+	for _, part := range strings.Split(strings.ReplaceAll(revRange, "...", ".."), "..") {
+		if part != "" {
+			revs = append(revs, part)
+		}
+	}
+	return revs
+}
+
+// checkRevisions makes sure the revisions and ranges of a command name
+// commits before it runs
+func (vm *ViewManager) checkRevisions(args ...string) error {
+	if !vm.client.IsRepository() {
+		return fmt.Errorf("not in a git repository")
+	}
+	for _, arg := range args {
+		for _, rev := range splitRevRange(arg) {
+			if _, err := vm.client.ResolveRevision(rev); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CompleteRevision returns the branches, tags and recent commits which
+// complete the revision being typed. In a range only the last revision is
+// completed.
+func (vm *ViewManager) CompleteRevision(word string) []string {
+	vm.mutex.RLock()
+	defer vm.mutex.RUnlock()
+
+	if !vm.client.IsRepository() {
+		return nil
+	}
+
+	prefix := ""
+	if i := strings.LastIndex(word, ".."); i >= 0 {
+		prefix, word = word[:i+2], word[i+2:]
+		if strings.HasPrefix(word, ".") {
+			prefix, word = prefix+".", word[1:]
+		}
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if strings.HasPrefix(name, word) && !seen[name] {
+			seen[name] = true
+			names = append(names, prefix+name)
+		}
+	}
+
+	add("HEAD")
+	var refNames []string
+	for _, ref := range append(vm.refIndex.OfType(git.RefTypeBranch), vm.refIndex.OfType(git.RefTypeTag)...) {
+		refNames = append(refNames, git.ShortRefName(ref.Name))
+	}
+	sort.Strings(refNames)
+	for _, name := range refNames {
+		add(name)
+	}
+
+	// Recent commits follow the names, newest first
+	if mainView, ok := vm.views[ViewTypeMain].(*MainView); ok {
+		for i, commit := range mainView.commits {
+			if i == recentCommitCandidates {
+				break
+			}
+			add(vm.client.AbbrevHash(commit.Hash))
+		}
+	}
+	return names
+}
+
+// DiffCommand handles the :diff command, showing the diff of a commit or
+// of a range such as A..B, or the diff view as it is without arguments
+func (vm *ViewManager) DiffCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if len(args) > 1 {
+		return fmt.Errorf("usage: diff [rev|rev1..rev2]")
+	}
+	diffView, ok := vm.views[ViewTypeDiff].(*DiffView)
+	if !ok {
+		return fmt.Errorf("diff view not found")
+	}
+	if len(args) == 0 {
+		return vm.switchView(ViewTypeDiff)
+	}
+
+	if strings.Contains(args[0], "..") {
+		if err := vm.checkRevisions(args[0]); err != nil {
+			return err
+		}
+		if err := diffView.SetRange(args[0], nil); err != nil {
+			return err
+		}
+		return vm.switchView(ViewTypeDiff)
+	}
+
+	hash, err := vm.client.ResolveRevision(args[0])
+	if err != nil {
+		return err
+	}
+	diffView.SetCommitHash(hash)
+	return vm.switchView(ViewTypeDiff)
+}
+
+// ShowCommand handles the :show command, showing the diff of a commit.
+// The word commit may come first, as in show commit <rev>.
+func (vm *ViewManager) ShowCommand(args []string) error {
+	if len(args) == 2 && args[0] == "commit" {
+		args = args[1:]
+	}
+	if len(args) != 1 || strings.Contains(args[0], "..") {
+		return fmt.Errorf("usage: show [commit] <rev>")
+	}
+	return vm.DiffCommand(args)
+}
+
+// BlameCommand handles the :blame command, showing the diff of the commit
+// which last changed a line of a file in the work tree, or the file at the
+// line when it is not committed yet. Paths are relative to the root of the
+// repository unless absolute.
+func (vm *ViewManager) BlameCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if len(args) != 1 {
+		return fmt.Errorf("usage: blame <file>[:<line>]")
+	}
+	if !vm.client.IsRepository() {
+		return fmt.Errorf("not in a git repository")
+	}
+	path, line := args[0], 1
+	if i := strings.LastIndex(path, ":"); i >= 0 {
+		n, err := strconv.Atoi(path[i+1:])
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid line number: %s", path[i+1:])
+		}
+		path, line = path[:i], n
+	}
+	if filepath.IsAbs(path) {
+		rel, err := filepath.Rel(vm.client.GetRootPath(), path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("%s is outside of the repository", path)
+		}
+		path = rel
+	}
+	path = filepath.ToSlash(filepath.Clean(path))
+
+	lines, err := vm.client.Blame("", path, &git.BlameOptions{
+		IgnoreRevs: strings.Fields(vm.config.Git.BlameIgnoreRevs),
+	})
+	if err != nil {
+		return err
+	}
+	if line > len(lines) {
+		return fmt.Errorf("%s has no line %d", path, line)
+	}
+	commit := lines[line-1].Commit
+
+	if commit == git.NotCommitted {
+		blobView, ok := vm.views[ViewTypeBlob].(*BlobView)
+		if !ok {
+			return fmt.Errorf("blob view not found")
+		}
+		if err := blobView.SetFile("", path, line); err != nil {
+			return err
+		}
+		vm.setMessage("Line %d of %s is not committed yet", line, path)
+		return vm.switchView(ViewTypeBlob)
+	}
+
+	diffView, ok := vm.views[ViewTypeDiff].(*DiffView)
+	if !ok {
+		return fmt.Errorf("diff view not found")
+	}
+	diffView.SetCommitHash(commit)
+	diffView.restoreAnchor(path, 0)
+	vm.setMessage("Line %d of %s was last changed by %s", line, path, vm.client.AbbrevHash(commit))
+	return vm.switchView(ViewTypeDiff)
+}
+
+// LogCommand handles the :log command, selecting the given commit in the
+// main view
+func (vm *ViewManager) LogCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if len(args) > 1 {
+		return fmt.Errorf("usage: log [rev]")
+	}
+	mainView, ok := vm.views[ViewTypeMain].(*MainView)
+	if !ok {
+		return fmt.Errorf("main view not found")
+	}
+	if len(args) == 1 {
+		hash, err := vm.client.ResolveRevision(args[0])
+		if err != nil {
+			return err
+		}
+		if !mainView.selectCommit(hash) {
+			return fmt.Errorf("%s is not loaded in the log", args[0])
+		}
+	}
+	return vm.switchView(ViewTypeMain)
+}
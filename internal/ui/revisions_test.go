@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevisionCommands(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	refsTestGit(t, dir, "init", "--quiet", "--initial-branch=main")
+	refsTestGit(t, dir, "commit", "--quiet", "--allow-empty", "-m", "first")
+	first := refsTestGit(t, dir, "rev-parse", "HEAD")
+	refsTestGit(t, dir, "commit", "--quiet", "--allow-empty", "-m", "second")
+	refsTestGit(t, dir, "branch", "topic-a")
+	refsTestGit(t, dir, "branch", "topic-b")
+	refsTestGit(t, dir, "tag", "v1.0", first)
+
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+	vm.SetSize(100, 30)
+	vm.SetRepoPath(dir)
+	vm.views[ViewTypeMain].(*MainView).commits = []*git.Commit{
+		{Hash: refsTestGit(t, dir, "rev-parse", "HEAD")},
+		{Hash: first},
+	}
+
+	assert.Equal(t, []string{"topic-a", "topic-b"}, vm.CompleteRevision("top"))
+	assert.Equal(t, []string{"main..v1.0"}, vm.CompleteRevision("main..v"))
+	assert.Equal(t, []string{"main...topic-a", "main...topic-b"}, vm.CompleteRevision("main...t"))
+	assert.Equal(t, []string{client.AbbrevHash(first)}, vm.CompleteRevision(first[:6]))
+	assert.Contains(t, vm.CompleteRevision(""), "HEAD")
+
+	// Unknown revisions are refused before anything runs
+	assert.EqualError(t, vm.DiffStatCommand([]string{"main..nope"}), "unknown revision: nope")
+	assert.EqualError(t, vm.CompareCommand([]string{"nope"}), "unknown revision: nope")
+	assert.EqualError(t, vm.DiffCommand([]string{"nope"}), "unknown revision: nope")
+	assert.EqualError(t, vm.LogCommand([]string{"nope"}), "unknown revision: nope")
+
+	require.NoError(t, vm.LogCommand([]string{"v1.0"}))
+	assert.Equal(t, ViewTypeMain, vm.GetCurrentView())
+	assert.Equal(t, first, vm.GetSelectedCommit().Hash)
+
+	require.NoError(t, vm.DiffCommand([]string{"v1.0"}))
+	assert.Equal(t, ViewTypeDiff, vm.GetCurrentView())
+	diffView := vm.views[ViewTypeDiff].(*DiffView)
+	assert.Equal(t, first, diffView.GetCommitHash())
+	require.NoError(t, vm.DiffCommand([]string{"v1.0..main"}))
+	revRange, _ := diffView.GetRange()
+	assert.Equal(t, "v1.0..main", revRange)
+}
+
+func TestCommandArgumentCompletion(t *testing.T) {
+	cm := NewCommandManager()
+	cm.Register(&Command{Name: "show", Handler: func([]string) error { return nil }, Complete: func(word string) []string {
+		var matches []string
+		for _, name := range []string{"feature-a", "feature-b", "main"} {
+			if len(word) <= len(name) && name[:len(word)] == word {
+				matches = append(matches, name)
+			}
+		}
+		return matches
+	}})
+
+	cm.StartCommandModeWith("show f")
+	cm.HandleKey(tcell.KeyTab, 0, 0)
+	assert.Equal(t, "show feature-", cm.GetBuffer())
+	assert.Equal(t, []string{"feature-a", "feature-b"}, cm.GetSuggestions())
+
+	cm.HandleKey(tcell.KeyRune, 'b', 0)
+	assert.Empty(t, cm.GetSuggestions())
+	cm.HandleKey(tcell.KeyTab, 0, 0)
+	assert.Equal(t, "show feature-b ", cm.GetBuffer())
+
+	cm.HandleKey(tcell.KeyRune, 'x', 0)
+	cm.HandleKey(tcell.KeyTab, 0, 0)
+	assert.Equal(t, "show feature-b x", cm.GetBuffer())
+
+	// Commands without completion are left alone
+	cm.StartCommandModeWith("refresh f")
+	cm.HandleKey(tcell.KeyTab, 0, 0)
+	assert.Equal(t, "refresh f", cm.GetBuffer())
+	cm.StartCommandModeWith("refr")
+	cm.HandleKey(tcell.KeyTab, 0, 0)
+	assert.Equal(t, "refresh", cm.GetBuffer())
+}
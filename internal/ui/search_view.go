@@ -0,0 +1,385 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// commitMatch is a commit found by a search, along with the line of the
+// commit which matched
+type commitMatch struct {
+	commit  *git.Commit
+	snippet string
+}
+
+// SearchView lists the commits matching the last search
+type SearchView struct {
+	*BaseView
+	*Scrollable
+	config   *config.Config
+	client   git.Client
+	pattern  string
+	matches  []commitMatch
+	selected int
+	repoPath string
+	frame    *Frame
+}
+
+// NewSearchView creates a new search results view
+func NewSearchView(config *config.Config, client git.Client) *SearchView {
+	return &SearchView{
+		BaseView:   NewBaseView(ViewTypeSearch),
+		Scrollable: NewScrollable(),
+		config:     config,
+		client:     client,
+		frame:      NewFrame(config, "Search"),
+	}
+}
+
+// searchCommits returns the commits whose message or author contains the
+// pattern, ignoring case
+func searchCommits(commits []*git.Commit, pattern string) []commitMatch {
+	needle := strings.ToLower(pattern)
+	var matches []commitMatch
+
+	for _, commit := range commits {
+		if snippet, ok := matchCommit(commit, needle); ok {
+			matches = append(matches, commitMatch{commit: commit, snippet: snippet})
+		}
+	}
+	return matches
+}
+
+// matchCommit returns the first line of a commit containing the lowercase
+// needle
+func matchCommit(commit *git.Commit, needle string) (string, bool) {
+	message := commit.Message
+	if message == "" {
+		message = strings.TrimSpace(commit.Summary + "\n\n" + commit.Body)
+	}
+	for _, line := range strings.Split(message, "\n") {
+		if strings.Contains(strings.ToLower(line), needle) {
+			return strings.TrimSpace(line), true
+		}
+	}
+	if strings.Contains(strings.ToLower(commit.Author.Name), needle) {
+		return "Author: " + commit.Author.Name, true
+	}
+	for _, coAuthor := range commit.CoAuthors() {
+		if strings.Contains(strings.ToLower(coAuthor.Name), needle) {
+			return "Co-author: " + coAuthor.Name, true
+		}
+	}
+	return "", false
+}
+
+// Render renders the search view
+func (v *SearchView) Render(screen tcell.Screen, x, y, width, height int) error {
+	v.SetPosition(x, y, width, height)
+	v.SetHeight(height - 2) // Account for borders
+
+	// Draw the frame, and its scrollbar once the content is laid out
+	contentX, contentY, contentWidth, contentHeight := v.frame.Draw(screen, x, y, width, height)
+	defer v.frame.DrawScrollbar(screen, v.Scrollable)
+
+	if contentWidth <= 0 || contentHeight <= 0 {
+		return nil
+	}
+
+	v.renderMatches(screen, contentX, contentY, contentWidth, contentHeight)
+
+	return nil
+}
+
+// renderMatches renders the visible matches, one per line
+func (v *SearchView) renderMatches(screen tcell.Screen, x, y, width, height int) {
+	if len(v.matches) == 0 {
+		msg := "No matching commits"
+		if v.pattern == "" {
+			msg = "No search"
+		}
+
+		msgX := x + (width-len(msg))/2
+		msgY := y + height/2
+		if msgX >= x && msgY >= y {
+			for i, char := range msg {
+				screen.SetContent(msgX+i, msgY, char, nil, tcell.StyleDefault)
+			}
+		}
+		return
+	}
+
+	v.SetMaxOffset(len(v.matches) - height)
+
+	start := v.GetOffset()
+	end := start + height
+	if end > len(v.matches) {
+		end = len(v.matches)
+	}
+
+	for i := start; i < end; i++ {
+		v.drawMatch(screen, x, y+(i-start), width, i)
+	}
+}
+
+// drawMatch draws a match as its short hash, subject and the matching line
+// if that is not the subject
+func (v *SearchView) drawMatch(screen tcell.Screen, x, y, width, index int) {
+	match := v.matches[index]
+
+	idStyle := tcell.StyleDefault.Foreground(tcell.ColorBlue)
+	textStyle := tcell.StyleDefault
+	snippetStyle := tcell.StyleDefault.Foreground(tcell.ColorYellow)
+	if index == v.selected {
+		background := tcell.ColorDarkBlue
+		if v.IsFocused() {
+			background = tcell.ColorBlue
+		}
+		idStyle = idStyle.Background(background)
+		textStyle = textStyle.Background(background)
+		snippetStyle = snippetStyle.Background(background)
+	}
+
+	var cells []cell
+	add := func(text string, style tcell.Style) {
+		for _, ch := range text {
+			cells = append(cells, cell{ch, style})
+		}
+	}
+
+	add(v.client.AbbrevHash(match.commit.Hash)+" ", idStyle)
+	add(match.commit.Summary, textStyle)
+	if match.snippet != match.commit.Summary {
+		add("  ", textStyle)
+		add(match.snippet, snippetStyle)
+	}
+
+	drawCells(screen, x, y, width, cells, 0, textStyle)
+}
+
+// HandleKey handles keyboard input
+func (v *SearchView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
+	if !v.IsFocused() {
+		return false
+	}
+
+	switch key {
+	case tcell.KeyUp:
+		v.moveUp()
+		return true
+	case tcell.KeyDown:
+		v.moveDown()
+		return true
+	case tcell.KeyHome:
+		v.ScrollToTop()
+		v.selected = 0
+		return true
+	case tcell.KeyEnd:
+		v.ScrollToBottom()
+		v.selected = max(0, len(v.matches)-1)
+		return true
+	}
+
+	switch ch {
+	case 'j':
+		v.moveDown()
+		return true
+	case 'k':
+		v.moveUp()
+		return true
+	case 'g':
+		v.ScrollToTop()
+		v.selected = 0
+		return true
+	case 'G':
+		v.ScrollToBottom()
+		v.selected = max(0, len(v.matches)-1)
+		return true
+	}
+
+	return false
+}
+
+// moveUp moves selection up
+func (v *SearchView) moveUp() {
+	if v.selected > 0 {
+		v.selected--
+		if v.selected < v.GetOffset() {
+			v.ScrollUp()
+		}
+	}
+}
+
+// moveDown moves selection down
+func (v *SearchView) moveDown() {
+	if v.selected < len(v.matches)-1 {
+		v.selected++
+		visibleEnd := v.GetOffset() + v.getPageSize()
+		if v.selected >= visibleEnd {
+			v.ScrollDown()
+		}
+	}
+}
+
+// getPageSize returns the number of visible lines
+func (v *SearchView) getPageSize() int {
+	_, _, _, height := v.GetPosition()
+	return height - 2 // Account for borders
+}
+
+// Refresh keeps the results, which stay until the search is cleared
+func (v *SearchView) Refresh() error {
+	return nil
+}
+
+// setResults replaces the results with those of a new search
+func (v *SearchView) setResults(pattern string, matches []commitMatch) {
+	v.pattern = pattern
+	v.matches = matches
+	v.selected = 0
+	v.ScrollToTop()
+	v.frame.Title = "Search"
+	if pattern != "" {
+		v.frame.Title = fmt.Sprintf("Search %q: %d commits", pattern, len(matches))
+	}
+}
+
+// GetSelectedMatch returns the commit of the selected result
+func (v *SearchView) GetSelectedMatch() *git.Commit {
+	if v.selected < 0 || v.selected >= len(v.matches) {
+		return nil
+	}
+	return v.matches[v.selected].commit
+}
+
+// Selection returns the selected commit for placeholder expansion
+func (v *SearchView) Selection() Selection {
+	var sel Selection
+	if commit := v.GetSelectedMatch(); commit != nil {
+		sel.Commit = commit.Hash
+	}
+	return sel
+}
+
+// SetRepoPath sets the repository path
+func (v *SearchView) SetRepoPath(path string) {
+	v.repoPath = path
+	v.setResults("", nil)
+}
+
+// SearchCommand handles the :search command. Without a pattern it shows
+// the results of the last search again.
+func (vm *ViewManager) SearchCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	// The help view is searched in place
+	if help, ok := vm.views[vm.currentView].(*HelpView); ok {
+		return vm.searchHelp(help, args)
+	}
+
+	view, ok := vm.views[ViewTypeSearch].(*SearchView)
+	if !ok {
+		return fmt.Errorf("search view not found")
+	}
+	mainView, ok := vm.views[ViewTypeMain].(*MainView)
+	if !ok {
+		return fmt.Errorf("main view not found")
+	}
+
+	if len(args) == 0 {
+		if view.pattern == "" {
+			return fmt.Errorf("usage: search <pattern>")
+		}
+		return vm.switchView(ViewTypeSearch)
+	}
+
+	// The commits not taken yet are searched too
+	if err := mainView.loadAll(); err != nil {
+		return fmt.Errorf("failed to get commits: %w", err)
+	}
+
+	pattern := strings.Join(args, " ")
+	matches := searchCommits(mainView.commits, pattern)
+	view.setResults(pattern, matches)
+	if len(matches) == 0 {
+		vm.setMessage("No commits match %q", pattern)
+		return nil
+	}
+	return vm.switchView(ViewTypeSearch)
+}
+
+// ClearSearchCommand handles the :clear-search command
+func (vm *ViewManager) ClearSearchCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if view, ok := vm.views[ViewTypeSearch].(*SearchView); ok {
+		view.setResults("", nil)
+	}
+	if vm.currentView == ViewTypeSearch {
+		return vm.switchView(ViewTypeMain)
+	}
+	return nil
+}
+
+// jumpToMatch selects the commit of the selected result in the main view
+func (vm *ViewManager) jumpToMatch() error {
+	view, ok := vm.views[ViewTypeSearch].(*SearchView)
+	if !ok {
+		return fmt.Errorf("search view not found")
+	}
+	commit := view.GetSelectedMatch()
+	if commit == nil {
+		return fmt.Errorf("no match selected")
+	}
+
+	mainView, ok := vm.views[ViewTypeMain].(*MainView)
+	if !ok || !mainView.selectCommit(commit.Hash) {
+		return fmt.Errorf("commit %s is no longer listed", vm.client.AbbrevHash(commit.Hash))
+	}
+	return vm.switchView(ViewTypeMain)
+}
+
+// findMatch moves the main view selection to the next, or previous, commit
+// of the search results, wrapping around at the ends of the log
+func (vm *ViewManager) findMatch(direction int) error {
+	view, ok := vm.views[ViewTypeSearch].(*SearchView)
+	if !ok || len(view.matches) == 0 {
+		return fmt.Errorf("no search results, use / to search")
+	}
+	mainView, ok := vm.views[ViewTypeMain].(*MainView)
+	if !ok || len(mainView.commits) == 0 {
+		return fmt.Errorf("no commits")
+	}
+
+	// Pick the match closest to the selection in the given direction, using
+	// the distance after wrapping around
+	count := len(mainView.commits)
+	best, bestDistance := -1, count+1
+	for i, match := range view.matches {
+		position, ok := mainView.commitPosition(match.commit.Hash)
+		if !ok {
+			continue
+		}
+		distance := ((position-mainView.selected)*direction%count + count) % count
+		if distance == 0 {
+			distance = count
+		}
+		if distance < bestDistance {
+			best, bestDistance = i, distance
+		}
+	}
+
+	if best >= 0 {
+		mainView.selectCommit(view.matches[best].commit.Hash)
+		view.selected = best
+		vm.setMessage("Match %d of %d for %q", best+1, len(view.matches), view.pattern)
+		return nil
+	}
+	return fmt.Errorf("no matches for %q in the log", view.pattern)
+}
@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func searchTestCommits() []*git.Commit {
+	return []*git.Commit{
+		{Hash: "1111111111111111111111111111111111111111", Summary: "Fix parser crash", Message: "Fix parser crash", Author: git.Signature{Name: "Alice"}},
+		{Hash: "2222222222222222222222222222222222222222", Summary: "Add docs", Message: "Add docs\n\nMention the parser options", Author: git.Signature{Name: "Bob"}},
+		{Hash: "3333333333333333333333333333333333333333", Summary: "Bump version", Message: "Bump version", Author: git.Signature{Name: "Alice"}},
+		{Hash: "4444444444444444444444444444444444444444", Summary: "Refactor PARSER", Message: "Refactor PARSER", Author: git.Signature{Name: "Carol"}},
+	}
+}
+
+func TestSearchCommits(t *testing.T) {
+	matches := searchCommits(searchTestCommits(), "parser")
+	if assert.Len(t, matches, 3) {
+		assert.Equal(t, "Fix parser crash", matches[0].snippet)
+		assert.Equal(t, "Mention the parser options", matches[1].snippet)
+		assert.Equal(t, "Refactor PARSER", matches[2].snippet)
+	}
+
+	matches = searchCommits(searchTestCommits(), "alice")
+	if assert.Len(t, matches, 2) {
+		assert.Equal(t, "Author: Alice", matches[0].snippet)
+	}
+
+	assert.Empty(t, searchCommits(searchTestCommits(), "nothing"))
+
+	// Co-authors are matched too, though only the summary is loaded
+	paired := &git.Commit{Summary: "Pair", Message: "Pair", Author: git.Signature{Name: "Bob"},
+		Trailers: []git.Trailer{{Key: "Co-authored-by", Value: "Alice <alice@example.com>"}}}
+	matches = searchCommits([]*git.Commit{paired}, "alice")
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, "Co-author: Alice", matches[0].snippet)
+	}
+}
+
+func TestSearchViewRender(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	assert.NoError(t, screen.Init())
+	screen.SetSize(60, 5)
+
+	view := NewSearchView(&config.Config{}, git.NewClient())
+	assert.Equal(t, ViewTypeSearch, view.GetType())
+	view.setResults("parser", searchCommits(searchTestCommits(), "parser"))
+
+	readRow := func(y int) string {
+		var text []rune
+		for x := 1; x < 59; x++ {
+			ch, _, _, _ := screen.GetContent(x, y)
+			text = append(text, ch)
+		}
+		return strings.TrimRight(string(text), " ")
+	}
+
+	// The matching line is shown when it is not the subject
+	assert.NoError(t, view.Render(screen, 0, 0, 60, 5))
+	assert.Equal(t, "1111111 Fix parser crash", readRow(1))
+	assert.Equal(t, "2222222 Add docs  Mention the parser options", readRow(2))
+}
+
+func TestViewManagerSearch(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	assert.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	vm := NewViewManager(screen, cfg, git.NewClient(), NewKeyBindingManager(cfg))
+	vm.SetSize(80, 24)
+
+	mainView := vm.GetView(ViewTypeMain).(*MainView)
+	mainView.commits = searchTestCommits()
+
+	// Searching before any results exist is reported
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'n', 0))
+	assert.Equal(t, "no search results, use / to search", vm.GetMessage())
+	assert.Error(t, vm.SearchCommand(nil))
+
+	assert.True(t, vm.HandleKey(tcell.KeyRune, '/', 0))
+	assert.Equal(t, "search ", vm.TakeCommandRequest())
+
+	assert.NoError(t, vm.SearchCommand([]string{"parser"}))
+	assert.Equal(t, ViewTypeSearch, vm.GetCurrentView())
+
+	// Enter jumps to the selected match in the main view
+	assert.True(t, vm.HandleKey(tcell.KeyDown, 0, 0))
+	assert.True(t, vm.HandleKey(tcell.KeyEnter, 0, 0))
+	assert.Equal(t, ViewTypeMain, vm.GetCurrentView())
+	assert.Equal(t, "2222222222222222222222222222222222222222", mainView.GetSelectedCommit().Hash)
+
+	// n and N cycle through the matches, wrapping around
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'n', 0))
+	assert.Equal(t, "4444444444444444444444444444444444444444", mainView.GetSelectedCommit().Hash)
+	assert.Equal(t, `Match 3 of 3 for "parser"`, vm.GetMessage())
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'n', 0))
+	assert.Equal(t, "1111111111111111111111111111111111111111", mainView.GetSelectedCommit().Hash)
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'N', 0))
+	assert.Equal(t, "4444444444444444444444444444444444444444", mainView.GetSelectedCommit().Hash)
+
+	// The results are kept until cleared
+	assert.NoError(t, vm.SearchCommand(nil))
+	assert.Equal(t, ViewTypeSearch, vm.GetCurrentView())
+	assert.NoError(t, vm.ClearSearchCommand(nil))
+	assert.Equal(t, ViewTypeMain, vm.GetCurrentView())
+	assert.Error(t, vm.SearchCommand(nil))
+
+	assert.NoError(t, vm.SearchCommand([]string{"nothing"}))
+	assert.Equal(t, ViewTypeMain, vm.GetCurrentView())
+	assert.Equal(t, `No commits match "nothing"`, vm.GetMessage())
+}
+
+func TestSearchCommandSearchesWholeLog(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	refsTestGit(t, dir, "branch", "--quiet", "-D", "topic")
+	script := "for i in $(seq 150); do git -c user.name=Test -c user.email=test@example.com commit --quiet --allow-empty -m \"change $i\"; done"
+	cmd := exec.Command("sh", "-c", script)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "%s", output)
+
+	mainView := vm.views[ViewTypeMain].(*MainView)
+	require.NoError(t, mainView.Refresh())
+	require.Len(t, mainView.commits, logPageSize)
+
+	// The commits past the first page are searched and can be jumped to
+	require.NoError(t, vm.SearchCommand([]string{"change 3"}))
+	view := vm.views[ViewTypeSearch].(*SearchView)
+	require.NotEmpty(t, view.matches)
+	assert.Len(t, mainView.commits, 151)
+	assert.True(t, mainView.loader.done)
+	view.selected = len(view.matches) - 1
+	require.NoError(t, vm.jumpToMatch())
+	assert.Equal(t, "change 3", mainView.GetSelectedCommit().Summary)
+}
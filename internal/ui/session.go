@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// SessionEvent is a line of a session recording. Key, paste and resize events
+// are the input which a replay feeds back, frames are the screen contents
+// after each redraw and only serve to show what the user saw.
+type SessionEvent struct {
+	Time   int64    `json:"t"` // Milliseconds since the recording started
+	Type   string   `json:"type"`
+	Key    int16    `json:"key,omitempty"`
+	Rune   string   `json:"rune,omitempty"`
+	Mod    int16    `json:"mod,omitempty"`
+	Width  int      `json:"width,omitempty"`
+	Height int      `json:"height,omitempty"`
+	Start  bool     `json:"start,omitempty"` // Paste starting rather than ending
+	Lines  []string `json:"lines,omitempty"`
+}
+
+// Session event types
+const (
+	SessionKey    = "key"
+	SessionResize = "resize"
+	SessionPaste  = "paste"
+	SessionFrame  = "frame"
+)
+
+// SessionRecorder writes key events and screen frames as JSON lines
+type SessionRecorder struct {
+	mutex sync.Mutex
+	w     io.Writer
+	start time.Time
+	err   error
+}
+
+// NewSessionRecorder creates a recorder writing to w
+func NewSessionRecorder(w io.Writer) *SessionRecorder {
+	return &SessionRecorder{w: w, start: time.Now()}
+}
+
+// Err returns the first error writing the recording
+func (r *SessionRecorder) Err() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.err
+}
+
+// recordEvent records a key, paste or resize event
+func (r *SessionRecorder) recordEvent(ev tcell.Event) {
+	switch ev := ev.(type) {
+	case *tcell.EventKey:
+		event := SessionEvent{Type: SessionKey, Key: int16(ev.Key()), Mod: int16(ev.Modifiers())}
+		if ev.Key() == tcell.KeyRune {
+			event.Rune = string(ev.Rune())
+		}
+		r.write(event)
+	case *tcell.EventResize:
+		width, height := ev.Size()
+		r.write(SessionEvent{Type: SessionResize, Width: width, Height: height})
+	case *tcell.EventPaste:
+		r.write(SessionEvent{Type: SessionPaste, Start: ev.Start()})
+	}
+}
+
+// recordFrame records the screen contents as lines of text
+func (r *SessionRecorder) recordFrame(screen tcell.Screen) {
+	r.write(SessionEvent{Type: SessionFrame, Lines: screenLines(screen)})
+}
+
+// screenLines returns the text on the screen, one string per row with
+// trailing blanks removed
+func screenLines(screen tcell.Screen) []string {
+	width, height := screen.Size()
+	lines := make([]string, height)
+	for y := 0; y < height; y++ {
+		var line strings.Builder
+		for x := 0; x < width; x++ {
+			ch, _, _, w := screen.GetContent(x, y)
+			if ch == 0 {
+				ch = ' '
+			}
+			line.WriteRune(ch)
+			if w == 2 {
+				x++
+			}
+		}
+		lines[y] = strings.TrimRight(line.String(), " ")
+	}
+	return lines
+}
+
+// write appends an event, stamped with the time since the recording started
+func (r *SessionRecorder) write(event SessionEvent) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.err != nil {
+		return
+	}
+
+	event.Time = time.Since(r.start).Milliseconds()
+	data, err := json.Marshal(event)
+	if err != nil {
+		r.err = err
+		return
+	}
+	_, r.err = r.w.Write(append(data, '\n'))
+}
+
+// ReadSession reads the events of a recording
+func ReadSession(rd io.Reader) ([]SessionEvent, error) {
+	var events []SessionEvent
+	scanner := bufio.NewScanner(rd)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineno := 0
+	for scanner.Scan() {
+		lineno++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var event SessionEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineno, err)
+		}
+		switch event.Type {
+		case SessionKey, SessionResize, SessionPaste, SessionFrame:
+		default:
+			return nil, fmt.Errorf("line %d: unknown event type %q", lineno, event.Type)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// tcellEvent converts a recorded input event back into the tcell event, or
+// returns nil for frames
+func (e SessionEvent) tcellEvent() tcell.Event {
+	switch e.Type {
+	case SessionKey:
+		var ch rune
+		if e.Rune != "" {
+			ch = []rune(e.Rune)[0]
+		}
+		return tcell.NewEventKey(tcell.Key(e.Key), ch, tcell.ModMask(e.Mod))
+	case SessionResize:
+		return tcell.NewEventResize(e.Width, e.Height)
+	case SessionPaste:
+		return tcell.NewEventPaste(e.Start)
+	}
+	return nil
+}
+
+// SetRecorder records the session to the given recorder while running
+func (t *Terminal) SetRecorder(recorder *SessionRecorder) {
+	t.recorder = recorder
+}
+
+// secretInput returns whether ev is typed into a prompt masking it, as
+// passwords are, which is kept out of recordings
+func (t *Terminal) secretInput(ev tcell.Event) bool {
+	switch ev.(type) {
+	case *tcell.EventKey, *tcell.EventPaste:
+		return t.viewManager != nil && t.viewManager.SecretInput()
+	}
+	return false
+}
+
+// SetReplay feeds the input events of a recording to the terminal once it
+// runs, keeping their original timing
+func (t *Terminal) SetReplay(events []SessionEvent) {
+	t.replay = events
+}
+
+// replayEvents posts the recorded input events to the event loop
+func (t *Terminal) replayEvents(events []SessionEvent) {
+	start := time.Now()
+	for _, event := range events {
+		ev := event.tcellEvent()
+		if ev == nil {
+			continue
+		}
+		if delay := time.Duration(event.Time)*time.Millisecond - time.Since(start); delay > 0 {
+			time.Sleep(delay)
+		}
+		if !t.running.Load() {
+			return
+		}
+		t.eventCh <- ev
+	}
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionRecording(t *testing.T) {
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	term := newTestTerminal(t, cfg)
+
+	var buf bytes.Buffer
+	recorder := NewSessionRecorder(&buf)
+	term.SetRecorder(recorder)
+
+	require.NoError(t, term.handleEvent(tcell.NewEventKey(tcell.KeyRune, 'h', tcell.ModNone)))
+	require.NoError(t, term.handleEvent(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)))
+	require.NoError(t, recorder.Err())
+
+	events, err := ReadSession(&buf)
+	require.NoError(t, err)
+
+	var keys []SessionEvent
+	var frames int
+	for _, event := range events {
+		switch event.Type {
+		case SessionKey:
+			keys = append(keys, event)
+		case SessionFrame:
+			frames++
+			assert.Len(t, event.Lines, 24)
+		}
+	}
+	if assert.Len(t, keys, 2) {
+		assert.Equal(t, "h", keys[0].Rune)
+		assert.Equal(t, int16(tcell.KeyDown), keys[1].Key)
+	}
+	assert.Equal(t, 2, frames)
+
+	// The help view was on screen when the frame was taken
+	last := events[len(events)-1]
+	assert.Equal(t, SessionFrame, last.Type)
+	assert.Contains(t, last.Lines[0], "Go Tig Help")
+
+	// Replaying the input brings a fresh terminal to the same view
+	replayed := newTestTerminal(t, cfg)
+	for _, event := range events {
+		if ev := event.tcellEvent(); ev != nil {
+			require.NoError(t, replayed.handleEvent(ev))
+		}
+	}
+	assert.Equal(t, ViewTypeHelp, replayed.viewManager.GetCurrentView())
+}
+
+func TestSessionRecordingSecrets(t *testing.T) {
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	term := newTestTerminal(t, cfg)
+
+	var buf bytes.Buffer
+	term.SetRecorder(NewSessionRecorder(&buf))
+
+	// What is typed into a masked prompt is left out
+	var answer string
+	term.viewManager.askInput("Password:", nil, true, func(text string, ok bool) { answer = text })
+	for _, ch := range "hunter2" {
+		require.NoError(t, term.handleEvent(tcell.NewEventKey(tcell.KeyRune, ch, tcell.ModNone)))
+	}
+	require.NoError(t, term.handleEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)))
+	assert.Equal(t, "hunter2", answer)
+	require.NoError(t, term.handleEvent(tcell.NewEventKey(tcell.KeyRune, 'h', tcell.ModNone)))
+	assert.NotContains(t, buf.String(), "hunter2")
+
+	events, err := ReadSession(&buf)
+	require.NoError(t, err)
+	var keys []string
+	for _, event := range events {
+		if event.Type == SessionKey {
+			keys = append(keys, event.Rune)
+		}
+	}
+	assert.Equal(t, []string{"h"}, keys)
+}
+
+func TestReadSessionErrors(t *testing.T) {
+	_, err := ReadSession(strings.NewReader("{\"type\":\"key\",\"key\":256,\"rune\":\"j\"}\nnot json\n"))
+	assert.EqualError(t, err, "line 2: invalid character 'o' in literal null (expecting 'u')")
+
+	_, err = ReadSession(strings.NewReader(`{"type":"mouse"}`))
+	assert.EqualError(t, err, `line 1: unknown event type "mouse"`)
+}
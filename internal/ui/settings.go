@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/azhao1981/tig/internal/config"
+)
+
+// configReload is posted to the event loop when the tigrc file changed
+type configReload struct{}
+
+// setCommand handles the :set command. Options are given as in tigrc, with
+// or without the equal sign; without a value the current value is shown.
+func (t *Terminal) setCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: set <option> [=] [value]")
+	}
+
+	text := strings.Join(args, " ")
+	name, value, ok := strings.Cut(text, "=")
+	if !ok {
+		name, value = args[0], strings.Join(args[1:], " ")
+	}
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+
+	if value == "" && !ok {
+		current, err := t.config.Get(name)
+		if err != nil {
+			return err
+		}
+		t.viewManager.SetMessage("%s = %s", name, current)
+		return nil
+	}
+
+	if name == "theme" && !isScheme(value) {
+		return fmt.Errorf("unknown theme: %s", value)
+	}
+
+	err := t.viewManager.UpdateConfig(func(cfg *config.Config) error {
+		return cfg.Set(name, value)
+	})
+	if err != nil {
+		return err
+	}
+
+	t.applyConfig()
+	t.viewManager.SetMessage("%s = %s", name, value)
+	return nil
+}
+
+// saveConfigCommand handles the :save-config command, which writes the
+// effective configuration to the user's tigrc or the given file
+func (t *Terminal) saveConfigCommand(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: save-config [file]")
+	}
+
+	path := ""
+	if len(args) == 1 {
+		path = args[0]
+	} else {
+		var err error
+		if path, err = config.UserConfigPath(); err != nil {
+			return err
+		}
+	}
+
+	if err := t.config.SaveTo(path); err != nil {
+		return err
+	}
+	t.viewManager.SetMessage("Saved configuration to %s", path)
+	return nil
+}
+
+// reloadConfig rereads the tigrc file after it changed on disk. Settings
+// changed with :set or on the command line are replaced by the file's.
+func (t *Terminal) reloadConfig() {
+	next, err := config.LoadPath(t.config.Path)
+	if err != nil {
+		t.viewManager.SetMessage("Failed to reload config: %v", err)
+		return
+	}
+
+	err = t.viewManager.UpdateConfig(func(cfg *config.Config) error {
+		// Read-only mode asked for on the command line stays on
+		next.General.ReadOnly = next.General.ReadOnly || cfg.General.ReadOnly
+		*cfg = *next
+		return nil
+	})
+	t.applyConfig()
+
+	switch {
+	case err != nil:
+		t.viewManager.SetMessage("%v", err)
+	case len(next.Warnings) > 0:
+		t.viewManager.SetMessage("%s", next.Warnings[0])
+	default:
+		t.viewManager.SetMessage("Reloaded %s", next.Path)
+	}
+}
+
+// applyConfig updates the state derived from the configuration
+func (t *Terminal) applyConfig() {
+	t.theme = newSchemeTheme(t.config)
+	t.keyBindingMgr.Reload()
+	t.refreshInterval.Store(int64(t.config.General.RefreshInterval))
+}
+
+// newSchemeTheme creates the theme of the configured color scheme
+func newSchemeTheme(cfg *config.Config) *Theme {
+	if cfg.Colors.Scheme != "" && cfg.Colors.Scheme != "default" {
+		return LoadScheme(cfg.Colors.Scheme)
+	}
+	return NewTheme(cfg)
+}
+
+// isScheme checks that a name is one of the predefined color schemes
+func isScheme(name string) bool {
+	for _, scheme := range AvailableSchemes() {
+		if scheme == name {
+			return true
+		}
+	}
+	return false
+}
+
+// configModTime returns the modification time of a config file, or the
+// zero time if it cannot be read
+func configModTime(path string) time.Time {
+	if path == "" {
+		return time.Time{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
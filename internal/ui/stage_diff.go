@@ -0,0 +1,218 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azhao1981/tig/internal/git"
+)
+
+// stageFile is a file of the status view shown in the diff view, along
+// with the section of the status it was selected in
+type stageFile struct {
+	path    string
+	section string
+}
+
+// diff returns the changes of the file the section lists: the staged
+// changes, the changes not staged yet, or the whole content of an
+// untracked file
+func (s *stageFile) diff(client git.Client) (*git.Diff, error) {
+	switch s.section {
+	case statusSectionStaged:
+		return client.GetStagedDiff(s.path)
+	case statusSectionUntracked:
+		return client.GetUntrackedDiff(s.path)
+	default:
+		return client.GetDiff(s.path)
+	}
+}
+
+// pagerArgs returns the git arguments producing the raw patch of the file
+func (s *stageFile) pagerArgs() []string {
+	switch s.section {
+	case statusSectionStaged:
+		return []string{"diff", "--cached", "--patch-with-stat", "-M"}
+	case statusSectionUntracked:
+		return []string{"diff", "--no-index", "--patch-with-stat"}
+	default:
+		return []string{"diff", "--patch-with-stat"}
+	}
+}
+
+// pagerPaths returns the paths ending the git arguments of the file
+func (s *stageFile) pagerPaths() []string {
+	if s.section == statusSectionUntracked {
+		return []string{"--", "/dev/null", s.path}
+	}
+	return []string{"--", s.path}
+}
+
+// unstagedSection returns the section listing the changes of a file not
+// staged yet, untracked for files missing from the index
+func unstagedSection(client git.Client, path string) string {
+	if status, err := client.GetStatus(); err == nil {
+		for _, file := range status.Untracked {
+			if file.Path == path {
+				return statusSectionUntracked
+			}
+		}
+	}
+	return statusSectionModified
+}
+
+// SetStageFile shows the changes of a file of the status view, as listed
+// in the given section, instead of a commit or a range
+func (v *DiffView) SetStageFile(path, section string) error {
+	v.folds = make(foldSet)
+	v.commitHash = ""
+	v.revRange = ""
+	v.paths = nil
+	v.stage = &stageFile{path: path, section: section}
+	v.setDiff(nil)
+	v.ScrollToTop()
+	v.hscroll = 0
+	return v.Refresh()
+}
+
+// GetStageFile returns the file of the status view being shown and the
+// section it is listed in, if any
+func (v *DiffView) GetStageFile() (string, string) {
+	if v.stage == nil {
+		return "", ""
+	}
+	return v.stage.path, v.stage.section
+}
+
+// selectedEntry returns the file under the selection along with the
+// section listing it, nil on headers and blank lines
+func (v *StatusView) selectedEntry() (*git.FileStatus, string) {
+	if v.status == nil {
+		return nil, ""
+	}
+	lines, sections := v.buildStatusContent()
+	return v.entryAt(lines, sections, v.selected)
+}
+
+// entryAt returns the file listed on a line of the status content along
+// with the section listing it, nil on headers and blank lines
+func (v *StatusView) entryAt(lines, sections []string, i int) (*git.FileStatus, string) {
+	if i < 0 || i >= len(lines) || !strings.HasPrefix(lines[i], "\t") {
+		return nil, ""
+	}
+
+	section := sections[i]
+	var files []git.FileStatus
+	switch section {
+	case statusSectionStaged:
+		files = v.status.Staged
+	case statusSectionModified:
+		files = v.status.Modified
+	case statusSectionUntracked:
+		files = v.status.Untracked
+	case statusSectionConflict:
+		files = v.status.Conflict
+	}
+
+	// Count the entries of the section up to the line
+	index := -1
+	for j := i; j >= 0 && sections[j] == section; j-- {
+		if strings.HasPrefix(lines[j], "\t") {
+			index++
+		}
+	}
+	if index < 0 || index >= len(files) {
+		return nil, ""
+	}
+	return &files[index], section
+}
+
+// openStageFile opens the file selected in the status view in the diff
+// view
+func (vm *ViewManager) openStageFile() error {
+	statusView, ok := vm.views[ViewTypeStatus].(*StatusView)
+	if !ok {
+		return fmt.Errorf("status view not found")
+	}
+	file, section := statusView.selectedEntry()
+	if file == nil {
+		return fmt.Errorf("no file selected")
+	}
+	return vm.showStageFile(file.Path, section)
+}
+
+// toggleStageFile stages the file shown in the diff view, or unstages it
+// when its staged changes are shown, then shows the changes of the file on
+// the other side of the index. With unstageOnly, files which aren't shown
+// staged are left alone.
+func (vm *ViewManager) toggleStageFile(unstageOnly bool) error {
+	diffView, ok := vm.views[ViewTypeDiff].(*DiffView)
+	if !ok || diffView.stage == nil {
+		return fmt.Errorf("only changes of the worktree can be staged")
+	}
+	path, section := diffView.stage.path, diffView.stage.section
+
+	if section == statusSectionStaged {
+		if err := vm.client.UnstageFile(path); err != nil {
+			return err
+		}
+		// Unstaging a new file leaves it untracked
+		section = unstagedSection(vm.client, path)
+		vm.setMessage("Unstaged %s", path)
+	} else {
+		if unstageOnly {
+			return fmt.Errorf("%s has no staged changes shown", path)
+		}
+		if err := vm.client.StageFile(path); err != nil {
+			return err
+		}
+		section = statusSectionStaged
+		vm.setMessage("Staged %s", path)
+	}
+
+	if statusView, ok := vm.views[ViewTypeStatus].(*StatusView); ok {
+		_ = statusView.refreshChanged()
+	}
+	diffView.stage.section = section
+	return diffView.Refresh()
+}
+
+// showStageSide shows the staged or the unstaged changes of the file shown
+// in the diff view, or of the file selected in the status view. Without a
+// side, the side not shown is.
+func (vm *ViewManager) showStageSide(side string) error {
+	diffView, ok := vm.views[ViewTypeDiff].(*DiffView)
+	if !ok {
+		return fmt.Errorf("diff view not found")
+	}
+
+	var path, section string
+	switch vm.currentView {
+	case ViewTypeStatus:
+		statusView, ok := vm.views[ViewTypeStatus].(*StatusView)
+		if !ok {
+			return fmt.Errorf("status view not found")
+		}
+		file, selected := statusView.selectedEntry()
+		if file == nil {
+			return fmt.Errorf("no file selected")
+		}
+		path, section = file.Path, selected
+	case ViewTypeDiff:
+		if diffView.stage == nil {
+			return fmt.Errorf("only changes of the worktree have staged and unstaged sides")
+		}
+		path, section = diffView.stage.path, diffView.stage.section
+	}
+
+	if side == "" {
+		side = statusSectionStaged
+		if section == statusSectionStaged {
+			side = statusSectionModified
+		}
+	}
+	if side != statusSectionStaged {
+		side = unstagedSection(vm.client, path)
+	}
+	return vm.showStageFile(path, side)
+}
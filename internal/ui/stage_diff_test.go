@@ -0,0 +1,308 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusViewOpensStageFile(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	refsTestGit(t, dir, "init", "--quiet", "--initial-branch=main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("base\n"), 0644))
+	refsTestGit(t, dir, "add", "file.txt")
+	refsTestGit(t, dir, "commit", "--quiet", "-m", "first")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("changed\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.txt"), []byte("one\ntwo\n"), 0644))
+
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+	vm.SetSize(100, 30)
+	vm.SetRepoPath(dir)
+	require.NoError(t, vm.SwitchView(ViewTypeStatus))
+
+	// The status of the repository model is a sample, so list the files
+	// of the worktree by hand
+	statusView := vm.views[ViewTypeStatus].(*StatusView)
+	selectLine := func(entry string) {
+		statusView.status = &git.Status{
+			Branch:    "main",
+			Modified:  []git.FileStatus{{Path: "file.txt", Y: "M", IsModified: true}},
+			Untracked: []git.FileStatus{{Path: "new.txt", Y: "?", IsUntracked: true}},
+		}
+		lines := statusView.buildStatusLines()
+		for i, line := range lines {
+			if line == entry {
+				statusView.selected = i
+				return
+			}
+		}
+		t.Fatalf("no status line %q in %q", entry, lines)
+	}
+	diffLines := func() []string {
+		var lines []string
+		for _, file := range vm.views[ViewTypeDiff].(*DiffView).GetDiff().Files {
+			for _, hunk := range file.Hunks {
+				for _, line := range hunk.Lines {
+					lines = append(lines, line.Content)
+				}
+			}
+		}
+		return lines
+	}
+
+	// Untracked files are shown in full, as if all lines were added
+	selectLine("\tnew.txt")
+	assert.True(t, vm.HandleKey(tcell.KeyEnter, 0, 0))
+	assert.Equal(t, ViewTypeDiff, vm.GetCurrentView())
+	diffView := vm.views[ViewTypeDiff].(*DiffView)
+	path, section := diffView.GetStageFile()
+	assert.Equal(t, "new.txt", path)
+	assert.Equal(t, statusSectionUntracked, section)
+	assert.Equal(t, []string{"one", "two"}, diffLines())
+	args, err := diffView.PagerArgs()
+	require.NoError(t, err)
+	assert.Equal(t, "diff --no-index --patch-with-stat -- /dev/null new.txt", strings.Join(args, " "))
+
+	require.NoError(t, vm.SwitchView(ViewTypeStatus))
+	selectLine("\tmodified: file.txt")
+	assert.True(t, vm.HandleKey(tcell.KeyEnter, 0, 0))
+	_, section = diffView.GetStageFile()
+	assert.Equal(t, statusSectionModified, section)
+	assert.Equal(t, []string{"base", "changed"}, diffLines())
+
+	// Headers have no file to show
+	require.NoError(t, vm.SwitchView(ViewTypeStatus))
+	selectLine("Untracked files:")
+	assert.True(t, vm.HandleKey(tcell.KeyEnter, 0, 0))
+	assert.Equal(t, ViewTypeStatus, vm.GetCurrentView())
+
+	diffView.SetCommitHash("")
+	path, _ = diffView.GetStageFile()
+	assert.Empty(t, path)
+}
+
+func TestDiffViewTogglesStageFile(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	refsTestGit(t, dir, "init", "--quiet", "--initial-branch=main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("base\n"), 0644))
+	refsTestGit(t, dir, "add", "file.txt")
+	refsTestGit(t, dir, "commit", "--quiet", "-m", "first")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("changed\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new\n"), 0644))
+
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+	vm.SetSize(100, 30)
+	vm.SetRepoPath(dir)
+	diffView := vm.views[ViewTypeDiff].(*DiffView)
+	require.NoError(t, vm.SwitchView(ViewTypeDiff))
+
+	section := func() string {
+		_, section := diffView.GetStageFile()
+		return section
+	}
+
+	require.NoError(t, diffView.SetStageFile("file.txt", statusSectionModified))
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'a', 0))
+	assert.Equal(t, statusSectionStaged, section())
+	assert.Equal(t, "M  file.txt", refsTestGit(t, dir, "status", "--porcelain", "file.txt"))
+	assert.Len(t, diffView.GetDiff().Files, 1)
+
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'a', 0))
+	assert.Equal(t, statusSectionModified, section())
+	assert.Equal(t, "M file.txt", refsTestGit(t, dir, "status", "--porcelain", "file.txt"))
+
+	// Only shown staged changes can be unstaged
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'u', 0))
+	assert.Equal(t, statusSectionModified, section())
+	assert.Contains(t, vm.GetMessage(), "no staged changes")
+
+	// Unstaged new files become untracked again
+	require.NoError(t, diffView.SetStageFile("new.txt", statusSectionUntracked))
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'a', 0))
+	assert.Equal(t, statusSectionStaged, section())
+	assert.True(t, diffView.GetDiff().Files[0].IsNew)
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'u', 0))
+	assert.Equal(t, statusSectionUntracked, section())
+	assert.Equal(t, "?? new.txt", refsTestGit(t, dir, "status", "--porcelain", "new.txt"))
+
+	// Diffs of commits have nothing to stage
+	diffView.Clear()
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'a', 0))
+	assert.Contains(t, vm.GetMessage(), "only changes of the worktree")
+}
+
+func TestStageSideSwitching(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	refsTestGit(t, dir, "init", "--quiet", "--initial-branch=main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("base\n"), 0644))
+	refsTestGit(t, dir, "add", "file.txt")
+	refsTestGit(t, dir, "commit", "--quiet", "-m", "first")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("staged\n"), 0644))
+	refsTestGit(t, dir, "add", "file.txt")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("unstaged\n"), 0644))
+
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+	vm.SetSize(100, 30)
+	vm.SetRepoPath(dir)
+	diffView := vm.views[ViewTypeDiff].(*DiffView)
+
+	added := func() string {
+		for _, line := range diffView.GetDiff().Files[0].Hunks[0].Lines {
+			if line.Type == git.DiffLineAddition {
+				return line.Content
+			}
+		}
+		return ""
+	}
+
+	require.NoError(t, vm.SwitchView(ViewTypeStatus))
+	statusView := vm.views[ViewTypeStatus].(*StatusView)
+	statusView.status = &git.Status{
+		Staged:   []git.FileStatus{{Path: "file.txt", X: "M", IsModified: true}},
+		Modified: []git.FileStatus{{Path: "file.txt", Y: "M", IsModified: true}},
+	}
+	lines := statusView.buildStatusLines()
+	for i, line := range lines {
+		if line == "\tmodified: file.txt" {
+			statusView.selected = i
+			break
+		}
+	}
+
+	// From the status view the selected file is opened on either side
+	assert.True(t, vm.HandleKey(tcell.KeyRune, '1', 0))
+	assert.Equal(t, ViewTypeDiff, vm.GetCurrentView())
+	assert.Equal(t, "unstaged", added())
+
+	assert.True(t, vm.HandleKey(tcell.KeyRune, '2', 0))
+	_, section := diffView.GetStageFile()
+	assert.Equal(t, statusSectionStaged, section)
+	assert.Equal(t, "staged", added())
+
+	assert.True(t, vm.HandleKey(tcell.KeyTab, 0, 0))
+	_, section = diffView.GetStageFile()
+	assert.Equal(t, statusSectionModified, section)
+	assert.Equal(t, "unstaged", added())
+
+	assert.True(t, vm.HandleKey(tcell.KeyTab, 0, 0))
+	assert.Equal(t, "staged", added())
+
+	// Diffs of commits have no sides
+	diffView.Clear()
+	assert.True(t, vm.HandleKey(tcell.KeyRune, '1', 0))
+	assert.Contains(t, vm.GetMessage(), "only changes of the worktree")
+}
+
+func TestStageFileFocusMemory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	refsTestGit(t, dir, "init", "--quiet", "--initial-branch=main")
+	var lines []string
+	for i := 1; i <= 100; i++ {
+		lines = append(lines, strings.Repeat("x", i%40+1))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte(strings.Join(lines, "\n")+"\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.txt"), []byte("other\n"), 0644))
+	refsTestGit(t, dir, "add", ".")
+	refsTestGit(t, dir, "commit", "--quiet", "-m", "first")
+	lines[0] = "changed"
+	for i := 60; i < 100; i++ {
+		lines[i] = "changed too"
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte(strings.Join(lines, "\n")+"\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.txt"), []byte("changed\n"), 0644))
+
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+	vm.SetSize(100, 30)
+	vm.SetRepoPath(dir)
+	require.NoError(t, vm.SwitchView(ViewTypeStatus))
+
+	// The status of the repository model is a sample, so list the files
+	// of the worktree by hand
+	statusView := vm.views[ViewTypeStatus].(*StatusView)
+	statusView.status = &git.Status{
+		Branch: "main",
+		Modified: []git.FileStatus{
+			{Path: "file.txt", Y: "M", IsModified: true},
+			{Path: "other.txt", Y: "M", IsModified: true},
+		},
+	}
+	require.True(t, statusView.selectFile("file.txt", statusSectionModified))
+	selected := func() string {
+		file, _ := statusView.selectedEntry()
+		if file == nil {
+			return ""
+		}
+		return file.Path
+	}
+
+	// Enter on the second hunk goes back to the file, wherever the status
+	// selection moved meanwhile
+	assert.True(t, vm.HandleKey(tcell.KeyEnter, 0, 0))
+	diffView := vm.views[ViewTypeDiff].(*DiffView)
+	require.Len(t, diffView.GetDiff().Files[0].Hunks, 2)
+	for i := 0; diffView.hunkIndex() < 1; i++ {
+		require.Less(t, i, 100)
+		require.True(t, vm.HandleKey(tcell.KeyRune, 'j', 0))
+	}
+	statusView.selected = 0
+	assert.True(t, vm.HandleKey(tcell.KeyEnter, 0, 0))
+	assert.Equal(t, ViewTypeStatus, vm.GetCurrentView())
+	assert.Equal(t, "file.txt", selected())
+
+	// Other files open at the top, and the file again at the second hunk
+	require.True(t, statusView.selectFile("other.txt", statusSectionModified))
+	assert.True(t, vm.HandleKey(tcell.KeyEnter, 0, 0))
+	assert.Equal(t, 0, diffView.GetOffset())
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 's', 0))
+	assert.Equal(t, "other.txt", selected())
+	require.True(t, statusView.selectFile("file.txt", statusSectionModified))
+	assert.True(t, vm.HandleKey(tcell.KeyEnter, 0, 0))
+	assert.Equal(t, 1, diffView.hunkIndex())
+
+	// The status selection is only restored once
+	require.NoError(t, vm.SwitchView(ViewTypeStatus))
+	statusView.selected = 0
+	require.NoError(t, vm.SwitchView(ViewTypeMain))
+	require.NoError(t, vm.SwitchView(ViewTypeStatus))
+	assert.Equal(t, 0, statusView.selected)
+}
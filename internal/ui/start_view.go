@@ -0,0 +1,413 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// startAction is what choosing an item of the start view does
+type startAction int
+
+const (
+	startNone startAction = iota
+	startInit
+	startClone
+	startBrowse
+	startOpen
+)
+
+// startRow is a line of the start view, either text or an item to choose
+type startRow struct {
+	text   string
+	action startAction
+	path   string // Repository to open or directory to browse
+}
+
+// StartView is shown outside of repositories and offers to create, clone
+// or open one
+type StartView struct {
+	*BaseView
+	*Scrollable
+	config   *config.Config
+	client   git.Client
+	recent   *config.RecentRepos
+	rows     []startRow
+	selected int
+	repoPath string
+	browsing string // Directory being browsed, empty on the menu
+	frame    *Frame
+}
+
+// NewStartView creates a new start view
+func NewStartView(config *config.Config, client git.Client) *StartView {
+	v := &StartView{
+		BaseView:   NewBaseView(ViewTypeStart),
+		Scrollable: NewScrollable(),
+		config:     config,
+		client:     client,
+		frame:      NewFrame(config, "Welcome"),
+	}
+	v.buildRows()
+	return v
+}
+
+// buildRows lays out either the menu or the directory being browsed
+func (v *StartView) buildRows() {
+	if v.browsing != "" {
+		v.rows = browseRows(v.browsing)
+	} else {
+		v.rows = v.menuRows()
+	}
+	v.selected = 0
+	v.ScrollToTop()
+	v.selectNext(0, 1)
+}
+
+// menuRows lists the ways to get to a repository
+func (v *StartView) menuRows() []startRow {
+	dir := v.repoPath
+	if dir == "" {
+		dir = "."
+	}
+	rows := []startRow{
+		{text: "Not in a git repository: " + dir},
+		{},
+		{text: "  Initialize a new repository here", action: startInit, path: dir},
+		{text: "  Clone a repository", action: startClone},
+		{text: "  Browse for a repository", action: startBrowse, path: dir},
+	}
+
+	if v.recent != nil && len(v.recent.Repos) > 0 {
+		rows = append(rows, startRow{}, startRow{text: "Recent repositories"})
+		for _, repo := range v.recent.Repos {
+			if isRepoDir(repo) {
+				rows = append(rows, startRow{text: "  " + repo, action: startOpen, path: repo})
+			}
+		}
+	}
+	return rows
+}
+
+// browseRows lists the subdirectories of dir, marking the repositories
+// among them
+func browseRows(dir string) []startRow {
+	rows := []startRow{{text: "Browse " + dir}, {}}
+	if isRepoDir(dir) {
+		rows = append(rows, startRow{text: "  . (open this repository)", action: startOpen, path: dir})
+	}
+	if parent := filepath.Dir(dir); parent != dir {
+		rows = append(rows, startRow{text: "  ../", action: startBrowse, path: parent})
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return append(rows, startRow{text: "  " + err.Error()})
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if isRepoDir(path) {
+			rows = append(rows, startRow{text: "  " + name + "/ (repository)", action: startOpen, path: path})
+		} else {
+			rows = append(rows, startRow{text: "  " + name + "/", action: startBrowse, path: path})
+		}
+	}
+	return rows
+}
+
+// isRepoDir returns whether dir is the top of a worktree
+func isRepoDir(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// Render renders the start view
+func (v *StartView) Render(screen tcell.Screen, x, y, width, height int) error {
+	v.SetPosition(x, y, width, height)
+	v.SetHeight(height - 2) // Account for borders
+
+	// Draw the frame, and its scrollbar once the content is laid out
+	contentX, contentY, contentWidth, contentHeight := v.frame.Draw(screen, x, y, width, height)
+	defer v.frame.DrawScrollbar(screen, v.Scrollable)
+
+	if contentWidth <= 0 || contentHeight <= 0 {
+		return nil
+	}
+
+	v.SetMaxOffset(len(v.rows) - contentHeight)
+
+	start := v.GetOffset()
+	end := start + contentHeight
+	if end > len(v.rows) {
+		end = len(v.rows)
+	}
+
+	for i := start; i < end; i++ {
+		v.drawRow(screen, contentX, contentY+(i-start), contentWidth, i)
+	}
+
+	return nil
+}
+
+// drawRow draws a text or item row
+func (v *StartView) drawRow(screen tcell.Screen, x, y, width, index int) {
+	row := v.rows[index]
+
+	style := tcell.StyleDefault
+	if row.action == startNone {
+		style = style.Bold(true)
+	}
+	if index == v.selected && row.action != startNone {
+		if v.IsFocused() {
+			style = style.Background(tcell.ColorBlue).Foreground(tcell.ColorWhite)
+		} else {
+			style = style.Background(tcell.ColorDarkBlue).Foreground(tcell.ColorWhite)
+		}
+	}
+
+	cells := textCells(row.text, style)
+	if index == v.selected {
+		for cellsWidth(cells) < width {
+			cells = append(cells, cell{' ', style})
+		}
+	}
+	drawCells(screen, x, y, width, cells, 0, style)
+}
+
+// HandleKey handles keyboard input
+func (v *StartView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
+	if !v.IsFocused() {
+		return false
+	}
+
+	switch key {
+	case tcell.KeyUp:
+		v.selectNext(v.selected-1, -1)
+		return true
+	case tcell.KeyDown:
+		v.selectNext(v.selected+1, 1)
+		return true
+	case tcell.KeyHome:
+		v.selectNext(0, 1)
+		return true
+	case tcell.KeyEnd:
+		v.selectNext(len(v.rows)-1, -1)
+		return true
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		// Go back to the menu
+		if v.browsing != "" {
+			v.browse("")
+			return true
+		}
+	}
+
+	switch ch {
+	case 'j':
+		v.selectNext(v.selected+1, 1)
+		return true
+	case 'k':
+		v.selectNext(v.selected-1, -1)
+		return true
+	case 'g':
+		v.selectNext(0, 1)
+		return true
+	case 'G':
+		v.selectNext(len(v.rows)-1, -1)
+		return true
+	}
+
+	return false
+}
+
+// selectNext selects the first item from index on in the given direction,
+// keeping the selection if there is none
+func (v *StartView) selectNext(index, direction int) {
+	for i := index; i >= 0 && i < len(v.rows); i += direction {
+		if v.rows[i].action == startNone {
+			continue
+		}
+		v.selected = i
+
+		pageSize := v.getPageSize()
+		if v.selected < v.GetOffset() {
+			v.SetOffset(v.selected)
+		} else if pageSize > 0 && v.selected >= v.GetOffset()+pageSize {
+			v.SetOffset(v.selected - pageSize + 1)
+		}
+		return
+	}
+}
+
+// getPageSize returns the number of visible lines
+func (v *StartView) getPageSize() int {
+	_, _, _, height := v.GetPosition()
+	return height - 2 // Account for borders
+}
+
+// browse shows the subdirectories of dir, or the menu again when dir is
+// empty
+func (v *StartView) browse(dir string) {
+	v.browsing = dir
+	v.buildRows()
+}
+
+// GetSelectedRow returns the selected item
+func (v *StartView) GetSelectedRow() *startRow {
+	if v.selected < 0 || v.selected >= len(v.rows) || v.rows[v.selected].action == startNone {
+		return nil
+	}
+	return &v.rows[v.selected]
+}
+
+// Refresh lists the recent repositories again, which may have been
+// removed meanwhile
+func (v *StartView) Refresh() error {
+	if v.browsing == "" {
+		v.buildRows()
+	}
+	return nil
+}
+
+// SetRecentRepos sets the recently opened repositories offered on the menu
+func (v *StartView) SetRecentRepos(recent *config.RecentRepos) {
+	v.recent = recent
+	v.buildRows()
+}
+
+// SetRepoPath sets the repository path
+func (v *StartView) SetRepoPath(path string) {
+	v.repoPath = path
+	v.browsing = ""
+	v.buildRows()
+}
+
+// SetRecentRepos sets the recently opened repositories, which are offered
+// when starting outside of a repository and extended as others are opened
+func (vm *ViewManager) SetRecentRepos(recent *config.RecentRepos) {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	vm.recent = recent
+	if view, ok := vm.views[ViewTypeStart].(*StartView); ok {
+		view.SetRecentRepos(recent)
+	}
+}
+
+// chooseStartItem does what the selected item of the start view offers
+func (vm *ViewManager) chooseStartItem() error {
+	view, ok := vm.views[ViewTypeStart].(*StartView)
+	if !ok {
+		return fmt.Errorf("start view not found")
+	}
+	row := view.GetSelectedRow()
+	if row == nil {
+		return nil
+	}
+
+	switch row.action {
+	case startInit:
+		return vm.initRepository(row.path, "")
+	case startClone:
+		vm.commandRequest = "clone "
+	case startBrowse:
+		view.browse(row.path)
+	case startOpen:
+		if err := vm.client.Open(row.path); err != nil {
+			return err
+		}
+		vm.repositoryOpened()
+	}
+	return nil
+}
+
+// InitCommand handles the :init command, which creates a repository in the
+// directory tig was started in, optionally naming its initial branch
+func (vm *ViewManager) InitCommand(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: init [branch]")
+	}
+
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if vm.client.IsRepository() {
+		return fmt.Errorf("already in the repository %s", vm.client.GetRootPath())
+	}
+	branch := ""
+	if len(args) == 1 {
+		branch = args[0]
+	}
+	return vm.initRepository(vm.repoPath, branch)
+}
+
+// initRepository creates a repository at path and opens it
+func (vm *ViewManager) initRepository(path, branch string) error {
+	if err := vm.client.Init(path, branch); err != nil {
+		return err
+	}
+	vm.repositoryOpened()
+
+	// The branch is unborn, but the status knows its name
+	if status, err := vm.client.GetBranchStatus(); err == nil && status.Branch != "" {
+		vm.setMessage("Initialized empty repository in %s on branch %s", vm.client.GetRootPath(), status.Branch)
+	} else {
+		vm.setMessage("Initialized empty repository in %s", vm.client.GetRootPath())
+	}
+	return nil
+}
+
+// CloneCommand handles the :clone command, which clones a repository and
+// opens the clone
+func (vm *ViewManager) CloneCommand(args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("usage: clone <url> [directory]")
+	}
+
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	// Like git clone, relative directories are taken from the directory
+	// tig was started in
+	dir := ""
+	if len(args) == 2 {
+		dir = args[1]
+	}
+
+	path, err := vm.client.Clone(args[0], dir)
+	if err != nil {
+		return err
+	}
+	vm.repositoryOpened()
+	vm.setMessage("Cloned %s into %s", args[0], path)
+	return nil
+}
+
+// repositoryOpened shows the repository the client just opened and
+// remembers it as recently opened
+func (vm *ViewManager) repositoryOpened() {
+	root := vm.client.GetRootPath()
+	vm.setRepoPath(root)
+	_ = vm.switchView(ViewTypeMain)
+
+	if vm.recent != nil {
+		if err := vm.recent.Add(root); err != nil {
+			vm.setMessage("%v", err)
+			return
+		}
+	}
+	vm.setMessage("Opened %s", root)
+}
@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartViewBrowse(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "repo", ".git"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "plain"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".hidden"), 0755))
+
+	recent, err := config.LoadRecentRepos(filepath.Join(dir, "recent"))
+	require.NoError(t, err)
+	recent.Repos = []string{filepath.Join(dir, "repo"), filepath.Join(dir, "gone")}
+
+	view := NewStartView(&config.Config{}, git.NewClient())
+	view.Focus()
+	view.SetRepoPath(dir)
+	view.SetRecentRepos(recent)
+
+	// Repositories which no longer exist aren't offered
+	var texts []string
+	for _, row := range view.rows {
+		texts = append(texts, row.text)
+	}
+	assert.Equal(t, []string{
+		"Not in a git repository: " + dir,
+		"",
+		"  Initialize a new repository here",
+		"  Clone a repository",
+		"  Browse for a repository",
+		"",
+		"Recent repositories",
+		"  " + filepath.Join(dir, "repo"),
+	}, texts)
+	assert.Equal(t, startInit, view.GetSelectedRow().action)
+
+	view.browse(dir)
+	texts = nil
+	for _, row := range view.rows {
+		texts = append(texts, row.text)
+	}
+	assert.Equal(t, []string{"Browse " + dir, "", "  ../", "  plain/", "  repo/ (repository)"}, texts)
+
+	view.HandleKey(tcell.KeyRune, 'G', 0)
+	assert.Equal(t, startRow{text: "  repo/ (repository)", action: startOpen, path: filepath.Join(dir, "repo")}, *view.GetSelectedRow())
+
+	view.HandleKey(tcell.KeyBackspace2, 0, 0)
+	assert.Equal(t, "", view.browsing)
+}
+
+func TestViewManagerStartInit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	recent, err := config.LoadRecentRepos(filepath.Join(dir, "state", "recent"))
+	require.NoError(t, err)
+
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	client := git.NewClient()
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+	vm.SetSize(80, 24)
+	vm.SetRecentRepos(recent)
+	vm.SetRepoPath(dir)
+	assert.Equal(t, ViewTypeStart, vm.GetCurrentView())
+
+	// The first item initializes a repository where tig was started
+	assert.True(t, vm.HandleKey(tcell.KeyEnter, 0, 0))
+	assert.True(t, client.IsRepository())
+	assert.DirExists(t, filepath.Join(dir, ".git"))
+	assert.Equal(t, ViewTypeMain, vm.GetCurrentView())
+	assert.Equal(t, []string{client.GetRootPath()}, recent.Repos)
+	assert.Contains(t, vm.GetMessage(), "Initialized empty repository in "+client.GetRootPath())
+
+	// Cloning the new repository opens the clone
+	output, err := exec.Command("git", "-C", dir, "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "--quiet", "--allow-empty", "-m", "initial").CombinedOutput()
+	require.NoError(t, err, "git commit: %s", output)
+	clone := filepath.Join(t.TempDir(), "clone")
+	assert.Error(t, vm.CloneCommand(nil))
+	require.NoError(t, vm.CloneCommand([]string{dir, clone}))
+	assert.Equal(t, clone, client.GetRootPath())
+	assert.Equal(t, []string{clone, dir}, recent.Repos)
+}
+
+func TestViewManagerInitCommand(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	client := git.NewClient()
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+	vm.SetSize(80, 24)
+	vm.SetRepoPath(dir)
+
+	assert.Error(t, vm.InitCommand([]string{"a", "b"}))
+	require.NoError(t, vm.InitCommand([]string{"trunk"}))
+	assert.Equal(t, "Initialized empty repository in "+dir+" on branch trunk", vm.GetMessage())
+	assert.Equal(t, ViewTypeMain, vm.GetCurrentView())
+	assert.Equal(t, filepath.Base(dir)+" trunk", vm.GetTitle())
+
+	assert.Error(t, vm.InitCommand(nil))
+}
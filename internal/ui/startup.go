@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/azhao1981/tig/internal/config"
+)
+
+// startupViews are the views startup-view can name
+var startupViews = map[string]ViewType{
+	"main":   ViewTypeMain,
+	"status": ViewTypeStatus,
+	"refs":   ViewTypeRefs,
+	"stash":  ViewTypeStash,
+	"review": ViewTypeReview,
+}
+
+// applyStartup sets the views up as the configuration asks tig to start:
+// the log limited by main-filter, the sections of status-folded folded, and
+// startup-view shown first
+func (vm *ViewManager) applyStartup() error {
+	if mainView, ok := vm.views[ViewTypeMain].(*MainView); ok {
+		name, view, err := vm.config.MainFilter()
+		if err != nil {
+			return err
+		}
+		if !view.IsEmpty() {
+			mainView.SetFilter(name, logFilter(view))
+		}
+	}
+
+	if statusView, ok := vm.views[ViewTypeStatus].(*StatusView); ok {
+		for _, section := range config.ListValues(vm.config.Views.Status.Folded) {
+			statusView.folds[section] = true
+		}
+	}
+
+	if viewType, ok := startupViews[vm.config.General.StartupView]; ok {
+		return vm.switchView(viewType)
+	}
+	return nil
+}
+
+// FocusCommand handles the :focus command, which another tig started for
+// the repository sends instead of starting, showing the view it was asked
+// for and drawing attention with the notify options
+func (vm *ViewManager) FocusCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if len(args) > 1 {
+		return fmt.Errorf("usage: focus [main|status|refs|stash|review]")
+	}
+	if len(args) == 1 {
+		viewType, ok := startupViews[args[0]]
+		if !ok {
+			return fmt.Errorf("unknown view: %s", args[0])
+		}
+		if err := vm.switchView(viewType); err != nil {
+			return err
+		}
+	}
+	vm.setMessage("Another tig was started for this repository")
+	vm.alert(vm.message)
+	return nil
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartupConfig(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	mainView := vm.views[ViewTypeMain].(*MainView)
+	statusView := vm.views[ViewTypeStatus].(*StatusView)
+	require.NoError(t, vm.RefreshAll())
+	assert.Equal(t, ViewTypeMain, vm.GetCurrentView())
+	assert.Len(t, mainView.commits, 2)
+
+	require.NoError(t, vm.config.Set("startup-view", "status"))
+	require.NoError(t, vm.config.Set("main-filter", "branch=HEAD"))
+	require.NoError(t, vm.config.Set("status-folded", "untracked"))
+	vm.SetRepoPath(dir)
+	require.NoError(t, vm.RefreshAll())
+	assert.Equal(t, ViewTypeStatus, vm.GetCurrentView())
+
+	// The log of main leaves the commit of topic out
+	assert.Len(t, mainView.commits, 1)
+	assert.Equal(t, "Log: branch=HEAD", mainView.frame.Title)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new\n"), 0644))
+	statusView.cache.Invalidate()
+	require.NoError(t, statusView.Refresh())
+	assert.Contains(t, strings.Join(statusView.buildStatusLines(), "\n"), "Untracked files: [folded, 1 files]")
+
+	// A saved view which doesn't exist leaves the log as it was
+	require.NoError(t, vm.config.Set("main-filter", "theirs"))
+	vm.SetRepoPath(dir)
+	assert.Equal(t, "main-filter: no view named theirs", vm.GetMessage())
+}
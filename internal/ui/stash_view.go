@@ -0,0 +1,387 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// stashKeys are the keys of the stash view acting on stashes
+var stashKeys = map[rune]string{
+	'a': "stash-apply",
+	'p': "stash-pop",
+	'd': "stash-drop",
+	'c': "stash-push",
+	'u': "stash-untracked",
+}
+
+// StashView lists the stashes, which can be applied, popped, dropped or
+// opened in the diff view, and stashes the local changes
+type StashView struct {
+	*BaseView
+	*Scrollable
+	config   *config.Config
+	client   git.Client
+	stashes  []*git.Stash
+	err      error
+	selected int
+	repoPath string
+	frame    *Frame
+
+	run func(action string) // Runs the action of a key on the selected stash
+}
+
+// NewStashView creates a new stash view
+func NewStashView(config *config.Config, client git.Client) *StashView {
+	return &StashView{
+		BaseView:   NewBaseView(ViewTypeStash),
+		Scrollable: NewScrollable(),
+		config:     config,
+		client:     client,
+		frame:      NewFrame(config, "Stashes"),
+	}
+}
+
+// Render renders the stash view
+func (v *StashView) Render(screen tcell.Screen, x, y, width, height int) error {
+	v.SetPosition(x, y, width, height)
+	v.SetHeight(height - 2) // Account for borders
+
+	v.frame.Title = "Stashes"
+	if len(v.stashes) > 0 {
+		v.frame.Title = fmt.Sprintf("Stashes: %d", len(v.stashes))
+	}
+	contentX, contentY, contentWidth, contentHeight := v.frame.Draw(screen, x, y, width, height)
+	defer v.frame.DrawScrollbar(screen, v.Scrollable)
+
+	if contentWidth <= 0 || contentHeight <= 0 {
+		return nil
+	}
+
+	if v.err != nil || len(v.stashes) == 0 {
+		msg := "No stashes, press c to stash the local changes or u to include untracked files"
+		if v.err != nil {
+			msg = v.err.Error()
+		}
+		msgX := contentX + (contentWidth-len(msg))/2
+		msgY := contentY + contentHeight/2
+		drawCells(screen, max(contentX, msgX), msgY, contentWidth, textCells(msg, tcell.StyleDefault.Dim(true)), 0, tcell.StyleDefault)
+		return nil
+	}
+
+	v.SetMaxOffset(len(v.stashes) - contentHeight)
+
+	start := v.GetOffset()
+	end := min(start+contentHeight, len(v.stashes))
+	now := time.Now()
+	for i := start; i < end; i++ {
+		v.drawStash(screen, contentX, contentY+(i-start), contentWidth, i, now)
+	}
+
+	return nil
+}
+
+// drawStash draws a stash as its name, date, branch and message
+func (v *StashView) drawStash(screen tcell.Screen, x, y, width, index int, now time.Time) {
+	stash := v.stashes[index]
+
+	nameStyle := tcell.StyleDefault.Foreground(tcell.ColorTeal)
+	dateStyle := tcell.StyleDefault.Foreground(tcell.ColorBlue)
+	branchStyle := tcell.StyleDefault.Foreground(tcell.ColorGreen)
+	textStyle := tcell.StyleDefault
+	if index == v.selected {
+		background := tcell.ColorDarkBlue
+		if v.IsFocused() {
+			background = tcell.ColorBlue
+		}
+		nameStyle = nameStyle.Background(background)
+		dateStyle = dateStyle.Background(background)
+		branchStyle = branchStyle.Background(background)
+		textStyle = textStyle.Background(background)
+	}
+
+	cells := textCells(fmt.Sprintf("%-11s ", stashName(stash)), nameStyle)
+	cells = append(cells, textCells(formatDate(v.config, stash.Commit.Author.Time, now)+" ", dateStyle)...)
+	if stash.Branch != "" {
+		cells = append(cells, textCells(stash.Branch+" ", branchStyle)...)
+	}
+	cells = append(cells, textCells(stash.Message, textStyle)...)
+	if index == v.selected {
+		for cellsWidth(cells) < width {
+			cells = append(cells, cell{' ', textStyle})
+		}
+	}
+
+	drawCells(screen, x, y, width, cells, 0, textStyle)
+}
+
+// stashName returns the stash@{n} name of a stash
+func stashName(stash *git.Stash) string {
+	return fmt.Sprintf("stash@{%d}", stash.Index)
+}
+
+// HandleKey handles keyboard input
+func (v *StashView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
+	if !v.IsFocused() {
+		return false
+	}
+
+	switch key {
+	case tcell.KeyUp:
+		v.moveUp()
+		return true
+	case tcell.KeyDown:
+		v.moveDown()
+		return true
+	case tcell.KeyHome:
+		v.ScrollToTop()
+		v.selected = 0
+		return true
+	case tcell.KeyEnd:
+		v.ScrollToBottom()
+		v.selected = max(0, len(v.stashes)-1)
+		return true
+	}
+
+	if action, ok := stashKeys[ch]; ok && key == tcell.KeyRune {
+		if v.run != nil {
+			v.run(action)
+		}
+		return true
+	}
+
+	switch ch {
+	case 'j':
+		v.moveDown()
+		return true
+	case 'k':
+		v.moveUp()
+		return true
+	case 'g':
+		v.ScrollToTop()
+		v.selected = 0
+		return true
+	case 'G':
+		v.ScrollToBottom()
+		v.selected = max(0, len(v.stashes)-1)
+		return true
+	}
+
+	return false
+}
+
+// moveUp moves selection up
+func (v *StashView) moveUp() {
+	if v.selected > 0 {
+		v.selected--
+		if v.selected < v.GetOffset() {
+			v.ScrollUp()
+		}
+	}
+}
+
+// moveDown moves selection down
+func (v *StashView) moveDown() {
+	if v.selected < len(v.stashes)-1 {
+		v.selected++
+		visibleEnd := v.GetOffset() + v.getPageSize()
+		if v.selected >= visibleEnd {
+			v.ScrollDown()
+		}
+	}
+}
+
+// getPageSize returns the number of visible lines
+func (v *StashView) getPageSize() int {
+	_, _, _, height := v.GetPosition()
+	return height - 2 // Account for borders
+}
+
+// Refresh lists the stashes again, keeping the selected one selected
+func (v *StashView) Refresh() error {
+	if !v.client.IsRepository() {
+		v.stashes, v.err = nil, nil
+		v.selected = 0
+		return nil
+	}
+
+	var selected string
+	if stash := v.GetSelectedStash(); stash != nil {
+		selected = stash.Commit.Hash
+	}
+	v.stashes, v.err = v.client.GetStashes()
+	v.selected = findSelection(len(v.stashes), v.selected, func(i int) bool {
+		return v.stashes[i].Commit.Hash == selected
+	})
+	return nil
+}
+
+// GetSelectedStash returns the selected stash
+func (v *StashView) GetSelectedStash() *git.Stash {
+	if v.selected < 0 || v.selected >= len(v.stashes) {
+		return nil
+	}
+	return v.stashes[v.selected]
+}
+
+// DiffRange returns the changes of the worktree the selected stash keeps,
+// against the commit it was made on
+func (v *StashView) DiffRange() (string, []string, error) {
+	stash := v.GetSelectedStash()
+	if stash == nil {
+		return "", nil, fmt.Errorf("no stash selected")
+	}
+	return stash.Commit.Hash + "^.." + stash.Commit.Hash, nil, nil
+}
+
+// Selection returns the selected stash for placeholder expansion
+func (v *StashView) Selection() Selection {
+	var sel Selection
+	if stash := v.GetSelectedStash(); stash != nil {
+		sel.Stash = stashName(stash)
+		sel.Commit = stash.Commit.Hash
+	}
+	return sel
+}
+
+// HintContext gives hints for stashes
+func (v *StashView) HintContext() string {
+	if v.GetSelectedStash() == nil {
+		return ""
+	}
+	return hintContextStash
+}
+
+// PagerArgs returns the git arguments listing the stashes
+func (v *StashView) PagerArgs() ([]string, error) {
+	return []string{"stash", "list"}, nil
+}
+
+// SetRepoPath sets the repository path
+func (v *StashView) SetRepoPath(path string) {
+	v.repoPath = path
+	v.stashes, v.err = nil, nil
+	v.selected = 0
+	v.ScrollToTop()
+}
+
+// runStashAction runs the action of a key of the stash view on the
+// selected stash, dropping it once confirmed. Stashing the local changes
+// prompts for the message with :stash push.
+func (vm *ViewManager) runStashAction(action string) {
+	view, ok := vm.views[ViewTypeStash].(*StashView)
+	if !ok {
+		return
+	}
+
+	switch action {
+	case "stash-push":
+		vm.commandRequest = "stash push "
+		return
+	case "stash-untracked":
+		vm.commandRequest = "stash push --include-untracked "
+		return
+	}
+
+	stash := view.GetSelectedStash()
+	if stash == nil {
+		vm.setMessage("No stash selected")
+		return
+	}
+	if err := vm.actOnStash(action, stash); err != nil {
+		vm.setMessage("%v", err)
+	}
+}
+
+// actOnStash applies, pops or drops a stash, asking first to drop it
+func (vm *ViewManager) actOnStash(action string, stash *git.Stash) error {
+	name, hash := stashName(stash), stash.Commit.Hash
+	switch action {
+	case "stash-apply":
+		if err := vm.client.ApplyStash(hash); err != nil {
+			return err
+		}
+		vm.setMessage("Applied %s", name)
+	case "stash-pop":
+		if err := vm.client.PopStash(hash); err != nil {
+			return err
+		}
+		vm.setMessage("Popped %s", name)
+	case "stash-drop":
+		vm.askConfirmation(fmt.Sprintf("Drop %s?", name), []string{
+			stash.Message,
+			fmt.Sprintf("Its commit %s is only found by git fsck once dropped", vm.client.AbbrevHash(hash)),
+		}, func() error {
+			if err := vm.client.DropStash(hash); err != nil {
+				return err
+			}
+			vm.setMessage("Dropped %s", name)
+			return vm.refreshAll()
+		})
+		return nil
+	default:
+		return fmt.Errorf("unknown stash action: %s", action)
+	}
+	return vm.refreshAll()
+}
+
+// StashCommand handles the :stash command, which shows the stash view,
+// stashes the local changes with push, and applies, pops or drops the
+// named stash, the selected one by default
+func (vm *ViewManager) StashCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	view, ok := vm.views[ViewTypeStash].(*StashView)
+	if !ok {
+		return fmt.Errorf("stash view not found")
+	}
+	if len(args) == 0 {
+		if err := view.Refresh(); err != nil {
+			return err
+		}
+		return vm.switchView(ViewTypeStash)
+	}
+	if vm.config.General.ReadOnly {
+		return fmt.Errorf("stash %s is disabled in read-only mode", args[0])
+	}
+
+	switch args[0] {
+	case "push":
+		untracked := len(args) > 1 && (args[1] == "--include-untracked" || args[1] == "-u")
+		if untracked {
+			args = args[1:]
+		}
+		if _, err := vm.client.PushStash(strings.Join(args[1:], " "), untracked); err != nil {
+			return err
+		}
+		vm.setMessage("Stashed the local changes as stash@{0}")
+		if err := vm.refreshAll(); err != nil {
+			return err
+		}
+		return vm.switchView(ViewTypeStash)
+	case "apply", "pop", "drop":
+		if err := view.Refresh(); err != nil {
+			return err
+		}
+		stash := view.GetSelectedStash()
+		if len(args) > 1 {
+			stash = nil
+			for _, s := range view.stashes {
+				if stashName(s) == args[1] || strings.HasPrefix(s.Commit.Hash, args[1]) {
+					stash = s
+					break
+				}
+			}
+		}
+		if stash == nil {
+			return fmt.Errorf("no such stash")
+		}
+		return vm.actOnStash("stash-"+args[0], stash)
+	}
+	return fmt.Errorf("usage: stash [push [--include-untracked] [message]|apply|pop|drop [stash@{n}]]")
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStashView(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	view := vm.views[ViewTypeStash].(*StashView)
+
+	require.NoError(t, vm.StashCommand(nil))
+	assert.Equal(t, ViewTypeStash, vm.GetCurrentView())
+	require.NoError(t, vm.Render())
+	assert.Contains(t, strings.Join(screenLines(vm.screen), "\n"), "No stashes")
+
+	// c and u prompt for the message of the stash
+	vm.HandleKey(tcell.KeyRune, 'u', 0)
+	assert.Equal(t, "stash push --include-untracked ", vm.commandRequest)
+	vm.commandRequest = ""
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("first\n"), 0644))
+	require.NoError(t, vm.StashCommand([]string{"push", "first", "change"}))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new\n"), 0644))
+	require.NoError(t, vm.StashCommand([]string{"push", "-u"}))
+	_, err := os.Stat(filepath.Join(dir, "new.txt"))
+	assert.True(t, os.IsNotExist(err))
+	require.Len(t, view.stashes, 2)
+	require.NoError(t, vm.Render())
+	text := strings.Join(screenLines(vm.screen), "\n")
+	assert.Contains(t, text, "Stashes: 2")
+	assert.Contains(t, text, "stash@{1}")
+	assert.Contains(t, text, "main first change")
+
+	// Enter shows the changes the stash keeps
+	vm.HandleKey(tcell.KeyRune, 'j', 0)
+	assert.Equal(t, "stash@{1}", view.Selection().Stash)
+	vm.HandleKey(tcell.KeyEnter, 0, 0)
+	require.Equal(t, ViewTypeDiff, vm.GetCurrentView())
+	diff := vm.views[ViewTypeDiff].(*DiffView).GetDiff()
+	require.Len(t, diff.Files, 1)
+	assert.Equal(t, "file.txt", diff.Files[0].NewPath)
+
+	// Popping the older stash leaves the other one selected
+	require.NoError(t, vm.switchView(ViewTypeStash))
+	vm.HandleKey(tcell.KeyRune, 'p', 0)
+	assert.Equal(t, "Popped stash@{1}", vm.GetMessage())
+	data, err := os.ReadFile(filepath.Join(dir, "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "first\n", string(data))
+	require.Len(t, view.stashes, 1)
+	assert.Equal(t, 0, view.selected)
+
+	// Dropping asks first
+	vm.HandleKey(tcell.KeyRune, 'd', 0)
+	require.True(t, vm.Confirming())
+	vm.HandleKey(tcell.KeyRune, 'n', 0)
+	assert.Len(t, view.stashes, 1)
+	vm.HandleKey(tcell.KeyRune, 'a', 0)
+	assert.Equal(t, "Applied stash@{0}", vm.GetMessage())
+	_, err = os.Stat(filepath.Join(dir, "new.txt"))
+	assert.NoError(t, err)
+	vm.HandleKey(tcell.KeyRune, 'd', 0)
+	require.True(t, vm.Confirming())
+	vm.HandleKey(tcell.KeyRune, 'y', 0)
+	assert.Equal(t, "Dropped stash@{0}", vm.GetMessage())
+	assert.Empty(t, view.stashes)
+
+	vm.config.General.ReadOnly = true
+	vm.HandleKey(tcell.KeyRune, 'c', 0)
+	assert.Equal(t, "stash-push is disabled in read-only mode", vm.GetMessage())
+	assert.Empty(t, vm.commandRequest)
+	assert.Error(t, vm.StashCommand([]string{"push"}))
+	assert.NoError(t, vm.StashCommand(nil))
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/azhao1981/tig/internal/git"
+)
+
+// statusMoreFormat is the line standing for the untracked files beyond
+// status-untracked-limit
+const statusMoreFormat = "  … and %d more, Enter to show them"
+
+// statusFilterMatches returns whether a path matches the filter of the
+// status view: a glob when it has wildcards, matched against the path and
+// its base name, or else a substring regardless of case
+func statusFilterMatches(filter, file string) bool {
+	if isGlob(filter) {
+		whole, _ := path.Match(filter, file)
+		base, _ := path.Match(filter, path.Base(file))
+		return whole || base
+	}
+	return strings.Contains(strings.ToLower(file), strings.ToLower(filter))
+}
+
+// isGlob returns whether a filter is a valid glob pattern with wildcards
+func isGlob(filter string) bool {
+	if !strings.ContainsAny(filter, "*?[") {
+		return false
+	}
+	_, err := path.Match(filter, "")
+	return err == nil
+}
+
+// filterStatus returns a copy of a status with only the files matching the
+// filter of the view, the status itself if there is none
+func (v *StatusView) filterStatus(status *git.Status) *git.Status {
+	if v.filter == "" {
+		return status
+	}
+
+	filtered := *status
+	for _, files := range []*[]git.FileStatus{&filtered.Staged, &filtered.Modified, &filtered.Untracked, &filtered.Conflict} {
+		var kept []git.FileStatus
+		for _, file := range *files {
+			if statusFilterMatches(v.filter, file.Path) {
+				kept = append(kept, file)
+			}
+		}
+		*files = kept
+	}
+	return &filtered
+}
+
+// shownUntracked returns the untracked files listed, and how many more are
+// hidden by status-untracked-limit until shown
+func (v *StatusView) shownUntracked() ([]git.FileStatus, int) {
+	files := v.status.Untracked
+	limit := v.config.Views.Status.UntrackedLimit
+	if v.allUntracked || limit <= 0 || len(files) <= limit {
+		return files, 0
+	}
+	return files[:limit], len(files) - limit
+}
+
+// selectedMore returns whether the line standing for the hidden untracked
+// files is selected
+func (v *StatusView) selectedMore() bool {
+	if v.status == nil {
+		return false
+	}
+	lines, _ := v.buildStatusContent()
+	_, hidden := v.shownUntracked()
+	return hidden > 0 && v.selected >= 0 && v.selected < len(lines) &&
+		lines[v.selected] == fmt.Sprintf(statusMoreFormat, hidden)
+}
+
+// setFilter narrows the files listed to those matching a pattern, all of
+// them when empty
+func (v *StatusView) setFilter(filter string) error {
+	v.filter = filter
+	v.selected = 0
+	v.ScrollToTop()
+	return v.Refresh()
+}
+
+// StatusFilterCommand handles the :status-filter command, which narrows the
+// files of the status view to those matching a glob or substring
+func (vm *ViewManager) StatusFilterCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	view, ok := vm.views[ViewTypeStatus].(*StatusView)
+	if !ok {
+		return fmt.Errorf("status view not found")
+	}
+
+	filter := strings.Join(args, " ")
+	if err := view.setFilter(filter); err != nil {
+		return err
+	}
+	if filter == "" {
+		vm.setMessage("Showing all files")
+	} else if strings.ContainsAny(filter, "*?[") && !isGlob(filter) {
+		vm.setMessage("Invalid pattern %q, matched as a substring", filter)
+	}
+	return vm.switchView(ViewTypeStatus)
+}
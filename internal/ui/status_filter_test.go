@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusFilterMatches(t *testing.T) {
+	testCases := []struct {
+		filter, path string
+		match        bool
+	}{
+		{"main", "cmd/Main.go", true},
+		{"*.go", "cmd/main.go", true},
+		{"cmd/*", "cmd/main.go", true},
+		{"*.md", "cmd/main.go", false},
+		{"[", "a[b", true}, // Invalid globs match as substrings
+		{"doc", "cmd/main.go", false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.match, statusFilterMatches(tc.filter, tc.path), "%s %s", tc.filter, tc.path)
+	}
+}
+
+func TestStatusViewFilter(t *testing.T) {
+	client := openTestRepo(t)
+	dir := client.GetRootPath()
+	writeStatusFiles(t, dir, "base\n", "README.md", "main.go", "docs/guide.md")
+	refsTestGit(t, dir, "add", ".")
+	refsTestGit(t, dir, "commit", "--quiet", "-m", "base")
+	writeStatusFiles(t, dir, "changed\n", "README.md", "main.go", "docs/guide.md", "notes.md")
+	refsTestGit(t, dir, "add", "README.md")
+
+	view := NewStatusView(&config.Config{}, client)
+	view.filter = "*.md"
+	require.NoError(t, view.Refresh())
+	assert.Equal(t, 4, view.total)
+	assert.Equal(t, []string{"README.md"}, statusPaths(view.status.Staged))
+	assert.Equal(t, []string{"docs/guide.md"}, statusPaths(view.status.Modified))
+	assert.Equal(t, []string{"notes.md"}, statusPaths(view.status.Untracked))
+
+	view.filter = "nothing"
+	require.NoError(t, view.Refresh())
+	lines := view.buildStatusLines()
+	assert.Equal(t, `none of the 4 changed files match "nothing"`, lines[len(lines)-1])
+}
+
+func TestStatusViewUntrackedLimit(t *testing.T) {
+	client := openTestRepo(t)
+	dir := client.GetRootPath()
+	writeStatusFiles(t, dir, "base\n", "base.txt")
+	refsTestGit(t, dir, "add", ".")
+	refsTestGit(t, dir, "commit", "--quiet", "-m", "base")
+	for i := 0; i < 5; i++ {
+		writeStatusFiles(t, dir, "new\n", fmt.Sprintf("file%d", i))
+	}
+
+	cfg := &config.Config{}
+	cfg.Views.Status.UntrackedLimit = 2
+	view := NewStatusView(cfg, client)
+	view.Focus()
+	view.SetPosition(0, 0, 80, 20)
+	require.NoError(t, view.Refresh())
+
+	lines, sections := view.buildStatusContent()
+	assert.Equal(t, []string{
+		"On branch main",
+		"",
+		"Untracked files:",
+		`  (use "git add <file>..." to include in what will be committed)`,
+		"\tfile0",
+		"\tfile1",
+		"  … and 3 more, Enter to show them",
+		"",
+		"5 untracked changes",
+	}, lines)
+	assert.Equal(t, statusSectionUntracked, sections[6])
+
+	// The folded section counts them all
+	view.folds[statusSectionUntracked] = true
+	assert.Equal(t, "Untracked files: [folded, 5 files]", view.buildStatusLines()[2])
+	view.folds[statusSectionUntracked] = false
+
+	// Enter elsewhere is left to the view manager
+	view.selected = 4
+	assert.False(t, view.HandleKey(tcell.KeyEnter, 0, 0))
+	view.selected = 6
+	file, _ := view.selectedEntry()
+	assert.Nil(t, file)
+	require.True(t, view.HandleKey(tcell.KeyEnter, 0, 0))
+	lines = view.buildStatusLines()
+	assert.Len(t, lines, 11)
+	assert.Equal(t, "\tfile4", lines[8])
+}
+
+func TestStatusFilterCommand(t *testing.T) {
+	_, vm := autostashTestRepo(t)
+	require.NoError(t, vm.StatusFilterCommand([]string{"*.go"}))
+	assert.Equal(t, ViewTypeStatus, vm.GetCurrentView())
+	view := vm.views[ViewTypeStatus].(*StatusView)
+	assert.Equal(t, "*.go", view.filter)
+	for _, file := range view.getAllFiles() {
+		assert.Contains(t, file.Path, ".go")
+	}
+
+	// '/' prompts for the filter in the status view
+	assert.True(t, vm.HandleKey(tcell.KeyRune, '/', 0))
+	assert.Equal(t, "status-filter ", vm.TakeCommandRequest())
+
+	require.NoError(t, vm.StatusFilterCommand(nil))
+	assert.Equal(t, "", view.filter)
+	assert.Equal(t, "Showing all files", vm.GetMessage())
+}
@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/azhao1981/tig/internal/git"
+)
+
+// sortStatus returns a copy of a status with the files of each section in
+// the order set by status-sort, grouped by top-level directory with
+// status-group-by-dir. The status itself is left alone as it is cached.
+func (v *StatusView) sortStatus(status *git.Status) *git.Status {
+	sorted := *status
+	order := v.config.Views.Status.Sort
+
+	var staged, unstaged map[string]int
+	if order == "size" {
+		staged, unstaged = v.changeSizes(true), v.changeSizes(false)
+	}
+	root := v.client.GetRootPath()
+
+	sections := []struct {
+		files *[]git.FileStatus
+		sizes map[string]int
+	}{
+		{&sorted.Staged, staged},
+		{&sorted.Modified, unstaged},
+		{&sorted.Untracked, nil},
+		{&sorted.Conflict, unstaged},
+	}
+	for _, section := range sections {
+		files := append([]git.FileStatus(nil), *section.files...)
+		switch order {
+		case "extension":
+			sortFilesBy(files, func(file git.FileStatus) string { return filepath.Ext(file.Path) })
+		case "mtime":
+			times := make(map[string]time.Time)
+			for _, file := range files {
+				if info, err := os.Stat(filepath.Join(root, file.Path)); err == nil {
+					times[file.Path] = info.ModTime()
+				}
+			}
+			sortFilesDescending(files, func(a, b git.FileStatus) int { return times[a.Path].Compare(times[b.Path]) })
+		case "size":
+			sizes := section.sizes
+			if sizes == nil {
+				sizes = untrackedSizes(root, files)
+			}
+			sortFilesDescending(files, func(a, b git.FileStatus) int { return sizes[a.Path] - sizes[b.Path] })
+		default:
+			// By path alone
+			sortFilesBy(files, func(git.FileStatus) string { return "" })
+		}
+		if v.config.Views.Status.GroupByDir {
+			sort.SliceStable(files, func(i, j int) bool {
+				return topLevelDir(files[i].Path) < topLevelDir(files[j].Path)
+			})
+		}
+		*section.files = files
+	}
+	return &sorted
+}
+
+// sortFilesBy sorts files by the given key, then by path
+func sortFilesBy(files []git.FileStatus, key func(file git.FileStatus) string) {
+	sort.SliceStable(files, func(i, j int) bool {
+		if a, b := key(files[i]), key(files[j]); a != b {
+			return a < b
+		}
+		return files[i].Path < files[j].Path
+	})
+}
+
+// sortFilesDescending sorts files with the greatest first, as told by
+// compare, then by path
+func sortFilesDescending(files []git.FileStatus, compare func(a, b git.FileStatus) int) {
+	sort.SliceStable(files, func(i, j int) bool {
+		if c := compare(files[i], files[j]); c != 0 {
+			return c > 0
+		}
+		return files[i].Path < files[j].Path
+	})
+}
+
+// changeSizes returns the number of changed lines of the files with changes
+// staged or not, empty when git fails to count them
+func (v *StatusView) changeSizes(staged bool) map[string]int {
+	sizes := make(map[string]int)
+	stats, err := v.client.GetChangeStat(staged)
+	if err != nil {
+		return sizes
+	}
+	for _, stat := range stats {
+		sizes[stat.Path] = stat.Additions + stat.Deletions
+	}
+	return sizes
+}
+
+// untrackedSizes returns the number of lines of untracked files, which
+// adding them adds
+func untrackedSizes(root string, files []git.FileStatus) map[string]int {
+	sizes := make(map[string]int)
+	for _, file := range files {
+		if data, err := os.ReadFile(filepath.Join(root, file.Path)); err == nil {
+			sizes[file.Path] = bytes.Count(data, []byte("\n"))
+		}
+	}
+	return sizes
+}
+
+// topLevelDir returns the top-level directory of a path, empty for files at
+// the root of the repository
+func topLevelDir(path string) string {
+	dir, _, ok := strings.Cut(path, "/")
+	if !ok {
+		return ""
+	}
+	return dir
+}
+
+// groupEntries inserts the header of each top-level directory before its
+// files, which are grouped already
+func groupEntries(files []git.FileStatus, entries []string) []string {
+	var grouped []string
+	for i, file := range files {
+		dir := topLevelDir(file.Path)
+		if i == 0 || dir != topLevelDir(files[i-1].Path) {
+			count := 0
+			for _, other := range files[i:] {
+				if topLevelDir(other.Path) == dir {
+					count++
+				}
+			}
+			name := "./"
+			if dir != "" {
+				name = dir + "/"
+			}
+			grouped = append(grouped, fmt.Sprintf("  %s (%s)", name, countFiles(count)))
+		}
+		grouped = append(grouped, entries[i])
+	}
+	return grouped
+}
@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statusPaths returns the paths of the files of a section
+func statusPaths(files []git.FileStatus) []string {
+	var paths []string
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
+
+func TestStatusViewSort(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	refsTestGit(t, dir, "init", "--quiet", "--initial-branch=main")
+	write := func(name, content string, age time.Duration) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		mtime := time.Now().Add(-age)
+		require.NoError(t, os.Chtimes(path, mtime, mtime))
+	}
+	for _, name := range []string{"b.txt", "a.go", "src/c.go", "doc/d.md"} {
+		write(name, "x\n", 0)
+	}
+	refsTestGit(t, dir, "add", ".")
+	refsTestGit(t, dir, "commit", "--quiet", "-m", "base")
+	write("b.txt", "1\n2\n3\n", 3*time.Hour)
+	write("a.go", "1\n", time.Hour)
+	write("src/c.go", "1\n2\n", 2*time.Hour)
+	write("doc/d.md", "1\n2\n3\n4\n", 4*time.Hour)
+	write("new.txt", "1\n2\n", 0)
+	write("tmp.log", "1\n2\n3\n4\n5\n", time.Hour)
+
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	cfg := &config.Config{}
+	view := NewStatusView(cfg, client)
+	status, err := client.GetStatus()
+	require.NoError(t, err)
+	modified, untracked := statusPaths(status.Modified), statusPaths(status.Untracked)
+	require.ElementsMatch(t, []string{"a.go", "b.txt", "doc/d.md", "src/c.go"}, modified)
+	require.ElementsMatch(t, []string{"new.txt", "tmp.log"}, untracked)
+
+	testCases := []struct {
+		order     string
+		modified  []string
+		untracked []string
+	}{
+		{"path", []string{"a.go", "b.txt", "doc/d.md", "src/c.go"}, []string{"new.txt", "tmp.log"}},
+		{"extension", []string{"a.go", "src/c.go", "doc/d.md", "b.txt"}, []string{"tmp.log", "new.txt"}},
+		{"mtime", []string{"a.go", "src/c.go", "b.txt", "doc/d.md"}, []string{"new.txt", "tmp.log"}},
+		{"size", []string{"doc/d.md", "b.txt", "src/c.go", "a.go"}, []string{"tmp.log", "new.txt"}},
+	}
+	for _, tc := range testCases {
+		cfg.Views.Status.Sort = tc.order
+		sorted := view.sortStatus(status)
+		assert.Equal(t, tc.modified, statusPaths(sorted.Modified), tc.order)
+		assert.Equal(t, tc.untracked, statusPaths(sorted.Untracked), tc.order)
+	}
+	// The cached status is left alone
+	assert.Equal(t, modified, statusPaths(status.Modified))
+	assert.Equal(t, untracked, statusPaths(status.Untracked))
+
+	// Files at the root come first, then each directory under a header
+	cfg.Views.Status.Sort = "path"
+	cfg.Views.Status.GroupByDir = true
+	require.NoError(t, view.Refresh())
+	assert.Equal(t, []string{"a.go", "b.txt", "doc/d.md", "src/c.go"}, statusPaths(view.status.Modified))
+	lines, _ := view.buildStatusContent()
+	assert.Equal(t, []string{
+		"On branch main",
+		"",
+		"Changes not staged for commit:",
+		"  (use \"git add <file>...\" to update what will be committed)",
+		"  (use \"git checkout -- <file>...\" to discard changes in working directory)",
+		"  ./ (2 files)",
+		"\tmodified: a.go",
+		"\tmodified: b.txt",
+		"  doc/ (1 file)",
+		"\tmodified: doc/d.md",
+		"  src/ (1 file)",
+		"\tmodified: src/c.go",
+		"",
+	}, lines[:13])
+
+	// Group headers are not files
+	view.selected = 8
+	file, _ := view.selectedEntry()
+	assert.Nil(t, file)
+	view.selected = 9
+	file, _ = view.selectedEntry()
+	require.NotNil(t, file)
+	assert.Equal(t, "doc/d.md", file.Path)
+}
@@ -18,10 +18,30 @@ type StatusView struct {
 	status   *git.Status
 	selected int
 	repoPath string
-	box      *DrawBox
+	frame    *Frame
 	mode     StatusMode
+	folds    foldSet
+	foldKeys foldPrefix
+	cache    *git.StatusCache
+	bulk     func(run func() error) error // Runs operations on every file, set by the view manager
+	filter   string                       // Glob or substring the files listed match, empty for all
+	total    int                          // Files of the status before filtering
+
+	allUntracked bool // Untracked files past status-untracked-limit are shown
+
+	// confirm runs a destructive action once confirmed as configured, set
+	// by the view manager
+	confirm func(action, prompt, name string, details []string, run func() error) error
 }
 
+// Foldable sections of the status view
+const (
+	statusSectionStaged    = "staged"
+	statusSectionModified  = "modified"
+	statusSectionUntracked = "untracked"
+	statusSectionConflict  = "conflict"
+)
+
 // StatusMode represents the current status display mode
 type StatusMode int
 
@@ -40,8 +60,9 @@ func NewStatusView(config *config.Config, client git.Client) *StatusView {
 		Scrollable: NewScrollable(),
 		config:     config,
 		client:     client,
-		box:        NewDrawBox("Status", tcell.StyleDefault.Foreground(tcell.ColorWhite)),
+		frame:      NewFrame(config, "Status"),
 		mode:       StatusModeFiles,
+		folds:      make(foldSet),
 	}
 }
 
@@ -50,14 +71,13 @@ func (v *StatusView) Render(screen tcell.Screen, x, y, width, height int) error
 	v.SetPosition(x, y, width, height)
 	v.SetHeight(height - 2) // Account for borders
 
-	// Draw box
-	v.box.Draw(screen, x, y, width, height)
-
-	// Draw content area
-	contentX := x + 1
-	contentY := y + 1
-	contentWidth := width - 2
-	contentHeight := height - 2
+	// Draw the frame, and its scrollbar once the content is laid out
+	v.frame.Status = ""
+	if v.filter != "" {
+		v.frame.Status = fmt.Sprintf("Filter: %s", v.filter)
+	}
+	contentX, contentY, contentWidth, contentHeight := v.frame.Draw(screen, x, y, width, height)
+	defer v.frame.DrawScrollbar(screen, v.Scrollable)
 
 	if contentWidth <= 0 || contentHeight <= 0 {
 		return nil
@@ -131,70 +151,112 @@ func (v *StatusView) renderStatus(screen tcell.Screen, x, y, width, height int)
 
 // buildStatusLines builds the status content lines
 func (v *StatusView) buildStatusLines() []string {
+	lines, _ := v.buildStatusContent()
+	return lines
+}
+
+// buildStatusContent builds the status content lines along with the key of
+// the foldable section each line belongs to, "" for lines outside of one
+func (v *StatusView) buildStatusContent() ([]string, []string) {
 	lines := make([]string, 0)
+	sections := make([]string, 0)
 
 	// Add branch information
 	if v.status.Branch != "" {
 		lines = append(lines, fmt.Sprintf("On branch %s", v.status.Branch))
+		if v.status.NoCommits {
+			lines = append(lines, "", "No commits yet")
+		}
 		
-		// Add ahead/behind information
-		if v.status.Ahead > 0 || v.status.Behind > 0 {
-			aheadBehind := fmt.Sprintf("Your branch is ahead of 'origin/%s' by %d commit(s)", v.status.Branch, v.status.Ahead)
-			if v.status.Behind > 0 {
-				aheadBehind = fmt.Sprintf("Your branch is behind 'origin/%s' by %d commit(s)", v.status.Branch, v.status.Behind)
-			}
-			if v.status.Ahead > 0 && v.status.Behind > 0 {
-				aheadBehind = fmt.Sprintf("Your branch and 'origin/%s' have diverged by %d and %d commits respectively", v.status.Branch, v.status.Ahead, v.status.Behind)
+		// Add ahead/behind information, as git status words it
+		if upstream := v.status.Upstream; upstream != "" {
+			aheadBehind := fmt.Sprintf("Your branch is up to date with '%s'.", upstream)
+			switch ahead, behind := v.status.Ahead, v.status.Behind; {
+			case ahead > 0 && behind > 0:
+				aheadBehind = fmt.Sprintf("Your branch and '%s' have diverged, and have %d and %d different commits each, respectively.", upstream, ahead, behind)
+			case ahead > 0:
+				aheadBehind = fmt.Sprintf("Your branch is ahead of '%s' by %s.", upstream, countCommits(ahead))
+			case behind > 0:
+				aheadBehind = fmt.Sprintf("Your branch is behind '%s' by %s.", upstream, countCommits(behind))
 			}
 			lines = append(lines, aheadBehind)
 		}
 		lines = append(lines, "")
 	}
+	for len(sections) < len(lines) {
+		sections = append(sections, "")
+	}
 
-	// Add staged files
-	if len(v.status.Staged) > 0 {
-		lines = append(lines, "Changes to be committed:")
-		lines = append(lines, `  (use "git reset HEAD <file>..." to unstage)`)
-		for _, file := range v.status.Staged {
-			lines = append(lines, fmt.Sprintf("\t%s: %s", v.formatStatus(file.X), file.Path))
+	addSection := func(key, title string, hints []string, files []git.FileStatus, entries []string, hidden int) {
+		if len(entries) == 0 {
+			return
+		}
+		if v.folds[key] {
+			lines = append(lines, fmt.Sprintf("%s [folded, %d files]", title, len(entries)+hidden))
+			sections = append(sections, key)
+		} else {
+			if v.config.Views.Status.GroupByDir {
+				entries = groupEntries(files, entries)
+			}
+			if hidden > 0 {
+				entries = append(entries, fmt.Sprintf(statusMoreFormat, hidden))
+			}
+			lines = append(lines, title)
+			lines = append(lines, hints...)
+			lines = append(lines, entries...)
+			for i := 0; i < 1+len(hints)+len(entries); i++ {
+				sections = append(sections, key)
+			}
 		}
 		lines = append(lines, "")
+		sections = append(sections, "")
 	}
 
+	// Add staged files
+	var entries []string
+	for _, file := range v.status.Staged {
+		entries = append(entries, fmt.Sprintf("\t%s: %s%s", v.formatStatus(file.X), file.Path, eolNote(file)))
+	}
+	addSection(statusSectionStaged, "Changes to be committed:", []string{
+		`  (use "git reset HEAD <file>..." to unstage)`,
+	}, v.status.Staged, entries, 0)
+
 	// Add modified files
-	if len(v.status.Modified) > 0 {
-		lines = append(lines, "Changes not staged for commit:")
-		lines = append(lines, "  (use \"git add <file>...\" to update what will be committed)")
-		lines = append(lines, "  (use \"git checkout -- <file>...\" to discard changes in working directory)")
-		for _, file := range v.status.Modified {
-			lines = append(lines, fmt.Sprintf("\t%s: %s", v.formatStatus(file.Y), file.Path))
-		}
-		lines = append(lines, "")
+	entries = nil
+	for _, file := range v.status.Modified {
+		entries = append(entries, fmt.Sprintf("\t%s: %s%s", v.formatStatus(file.Y), file.Path, eolNote(file)))
 	}
+	addSection(statusSectionModified, "Changes not staged for commit:", []string{
+		"  (use \"git add <file>...\" to update what will be committed)",
+		"  (use \"git checkout -- <file>...\" to discard changes in working directory)",
+	}, v.status.Modified, entries, 0)
 
-	// Add untracked files
-	if len(v.status.Untracked) > 0 {
-		lines = append(lines, "Untracked files:")
-		lines = append(lines, `  (use "git add <file>..." to include in what will be committed)`)
-		for _, file := range v.status.Untracked {
-			lines = append(lines, fmt.Sprintf("\t%s", file.Path))
-		}
-		lines = append(lines, "")
+	// Add untracked files, up to status-untracked-limit
+	entries = nil
+	untracked, hidden := v.shownUntracked()
+	for _, file := range untracked {
+		entries = append(entries, fmt.Sprintf("\t%s", file.Path))
 	}
+	addSection(statusSectionUntracked, "Untracked files:", []string{
+		`  (use "git add <file>..." to include in what will be committed)`,
+	}, untracked, entries, hidden)
 
 	// Add conflict files
-	if len(v.status.Conflict) > 0 {
-		lines = append(lines, "Unmerged paths:")
-		lines = append(lines, `  (use "git add <file>..." to mark resolution)`)
-		for _, file := range v.status.Conflict {
-			lines = append(lines, fmt.Sprintf("\tboth modified: %s", file.Path))
-		}
-		lines = append(lines, "")
+	entries = nil
+	for _, file := range v.status.Conflict {
+		entries = append(entries, fmt.Sprintf("\tboth modified: %s", file.Path))
 	}
+	addSection(statusSectionConflict, "Unmerged paths:", []string{
+		`  (use "git add <file>..." to mark resolution)`,
+	}, v.status.Conflict, entries, 0)
 
 	// Add summary
 	if len(v.status.Staged) == 0 && len(v.status.Modified) == 0 && len(v.status.Untracked) == 0 && len(v.status.Conflict) == 0 {
-		lines = append(lines, "nothing to commit, working tree clean")
+		if v.filter != "" && v.total > 0 {
+			lines = append(lines, fmt.Sprintf("none of the %d changed files match %q", v.total, v.filter))
+		} else {
+			lines = append(lines, "nothing to commit, working tree clean")
+		}
 	} else {
 		var staged, modified, untracked, conflict int
 		staged = len(v.status.Staged)
@@ -221,20 +283,29 @@ func (v *StatusView) buildStatusLines() []string {
 		}
 		lines = append(lines, summary)
 	}
-	
-	// Add key bindings help
-	lines = append(lines, "")
-	lines = append(lines, "Key bindings:")
-	lines = append(lines, "  a - stage/unstage selected file")
-	lines = append(lines, "  u - unstage selected file")
-	lines = append(lines, "  d - discard changes to selected file")
-	lines = append(lines, "  A - stage all files")
-	lines = append(lines, "  U - unstage all files")
-	lines = append(lines, "  c - commit staged changes")
-	lines = append(lines, "  s - switch display mode")
-	lines = append(lines, "  q - quit")
+	for len(sections) < len(lines) {
+		sections = append(sections, "")
+	}
 
-	return lines
+	return lines, sections
+}
+
+// HintContext returns whether a file, and which kind, or the header of a
+// section is selected
+func (v *StatusView) HintContext() string {
+	if v.status == nil {
+		return ""
+	}
+	lines, sections := v.buildStatusContent()
+	if v.selected < 0 || v.selected >= len(lines) || sections[v.selected] == "" {
+		return ""
+	}
+	if !strings.HasPrefix(lines[v.selected], "\t") {
+		return hintContextHeader
+	}
+
+	// The sections are named after the kind of files they list
+	return sections[v.selected]
 }
 
 // formatStatus formats the git status character
@@ -290,7 +361,20 @@ func (v *StatusView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
 		return false
 	}
 
+	if key != tcell.KeyRune {
+		v.foldKeys.reset()
+	} else if action, ok := v.foldKeys.handle(ch); ok {
+		v.fold(action)
+		return true
+	}
+
 	switch key {
+	case tcell.KeyEnter:
+		// Enter on the line standing for hidden untracked files shows them
+		if v.selectedMore() {
+			v.allUntracked = true
+			return true
+		}
 	case tcell.KeyUp:
 		v.moveUp()
 		return true
@@ -350,10 +434,6 @@ func (v *StatusView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
 		// Unstage all files
 		v.unstageAllFiles()
 		return true
-	case 'c':
-		// Commit staged changes
-		v.commit()
-		return true
 	}
 
 	return false
@@ -371,6 +451,9 @@ func (v *StatusView) moveUp() {
 
 // moveDown moves selection down
 func (v *StatusView) moveDown() {
+	if v.status == nil {
+		return
+	}
 	lines := v.buildStatusLines()
 	if v.selected < len(lines)-1 {
 		v.selected++
@@ -381,6 +464,41 @@ func (v *StatusView) moveDown() {
 	}
 }
 
+// fold folds or unfolds the section under the selection, or all sections
+func (v *StatusView) fold(action foldAction) {
+	if action == foldNone || v.status == nil {
+		return
+	}
+
+	_, sections := v.buildStatusContent()
+	key := ""
+	if v.selected < len(sections) {
+		key = sections[v.selected]
+	}
+	if key == "" && action != foldOpenAll && action != foldCloseAll {
+		return
+	}
+
+	v.folds.apply(action, key, []string{
+		statusSectionStaged,
+		statusSectionModified,
+		statusSectionUntracked,
+		statusSectionConflict,
+	})
+
+	// Keep the selection on the header of the section that was acted on
+	lines, sections := v.buildStatusContent()
+	for i, section := range sections {
+		if section == key && key != "" {
+			v.selected = i
+			break
+		}
+	}
+	if v.selected >= len(lines) {
+		v.selected = len(lines) - 1
+	}
+}
+
 // toggleMode toggles between different status display modes
 func (v *StatusView) toggleMode() {
 	v.mode = (v.mode + 1) % 5 // Cycle through 5 modes
@@ -396,24 +514,109 @@ func (v *StatusView) Refresh() error {
 		return nil
 	}
 
-	repo, err := v.client.GetRepository()
+	status, err := v.loadStatus()
 	if err != nil {
-		return fmt.Errorf("failed to get repository: %w", err)
+		return fmt.Errorf("failed to get status: %w", err)
 	}
 
-	// Get repository status
-	status, err := repo.GetStatus()
-	if err != nil {
-		return fmt.Errorf("failed to get status: %w", err)
+	// Keep the line of the selected file selected when it is still listed,
+	// lines of different sections are told apart by their section
+	var selectedLine, selectedSection string
+	if v.status != nil {
+		lines, sections := v.buildStatusContent()
+		if v.selected >= 0 && v.selected < len(lines) {
+			selectedLine, selectedSection = lines[v.selected], sections[v.selected]
+		}
 	}
 
-	v.status = status
-	v.selected = 0
-	v.ScrollToTop()
+	v.total = len(status.Staged) + len(status.Modified) + len(status.Untracked) + len(status.Conflict)
+	v.status = v.sortStatus(v.filterStatus(status))
+	lines, sections := v.buildStatusContent()
+	v.selected = findSelection(len(lines), v.selected, func(i int) bool {
+		return selectedLine != "" && lines[i] == selectedLine && sections[i] == selectedSection
+	})
+	v.scrollToSelection()
 
 	return nil
 }
 
+// loadStatus returns the status of the repository, reusing the last one
+// while nothing changed
+func (v *StatusView) loadStatus() (*git.Status, error) {
+	if v.cache == nil || v.cache.Root() != v.client.GetRootPath() {
+		v.cache = git.NewStatusCache(v.client.GetRootPath())
+	}
+	return v.cache.Get(func() (*git.Status, error) {
+		status, err := v.client.GetStatus()
+		if err == nil {
+			v.markEOLOnly(status)
+		}
+		return status, err
+	})
+}
+
+// scrollToSelection scrolls the selected line into view
+func (v *StatusView) scrollToSelection() {
+	if v.selected < v.GetOffset() {
+		v.SetOffset(v.selected)
+	} else if pageSize := v.getPageSize(); pageSize > 0 && v.selected >= v.GetOffset()+pageSize {
+		v.SetOffset(v.selected - pageSize + 1)
+	}
+}
+
+// markEOLOnly flags the staged and modified files whose line endings alone
+// changed, as with core.autocrlf or a .gitattributes eol set after the
+// files were committed
+func (v *StatusView) markEOLOnly(status *git.Status) {
+	mark := func(files []git.FileStatus, staged bool) {
+		paths, err := v.client.EOLOnlyChanges(staged)
+		if err != nil || len(paths) == 0 {
+			return
+		}
+		eolOnly := make(map[string]bool, len(paths))
+		for _, path := range paths {
+			eolOnly[path] = true
+		}
+		for i := range files {
+			files[i].EOLOnly = eolOnly[files[i].Path]
+		}
+	}
+	mark(status.Staged, true)
+	mark(status.Modified, false)
+}
+
+// eolNote returns the note following the entries of files whose line
+// endings alone changed
+func eolNote(file git.FileStatus) string {
+	if file.EOLOnly {
+		return " (line endings only)"
+	}
+	return ""
+}
+
+// refreshChanged refreshes the view after changing the index or worktree,
+// bypassing the cached status
+func (v *StatusView) refreshChanged() error {
+	if v.cache != nil {
+		v.cache.Invalidate()
+	}
+	return v.Refresh()
+}
+
+// PagerArgs returns the git arguments producing the raw status output
+func (v *StatusView) PagerArgs() ([]string, error) {
+	return []string{"status"}, nil
+}
+
+// Selection returns the selected file for placeholder expansion
+func (v *StatusView) Selection() Selection {
+	var sel Selection
+	if file := v.GetSelectedFile(); file != nil {
+		sel.File = file.Path
+	}
+	return sel
+}
+
 // SetRepoPath sets the repository path
 func (v *StatusView) SetRepoPath(path string) {
 	v.repoPath = path
@@ -486,7 +689,7 @@ func (v *StatusView) stageSelectedFile() error {
 		}
 		
 		// Refresh the status view
-		return v.Refresh()
+		return v.refreshChanged()
 	}
 	
 	return nil
@@ -506,7 +709,7 @@ func (v *StatusView) unstageSelectedFile() error {
 		}
 		
 		// Refresh the status view
-		return v.Refresh()
+		return v.refreshChanged()
 	}
 	
 	return nil
@@ -536,13 +739,20 @@ func (v *StatusView) discardSelectedFile() error {
 	}
 
 	if file.IsModified {
-		err := v.client.DiscardChanges(file.Path)
-		if err != nil {
-			return fmt.Errorf("failed to discard changes to %s: %w", file.Path, err)
+		discard := func() error {
+			err := v.client.DiscardChanges(file.Path)
+			if err != nil {
+				return fmt.Errorf("failed to discard changes to %s: %w", file.Path, err)
+			}
+
+			// Refresh the status view
+			return v.refreshChanged()
 		}
-		
-		// Refresh the status view
-		return v.Refresh()
+		if v.confirm == nil {
+			return discard()
+		}
+		return v.confirm("discard", "Discard the changes to "+file.Path+"?", file.Path,
+			[]string{":undo-discard restores them"}, discard)
 	}
 	
 	return nil
@@ -550,27 +760,29 @@ func (v *StatusView) discardSelectedFile() error {
 
 // stageAllFiles stages all modified and untracked files
 func (v *StatusView) stageAllFiles() error {
-	err := v.client.StageAll()
+	err := v.runBulk(v.client.StageAll)
 	if err != nil {
 		return fmt.Errorf("failed to stage all files: %w", err)
 	}
 	
-	return v.Refresh()
+	return v.refreshChanged()
+}
+
+// runBulk runs an operation on every file, through the view manager when
+// the view has one so that the periodic refresh waits for it
+func (v *StatusView) runBulk(run func() error) error {
+	if v.bulk == nil {
+		return run()
+	}
+	return v.bulk(run)
 }
 
 // unstageAllFiles unstages all files
 func (v *StatusView) unstageAllFiles() error {
-	err := v.client.UnstageAll()
+	err := v.runBulk(v.client.UnstageAll)
 	if err != nil {
 		return fmt.Errorf("failed to unstage all files: %w", err)
 	}
 	
-	return v.Refresh()
+	return v.refreshChanged()
 }
-
-// commit opens a commit interface
-func (v *StatusView) commit() {
-	// This would be implemented with a commit dialog
-	// For now, we'll just log the action
-	fmt.Printf("Commit functionality would be implemented here\n")
-}
\ No newline at end of file
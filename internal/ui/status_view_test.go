@@ -1,12 +1,16 @@
 package ui
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"github.com/azhao1981/tig/internal/config"
 	"github.com/azhao1981/tig/internal/git"
 	"github.com/gdamore/tcell/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewStatusView(t *testing.T) {
@@ -64,15 +68,15 @@ func TestStatusViewHandleKey(t *testing.T) {
 		Modified: []git.FileStatus{{Path: "file2.txt"}},
 	}
 
-	assert.Equal(t, 0, view.GetOffset())
+	assert.Equal(t, 0, view.selected)
 
 	handled := view.HandleKey(tcell.KeyDown, 0, 0)
 	assert.True(t, handled)
-	assert.Equal(t, 1, view.GetOffset())
+	assert.Equal(t, 1, view.selected)
 
 	handled = view.HandleKey(tcell.KeyUp, 0, 0)
 	assert.True(t, handled)
-	assert.Equal(t, 0, view.GetOffset())
+	assert.Equal(t, 0, view.selected)
 }
 
 func TestStatusViewRefresh(t *testing.T) {
@@ -91,6 +95,67 @@ func TestStatusViewRefresh(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+// openTestRepo returns a client for a new, empty repository
+func openTestRepo(t *testing.T) git.Client {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	output, err := exec.Command("git", "init", "--quiet", "--initial-branch=main", dir).CombinedOutput()
+	require.NoError(t, err, "git init: %s", output)
+
+	client := git.NewClient()
+	require.NoError(t, client.Open(dir))
+	return client
+}
+
+// writeStatusFiles writes files with the given content into a worktree,
+// creating their directories
+func writeStatusFiles(t *testing.T, dir, content string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestStatusViewRefreshKeepsSelection(t *testing.T) {
+	client := openTestRepo(t)
+	dir := client.GetRootPath()
+	writeStatusFiles(t, dir, "package main\n", "main.go")
+	refsTestGit(t, dir, "add", "main.go")
+	refsTestGit(t, dir, "commit", "--quiet", "-m", "main")
+	writeStatusFiles(t, dir, "package main\n\nfunc main() {}\n", "main.go")
+	writeStatusFiles(t, dir, "# readme\n", "README.md", "extra.go")
+	refsTestGit(t, dir, "add", "README.md", "extra.go")
+
+	view := NewStatusView(&config.Config{}, client)
+	view.SetPosition(0, 0, 80, 24)
+	require.NoError(t, view.Refresh())
+	lines := view.buildStatusLines()
+	for i, line := range lines {
+		if line == "\tmodified: main.go" {
+			view.selected = i
+		}
+	}
+	require.NotZero(t, view.selected)
+	selected := view.selected
+
+	// Unstaging a file listed before the selection moves it up
+	refsTestGit(t, dir, "rm", "--cached", "--quiet", "extra.go")
+	require.NoError(t, view.refreshChanged())
+	assert.Equal(t, selected-1, view.selected)
+	assert.Equal(t, "\tmodified: main.go", view.buildStatusLines()[view.selected])
+
+	// Without the file the position is kept
+	view.selected = len(lines) + 10
+	require.NoError(t, view.Refresh())
+	assert.Equal(t, len(view.buildStatusLines())-1, view.selected)
+}
+
 func TestStatusViewGetSelectedFile(t *testing.T) {
 	cfg := &config.Config{}
 	client := git.NewClient()
@@ -155,3 +220,39 @@ func TestStatusViewRenderWithNilStatus(t *testing.T) {
 	err = view.Render(screen, 0, 0, 80, 24)
 	assert.NoError(t, err)
 }
+
+func TestStatusViewFold(t *testing.T) {
+	cfg := &config.Config{}
+	client := git.NewClient()
+
+	view := NewStatusView(cfg, client)
+	view.Focus()
+	view.SetPosition(0, 0, 80, 24)
+	view.status = &git.Status{
+		Staged:    []git.FileStatus{{Path: "file1.txt", X: "M"}},
+		Untracked: []git.FileStatus{{Path: "file2.txt"}, {Path: "file3.txt"}},
+	}
+
+	lines := view.buildStatusLines()
+	assert.Equal(t, "Untracked files:", lines[4])
+
+	// Fold the untracked section from one of its files
+	view.selected = 6
+	view.HandleKey(tcell.KeyRune, 'z', 0)
+	view.HandleKey(tcell.KeyRune, 'c', 0)
+	folded := view.buildStatusLines()
+	assert.Equal(t, "Untracked files: [folded, 2 files]", folded[4])
+	assert.Equal(t, "", folded[5])
+	assert.Equal(t, 4, view.selected)
+	assert.Len(t, folded, len(lines)-3)
+
+	// Folding outside of a section does nothing
+	view.selected = 5
+	view.HandleKey(tcell.KeyRune, 'z', 0)
+	view.HandleKey(tcell.KeyRune, 'a', 0)
+	assert.Equal(t, folded, view.buildStatusLines())
+
+	view.HandleKey(tcell.KeyRune, 'z', 0)
+	view.HandleKey(tcell.KeyRune, 'R', 0)
+	assert.Equal(t, lines, view.buildStatusLines())
+}
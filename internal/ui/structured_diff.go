@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/azhao1981/tig/internal/git"
+)
+
+// structuredSummaries describe the diffs of each structured diff mode
+var structuredSummaries = map[string]string{
+	"json":     "json diff: keys sorted, indentation normalized",
+	"notebook": "notebook diff: keys sorted, cell outputs stripped",
+}
+
+// structureDiff replaces the hunks of the files structured-diffs gives a
+// structured diff mode with the hunks of their normalized contents. Files
+// failing to normalize, such as invalid JSON, and files converted by
+// their own textconv keep their hunks.
+func (v *DiffView) structureDiff(diff *git.Diff) {
+	if diff == nil {
+		return
+	}
+	for _, file := range diff.Files {
+		mode := v.config.StructuredDiffMode(file.Path())
+		if mode == "" || file.IsBinary || file.Textconv != "" || len(file.Hunks) == 0 {
+			continue
+		}
+
+		oldData, err := v.readBlob(file.OldHash)
+		if err != nil {
+			continue
+		}
+		newData, err := v.readBlob(file.NewHash)
+		if err != nil {
+			// Changes of the worktree are not in the repository yet
+			if newData, err = os.ReadFile(filepath.Join(v.client.GetRootPath(), file.Path())); err != nil {
+				continue
+			}
+		}
+
+		oldText, err := git.NormalizeStructured(mode, oldData)
+		if err != nil {
+			continue
+		}
+		newText, err := git.NormalizeStructured(mode, newData)
+		if err != nil {
+			continue
+		}
+		hunks, err := git.DiffTexts(oldText, newText)
+		if err != nil {
+			continue
+		}
+		file.Hunks = hunks
+		file.Structured = mode
+	}
+}
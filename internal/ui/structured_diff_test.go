@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructuredDiff(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data.json"), []byte(`{"name": "tig", "version": 1, "tags": ["a"]}`), 0644))
+	refsTestGit(t, dir, "add", "data.json")
+	refsTestGit(t, dir, "commit", "--quiet", "-m", "data")
+
+	// Keys reordered and reindented, one value changed
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data.json"), []byte("{\n\t\"version\": 2,\n\t\"tags\": [\"a\"],\n\t\"name\": \"tig\"\n}\n"), 0644))
+	diffView := vm.views[ViewTypeDiff].(*DiffView)
+	require.NoError(t, vm.showStageFile("data.json", statusSectionModified))
+	assert.Empty(t, diffView.GetDiff().Files[0].Structured)
+
+	require.NoError(t, vm.config.Set("structured-diffs", "*.json:json"))
+	require.NoError(t, diffView.Refresh())
+	file := diffView.GetDiff().Files[0]
+	assert.Equal(t, "json", file.Structured)
+	var changed []string
+	for _, hunk := range file.Hunks {
+		for _, line := range hunk.Lines {
+			switch line.Type {
+			case git.DiffLineDeletion:
+				changed = append(changed, "-"+line.Content)
+			case git.DiffLineAddition:
+				changed = append(changed, "+"+line.Content)
+			}
+		}
+	}
+	assert.Equal(t, []string{`-  "version": 1`, `+  "version": 2`}, changed)
+	var texts []string
+	for _, row := range diffView.rows {
+		texts = append(texts, row.text)
+	}
+	assert.Contains(t, texts, structuredSummaries["json"])
+
+	// The hunks are not those of the file
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'S', 0))
+	assert.Equal(t, "hunks of structured diffs cannot be staged", vm.GetMessage())
+
+	// Files which are not valid JSON are diffed as they are
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data.json"), []byte("{broken\n"), 0644))
+	require.NoError(t, diffView.Refresh())
+	assert.Empty(t, diffView.GetDiff().Files[0].Structured)
+}
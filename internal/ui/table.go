@@ -0,0 +1,261 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Alignment of the cells of a table column
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignRight
+)
+
+// TableColumn describes a column of a Table
+type TableColumn struct {
+	Title    string
+	Align    Alignment
+	MinWidth int                    // Width the column is cut down to before being left out, never cut when at least its content
+	MaxWidth int                    // Width the column never exceeds, 0 for none
+	Priority int                    // Columns of a lower priority are cut first when the table is too wide
+	Expand   bool                   // The column takes the room the others leave
+	Style    tcell.Style            // Style of the cells instead of that of their row, when set
+	Less     func(a, b string) bool // Orders the rows when sorting by the column, by text when nil
+}
+
+// TableRow is a row of a Table, one cell per column
+type TableRow struct {
+	Cells []string
+	Style tcell.Style
+}
+
+// Table draws rows of cells in columns sized to their content, cutting
+// the least important ones with an ellipsis or leaving them out when the
+// table doesn't fit, and sorted by a column whose header says so
+type Table struct {
+	Columns    []TableColumn
+	Rows       []TableRow
+	Header     bool // The titles of the columns are drawn on the first row
+	Selected   int  // Row drawn highlighted, -1 for none
+	Offset     int  // First row drawn
+	SortColumn int  // Column the rows are sorted by, -1 for none
+	SortDesc   bool
+}
+
+// tableGap is the room between two columns
+const tableGap = 1
+
+// selectedRowBackground is the background of the selected row
+const selectedRowBackground = tcell.ColorBlue
+
+// NewTable creates a table with the given columns and no rows
+func NewTable(columns ...TableColumn) *Table {
+	return &Table{Columns: columns, Selected: -1, SortColumn: -1}
+}
+
+// title returns the title of a column as drawn in the header, with an
+// arrow when the rows are sorted by it
+func (t *Table) title(column int) string {
+	title := t.Columns[column].Title
+	if column == t.SortColumn {
+		if t.SortDesc {
+			return title + " ▼"
+		}
+		return title + " ▲"
+	}
+	return title
+}
+
+// Widths returns the width of each column of the table drawn in width
+// columns, 0 for those left out
+func (t *Table) Widths(width int) []int {
+	widths := make([]int, len(t.Columns))
+	for i, column := range t.Columns {
+		if t.Header {
+			widths[i] = cellsWidth(textCells(t.title(i), tcell.StyleDefault))
+		}
+		for _, row := range t.Rows {
+			if i < len(row.Cells) {
+				widths[i] = max(widths[i], cellsWidth(textCells(row.Cells[i], tcell.StyleDefault)))
+			}
+		}
+		if column.MaxWidth > 0 {
+			widths[i] = min(widths[i], column.MaxWidth)
+		}
+	}
+
+	// Columns without content take no room, nor a gap
+	total := func() int {
+		sum, shown := 0, 0
+		for _, w := range widths {
+			if w > 0 {
+				sum += w
+				shown++
+			}
+		}
+		return sum + max(shown-1, 0)*tableGap
+	}
+
+	// The least important column is cut down to its minimum width, then
+	// left out, and so on until the table fits. Of columns as important,
+	// the rightmost goes first.
+	for total() > width {
+		cut := -1
+		for i, column := range t.Columns {
+			if widths[i] > 0 && (cut < 0 || column.Priority <= t.Columns[cut].Priority) {
+				cut = i
+			}
+		}
+		switch {
+		case total() == widths[cut]:
+			// The last column left is cut short rather than left out
+			widths[cut] = max(width, 0)
+		case widths[cut] > t.Columns[cut].MinWidth:
+			widths[cut] = max(widths[cut]-(total()-width), t.Columns[cut].MinWidth)
+		default:
+			widths[cut] = 0
+		}
+	}
+
+	for i, column := range t.Columns {
+		if column.Expand {
+			widths[i] += max(width-total(), 0)
+			break
+		}
+	}
+	return widths
+}
+
+// Draw draws the header, when shown, and as many rows from Offset as fit
+func (t *Table) Draw(screen tcell.Screen, x, y, width, height int) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	widths := t.Widths(width)
+
+	if t.Header {
+		titles := make([]string, len(t.Columns))
+		styles := make([]tcell.Style, len(t.Columns))
+		for i := range t.Columns {
+			titles[i], styles[i] = t.title(i), tcell.StyleDefault.Bold(true)
+		}
+		t.drawRow(screen, x, y, width, widths, titles, styles)
+		y++
+		height--
+	}
+
+	for i := t.Offset; i >= 0 && i < len(t.Rows) && i-t.Offset < height; i++ {
+		row := t.Rows[i]
+		styles := make([]tcell.Style, len(t.Columns))
+		for j, column := range t.Columns {
+			styles[j] = row.Style
+			if column.Style != tcell.StyleDefault {
+				styles[j] = column.Style
+			}
+			if i == t.Selected {
+				styles[j] = styles[j].Background(selectedRowBackground)
+			}
+		}
+		if i == t.Selected {
+			for col := x; col < x+width; col++ {
+				screen.SetContent(col, y+i-t.Offset, ' ', nil, tcell.StyleDefault.Background(selectedRowBackground))
+			}
+		}
+		t.drawRow(screen, x, y+i-t.Offset, width, widths, row.Cells, styles)
+	}
+}
+
+// drawRow draws cells in columns of the given widths, each in its style
+func (t *Table) drawRow(screen tcell.Screen, x, y, width int, widths []int, cells []string, styles []tcell.Style) {
+	col := x
+	for i, column := range t.Columns {
+		if widths[i] == 0 {
+			continue
+		}
+		text := ""
+		if i < len(cells) {
+			text = cells[i]
+		}
+		fitted := fitCells(textCells(text, styles[i]), widths[i], column.Align, styles[i])
+		drawCells(screen, col, y, min(widths[i], x+width-col), fitted, 0, styles[i])
+		col += widths[i] + tableGap
+	}
+}
+
+// fitCells cuts cells to width, ending them with an ellipsis when cut, and
+// pads them on the side opposite to their alignment
+func fitCells(cells []cell, width int, align Alignment, style tcell.Style) []cell {
+	if cellsWidth(cells) > width {
+		kept, used := cells[:0:0], 0
+		for _, c := range cells {
+			if used+runeColumns(c.ch) > width-1 {
+				break
+			}
+			kept = append(kept, c)
+			used += runeColumns(c.ch)
+		}
+		cells = append(kept, cell{'…', style})
+	}
+	if align == AlignRight {
+		padding := make([]cell, 0, width)
+		for i := cellsWidth(cells); i < width; i++ {
+			padding = append(padding, cell{' ', style})
+		}
+		return append(padding, cells...)
+	}
+	return padCells(cells, width, style)
+}
+
+// SortBy sorts the rows by a column, the other way round when they
+// already are sorted by it. The selected row is kept selected.
+func (t *Table) SortBy(column int) {
+	if column < 0 || column >= len(t.Columns) {
+		return
+	}
+	t.SortDesc = column == t.SortColumn && !t.SortDesc
+	t.SortColumn = column
+	t.Sort()
+}
+
+// Sort sorts the rows as SortColumn and SortDesc say
+func (t *Table) Sort() {
+	if t.SortColumn < 0 || t.SortColumn >= len(t.Columns) {
+		return
+	}
+	less := t.Columns[t.SortColumn].Less
+	if less == nil {
+		less = func(a, b string) bool { return strings.ToLower(a) < strings.ToLower(b) }
+	}
+	key := func(row TableRow) string {
+		if t.SortColumn < len(row.Cells) {
+			return row.Cells[t.SortColumn]
+		}
+		return ""
+	}
+
+	order := make([]int, len(t.Rows))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := key(t.Rows[order[i]]), key(t.Rows[order[j]])
+		if t.SortDesc {
+			return less(b, a)
+		}
+		return less(a, b)
+	})
+
+	rows := make([]TableRow, len(t.Rows))
+	selected := -1
+	for i, from := range order {
+		rows[i] = t.Rows[from]
+		if from == t.Selected {
+			selected = i
+		}
+	}
+	t.Rows, t.Selected = rows, selected
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableWidths(t *testing.T) {
+	table := NewTable(
+		TableColumn{Title: "Name", Priority: 2, Expand: true},
+		TableColumn{Title: "Size", Align: AlignRight, MinWidth: 3},
+		TableColumn{Title: "Commit", Priority: 1, MinWidth: 7},
+	)
+	table.Header = true
+	table.Rows = []TableRow{
+		{Cells: []string{"README.md", "12345", "abcdef1"}},
+		{Cells: []string{"a-rather-long-name.go", "7", "1234567"}},
+	}
+
+	// Columns take the width of their content, the expanding one the rest
+	assert.Equal(t, []int{21, 5, 7}, table.Widths(35))
+	assert.Equal(t, []int{27, 5, 7}, table.Widths(41))
+
+	// The least important columns are cut down, then left out, before the
+	// others are touched
+	assert.Equal(t, []int{21, 3, 7}, table.Widths(33))
+	assert.Equal(t, []int{22, 0, 7}, table.Widths(30))
+	assert.Equal(t, []int{28, 0, 0}, table.Widths(28))
+	assert.Equal(t, []int{10, 0, 0}, table.Widths(10))
+}
+
+func TestTableDraw(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	screen.SetSize(30, 5)
+
+	table := NewTable(
+		TableColumn{Title: "Name", Expand: true},
+		TableColumn{Title: "Size", Align: AlignRight, Priority: 1, Less: func(a, b string) bool {
+			x, _ := strconv.Atoi(a)
+			y, _ := strconv.Atoi(b)
+			return x < y
+		}},
+	)
+	table.Header = true
+	table.Rows = []TableRow{
+		{Cells: []string{"b.txt", "10"}},
+		{Cells: []string{"a-file-with-a-very-long-name.txt", "9"}},
+		{Cells: []string{"c.txt", "100"}},
+	}
+	table.Selected = 1
+
+	// Sorting by a column marks its header and keeps the selection
+	table.SortBy(1)
+	assert.Equal(t, 0, table.Selected)
+	table.SortBy(1)
+	assert.Equal(t, 2, table.Selected)
+	table.Offset = 1
+	table.Draw(screen, 0, 0, 20, 3)
+	screen.Show()
+
+	assert.Equal(t, []string{
+		"Name          Size ▼",
+		"b.txt             10",
+		"a-file-with-…      9",
+	}, screenLines(screen)[:3])
+}
@@ -2,18 +2,22 @@ package ui
 
 import (
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/azhao1981/tig/internal/config"
 	"github.com/azhao1981/tig/internal/git"
+	"github.com/rivo/uniseg"
 )
 
 type Terminal struct {
 	screen          tcell.Screen
 	width           int
 	height          int
-	running         bool
+	running         atomic.Bool // Set by the event loop, read by the goroutines feeding it
+	done            chan struct{} // Closed once the event loop stopped
 	eventCh         chan tcell.Event
 	viewManager     *ViewManager
 	lastUpdate      time.Time
@@ -21,6 +25,14 @@ type Terminal struct {
 	keyBindingMgr   *KeyBindingManager
 	commandMgr      *CommandManager
 	commandMode     bool
+	palette         *Palette // Command palette, nil while closed
+	paste           pasteBuffer // Keys of the bracketed paste under way
+	config          *config.Config
+	refreshInterval atomic.Int64 // Seconds between refreshes, 0 to disable
+	windowTitle     string // Title last set with setTerminalTitle
+	recorder        *SessionRecorder
+	replay          []SessionEvent
+	recent          *config.RecentRepos
 }
 
 func NewTerminal() (*Terminal, error) {
@@ -28,7 +40,12 @@ func NewTerminal() (*Terminal, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create screen: %w", err)
 	}
+	return NewTerminalWithScreen(screen)
+}
 
+// NewTerminalWithScreen creates a terminal drawing on the given screen, such
+// as a simulation screen for tests
+func NewTerminalWithScreen(screen tcell.Screen) (*Terminal, error) {
 	if err := screen.Init(); err != nil {
 		return nil, fmt.Errorf("failed to initialize screen: %w", err)
 	}
@@ -39,7 +56,6 @@ func NewTerminal() (*Terminal, error) {
 		screen:     screen,
 		width:      width,
 		height:     height,
-		running:    false,
 		eventCh:    make(chan tcell.Event, 10),
 		lastUpdate: time.Now(),
 	}
@@ -48,11 +64,18 @@ func NewTerminal() (*Terminal, error) {
 	return terminal, nil
 }
 
+// SetRecentRepos sets the recently opened repositories offered when
+// starting outside of a repository
+func (t *Terminal) SetRecentRepos(recent *config.RecentRepos) {
+	t.recent = recent
+}
+
 func (t *Terminal) setupScreen() {
 	defaultStyle := tcell.StyleDefault
 	t.screen.SetStyle(defaultStyle)
 	t.screen.Clear()
 	t.screen.HideCursor()
+	t.screen.EnablePaste()
 }
 
 func (t *Terminal) Close() error {
@@ -63,8 +86,11 @@ func (t *Terminal) Close() error {
 }
 
 func (t *Terminal) Run(cfg *config.Config, client git.Client, repoPath string) error {
+	t.config = cfg
+
 	// Initialize theme
-	t.theme = NewTheme(cfg)
+	t.theme = newSchemeTheme(cfg)
+	t.refreshInterval.Store(int64(cfg.General.RefreshInterval))
 
 	// Initialize key binding manager
 	t.keyBindingMgr = NewKeyBindingManager(cfg)
@@ -75,13 +101,43 @@ func (t *Terminal) Run(cfg *config.Config, client git.Client, repoPath string) e
 	// Initialize view manager
 	t.viewManager = NewViewManager(t.screen, cfg, client, t.keyBindingMgr)
 	t.viewManager.SetSize(t.width, t.height)
+	t.viewManager.SetRecentRepos(t.recent)
 	t.viewManager.SetRepoPath(repoPath)
 
+	// Git and ssh prompt for credentials in tig instead of the terminal
+	if stop, err := t.viewManager.listenAskPass(); err == nil {
+		defer stop()
+	}
+
+	// Bind commands which need access to the views
+	t.registerCommands()
+
 	// Initial refresh of all views
 	t.viewManager.RefreshAll()
 
-	t.running = true
-	defer func() { t.running = false }()
+	// Point out tigrc problems without refusing to start
+	if n := len(cfg.Warnings); n == 1 {
+		t.viewManager.SetMessage("%s", cfg.Warnings[0])
+	} else if n > 1 {
+		t.viewManager.SetMessage("%s (and %d more tigrc errors)", cfg.Warnings[0], n-1)
+	}
+
+	// Editors and other programs drive tig through the control socket,
+	// until the event loop stops
+	t.done = make(chan struct{})
+	defer close(t.done)
+	if path, err := ControlSocket(cfg, client, repoPath); err != nil {
+		t.viewManager.SetMessage("No control socket: %v", err)
+	} else if path != "" {
+		if stop, err := t.listenControl(path); err != nil {
+			t.viewManager.SetMessage("No control socket: %v", err)
+		} else {
+			defer stop()
+		}
+	}
+
+	t.running.Store(true)
+	defer t.running.Store(false)
 
 	// Initial draw
 	t.draw()
@@ -89,10 +145,14 @@ func (t *Terminal) Run(cfg *config.Config, client git.Client, repoPath string) e
 	// Start event loop
 	go t.pollEvents()
 
-	// Start periodic refresh
-	go t.periodicRefresh()
+	// Start periodic refresh, which also watches the tigrc for changes
+	go t.periodicRefresh(cfg.Path)
+
+	if t.replay != nil {
+		go t.replayEvents(t.replay)
+	}
 
-	for t.running {
+	for t.running.Load() {
 		select {
 		case ev := <-t.eventCh:
 			if err := t.handleEvent(ev); err != nil {
@@ -105,7 +165,7 @@ func (t *Terminal) Run(cfg *config.Config, client git.Client, repoPath string) e
 }
 
 func (t *Terminal) pollEvents() {
-	for t.running {
+	for t.running.Load() {
 		ev := t.screen.PollEvent()
 		if ev != nil {
 			t.eventCh <- ev
@@ -113,41 +173,136 @@ func (t *Terminal) pollEvents() {
 	}
 }
 
-func (t *Terminal) periodicRefresh() {
-	refreshTicker := time.NewTicker(5 * time.Second)
+// redraw is posted to the event loop for the screen to be drawn again from
+// there, the views being drawn only between events
+type redraw struct{}
+
+// requestDraw asks the event loop to draw the screen again. The request is
+// dropped while the event queue is full, as it will be drawn anyway.
+func (t *Terminal) requestDraw() {
+	_ = t.screen.PostEvent(tcell.NewEventInterrupt(redraw{}))
+}
+
+// periodicRefresh refreshes the current view at the configured interval
+// and asks the event loop to reload the configuration when the tigrc file
+// it was loaded from changes
+func (t *Terminal) periodicRefresh(configPath string) {
+	refreshTicker := time.NewTicker(time.Second)
 	defer refreshTicker.Stop()
 
-	for t.running {
+	modTime := configModTime(configPath)
+	elapsed := int64(0)
+
+	for t.running.Load() {
 		select {
 		case <-refreshTicker.C:
+			if configPath != "" {
+				if mtime := configModTime(configPath); !mtime.Equal(modTime) {
+					modTime = mtime
+					t.eventCh <- tcell.NewEventInterrupt(configReload{})
+				}
+			}
+
 			if t.viewManager != nil {
-				t.viewManager.RefreshCurrent()
-				t.draw()
+				t.viewManager.AutoFetchIfDue()
+			}
+
+			elapsed++
+			interval := t.refreshInterval.Load()
+			// Refs changing alone are caught in between refreshes
+			if interval > 0 && elapsed < interval && t.viewManager != nil && t.viewManager.RefreshRefs() {
+				t.requestDraw()
+				continue
+			}
+			if interval <= 0 || elapsed < interval {
+				// Relative dates are redrawn as they age, and the
+				// progress of remote jobs as it spins, without reloading
+				// the view
+				if t.viewManager != nil && (t.viewManager.ShowsRelativeDates() || t.viewManager.ShowsProgress()) {
+					t.requestDraw()
+				}
+				continue
+			}
+			elapsed = 0
+			if t.viewManager != nil && t.viewManager.AutoRefresh() {
+				t.requestDraw()
 			}
 		}
 	}
 }
 
 func (t *Terminal) handleEvent(ev tcell.Event) error {
+	if t.recorder != nil && !t.secretInput(ev) {
+		t.recorder.recordEvent(ev)
+	}
+
 	switch ev := ev.(type) {
 	case *tcell.EventKey:
+		if t.paste.active {
+			t.addPastedKey(ev)
+			return nil
+		}
 		return t.handleKeyEvent(ev)
+	case *tcell.EventPaste:
+		return t.handlePasteEvent(ev)
 	case *tcell.EventResize:
 		return t.handleResizeEvent(ev)
 	case *tcell.EventMouse:
 		return t.handleMouseEvent(ev)
+	case *tcell.EventInterrupt:
+		switch data := ev.Data().(type) {
+		case redraw:
+			t.draw()
+		case configReload:
+			t.reloadConfig()
+			t.draw()
+		case driverCall:
+			data()
+		case jobEvent:
+			data()
+			t.draw()
+		case controlCall:
+			data.reply <- t.runControl(data.line)
+			t.draw()
+		}
 	}
 	return nil
 }
 
 func (t *Terminal) handleKeyEvent(ev *tcell.EventKey) error {
+	if t.palette != nil {
+		return t.handlePaletteKey(ev)
+	}
+
+	// A pending confirmation takes any key, even Esc, as its answer
+	if t.viewManager != nil && t.viewManager.Confirming() {
+		t.viewManager.HandleKey(ev.Key(), ev.Rune(), ev.Modifiers())
+		t.draw()
+		return nil
+	}
+
 	// Handle command mode
 	if t.commandMode {
+		// Enter on an empty prompt offers the commands to pick from
+		if ev.Key() == tcell.KeyEnter && t.commandMgr.GetBuffer() == "" {
+			t.commandMgr.StopCommandMode()
+			t.commandMode = false
+			t.openPalette()
+			t.draw()
+			return nil
+		}
+
+		if ev.Key() == tcell.KeyCtrlV {
+			t.pasteClipboard()
+			t.draw()
+			return nil
+		}
+
 		if handled := t.commandMgr.HandleKey(ev.Key(), ev.Rune(), ev.Modifiers()); handled {
 			if ev.Key() == tcell.KeyEnter {
 				// Execute command
 				if err := t.executeCommand(); err != nil {
-					// TODO: Show error message
+					t.viewManager.SetMessage("%v", err)
 				}
 				t.commandMode = false
 				t.draw()
@@ -163,6 +318,11 @@ func (t *Terminal) handleKeyEvent(ev *tcell.EventKey) error {
 		return nil
 	}
 
+	// Any new key press dismisses the previous message
+	if t.viewManager != nil {
+		t.viewManager.ClearMessage()
+	}
+
 	// Handle command mode activation
 	if ev.Rune() == ':' {
 		t.commandMode = true
@@ -174,21 +334,33 @@ func (t *Terminal) handleKeyEvent(ev *tcell.EventKey) error {
 	// Handle global keys
 	switch ev.Key() {
 	case tcell.KeyEsc, tcell.KeyCtrlC:
-		t.running = false
+		t.running.Store(false)
 		return nil
 	case tcell.KeyCtrlL:
 		t.screen.Sync()
 		t.viewManager.RefreshAll()
 		t.draw()
 		return nil
+	case tcell.KeyCtrlP:
+		t.openPalette()
+		t.draw()
+		return nil
 	}
 
 	// Handle view-specific key events
 	if t.viewManager != nil {
 		if handled := t.viewManager.HandleKey(ev.Key(), ev.Rune(), ev.Modifiers()); handled {
+			// Actions needing more input prompt for it on the command line
+			if request := t.viewManager.TakeCommandRequest(); request != "" {
+				t.commandMode = true
+				t.commandMgr.StartCommandModeWith(request)
+			}
 			t.draw()
 			return nil
 		}
+		if t.viewManager.QuitRequested() {
+			t.running.Store(false)
+		}
 	}
 
 	return nil
@@ -217,11 +389,413 @@ func (t *Terminal) draw() {
 	// Render current view
 	t.viewManager.Render()
 	t.lastUpdate = time.Now()
+	t.setTerminalTitle(t.viewManager.GetTitle())
+
+	if t.palette != nil {
+		t.palette.Draw(t.screen, t.width, t.height)
+	}
 
-	// Render command line if active
+	// Render command line if active, otherwise any pending message
 	if t.commandMode {
 		t.drawCommandLine()
+	} else {
+		t.drawMessage()
+	}
+
+	t.screen.Show()
+
+	if t.recorder != nil {
+		t.recorder.recordFrame(t.screen)
+	}
+}
+
+// drawMessage draws the status line message at the bottom of the screen
+func (t *Terminal) drawMessage() {
+	message := t.viewManager.GetMessage()
+	if message == "" {
+		return
+	}
+
+	msgY := t.height - 1
+	style := tcell.StyleDefault.Background(tcell.ColorDarkGray).Foreground(tcell.ColorWhite)
+	for x := 0; x < t.width; x++ {
+		t.screen.SetContent(x, msgY, ' ', nil, style)
 	}
+	t.drawText(0, msgY, style, message)
+}
+
+// registerCommands binds commands whose handlers need the view manager
+func (t *Terminal) registerCommands() {
+	t.commandMgr.SetReadOnly(func() bool { return t.config.General.ReadOnly })
+
+	for _, name := range []string{"add", "reset"} {
+		if cmd, ok := t.commandMgr.Get(name); ok {
+			cmd.Complete = t.viewManager.CompletePath
+		}
+	}
+
+	t.commandMgr.Register(&Command{
+		Name:        "pager",
+		Description: "Open view content or git command output in the external pager",
+		Handler:     t.viewManager.PagerCommand,
+		Usage:       "pager [git-args...]",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "diff",
+		Description: "Show the diff of a commit or of a range",
+		Handler:     t.viewManager.DiffCommand,
+		Usage:       "diff [rev|rev1..rev2]",
+		Complete:    t.viewManager.CompleteRevision,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "show",
+		Description: "Show the diff of a commit",
+		Handler:     t.viewManager.ShowCommand,
+		Usage:       "show [commit] <rev>",
+		Complete:    t.viewManager.CompleteRevision,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "focus",
+		Description: "Show a view and draw attention to tig, as another tig started for the repository does",
+		Handler:     t.viewManager.FocusCommand,
+		Usage:       "focus [main|status|refs|stash|review]",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "blame",
+		Description: "Show the diff of the commit which last changed a line of a file",
+		Handler:     t.viewManager.BlameCommand,
+		Usage:       "blame <file>[:<line>]",
+		Complete:    t.viewManager.CompletePath,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "log",
+		Description: "Show the log, selecting the given commit",
+		Handler:     t.viewManager.LogCommand,
+		Usage:       "log [rev]",
+		Complete:    t.viewManager.CompleteRevision,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "log-filter",
+		Description: "Filter the log by author, path, date range and branch, showing the whole log without filters",
+		Handler:     t.viewManager.LogFilterCommand,
+		Usage:       "log-filter [author=<pattern>] [path=<path>] [since=<date>] [until=<date>] [branch=<rev>]",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "log-view",
+		Description: "Open a saved log view, picking it from a list without a name",
+		Handler:     t.viewManager.LogViewCommand,
+		Usage:       "log-view [name]",
+		Complete:    t.viewManager.CompleteLogView,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "save-log-view",
+		Description: "Save the filters of the log under a name in the user's tigrc",
+		Handler:     t.viewManager.SaveLogViewCommand,
+		Usage:       "save-log-view <name>",
+		Complete:    t.viewManager.CompleteLogView,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "audit-log",
+		Description: "Open the changes made to the repository through tig in the pager",
+		Handler:     t.viewManager.AuditLogCommand,
+		Usage:       "audit-log",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "diffstat",
+		Description: "Show the diffstat between two revisions",
+		Handler:     t.viewManager.DiffStatCommand,
+		Usage:       "diffstat <rev1>..<rev2>",
+		Complete:    t.viewManager.CompleteRevision,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "range-diff",
+		Description: "Compare two versions of a patch series",
+		Handler:     t.viewManager.RangeDiffCommand,
+		Usage:       "range-diff <old-range> <new-range>",
+		Complete:    t.viewManager.CompleteRevision,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "backport",
+		Description: "Cherry-pick a commit or stash onto another branch",
+		Handler:     t.viewManager.BackportCommand,
+		Usage:       "backport <branch> [commit|stash@{n}]",
+		Mutating:    true,
+		Complete:    t.viewManager.CompleteRevision,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "checkout",
+		Description: "Check out a branch or revision, offering to stash local changes in the way, or start an orphan branch",
+		Handler:     t.viewManager.CheckoutCommand,
+		Usage:       "checkout [--orphan] <branch|rev>",
+		Mutating:    true,
+		Complete:    t.viewManager.CompleteRevision,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "branch",
+		Description: "Create, rename or delete a branch",
+		Handler:     t.viewManager.BranchCommand,
+		Usage:       "branch <name> [start] | -m <branch> <new-name> | -d|-D <branch>",
+		Mutating:    true,
+		Complete:    t.viewManager.CompleteRevision,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "rebase",
+		Description: "Rebase the current branch, offering to stash local changes first",
+		Handler:     t.viewManager.RebaseCommand,
+		Usage:       "rebase <upstream>",
+		Mutating:    true,
+		Complete:    t.viewManager.CompleteRevision,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "reset-branch",
+		Description: "Reset the current branch to a revision, once confirmed",
+		Handler:     t.viewManager.ResetBranchCommand,
+		Usage:       "reset-branch [--soft|--mixed|--hard] <rev>",
+		Mutating:    true,
+		Complete:    t.viewManager.CompleteRevision,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "undo-discard",
+		Description: "Restore the file whose changes were discarded last",
+		Handler:     t.viewManager.UndoDiscardCommand,
+		Usage:       "undo-discard",
+		Mutating:    true,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "autosquash",
+		Description: "Squash fixup commits into the commits they fix",
+		Handler:     t.viewManager.AutosquashCommand,
+		Usage:       "autosquash",
+		Mutating:    true,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "!",
+		Description: "Run an external command with %(commit), %(file), ... expanded",
+		Handler:     t.viewManager.ExternalCommand,
+		Usage:       "!<command> [args...]",
+		Mutating:    true,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "set",
+		Description: "Show or change an option, such as tab-size or theme",
+		Handler:     t.setCommand,
+		Usage:       "set <option> [=] [value]",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "save-config",
+		Description: "Write the current configuration to the user's tigrc",
+		Handler:     t.saveConfigCommand,
+		Usage:       "save-config [file]",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "export-keys",
+		Description: "Write the key bindings to a file as tigrc bind lines, or as markdown for .md files",
+		Handler:     t.exportKeysCommand,
+		Usage:       "export-keys <file>",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "search",
+		Description: "Search commits by message or author",
+		Handler:     t.viewManager.SearchCommand,
+		Usage:       "search [pattern]",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "refs-filter",
+		Description: "Show the refs whose name matches a glob or substring, all of them without one",
+		Handler:     t.viewManager.RefsFilterCommand,
+		Usage:       "refs-filter [pattern]",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "status-filter",
+		Description: "Show the files of the status view matching a glob or substring, all of them without one",
+		Handler:     t.viewManager.StatusFilterCommand,
+		Usage:       "status-filter [pattern]",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "maintenance",
+		Description: "Show repository statistics, or run git gc, repack or prune",
+		Handler:     t.viewManager.MaintenanceCommand,
+		Usage:       "maintenance [gc|repack|prune]",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "large-objects",
+		Description: "Find the largest blobs of the history and the commits adding them",
+		Handler:     t.viewManager.LargeObjectsCommand,
+		Usage:       "large-objects [count]",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "fsck",
+		Description: "Check the integrity of the repository with git fsck",
+		Handler:     t.viewManager.FsckCommand,
+		Usage:       "fsck",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "stash",
+		Description: "List the stashes, or stash the local changes and apply, pop or drop a stash",
+		Handler:     t.viewManager.StashCommand,
+		Usage:       "stash [push [--include-untracked] [message]|apply|pop|drop [stash@{n}]]",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "commit",
+		Description: "Commit the staged changes with the given message",
+		Handler:     t.viewManager.CommitCommand,
+		Usage:       "commit <message>",
+		Mutating:    true,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "tree",
+		Description: "Browse the files of a revision, or of the index without one",
+		Handler:     t.viewManager.TreeCommand,
+		Usage:       "tree [rev]",
+		Complete:    t.viewManager.CompleteRevision,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "grep",
+		Description: "Search the tracked files by content, showing the lines around the matches",
+		Handler:     t.viewManager.GrepCommand,
+		Usage:       "grep [-i] <pattern> [-- <paths>...]",
+		Complete:    t.viewManager.CompletePath,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "review",
+		Description: "List the review notes, write them as markdown or delete them",
+		Handler:     t.viewManager.ReviewCommand,
+		Usage:       "review [export <file>|clear]",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "review-note",
+		Description: "Leave a review note on the hunk or file under the cursor of the diff view",
+		Handler:     t.viewManager.ReviewNoteCommand,
+		Usage:       "review-note <text>",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "compare",
+		Description: "Compare the current branch with another one",
+		Handler:     t.viewManager.CompareCommand,
+		Usage:       "compare <branch>",
+		Complete:    t.viewManager.CompleteRevision,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "format-patch",
+		Description: "Write the commits of the visual range, or the selected commit, as patches",
+		Handler:     t.viewManager.FormatPatchCommand,
+		Usage:       "format-patch <directory>",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "merge-base",
+		Description: "Select the merge base of the selected commit and a revision",
+		Handler:     t.viewManager.MergeBaseCommand,
+		Usage:       "merge-base <rev>",
+		Complete:    t.viewManager.CompleteRevision,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "init",
+		Description: "Create a repository in the current directory",
+		Handler:     t.viewManager.InitCommand,
+		Usage:       "init [branch]",
+		Mutating:    true,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "clone",
+		Description: "Clone a repository and open it",
+		Handler:     t.viewManager.CloneCommand,
+		Usage:       "clone <url> [directory]",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "clear-search",
+		Description: "Forget the results of the last search",
+		Handler:     t.viewManager.ClearSearchCommand,
+		Usage:       "clear-search",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "cherry-pick",
+		Description: "Apply the changes of a commit to the current branch",
+		Handler:     t.viewManager.CherryPickCommand,
+		Usage:       "cherry-pick [commit]",
+		Mutating:    true,
+		Complete:    t.viewManager.CompleteRevision,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "revert",
+		Description: "Undo the changes of a commit on the current branch",
+		Handler:     t.viewManager.RevertCommand,
+		Usage:       "revert [commit]",
+		Mutating:    true,
+		Complete:    t.viewManager.CompleteRevision,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "fetch",
+		Description: "Fetch all remotes",
+		Handler:     t.viewManager.FetchCommand,
+		Usage:       "fetch",
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "pull",
+		Description: "Fast-forward the current branch to its upstream",
+		Handler:     t.viewManager.PullCommand,
+		Usage:       "pull",
+		Mutating:    true,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "push",
+		Description: "Push the selected branch or tag, or the current branch, to a remote",
+		Handler:     t.viewManager.PushCommand,
+		Usage:       "push [--force] [remote]",
+		Mutating:    true,
+	})
+
+	t.commandMgr.Register(&Command{
+		Name:        "delete-remote",
+		Description: "Delete the selected branch or tag from a remote",
+		Handler:     t.viewManager.DeleteRemoteCommand,
+		Usage:       "delete-remote [remote]",
+		Mutating:    true,
+	})
 }
 
 func (t *Terminal) drawWelcome() {
@@ -289,25 +863,22 @@ func (t *Terminal) drawCommandLine() {
 		t.screen.SetContent(x, cmdY, ' ', nil, tcell.StyleDefault.Background(tcell.ColorBlue).Foreground(tcell.ColorWhite))
 	}
 
-	// Draw the command prompt
+	// Draw the command prompt, scrolled left when the cursor would be past
+	// the right edge
 	prompt := ":"
 	cmdBuffer := t.commandMgr.GetBuffer()
-	cursorPos := t.commandMgr.GetCursor()
-
-	fullText := prompt + cmdBuffer
-	if len(fullText) > t.width {
-		// Truncate if too long
-		start := len(fullText) - t.width + 1
-		if start < 0 {
-			start = 0
+	cursorPos := promptColumns(prompt + cmdBuffer[:t.commandMgr.GetCursor()])
+	offset := max(0, cursorPos-t.width+1)
+	cursorPos -= offset
+	drawPrompt(t.screen, cmdY, t.width, prompt+cmdBuffer, offset, tcell.StyleDefault.Background(tcell.ColorBlue).Foreground(tcell.ColorWhite))
+
+	// Offer the candidates of an ambiguous completion above the prompt
+	if suggestions := t.commandMgr.GetSuggestions(); len(suggestions) > 0 && cmdY > 0 {
+		style := tcell.StyleDefault.Background(tcell.ColorDarkGray).Foreground(tcell.ColorWhite)
+		for x := 0; x < t.width; x++ {
+			t.screen.SetContent(x, cmdY-1, ' ', nil, style)
 		}
-		fullText = fullText[start:]
-		cursorPos = len(prompt) + t.commandMgr.GetCursor() - start
-	}
-
-	// Draw the text
-	for i, r := range fullText {
-		t.screen.SetContent(i, cmdY, r, nil, tcell.StyleDefault.Background(tcell.ColorBlue).Foreground(tcell.ColorWhite))
+		t.drawText(0, cmdY-1, style, strings.Join(suggestions, "  "))
 	}
 
 	// Position the cursor
@@ -318,6 +889,33 @@ func (t *Terminal) drawCommandLine() {
 	t.screen.ShowCursor(cursorX, cmdY)
 }
 
+// promptColumns returns the number of columns text takes on the prompt
+func promptColumns(text string) int {
+	columns, state := 0, -1
+	for text != "" {
+		var cluster string
+		cluster, text, _, state = uniseg.FirstGraphemeClusterInString(text, state)
+		columns += runeColumns([]rune(cluster)[0])
+	}
+	return columns
+}
+
+// drawPrompt draws the prompt line from the given column on, characters
+// made of several runes, such as accented letters, taking a single cell
+func drawPrompt(screen tcell.Screen, y, width int, text string, offset int, style tcell.Style) {
+	col, state := -offset, -1
+	for text != "" && col < width {
+		var cluster string
+		cluster, text, _, state = uniseg.FirstGraphemeClusterInString(text, state)
+		runes := []rune(cluster)
+		w := runeColumns(runes[0])
+		if col >= 0 && col+w <= width {
+			screen.SetContent(col, y, runes[0], runes[1:], style)
+		}
+		col += w
+	}
+}
+
 func (t *Terminal) executeCommand() error {
 	if !t.commandMode {
 		return nil
@@ -1,9 +1,12 @@
 package ui
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
 	"github.com/gdamore/tcell/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -67,3 +70,78 @@ func TestTerminalMethods(t *testing.T) {
 	assert.Equal(t, 80, w)
 	assert.Equal(t, 24, h)
 }
+
+// newTestTerminal wires up a terminal on a simulation screen without
+// starting its event loop
+func newTestTerminal(t *testing.T, cfg *config.Config) *Terminal {
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	screen.SetSize(80, 24)
+
+	term := &Terminal{screen: screen, width: 80, height: 24, config: cfg}
+	term.keyBindingMgr = NewKeyBindingManager(cfg)
+	term.commandMgr = NewCommandManager()
+	term.viewManager = NewViewManager(screen, cfg, git.NewClient(), term.keyBindingMgr)
+	term.viewManager.SetSize(80, 24)
+	term.registerCommands()
+	return term
+}
+
+func TestSetCommand(t *testing.T) {
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	term := newTestTerminal(t, cfg)
+
+	assert.NoError(t, term.setCommand([]string{"tab-size", "=", "4"}))
+	assert.Equal(t, 4, cfg.UI.TabSize)
+	assert.NoError(t, term.setCommand([]string{"show-author", "no"}))
+	assert.False(t, cfg.Views.Main.ShowAuthor)
+	assert.NoError(t, term.setCommand([]string{"refresh-interval=30"}))
+	assert.Equal(t, int64(30), term.refreshInterval.Load())
+
+	assert.NoError(t, term.setCommand([]string{"theme", "dark"}))
+	assert.Equal(t, "dark", cfg.Colors.Scheme)
+	assert.Error(t, term.setCommand([]string{"theme", "neon"}))
+
+	// Without a value the current one is shown
+	assert.NoError(t, term.setCommand([]string{"tab-size"}))
+	assert.Equal(t, "tab-size = 4", term.viewManager.GetMessage())
+
+	assert.Error(t, term.setCommand(nil))
+	assert.Error(t, term.setCommand([]string{"tab-size", "wide"}))
+	assert.Error(t, term.setCommand([]string{"no-such-option", "1"}))
+}
+
+func TestReloadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tigrc")
+	require.NoError(t, os.WriteFile(path, []byte("set tab-size = 4\n"), 0644))
+	cfg, err := config.LoadPath(path)
+	require.NoError(t, err)
+	term := newTestTerminal(t, cfg)
+
+	require.NoError(t, os.WriteFile(path, []byte("set tab-size = 2\nbind generic x !true\n"), 0644))
+	term.reloadConfig()
+	assert.Equal(t, 2, cfg.UI.TabSize)
+	assert.Equal(t, "Reloaded "+path, term.viewManager.GetMessage())
+
+	action, ok := term.keyBindingMgr.MatchEvent(tcell.KeyRune, 'x', 0)
+	assert.True(t, ok)
+	assert.Equal(t, "!true", action)
+}
+
+func TestSaveConfigCommand(t *testing.T) {
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	term := newTestTerminal(t, cfg)
+
+	require.NoError(t, term.setCommand([]string{"tab-size", "3"}))
+	path := filepath.Join(t.TempDir(), "tigrc")
+	assert.NoError(t, term.saveConfigCommand([]string{path}))
+	assert.Equal(t, "Saved configuration to "+path, term.viewManager.GetMessage())
+
+	saved, err := config.LoadPath(path)
+	require.NoError(t, err)
+	assert.Equal(t, 3, saved.UI.TabSize)
+
+	assert.Error(t, term.saveConfigCommand([]string{"a", "b"}))
+}
@@ -64,6 +64,8 @@ func (t *Theme) setDefaults() {
 		"diff-del":      tcell.ColorRed,
 		"branch":        tcell.ColorFuchsia,
 		"tag":           tcell.ColorYellow,
+		"remote":        tcell.ColorGreen,
+		"head":          tcell.ColorAqua,
 		"author":        tcell.ColorAqua,
 		"date":          tcell.ColorGreen,
 		"id":            tcell.ColorBlue,
@@ -191,6 +193,8 @@ func LoadScheme(schemeName string) *Theme {
 			"diff-del":      "red",
 			"branch":        "magenta",
 			"tag":           "yellow",
+			"remote":        "green",
+			"head":          "cyan",
 			"author":        "cyan",
 			"date":          "green",
 			"id":            "blue",
@@ -215,6 +219,8 @@ func LoadScheme(schemeName string) *Theme {
 			"diff-del":      "red",
 			"branch":        "magenta",
 			"tag":           "brown",
+			"remote":        "green",
+			"head":          "blue",
 			"author":        "blue",
 			"date":          "green",
 			"id":            "blue",
@@ -239,6 +245,8 @@ func LoadScheme(schemeName string) *Theme {
 			"diff-del":      "white",
 			"branch":        "white",
 			"tag":           "white",
+			"remote":        "white",
+			"head":          "white",
 			"author":        "white",
 			"date":          "white",
 			"id":            "white",
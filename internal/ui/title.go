@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+)
+
+// formatTitle describes the repository and the state of its current branch,
+// like "tig main* ↑1 ↓2" for a dirty branch which diverged from upstream
+func formatTitle(repo string, status *git.BranchStatus) string {
+	parts := []string{repo}
+	if status == nil {
+		return repo
+	}
+
+	branch := status.Branch
+	if branch == "" {
+		branch = "(detached)"
+	}
+	if status.Dirty {
+		branch += "*"
+	}
+	parts = append(parts, branch)
+
+	if status.Ahead > 0 {
+		parts = append(parts, fmt.Sprintf("↑%d", status.Ahead))
+	}
+	if status.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("↓%d", status.Behind))
+	}
+	return strings.Join(parts, " ")
+}
+
+// updateTitle recomputes the title bar from the repository state, taken
+// from the status the status view caches so that the periodic refresh
+// doesn't run git status for the title alone
+func (vm *ViewManager) updateTitle() {
+	vm.branchStatus = nil
+	if !vm.client.IsRepository() {
+		vm.title = ""
+		return
+	}
+
+	if statusView, ok := vm.views[ViewTypeStatus].(*StatusView); ok {
+		if status, err := statusView.loadStatus(); err == nil {
+			vm.branchStatus = status.BranchStatus()
+		}
+	} else if status, err := vm.client.GetBranchStatus(); err == nil {
+		vm.branchStatus = status
+	}
+	vm.formatTitle()
+}
+
+// updateTitleRefs updates the branch of the title bar and how far it is
+// from its upstream from the ref index, for refs changing alone. Whether
+// the worktree is dirty is kept from the last update.
+func (vm *ViewManager) updateTitleRefs() {
+	// Detached HEADs and unborn branches have nothing in the index
+	if vm.branchStatus == nil || vm.refIndex == nil || vm.refIndex.Head == "" {
+		return
+	}
+
+	status := *vm.branchStatus
+	status.Branch = strings.TrimPrefix(vm.refIndex.Head, "refs/heads/")
+	status.Upstream = git.ShortRefName(vm.refIndex.Upstream(vm.refIndex.Head))
+	status.Ahead, status.Behind = vm.refIndex.Tracking(vm.refIndex.Head)
+	vm.branchStatus = &status
+	vm.formatTitle()
+}
+
+// formatTitle sets the title bar from the last state of the branch
+func (vm *ViewManager) formatTitle() {
+	vm.title = formatTitle(filepath.Base(vm.client.GetRootPath()), vm.branchStatus)
+	if vm.config.General.ReadOnly {
+		vm.title += " [read-only]"
+	}
+}
+
+// GetTitle returns the text of the title bar, empty outside repositories
+func (vm *ViewManager) GetTitle() string {
+	vm.mutex.RLock()
+	defer vm.mutex.RUnlock()
+	return vm.title
+}
+
+// drawTitle draws the title bar on the top line of the screen
+func (vm *ViewManager) drawTitle() {
+	style := tcell.StyleDefault.Background(tcell.ColorDarkGreen).Foreground(tcell.ColorWhite).Bold(true)
+	var cells []cell
+	for _, ch := range " " + vm.title {
+		cells = append(cells, cell{ch, style})
+	}
+	for cellsWidth(cells) < vm.width {
+		cells = append(cells, cell{' ', style})
+	}
+	drawCells(vm.screen, 0, 0, vm.width, cells, 0, style)
+}
+
+// setTerminalTitle sets the title of the terminal window or tab with the
+// OSC 0 escape sequence, when it changed since it was last set
+func (t *Terminal) setTerminalTitle(title string) {
+	if title == t.windowTitle {
+		return
+	}
+	t.windowTitle = title
+
+	tty, ok := t.screen.Tty()
+	if !ok || tty == nil {
+		return
+	}
+	if title == "" {
+		title = "tig"
+	} else {
+		title = "tig: " + title
+	}
+	fmt.Fprintf(tty, "\x1b]0;%s\x07", stripControl(title))
+}
+
+// stripControl removes the control characters of a text sent in an escape
+// sequence, which would end the sequence early
+func stripControl(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r < ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, text)
+}
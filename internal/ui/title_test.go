@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/azhao1981/tig/internal/git"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatTitle(t *testing.T) {
+	assert.Equal(t, "tig", formatTitle("tig", nil))
+	assert.Equal(t, "tig main", formatTitle("tig", &git.BranchStatus{Branch: "main"}))
+	assert.Equal(t, "tig main* ↑1 ↓2", formatTitle("tig", &git.BranchStatus{Branch: "main", Dirty: true, Ahead: 1, Behind: 2}))
+	assert.Equal(t, "tig (detached) ↓3", formatTitle("tig", &git.BranchStatus{Behind: 3}))
+}
+
+func TestViewManagerRenderTitle(t *testing.T) {
+	client := openTestRepo(t)
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	cfg := &config.Config{}
+	vm := NewViewManager(screen, cfg, client, NewKeyBindingManager(cfg))
+	vm.SetSize(80, 24)
+
+	require.NoError(t, vm.RefreshAll())
+	title := vm.GetTitle()
+	assert.True(t, strings.HasPrefix(title, filepath.Base(client.GetRootPath())), title)
+
+	require.NoError(t, vm.Render())
+	lines := screenLines(screen)
+	assert.Equal(t, title, strings.TrimSpace(lines[0]))
+	assert.True(t, strings.HasPrefix(lines[1], "┌"), lines[1])
+}
@@ -20,8 +20,10 @@ type TreeView struct {
 	files       []*git.File
 	selected    int
 	currentPath string
+	rev         string // Revision whose tree is shown, the index when empty
 	rootPath    string
 	repoPath    string
+	frame       *Frame
 }
 
 // NewTreeView creates a new tree view
@@ -34,6 +36,7 @@ func NewTreeView(config *config.Config, client git.Client) *TreeView {
 		files:       []*git.File{},
 		currentPath: "",
 		rootPath:    "",
+		frame:       NewFrame(config, "Repository Tree"),
 	}
 }
 
@@ -43,8 +46,19 @@ func (v *TreeView) Load() error {
 		return fmt.Errorf("no git client available")
 	}
 
-	// Get files from git repository
-	files, err := v.client.GetFiles(v.currentPath)
+	if !v.client.IsRepository() {
+		v.files = []*git.File{}
+		return nil
+	}
+
+	// Get files from the tree of the revision, or the index
+	var files []*git.File
+	var err error
+	if v.rev != "" {
+		files, err = v.client.GetTree(v.rev, v.currentPath)
+	} else {
+		files, err = v.client.GetFiles(v.currentPath)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get files: %w", err)
 	}
@@ -68,43 +82,40 @@ func (v *TreeView) sortFiles() {
 // Render renders the tree view
 func (v *TreeView) Render(screen tcell.Screen, x, y, width, height int) error {
 	v.SetPosition(x, y, width, height)
-	v.SetHeight(height - 3) // Account for header and status bar
+	v.SetHeight(v.listRows())
 	
 	if width == 0 || height == 0 {
 		return fmt.Errorf("invalid screen dimensions")
 	}
 
-	// Draw header
-	header := "Repository Tree"
+	// Draw the frame, and its scrollbar once the content is laid out
+	v.frame.Title = "Repository Tree"
 	if v.currentPath != "" {
-		header = fmt.Sprintf("Tree: %s", v.currentPath)
+		v.frame.Title = fmt.Sprintf("Tree: %s", v.currentPath)
 	}
-	
-	// Truncate header if too long
-	if len(header) > width {
-		header = header[:width-3] + "..."
+	if v.rev != "" {
+		v.frame.Title += " at " + v.client.AbbrevHash(v.rev)
 	}
-	
-	style := tcell.StyleDefault.Bold(true)
-	v.drawText(screen, 0, 0, style, header)
-	
-	// Draw separator
-	for x := 0; x < width; x++ {
-		screen.SetContent(x, 1, '-', nil, tcell.StyleDefault)
+	v.frame.Status = "Use ↑/↓ to navigate, Enter to enter dir, h/← to go up, r to refresh"
+	x, y, width, height = v.frame.Draw(screen, x, y, width, height)
+	defer v.frame.DrawScrollbar(screen, v.Scrollable)
+	if width <= 0 || height <= 0 {
+		return nil
 	}
 
 	// Draw file list
-	startY := 2
-	maxRows := height - startY - 1
+	startY := y
+	maxRows := height
+	v.SetMaxOffset(len(v.files) - maxRows)
 	
 	if len(v.files) == 0 {
 		msg := "No files found"
 		if v.repoPath == "" {
 			msg = "No repository opened"
 		}
-		msgX := (width - len(msg)) / 2
-		msgY := height / 2
-		v.drawText(screen, msgX, msgY, tcell.StyleDefault.Dim(true), msg)
+		msgX := x + (width-len(msg))/2
+		msgY := y + height/2
+		v.drawText(screen, msgX, msgY, width, tcell.StyleDefault.Dim(true), msg)
 	} else {
 		// Calculate visible range
 		visibleStart := 0
@@ -124,12 +135,12 @@ func (v *TreeView) Render(screen tcell.Screen, x, y, width, height int) error {
 		// Draw files
 		for i := visibleStart; i < visibleEnd; i++ {
 			file := v.files[i]
-			y := startY + (i - visibleStart)
+			lineY := startY + (i - visibleStart)
 			
 			if i == v.selected {
 				// Highlight selected item
-				for xPos := 0; xPos < width; xPos++ {
-					screen.SetContent(xPos, y, ' ', nil, tcell.StyleDefault.Background(tcell.ColorBlue))
+				for xPos := x; xPos < x+width; xPos++ {
+					screen.SetContent(xPos, lineY, ' ', nil, tcell.StyleDefault.Background(tcell.ColorBlue))
 				}
 			}
 
@@ -147,11 +158,15 @@ func (v *TreeView) Render(screen tcell.Screen, x, y, width, height int) error {
 				path = strings.TrimPrefix(path, "/")
 			}
 
-			line := fmt.Sprintf("%s %s", icon, path)
+			size := ""
+			if !file.IsDir {
+				size = git.FormatSize(file.Size)
+			}
+			line := fmt.Sprintf("%s %10s %s %s", file.Mode, size, icon, path)
 			
 			// Truncate if too long
 			maxLen := width - 4
-			if len(line) > maxLen {
+			if maxLen > 3 && len(line) > maxLen {
 				line = line[:maxLen-3] + "..."
 			}
 
@@ -160,29 +175,27 @@ func (v *TreeView) Render(screen tcell.Screen, x, y, width, height int) error {
 				style = style.Bold(true)
 			}
 			
-			v.drawText(screen, 2, y, style, line)
-		}
-
-		// Draw scrollbar if needed
-		if len(v.files) > maxRows {
-			v.drawScrollbar(screen, len(v.files), maxRows, v.GetOffset())
+			v.drawText(screen, x+2, lineY, width-2, style, line)
 		}
 	}
 
-	// Draw status bar
-	v.drawStatusBar(screen, width, height)
-	
 	// Position cursor
 	if v.selected >= 0 && v.selected < len(v.files) {
 		cursorY := startY + (v.selected - v.GetOffset())
-		if cursorY >= startY && cursorY < height-1 {
-			screen.ShowCursor(0, cursorY)
+		if cursorY >= startY && cursorY < y+height {
+			screen.ShowCursor(x, cursorY)
 		}
 	}
 
 	return nil
 }
 
+// listRows returns the number of files shown at once
+func (v *TreeView) listRows() int {
+	_, _, _, height := v.GetPosition()
+	return height - 2 // Account for the frame
+}
+
 // HandleKey handles key events for the tree view
 func (v *TreeView) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
 	switch {
@@ -231,8 +244,7 @@ func (v *TreeView) moveUp() {
 func (v *TreeView) moveDown() {
 	if v.selected < len(v.files)-1 {
 		v.selected++
-		_, _, _, height := v.GetPosition()
-		maxRows := height - 3
+		maxRows := v.listRows()
 		if v.selected >= v.GetOffset()+maxRows {
 			v.SetOffset(v.selected - maxRows + 1)
 		}
@@ -316,8 +328,7 @@ func (v *TreeView) refresh() {
 
 // pageUp moves up by one page
 func (v *TreeView) pageUp() {
-	_, _, _, height := v.GetPosition()
-	pageSize := height - 3
+	pageSize := v.listRows()
 	
 	if v.selected >= pageSize {
 		v.selected -= pageSize
@@ -332,8 +343,7 @@ func (v *TreeView) pageUp() {
 
 // pageDown moves down by one page
 func (v *TreeView) pageDown() {
-	_, _, _, height := v.GetPosition()
-	pageSize := height - 3
+	pageSize := v.listRows()
 	
 	if v.selected+pageSize < len(v.files) {
 		v.selected += pageSize
@@ -346,8 +356,7 @@ func (v *TreeView) pageDown() {
 
 // adjustScroll adjusts the scroll position based on selected index
 func (v *TreeView) adjustScroll() {
-	_, _, _, height := v.GetPosition()
-	maxRows := height - 3
+	maxRows := v.listRows()
 	
 	if v.selected < v.GetOffset() {
 		v.SetOffset(v.selected)
@@ -356,77 +365,95 @@ func (v *TreeView) adjustScroll() {
 	}
 }
 
-// drawStatusBar draws the status bar
-func (v *TreeView) drawStatusBar(screen tcell.Screen, width, height int) {
-	if height < 2 {
-		return
-	}
+// GetType returns the view type
+func (v *TreeView) GetType() ViewType {
+	return ViewTypeTree
+}
 
-	// Draw status bar background
-	statusStyle := tcell.StyleDefault.Background(tcell.ColorDarkGray).Foreground(tcell.ColorWhite)
-	for x := 0; x < width; x++ {
-		screen.SetContent(x, height-1, ' ', nil, statusStyle)
+// drawText draws text at the specified position, cut at the given width
+func (v *TreeView) drawText(screen tcell.Screen, x, y, width int, style tcell.Style, text string) {
+	drawCells(screen, x, y, width, textCells(text, style), 0, style)
+}
+
+// Refresh refreshes the tree view
+func (v *TreeView) Refresh() error {
+	var selectedPath string
+	if v.selected >= 0 && v.selected < len(v.files) {
+		selectedPath = v.files[v.selected].Path
 	}
 
-	// Status text
-	status := "Tree View - Use ↑/↓ to navigate, Enter to enter dir, h/← to go up, r to refresh"
-	if len(status) > width {
-		status = status[:width-1]
+	if err := v.Load(); err != nil {
+		return err
 	}
-	v.drawText(screen, 0, height-1, statusStyle, status)
-}
 
-// GetType returns the view type
-func (v *TreeView) GetType() ViewType {
-	return ViewTypeTree
+	// Keep the selected file selected when it is still listed
+	v.selected = findSelection(len(v.files), v.selected, func(i int) bool {
+		return v.files[i].Path == selectedPath
+	})
+	v.adjustScroll()
+	return nil
 }
 
-// drawText draws text at the specified position
-func (v *TreeView) drawText(screen tcell.Screen, x, y int, style tcell.Style, text string) {
-	width, _ := screen.Size()
-	for i, r := range text {
-		if x+i >= width {
-			break
-		}
-		screen.SetContent(x+i, y, r, nil, style)
+// revision returns the revision files are shown as of, HEAD when listing
+// the index
+func (v *TreeView) revision() string {
+	if v.rev != "" {
+		return v.rev
 	}
+	return "HEAD"
 }
 
-// drawScrollbar draws the scrollbar if needed
-func (v *TreeView) drawScrollbar(screen tcell.Screen, totalItems, visibleItems, offset int) {
-	if totalItems <= visibleItems {
-		return
+// SetRevision shows the tree of a revision, from its root, or the index
+// when rev is empty
+func (v *TreeView) SetRevision(rev string) error {
+	previous, path := v.rev, v.currentPath
+	v.rev, v.currentPath = rev, ""
+	if err := v.Load(); err != nil {
+		v.rev, v.currentPath = previous, path
+		return err
 	}
+	v.selected = 0
+	v.ScrollToTop()
+	return nil
+}
 
-	width, height := screen.Size()
-	if height < 5 {
-		return
+// PagerArgs returns the git arguments producing the full content of the
+// selected file as of the revision shown
+func (v *TreeView) PagerArgs() ([]string, error) {
+	if v.selected < 0 || v.selected >= len(v.files) {
+		return nil, fmt.Errorf("no file selected")
 	}
 
-	scrollbarHeight := height - 4
-	scrollRatio := float64(visibleItems) / float64(totalItems)
-	thumbHeight := int(float64(scrollbarHeight) * scrollRatio)
-	if thumbHeight < 1 {
-		thumbHeight = 1
+	file := v.files[v.selected]
+	if file.IsDir {
+		return nil, fmt.Errorf("%s is a directory", file.Path)
 	}
+	return []string{"show", v.revision() + ":" + v.fullPath(file)}, nil
+}
 
-	scrollPos := int(float64(offset) / float64(totalItems-visibleItems) * float64(scrollbarHeight-thumbHeight))
-
-	// Draw scrollbar background
-	scrollbarX := width - 1
-	for y := 2; y < height-2; y++ {
-		screen.SetContent(scrollbarX, y, '│', nil, tcell.StyleDefault.Dim(true))
+// fullPath returns the repository relative path of a listed file
+func (v *TreeView) fullPath(file *git.File) string {
+	if v.currentPath == "" || strings.HasPrefix(file.Path, v.currentPath+"/") {
+		return file.Path
 	}
+	return v.currentPath + "/" + file.Path
+}
 
-	// Draw scrollbar thumb
-	for y := 2 + scrollPos; y < 2+scrollPos+thumbHeight && y < height-2; y++ {
-		screen.SetContent(scrollbarX, y, '█', nil, tcell.StyleDefault)
+// Selection returns the selected file, or directory, for placeholder
+// expansion
+func (v *TreeView) Selection() Selection {
+	sel := Selection{Directory: v.currentPath}
+	if v.selected < 0 || v.selected >= len(v.files) {
+		return sel
 	}
-}
 
-// Refresh refreshes the tree view
-func (v *TreeView) Refresh() error {
-	return v.Load()
+	file := v.files[v.selected]
+	if file.IsDir {
+		sel.Directory = v.fullPath(file)
+	} else {
+		sel.File = v.fullPath(file)
+	}
+	return sel
 }
 
 // SetRepoPath sets the repository path
@@ -434,5 +461,70 @@ func (v *TreeView) SetRepoPath(path string) {
 	v.repoPath = path
 	v.rootPath = path
 	v.currentPath = ""
+	v.rev = ""
 	v.Load()
-}
\ No newline at end of file
+}
+// openTreeFile shows the content of the selected file of the tree view as
+// of the revision shown
+func (vm *ViewManager) openTreeFile() error {
+	view, ok := vm.views[ViewTypeTree].(*TreeView)
+	if !ok {
+		return fmt.Errorf("tree view not found")
+	}
+	if view.selected < 0 || view.selected >= len(view.files) {
+		return fmt.Errorf("no file selected")
+	}
+	file := view.files[view.selected]
+	if file.IsDir {
+		return fmt.Errorf("%s is a directory", file.Path)
+	}
+
+	rev, err := vm.client.ResolveRevision(view.revision())
+	if err != nil {
+		return err
+	}
+	blobView, ok := vm.views[ViewTypeBlob].(*BlobView)
+	if !ok {
+		return fmt.Errorf("blob view not found")
+	}
+	if err := blobView.SetFile(rev, view.fullPath(file), 1); err != nil {
+		return err
+	}
+	return vm.switchView(ViewTypeBlob)
+}
+
+// TreeCommand handles the :tree command, showing the tree of a revision,
+// or of the index without one
+func (vm *ViewManager) TreeCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if len(args) > 1 {
+		return fmt.Errorf("usage: tree [rev]")
+	}
+	var rev string
+	if len(args) == 1 {
+		if !vm.client.IsRepository() {
+			return fmt.Errorf("not in a git repository")
+		}
+		hash, err := vm.client.ResolveRevision(args[0])
+		if err != nil {
+			return err
+		}
+		rev = hash
+	}
+	return vm.showTree(rev)
+}
+
+// showTree switches to the tree view showing the tree of a revision, or of
+// the index when rev is empty
+func (vm *ViewManager) showTree(rev string) error {
+	view, ok := vm.views[ViewTypeTree].(*TreeView)
+	if !ok {
+		return fmt.Errorf("tree view not found")
+	}
+	if err := view.SetRevision(rev); err != nil {
+		return err
+	}
+	return vm.switchView(ViewTypeTree)
+}
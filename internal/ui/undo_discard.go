@@ -0,0 +1,27 @@
+package ui
+
+import (
+	"fmt"
+)
+
+// UndoDiscardCommand handles the :undo-discard command, which writes back
+// the file whose changes were discarded last
+func (vm *ViewManager) UndoDiscardCommand(args []string) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if len(args) > 0 {
+		return fmt.Errorf("usage: undo-discard")
+	}
+	if !vm.client.IsRepository() {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	path, err := vm.client.UndoDiscard()
+	if err != nil {
+		return err
+	}
+
+	vm.setMessage("Restored the discarded changes to %s", path)
+	return vm.refreshAll()
+}
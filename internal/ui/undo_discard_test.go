@@ -0,0 +1,25 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewManagerUndoDiscard(t *testing.T) {
+	dir, vm := autostashTestRepo(t)
+
+	assert.ErrorContains(t, vm.UndoDiscardCommand(nil), "no discarded changes")
+	assert.Error(t, vm.UndoDiscardCommand([]string{"file.txt"}))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("mine\n"), 0644))
+	require.NoError(t, vm.client.DiscardChanges("file.txt"))
+	require.NoError(t, vm.UndoDiscardCommand(nil))
+	assert.Equal(t, "Restored the discarded changes to file.txt", vm.GetMessage())
+	data, err := os.ReadFile(filepath.Join(dir, "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "mine\n", string(data))
+}
@@ -14,6 +14,19 @@ const (
 	ViewTypeTree
 	ViewTypeRefs
 	ViewTypeHelp
+	ViewTypeDiffStat
+	ViewTypeRangeDiff
+	ViewTypeSearch
+	ViewTypeCompare
+	ViewTypeStart
+	ViewTypeJobs
+	ViewTypeMaintenance
+	ViewTypeLargeObjects
+	ViewTypeFsck
+	ViewTypeStash
+	ViewTypeReview
+	ViewTypeGrep
+	ViewTypeBlob
 )
 
 // View represents a generic interface for all views
@@ -166,6 +179,9 @@ func NewScrollable() *Scrollable {
 
 // SetMaxOffset sets the maximum scroll offset
 func (s *Scrollable) SetMaxOffset(max int) {
+	if max < 0 {
+		max = 0
+	}
 	s.maxOffset = max
 	if s.offset > max {
 		s.offset = max
@@ -241,4 +257,22 @@ func (s *Scrollable) IsAtTop() bool {
 // IsAtBottom returns whether the view is at the bottom
 func (s *Scrollable) IsAtBottom() bool {
 	return s.offset == s.maxOffset
-}
\ No newline at end of file
+}
+
+// findSelection returns the index of the first of n items matching the
+// item selected before a refresh, or the old index kept within bounds when
+// that item is gone
+func findSelection(n, previous int, match func(i int) bool) int {
+	for i := 0; i < n; i++ {
+		if match(i) {
+			return i
+		}
+	}
+	if previous >= n {
+		previous = n - 1
+	}
+	if previous < 0 {
+		previous = 0
+	}
+	return previous
+}
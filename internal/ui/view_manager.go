@@ -2,7 +2,9 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/azhao1981/tig/internal/config"
@@ -21,6 +23,25 @@ type ViewManager struct {
 	width           int
 	height          int
 	keyBindingMgr   *KeyBindingManager
+	message         string
+	quitRequested   bool
+	commandRequest  string
+	title           string // Repository state shown on the top line
+	recent          *config.RecentRepos
+	confirmation    *confirmation     // Action waiting for y to be pressed
+	fileLog         *fileLog          // Last commits of a file shown over the view
+	logViewPicker   *logViewPicker    // Saved log views shown over the view
+	focus           focusMemory       // Where the focus of the views was
+	refIndex        *git.RefIndex     // Refs read once per refresh for all views
+	refsStamp       uint64            // Stamp of the refs the index was read at
+	branchStatus    *git.BranchStatus // State of the current branch shown in the title
+	marks           markMemory        // Marks set with m in each view
+	markPrefix      rune              // m or ' waiting for the letter of a mark
+	jobs            []*job            // Operations running in the background
+	lastFetch       time.Time         // When auto-fetch last started
+	repoAutoFetch   int               // Minutes between auto-fetches set by the repository, -1 if unset
+	refreshPause    refreshPause      // Periodic refresh held off during operations on many files
+	integrity       *integrityWarning // Broken or dangling objects noticed
 }
 
 // NewViewManager creates a new view manager
@@ -32,6 +53,9 @@ func NewViewManager(screen tcell.Screen, config *config.Config, client git.Clien
 		views:         make(map[ViewType]View),
 		currentView:   ViewTypeMain,
 		keyBindingMgr: keyBindingMgr,
+		repoAutoFetch: -1,
+		focus:         make(focusMemory),
+		marks:         make(markMemory),
 	}
 
 	// Initialize views
@@ -43,6 +67,8 @@ func NewViewManager(screen tcell.Screen, config *config.Config, client git.Clien
 func (vm *ViewManager) initializeViews() {
 	// Create main view
 	mainView := NewMainView(vm.config, vm.client)
+	mainView.run = vm.runMainAction
+	mainView.post = vm.postLogPage
 	vm.views[ViewTypeMain] = mainView
 
 	// Create diff view
@@ -51,6 +77,8 @@ func (vm *ViewManager) initializeViews() {
 
 	// Create status view
 	statusView := NewStatusView(vm.config, vm.client)
+	statusView.bulk = vm.bulk
+	statusView.confirm = vm.confirmAction
 	vm.views[ViewTypeStatus] = statusView
 
 	// Create tree view
@@ -59,12 +87,69 @@ func (vm *ViewManager) initializeViews() {
 
 	// Create refs view
 	refsView := NewRefsView(vm.config, vm.client)
+	refsView.run = vm.runRefAction
 	vm.views[ViewTypeRefs] = refsView
 
 	// Create help view
 	helpView := NewHelpView(vm.config, vm.client)
 	vm.views[ViewTypeHelp] = helpView
 
+	// Create diffstat view
+	diffStatView := NewDiffStatView(vm.config, vm.client)
+	vm.views[ViewTypeDiffStat] = diffStatView
+
+	// Create range-diff view
+	rangeDiffView := NewRangeDiffView(vm.config, vm.client)
+	vm.views[ViewTypeRangeDiff] = rangeDiffView
+
+	// Create search results view
+	searchView := NewSearchView(vm.config, vm.client)
+	vm.views[ViewTypeSearch] = searchView
+
+	// Create branch comparison view
+	compareView := NewCompareView(vm.config, vm.client)
+	vm.views[ViewTypeCompare] = compareView
+
+	// Create start view, shown outside of repositories
+	startView := NewStartView(vm.config, vm.client)
+	vm.views[ViewTypeStart] = startView
+
+	// Create background jobs view
+	jobsView := NewJobsView(vm.config, vm.client)
+	vm.views[ViewTypeJobs] = jobsView
+
+	// Create repository maintenance view
+	maintenanceView := NewMaintenanceView(vm.config, vm.client)
+	maintenanceView.load = vm.loadRepoStats
+	maintenanceView.run = vm.runMaintenance
+	vm.views[ViewTypeMaintenance] = maintenanceView
+
+	// Create large objects view
+	largeObjectsView := NewLargeObjectsView(vm.config, vm.client)
+	vm.views[ViewTypeLargeObjects] = largeObjectsView
+
+	// Create fsck view
+	fsckView := NewFsckView(vm.config, vm.client)
+	vm.views[ViewTypeFsck] = fsckView
+
+	// Create stash view
+	stashView := NewStashView(vm.config, vm.client)
+	stashView.run = vm.runStashAction
+	vm.views[ViewTypeStash] = stashView
+
+	// Create review notes view
+	reviewView := NewReviewView(vm.config, vm.client)
+	reviewView.run = vm.runReviewAction
+	vm.views[ViewTypeReview] = reviewView
+
+	// Create grep results view
+	grepView := NewGrepView(vm.config, vm.client)
+	vm.views[ViewTypeGrep] = grepView
+
+	// Create blob view
+	blobView := NewBlobView(vm.config, vm.client)
+	vm.views[ViewTypeBlob] = blobView
+
 	// Set initial focus
 	vm.setFocus(vm.currentView)
 }
@@ -88,7 +173,27 @@ func (vm *ViewManager) SetRepoPath(path string) {
 	vm.mutex.Lock()
 	defer vm.mutex.Unlock()
 
+	vm.setRepoPath(path)
+
+	// Outside of a repository offer to get into one
+	if !vm.client.IsRepository() {
+		_ = vm.switchView(ViewTypeStart)
+		return
+	}
+	if err := vm.applyStartup(); err != nil {
+		vm.setMessage("%v", err)
+	}
+}
+
+// setRepoPath sets the repository path (internal, without lock)
+func (vm *ViewManager) setRepoPath(path string) {
 	vm.repoPath = path
+	vm.resetAutoFetch()
+	vm.integrity = nil
+	vm.focus = make(focusMemory)
+	vm.marks = make(markMemory)
+	vm.markPrefix = 0
+	vm.updateRefIndex()
 	
 	// Update repository path for all views
 	for _, view := range vm.views {
@@ -105,6 +210,28 @@ func (vm *ViewManager) SetRepoPath(path string) {
 			v.SetRepoPath(path)
 		case *HelpView:
 			v.SetRepoPath(path)
+		case *DiffStatView:
+			v.SetRepoPath(path)
+		case *RangeDiffView:
+			v.SetRepoPath(path)
+		case *SearchView:
+			v.SetRepoPath(path)
+		case *CompareView:
+			v.SetRepoPath(path)
+		case *StartView:
+			v.SetRepoPath(path)
+		case *JobsView:
+			v.SetRepoPath(path)
+		case *MaintenanceView:
+			v.SetRepoPath(path)
+		case *LargeObjectsView:
+			v.SetRepoPath(path)
+		case *FsckView:
+			v.SetRepoPath(path)
+		case *StashView:
+			v.SetRepoPath(path)
+		case *ReviewView:
+			v.SetRepoPath(path)
 		}
 	}
 
@@ -117,10 +244,20 @@ func (vm *ViewManager) SwitchView(viewType ViewType) error {
 	vm.mutex.Lock()
 	defer vm.mutex.Unlock()
 
+	return vm.switchView(viewType)
+}
+
+// switchView switches to a different view (internal, without lock)
+func (vm *ViewManager) switchView(viewType ViewType) error {
 	if _, exists := vm.views[viewType]; !exists {
 		return fmt.Errorf("view type %d not found", viewType)
 	}
 
+	// Leaving the diff of a status file, remember where it was left
+	if vm.currentView == ViewTypeDiff && viewType != ViewTypeDiff {
+		vm.rememberStageFocus()
+	}
+
 	// Blur current view
 	if current, exists := vm.views[vm.currentView]; exists {
 		current.Blur()
@@ -129,6 +266,9 @@ func (vm *ViewManager) SwitchView(viewType ViewType) error {
 	// Switch to new view
 	vm.currentView = viewType
 	vm.setFocus(vm.currentView)
+	if viewType == ViewTypeStatus {
+		vm.restoreStatusFocus()
+	}
 
 	return nil
 }
@@ -158,81 +298,401 @@ func (vm *ViewManager) Render() error {
 		return fmt.Errorf("current view %d not found", vm.currentView)
 	}
 
-	return view.Render(vm.screen, 0, 0, vm.width, vm.height)
+	y, height := 0, vm.height
+
+	// Inside a repository the top line shows its state
+	if vm.title != "" && height > 1 {
+		vm.drawTitle()
+		y, height = 1, height-1
+	}
+	if vm.integrity != nil && height > 2 {
+		vm.drawIntegrity(y)
+		y, height = y+1, height-1
+	}
+
+	// The bottom line hints at what can be done with the selected item
+	if hints := vm.currentHints(); len(hints) > 0 && height > 2 {
+		height--
+		vm.drawHints(y+height, hints)
+	}
+
+	if err := view.Render(vm.screen, 0, y, vm.width, height); err != nil {
+		return err
+	}
+	if vm.fileLog != nil {
+		vm.drawFileLog()
+	}
+	if vm.logViewPicker != nil {
+		vm.drawLogViewPicker()
+	}
+	vm.drawProgress()
+	if vm.confirmation != nil {
+		vm.drawConfirmation()
+	}
+	return nil
 }
 
 // HandleKey handles keyboard input
 func (vm *ViewManager) HandleKey(key tcell.Key, ch rune, mod tcell.ModMask) bool {
-	vm.mutex.RLock()
-	defer vm.mutex.RUnlock()
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if vm.confirmation != nil {
+		vm.answerConfirmation(key, ch)
+		return true
+	}
+	if vm.fileLog != nil {
+		vm.answerFileLog(key, ch)
+		return true
+	}
+	if vm.logViewPicker != nil {
+		vm.answerLogViewPicker(key, ch)
+		return true
+	}
+
+	if vm.handleMarkKey(key, ch) {
+		return true
+	}
+
+	if action, refused := vm.refusedAction(key, ch, mod); refused {
+		vm.setMessage("%s is disabled in read-only mode", action)
+		return true
+	}
+
+	// Give the current view the first chance to handle the key so that
+	// view-specific bindings take precedence over the generic keymap
+	if view, exists := vm.views[vm.currentView]; exists {
+		if view.HandleKey(key, ch, mod) {
+			return true
+		}
+	}
 
 	// Check for key bindings using the key binding manager
 	if action, ok := vm.keyBindingMgr.MatchEvent(key, ch, mod); ok {
 		switch action {
 		case "quit":
+			vm.quitRequested = true
 			return false
 		case "refresh":
-			vm.RefreshAll()
+			vm.refreshAll()
 			return true
 		case "status":
-			_ = vm.SwitchView(ViewTypeStatus)
+			_ = vm.switchView(ViewTypeStatus)
 			return true
 		case "diff":
-			_ = vm.SwitchView(ViewTypeDiff)
+			_ = vm.switchView(ViewTypeDiff)
 			return true
 		case "log":
-			_ = vm.SwitchView(ViewTypeMain)
+			if err := vm.showLog("", ""); err != nil {
+				vm.setMessage("%v", err)
+			}
 			return true
 		case "tree":
-			_ = vm.SwitchView(ViewTypeTree)
+			// From the main view the tree is the one of the selected commit
+			if mainView, ok := vm.views[ViewTypeMain].(*MainView); ok && vm.currentView == ViewTypeMain {
+				if commit := mainView.GetSelectedCommit(); commit != nil {
+					if err := vm.showTree(commit.Hash); err != nil {
+						vm.setMessage("%v", err)
+					}
+					return true
+				}
+			}
+			_ = vm.switchView(ViewTypeTree)
 			return true
 		case "refs":
-			_ = vm.SwitchView(ViewTypeRefs)
+			_ = vm.switchView(ViewTypeRefs)
+			return true
+		case "jobs":
+			_ = vm.switchView(ViewTypeJobs)
 			return true
 		case "help":
-			_ = vm.SwitchView(ViewTypeHelp)
+			_ = vm.switchView(ViewTypeHelp)
+			return true
+		case "up", "down", "page-up", "page-down", "top", "bottom":
+			// Rebound navigation keys reach the view as the keys it handles
+			view, exists := vm.views[vm.currentView]
+			if !exists {
+				return false
+			}
+			return view.HandleKey(map[string]tcell.Key{
+				"up":        tcell.KeyUp,
+				"down":      tcell.KeyDown,
+				"page-up":   tcell.KeyPgUp,
+				"page-down": tcell.KeyPgDn,
+				"top":       tcell.KeyHome,
+				"bottom":    tcell.KeyEnd,
+			}[action], 0, 0)
+		case "pager":
+			if err := vm.pipeCurrentView(); err != nil {
+				vm.setMessage("%v", err)
+			}
+			return true
+		case "edit":
+			if err := vm.editCurrentView(); err != nil {
+				vm.setMessage("%v", err)
+			}
+			return true
+		case "diff-unstaged", "diff-staged", "diff-toggle-staged":
+			if vm.currentView != ViewTypeStatus && vm.currentView != ViewTypeDiff {
+				return false
+			}
+			side := map[string]string{
+				"diff-unstaged": statusSectionModified,
+				"diff-staged":   statusSectionStaged,
+			}[action]
+			if err := vm.showStageSide(side); err != nil {
+				vm.setMessage("%v", err)
+			}
+			return true
+		case "toggle-eol-changes":
+			if vm.currentView != ViewTypeDiff {
+				return false
+			}
+			if err := vm.toggleEOLChanges(); err != nil {
+				vm.setMessage("%v", err)
+			}
+			return true
+		case "commit":
+			if vm.currentView != ViewTypeStatus {
+				return false
+			}
+			if err := vm.startCommit(); err != nil {
+				vm.setMessage("%v", err)
+			}
+			return true
+		case "copy-hash":
+			if err := vm.copyCommitHash(); err != nil {
+				vm.setMessage("%v", err)
+			}
+			return true
+		case "show-commit":
+			if err := vm.showCommitAtCursor(); err != nil {
+				vm.setMessage("%v", err)
+			}
+			return true
+		case "review-note":
+			if vm.currentView != ViewTypeDiff {
+				return false
+			}
+			if err := vm.noteHunk(); err != nil {
+				vm.setMessage("%v", err)
+			}
+			return true
+		case "stage", "unstage":
+			if vm.currentView != ViewTypeDiff {
+				return false
+			}
+			if err := vm.toggleStageFile(action == "unstage"); err != nil {
+				vm.setMessage("%v", err)
+			}
 			return true
-		case "up":
-			// Let views handle navigation
-			if view, exists := vm.views[vm.currentView]; exists {
-				return view.HandleKey(tcell.KeyUp, 0, 0)
-			}
-		case "down":
-			// Let views handle navigation
-			if view, exists := vm.views[vm.currentView]; exists {
-				return view.HandleKey(tcell.KeyDown, 0, 0)
-			}
-		case "page-up":
-			// Let views handle navigation
-			if view, exists := vm.views[vm.currentView]; exists {
-				return view.HandleKey(tcell.KeyPgUp, 0, 0)
-			}
-		case "page-down":
-			// Let views handle navigation
-			if view, exists := vm.views[vm.currentView]; exists {
-				return view.HandleKey(tcell.KeyPgDn, 0, 0)
-			}
-		case "top":
-			// Let views handle navigation
-			if view, exists := vm.views[vm.currentView]; exists {
-				return view.HandleKey(tcell.KeyHome, 0, 0)
-			}
-		case "bottom":
-			// Let views handle navigation
-			if view, exists := vm.views[vm.currentView]; exists {
-				return view.HandleKey(tcell.KeyEnd, 0, 0)
+		case "file-log":
+			if vm.currentView != ViewTypeStatus && vm.currentView != ViewTypeTree {
+				return false
 			}
+			if err := vm.showFileLog(); err != nil {
+				vm.setMessage("%v", err)
+			}
+			return true
+		case "edit-hunk":
+			if vm.currentView != ViewTypeDiff {
+				return false
+			}
+			if err := vm.editHunk(); err != nil {
+				vm.setMessage("%v", err)
+			}
+			return true
+		case "stage-hunk", "mark-line", "stage-lines", "discard-hunk", "apply-hunk":
+			if vm.currentView != ViewTypeDiff {
+				return false
+			}
+			run := map[string]func() error{
+				"stage-hunk":   vm.stageHunk,
+				"mark-line":    vm.markLine,
+				"stage-lines":  vm.stageLines,
+				"discard-hunk": vm.discardHunk,
+				"apply-hunk":   vm.applyHunk,
+			}[action]
+			if err := run(); err != nil {
+				vm.setMessage("%v", err)
+			}
+			return true
+		case "backport":
+			if vm.currentView != ViewTypeMain {
+				return false
+			}
+			vm.commandRequest = "backport "
+			return true
+		case "fixup":
+			if vm.currentView != ViewTypeMain {
+				return false
+			}
+			if err := vm.createFixup(); err != nil {
+				vm.setMessage("%v", err)
+			}
+			return true
+		case "delete-refs":
+			if vm.currentView != ViewTypeRefs {
+				return false
+			}
+			if err := vm.deleteMarkedRefs(); err != nil {
+				vm.setMessage("%v", err)
+			}
+			return true
+		case "fetch":
+			if err := vm.startFetch(); err != nil {
+				vm.setMessage("%v", err)
+			}
+			return true
+		case "push", "delete-remote":
+			if action == "delete-remote" && vm.currentView != ViewTypeRefs {
+				return false
+			}
+			if err := vm.pushSelectedRef("", action == "delete-remote", false); err != nil {
+				vm.setMessage("%v", err)
+			}
+			return true
+		case "search":
+			// The files of the status view and the refs are filtered
+			// instead
+			vm.commandRequest = "search "
+			if vm.currentView == ViewTypeStatus {
+				vm.commandRequest = "status-filter "
+			}
+			if vm.currentView == ViewTypeRefs {
+				vm.commandRequest = "refs-filter "
+			}
+			return true
+		case "parent":
+			if vm.currentView != ViewTypeMain {
+				return false
+			}
+			if err := vm.jumpToParent(); err != nil {
+				vm.setMessage("%v", err)
+			}
+			return true
+		case "search-next", "search-prev":
+			direction := 1
+			if action == "search-prev" {
+				direction = -1
+			}
+			if help, ok := vm.views[vm.currentView].(*HelpView); ok && help.pattern != "" {
+				if !help.findMatch(direction) {
+					vm.setMessage("No help matches %q", help.pattern)
+				}
+				return true
+			}
+			if vm.currentView != ViewTypeMain {
+				return false
+			}
+			if err := vm.findMatch(direction); err != nil {
+				vm.setMessage("%v", err)
+			}
+			return true
+		case "enter":
+			if vm.currentView == ViewTypeStart {
+				if err := vm.chooseStartItem(); err != nil {
+					vm.setMessage("%v", err)
+				}
+				return true
+			}
+			if vm.currentView == ViewTypeStatus {
+				if err := vm.openStageFile(); err != nil {
+					vm.setMessage("%v", err)
+				}
+				return true
+			}
+			if vm.currentView == ViewTypeDiff {
+				// Enter on a hunk of a status file goes back to the file
+				if diffView, ok := vm.views[ViewTypeDiff].(*DiffView); ok && diffView.stage != nil {
+					_ = vm.switchView(ViewTypeStatus)
+					return true
+				}
+			}
+			if vm.currentView == ViewTypeSearch {
+				if err := vm.jumpToMatch(); err != nil {
+					vm.setMessage("%v", err)
+				}
+				return true
+			}
+			if vm.currentView == ViewTypeTree {
+				// Directories are entered by the view itself
+				if err := vm.openTreeFile(); err != nil {
+					vm.setMessage("%v", err)
+				}
+				return true
+			}
+			if vm.currentView == ViewTypeGrep {
+				if err := vm.openGrepLine(); err != nil {
+					vm.setMessage("%v", err)
+				}
+				return true
+			}
+			if _, ok := vm.views[vm.currentView].(DiffOpener); !ok {
+				return false
+			}
+			if err := vm.openDiff(); err != nil {
+				vm.setMessage("%v", err)
+			}
+			return true
 		}
-	}
 
-	// Handle view-specific key bindings
-	if view, exists := vm.views[vm.currentView]; exists {
-		return view.HandleKey(key, ch, mod)
+		// Bindings of the form !command run external commands
+		if strings.HasPrefix(action, "!") {
+			if err := vm.runExternal(strings.Fields(action[1:])); err != nil {
+				vm.setMessage("%v", err)
+			}
+			return true
+		}
 	}
 
 	return false
 }
 
+// QuitRequested returns whether the quit action has been triggered
+func (vm *ViewManager) QuitRequested() bool {
+	vm.mutex.RLock()
+	defer vm.mutex.RUnlock()
+	return vm.quitRequested
+}
+
+// TakeCommandRequest returns and clears the command line an action asked
+// to be opened with, if any
+func (vm *ViewManager) TakeCommandRequest() string {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+	request := vm.commandRequest
+	vm.commandRequest = ""
+	return request
+}
+
+// SetMessage sets the message shown in the status line
+func (vm *ViewManager) SetMessage(format string, args ...interface{}) {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+	vm.setMessage(format, args...)
+}
+
+// setMessage sets the status line message (internal, without lock)
+func (vm *ViewManager) setMessage(format string, args ...interface{}) {
+	vm.message = fmt.Sprintf(format, args...)
+	vm.noteCorruption(args)
+}
+
+// GetMessage returns the message shown in the status line
+func (vm *ViewManager) GetMessage() string {
+	vm.mutex.RLock()
+	defer vm.mutex.RUnlock()
+	return vm.message
+}
+
+// ClearMessage clears the status line message
+func (vm *ViewManager) ClearMessage() {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+	vm.message = ""
+}
+
 // GetCurrentView returns the current view type
 func (vm *ViewManager) GetCurrentView() ViewType {
 	vm.mutex.RLock()
@@ -247,6 +707,18 @@ func (vm *ViewManager) GetView(viewType ViewType) View {
 	return vm.views[viewType]
 }
 
+// UpdateConfig changes the configuration shared by the views and refreshes
+// them so the change shows right away
+func (vm *ViewManager) UpdateConfig(fn func(cfg *config.Config) error) error {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if err := fn(vm.config); err != nil {
+		return err
+	}
+	return vm.refreshAll()
+}
+
 // RefreshAll refreshes all views
 func (vm *ViewManager) RefreshAll() error {
 	vm.mutex.Lock()
@@ -258,6 +730,14 @@ func (vm *ViewManager) RefreshAll() error {
 // refreshAll refreshes all views (internal, without lock)
 func (vm *ViewManager) refreshAll() error {
 	var lastErr error
+
+	// Everything is refreshed after a change or when asked to, so the
+	// cached status isn't trusted
+	if statusView, ok := vm.views[ViewTypeStatus].(*StatusView); ok && statusView.cache != nil {
+		statusView.cache.Invalidate()
+	}
+	vm.updateTitle()
+	vm.updateRefIndex()
 	
 	for _, view := range vm.views {
 		if err := view.Refresh(); err != nil {
@@ -273,6 +753,8 @@ func (vm *ViewManager) RefreshCurrent() error {
 	vm.mutex.Lock()
 	defer vm.mutex.Unlock()
 
+	vm.updateTitle()
+	vm.updateRefIndex()
 	if view, exists := vm.views[vm.currentView]; exists {
 		return view.Refresh()
 	}
@@ -296,6 +778,11 @@ func (vm *ViewManager) GetSelectedCommit() *git.Commit {
 	vm.mutex.RLock()
 	defer vm.mutex.RUnlock()
 
+	return vm.getSelectedCommit()
+}
+
+// getSelectedCommit returns the selected commit (internal, without lock)
+func (vm *ViewManager) getSelectedCommit() *git.Commit {
 	if mainView, ok := vm.views[ViewTypeMain].(*MainView); ok {
 		return mainView.GetSelectedCommit()
 	}
@@ -307,7 +794,7 @@ func (vm *ViewManager) UpdateDiffView() error {
 	vm.mutex.Lock()
 	defer vm.mutex.Unlock()
 
-	commit := vm.GetSelectedCommit()
+	commit := vm.getSelectedCommit()
 	if commit != nil {
 		if diffView, ok := vm.views[ViewTypeDiff].(*DiffView); ok {
 			diffView.SetCommitHash(commit.Hash)
@@ -317,6 +804,35 @@ func (vm *ViewManager) UpdateDiffView() error {
 	return fmt.Errorf("no commit selected")
 }
 
+// DiffOpener is implemented by views whose selected item can be opened in
+// the diff view
+type DiffOpener interface {
+	// DiffRange returns the revision range and paths to show the diff of
+	DiffRange() (string, []string, error)
+}
+
+// openDiff shows the diff of the selected item of the current view
+func (vm *ViewManager) openDiff() error {
+	opener, ok := vm.views[vm.currentView].(DiffOpener)
+	if !ok {
+		return fmt.Errorf("current view has nothing to open")
+	}
+
+	revRange, paths, err := opener.DiffRange()
+	if err != nil {
+		return err
+	}
+
+	diffView, ok := vm.views[ViewTypeDiff].(*DiffView)
+	if !ok {
+		return fmt.Errorf("diff view not found")
+	}
+	if err := diffView.SetRange(revRange, paths); err != nil {
+		return err
+	}
+	return vm.switchView(ViewTypeDiff)
+}
+
 // ShowHelp shows the help view
 func (vm *ViewManager) ShowHelp() error {
 	// TODO: Implement help view
@@ -80,6 +80,28 @@ func TestViewManagerHandleKey(t *testing.T) {
 	assert.True(t, handled)
 }
 
+func TestViewManagerHandleKeyReboundNavigation(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	err := screen.Init()
+	assert.NoError(t, err)
+	cfg := &config.Config{}
+	cfg.Keymaps.Bindings = map[string]string{"down": "<C-n>"}
+	client := git.NewClient()
+	keyBindingMgr := NewKeyBindingManager(cfg)
+
+	vm := NewViewManager(screen, cfg, client, keyBindingMgr)
+	vm.SetSize(80, 24)
+	assert.NoError(t, vm.SwitchView(ViewTypeHelp))
+
+	helpView := vm.views[ViewTypeHelp].(*HelpView)
+	assert.NoError(t, helpView.Load())
+	assert.Equal(t, 0, helpView.selected)
+
+	// The view only knows the arrow keys, the rebound key is translated
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'n', tcell.ModCtrl))
+	assert.Equal(t, 1, helpView.selected)
+}
+
 func TestViewManagerRefreshAll(t *testing.T) {
 	screen := tcell.NewSimulationScreen("")
 	err := screen.Init()
@@ -145,3 +167,45 @@ func TestViewManagerUpdateDiffView(t *testing.T) {
 	diffView := vm.GetView(ViewTypeDiff).(*DiffView)
 	assert.Equal(t, "1", diffView.GetCommitHash())
 }
+
+func TestViewManagerBackportPrompt(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	err := screen.Init()
+	assert.NoError(t, err)
+	cfg := &config.Config{}
+	client := git.NewClient()
+	keyBindingMgr := NewKeyBindingManager(cfg)
+
+	vm := NewViewManager(screen, cfg, client, keyBindingMgr)
+
+	// B prompts for the target branch on the command line
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'B', 0))
+	assert.Equal(t, "backport ", vm.TakeCommandRequest())
+	assert.Equal(t, "", vm.TakeCommandRequest())
+
+	// Only the main view has commits to backport
+	_ = vm.SwitchView(ViewTypeHelp)
+	vm.HandleKey(tcell.KeyRune, 'B', 0)
+	assert.Equal(t, "", vm.TakeCommandRequest())
+
+	assert.Error(t, vm.BackportCommand(nil))
+	assert.Error(t, vm.BackportCommand([]string{"release"}))
+}
+
+func TestViewManagerFixup(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	err := screen.Init()
+	assert.NoError(t, err)
+	cfg := &config.Config{}
+	client := git.NewClient()
+	keyBindingMgr := NewKeyBindingManager(cfg)
+
+	vm := NewViewManager(screen, cfg, client, keyBindingMgr)
+
+	// Without a repository the failure is reported in the status line
+	assert.True(t, vm.HandleKey(tcell.KeyRune, 'f', 0))
+	assert.Equal(t, "not in a git repository", vm.GetMessage())
+
+	assert.Error(t, vm.AutosquashCommand(nil))
+	assert.Error(t, vm.AutosquashCommand([]string{"extra"}))
+}
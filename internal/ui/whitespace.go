@@ -0,0 +1,173 @@
+package ui
+
+import (
+	"github.com/azhao1981/tig/internal/config"
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+// cell is a character of a line as drawn on screen
+type cell struct {
+	ch    rune
+	style tcell.Style
+}
+
+// whitespaceMarks controls how tabs and whitespace errors are drawn
+type whitespaceMarks struct {
+	tabSize  int
+	tabs     bool
+	trailing bool
+	cr       bool
+}
+
+// Styles of the whitespace indicators
+var (
+	tabMarkStyle      = tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
+	trailingMarkStyle = tcell.StyleDefault.Background(tcell.ColorRed)
+	crMarkStyle       = tcell.StyleDefault.Foreground(tcell.ColorRed).Bold(true)
+)
+
+// newWhitespaceMarks reads the whitespace settings from the configuration
+func newWhitespaceMarks(cfg *config.Config) whitespaceMarks {
+	tabSize := cfg.UI.TabSize
+	if tabSize <= 0 {
+		tabSize = 8
+	}
+	return whitespaceMarks{
+		tabSize:  tabSize,
+		tabs:     cfg.UI.ShowTabs,
+		trailing: cfg.UI.ShowTrailingSpace,
+		cr:       cfg.UI.ShowCR,
+	}
+}
+
+// cells expands the tabs of a line and applies the whitespace indicators.
+// Trailing whitespace is only looked for from the given index on, so the
+// prefix of a diff line is left alone; a negative index disables it, as
+// it only matters on lines being added.
+func (m whitespaceMarks) cells(text string, style tcell.Style, trailingFrom int) []cell {
+	return m.markCells(textCells(text, style), trailingFrom)
+}
+
+// markCells is cells for a line whose characters are styled already
+func (m whitespaceMarks) markCells(line []cell, trailingFrom int) []cell {
+	// A CR ending a line comes from a CRLF line ending
+	hasCR := len(line) > 0 && line[len(line)-1].ch == '\r'
+	if hasCR {
+		line = line[:len(line)-1]
+	}
+
+	trailingStart := len(line)
+	if trailingFrom >= 0 && m.trailing {
+		for trailingStart > trailingFrom && (line[trailingStart-1].ch == ' ' || line[trailingStart-1].ch == '\t') {
+			trailingStart--
+		}
+	}
+
+	cells := make([]cell, 0, len(line))
+	col := 0
+	for i, c := range line {
+		chStyle := c.style
+		if i >= trailingStart {
+			chStyle = trailingMarkStyle
+		}
+
+		if c.ch != '\t' {
+			cells = append(cells, cell{c.ch, chStyle})
+			col += runeColumns(c.ch)
+			continue
+		}
+
+		width := m.tabSize - col%m.tabSize
+		col += width
+		for j := 0; j < width; j++ {
+			if j == 0 && m.tabs {
+				markStyle := tabMarkStyle
+				if i >= trailingStart {
+					markStyle = trailingMarkStyle
+				}
+				cells = append(cells, cell{'→', markStyle})
+			} else {
+				cells = append(cells, cell{' ', chStyle})
+			}
+		}
+	}
+
+	if hasCR && m.cr {
+		cells = append(cells, cell{'^', crMarkStyle}, cell{'M', crMarkStyle})
+	}
+	return cells
+}
+
+// runeColumns returns the number of columns a character takes on screen
+func runeColumns(ch rune) int {
+	if w := runewidth.RuneWidth(ch); w > 0 {
+		return w
+	}
+	return 1
+}
+
+// cellsWidth returns the number of columns taken by cells
+func cellsWidth(cells []cell) int {
+	width := 0
+	for _, c := range cells {
+		width += runeColumns(c.ch)
+	}
+	return width
+}
+
+// truncateText cuts text to at most width columns, ending it with an
+// ellipsis when cut. Wide characters are left out whole rather than split.
+func truncateText(text string, width int) string {
+	return runewidth.Truncate(text, max(width, 0), "…")
+}
+
+// padText truncates or pads text with spaces to exactly width columns
+func padText(text string, width int) string {
+	return runewidth.FillRight(truncateText(text, width), width)
+}
+
+// drawCells draws a line of cells scrolled horizontally by offset columns.
+// Lines not fitting in width end with "..." in truncStyle, and wide
+// characters cut by either edge are drawn as spaces.
+func drawCells(screen tcell.Screen, x, y, width int, cells []cell, offset int, truncStyle tcell.Style) {
+	if width <= 0 {
+		return
+	}
+
+	limit := width
+	truncated := cellsWidth(cells)-offset > width
+	if truncated {
+		limit = max(0, width-3)
+	}
+
+	col := -offset
+	for _, c := range cells {
+		if col >= limit {
+			break
+		}
+		w := runeColumns(c.ch)
+		switch {
+		case col >= 0 && col+w <= limit:
+			screen.SetContent(x+col, y, c.ch, nil, c.style)
+		case col+w > 0:
+			for i := max(col, 0); i < min(col+w, limit); i++ {
+				screen.SetContent(x+i, y, ' ', nil, c.style)
+			}
+		}
+		col += w
+	}
+
+	col = min(max(col, 0), limit)
+	if truncated {
+		for _, ch := range "..."[:width-limit] {
+			screen.SetContent(x+col, y, ch, nil, truncStyle)
+			col++
+		}
+	}
+
+	// Fill remaining space with background
+	for ; col < width; col++ {
+		screen.SetContent(x+col, y, ' ', nil, tcell.StyleDefault)
+	}
+}